@@ -17,6 +17,8 @@ import (
 type IDInfo struct {
 	ID       string `json:"ID"`
 	ClientID string `json:"ClientID"`
+	// ClientPublicKey is the key that results can be encrypted for with the RecipientPublicKey publisher param
+	ClientPublicKey string `json:"ClientPublicKey"`
 }
 
 func NewCmd() *cobra.Command {
@@ -77,9 +79,15 @@ func id(cmd *cobra.Command, outputOpts output.OutputOptions) error {
 	}
 	defer closer.CloseWithLogOnError("libp2pHost", libp2pHost)
 
+	clientPublicKey, err := config.GetClientPublicKeyString()
+	if err != nil {
+		return err
+	}
+
 	info := IDInfo{
-		ID:       libp2pHost.ID().String(),
-		ClientID: system.GetClientID(),
+		ID:              libp2pHost.ID().String(),
+		ClientID:        system.GetClientID(),
+		ClientPublicKey: clientPublicKey,
 	}
 
 	return output.OutputOne(cmd, idColumns, outputOpts, info)
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"fmt"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -103,6 +104,11 @@ func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
 
 	o.printHeaderData(cmd, job)
 	o.printExecutionsSummary(cmd, executions)
+	if job.IsArray() {
+		if err = o.printArraySummary(cmd, job, executions); err != nil {
+			return fmt.Errorf("failed to write job array summary %s: %w", jobID, err)
+		}
+	}
 
 	jobHistory := lo.Filter(history, func(entry *models.JobHistory, _ int) bool {
 		return entry.Type == models.JobHistoryTypeJobLevel
@@ -141,6 +147,9 @@ func (o *DescribeOptions) printHeaderData(cmd *cobra.Command, job *models.Job) {
 	if job.Type == models.JobTypeBatch || job.Type == models.JobTypeService {
 		headerData = append(headerData, collections.NewPair[string, any]("Count", job.Count))
 	}
+	if job.IsArray() {
+		headerData = append(headerData, collections.NewPair[string, any]("Array Size", job.Array.Size()))
+	}
 
 	// Additional data
 	headerData = append(headerData, []collections.Pair[string, any]{
@@ -165,10 +174,96 @@ func (o *DescribeOptions) printExecutionsSummary(cmd *cobra.Command, executions
 			summaryPairs = append(summaryPairs, collections.NewPair[string, any](typ.String(), summaryMap[typ]))
 		}
 	}
+
+	// Resources actually used by the executions, as reported by the compute nodes
+	var usage models.ResourceUsage
+	for _, e := range executions {
+		usage.Add(e.ResourceUsage)
+	}
+	if !usage.IsZero() {
+		summaryPairs = append(summaryPairs,
+			collections.NewPair[string, any]("CPU Time", usage.CPUString()),
+			collections.NewPair[string, any]("Peak Memory", usage.MemoryString()),
+			collections.NewPair[string, any]("I/O", usage.IOString()),
+		)
+		if usage.FunctionCalls > 0 {
+			summaryPairs = append(summaryPairs, collections.NewPair[string, any]("Function Calls", usage.FunctionCallsString()))
+		}
+	}
 	output.Bold(cmd, "\nSummary\n")
 	output.KeyValue(cmd, summaryPairs)
 }
 
+// arrayIndexSummary summarizes the executions of a single index of an array job.
+type arrayIndexSummary struct {
+	index    int
+	attempts int
+	latest   *models.Execution
+}
+
+func (o *DescribeOptions) printArraySummary(cmd *cobra.Command, job *models.Job, executions []*models.Execution) error {
+	summaries := make(map[int]*arrayIndexSummary)
+	for _, index := range job.Array.Indexes() {
+		summaries[index] = &arrayIndexSummary{index: index}
+	}
+	// executions are sorted by most recent first
+	for _, e := range executions {
+		summary, ok := summaries[e.ArrayIndex]
+		if !ok {
+			continue
+		}
+		summary.attempts++
+		if summary.latest == nil {
+			summary.latest = e
+		}
+	}
+
+	completed := 0
+	rows := make([]*arrayIndexSummary, 0, len(summaries))
+	for _, index := range job.Array.Indexes() {
+		summary := summaries[index]
+		if summary.latest != nil && summary.latest.ComputeState.StateType == models.ExecutionStateCompleted {
+			completed++
+		}
+		rows = append(rows, summary)
+	}
+
+	tableOptions := output.OutputOptions{
+		Format:  output.TableFormat,
+		NoStyle: true,
+	}
+	arrayCols := []output.TableColumn[*arrayIndexSummary]{
+		{
+			ColumnConfig: table.ColumnConfig{Name: "Index"},
+			Value:        func(s *arrayIndexSummary) string { return strconv.Itoa(s.index) },
+		},
+		{
+			ColumnConfig: table.ColumnConfig{Name: "State", WidthMax: 17, WidthMaxEnforcer: text.WrapText},
+			Value: func(s *arrayIndexSummary) string {
+				if s.latest == nil {
+					return "Pending"
+				}
+				return s.latest.ComputeState.StateType.String()
+			},
+		},
+		{
+			ColumnConfig: table.ColumnConfig{Name: "Attempts"},
+			Value:        func(s *arrayIndexSummary) string { return strconv.Itoa(s.attempts) },
+		},
+		{
+			ColumnConfig: table.ColumnConfig{Name: "Latest Execution"},
+			Value: func(s *arrayIndexSummary) string {
+				if s.latest == nil {
+					return ""
+				}
+				return idgen.ShortUUID(s.latest.ID)
+			},
+		},
+	}
+	output.Bold(cmd, fmt.Sprintf("\nArray Indexes (%d/%d completed)\n", completed, len(rows)))
+	return output.Output(cmd, arrayCols, tableOptions, rows)
+}
+
 func (o *DescribeOptions) printExecutions(cmd *cobra.Command, executions []*models.Execution) error {
 	// Executions table
 	tableOptions := output.OutputOptions{
@@ -183,6 +278,8 @@ func (o *DescribeOptions) printExecutions(cmd *cobra.Command, executions []*mode
 		executionColumnRev,
 		executionColumnCreatedSince,
 		executionColumnModifiedSince,
+		executionColumnCPU,
+		executionColumnMemory,
 		executionColumnComment,
 	}
 	output.Bold(cmd, "\nExecutions\n")
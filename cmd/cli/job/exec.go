@@ -0,0 +1,256 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	execShortDesc = templates.LongDesc(i18n.T(`
+		Run a command in, or attach to, a running execution of a job
+`))
+
+	execLongDesc = templates.LongDesc(i18n.T(`
+		Run a command in a running execution of a job, or attach to the output of its main process.
+		The latest running execution of the job is used, unless an execution is specified.
+
+		Running commands is supported by the Docker engine. The WASM engine only supports attaching.
+		Access is controlled by the node's authorization policy, and requires the exec permission on the namespace
+		of the job.
+`))
+
+	//nolint:lll // Documentation
+	execExample = templates.Examples(i18n.T(`
+		# List the files in the working directory of a running service job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 -- ls -la
+
+		# Open an interactive shell in a specific execution of a job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 --execution-id e-5b3b4a73-e0e2-4a5c-b5a7-1b40e3e0cba5 -it -- /bin/sh
+
+		# Attach to the output of a running job
+		bacalhau job exec j-51225160-807e-48b8-88c9-28311c7899e1 --attach
+`))
+)
+
+type ExecOptions struct {
+	ExecutionID string
+	Namespace   string
+	Attach      bool
+	TTY         bool
+	Stdin       bool
+}
+
+func NewExecOptions() *ExecOptions {
+	return &ExecOptions{
+		Namespace: models.DefaultNamespace,
+	}
+}
+
+func NewExecCmd() *cobra.Command {
+	o := NewExecOptions()
+
+	execCmd := &cobra.Command{
+		Use:     "exec [id] [--attach | -- command [args...]]",
+		Short:   execShortDesc,
+		Long:    execLongDesc,
+		Example: execExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			exitCode, err := o.run(cmd, cmdArgs)
+			if err != nil {
+				return err
+			}
+			if exitCode != 0 {
+				os.Exit(exitCode)
+			}
+			return nil
+		},
+	}
+
+	execCmd.PersistentFlags().StringVarP(&o.ExecutionID, "execution-id", "e", o.ExecutionID,
+		"Run the command in a specific execution of the job.",
+	)
+	execCmd.PersistentFlags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		"The namespace of the job.",
+	)
+	execCmd.PersistentFlags().BoolVar(&o.Attach, "attach", o.Attach,
+		"Attach to the output of the main process of the execution instead of running a command.",
+	)
+	execCmd.PersistentFlags().BoolVarP(&o.TTY, "tty", "t", o.TTY,
+		"Allocate a terminal for the command.",
+	)
+	execCmd.PersistentFlags().BoolVarP(&o.Stdin, "stdin", "i", o.Stdin,
+		"Forward stdin to the command.",
+	)
+	return execCmd
+}
+
+func (o *ExecOptions) run(cmd *cobra.Command, cmdArgs []string) (int, error) {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	jobID := cmdArgs[0]
+	var command []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		command = cmdArgs[dash:]
+	}
+	if o.Attach && (len(command) > 0 || o.Stdin || o.TTY) {
+		return 0, fmt.Errorf("--attach can't be used with a command, --stdin or --tty")
+	}
+	if !o.Attach && len(command) == 0 {
+		return 0, fmt.Errorf("a command to run is required after --, or --attach")
+	}
+
+	stdinFd := int(os.Stdin.Fd())
+	stdoutFd := int(os.Stdout.Fd())
+	tty := o.TTY && term.IsTerminal(stdinFd) && term.IsTerminal(stdoutFd)
+	if o.TTY && !tty {
+		cmd.PrintErrln("stdin is not a terminal, running the command without a terminal")
+	}
+
+	events := make(chan models.ExecInput)
+	send := func(msg models.ExecInput) {
+		select {
+		case events <- msg:
+		case <-ctx.Done():
+		}
+	}
+
+	// input is closed once the command exits or the user leaves, which detaches from the command
+	input := make(chan models.ExecInput)
+	go func() {
+		defer close(input)
+		for {
+			select {
+			case msg := <-events:
+				select {
+				case input <- msg:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	apiClient := util.GetAPIClientV2(cmd)
+	request := &apimodels.ExecRequest{
+		JobID:       jobID,
+		ExecutionID: o.ExecutionID,
+		Command:     command,
+		Attach:      o.Attach,
+		TTY:         tty,
+		Stdin:       o.Stdin,
+	}
+	request.Namespace = o.Namespace
+	output, err := apiClient.Jobs().Exec(ctx, request, input)
+	if err != nil {
+		if errResp, ok := err.(*bacerrors.ErrorResponse); ok {
+			return 0, errResp
+		}
+		return 0, fmt.Errorf("unknown error trying to exec into job (ID: %s): %w", jobID, err)
+	}
+
+	if tty {
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return 0, fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+		defer func() {
+			_ = term.Restore(stdinFd, state)
+		}()
+		go o.forwardResize(ctx, stdoutFd, send)
+	}
+	if o.Stdin {
+		go o.forwardStdin(os.Stdin, send)
+	}
+
+	return readExecOutput(ctx, output, cmd.OutOrStdout(), cmd.ErrOrStderr())
+}
+
+// forwardStdin sends the input of the user to the command, and closes its stdin once the input ends.
+func (o *ExecOptions) forwardStdin(stdin io.Reader, send func(models.ExecInput)) {
+	buf := make([]byte, 32*1024) //nolint:gomnd
+	for {
+		n, err := stdin.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			send(models.ExecInput{Data: data})
+		}
+		if err != nil {
+			send(models.ExecInput{CloseStdin: true})
+			return
+		}
+	}
+}
+
+// forwardResize sends the size of the terminal to the command, and again each time it changes.
+func (o *ExecOptions) forwardResize(ctx context.Context, fd int, send func(models.ExecInput)) {
+	sendSize := func() {
+		width, height, err := term.GetSize(fd)
+		if err == nil {
+			send(models.ExecInput{Resize: &models.TerminalSize{Height: uint(height), Width: uint(width)}})
+		}
+	}
+	sendSize()
+	if len(util.ResizeSignals) == 0 {
+		return
+	}
+
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, util.ResizeSignals...)
+	defer signal.Stop(resized)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resized:
+			sendSize()
+		}
+	}
+}
+
+// readExecOutput writes the output of the command until it exits, and returns its exit code.
+func readExecOutput(ctx context.Context, output <-chan *concurrency.AsyncResult[models.ExecOutput],
+	stdout, stderr io.Writer) (int, error) {
+	for {
+		select {
+		case result, ok := <-output:
+			if !ok {
+				return 0, fmt.Errorf("connection closed before the command exited")
+			}
+			if result.Err != nil {
+				return 0, fmt.Errorf("error received from server: %w", result.Err)
+			}
+			msg := result.Value
+			if msg.Exited {
+				return msg.ExitCode, nil
+			}
+			w := stdout
+			if msg.Type == models.ExecutionLogTypeSTDERR {
+				w = stderr
+			}
+			if _, err := w.Write(msg.Data); err != nil {
+				return 0, fmt.Errorf("failed to write output: %w", err)
+			}
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
@@ -104,9 +104,22 @@ var (
 		ColumnConfig: table.ColumnConfig{Name: "Desired", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
 		Value:        func(e *models.Execution) string { return e.DesiredState.StateType.String() },
 	}
+	executionColumnCPU = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "CPU Time", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return e.ResourceUsage.CPUString() },
+	}
+	executionColumnMemory = output.TableColumn[*models.Execution]{
+		ColumnConfig: table.ColumnConfig{Name: "Peak Mem.", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.Execution) string { return e.ResourceUsage.MemoryString() },
+	}
 	executionColumnComment = output.TableColumn[*models.Execution]{
 		ColumnConfig: table.ColumnConfig{Name: "Comment", WidthMax: 40, WidthMaxEnforcer: text.WrapText},
-		Value:        func(e *models.Execution) string { return e.ComputeState.Message },
+		Value: func(e *models.Execution) string {
+			if e.FailureReason != "" {
+				return fmt.Sprintf("[%s] %s", e.FailureReason, e.ComputeState.Message)
+			}
+			return e.ComputeState.Message
+		},
 	}
 )
 
@@ -118,6 +131,8 @@ var executionColumns = []output.TableColumn[*models.Execution]{
 	executionColumnRev,
 	executionColumnState,
 	executionColumnDesired,
+	executionColumnCPU,
+	executionColumnMemory,
 }
 
 func (o *ExecutionOptions) run(cmd *cobra.Command, args []string) error {
@@ -14,6 +14,7 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewExecCmd())
 	cmd.AddCommand(NewExecutionCmd())
 	cmd.AddCommand(NewHistoryCmd())
 	cmd.AddCommand(NewListCmd())
@@ -0,0 +1,53 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// DescribeOptions is a struct to support namespace command
+type DescribeOptions struct {
+	OutputOpts output.NonTabularOutputOptions
+}
+
+// NewDescribeOptions returns initialized Options
+func NewDescribeOptions() *DescribeOptions {
+	return &DescribeOptions{
+		OutputOpts: output.NonTabularOutputOptions{Format: output.YAMLFormat},
+	}
+}
+
+func NewDescribeCmd() *cobra.Command {
+	o := NewDescribeOptions()
+	describeCmd := &cobra.Command{
+		Use:   "describe [namespace]",
+		Short: "Get the quota and usage of a namespace.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.run,
+	}
+	describeCmd.Flags().AddFlagSet(cliflags.OutputNonTabularFormatFlags(&o.OutputOpts))
+	return describeCmd
+}
+
+// Run executes namespace describe command
+func (o *DescribeOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	response, err := util.GetAPIClientV2(cmd).Namespaces().Get(ctx, &apimodels.GetNamespaceRequest{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("could not get namespace %s: %w", name, err)
+	}
+
+	if err = output.OutputOneNonTabular(cmd, o.OutputOpts, response.Namespace); err != nil {
+		return fmt.Errorf("failed to write namespace %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+var listColumns = []output.TableColumn[*apimodels.Namespace]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(ns *apimodels.Namespace) string { return ns.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "executions"},
+		Value:        func(ns *apimodels.Namespace) string { return fmt.Sprint(ns.Usage.Executions) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "cpu"},
+		Value:        func(ns *apimodels.Namespace) string { return fmt.Sprintf("%g", ns.Usage.Resources.CPU) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "memory"},
+		Value:        func(ns *apimodels.Namespace) string { return humanize.Bytes(ns.Usage.Resources.Memory) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "gpu"},
+		Value:        func(ns *apimodels.Namespace) string { return fmt.Sprint(ns.Usage.Resources.GPU) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "quota"},
+		Value: func(ns *apimodels.Namespace) string {
+			if ns.Quota == nil {
+				return "none"
+			}
+			return ns.Quota.String()
+		},
+	},
+}
+
+// ListOptions is a struct to support namespace command
+type ListOptions struct {
+	output.OutputOptions
+	cliflags.ListOptions
+}
+
+// NewListOptions returns initialized Options
+func NewListOptions() *ListOptions {
+	return &ListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+		ListOptions:   cliflags.ListOptions{OrderByFields: []string{"name"}},
+	}
+}
+
+func NewListCmd() *cobra.Command {
+	o := NewListOptions()
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the namespaces that have a quota or active executions.",
+		Args:  cobra.NoArgs,
+		RunE:  o.run,
+	}
+	listCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
+	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return listCmd
+}
+
+// Run executes namespace list command
+func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	response, err := util.GetAPIClientV2(cmd).Namespaces().List(ctx, &apimodels.ListNamespacesRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			Limit:     o.Limit,
+			NextToken: o.NextToken,
+			OrderBy:   o.OrderBy,
+			Reverse:   o.Reverse,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, listColumns, o.OutputOptions, response.Namespaces); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+//go:build unit || !integration
+
+package namespace_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	cmdtesting "github.com/bacalhau-project/bacalhau/cmd/testing"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/setup"
+)
+
+type NamespaceSuite struct {
+	cmdtesting.BaseSuite
+}
+
+func TestNamespaceSuite(t *testing.T) {
+	suite.Run(t, new(NamespaceSuite))
+}
+
+func (s *NamespaceSuite) SetupSuite() {
+	logger.ConfigureTestLogging(s.T())
+	setup.SetupBacalhauRepoForTesting(s.T())
+}
+
+func (s *NamespaceSuite) TestSetAndRemoveQuota() {
+	_, out, err := s.ExecuteTestCobraCommand(
+		"namespace", "set-quota", "team-a",
+		"--max-executions", "3",
+		"--cpu", "2",
+	)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "executions: 3, cpu: 2")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "team-a")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "describe", "team-a")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "MaxConcurrentExecutions: 3")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "remove-quota", "team-a")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "removed")
+
+	_, _, err = s.ExecuteTestCobraCommand("namespace", "remove-quota", "team-a")
+	s.Require().Error(err)
+}
+
+func (s *NamespaceSuite) TestSetInvalidQuota() {
+	_, _, err := s.ExecuteTestCobraCommand("namespace", "set-quota", "team-a", "--cpu", "lots")
+	s.Require().Error(err)
+}
+
+func (s *NamespaceSuite) TestUsage() {
+	_, out, err := s.ExecuteTestCobraCommand("namespace", "usage", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "namespace,jobs,executions,cpu time,peak memory,i/o")
+
+	_, out, err = s.ExecuteTestCobraCommand("namespace", "usage", "default", "--jobs", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "job,namespace,executions,cpu time,peak memory,i/o")
+}
@@ -0,0 +1,95 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// SetQuotaOptions is a struct to support namespace set-quota command
+type SetQuotaOptions struct {
+	MaxExecutions  int
+	CPU            string
+	Memory         string
+	GPU            string
+	NonPreemptible bool
+}
+
+func NewSetQuotaCmd() *cobra.Command {
+	o := &SetQuotaOptions{}
+	setQuotaCmd := &cobra.Command{
+		Use:   "set-quota [namespace]",
+		Short: "Create or replace the quota of a namespace.",
+		Long: `Create or replace the quota of a namespace. Executions of jobs in the namespace that would exceed
+the quota are held back by the orchestrator until enough of the namespace's executions complete.
+Limits that are not set are unlimited.`,
+		Example: `  # Allow at most 10 concurrent executions using 8 CPUs and 16GB of memory in total
+  bacalhau namespace set-quota team-a --max-executions 10 --cpu 8 --memory 16GB`,
+		Args: cobra.ExactArgs(1),
+		RunE: o.run,
+	}
+	setQuotaCmd.Flags().IntVar(&o.MaxExecutions, "max-executions", o.MaxExecutions,
+		"Maximum number of concurrent executions in the namespace. Zero means no limit.")
+	setQuotaCmd.Flags().StringVar(&o.CPU, "cpu", o.CPU,
+		"Maximum aggregate CPU of the namespace's executions, e.g. 500m or 4.")
+	setQuotaCmd.Flags().StringVar(&o.Memory, "memory", o.Memory,
+		"Maximum aggregate memory of the namespace's executions, e.g. 16GB.")
+	setQuotaCmd.Flags().StringVar(&o.GPU, "gpu", o.GPU,
+		"Maximum aggregate number of GPUs of the namespace's executions.")
+	setQuotaCmd.Flags().BoolVar(&o.NonPreemptible, "non-preemptible", o.NonPreemptible,
+		"Protect the namespace's executions from being preempted by higher priority jobs.")
+	return setQuotaCmd
+}
+
+func (o *SetQuotaOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	quota := &models.NamespaceQuota{
+		Namespace:               name,
+		MaxConcurrentExecutions: o.MaxExecutions,
+		MaxResources: &models.ResourcesConfig{
+			CPU:    o.CPU,
+			Memory: o.Memory,
+			GPU:    o.GPU,
+		},
+		NonPreemptible: o.NonPreemptible,
+	}
+	quota.Normalize()
+	if err := quota.Validate(); err != nil {
+		return fmt.Errorf("invalid quota: %w", err)
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Namespaces().PutQuota(ctx, &apimodels.PutNamespaceQuotaRequest{
+		Name:  name,
+		Quota: quota,
+	})
+	if err != nil {
+		return fmt.Errorf("could not set quota of namespace %s: %w", name, err)
+	}
+	cmd.Printf("Quota of namespace %s set to %s\n", name, response.Quota.String())
+	return nil
+}
+
+func NewRemoveQuotaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-quota [namespace]",
+		Short: "Remove the quota of a namespace, so that its executions are no longer limited.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+			_, err := util.GetAPIClientV2(cmd).Namespaces().DeleteQuota(ctx, &apimodels.DeleteNamespaceQuotaRequest{
+				Name: name,
+			})
+			if err != nil {
+				return fmt.Errorf("could not remove quota of namespace %s: %w", name, err)
+			}
+			cmd.Printf("Quota of namespace %s removed\n", name)
+			return nil
+		},
+	}
+}
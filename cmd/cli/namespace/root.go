@@ -0,0 +1,23 @@
+package namespace
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "namespace",
+		Short:              "Commands to query namespaces, their resource usage and manage their quotas.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewSetQuotaCmd())
+	cmd.AddCommand(NewRemoveQuotaCmd())
+	cmd.AddCommand(NewUsageCmd())
+	return cmd
+}
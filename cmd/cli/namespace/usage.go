@@ -0,0 +1,142 @@
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	usageLong = templates.LongDesc(i18n.T(`
+		Report the resources actually used by the executions of jobs, as measured by the compute nodes,
+		in total per namespace or per job. Unlike the usage shown by list and describe, which is the
+		resources allocated to active executions, this includes completed and failed executions.
+`))
+
+	usageExample = templates.Examples(i18n.T(`
+		# Usage of all namespaces
+		bacalhau namespace usage
+
+		# Usage of each job in a namespace
+		bacalhau namespace usage team-a --jobs
+
+		# Usage of a single job, as JSON
+		bacalhau namespace usage --job j-e3f8c209-d683-4a41-b840-f09b88d087b9 --output json
+`))
+)
+
+var namespaceUsageColumns = []output.TableColumn[models.NamespaceResourceUsage]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(u models.NamespaceResourceUsage) string { return u.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "jobs"},
+		Value:        func(u models.NamespaceResourceUsage) string { return fmt.Sprint(u.Jobs) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "executions"},
+		Value:        func(u models.NamespaceResourceUsage) string { return fmt.Sprint(u.Executions) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "cpu time"},
+		Value:        func(u models.NamespaceResourceUsage) string { return u.Usage.CPUString() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "peak memory"},
+		Value:        func(u models.NamespaceResourceUsage) string { return u.Usage.MemoryString() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "i/o"},
+		Value:        func(u models.NamespaceResourceUsage) string { return u.Usage.IOString() },
+	},
+}
+
+var jobUsageColumns = []output.TableColumn[models.JobResourceUsage]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "job"},
+		Value:        func(u models.JobResourceUsage) string { return u.JobID },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(u models.JobResourceUsage) string { return u.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "executions"},
+		Value:        func(u models.JobResourceUsage) string { return fmt.Sprint(u.Executions) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "cpu time"},
+		Value:        func(u models.JobResourceUsage) string { return u.Usage.CPUString() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "peak memory"},
+		Value:        func(u models.JobResourceUsage) string { return u.Usage.MemoryString() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "i/o"},
+		Value:        func(u models.JobResourceUsage) string { return u.Usage.IOString() },
+	},
+}
+
+// UsageOptions is a struct to support namespace usage command
+type UsageOptions struct {
+	output.OutputOptions
+	JobID  string
+	PerJob bool
+}
+
+// NewUsageOptions returns initialized Options
+func NewUsageOptions() *UsageOptions {
+	return &UsageOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewUsageCmd() *cobra.Command {
+	o := NewUsageOptions()
+	usageCmd := &cobra.Command{
+		Use:     "usage [namespace]",
+		Short:   "Report the resources used by the jobs of a namespace, or of all namespaces.",
+		Long:    usageLong,
+		Example: usageExample,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    o.run,
+	}
+	usageCmd.Flags().StringVar(&o.JobID, "job", o.JobID, "Only report the usage of this job.")
+	usageCmd.Flags().BoolVar(&o.PerJob, "jobs", o.PerJob, "Report the usage of each job instead of each namespace.")
+	usageCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return usageCmd
+}
+
+// Run executes namespace usage command
+func (o *UsageOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	request := &apimodels.GetUsageRequest{JobID: o.JobID}
+	if len(args) > 0 {
+		request.Namespace = args[0]
+	}
+	response, err := util.GetAPIClientV2(cmd).Namespaces().Usage(ctx, request)
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if o.PerJob || o.JobID != "" {
+		err = output.Output(cmd, jobUsageColumns, o.OutputOptions, response.Jobs)
+	} else {
+		err = output.Output(cmd, namespaceUsageColumns, o.OutputOptions, response.Namespaces)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
@@ -2,15 +2,18 @@ package node
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/spf13/cobra"
 )
 
 type NodeActionCmd struct {
-	action  string
-	message string
+	action       string
+	message      string
+	drainTimeout time.Duration
 }
 
 func NewActionCmd(action apimodels.NodeAction) *cobra.Command {
@@ -27,6 +30,10 @@ func NewActionCmd(action apimodels.NodeAction) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&actionCmd.message, "message", "m", "", "Message to include with the action")
+	if action == apimodels.NodeActionDrain {
+		cmd.Flags().DurationVar(&actionCmd.drainTimeout, "timeout", models.DefaultNodeDrainTimeout,
+			"Time running executions are given to complete before they are stopped and rescheduled on other nodes")
+	}
 	return cmd
 }
 
@@ -36,9 +43,10 @@ func (n *NodeActionCmd) run(cmd *cobra.Command, args []string) error {
 	nodeID := args[0]
 
 	response, err := util.GetAPIClientV2(cmd).Nodes().Put(ctx, &apimodels.PutNodeRequest{
-		NodeID:  nodeID,
-		Action:  n.action,
-		Message: n.message,
+		NodeID:       nodeID,
+		Action:       n.action,
+		Message:      n.message,
+		DrainTimeout: n.drainTimeout,
 	})
 	if err != nil {
 		util.Fatal(cmd, fmt.Errorf("could not %s node %s: %w", n.action, nodeID, err), 1)
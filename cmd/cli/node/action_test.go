@@ -94,6 +94,45 @@ func (s *NodeActionSuite) TestListNodes() {
 	s.Require().NotContains(out, nodeID)
 }
 
+func (s *NodeActionSuite) TestCordonAndDrainNodes() {
+	_, out, err := s.ExecuteTestCobraCommand(
+		"node",
+		"list",
+		"--output", "csv",
+	)
+	s.Require().NoError(err)
+	nodeID := getCells(out, 1)[0]
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "cordon", nodeID)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "cordon", nodeID)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "node already cordoned")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Equal("CONNECTED (CORDONED)", getCells(out, 1)[3])
+
+	// draining a cordoned node is allowed
+	_, out, err = s.ExecuteTestCobraCommand("node", "drain", nodeID, "--timeout", "1m")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Equal("CONNECTED (DRAINING)", getCells(out, 1)[3])
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "uncordon", nodeID)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Ok")
+
+	_, out, err = s.ExecuteTestCobraCommand("node", "uncordon", nodeID)
+	s.Require().NoError(err)
+	s.Require().Contains(out, "node not cordoned")
+}
+
 func getCells(output string, lineNo int) []string {
 	lines := strings.Split(output, "\n")
 	line := lines[lineNo]
@@ -33,6 +33,9 @@ var alwaysColumns = []output.TableColumn[*models.NodeState]{
 		ColumnConfig: table.ColumnConfig{Name: "status"},
 		Value: func(ni *models.NodeState) string {
 			if ni.Info.ComputeNodeInfo != nil {
+				if ni.IsCordoned() {
+					return fmt.Sprintf("%s (%s)", ni.Connection, ni.Cordon)
+				}
 				return ni.Connection.String()
 			}
 
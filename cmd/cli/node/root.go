@@ -23,8 +23,17 @@ func NewCmd() *cobra.Command {
 	// Reject Action
 	cmd.AddCommand(NewActionCmd(apimodels.NodeActionReject))
 
-	// Reject Action
+	// Delete Action
 	cmd.AddCommand(NewActionCmd(apimodels.NodeActionDelete))
 
+	// Cordon Action
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionCordon))
+
+	// Drain Action
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionDrain))
+
+	// Uncordon Action
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionUncordon))
+
 	return cmd
 }
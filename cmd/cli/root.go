@@ -13,7 +13,9 @@ import (
 	"github.com/bacalhau-project/bacalhau/cmd/cli/agent"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/exec"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/job"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/namespace"
 	"github.com/bacalhau-project/bacalhau/cmd/cli/node"
+	"github.com/bacalhau-project/bacalhau/cmd/cli/secret"
 
 	"github.com/bacalhau-project/bacalhau/cmd/cli/cancel"
 	configcli "github.com/bacalhau-project/bacalhau/cmd/cli/config"
@@ -159,6 +161,12 @@ func NewRootCmd() *cobra.Command {
 	// Register nodes subcommands
 	RootCmd.AddCommand(node.NewCmd())
 
+	// Register namespace subcommands
+	RootCmd.AddCommand(namespace.NewCmd())
+
+	// Register secret subcommands
+	RootCmd.AddCommand(secret.NewCmd())
+
 	// Register exec commands
 	RootCmd.AddCommand(exec.NewCmd())
 
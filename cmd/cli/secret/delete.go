@@ -0,0 +1,44 @@
+package secret
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// DeleteOptions is a struct to support secret delete command
+type DeleteOptions struct {
+	Namespace string
+}
+
+func NewDeleteCmd() *cobra.Command {
+	o := &DeleteOptions{}
+	deleteCmd := &cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a secret. Executions of jobs that reference it fail to start afterwards.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.run,
+	}
+	deleteCmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		"The namespace of the secret. Defaults to the default namespace.")
+	return deleteCmd
+}
+
+func (o *DeleteOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	_, err := util.GetAPIClientV2(cmd).Secrets().Delete(ctx, &apimodels.DeleteSecretRequest{
+		BasePutRequest: apimodels.BasePutRequest{
+			BaseRequest: apimodels.BaseRequest{Namespace: o.Namespace},
+		},
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete secret %s: %w", name, err)
+	}
+	cmd.Printf("Secret %s deleted\n", name)
+	return nil
+}
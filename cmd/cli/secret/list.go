@@ -0,0 +1,88 @@
+package secret
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+var listColumns = []output.TableColumn[*models.Secret]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "name"},
+		Value:        func(s *models.Secret) string { return s.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "namespace"},
+		Value:        func(s *models.Secret) string { return s.Namespace },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "created"},
+		Value:        func(s *models.Secret) string { return s.GetCreateTime().Format(time.DateTime) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "modified"},
+		Value:        func(s *models.Secret) string { return s.GetModifyTime().Format(time.DateTime) },
+	},
+}
+
+// ListOptions is a struct to support secret list command
+type ListOptions struct {
+	output.OutputOptions
+	cliflags.ListOptions
+	Namespace string
+}
+
+// NewListOptions returns initialized Options
+func NewListOptions() *ListOptions {
+	return &ListOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+		ListOptions:   cliflags.ListOptions{OrderByFields: []string{"name"}},
+	}
+}
+
+func NewListCmd() *cobra.Command {
+	o := NewListOptions()
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the secrets of a namespace. Their values are never shown.",
+		Args:  cobra.NoArgs,
+		RunE:  o.run,
+	}
+	listCmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		"The namespace of the secrets. Defaults to the default namespace.")
+	listCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
+	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return listCmd
+}
+
+// Run executes secret list command
+func (o *ListOptions) run(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	response, err := util.GetAPIClientV2(cmd).Secrets().List(ctx, &apimodels.ListSecretsRequest{
+		BaseListRequest: apimodels.BaseListRequest{
+			BaseGetRequest: apimodels.BaseGetRequest{
+				BaseRequest: apimodels.BaseRequest{Namespace: o.Namespace},
+			},
+			Limit:     o.Limit,
+			NextToken: o.NextToken,
+			OrderBy:   o.OrderBy,
+			Reverse:   o.Reverse,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed request: %w", err)
+	}
+
+	if err = output.Output(cmd, listColumns, o.OutputOptions, response.Secrets); err != nil {
+		return fmt.Errorf("failed to output: %w", err)
+	}
+	return nil
+}
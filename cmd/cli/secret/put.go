@@ -0,0 +1,116 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	putLong = templates.LongDesc(i18n.T(`
+		Create or replace a secret in a namespace. The value is encrypted at rest by the orchestrator,
+		and is never returned by the API. Jobs of the namespace reference the secret by name from the
+		environment variables of their tasks, and its value is only resolved on the compute node that
+		runs them, where it is redacted from their outputs and logs.
+
+		The value is read from standard input unless --value or --from-file is set.
+`))
+
+	putExample = templates.Examples(i18n.T(`
+		# Create a secret from standard input
+		echo -n "my-api-token" | bacalhau secret put api-token
+
+		# Create a secret in a namespace from a file
+		bacalhau secret put registry-password --namespace team-a --from-file ./password.txt
+
+		# Reference the secret from the environment variables of a task in a job spec
+		#   Env:
+		#     TOKEN: secret:api-token
+`))
+)
+
+// PutOptions is a struct to support secret put command
+type PutOptions struct {
+	Namespace string
+	Value     string
+	FromFile  string
+}
+
+func NewPutCmd() *cobra.Command {
+	o := &PutOptions{}
+	putCmd := &cobra.Command{
+		Use:     "put [name]",
+		Short:   "Create or replace a secret.",
+		Long:    putLong,
+		Example: putExample,
+		Args:    cobra.ExactArgs(1),
+		RunE:    o.run,
+	}
+	putCmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		"The namespace of the secret. Defaults to the default namespace.")
+	putCmd.Flags().StringVar(&o.Value, "value", o.Value,
+		"The value of the secret. Prefer standard input or --from-file, as the value may be kept in your shell history.")
+	putCmd.Flags().StringVar(&o.FromFile, "from-file", o.FromFile,
+		"Read the value of the secret from a file.")
+	putCmd.MarkFlagsMutuallyExclusive("value", "from-file")
+	return putCmd
+}
+
+func (o *PutOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+	if err := models.ValidateSecretName(name); err != nil {
+		return err
+	}
+
+	value, err := o.readValue(cmd)
+	if err != nil {
+		return err
+	}
+
+	response, err := util.GetAPIClientV2(cmd).Secrets().Put(ctx, &apimodels.PutSecretRequest{
+		BasePutRequest: apimodels.BasePutRequest{
+			BaseRequest: apimodels.BaseRequest{Namespace: o.Namespace},
+		},
+		Name:  name,
+		Value: value,
+	})
+	if err != nil {
+		return fmt.Errorf("could not put secret %s: %w", name, err)
+	}
+	cmd.Printf("Secret %s put in namespace %s\n", response.Secret.Name, response.Secret.Namespace)
+	return nil
+}
+
+// readValue returns the value of the secret from the flags, a file or standard input. Trailing newlines
+// of files and standard input are removed, as they are rarely part of the secret.
+func (o *PutOptions) readValue(cmd *cobra.Command) (string, error) {
+	if o.Value != "" {
+		return o.Value, nil
+	}
+
+	var data []byte
+	var err error
+	if o.FromFile != "" {
+		data, err = os.ReadFile(o.FromFile)
+	} else {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read secret value: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("secret value is empty")
+	}
+	return value, nil
+}
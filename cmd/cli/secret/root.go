@@ -0,0 +1,21 @@
+package secret
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                "secret",
+		Short:              "Commands to manage the secrets that jobs of a namespace can reference.",
+		PersistentPreRunE:  hook.AfterParentPreRunHook(hook.RemoteCmdPreRunHooks),
+		PersistentPostRunE: hook.AfterParentPostRunHook(hook.RemoteCmdPostRunHooks),
+	}
+
+	cmd.AddCommand(NewPutCmd())
+	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewDeleteCmd())
+	return cmd
+}
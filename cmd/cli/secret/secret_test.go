@@ -0,0 +1,59 @@
+//go:build unit || !integration
+
+package secret_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	cmdtesting "github.com/bacalhau-project/bacalhau/cmd/testing"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/setup"
+)
+
+type SecretSuite struct {
+	cmdtesting.BaseSuite
+}
+
+func TestSecretSuite(t *testing.T) {
+	suite.Run(t, new(SecretSuite))
+}
+
+func (s *SecretSuite) SetupSuite() {
+	logger.ConfigureTestLogging(s.T())
+	setup.SetupBacalhauRepoForTesting(s.T())
+}
+
+func (s *SecretSuite) TestPutListAndDelete() {
+	_, out, err := s.ExecuteTestCobraCommandWithStdin(strings.NewReader("s3cr3t\n"),
+		"secret", "put", "api-token", "--namespace", "team-a")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "Secret api-token put in namespace team-a")
+
+	_, out, err = s.ExecuteTestCobraCommand("secret", "list", "--namespace", "team-a", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "api-token,team-a")
+	s.Require().NotContains(out, "s3cr3t")
+
+	// secrets are scoped to their namespace
+	_, out, err = s.ExecuteTestCobraCommand("secret", "list", "--output", "csv")
+	s.Require().NoError(err)
+	s.Require().NotContains(out, "api-token")
+
+	_, out, err = s.ExecuteTestCobraCommand("secret", "delete", "api-token", "--namespace", "team-a")
+	s.Require().NoError(err)
+	s.Require().Contains(out, "deleted")
+
+	_, _, err = s.ExecuteTestCobraCommand("secret", "delete", "api-token", "--namespace", "team-a")
+	s.Require().Error(err)
+}
+
+func (s *SecretSuite) TestPutInvalidSecret() {
+	_, _, err := s.ExecuteTestCobraCommand("secret", "put", "not a name", "--value", "v")
+	s.Require().Error(err)
+
+	_, _, err = s.ExecuteTestCobraCommandWithStdin(strings.NewReader(""), "secret", "put", "empty")
+	s.Require().Error(err)
+}
@@ -243,6 +243,11 @@ func serve(cmd *cobra.Command) error {
 		return err
 	}
 
+	pluginConfig, err := config.Get[types.PluginConfig](types.NodePlugins)
+	if err != nil {
+		return err
+	}
+
 	authConfig, err := config.Get[types.AuthConfig](types.Auth)
 	if err != nil {
 		return err
@@ -261,6 +266,7 @@ func serve(cmd *cobra.Command) error {
 		CleanupManager:        cm,
 		IPFSClient:            ipfsClient,
 		DisabledFeatures:      featureConfig,
+		Plugins:               pluginConfig,
 		HostAddress:           config.ServerAPIHost(),
 		APIPort:               config.ServerAPIPort(),
 		ComputeConfig:         computeConfig,
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	pkgerrors "github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
@@ -15,11 +17,14 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
 	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	sqljobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/sql"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 
 	"github.com/bacalhau-project/bacalhau/cmd/util/flags/configflags"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/transformer"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/semantic"
@@ -27,6 +32,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/node"
+	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
 
@@ -76,6 +82,7 @@ func GetComputeConfig(ctx context.Context, createExecutionStore bool) (node.Comp
 		LogStreamBufferSize:          cfg.LogStreamConfig.ChannelBufferSize,
 		ExecutionStore:               executionStore,
 		LocalPublisher:               cfg.LocalPublisher,
+		InputCache:                   cfg.InputCache,
 	})
 }
 
@@ -94,6 +101,11 @@ func GetRequesterConfig(ctx context.Context, createJobStore bool) (node.Requeste
 		}
 	}
 
+	jobRetention, err := getJobRetentionPolicy(cfg.JobRetention)
+	if err != nil {
+		return node.RequesterConfig{}, pkgerrors.Wrapf(err, "failed to create job retention policy")
+	}
+
 	requesterConfig, err := node.NewRequesterConfigWith(node.RequesterConfigParams{
 		JobDefaults: transformer.JobDefaults{
 			ExecutionTimeout: time.Duration(cfg.JobDefaults.ExecutionTimeout),
@@ -122,6 +134,10 @@ func GetRequesterConfig(ctx context.Context, createJobStore bool) (node.Requeste
 		TranslationEnabled:             cfg.TranslationEnabled,
 		JobStore:                       jobStore,
 		DefaultPublisher:               cfg.DefaultPublisher,
+		Preemption: orchestrator.PreemptionPolicy{
+			Enabled: cfg.Preemption.Enabled,
+		},
+		JobRetention: jobRetention,
 	})
 	if err != nil {
 		return node.RequesterConfig{}, err
@@ -240,7 +256,7 @@ func getNetworkConfig() (node.NetworkConfig, error) {
 }
 
 func getExecutionStore(ctx context.Context, storeCfg types.JobStoreConfig) (store.ExecutionStore, error) {
-	if err := storeCfg.Validate(); err != nil {
+	if err := storeCfg.ValidateExecutionStore(); err != nil {
 		return nil, err
 	}
 
@@ -261,11 +277,52 @@ func getJobStore(ctx context.Context, storeCfg types.JobStoreConfig) (jobstore.S
 	case types.BoltDB:
 		log.Ctx(ctx).Debug().Str("Path", storeCfg.Path).Msg("creating boltdb backed jobstore")
 		return boltjobstore.NewBoltJobStore(storeCfg.Path)
+	case types.SQLite:
+		log.Ctx(ctx).Debug().Str("Path", storeCfg.Path).Msg("creating sqlite backed jobstore")
+		return sqljobstore.NewSQLiteJobStore(storeCfg.Path)
+	case types.Postgres:
+		log.Ctx(ctx).Debug().Msg("creating postgres backed jobstore")
+		return sqljobstore.NewPostgresJobStore(storeCfg.Path)
 	default:
 		return nil, fmt.Errorf("unknown JobStore type: %s", storeCfg.Type)
 	}
 }
 
+func getJobRetentionPolicy(cfg types.JobRetentionConfig) (orchestrator.RetentionPolicy, error) {
+	policy := orchestrator.RetentionPolicy{
+		MaxAge:       time.Duration(cfg.MaxAge),
+		KeepLast:     cfg.KeepLast,
+		GroupByLabel: cfg.GroupByLabel,
+		Interval:     time.Duration(cfg.Interval),
+	}
+	if !policy.IsEnabled() {
+		return policy, nil
+	}
+
+	var err error
+	switch strings.ToLower(cfg.Archive.Type) {
+	case "":
+	case "local":
+		policy.Archive, err = archive.NewLocalArchive(cfg.Archive.Path)
+	case "s3":
+		var s3Config aws.Config
+		s3Config, err = s3helper.DefaultAWSConfig()
+		if err != nil {
+			return policy, err
+		}
+		policy.Archive, err = archive.NewS3Archive(archive.S3ArchiveParams{
+			ClientProvider: s3helper.NewClientProvider(s3helper.ClientProviderParams{AWSConfig: s3Config}),
+			Bucket:         cfg.Archive.Bucket,
+			Prefix:         cfg.Archive.Prefix,
+			Endpoint:       cfg.Archive.Endpoint,
+			Region:         cfg.Archive.Region,
+		})
+	default:
+		err = fmt.Errorf("unknown job archive type: %s", cfg.Archive.Type)
+	}
+	return policy, err
+}
+
 func getNodeID(ctx context.Context) (string, error) {
 	nodeName, err := config.Get[string](types.NodeName)
 	if err != nil {
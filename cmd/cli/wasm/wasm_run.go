@@ -10,6 +10,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models/migration/legacy"
 	"github.com/ipfs/go-cid"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
@@ -320,7 +321,12 @@ func validateWasm(cmd *cobra.Command, args []string, opts *WasmRunOptions) error
 		return err
 	}
 
-	err = wasm.ValidateModuleImports(module, wasi)
+	http, err := wasm.NewHTTPHost(nil, 0, *log.Ctx(ctx)).Builder(engine).Compile(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = wasm.ValidateModuleImports(module, wasi, http)
 	if err != nil {
 		return err
 	}
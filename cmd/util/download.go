@@ -7,9 +7,12 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/encryption"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 
 	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
@@ -64,6 +67,15 @@ func DownloadResultsHandler(
 		return err
 	}
 
+	// results are only decrypted if the job's publisher encrypted them
+	jobResponse, err := GetAPIClientV2(cmd).Jobs().Get(ctx, &apimodels.GetJobRequest{
+		JobID: jobID,
+	})
+	if err != nil {
+		Fatal(cmd, fmt.Errorf("could not get job %s: %w", jobID, err), 1)
+	}
+	processedDownloadSettings.Encrypted = encryption.IsEncrypted(jobResponse.Job.Task().Publisher)
+
 	err = downloader.DownloadResults(
 		ctx,
 		response.Results,
@@ -88,6 +100,14 @@ func processDownloadSettings(settings *cliflags.DownloaderSettings, jobID string
 		}
 		settings.OutputDir = dir
 	}
+	if settings.DecryptionKey == nil {
+		// results encrypted for the client key are decrypted transparently
+		key, err := config.GetClientPrivateKey()
+		if err != nil {
+			log.Debug().Err(err).Msg("No client key to decrypt encrypted results")
+		}
+		settings.DecryptionKey = key
+	}
 	return settings, nil
 }
 
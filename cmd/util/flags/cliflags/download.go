@@ -1,6 +1,7 @@
 package cliflags
 
 import (
+	"crypto/rsa"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/downloader"
@@ -21,6 +22,10 @@ type DownloaderSettings struct {
 	OutputDir  string
 	SingleFile string
 	Raw        bool
+	// DecryptionKey is the client key, which decrypts results that were encrypted for it
+	DecryptionKey *rsa.PrivateKey
+	// Encrypted is set when results were encrypted by the publisher, and are decrypted with DecryptionKey
+	Encrypted bool
 }
 
 func NewDownloadFlags(settings *DownloaderSettings) *pflag.FlagSet {
@@ -7,14 +7,14 @@ var RequesterJobStorageFlags = []Definition{
 		FlagName:             "requester-job-store-type",
 		ConfigPath:           types.NodeRequesterJobStoreType,
 		DefaultValue:         Default.Node.Requester.JobStore.Type,
-		Description:          "The type of job store used by the requester node (BoltDB)",
+		Description:          "The type of job store used by the requester node (BoltDB, SQLite or Postgres)",
 		EnvironmentVariables: []string{"BACALHAU_JOB_STORE_TYPE"},
 	},
 	{
 		FlagName:             "requester-job-store-path",
 		ConfigPath:           types.NodeRequesterJobStorePath,
 		DefaultValue:         Default.Node.Requester.JobStore.Path,
-		Description:          "The path used for the requester job store store when using BoltDB",
+		Description:          "The path used for the requester job store when using BoltDB or SQLite, or the connection string when using Postgres",
 		EnvironmentVariables: []string{"BACALHAU_JOB_STORE_PATH"},
 	},
 }
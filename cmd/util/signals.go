@@ -7,3 +7,7 @@ import "os"
 var ShutdownSignals = []os.Signal{
 	os.Interrupt,
 }
+
+// ResizeSignals are sent when the size of the terminal changes, which is
+// not signalled on this platform.
+var ResizeSignals = []os.Signal{}
@@ -11,3 +11,8 @@ var ShutdownSignals = []os.Signal{
 	os.Interrupt,
 	syscall.SIGTERM,
 }
+
+// ResizeSignals are sent when the size of the terminal changes.
+var ResizeSignals = []os.Signal{
+	syscall.SIGWINCH,
+}
@@ -36,6 +36,7 @@ namespace_read     := 1
 namespace_write    := 2
 namespace_download := 4
 namespace_cancel   := 8
+namespace_exec     := 16
 
 read_only := bits.or(namespace_read, namespace_download)
-full_access := bits.or(bits.or(namespace_write, namespace_cancel), read_only)
+full_access := bits.or(bits.or(bits.or(namespace_write, namespace_cancel), namespace_exec), read_only)
@@ -4,6 +4,7 @@ import rego.v1
 default allow = false
 
 job_endpoint := ["api", "v1", "orchestrator", "jobs"]
+secrets_endpoint := ["api", "v1", "orchestrator", "secrets"]
 
 # https://developer.mozilla.org/en-US/docs/Glossary/Safe/HTTP
 http_safe_methods := ["GET", "HEAD", "OPTIONS"]
@@ -14,6 +15,18 @@ is_legacy_api if {
     input.http.path[2] == "requester"
 }
 
+# Exec runs commands in executions of jobs, so it is never allowed anonymously
+is_exec_api if {
+    count(input.http.path) == 6
+    array.slice(input.http.path, 0, 4) == job_endpoint
+    input.http.path[5] == "exec"
+}
+
+# Secrets are only ever accessed with a token, as even their names belong to their namespace
+is_secrets_api if {
+    array.slice(input.http.path, 0, 4) == secrets_endpoint
+}
+
 # Allow writing jobs if the access token has namespace write access
 allow if {
     input.http.path == job_endpoint
@@ -30,10 +43,37 @@ allow if {
     namespace_readable(job_namespace_perms)
 }
 
+# Allow running commands in executions of jobs if the access token has namespace exec access
+allow if {
+    is_exec_api
+    input.http.method in http_safe_methods
+
+    namespace_executable(query_namespace_perms)
+}
+
+# Allow creating and removing secrets if the access token has namespace write access
+allow if {
+    is_secrets_api
+    count(input.http.path) == 5
+    input.http.method in ["PUT", "DELETE"]
+
+    namespace_writable(query_namespace_perms)
+}
+
+# Allow listing secrets if the access token has namespace read access
+allow if {
+    input.http.path == secrets_endpoint
+    input.http.method in http_safe_methods
+
+    namespace_readable(query_namespace_perms)
+}
+
 # Allow reading all other endpoints, inclduing by users who don't have a token
 allow if {
     input.http.path != job_endpoint
     not is_legacy_api
+    not is_exec_api
+    not is_secrets_api
     input.http.method in http_safe_methods
 }
 
@@ -91,6 +131,13 @@ job_namespace := ns if {
     ns := jobRequest["namespace"]
 }
 
+# The namespace of the request query, such as of the job that commands are run in or of secrets
+default query_namespace := "default"
+query_namespace := input.http.query.namespace[0]
+
+# The permissions the access token grants on the namespace of the request query
+query_namespace_perms := bits.or(object.get(token_namespaces, query_namespace, 0), object.get(token_namespaces, "*", 0))
+
 # The list of namespaces from the verified access token
 token_namespaces := ns if {
     authHeader := input.http.headers["Authorization"][0]
@@ -106,3 +153,4 @@ namespace_readable(namespace)     if { bits.and(namespace, 1) != 0 }
 namespace_writable(namespace)     if { bits.and(namespace, 2) != 0 }
 namespace_downloadable(namespace) if { bits.and(namespace, 4) != 0 }
 namespace_cancelable(namespace)   if { bits.and(namespace, 8) != 0 }
+namespace_executable(namespace)   if { bits.and(namespace, 16) != 0 }
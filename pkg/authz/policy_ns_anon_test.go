@@ -24,6 +24,7 @@ const (
 	NamespaceWritable     uint8 = 0b0010
 	NamespaceDownloadable uint8 = 0b0100
 	NamespaceCancellable  uint8 = 0b1000
+	NamespaceExecutable   uint8 = 0b10000
 )
 
 func getJWTWithNamespace(t *testing.T, signingKey crypto.PrivateKey, namespace string, perms uint8) string {
@@ -73,6 +74,36 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 			"other", "other", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/nodes", sameKey, require.True},
 		{"deny writing other APIs",
 			"other", "other", "test", NamespaceNoPermission, http.MethodDelete, "/api/v1/orchestrator/nodes", sameKey, require.False},
+		{"allow exec into job in executable namespace",
+			"test", "test", "test", NamespaceExecutable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=test", sameKey, require.True},
+		{"deny exec into job in unexecutable namespace",
+			"test", "test", "test", NamespaceReadable | NamespaceWritable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=test", sameKey, require.False},
+		{"deny exec into job in alternative namespace",
+			"other", "other", "test", NamespaceExecutable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=other", sameKey, require.False},
+		{"deny exec into job without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec?namespace=test", sameKey, require.False},
+		{"allow exec into job in default namespace",
+			"default", "default", "default", NamespaceExecutable, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/exec", sameKey, require.True},
+		{"allow reading job logs without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/jobs/j-1/logs", sameKey, require.True},
+		{"allow secret put in writable namespace",
+			"test", "test", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/secrets/token?namespace=test", sameKey, require.True},
+		{"allow secret delete in writable namespace",
+			"test", "test", "test", NamespaceWritable, http.MethodDelete, "/api/v1/orchestrator/secrets/token?namespace=test", sameKey, require.True},
+		{"deny secret put in unwritable namespace",
+			"test", "test", "test", NamespaceReadable, http.MethodPut, "/api/v1/orchestrator/secrets/token?namespace=test", sameKey, require.False},
+		{"deny secret put in alternative namespace",
+			"other", "other", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/secrets/token?namespace=other", sameKey, require.False},
+		{"allow secret put in default namespace",
+			"default", "default", "default", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/secrets/token", sameKey, require.True},
+		{"allow secret list in readable namespace",
+			"test", "test", "test", NamespaceReadable, http.MethodGet, "/api/v1/orchestrator/secrets?namespace=test", sameKey, require.True},
+		{"deny secret list in unreadable namespace",
+			"test", "test", "test", NamespaceWritable, http.MethodGet, "/api/v1/orchestrator/secrets?namespace=test", sameKey, require.False},
+		{"deny secret list in alternative namespace",
+			"other", "other", "test", NamespaceReadable, http.MethodGet, "/api/v1/orchestrator/secrets?namespace=other", sameKey, require.False},
+		{"deny secret list without token",
+			"test", "test", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/secrets?namespace=test", sameKey, require.False},
 		{"deny signed by wrong key",
 			"test", "test", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/jobs", newKey, require.False},
 	}
@@ -32,6 +32,14 @@ func (s *AvailableCapacityStrategy) ShouldBidBasedOnUsage(
 	runningCapacity := s.runningCapacityTracker.GetAvailableCapacity(ctx)
 	enqueuedCapacity := s.enqueuedCapacityTracker.GetAvailableCapacity(ctx)
 	totalCapacity := runningCapacity.Add(enqueuedCapacity)
+	if maxCapacity := s.runningCapacityTracker.GetMaxCapacity(ctx); !maxCapacity.HasGPUsFor(usage) {
+		return bidstrategy.BidStrategyResponse{
+			ShouldBid:  false,
+			ShouldWait: false,
+			Reason: fmt.Sprintf("insufficient GPUs - requested: %d matching %s, available: %d",
+				usage.GPU, usage.GPUSelector.String(), len(maxCapacity.MatchingGPUs(usage.GPUSelector))),
+		}, nil
+	}
 	if usage.LessThanEq(*totalCapacity) {
 		return bidstrategy.BidStrategyResponse{
 			ShouldBid:  true,
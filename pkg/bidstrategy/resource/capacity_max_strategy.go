@@ -24,6 +24,15 @@ func NewMaxCapacityStrategy(params MaxCapacityStrategyParams) *MaxCapacityStrate
 
 func (s *MaxCapacityStrategy) ShouldBidBasedOnUsage(
 	ctx context.Context, request bidstrategy.BidStrategyRequest, usage models.Resources) (bidstrategy.BidStrategyResponse, error) {
+	// GPU details are only known when the max job requirements are derived from the node's GPUs
+	if len(s.maxJobRequirements.GPUs) > 0 && !s.maxJobRequirements.HasGPUsFor(usage) {
+		return bidstrategy.BidStrategyResponse{
+			ShouldBid:  false,
+			ShouldWait: false,
+			Reason: fmt.Sprintf("insufficient GPUs - requested: %d matching %s, available: %d",
+				usage.GPU, usage.GPUSelector.String(), len(s.maxJobRequirements.MatchingGPUs(usage.GPUSelector))),
+		}, nil
+	}
 	if usage.LessThanEq(s.maxJobRequirements) {
 		return bidstrategy.BidStrategyResponse{
 			ShouldBid:  true,
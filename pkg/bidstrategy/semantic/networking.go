@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
 type NetworkingStrategy struct {
@@ -17,17 +19,31 @@ func NewNetworkingStrategy(accept bool) *NetworkingStrategy {
 }
 
 const (
-	accessReason    = "run jobs that require network access"
-	localOnlyReason = "run jobs that do not require network access"
+	accessReason            = "run jobs that require network access"
+	localOnlyReason         = "run jobs that do not require network access"
+	wasmAccessReason        = "run WASM jobs that require unfiltered network access, as WASM jobs can only make HTTP requests"
+	wasmDeterministicReason = "run deterministic WASM jobs that require network access, as HTTP responses are not deterministic"
 )
 
 // ShouldBid implements BidStrategy
 func (s *NetworkingStrategy) ShouldBid(
 	ctx context.Context,
 	request bidstrategy.BidStrategyRequest) (bidstrategy.BidStrategyResponse, error) {
-	if request.Job.Task().Network.Disabled() {
-		return bidstrategy.NewBidResponse(true, localOnlyReason), nil
+	for _, task := range request.Job.Tasks {
+		if task.Engine.IsType(models.EngineWasm) && task.Network.Type == models.NetworkFull {
+			return bidstrategy.NewBidResponse(false, wasmAccessReason), nil
+		}
+		if task.Engine.IsType(models.EngineWasm) && !task.Network.Disabled() {
+			if engine, err := wasmmodels.DecodeSpec(task.Engine); err == nil && engine.Deterministic {
+				return bidstrategy.NewBidResponse(false, wasmDeterministicReason), nil
+			}
+		}
+	}
+	for _, task := range request.Job.Tasks {
+		if !task.Network.Disabled() {
+			return bidstrategy.NewBidResponse(s.Accept, accessReason), nil
+		}
 	}
 
-	return bidstrategy.NewBidResponse(s.Accept, accessReason), nil
+	return bidstrategy.NewBidResponse(true, localOnlyReason), nil
 }
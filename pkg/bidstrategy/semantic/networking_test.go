@@ -13,6 +13,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/semantic"
+	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 )
 
 type networkingStrategyTestCase struct {
@@ -52,3 +53,49 @@ func TestNetworkingStrategy(t *testing.T) {
 
 	}
 }
+
+func TestNetworkingStrategyWasm(t *testing.T) {
+	for _, test := range []networkingStrategyTestCase{
+		{true, models.NetworkConfig{Type: models.NetworkNone}, true},
+		{false, models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}}, false},
+		{true, models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}}, true},
+		{true, models.NetworkConfig{Type: models.NetworkFull}, false},
+	} {
+		job := mock.Job()
+		job.Task().Engine = &models.SpecConfig{Type: models.EngineWasm, Params: map[string]interface{}{}}
+		job.Task().Network = &test.job_networking
+		strategy := semantic.NewNetworkingStrategy(test.accept)
+		request := bidstrategy.BidStrategyRequest{Job: *job}
+
+		t.Run("ShouldBid/"+test.String(), func(t *testing.T) {
+			response, err := strategy.ShouldBid(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, test.should_bid, response.ShouldBid)
+		})
+	}
+}
+
+func TestNetworkingStrategyDeterministicWasm(t *testing.T) {
+	for _, test := range []networkingStrategyTestCase{
+		{true, models.NetworkConfig{Type: models.NetworkNone}, true},
+		{true, models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}}, false},
+	} {
+		job := mock.Job()
+		job.Task().Engine = &models.SpecConfig{
+			Type: models.EngineWasm,
+			Params: wasmmodels.EngineSpec{
+				EntryModule:   &models.InputSource{Source: &models.SpecConfig{Type: models.StorageSourceInline}},
+				Deterministic: true,
+			}.ToMap(),
+		}
+		job.Task().Network = &test.job_networking
+		strategy := semantic.NewNetworkingStrategy(test.accept)
+		request := bidstrategy.BidStrategyRequest{Job: *job}
+
+		t.Run("ShouldBid/"+test.String(), func(t *testing.T) {
+			response, err := strategy.ShouldBid(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, test.should_bid, response.ShouldBid, response.Reason)
+		})
+	}
+}
@@ -27,6 +27,7 @@ type BidderParams struct {
 	Executor         Executor
 	Callback         Callback
 	GetApproveURL    func() *url.URL
+	Secrets          *ExecutionSecrets
 }
 
 type Bidder struct {
@@ -36,6 +37,7 @@ type Bidder struct {
 	executor        Executor
 	callback        Callback
 	getApproveURL   func() *url.URL
+	secrets         *ExecutionSecrets
 
 	semanticStrategy []bidstrategy.SemanticBidStrategy
 	resourceStrategy []bidstrategy.ResourceBidStrategy
@@ -51,6 +53,7 @@ func NewBidder(params BidderParams) Bidder {
 		callback:         params.Callback,
 		semanticStrategy: params.SemanticStrategy,
 		resourceStrategy: params.ResourceStrategy,
+		secrets:          params.Secrets,
 	}
 }
 
@@ -85,6 +88,9 @@ type BidderRequest struct {
 
 	// ResourceUsage specifies the requested resources for this execution
 	ResourceUsage *models.Resources
+
+	// Secrets holds the values of the secrets referenced by the job, when not waiting for approval
+	Secrets models.SecretValues
 }
 
 // TODO: evaluate the need for async bidding and marking bids as waiting
@@ -112,7 +118,8 @@ func (b Bidder) RunBidding(ctx context.Context, bidRequest *BidderRequest) {
 		})
 		return
 	}
-	b.handleBidResult(ctx, bidResult, bidRequest.SourcePeerID, bidRequest.WaitForApproval, bidRequest.Execution)
+	b.handleBidResult(ctx, bidResult, bidRequest.SourcePeerID, bidRequest.WaitForApproval, bidRequest.Execution,
+		bidRequest.Secrets)
 }
 
 type bidStrategyResponse struct {
@@ -128,6 +135,7 @@ func (b Bidder) handleBidResult(
 	targetPeer string,
 	waitForApproval bool,
 	execution *models.Execution,
+	secrets models.SecretValues,
 ) {
 	var (
 		routingMetadata = RoutingMetadata{
@@ -180,6 +188,7 @@ func (b Bidder) handleBidResult(
 			handleComputeFailure(ctx, err, "failed to create execution state")
 			return
 		}
+		b.secrets.Put(execution.ID, secrets)
 		if err := b.executor.Run(ctx, *localExecution); err != nil {
 			// no need to check for run errors as they are already handled by the executor.
 			log.Ctx(ctx).Error().Err(err).Msg("failed to run execution")
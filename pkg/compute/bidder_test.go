@@ -31,6 +31,7 @@ type BidderSuite struct {
 	mockExecutionStore   store.ExecutionStore
 	mockCallback         *compute.MockCallback
 	mockExecutor         *compute.MockExecutor
+	secrets              *compute.ExecutionSecrets
 	bidder               compute.Bidder
 }
 
@@ -53,6 +54,7 @@ func (s *BidderSuite) SetupTest() {
 	s.mockExecutionStore = execStore
 	s.mockCallback = compute.NewMockCallback(s.ctrl)
 	s.mockExecutor = compute.NewMockExecutor(s.ctrl)
+	s.secrets = compute.NewExecutionSecrets()
 	s.bidder = compute.NewBidder(compute.BidderParams{
 		NodeID:           "testNodeID",
 		SemanticStrategy: []bidstrategy.SemanticBidStrategy{s.mockSemanticStrategy},
@@ -64,6 +66,7 @@ func (s *BidderSuite) SetupTest() {
 		GetApproveURL: func() *url.URL {
 			return &url.URL{}
 		},
+		Secrets: s.secrets,
 	})
 }
 
@@ -273,6 +276,29 @@ func (s *BidderSuite) TestRunBidding_WithoutPendingApproval() {
 	}
 }
 
+func (s *BidderSuite) TestRunBidding_WithSecrets() {
+	ctx := context.Background()
+	execution := mock.ExecutionForJob(mock.Job())
+	secrets := models.SecretValues{"token": "s3cr3t"}
+
+	s.mockSemanticStrategy.EXPECT().ShouldBid(ctx, gomock.Any()).
+		Return(bidstrategy.BidStrategyResponse{ShouldBid: true, ShouldWait: false}, nil)
+	s.mockResourceStrategy.EXPECT().ShouldBidBasedOnUsage(ctx, gomock.Any(), gomock.Any()).
+		Return(bidstrategy.BidStrategyResponse{ShouldBid: true, ShouldWait: false}, nil)
+	// the secrets are available to the executor by the time it runs the execution
+	s.mockExecutor.EXPECT().Run(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ store.LocalExecutionState) error {
+			s.Equal(secrets, s.secrets.Get(execution.ID))
+			return nil
+		})
+
+	s.bidder.RunBidding(ctx, &compute.BidderRequest{
+		Execution:     execution,
+		ResourceUsage: &models.Resources{},
+		Secrets:       secrets,
+	})
+}
+
 type BidResponseMatcher struct {
 	accepted bool
 }
@@ -41,5 +41,11 @@ func (c ChainedCallback) OnComputeFailure(ctx context.Context, err ComputeError)
 	}
 }
 
+func (c ChainedCallback) OnCheckpoint(ctx context.Context, result CheckpointResult) {
+	for _, callback := range c.callbacks {
+		callback.OnCheckpoint(ctx, result)
+	}
+}
+
 // compile-time interface check
 var _ Callback = &ChainedCallback{}
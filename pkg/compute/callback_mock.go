@@ -7,6 +7,7 @@ type CallbackMock struct {
 	OnCancelCompleteHandler func(ctx context.Context, result CancelResult)
 	OnComputeFailureHandler func(ctx context.Context, err ComputeError)
 	OnRunCompleteHandler    func(ctx context.Context, result RunResult)
+	OnCheckpointHandler     func(ctx context.Context, result CheckpointResult)
 }
 
 // OnBidComplete implements Callback
@@ -37,4 +38,11 @@ func (c CallbackMock) OnRunComplete(ctx context.Context, result RunResult) {
 	}
 }
 
+// OnCheckpoint implements Callback
+func (c CallbackMock) OnCheckpoint(ctx context.Context, result CheckpointResult) {
+	if c.OnCheckpointHandler != nil {
+		c.OnCheckpointHandler(ctx, result)
+	}
+}
+
 var _ Callback = CallbackMock{}
@@ -2,6 +2,7 @@ package capacity
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -20,9 +21,26 @@ func NewDefaultsUsageCalculator(params DefaultsUsageCalculatorParams) *DefaultsU
 	}
 }
 
+// Calculate applies the defaults to the resources the main task of the job didn't request. The other tasks
+// of the job are limited to the resources they requested, so a sidecar requesting memory doesn't leave
+// the main task without any.
 func (c *DefaultsUsageCalculator) Calculate(
 	ctx context.Context, job models.Job, parsedUsage models.Resources) (*models.Resources, error) {
-	return parsedUsage.Merge(c.defaults), nil
+	if len(job.Tasks) == 0 {
+		return parsedUsage.Merge(c.defaults), nil
+	}
+	usage := &models.Resources{}
+	for _, task := range job.Tasks {
+		resources, err := task.ResourcesConfig.ToResources()
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		if task.IsMain() {
+			resources = resources.Merge(c.defaults)
+		}
+		usage = usage.Add(*resources)
+	}
+	return usage, nil
 }
 
 type ChainedUsageCalculatorParams struct {
@@ -27,16 +27,18 @@ func (c *DiskUsageCalculator) Calculate(ctx context.Context, job models.Job, par
 	requirements := &models.Resources{}
 
 	var totalDiskRequirements uint64 = 0
-	for _, input := range job.Task().InputSources {
-		strg, err := c.storages.Get(ctx, input.Source.Type)
-		if err != nil {
-			return nil, err
+	for _, task := range job.Tasks {
+		for _, input := range task.InputSources {
+			strg, err := c.storages.Get(ctx, input.Source.Type)
+			if err != nil {
+				return nil, err
+			}
+			volumeSize, err := strg.GetVolumeSize(ctx, *input)
+			if err != nil {
+				return nil, fmt.Errorf("error getting job disk space requirements: %w", err)
+			}
+			totalDiskRequirements += volumeSize
 		}
-		volumeSize, err := strg.GetVolumeSize(ctx, *input)
-		if err != nil {
-			return nil, fmt.Errorf("error getting job disk space requirements: %w", err)
-		}
-		totalDiskRequirements += volumeSize
 	}
 
 	// update the job requirements disk space with what we calculated
@@ -2,8 +2,11 @@ package capacity
 
 import (
 	"context"
+	"slices"
 	"sync"
 
+	"github.com/samber/lo"
+
 	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -26,7 +29,7 @@ func NewLocalTracker(params LocalTrackerParams) *LocalTracker {
 }
 
 func (t *LocalTracker) IsWithinLimits(ctx context.Context, usage models.Resources) bool {
-	return usage.LessThanEq(t.maxCapacity)
+	return usage.LessThanEq(t.maxCapacity) && t.maxCapacity.HasGPUsFor(usage)
 }
 
 func (t *LocalTracker) AddIfHasCapacity(ctx context.Context, usage models.Resources) *models.Resources {
@@ -38,13 +41,15 @@ func (t *LocalTracker) AddIfHasCapacity(ctx context.Context, usage models.Resour
 		return nil
 	}
 
-	// Allocate any GPUs that have been asked for but not chosen
+	// Allocate any GPUs that have been asked for but not chosen, from the
+	// available GPUs that match the selector of the usage if there is one
 	unspecifiedGPUs := math.Max(usage.GPU-uint64(len(usage.GPUs)), 0)
-	availableGPUs := t.maxCapacity.Sub(t.usedCapacity).GPUs
+	availableGPUs := t.maxCapacity.Sub(t.usedCapacity).MatchingGPUs(usage.GPUSelector)
+	availableGPUs, _ = lo.Difference(availableGPUs, usage.GPUs)
 	if unspecifiedGPUs > uint64(len(availableGPUs)) {
 		return nil
 	}
-	usage.GPUs = append(usage.GPUs, availableGPUs[:unspecifiedGPUs]...)
+	usage.GPUs = append(slices.Clone(usage.GPUs), availableGPUs[:unspecifiedGPUs]...)
 
 	t.usedCapacity = *t.usedCapacity.Add(usage)
 	return &usage
@@ -46,3 +46,36 @@ func TestDoesntAllocateGPUs(t *testing.T) {
 	require.Len(t, avail.GPUs, 2)
 	require.Equal(t, avail, tracker.maxCapacity)
 }
+
+func TestAllocatesSelectedGPUs(t *testing.T) {
+	tracker := NewLocalTracker(LocalTrackerParams{MaxCapacity: models.Resources{
+		GPU: 3,
+		GPUs: []models.GPU{
+			{Index: 0, Name: "Tesla T4", Vendor: models.GPUVendorNvidia, Memory: 15360},
+			{Index: 1, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+			{Index: 2, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+		},
+	}})
+	selector := &models.GPUSelector{Vendor: "nvidia", MinMemory: "80GiB"}
+
+	require.True(t, tracker.IsWithinLimits(context.Background(), models.Resources{GPU: 2, GPUSelector: selector}))
+	require.False(t, tracker.IsWithinLimits(context.Background(), models.Resources{GPU: 3, GPUSelector: selector}))
+
+	added := tracker.AddIfHasCapacity(context.Background(), models.Resources{GPU: 1, GPUSelector: selector})
+	require.NotNil(t, added)
+	require.Len(t, added.GPUs, 1)
+	require.Equal(t, uint64(1), added.GPUs[0].Index)
+
+	added = tracker.AddIfHasCapacity(context.Background(), models.Resources{GPU: 1, GPUSelector: selector})
+	require.NotNil(t, added)
+	require.Len(t, added.GPUs, 1)
+	require.Equal(t, uint64(2), added.GPUs[0].Index)
+
+	// the T4 is still available, but doesn't match the selector
+	added = tracker.AddIfHasCapacity(context.Background(), models.Resources{GPU: 1, GPUSelector: selector})
+	require.Nil(t, added)
+
+	avail := tracker.GetAvailableCapacity(context.Background())
+	require.Equal(t, uint64(1), avail.GPU)
+	require.Equal(t, "Tesla T4", avail.GPUs[0].Name)
+}
@@ -0,0 +1,237 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+const (
+	// checkpointStateDir is the directory in the execution storage that is mounted at the checkpoint path
+	checkpointStateDir = "checkpoint"
+	// checkpointRestoreTarget is the target of the checkpoint when it is fetched as an input
+	checkpointRestoreTarget = "/restore"
+	// checkpointUnpackDir is the directory in the execution storage where compressed checkpoints are unpacked
+	checkpointUnpackDir = "restored"
+	// checkpointSnapshotPattern is the pattern of temporary directories checkpoints are snapshot to
+	checkpointSnapshotPattern = "snapshot-*"
+)
+
+// checkpoint snapshots the execution of a checkpointed task, and stores the snapshot using the task's publisher.
+func (e *BaseExecutor) checkpoint(ctx context.Context, state store.LocalExecutionState) (*models.Checkpoint, error) {
+	execution := state.Execution
+	task := execution.Job.Task()
+
+	exe, err := e.executors.Get(ctx, task.Engine.Type)
+	if err != nil {
+		return nil, fmt.Errorf("getting executor %s: %w", task.Engine.Type, err)
+	}
+	checkpointer, ok := exe.(executor.Checkpointer)
+	if !ok {
+		return nil, fmt.Errorf("executor %s does not support checkpoints", task.Engine.Type)
+	}
+
+	executionStorage := filepath.Join(e.storageDirectory, execution.JobID, execution.ID)
+	dir, err := os.MkdirTemp(executionStorage, checkpointSnapshotPattern)
+	if err != nil {
+		return nil, fmt.Errorf("preparing checkpoint directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("failed to remove checkpoint directory at %s", dir)
+		}
+	}()
+
+	if err = checkpointer.Checkpoint(ctx, execution.ID, dir); err != nil {
+		return nil, fmt.Errorf("checkpointing execution: %w", err)
+	}
+	// each checkpoint is published under its own name, so that it is neither overwritten by a later checkpoint
+	// nor by the result of the execution while it may be restored from, and can be removed once superseded.
+	checkpointTime := time.Now().UTC()
+	snapshot := state
+	snapshot.Execution = execution.Copy()
+	snapshot.Execution.ID = fmt.Sprintf("%s-checkpoint-%d", execution.ID, checkpointTime.UnixNano())
+	result, err := e.publish(ctx, snapshot, dir)
+	if err != nil {
+		return nil, fmt.Errorf("storing checkpoint: %w", err)
+	}
+
+	log.Ctx(ctx).Debug().Str("execution", execution.ID).Msg("Execution checkpointed")
+	return &models.Checkpoint{
+		ExecutionID: execution.ID,
+		Result:      &result,
+		Time:        checkpointTime.UnixNano(),
+	}, nil
+}
+
+// checkpointAndNotify checkpoints the execution and notifies the requester of the checkpoint.
+func (e *BaseExecutor) checkpointAndNotify(ctx context.Context, state store.LocalExecutionState) (*models.Checkpoint, error) {
+	checkpoint, err := e.checkpoint(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	e.callback.OnCheckpoint(ctx, CheckpointResult{
+		ExecutionMetadata: NewExecutionMetadata(state.Execution),
+		RoutingMetadata: RoutingMetadata{
+			SourcePeerID: e.ID,
+			TargetPeerID: state.RequesterNodeID,
+		},
+		Checkpoint: *checkpoint,
+	})
+	return checkpoint, nil
+}
+
+// checkpointPeriodically checkpoints the execution at the interval of its task until ctx is done,
+// and notifies the requester of each checkpoint. Failed checkpoints are logged and retried at the next interval.
+// Only the latest checkpoint is kept, as each checkpoint supersedes the previous one once the requester knows of it.
+func (e *BaseExecutor) checkpointPeriodically(ctx context.Context, state store.LocalExecutionState) {
+	interval := state.Execution.Job.Task().Checkpoint.GetInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var previous *models.Checkpoint
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkpoint, err := e.checkpointAndNotify(ctx, state)
+			if err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("failed to checkpoint execution")
+				continue
+			}
+			if previous != nil {
+				e.removeCheckpoint(ctx, state, previous)
+			}
+			previous = checkpoint
+		}
+	}
+}
+
+// removeCheckpoint removes a checkpoint that was superseded by a later checkpoint of the execution.
+// Failing to remove it only leaves it in storage, so errors are logged.
+func (e *BaseExecutor) removeCheckpoint(ctx context.Context, state store.LocalExecutionState, checkpoint *models.Checkpoint) {
+	publisherType := state.Execution.Job.Task().Publisher.Type
+	checkpointPublisher, err := e.publishers.Get(ctx, publisherType)
+	if err == nil {
+		err = publisher.RemoveResult(ctx, checkpointPublisher, *checkpoint.Result)
+	}
+	if errors.Is(err, publisher.ErrRemoveNotSupported) {
+		log.Ctx(ctx).Debug().Msgf("publisher %s does not support removing superseded checkpoints", publisherType)
+	} else if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to remove superseded checkpoint")
+	}
+}
+
+// isCheckpointed returns true if the execution should be checkpointed periodically by its executor.
+func (e *BaseExecutor) isCheckpointed(ctx context.Context, execution *models.Execution) bool {
+	task := execution.Job.Task()
+	if task.Checkpoint.GetInterval() <= 0 {
+		return false
+	}
+	exe, err := e.executors.Get(ctx, task.Engine.Type)
+	if err != nil {
+		return false
+	}
+	_, ok := exe.(executor.Checkpointer)
+	return ok
+}
+
+// prepareCheckpoint returns the checkpoint parameters of the execution's main task, after fetching the
+// checkpoint the execution restores from, if any.
+func prepareCheckpoint(
+	ctx context.Context,
+	strgprovider storage.StorageProvider,
+	storageDirectory string,
+	execution *models.Execution,
+) (*executor.CheckpointParams, InputCleanupFn, error) {
+	params := &executor.CheckpointParams{
+		Path:     execution.Job.Task().Checkpoint.Path,
+		StateDir: filepath.Join(storageDirectory, checkpointStateDir),
+	}
+	if execution.RestoreFrom == nil || execution.RestoreFrom.Result == nil {
+		return params, nil, nil
+	}
+
+	log.Ctx(ctx).Info().Str("checkpoint", execution.RestoreFrom.ExecutionID).Msg("restoring execution from checkpoint")
+	volumes, cleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, &models.InputSource{
+		Source: execution.RestoreFrom.Result,
+		Target: checkpointRestoreTarget,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetching checkpoint: %w", err)
+	}
+	restoreDir, err := resolveCheckpoint(volumes[0].Volume.Source, filepath.Join(storageDirectory, checkpointUnpackDir))
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("unpacking checkpoint: %w", err)
+	}
+	params.RestoreDir = restoreDir
+	return params, cleanup, nil
+}
+
+// resolveCheckpoint returns the directory holding a fetched checkpoint. Publishers that compress results
+// produce a gzipped tarball, or a directory containing one, which is decompressed into unpackDir.
+func resolveCheckpoint(source, unpackDir string) (string, error) {
+	archive := source
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return "", err
+		}
+		if len(entries) != 1 || entries[0].IsDir() {
+			return source, nil
+		}
+		archive = filepath.Join(source, entries[0].Name())
+	}
+
+	compressed, err := isGzip(archive)
+	if err != nil {
+		return "", err
+	}
+	if !compressed {
+		if info.IsDir() {
+			return source, nil
+		}
+		return "", fmt.Errorf("checkpoint %s is neither a directory nor a compressed archive", source)
+	}
+	if err = os.MkdirAll(unpackDir, StorageDirectoryPerms); err != nil {
+		return "", err
+	}
+	if err = gzip.Decompress(archive, unpackDir); err != nil {
+		return "", err
+	}
+	return unpackDir, nil
+}
+
+func isGzip(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	magic := make([]byte, 2) //nolint:gomnd
+	if _, err = io.ReadFull(file, magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return magic[0] == 0x1f && magic[1] == 0x8b, nil
+}
@@ -0,0 +1,149 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// checkpointingExecutor is an executor of running executions that can be checkpointed and canceled.
+type checkpointingExecutor struct {
+	executor.Executor
+	mu       sync.Mutex
+	calls    []string
+	canceled chan struct{}
+}
+
+func (e *checkpointingExecutor) Checkpoint(_ context.Context, executionID string, dir string) error {
+	e.record("checkpoint")
+	return os.WriteFile(filepath.Join(dir, "state"), []byte(executionID), 0644)
+}
+
+func (e *checkpointingExecutor) Cancel(context.Context, string) error {
+	e.record("cancel")
+	close(e.canceled)
+	return nil
+}
+
+func (e *checkpointingExecutor) Wait(context.Context, string) (<-chan *models.RunCommandResult, <-chan error) {
+	resultC := make(chan *models.RunCommandResult, 1)
+	resultC <- &models.RunCommandResult{}
+	return resultC, make(chan error)
+}
+
+func (e *checkpointingExecutor) record(call string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, call)
+}
+
+// removingPublisher is a publisher that keeps track of the results it published and removed.
+type removingPublisher struct {
+	publisher.Publisher
+	mu        sync.Mutex
+	published []string
+	removed   []string
+}
+
+func (p *removingPublisher) PublishResult(_ context.Context, execution *models.Execution, _ string) (models.SpecConfig, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, execution.ID)
+	return models.SpecConfig{Type: models.StorageSourceURL, Params: map[string]interface{}{"URL": execution.ID}}, nil
+}
+
+func (p *removingPublisher) RemoveResult(_ context.Context, result models.SpecConfig) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removed = append(p.removed, result.Params["URL"].(string))
+	return nil
+}
+
+func newCheckpointTest(t *testing.T, callback Callback) (*BaseExecutor, *checkpointingExecutor, *removingPublisher, store.LocalExecutionState) {
+	execution := mock.Execution()
+	execution.Job.Task().Checkpoint = &models.CheckpointConfig{Path: "/checkpoint", Interval: 1}
+	storageDirectory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(storageDirectory, execution.JobID, execution.ID), 0755))
+
+	exe := &checkpointingExecutor{canceled: make(chan struct{})}
+	pub := &removingPublisher{}
+	e := &BaseExecutor{
+		ID:               "node",
+		callback:         callback,
+		storageDirectory: storageDirectory,
+		executors:        provider.NewNoopProvider[executor.Executor](exe),
+		publishers:       provider.NewNoopProvider[publisher.Publisher](pub),
+	}
+	return e, exe, pub, *store.NewLocalExecutionState(execution, "requester")
+}
+
+func TestCheckpointPeriodically_KeepsLatestCheckpoint(t *testing.T) {
+	checkpoints := make(chan models.Checkpoint, 3)
+	e, _, pub, state := newCheckpointTest(t, CallbackMock{
+		OnCheckpointHandler: func(_ context.Context, result CheckpointResult) {
+			checkpoints <- result.Checkpoint
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.checkpointPeriodically(ctx, state)
+	}()
+	var notified []models.Checkpoint
+	for len(notified) < 3 {
+		select {
+		case checkpoint := <-checkpoints:
+			notified = append(notified, checkpoint)
+		case <-time.After(5 * time.Second):
+			t.Fatal("execution was not checkpointed")
+		}
+	}
+	cancel()
+	<-done
+
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	require.Len(t, pub.published, 3)
+	for i, checkpoint := range notified {
+		assert.Equal(t, state.Execution.ID, checkpoint.ExecutionID)
+		assert.NotEqual(t, state.Execution.ID, pub.published[i], "checkpoints are not published as the result")
+		assert.Equal(t, pub.published[i], checkpoint.Result.Params["URL"])
+	}
+	assert.Equal(t, pub.published[:2], pub.removed, "superseded checkpoints are removed")
+}
+
+func TestCancel_CheckpointsBeforeCanceling(t *testing.T) {
+	checkpoints := make(chan models.Checkpoint, 1)
+	e, exe, pub, state := newCheckpointTest(t, CallbackMock{
+		OnCheckpointHandler: func(_ context.Context, result CheckpointResult) {
+			checkpoints <- result.Checkpoint
+		},
+	})
+
+	require.NoError(t, e.Cancel(context.Background(), state))
+	<-exe.canceled
+
+	exe.mu.Lock()
+	defer exe.mu.Unlock()
+	assert.Equal(t, []string{"checkpoint", "cancel"}, exe.calls)
+	require.Len(t, checkpoints, 1)
+	assert.Equal(t, state.Execution.ID, (<-checkpoints).ExecutionID)
+	assert.Empty(t, pub.removed, "the previous checkpoint is kept")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
@@ -24,6 +25,8 @@ type BaseEndpointParams struct {
 	Bidder          Bidder
 	Executor        Executor
 	LogServer       *logstream.Server
+	ExecServer      *execstream.Server
+	Secrets         *ExecutionSecrets
 }
 
 // Base implementation of Endpoint
@@ -34,6 +37,8 @@ type BaseEndpoint struct {
 	bidder          Bidder
 	executor        Executor
 	logServer       *logstream.Server
+	execServer      *execstream.Server
+	secrets         *ExecutionSecrets
 }
 
 func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
@@ -44,6 +49,8 @@ func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
 		bidder:          params.Bidder,
 		executor:        params.Executor,
 		logServer:       params.LogServer,
+		execServer:      params.ExecServer,
+		secrets:         params.Secrets,
 	}
 }
 
@@ -58,7 +65,7 @@ func (s BaseEndpoint) AskForBid(ctx context.Context, request AskForBidRequest) (
 	jobsReceived.Add(ctx, 1)
 
 	// parse job resource config
-	parsedUsage, err := request.Execution.Job.Task().ResourcesConfig.ToResources()
+	parsedUsage, err := request.Execution.Job.TotalResources()
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("Error parsing job resource config")
 		return AskForBidResponse{ExecutionMetadata: ExecutionMetadata{
@@ -73,6 +80,7 @@ func (s BaseEndpoint) AskForBid(ctx context.Context, request AskForBidRequest) (
 		Execution:       request.Execution,
 		WaitForApproval: request.WaitForApproval,
 		ResourceUsage:   parsedUsage,
+		Secrets:         request.Secrets,
 	})
 
 	return AskForBidResponse{ExecutionMetadata: ExecutionMetadata{
@@ -100,6 +108,7 @@ func (s BaseEndpoint) BidAccepted(ctx context.Context, request BidAcceptedReques
 	// Increment the number of jobs accepted by this compute node:
 	jobsAccepted.Add(ctx, 1)
 
+	s.secrets.Put(request.ExecutionID, request.Secrets)
 	err = s.executor.Run(ctx, localExecutionState)
 	if err != nil {
 		return BidAcceptedResponse{}, err
@@ -120,6 +129,7 @@ func (s BaseEndpoint) BidRejected(ctx context.Context, request BidRejectedReques
 	if err != nil {
 		return BidRejectedResponse{}, err
 	}
+	s.secrets.Delete(request.ExecutionID)
 	localExecutionState, err := s.executionStore.GetExecution(ctx, request.ExecutionID)
 	if err != nil {
 		return BidRejectedResponse{}, err
@@ -155,6 +165,7 @@ func (s BaseEndpoint) CancelExecution(ctx context.Context, request CancelExecuti
 	if err != nil {
 		return CancelExecutionResponse{}, err
 	}
+	s.secrets.Delete(request.ExecutionID)
 	return CancelExecutionResponse{
 		ExecutionMetadata: NewExecutionMetadata(localExecutionState.Execution),
 	}, nil
@@ -169,5 +180,20 @@ func (s BaseEndpoint) ExecutionLogs(ctx context.Context, request ExecutionLogsRe
 	})
 }
 
+func (s BaseEndpoint) Exec(ctx context.Context, request ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if s.execServer == nil {
+		return nil, fmt.Errorf("exec is not enabled on node %s", s.id)
+	}
+	log.Ctx(ctx).Info().Msgf("exec into execution %s requested by %s", request.ExecutionID, request.SourcePeerID)
+	return s.execServer.Exec(ctx, executor.ExecRequest{
+		ExecutionID: request.ExecutionID,
+		Command:     request.Command,
+		Attach:      request.Attach,
+		TTY:         request.TTY,
+		Stdin:       request.Stdin,
+	}, input)
+}
+
 // Compile-time interface check:
 var _ Endpoint = (*BaseEndpoint)(nil)
@@ -0,0 +1,187 @@
+package execstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ServerParams struct {
+	ExecutionStore store.ExecutionStore
+	Executors      executor.ExecutorProvider
+	Buffer         int
+}
+
+// Server runs commands in, or attaches to, ongoing executions on behalf of remote clients.
+type Server struct {
+	executionStore store.ExecutionStore
+	executors      executor.ExecutorProvider
+	buffer         int
+}
+
+// NewServer creates a new exec server
+func NewServer(params ServerParams) *Server {
+	return &Server{
+		executionStore: params.ExecutionStore,
+		executors:      params.Executors,
+		buffer:         params.Buffer,
+	}
+}
+
+// Exec runs a command in, or attaches to, an ongoing execution. Input received from the client is forwarded
+// to the command, and its output is streamed back through the returned channel. The last message of the stream
+// holds the exit code of the command. Closing the input channel detaches the client from the command.
+func (s *Server) Exec(ctx context.Context, request executor.ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	localExecutionState, err := s.executionStore.GetExecution(ctx, request.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if localExecutionState.State != store.ExecutionStateRunning {
+		return nil, fmt.Errorf("can't exec into execution %s in state %s", request.ExecutionID, localExecutionState.State)
+	}
+	engineType := localExecutionState.Execution.Job.Task().Engine.Type
+	exe, err := s.executors.Get(ctx, engineType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find executor for engine: %s. %w", engineType, err)
+	}
+	execer, ok := exe.(executor.Execer)
+	if !ok {
+		return nil, fmt.Errorf("executor for engine %s does not support exec", engineType)
+	}
+
+	session := newSession(s.buffer)
+	ctx, cancel := context.WithCancel(ctx)
+	go session.forwardInput(ctx, cancel, input)
+	go func() {
+		defer cancel()
+		exitCode, err := execer.Exec(ctx, request, session.streams(request))
+		switch {
+		case errors.Is(err, context.Canceled):
+			// the client detached from the command
+			session.close(nil)
+		case err != nil:
+			session.close(concurrency.NewAsyncError[models.ExecOutput](err))
+		default:
+			session.close(concurrency.NewAsyncValue(models.ExecOutput{Exited: true, ExitCode: exitCode}))
+		}
+	}()
+	return session.output, nil
+}
+
+// session holds the streams of a command run for a client
+type session struct {
+	stdinReader *io.PipeReader
+	stdinWriter *io.PipeWriter
+	resize      chan models.TerminalSize
+
+	mu     sync.Mutex
+	closed bool
+	output chan *concurrency.AsyncResult[models.ExecOutput]
+}
+
+func newSession(buffer int) *session {
+	stdinReader, stdinWriter := io.Pipe()
+	return &session{
+		stdinReader: stdinReader,
+		stdinWriter: stdinWriter,
+		resize:      make(chan models.TerminalSize, 1),
+		output:      make(chan *concurrency.AsyncResult[models.ExecOutput], buffer),
+	}
+}
+
+func (s *session) streams(request executor.ExecRequest) executor.ExecStreams {
+	streams := executor.ExecStreams{
+		Stdout: &outputWriter{session: s, stream: models.ExecutionLogTypeSTDOUT},
+		Stderr: &outputWriter{session: s, stream: models.ExecutionLogTypeSTDERR},
+		Resize: s.resize,
+	}
+	if request.Stdin {
+		streams.Stdin = s.stdinReader
+	}
+	return streams
+}
+
+// forwardInput forwards the input of the client to the command until the client detaches, which cancels the session.
+func (s *session) forwardInput(ctx context.Context, cancel context.CancelFunc, input <-chan models.ExecInput) {
+	defer func() {
+		_ = s.stdinWriter.Close()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-input:
+			if !ok {
+				cancel()
+				return
+			}
+			if len(msg.Data) > 0 {
+				if _, err := s.stdinWriter.Write(msg.Data); err != nil {
+					log.Ctx(ctx).Debug().Err(err).Msg("failed to write exec input")
+				}
+			}
+			if msg.Resize != nil {
+				// only the latest size matters, so a pending resize is replaced
+				select {
+				case <-s.resize:
+				default:
+				}
+				s.resize <- *msg.Resize
+			}
+			if msg.CloseStdin {
+				_ = s.stdinWriter.Close()
+			}
+		}
+	}
+}
+
+// send sends output to the client, unless the session is closed
+func (s *session) send(result *concurrency.AsyncResult[models.ExecOutput]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.output <- result
+	return true
+}
+
+// close sends the last result, if any, and closes the output of the session
+func (s *session) close(last *concurrency.AsyncResult[models.ExecOutput]) {
+	if last != nil {
+		s.send(last)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.output)
+	}
+	_ = s.stdinReader.Close()
+}
+
+// outputWriter writes the output of a stream of the command to the client
+type outputWriter struct {
+	session *session
+	stream  models.ExecutionLogType
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	// the data is copied as writers are allowed to reuse p once Write returns
+	data := make([]byte, len(p))
+	copy(data, p)
+	if !w.session.send(concurrency.NewAsyncValue(models.ExecOutput{Type: w.stream, Data: data})) {
+		return 0, io.ErrClosedPipe
+	}
+	return len(p), nil
+}
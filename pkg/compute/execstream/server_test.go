@@ -0,0 +1,148 @@
+//go:build unit || !integration
+
+package execstream
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// echoExecutor echoes the stdin of commands to their stdout, and exits with exitCode once stdin is closed
+type echoExecutor struct {
+	*noop.NoopExecutor
+	exitCode int
+}
+
+func (e *echoExecutor) Exec(ctx context.Context, request executor.ExecRequest, streams executor.ExecStreams) (int, error) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(streams.Stdout, streams.Stdin)
+		done <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case err := <-done:
+		return e.exitCode, err
+	}
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	executionStore store.ExecutionStore
+	server         *Server
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	executionStore, err := boltdb.NewStore(s.ctx, filepath.Join(s.T().TempDir(), "exec-test.db"))
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = executionStore.Close(s.ctx)
+	})
+	s.executionStore = executionStore
+
+	var exe executor.Executor = &echoExecutor{NoopExecutor: noop.NewNoopExecutor(), exitCode: 3}
+	s.server = NewServer(ServerParams{
+		ExecutionStore: executionStore,
+		Executors:      provider.NewNoopProvider(exe),
+		Buffer:         8,
+	})
+}
+
+func (s *ServerTestSuite) createExecution(state store.LocalExecutionStateType) string {
+	execution := mock.Execution()
+	s.Require().NoError(s.executionStore.CreateExecution(s.ctx, *store.NewLocalExecutionState(execution, "req")))
+	s.Require().NoError(s.executionStore.UpdateExecutionState(s.ctx, store.UpdateExecutionStateRequest{
+		ExecutionID: execution.ID,
+		NewState:    state,
+	}))
+	return execution.ID
+}
+
+func (s *ServerTestSuite) readAll(output <-chan *concurrency.AsyncResult[models.ExecOutput]) []models.ExecOutput {
+	var results []models.ExecOutput
+	for {
+		select {
+		case result, ok := <-output:
+			if !ok {
+				return results
+			}
+			s.Require().NoError(result.Err)
+			results = append(results, result.Value)
+		case <-time.After(5 * time.Second):
+			s.FailNow("timed out waiting for exec output")
+		}
+	}
+}
+
+func (s *ServerTestSuite) TestExec_ForwardsInputAndReturnsExitCode() {
+	executionID := s.createExecution(store.ExecutionStateRunning)
+	input := make(chan models.ExecInput, 2)
+	output, err := s.server.Exec(s.ctx, executor.ExecRequest{
+		ExecutionID: executionID,
+		Command:     []string{"cat"},
+		Stdin:       true,
+	}, input)
+	s.Require().NoError(err)
+
+	input <- models.ExecInput{Data: []byte("hello")}
+	input <- models.ExecInput{CloseStdin: true}
+
+	results := s.readAll(output)
+	s.Require().Len(results, 2)
+	s.Equal(models.ExecOutput{Type: models.ExecutionLogTypeSTDOUT, Data: []byte("hello")}, results[0])
+	s.Equal(models.ExecOutput{Exited: true, ExitCode: 3}, results[1])
+}
+
+func (s *ServerTestSuite) TestExec_ClosingInputDetaches() {
+	executionID := s.createExecution(store.ExecutionStateRunning)
+	input := make(chan models.ExecInput)
+	output, err := s.server.Exec(s.ctx, executor.ExecRequest{
+		ExecutionID: executionID,
+		Command:     []string{"cat"},
+		Stdin:       true,
+	}, input)
+	s.Require().NoError(err)
+
+	close(input)
+	s.Empty(s.readAll(output))
+}
+
+func (s *ServerTestSuite) TestExec_RequiresRunningExecution() {
+	executionID := s.createExecution(store.ExecutionStateBidAccepted)
+	_, err := s.server.Exec(s.ctx, executor.ExecRequest{
+		ExecutionID: executionID,
+		Command:     []string{"cat"},
+	}, make(chan models.ExecInput))
+	s.Error(err)
+}
+
+func (s *ServerTestSuite) TestExec_RequiresExecer() {
+	s.server.executors = provider.NewNoopProvider[executor.Executor](noop.NewNoopExecutor())
+	executionID := s.createExecution(store.ExecutionStateRunning)
+	_, err := s.server.Exec(s.ctx, executor.ExecRequest{
+		ExecutionID: executionID,
+		Command:     []string{"cat"},
+	}, make(chan models.ExecInput))
+	s.ErrorContains(err, "does not support exec")
+}
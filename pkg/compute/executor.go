@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
@@ -23,6 +24,9 @@ import (
 
 const StorageDirectoryPerms = 0755
 
+// cancelWaitTimeout is how long a canceled execution is waited on to stop before its cancel is reported.
+const cancelWaitTimeout = 30 * time.Second
+
 type BaseExecutorParams struct {
 	ID                     string
 	Callback               Callback
@@ -33,6 +37,7 @@ type BaseExecutorParams struct {
 	ResultsPath            ResultsPath
 	Publishers             publisher.PublisherProvider
 	FailureInjectionConfig model.FailureInjectionComputeConfig
+	Secrets                *ExecutionSecrets
 }
 
 // BaseExecutor is the base implementation for backend service.
@@ -47,6 +52,7 @@ type BaseExecutor struct {
 	publishers       publisher.PublisherProvider
 	resultsPath      ResultsPath
 	failureInjection model.FailureInjectionComputeConfig
+	secrets          *ExecutionSecrets
 }
 
 func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
@@ -60,6 +66,7 @@ func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
 		publishers:       params.Publishers,
 		failureInjection: params.FailureInjectionConfig,
 		resultsPath:      params.ResultsPath,
+		secrets:          params.Secrets,
 	}
 }
 
@@ -127,11 +134,35 @@ func PrepareRunArguments(
 	strgprovider storage.StorageProvider,
 	storageDirectory string,
 	execution *models.Execution,
+	task *models.Task,
 	resultsDir string,
 ) (*executor.RunCommandRequest, InputCleanupFn, error) {
 	var cleanupFuncs []func(context.Context) error
 
-	inputVolumes, inputCleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, execution.Job.Task().InputSources...)
+	// the resources allocated to the execution are the sum of the resources of all its tasks. Other tasks are
+	// limited to the resources they requested, and the main task gets the resources it requested, with the
+	// defaults applied to the execution for the ones it didn't, and the GPUs picked for the execution.
+	resources, err := task.ResourcesConfig.ToResources()
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.IsMain() {
+		remaining := execution.TotalAllocatedResources()
+		for _, other := range execution.Job.Tasks {
+			if other.IsMain() {
+				continue
+			}
+			otherResources, err := other.ResourcesConfig.ToResources()
+			if err != nil {
+				return nil, nil, fmt.Errorf("task %s: %w", other.Name, err)
+			}
+			remaining = remaining.Sub(*otherResources)
+		}
+		resources = resources.Merge(*remaining)
+		resources.GPUs = remaining.GPUs
+	}
+
+	inputVolumes, inputCleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, task.InputSources...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -154,8 +185,8 @@ func PrepareRunArguments(
 		provides more context on the need for the change).
 	*/
 	var engineArgs *models.SpecConfig
-	if execution.Job.Task().Engine.IsType(models.EngineWasm) {
-		wasmEngine, err := wasmmodels.DecodeSpec(execution.Job.Task().Engine)
+	if task.Engine.IsType(models.EngineWasm) {
+		wasmEngine, err := wasmmodels.DecodeSpec(task.Engine)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -172,18 +203,19 @@ func PrepareRunArguments(
 			Params: wasmEngine.ToArguments(volumes["entryModules"][0], volumes["importModules"]...).ToMap(),
 		}
 	} else {
-		engineArgs = execution.Job.Task().Engine
+		engineArgs = task.Engine
 	}
 
 	return &executor.RunCommandRequest{
 			JobID:        execution.Job.ID,
-			ExecutionID:  execution.ID,
-			Resources:    execution.TotalAllocatedResources(),
-			Network:      execution.Job.Task().Network,
-			Outputs:      execution.Job.Task().ResultPaths,
+			ExecutionID:  taskExecutionID(execution, task),
+			Resources:    resources,
+			Network:      task.Network,
+			Outputs:      task.ResultPaths,
 			Inputs:       inputVolumes,
 			ResultsDir:   resultsDir,
 			EngineParams: engineArgs,
+			Env:          task.Env,
 			OutputLimits: executor.OutputLimits{
 				MaxStdoutFileLength:   system.MaxStdoutFileLength,
 				MaxStdoutReturnLength: system.MaxStdoutReturnLength,
@@ -205,14 +237,27 @@ func PrepareRunArguments(
 
 type StartResult struct {
 	cleanup InputCleanupFn
+	tasks   *taskRunner
 	Err     error
 }
 
 func (r *StartResult) Cleanup(ctx context.Context) error {
+	var err error
 	if r.cleanup != nil {
-		return r.cleanup(ctx)
+		err = r.cleanup(ctx)
+	}
+	if r.tasks != nil {
+		err = errors.Join(err, r.tasks.cleanup(ctx))
 	}
-	return nil
+	return err
+}
+
+// TaskStates returns the state of the tasks of the execution
+func (r *StartResult) TaskStates() map[string]*models.TaskState {
+	if r.tasks == nil {
+		return nil
+	}
+	return r.tasks.taskStates()
 }
 
 func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *StartResult {
@@ -235,13 +280,35 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		return result
 	}
 
-	args, cleanup, err := PrepareRunArguments(ctx, e.Storages, executionStorage, execution, resultFolder)
+	args, cleanup, err := PrepareRunArguments(ctx, e.Storages, executionStorage, execution, execution.Job.Task(), resultFolder)
 	result.cleanup = cleanup
 	if err != nil {
 		result.Err = fmt.Errorf("preparing arguments: %w", err)
 		return result
 	}
 
+	// secrets are only resolved at execution time, so that their values are never part of the stored job
+	secrets := e.secrets.Get(execution.ID)
+	args.Env, err = secrets.ResolveEnv(args.Env)
+	if err != nil {
+		result.Err = fmt.Errorf("resolving secrets: %w", err)
+		return result
+	}
+
+	if execution.Job.Task().Checkpoint != nil {
+		checkpoint, checkpointCleanup, err := prepareCheckpoint(ctx, e.Storages, executionStorage, execution)
+		if checkpointCleanup != nil {
+			result.cleanup = func(ctx context.Context) error {
+				return errors.Join(cleanup(ctx), checkpointCleanup(ctx))
+			}
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("preparing checkpoint: %w", err)
+			return result
+		}
+		args.Checkpoint = checkpoint
+	}
+
 	if err := e.store.UpdateExecutionState(ctx, store.UpdateExecutionStateRequest{
 		ExecutionID: execution.ID,
 		ExpectedStates: []store.LocalExecutionStateType{
@@ -261,10 +328,26 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		return result
 	}
 
+	// prestart tasks must complete before the main task and its sidecars are started
+	result.tasks = newTaskRunner(e.executors, e.Storages, executionStorage, resultFolder, execution, secrets)
+	if err := result.tasks.runToCompletion(ctx, execution.Job.TasksWithLifecycle(models.TaskLifecyclePrestart)); err != nil {
+		result.Err = err
+		return result
+	}
+	if err := result.tasks.startAll(ctx, execution.Job.TasksWithLifecycle(models.TaskLifecycleSidecar)); err != nil {
+		result.Err = err
+		return result
+	}
+
 	if err := jobExecutor.Start(ctx, args); err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to start execution")
 		result.Err = err
 	}
+	if result.Err == nil || errors.Is(result.Err, executor.ErrAlreadyStarted) {
+		result.tasks.setState(execution.Job.Task().Name, models.TaskStateRunning, 0, "")
+	} else {
+		result.tasks.fail(execution.Job.Task(), result.Err)
+	}
 
 	return result
 }
@@ -300,9 +383,13 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 
 	stopwatch := telemetry.Timer(ctx, jobDurationMilliseconds, state.Execution.Job.MetricAttributes()...)
 	topic := EventTopicExecutionRunning
+	// usage is the resource usage reported by the executor, and is reported to the requester even on failure
+	// along with the egress log of the execution
+	var usage *models.ResourceUsage
+	var egress *models.EgressLog
 	defer func() {
 		if err != nil {
-			e.handleFailure(ctx, state, err, topic)
+			e.handleFailure(ctx, state, err, topic, usage, egress)
 		}
 		dur := stopwatch()
 		log.Ctx(ctx).Debug().
@@ -312,12 +399,26 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 			Msg("run complete")
 	}()
 
+	// secrets are forgotten once the execution completes, and redacted from its outputs
+	defer e.secrets.Delete(execution.ID)
+	secrets := e.secrets.Get(execution.ID)
+
 	res := e.Start(ctx, execution)
 	defer func() {
 		if err := res.Cleanup(ctx); err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to clean up start arguments")
 		}
 	}()
+	if res.tasks != nil {
+		// sidecars only run as long as the main task, and poststop tasks run once it is done, on every exit path
+		// including when the main task failed or timed out. They are run below when the main task succeeds, so
+		// that a failing poststop task fails the execution.
+		defer func() {
+			if err := res.tasks.stopAndRunPoststop(context.WithoutCancel(ctx), execution.Job); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("poststop task failed after the main task")
+			}
+		}()
+	}
 	if err := res.Err; err != nil {
 		if errors.Is(err, executor.ErrAlreadyStarted) {
 			// by not returning this error to the caller when the execution has already been started/is already running
@@ -334,6 +435,12 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		}
 	}
 
+	if e.isCheckpointed(ctx, execution) {
+		checkpointCtx, stopCheckpoints := context.WithCancel(ctx)
+		go e.checkpointPeriodically(checkpointCtx, state)
+		defer stopCheckpoints()
+	}
+
 	result, err := e.Wait(ctx, state)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
@@ -358,8 +465,26 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		}
 		return err
 	}
+	usage = result.ResourceUsage
+	egress = result.Egress
+	redactRunResult(secrets, result)
+	if res.tasks != nil {
+		res.tasks.recordResult(execution.Job.Task().Name, result)
+	}
 	if result.ErrorMsg != "" {
-		return fmt.Errorf("execution error: %s", result.ErrorMsg)
+		execErr := models.NewBaseError("execution error: %s", result.ErrorMsg)
+		if result.FailureReason != "" {
+			execErr = execErr.WithFailureReason(result.FailureReason)
+		}
+		if result.Egress != nil && result.Egress.Denied > 0 {
+			execErr = execErr.WithHint(fmt.Sprintf("The execution made %s", result.Egress))
+		}
+		return execErr
+	}
+	if res.tasks != nil {
+		if err = res.tasks.stopAndRunPoststop(ctx, execution.Job); err != nil {
+			return err
+		}
 	}
 	jobsCompleted.Add(ctx, 1)
 
@@ -383,6 +508,9 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		if err != nil {
 			return err
 		}
+		if err = redactResultFiles(secrets, resultsDir); err != nil {
+			return fmt.Errorf("redacting secrets from results: %w", err)
+		}
 
 		defer func() {
 			// cleanup resources
@@ -417,6 +545,7 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		},
 		PublishResult:    &publishedResult,
 		RunCommandResult: result,
+		TaskStates:       res.TaskStates(),
 	})
 	return err
 }
@@ -450,7 +579,7 @@ func (e *BaseExecutor) Cancel(ctx context.Context, state store.LocalExecutionSta
 	execution := state.Execution
 	defer func() {
 		if err != nil {
-			e.handleFailure(ctx, state, err, "Canceling")
+			e.handleFailure(ctx, state, err, "Canceling", nil, nil)
 		}
 	}()
 
@@ -460,21 +589,69 @@ func (e *BaseExecutor) Cancel(ctx context.Context, state store.LocalExecutionSta
 	if err != nil {
 		return err
 	}
+	// checkpoint the execution before it is stopped so that a later execution of the job can resume from it.
+	// The cancel is acknowledged before, and failing to checkpoint does not prevent the execution from being canceled.
+	// The previous checkpoint is kept, as a replacement execution may already be restoring from it.
+	if execution.Job.Task().Checkpoint != nil {
+		if _, err := e.checkpointAndNotify(ctx, state); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to checkpoint execution %s before canceling it", execution.ID)
+		}
+	}
 	if err := exe.Cancel(ctx, execution.ID); err != nil {
 		return err
 	}
+	e.cancelOtherTasks(ctx, execution)
 
-	e.callback.OnCancelComplete(ctx, CancelResult{
-		ExecutionMetadata: NewExecutionMetadata(execution),
+	// the egress log of the execution is only known once it has stopped, so the cancel is reported then
+	go e.notifyCancelComplete(context.WithoutCancel(ctx), exe, state)
+	return err
+}
+
+// notifyCancelComplete waits for a canceled execution to stop, and reports it to the requester along with
+// its egress log, if any.
+func (e *BaseExecutor) notifyCancelComplete(ctx context.Context, exe executor.Executor, state store.LocalExecutionState) {
+	ctx, cancel := context.WithTimeout(ctx, cancelWaitTimeout)
+	defer cancel()
+
+	result := CancelResult{
+		ExecutionMetadata: NewExecutionMetadata(state.Execution),
 		RoutingMetadata: RoutingMetadata{
 			SourcePeerID: e.ID,
 			TargetPeerID: state.RequesterNodeID,
 		},
-	})
-	return err
+	}
+	waitC, errC := exe.Wait(ctx, state.Execution.ID)
+	select {
+	case res := <-waitC:
+		if res != nil {
+			result.Egress = res.Egress
+		}
+	case err := <-errC:
+		log.Ctx(ctx).Debug().Err(err).Msgf("failed to wait on canceled execution %s", state.Execution.ID)
+	case <-ctx.Done():
+	}
+	e.callback.OnCancelComplete(ctx, result)
+}
+
+// cancelOtherTasks cancels the prestart, sidecar and poststop tasks of the execution that may be running.
+// Tasks that are not running are expected to fail to cancel, so failures are only logged.
+func (e *BaseExecutor) cancelOtherTasks(ctx context.Context, execution *models.Execution) {
+	for _, task := range execution.Job.Tasks {
+		if task.IsMain() {
+			continue
+		}
+		exe, err := e.executors.Get(ctx, task.Engine.Type)
+		if err == nil {
+			err = exe.Cancel(ctx, taskExecutionID(execution, task))
+		}
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msgf("did not cancel %s task %s", task.GetLifecycle(), task.Name)
+		}
+	}
 }
 
-func (e *BaseExecutor) handleFailure(ctx context.Context, state store.LocalExecutionState, err error, topic models.EventTopic) {
+func (e *BaseExecutor) handleFailure(ctx context.Context, state store.LocalExecutionState, err error,
+	topic models.EventTopic, usage *models.ResourceUsage, egress *models.EgressLog) {
 	log.Ctx(ctx).Warn().Err(err).Msgf("%s failed", topic)
 
 	execution := state.Execution
@@ -493,7 +670,9 @@ func (e *BaseExecutor) handleFailure(ctx context.Context, state store.LocalExecu
 				SourcePeerID: e.ID,
 				TargetPeerID: state.RequesterNodeID,
 			},
-			Event: models.EventFromError(topic, err),
+			Event:         models.EventFromError(topic, err),
+			ResourceUsage: usage,
+			Egress:        egress,
 		})
 	}
 }
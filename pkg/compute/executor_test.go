@@ -0,0 +1,71 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+func TestPrepareRunArguments_Env(t *testing.T) {
+	execution := mock.Execution()
+	task := execution.Job.Task()
+	task.InputSources = nil
+
+	// tasks without environment variables run with the environment of their engine spec only, as before
+	task.Env = nil
+	args, cleanup, err := compute.PrepareRunArguments(context.Background(), nil, t.TempDir(), execution, task, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, cleanup(context.Background()))
+	assert.Empty(t, args.Env)
+
+	// the task's environment variables, such as the index of array jobs, are passed to the executor
+	task.Env = map[string]string{"BACALHAU_ARRAY_INDEX": "3", "NAME": "value"}
+	args, cleanup, err = compute.PrepareRunArguments(context.Background(), nil, t.TempDir(), execution, task, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, cleanup(context.Background()))
+	assert.Equal(t, task.Env, args.Env)
+}
+
+func TestPrepareRunArguments_OnlySidecarRequestsMemory(t *testing.T) {
+	execution := mock.Execution()
+	main := execution.Job.Task()
+	main.InputSources = nil
+	main.ResourcesConfig = &models.ResourcesConfig{}
+	sidecar := main.Copy()
+	sidecar.Name = "sidecar"
+	sidecar.Lifecycle = models.TaskLifecycleSidecar
+	sidecar.ResourcesConfig = &models.ResourcesConfig{Memory: "1GB"}
+	execution.Job.Tasks = append(execution.Job.Tasks, sidecar)
+
+	// the defaults apply to the main task, even though the sidecar requested memory
+	calculator := capacity.NewDefaultsUsageCalculator(capacity.DefaultsUsageCalculatorParams{
+		Defaults: models.Resources{CPU: 0.5, Memory: 256_000_000},
+	})
+	total, err := execution.Job.TotalResources()
+	require.NoError(t, err)
+	allocated, err := calculator.Calculate(context.Background(), *execution.Job, *total)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Resources{CPU: 0.5, Memory: 1_256_000_000}, allocated)
+	execution.AllocateResources(main.Name, *allocated)
+
+	args, cleanup, err := compute.PrepareRunArguments(context.Background(), nil, t.TempDir(), execution, main, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, cleanup(context.Background()))
+	assert.Equal(t, 0.5, args.Resources.CPU)
+	assert.Equal(t, uint64(256_000_000), args.Resources.Memory)
+
+	args, cleanup, err = compute.PrepareRunArguments(context.Background(), nil, t.TempDir(), execution, sidecar, t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, cleanup(context.Background()))
+	assert.Zero(t, args.Resources.CPU)
+	assert.Equal(t, uint64(1_000_000_000), args.Resources.Memory)
+}
@@ -10,16 +10,23 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
+// SecretProvider returns the values of the secrets of an execution, which are redacted from its logs
+type SecretProvider interface {
+	Get(executionID string) models.SecretValues
+}
+
 type ServerParams struct {
 	ExecutionStore store.ExecutionStore
 	Executors      executor.ExecutorProvider
 	Buffer         int
+	Secrets        SecretProvider
 }
 
 type Server struct {
 	executionStore store.ExecutionStore
 	executors      executor.ExecutorProvider
 	buffer         int
+	secrets        SecretProvider
 }
 
 // NewServer creates a new log stream server
@@ -28,6 +35,7 @@ func NewServer(params ServerParams) *Server {
 		executionStore: params.ExecutionStore,
 		executors:      params.Executors,
 		buffer:         params.Buffer,
+		secrets:        params.Secrets,
 	}
 }
 
@@ -57,5 +65,33 @@ func (s *Server) GetLogStream(ctx context.Context, request executor.LogStreamReq
 		Buffer: s.buffer,
 	})
 
-	return streamer.Stream(ctx), nil
+	stream := streamer.Stream(ctx)
+	if s.secrets == nil {
+		return stream, nil
+	}
+	secrets := s.secrets.Get(request.ExecutionID)
+	if len(secrets) == 0 {
+		return stream, nil
+	}
+	return redactStream(ctx, stream, secrets), nil
+}
+
+// redactStream replaces the values of the secrets found in the lines of a log stream
+func redactStream(ctx context.Context, stream <-chan *concurrency.AsyncResult[models.ExecutionLog],
+	secrets models.SecretValues) <-chan *concurrency.AsyncResult[models.ExecutionLog] {
+	redacted := make(chan *concurrency.AsyncResult[models.ExecutionLog], cap(stream))
+	go func() {
+		defer close(redacted)
+		for result := range stream {
+			if result != nil {
+				result.Value.Line = secrets.Redact(result.Value.Line)
+			}
+			select {
+			case redacted <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return redacted
 }
@@ -0,0 +1,26 @@
+//go:build unit || !integration
+
+package logstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestRedactStream(t *testing.T) {
+	stream := make(chan *concurrency.AsyncResult[models.ExecutionLog], 2)
+	stream <- concurrency.NewAsyncValue(models.ExecutionLog{Type: models.ExecutionLogTypeSTDOUT, Line: "token is s3cr3t"})
+	stream <- concurrency.NewAsyncValue(models.ExecutionLog{Type: models.ExecutionLogTypeSTDERR, Line: "no secret here"})
+	close(stream)
+
+	var lines []string
+	for result := range redactStream(context.Background(), stream, models.SecretValues{"token": "s3cr3t"}) {
+		lines = append(lines, result.Value.Line)
+	}
+	assert.Equal(t, []string{"token is [REDACTED]", "no secret here"}, lines)
+}
@@ -8,8 +8,12 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/cache"
 )
 
+// maxAdvertisedCachedInputs limits the number of cached inputs shared in the node info
+const maxAdvertisedCachedInputs = 256
+
 type NodeInfoDecoratorParams struct {
 	Executors          executor.ExecutorProvider
 	Publisher          publisher.PublisherProvider
@@ -17,6 +21,8 @@ type NodeInfoDecoratorParams struct {
 	CapacityTracker    capacity.Tracker
 	ExecutorBuffer     *ExecutorBuffer
 	MaxJobRequirements models.Resources
+	// InputCache is the input cache of the node, or nil if inputs are not cached
+	InputCache *cache.InputCache
 }
 
 type NodeInfoDecorator struct {
@@ -26,6 +32,7 @@ type NodeInfoDecorator struct {
 	capacityTracker    capacity.Tracker
 	executorBuffer     *ExecutorBuffer
 	maxJobRequirements models.Resources
+	inputCache         *cache.InputCache
 }
 
 func NewNodeInfoDecorator(params NodeInfoDecoratorParams) *NodeInfoDecorator {
@@ -36,6 +43,7 @@ func NewNodeInfoDecorator(params NodeInfoDecoratorParams) *NodeInfoDecorator {
 		capacityTracker:    params.CapacityTracker,
 		executorBuffer:     params.ExecutorBuffer,
 		maxJobRequirements: params.MaxJobRequirements,
+		inputCache:         params.InputCache,
 	}
 }
 
@@ -51,6 +59,9 @@ func (n *NodeInfoDecorator) DecorateNodeInfo(ctx context.Context, nodeInfo model
 		RunningExecutions:  len(n.executorBuffer.RunningExecutions()),
 		EnqueuedExecutions: n.executorBuffer.EnqueuedExecutionsCount(),
 	}
+	if n.inputCache != nil {
+		nodeInfo.ComputeNodeInfo.CachedInputs = n.inputCache.Keys(maxAdvertisedCachedInputs)
+	}
 	return nodeInfo
 }
 
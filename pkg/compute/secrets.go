@@ -0,0 +1,154 @@
+package compute
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// redactChunkSize is the size of the chunks the result files are redacted by
+const redactChunkSize = 1024 * 1024
+
+// ExecutionSecrets holds the values of the secrets referenced by executions, keyed by execution ID.
+// Values are sent by the requester along with the request to run an execution, and are only kept in
+// memory until the execution completes, so that they are never persisted on the compute node.
+// A nil ExecutionSecrets holds no secrets.
+type ExecutionSecrets struct {
+	mu      sync.RWMutex
+	secrets map[string]models.SecretValues
+}
+
+// NewExecutionSecrets creates an empty registry of execution secrets
+func NewExecutionSecrets() *ExecutionSecrets {
+	return &ExecutionSecrets{
+		secrets: make(map[string]models.SecretValues),
+	}
+}
+
+// Put records the secrets of an execution, replacing any previously recorded ones
+func (s *ExecutionSecrets) Put(executionID string, values models.SecretValues) {
+	if s == nil || len(values) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[executionID] = values
+}
+
+// Get returns the secrets of an execution, or nil if it has none
+func (s *ExecutionSecrets) Get(executionID string) models.SecretValues {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.secrets[executionID]
+}
+
+// Delete forgets the secrets of an execution
+func (s *ExecutionSecrets) Delete(executionID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, executionID)
+}
+
+// redactRunResult replaces the values of the secrets found in the outputs of a run
+func redactRunResult(values models.SecretValues, result *models.RunCommandResult) {
+	if result == nil || len(values) == 0 {
+		return
+	}
+	result.STDOUT = values.Redact(result.STDOUT)
+	result.STDERR = values.Redact(result.STDERR)
+	result.ErrorMsg = values.Redact(result.ErrorMsg)
+}
+
+// redactResultFiles replaces the values of the secrets found in the stdout and stderr files that the tasks of an
+// execution wrote to its results directory, or to the directories of the other tasks in it, before they are published.
+func redactResultFiles(values models.SecretValues, resultsDir string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	return filepath.WalkDir(resultsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != resultsDir && filepath.Dir(path) != resultsDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() &&
+			(entry.Name() == models.DownloadFilenameStdout || entry.Name() == models.DownloadFilenameStderr) {
+			return redactFile(values, path)
+		}
+		return nil
+	})
+}
+
+// redactFile replaces the values of the secrets found in the file. The file is redacted by chunks, holding back
+// the end of each chunk that could be the start of a value continuing in the next chunk.
+func redactFile(values models.SecretValues, path string) error {
+	maxLen := 0
+	for _, value := range values {
+		maxLen = max(maxLen, len(value))
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	writer := bufio.NewWriter(out)
+	reader := bufio.NewReader(in)
+	chunk := make([]byte, redactChunkSize)
+	pending := ""
+	for {
+		n, readErr := io.ReadFull(reader, chunk)
+		text := values.Redact(pending + string(chunk[:n]))
+		eof := readErr == io.EOF || readErr == io.ErrUnexpectedEOF
+		if readErr != nil && !eof {
+			return readErr
+		}
+		keep := 0
+		if !eof {
+			keep = max(0, min(maxLen-1, len(text)))
+		}
+		if _, err = writer.WriteString(text[:len(text)-keep]); err != nil {
+			return err
+		}
+		pending = text[len(text)-keep:]
+		if eof {
+			break
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(out.Name(), info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), path)
+}
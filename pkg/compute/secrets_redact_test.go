@@ -0,0 +1,57 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestRedactResultFiles(t *testing.T) {
+	values := models.SecretValues{"token": "s3cr3t", "empty": ""}
+	resultsDir := t.TempDir()
+	// the second value straddles two chunks
+	stdout := "token=s3cr3t\n" + strings.Repeat("x", redactChunkSize-3) + "s3cr3t end"
+	files := map[string]string{
+		models.DownloadFilenameStdout:                        stdout,
+		models.DownloadFilenameStderr:                        "error: s3cr3t",
+		filepath.Join("logs", models.DownloadFilenameStdout): "sidecar s3cr3t",
+		filepath.Join("outputs", "result.txt"):               "output s3cr3t",
+		filepath.Join("outputs", "nested", "stdout"):         "nested s3cr3t",
+		models.DownloadFilenameExitCode:                      "0",
+	}
+	for name, content := range files {
+		path := filepath.Join(resultsDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	require.NoError(t, redactResultFiles(values, resultsDir))
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(resultsDir, name))
+		require.NoError(t, err)
+		return string(content)
+	}
+	assert.Equal(t, strings.ReplaceAll(stdout, "s3cr3t", models.RedactedSecretValue), read(models.DownloadFilenameStdout))
+	assert.Equal(t, "error: [REDACTED]", read(models.DownloadFilenameStderr))
+	assert.Equal(t, "sidecar [REDACTED]", read(filepath.Join("logs", models.DownloadFilenameStdout)))
+	assert.Equal(t, "output s3cr3t", read(filepath.Join("outputs", "result.txt")), "outputs of the job are left as is")
+	assert.Equal(t, "nested s3cr3t", read(filepath.Join("outputs", "nested", "stdout")))
+	assert.Equal(t, "0", read(models.DownloadFilenameExitCode))
+
+	entries, err := os.ReadDir(resultsDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 5, "no temporary files are left behind")
+}
+
+func TestRedactResultFiles_NoSecrets(t *testing.T) {
+	assert.NoError(t, redactResultFiles(nil, filepath.Join(t.TempDir(), "missing")))
+}
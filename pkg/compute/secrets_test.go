@@ -0,0 +1,32 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestExecutionSecrets(t *testing.T) {
+	secrets := compute.NewExecutionSecrets()
+	assert.Nil(t, secrets.Get("e-1"))
+
+	secrets.Put("e-1", models.SecretValues{"token": "s3cr3t"})
+	secrets.Put("e-2", nil)
+	assert.Equal(t, models.SecretValues{"token": "s3cr3t"}, secrets.Get("e-1"))
+	assert.Nil(t, secrets.Get("e-2"))
+
+	secrets.Delete("e-1")
+	assert.Nil(t, secrets.Get("e-1"))
+}
+
+func TestExecutionSecrets_Nil(t *testing.T) {
+	var secrets *compute.ExecutionSecrets
+	secrets.Put("e-1", models.SecretValues{"token": "s3cr3t"})
+	assert.Nil(t, secrets.Get("e-1"))
+	secrets.Delete("e-1")
+}
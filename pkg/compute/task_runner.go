@@ -0,0 +1,258 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+// taskRunner runs the prestart, sidecar and poststop tasks of an execution next to its main task,
+// and tracks the state of all of its tasks.
+// All tasks share the results directory of the execution. The main task writes its outputs to the root
+// of the results directory, as single task jobs always did, while the other tasks write theirs to a
+// sub-directory named after the task, so that their logs do not overwrite each other.
+type taskRunner struct {
+	executors        executor.ExecutorProvider
+	storages         storage.StorageProvider
+	storageDirectory string
+	resultsDir       string
+	execution        *models.Execution
+	secrets          models.SecretValues
+
+	mu       sync.Mutex
+	states   map[string]*models.TaskState
+	cleanups []InputCleanupFn
+	// stopped is true once the sidecars were stopped and the poststop tasks were run
+	stopped bool
+}
+
+func newTaskRunner(
+	executors executor.ExecutorProvider,
+	storages storage.StorageProvider,
+	storageDirectory string,
+	resultsDir string,
+	execution *models.Execution,
+	secrets models.SecretValues,
+) *taskRunner {
+	return &taskRunner{
+		executors:        executors,
+		storages:         storages,
+		storageDirectory: storageDirectory,
+		resultsDir:       resultsDir,
+		execution:        execution,
+		secrets:          secrets,
+		states:           models.NewTaskStates(execution.Job),
+	}
+}
+
+// taskExecutionID returns the ID the task is run with by its executor. The main task is run with the
+// execution ID itself, so that logs and cancellation keep addressing it directly.
+func taskExecutionID(execution *models.Execution, task *models.Task) string {
+	if task.IsMain() {
+		return execution.ID
+	}
+	return fmt.Sprintf("%s-%s", execution.ID, task.Name)
+}
+
+// taskResultsDir returns the results directory of a non-main task, which is a sub-directory of the results
+// directory of the execution named after the task. Task names are validated on submission, but the path is
+// checked again as it is created on the compute node.
+func taskResultsDir(resultsDir string, task *models.Task) (string, error) {
+	dir := filepath.Join(resultsDir, task.Name)
+	if rel, err := filepath.Rel(resultsDir, dir); err != nil || rel != task.Name {
+		return "", fmt.Errorf("task name %q is not a valid results directory name", task.Name)
+	}
+	return dir, nil
+}
+
+// runToCompletion runs the tasks one after the other, and fails on the first task that does not succeed.
+func (r *taskRunner) runToCompletion(ctx context.Context, tasks []*models.Task) error {
+	for _, task := range tasks {
+		if err := r.runTask(ctx, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTask runs the task to completion, within its execution timeout if it has one.
+func (r *taskRunner) runTask(ctx context.Context, task *models.Task) error {
+	if task.Timeouts != nil && task.Timeouts.GetExecutionTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeouts.GetExecutionTimeout())
+		defer cancel()
+	}
+	if err := r.start(ctx, task); err != nil && !errors.Is(err, executor.ErrAlreadyStarted) {
+		return err
+	}
+	result, err := r.wait(ctx, task)
+	if err != nil {
+		return err
+	}
+	if result.ErrorMsg != "" {
+		return fmt.Errorf("%s task %s failed: %s", task.GetLifecycle(), task.Name, result.ErrorMsg)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("%s task %s failed with exit code %d", task.GetLifecycle(), task.Name, result.ExitCode)
+	}
+	return nil
+}
+
+// stopAndRunPoststop stops the sidecars once the main task is done, and then runs the poststop tasks to
+// completion. It only runs once, so that it can both be called once the main task succeeded, to fail the
+// execution if a poststop task fails, and be deferred to run the poststop tasks on every other exit path.
+func (r *taskRunner) stopAndRunPoststop(ctx context.Context, job *models.Job) error {
+	r.mu.Lock()
+	stopped := r.stopped
+	r.stopped = true
+	r.mu.Unlock()
+	if stopped {
+		return nil
+	}
+	r.stop(ctx, job.TasksWithLifecycle(models.TaskLifecycleSidecar))
+	return r.runToCompletion(ctx, job.TasksWithLifecycle(models.TaskLifecyclePoststop))
+}
+
+// startAll starts the tasks without waiting for them to complete.
+func (r *taskRunner) startAll(ctx context.Context, tasks []*models.Task) error {
+	for _, task := range tasks {
+		if err := r.start(ctx, task); err != nil && !errors.Is(err, executor.ErrAlreadyStarted) {
+			return err
+		}
+	}
+	return nil
+}
+
+// start prepares the inputs of a non-main task and starts it.
+func (r *taskRunner) start(ctx context.Context, task *models.Task) error {
+	taskExecutor, err := r.executors.Get(ctx, task.Engine.Type)
+	if err != nil {
+		return r.fail(task, fmt.Errorf("getting executor %s of task %s: %w", task.Engine, task.Name, err))
+	}
+
+	resultsDir, err := taskResultsDir(r.resultsDir, task)
+	if err != nil {
+		return r.fail(task, err)
+	}
+	if err = os.MkdirAll(resultsDir, StorageDirectoryPerms); err != nil {
+		return r.fail(task, fmt.Errorf("preparing results path of task %s: %w", task.Name, err))
+	}
+
+	args, cleanup, err := PrepareRunArguments(ctx, r.storages, r.storageDirectory, r.execution, task, resultsDir)
+	if cleanup != nil {
+		r.mu.Lock()
+		r.cleanups = append(r.cleanups, cleanup)
+		r.mu.Unlock()
+	}
+	if err != nil {
+		return r.fail(task, fmt.Errorf("preparing arguments of task %s: %w", task.Name, err))
+	}
+	if args.Env, err = r.secrets.ResolveEnv(args.Env); err != nil {
+		return r.fail(task, fmt.Errorf("resolving secrets of task %s: %w", task.Name, err))
+	}
+
+	log.Ctx(ctx).Debug().Msgf("starting %s task %s", task.GetLifecycle(), task.Name)
+	if err = taskExecutor.Start(ctx, args); err != nil && !errors.Is(err, executor.ErrAlreadyStarted) {
+		return r.fail(task, fmt.Errorf("starting task %s: %w", task.Name, err))
+	}
+	r.setState(task.Name, models.TaskStateRunning, 0, "")
+	return err
+}
+
+// wait waits for a non-main task to complete and records its result.
+func (r *taskRunner) wait(ctx context.Context, task *models.Task) (*models.RunCommandResult, error) {
+	taskExecutor, err := r.executors.Get(ctx, task.Engine.Type)
+	if err != nil {
+		return nil, r.fail(task, fmt.Errorf("getting executor %s of task %s: %w", task.Engine, task.Name, err))
+	}
+	waitC, errC := taskExecutor.Wait(ctx, taskExecutionID(r.execution, task))
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-waitC:
+		redactRunResult(r.secrets, res)
+		r.recordResult(task.Name, res)
+		return res, nil
+	case err = <-errC:
+		return nil, r.fail(task, fmt.Errorf("waiting on task %s: %w", task.Name, err))
+	}
+}
+
+// stop cancels the tasks that are still running, such as sidecars once the main task completed.
+func (r *taskRunner) stop(ctx context.Context, tasks []*models.Task) {
+	for _, task := range tasks {
+		if r.state(task.Name) != models.TaskStateRunning {
+			continue
+		}
+		taskExecutor, err := r.executors.Get(ctx, task.Engine.Type)
+		if err == nil {
+			err = taskExecutor.Cancel(ctx, taskExecutionID(r.execution, task))
+		}
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to stop %s task %s", task.GetLifecycle(), task.Name)
+			continue
+		}
+		r.setState(task.Name, models.TaskStateStopped, 0, "")
+	}
+}
+
+// recordResult records the state of a task from the result of its run.
+func (r *taskRunner) recordResult(taskName string, result *models.RunCommandResult) {
+	if result.ErrorMsg != "" || result.ExitCode != 0 {
+		r.setState(taskName, models.TaskStateFailed, result.ExitCode, result.ErrorMsg)
+	} else {
+		r.setState(taskName, models.TaskStateCompleted, result.ExitCode, "")
+	}
+}
+
+func (r *taskRunner) fail(task *models.Task, err error) error {
+	r.setState(task.Name, models.TaskStateFailed, 0, err.Error())
+	return err
+}
+
+func (r *taskRunner) setState(taskName string, state string, exitCode int, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if taskState, ok := r.states[taskName]; ok {
+		taskState.State = state
+		taskState.ExitCode = exitCode
+		taskState.Message = message
+	}
+}
+
+func (r *taskRunner) state(taskName string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if taskState, ok := r.states[taskName]; ok {
+		return taskState.State
+	}
+	return ""
+}
+
+// taskStates returns a snapshot of the state of all tasks.
+func (r *taskRunner) taskStates() map[string]*models.TaskState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return models.CopyTaskStates(r.states)
+}
+
+// cleanup cleans up the inputs prepared for the tasks.
+func (r *taskRunner) cleanup(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var cleanupErr error
+	for _, cleanup := range r.cleanups {
+		cleanupErr = errors.Join(cleanupErr, cleanup(ctx))
+	}
+	r.cleanups = nil
+	return cleanupErr
+}
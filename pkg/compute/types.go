@@ -25,6 +25,10 @@ type Endpoint interface {
 	CancelExecution(context.Context, CancelExecutionRequest) (CancelExecutionResponse, error)
 	// ExecutionLogs returns the address of a suitable log server
 	ExecutionLogs(ctx context.Context, request ExecutionLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error)
+	// Exec runs a command in, or attaches to, an ongoing execution. Input is forwarded to the command
+	// and its output is streamed back until it exits, or the client detaches by closing the input channel.
+	Exec(ctx context.Context, request ExecRequest, input <-chan models.ExecInput) (
+		<-chan *concurrency.AsyncResult[models.ExecOutput], error)
 }
 
 // Executor Backend service that is responsible for running and publishing executions.
@@ -42,6 +46,7 @@ type Callback interface {
 	OnRunComplete(ctx context.Context, result RunResult)
 	OnCancelComplete(ctx context.Context, result CancelResult)
 	OnComputeFailure(ctx context.Context, err ComputeError)
+	OnCheckpoint(ctx context.Context, result CheckpointResult)
 }
 
 // ManagementEndpoint is the transport-based interface for compute nodes to
@@ -84,6 +89,8 @@ type AskForBidRequest struct {
 	// if set to true, the compute node will not start the execution until the requester approves the bid.
 	// If set to false, the compute node will automatically start the execution after bidding and when resources are available.
 	WaitForApproval bool
+	// Secrets holds the values of the secrets referenced by the job, when the execution does not wait for approval.
+	Secrets models.SecretValues
 }
 
 type AskForBidResponse struct {
@@ -95,6 +102,8 @@ type BidAcceptedRequest struct {
 	ExecutionID   string
 	Accepted      bool
 	Justification string
+	// Secrets holds the values of the secrets referenced by the job of the execution.
+	Secrets models.SecretValues
 }
 
 type BidAcceptedResponse struct {
@@ -128,6 +137,15 @@ type ExecutionLogsRequest struct {
 	Follow      bool
 }
 
+type ExecRequest struct {
+	RoutingMetadata
+	ExecutionID string
+	Command     []string
+	Attach      bool
+	TTY         bool
+	Stdin       bool
+}
+
 type ExecutionLogsResponse struct {
 	Address           string
 	ExecutionFinished bool
@@ -153,18 +171,32 @@ type RunResult struct {
 	ExecutionMetadata
 	PublishResult    *models.SpecConfig
 	RunCommandResult *models.RunCommandResult
+	TaskStates       map[string]*models.TaskState
 }
 
 // CancelResult Result of a job cancel that is returned to the caller through a Callback.
 type CancelResult struct {
 	RoutingMetadata
 	ExecutionMetadata
+	// Egress is the log of the outbound requests of the execution before it was canceled, if any
+	Egress *models.EgressLog
+}
+
+// CheckpointResult is a checkpoint taken of a running execution that is returned to the caller through a Callback.
+type CheckpointResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Checkpoint models.Checkpoint
 }
 
 type ComputeError struct {
 	RoutingMetadata
 	ExecutionMetadata
 	Event models.Event
+	// ResourceUsage is the usage of the execution before it failed, if any was measured
+	ResourceUsage *models.ResourceUsage
+	// Egress is the log of the outbound requests of the execution before it failed, if any
+	Egress *models.EgressLog
 }
 
 func (e ComputeError) Error() string {
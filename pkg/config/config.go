@@ -39,7 +39,9 @@ const (
 
 var (
 	ComputeExecutionsStorePath = filepath.Join(ComputeStorePath, "executions.db")
+	ComputeInputCachePath      = filepath.Join(ComputeStorePath, "input_cache")
 	OrchestratorJobStorePath   = filepath.Join(OrchestratorStorePath, "jobs.db")
+	OrchestratorJobArchivePath = filepath.Join(OrchestratorStorePath, "archive")
 )
 
 var (
@@ -76,7 +78,9 @@ func getDefaultConfig(path string) types.BacalhauConfig {
 	defaultConfig.Node.ExecutorPluginPath = filepath.Join(path, PluginsPath)
 	defaultConfig.Node.ComputeStoragePath = filepath.Join(path, ComputeStoragesPath)
 	defaultConfig.Node.Compute.ExecutionStore.Path = filepath.Join(path, ComputeExecutionsStorePath)
+	defaultConfig.Node.Compute.InputCache.Path = filepath.Join(path, ComputeInputCachePath)
 	defaultConfig.Node.Requester.JobStore.Path = filepath.Join(path, OrchestratorJobStorePath)
+	defaultConfig.Node.Requester.JobRetention.Archive.Path = filepath.Join(path, OrchestratorJobArchivePath)
 	defaultConfig.Update.CheckStatePath = filepath.Join(path, UpdateCheckStatePath)
 	defaultConfig.Auth.TokensPath = filepath.Join(path, TokensPath)
 
@@ -111,6 +111,7 @@ func TestConfig(t *testing.T) {
 				expected := configenv.Testing
 				configPath := t.TempDir()
 				expected.Node.Requester.JobStore.Path = filepath.Join(configPath, OrchestratorJobStorePath)
+				expected.Node.Requester.JobRetention.Archive.Path = filepath.Join(configPath, OrchestratorJobArchivePath)
 
 				_, err := Init(configPath)
 				require.NoError(t, err)
@@ -134,6 +134,10 @@ var TestingComputeConfig = types.ComputeConfig{
 	Logging: types.LoggingConfig{
 		LogRunningExecutionsInterval: types.Duration(10 * time.Second),
 	},
+	InputCache: types.InputCacheConfig{
+		MaxSize: "10GB",
+		Sources: []string{models.StorageSourceIPFS, models.StorageSourceS3, models.StorageSourceURL},
+	},
 	ManifestCache: types.DockerCacheConfig{
 		Size:      1000,
 		Duration:  types.Duration(1 * time.Hour),
@@ -130,6 +130,17 @@ func ShouldKeepStack() bool {
 	return os.Getenv("KEEP_STACK") != ""
 }
 
+// DockerExecutionCgroupsEnvVar opts in to running each docker container in a cgroup of its own,
+// so that its peak memory can be read once it exits.
+const DockerExecutionCgroupsEnvVar = "BACALHAU_DOCKER_EXECUTION_CGROUPS"
+
+// ShouldUseDockerExecutionCgroups returns true if docker containers should run in a cgroup of their own.
+// This overrides the cgroup parent configured in the docker daemon, and needs the cgroup filesystem
+// of the host, so it is off by default.
+func ShouldUseDockerExecutionCgroups() bool {
+	return os.Getenv(DockerExecutionCgroupsEnvVar) != ""
+}
+
 const (
 	DockerUsernameEnvVar = "DOCKER_USERNAME"
 	DockerPasswordEnvVar = "DOCKER_PASSWORD"
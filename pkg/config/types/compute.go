@@ -16,6 +16,7 @@ type ComputeConfig struct {
 	LogStreamConfig      LogStreamConfig           `yaml:"LogStream"`
 	LocalPublisher       LocalPublisherConfig      `yaml:"LocalPublisher"`
 	ControlPlaneSettings ComputeControlPlaneConfig `yaml:"ClusterTimeouts"`
+	InputCache           InputCacheConfig          `yaml:"InputCache"`
 }
 
 type CapacityConfig struct {
@@ -64,6 +65,21 @@ type LocalPublisherConfig struct {
 	Directory string `yaml:"Directory"`
 }
 
+type InputCacheConfig struct {
+	// Enabled caches the data of input sources on the compute node, so that executions using the same
+	// inputs do not fetch them again.
+	Enabled bool `yaml:"Enabled"`
+	// Path is the directory the cached inputs are stored in
+	Path string `yaml:"Path"`
+	// MaxSize is the maximum total size of the cached inputs, e.g. 10GB. The least recently used inputs
+	// are evicted to make room for new ones.
+	MaxSize string `yaml:"MaxSize"`
+	// TTL is how long inputs are cached for after they are fetched. Zero caches them until they are evicted.
+	TTL Duration `yaml:"TTL"`
+	// Sources are the types of input sources that are cached
+	Sources []string `yaml:"Sources"`
+}
+
 type ComputeControlPlaneConfig struct {
 	// The frequency with which the compute node will send node info (inc current labels)
 	// to the controlling requester node.
@@ -52,21 +52,25 @@ const NodeComputeCapacityTotalResourceLimitsCPU = "Node.Compute.Capacity.TotalRe
 const NodeComputeCapacityTotalResourceLimitsMemory = "Node.Compute.Capacity.TotalResourceLimits.Memory"
 const NodeComputeCapacityTotalResourceLimitsDisk = "Node.Compute.Capacity.TotalResourceLimits.Disk"
 const NodeComputeCapacityTotalResourceLimitsGPU = "Node.Compute.Capacity.TotalResourceLimits.GPU"
+const NodeComputeCapacityTotalResourceLimitsGPUSelector = "Node.Compute.Capacity.TotalResourceLimits.GPUSelector"
 const NodeComputeCapacityJobResourceLimits = "Node.Compute.Capacity.JobResourceLimits"
 const NodeComputeCapacityJobResourceLimitsCPU = "Node.Compute.Capacity.JobResourceLimits.CPU"
 const NodeComputeCapacityJobResourceLimitsMemory = "Node.Compute.Capacity.JobResourceLimits.Memory"
 const NodeComputeCapacityJobResourceLimitsDisk = "Node.Compute.Capacity.JobResourceLimits.Disk"
 const NodeComputeCapacityJobResourceLimitsGPU = "Node.Compute.Capacity.JobResourceLimits.GPU"
+const NodeComputeCapacityJobResourceLimitsGPUSelector = "Node.Compute.Capacity.JobResourceLimits.GPUSelector"
 const NodeComputeCapacityDefaultJobResourceLimits = "Node.Compute.Capacity.DefaultJobResourceLimits"
 const NodeComputeCapacityDefaultJobResourceLimitsCPU = "Node.Compute.Capacity.DefaultJobResourceLimits.CPU"
 const NodeComputeCapacityDefaultJobResourceLimitsMemory = "Node.Compute.Capacity.DefaultJobResourceLimits.Memory"
 const NodeComputeCapacityDefaultJobResourceLimitsDisk = "Node.Compute.Capacity.DefaultJobResourceLimits.Disk"
 const NodeComputeCapacityDefaultJobResourceLimitsGPU = "Node.Compute.Capacity.DefaultJobResourceLimits.GPU"
+const NodeComputeCapacityDefaultJobResourceLimitsGPUSelector = "Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector"
 const NodeComputeCapacityQueueResourceLimits = "Node.Compute.Capacity.QueueResourceLimits"
 const NodeComputeCapacityQueueResourceLimitsCPU = "Node.Compute.Capacity.QueueResourceLimits.CPU"
 const NodeComputeCapacityQueueResourceLimitsMemory = "Node.Compute.Capacity.QueueResourceLimits.Memory"
 const NodeComputeCapacityQueueResourceLimitsDisk = "Node.Compute.Capacity.QueueResourceLimits.Disk"
 const NodeComputeCapacityQueueResourceLimitsGPU = "Node.Compute.Capacity.QueueResourceLimits.GPU"
+const NodeComputeCapacityQueueResourceLimitsGPUSelector = "Node.Compute.Capacity.QueueResourceLimits.GPUSelector"
 const NodeComputeExecutionStore = "Node.Compute.ExecutionStore"
 const NodeComputeExecutionStoreType = "Node.Compute.ExecutionStore.Type"
 const NodeComputeExecutionStorePath = "Node.Compute.ExecutionStore.Path"
@@ -100,6 +104,12 @@ const NodeComputeControlPlaneSettingsInfoUpdateFrequency = "Node.Compute.Control
 const NodeComputeControlPlaneSettingsResourceUpdateFrequency = "Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency"
 const NodeComputeControlPlaneSettingsHeartbeatFrequency = "Node.Compute.ControlPlaneSettings.HeartbeatFrequency"
 const NodeComputeControlPlaneSettingsHeartbeatTopic = "Node.Compute.ControlPlaneSettings.HeartbeatTopic"
+const NodeComputeInputCache = "Node.Compute.InputCache"
+const NodeComputeInputCacheEnabled = "Node.Compute.InputCache.Enabled"
+const NodeComputeInputCachePath = "Node.Compute.InputCache.Path"
+const NodeComputeInputCacheMaxSize = "Node.Compute.InputCache.MaxSize"
+const NodeComputeInputCacheTTL = "Node.Compute.InputCache.TTL"
+const NodeComputeInputCacheSources = "Node.Compute.InputCache.Sources"
 const NodeRequester = "Node.Requester"
 const NodeRequesterJobDefaults = "Node.Requester.JobDefaults"
 const NodeRequesterJobDefaultsExecutionTimeout = "Node.Requester.JobDefaults.ExecutionTimeout"
@@ -113,6 +123,18 @@ const NodeRequesterJobSelectionPolicyProbeExec = "Node.Requester.JobSelectionPol
 const NodeRequesterJobStore = "Node.Requester.JobStore"
 const NodeRequesterJobStoreType = "Node.Requester.JobStore.Type"
 const NodeRequesterJobStorePath = "Node.Requester.JobStore.Path"
+const NodeRequesterJobRetention = "Node.Requester.JobRetention"
+const NodeRequesterJobRetentionMaxAge = "Node.Requester.JobRetention.MaxAge"
+const NodeRequesterJobRetentionKeepLast = "Node.Requester.JobRetention.KeepLast"
+const NodeRequesterJobRetentionGroupByLabel = "Node.Requester.JobRetention.GroupByLabel"
+const NodeRequesterJobRetentionInterval = "Node.Requester.JobRetention.Interval"
+const NodeRequesterJobRetentionArchive = "Node.Requester.JobRetention.Archive"
+const NodeRequesterJobRetentionArchiveType = "Node.Requester.JobRetention.Archive.Type"
+const NodeRequesterJobRetentionArchivePath = "Node.Requester.JobRetention.Archive.Path"
+const NodeRequesterJobRetentionArchiveBucket = "Node.Requester.JobRetention.Archive.Bucket"
+const NodeRequesterJobRetentionArchivePrefix = "Node.Requester.JobRetention.Archive.Prefix"
+const NodeRequesterJobRetentionArchiveEndpoint = "Node.Requester.JobRetention.Archive.Endpoint"
+const NodeRequesterJobRetentionArchiveRegion = "Node.Requester.JobRetention.Archive.Region"
 const NodeRequesterHousekeepingBackgroundTaskInterval = "Node.Requester.HousekeepingBackgroundTaskInterval"
 const NodeRequesterNodeRankRandomnessRange = "Node.Requester.NodeRankRandomnessRange"
 const NodeRequesterOverAskForBidsFactor = "Node.Requester.OverAskForBidsFactor"
@@ -133,6 +155,8 @@ const NodeRequesterStorageProvider = "Node.Requester.StorageProvider"
 const NodeRequesterStorageProviderS3 = "Node.Requester.StorageProvider.S3"
 const NodeRequesterStorageProviderS3PreSignedURLDisabled = "Node.Requester.StorageProvider.S3.PreSignedURLDisabled"
 const NodeRequesterStorageProviderS3PreSignedURLExpiration = "Node.Requester.StorageProvider.S3.PreSignedURLExpiration"
+const NodeRequesterPreemption = "Node.Requester.Preemption"
+const NodeRequesterPreemptionEnabled = "Node.Requester.Preemption.Enabled"
 const NodeRequesterTagCache = "Node.Requester.TagCache"
 const NodeRequesterTagCacheSize = "Node.Requester.TagCache.Size"
 const NodeRequesterTagCacheDuration = "Node.Requester.TagCache.Duration"
@@ -149,6 +173,9 @@ const NodeDownloadURLRequestTimeout = "Node.DownloadURLRequestTimeout"
 const NodeVolumeSizeRequestTimeout = "Node.VolumeSizeRequestTimeout"
 const NodeNodeInfoStoreTTL = "Node.NodeInfoStoreTTL"
 const NodeExecutorPluginPath = "Node.ExecutorPluginPath"
+const NodePlugins = "Node.Plugins"
+const NodePluginsPublishers = "Node.Plugins.Publishers"
+const NodePluginsStorages = "Node.Plugins.Storages"
 const NodeComputeStoragePath = "Node.ComputeStoragePath"
 const NodeLoggingMode = "Node.LoggingMode"
 const NodeType = "Node.Type"
@@ -74,21 +74,25 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsMemory, cfg.Node.Compute.Capacity.TotalResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsDisk, cfg.Node.Compute.Capacity.TotalResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsGPU, cfg.Node.Compute.Capacity.TotalResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.TotalResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimits, cfg.Node.Compute.Capacity.JobResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsCPU, cfg.Node.Compute.Capacity.JobResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsMemory, cfg.Node.Compute.Capacity.JobResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsDisk, cfg.Node.Compute.Capacity.JobResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsGPU, cfg.Node.Compute.Capacity.JobResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.JobResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimits, cfg.Node.Compute.Capacity.DefaultJobResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsCPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsMemory, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsDisk, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsGPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimits, cfg.Node.Compute.Capacity.QueueResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsCPU, cfg.Node.Compute.Capacity.QueueResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsMemory, cfg.Node.Compute.Capacity.QueueResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsDisk, cfg.Node.Compute.Capacity.QueueResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsGPU, cfg.Node.Compute.Capacity.QueueResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.QueueResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.SetDefault(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.SetDefault(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
@@ -122,6 +126,12 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeControlPlaneSettingsResourceUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency.AsTimeDuration())
 	p.Viper.SetDefault(NodeComputeControlPlaneSettingsHeartbeatFrequency, cfg.Node.Compute.ControlPlaneSettings.HeartbeatFrequency.AsTimeDuration())
 	p.Viper.SetDefault(NodeComputeControlPlaneSettingsHeartbeatTopic, cfg.Node.Compute.ControlPlaneSettings.HeartbeatTopic)
+	p.Viper.SetDefault(NodeComputeInputCache, cfg.Node.Compute.InputCache)
+	p.Viper.SetDefault(NodeComputeInputCacheEnabled, cfg.Node.Compute.InputCache.Enabled)
+	p.Viper.SetDefault(NodeComputeInputCachePath, cfg.Node.Compute.InputCache.Path)
+	p.Viper.SetDefault(NodeComputeInputCacheMaxSize, cfg.Node.Compute.InputCache.MaxSize)
+	p.Viper.SetDefault(NodeComputeInputCacheTTL, cfg.Node.Compute.InputCache.TTL.AsTimeDuration())
+	p.Viper.SetDefault(NodeComputeInputCacheSources, cfg.Node.Compute.InputCache.Sources)
 	p.Viper.SetDefault(NodeRequester, cfg.Node.Requester)
 	p.Viper.SetDefault(NodeRequesterJobDefaults, cfg.Node.Requester.JobDefaults)
 	p.Viper.SetDefault(NodeRequesterJobDefaultsExecutionTimeout, cfg.Node.Requester.JobDefaults.ExecutionTimeout.AsTimeDuration())
@@ -135,6 +145,18 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeRequesterJobStore, cfg.Node.Requester.JobStore)
 	p.Viper.SetDefault(NodeRequesterJobStoreType, cfg.Node.Requester.JobStore.Type)
 	p.Viper.SetDefault(NodeRequesterJobStorePath, cfg.Node.Requester.JobStore.Path)
+	p.Viper.SetDefault(NodeRequesterJobRetention, cfg.Node.Requester.JobRetention)
+	p.Viper.SetDefault(NodeRequesterJobRetentionMaxAge, cfg.Node.Requester.JobRetention.MaxAge.AsTimeDuration())
+	p.Viper.SetDefault(NodeRequesterJobRetentionKeepLast, cfg.Node.Requester.JobRetention.KeepLast)
+	p.Viper.SetDefault(NodeRequesterJobRetentionGroupByLabel, cfg.Node.Requester.JobRetention.GroupByLabel)
+	p.Viper.SetDefault(NodeRequesterJobRetentionInterval, cfg.Node.Requester.JobRetention.Interval.AsTimeDuration())
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchive, cfg.Node.Requester.JobRetention.Archive)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchiveType, cfg.Node.Requester.JobRetention.Archive.Type)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchivePath, cfg.Node.Requester.JobRetention.Archive.Path)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchiveBucket, cfg.Node.Requester.JobRetention.Archive.Bucket)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchivePrefix, cfg.Node.Requester.JobRetention.Archive.Prefix)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchiveEndpoint, cfg.Node.Requester.JobRetention.Archive.Endpoint)
+	p.Viper.SetDefault(NodeRequesterJobRetentionArchiveRegion, cfg.Node.Requester.JobRetention.Archive.Region)
 	p.Viper.SetDefault(NodeRequesterHousekeepingBackgroundTaskInterval, cfg.Node.Requester.HousekeepingBackgroundTaskInterval.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequesterNodeRankRandomnessRange, cfg.Node.Requester.NodeRankRandomnessRange)
 	p.Viper.SetDefault(NodeRequesterOverAskForBidsFactor, cfg.Node.Requester.OverAskForBidsFactor)
@@ -155,6 +177,8 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeRequesterStorageProviderS3, cfg.Node.Requester.StorageProvider.S3)
 	p.Viper.SetDefault(NodeRequesterStorageProviderS3PreSignedURLDisabled, cfg.Node.Requester.StorageProvider.S3.PreSignedURLDisabled)
 	p.Viper.SetDefault(NodeRequesterStorageProviderS3PreSignedURLExpiration, cfg.Node.Requester.StorageProvider.S3.PreSignedURLExpiration.AsTimeDuration())
+	p.Viper.SetDefault(NodeRequesterPreemption, cfg.Node.Requester.Preemption)
+	p.Viper.SetDefault(NodeRequesterPreemptionEnabled, cfg.Node.Requester.Preemption.Enabled)
 	p.Viper.SetDefault(NodeRequesterTagCache, cfg.Node.Requester.TagCache)
 	p.Viper.SetDefault(NodeRequesterTagCacheSize, cfg.Node.Requester.TagCache.Size)
 	p.Viper.SetDefault(NodeRequesterTagCacheDuration, cfg.Node.Requester.TagCache.Duration.AsTimeDuration())
@@ -171,6 +195,9 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeVolumeSizeRequestTimeout, cfg.Node.VolumeSizeRequestTimeout.AsTimeDuration())
 	p.Viper.SetDefault(NodeNodeInfoStoreTTL, cfg.Node.NodeInfoStoreTTL.AsTimeDuration())
 	p.Viper.SetDefault(NodeExecutorPluginPath, cfg.Node.ExecutorPluginPath)
+	p.Viper.SetDefault(NodePlugins, cfg.Node.Plugins)
+	p.Viper.SetDefault(NodePluginsPublishers, cfg.Node.Plugins.Publishers)
+	p.Viper.SetDefault(NodePluginsStorages, cfg.Node.Plugins.Storages)
 	p.Viper.SetDefault(NodeComputeStoragePath, cfg.Node.ComputeStoragePath)
 	p.Viper.SetDefault(NodeLoggingMode, cfg.Node.LoggingMode)
 	p.Viper.SetDefault(NodeType, cfg.Node.Type)
@@ -272,21 +299,25 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsMemory, cfg.Node.Compute.Capacity.TotalResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsDisk, cfg.Node.Compute.Capacity.TotalResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsGPU, cfg.Node.Compute.Capacity.TotalResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.TotalResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimits, cfg.Node.Compute.Capacity.JobResourceLimits)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsCPU, cfg.Node.Compute.Capacity.JobResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsMemory, cfg.Node.Compute.Capacity.JobResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsDisk, cfg.Node.Compute.Capacity.JobResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsGPU, cfg.Node.Compute.Capacity.JobResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.JobResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimits, cfg.Node.Compute.Capacity.DefaultJobResourceLimits)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsCPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsMemory, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsDisk, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsGPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimits, cfg.Node.Compute.Capacity.QueueResourceLimits)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsCPU, cfg.Node.Compute.Capacity.QueueResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsMemory, cfg.Node.Compute.Capacity.QueueResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsDisk, cfg.Node.Compute.Capacity.QueueResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsGPU, cfg.Node.Compute.Capacity.QueueResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.QueueResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.Set(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.Set(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
@@ -320,6 +351,12 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeControlPlaneSettingsResourceUpdateFrequency, cfg.Node.Compute.ControlPlaneSettings.ResourceUpdateFrequency.AsTimeDuration())
 	p.Viper.Set(NodeComputeControlPlaneSettingsHeartbeatFrequency, cfg.Node.Compute.ControlPlaneSettings.HeartbeatFrequency.AsTimeDuration())
 	p.Viper.Set(NodeComputeControlPlaneSettingsHeartbeatTopic, cfg.Node.Compute.ControlPlaneSettings.HeartbeatTopic)
+	p.Viper.Set(NodeComputeInputCache, cfg.Node.Compute.InputCache)
+	p.Viper.Set(NodeComputeInputCacheEnabled, cfg.Node.Compute.InputCache.Enabled)
+	p.Viper.Set(NodeComputeInputCachePath, cfg.Node.Compute.InputCache.Path)
+	p.Viper.Set(NodeComputeInputCacheMaxSize, cfg.Node.Compute.InputCache.MaxSize)
+	p.Viper.Set(NodeComputeInputCacheTTL, cfg.Node.Compute.InputCache.TTL.AsTimeDuration())
+	p.Viper.Set(NodeComputeInputCacheSources, cfg.Node.Compute.InputCache.Sources)
 	p.Viper.Set(NodeRequester, cfg.Node.Requester)
 	p.Viper.Set(NodeRequesterJobDefaults, cfg.Node.Requester.JobDefaults)
 	p.Viper.Set(NodeRequesterJobDefaultsExecutionTimeout, cfg.Node.Requester.JobDefaults.ExecutionTimeout.AsTimeDuration())
@@ -333,6 +370,18 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeRequesterJobStore, cfg.Node.Requester.JobStore)
 	p.Viper.Set(NodeRequesterJobStoreType, cfg.Node.Requester.JobStore.Type)
 	p.Viper.Set(NodeRequesterJobStorePath, cfg.Node.Requester.JobStore.Path)
+	p.Viper.Set(NodeRequesterJobRetention, cfg.Node.Requester.JobRetention)
+	p.Viper.Set(NodeRequesterJobRetentionMaxAge, cfg.Node.Requester.JobRetention.MaxAge.AsTimeDuration())
+	p.Viper.Set(NodeRequesterJobRetentionKeepLast, cfg.Node.Requester.JobRetention.KeepLast)
+	p.Viper.Set(NodeRequesterJobRetentionGroupByLabel, cfg.Node.Requester.JobRetention.GroupByLabel)
+	p.Viper.Set(NodeRequesterJobRetentionInterval, cfg.Node.Requester.JobRetention.Interval.AsTimeDuration())
+	p.Viper.Set(NodeRequesterJobRetentionArchive, cfg.Node.Requester.JobRetention.Archive)
+	p.Viper.Set(NodeRequesterJobRetentionArchiveType, cfg.Node.Requester.JobRetention.Archive.Type)
+	p.Viper.Set(NodeRequesterJobRetentionArchivePath, cfg.Node.Requester.JobRetention.Archive.Path)
+	p.Viper.Set(NodeRequesterJobRetentionArchiveBucket, cfg.Node.Requester.JobRetention.Archive.Bucket)
+	p.Viper.Set(NodeRequesterJobRetentionArchivePrefix, cfg.Node.Requester.JobRetention.Archive.Prefix)
+	p.Viper.Set(NodeRequesterJobRetentionArchiveEndpoint, cfg.Node.Requester.JobRetention.Archive.Endpoint)
+	p.Viper.Set(NodeRequesterJobRetentionArchiveRegion, cfg.Node.Requester.JobRetention.Archive.Region)
 	p.Viper.Set(NodeRequesterHousekeepingBackgroundTaskInterval, cfg.Node.Requester.HousekeepingBackgroundTaskInterval.AsTimeDuration())
 	p.Viper.Set(NodeRequesterNodeRankRandomnessRange, cfg.Node.Requester.NodeRankRandomnessRange)
 	p.Viper.Set(NodeRequesterOverAskForBidsFactor, cfg.Node.Requester.OverAskForBidsFactor)
@@ -353,6 +402,8 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeRequesterStorageProviderS3, cfg.Node.Requester.StorageProvider.S3)
 	p.Viper.Set(NodeRequesterStorageProviderS3PreSignedURLDisabled, cfg.Node.Requester.StorageProvider.S3.PreSignedURLDisabled)
 	p.Viper.Set(NodeRequesterStorageProviderS3PreSignedURLExpiration, cfg.Node.Requester.StorageProvider.S3.PreSignedURLExpiration.AsTimeDuration())
+	p.Viper.Set(NodeRequesterPreemption, cfg.Node.Requester.Preemption)
+	p.Viper.Set(NodeRequesterPreemptionEnabled, cfg.Node.Requester.Preemption.Enabled)
 	p.Viper.Set(NodeRequesterTagCache, cfg.Node.Requester.TagCache)
 	p.Viper.Set(NodeRequesterTagCacheSize, cfg.Node.Requester.TagCache.Size)
 	p.Viper.Set(NodeRequesterTagCacheDuration, cfg.Node.Requester.TagCache.Duration.AsTimeDuration())
@@ -369,6 +420,9 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeVolumeSizeRequestTimeout, cfg.Node.VolumeSizeRequestTimeout.AsTimeDuration())
 	p.Viper.Set(NodeNodeInfoStoreTTL, cfg.Node.NodeInfoStoreTTL.AsTimeDuration())
 	p.Viper.Set(NodeExecutorPluginPath, cfg.Node.ExecutorPluginPath)
+	p.Viper.Set(NodePlugins, cfg.Node.Plugins)
+	p.Viper.Set(NodePluginsPublishers, cfg.Node.Plugins.Publishers)
+	p.Viper.Set(NodePluginsStorages, cfg.Node.Plugins.Storages)
 	p.Viper.Set(NodeComputeStoragePath, cfg.Node.ComputeStoragePath)
 	p.Viper.Set(NodeLoggingMode, cfg.Node.LoggingMode)
 	p.Viper.Set(NodeType, cfg.Node.Type)
@@ -28,6 +28,9 @@ type NodeConfig struct {
 	NodeInfoStoreTTL          Duration `yaml:"NodeInfoStoreTTL"`
 
 	ExecutorPluginPath string `yaml:"ExecutorPluginPath"`
+	// Plugins lists the storage and publisher plugins in ExecutorPluginPath to start.
+	// Plugins that are not listed are never started.
+	Plugins PluginConfig `yaml:"Plugins"`
 
 	ComputeStoragePath string `yaml:"ComputeStoragePath"`
 
@@ -147,6 +150,13 @@ type FeatureConfig struct {
 	Storages   []string `yaml:"Storages"`
 }
 
+// PluginConfig lists plugins by name. A plugin named ftp is the binary bacalhau-storage-ftp
+// for storages, and bacalhau-publisher-ftp for publishers.
+type PluginConfig struct {
+	Publishers []string `yaml:"Publishers"`
+	Storages   []string `yaml:"Storages"`
+}
+
 type DockerCacheConfig struct {
 	Size      uint64   `yaml:"Size"`
 	Duration  Duration `yaml:"Duration"`
@@ -11,6 +11,7 @@ type RequesterConfig struct {
 	// How the node decides what jobs to run.
 	JobSelectionPolicy model.JobSelectionPolicy `yaml:"JobSelectionPolicy"`
 	JobStore           JobStoreConfig           `yaml:"JobStore"`
+	JobRetention       JobRetentionConfig       `yaml:"JobRetention"`
 
 	HousekeepingBackgroundTaskInterval Duration                              `yaml:"HousekeepingBackgroundTaskInterval"`
 	NodeRankRandomnessRange            int                                   `yaml:"NodeRankRandomnessRange"`
@@ -22,6 +23,7 @@ type RequesterConfig struct {
 	EvaluationBroker EvaluationBrokerConfig `yaml:"EvaluationBroker"`
 	Worker           WorkerConfig           `yaml:"Worker"`
 	StorageProvider  StorageProviderConfig  `yaml:"StorageProvider"`
+	Preemption       PreemptionConfig       `yaml:"Preemption"`
 
 	TagCache         DockerCacheConfig `yaml:"TagCache"`
 	DefaultPublisher string            `yaml:"DefaultPublisher"`
@@ -48,6 +50,41 @@ type WorkerConfig struct {
 	WorkerEvalDequeueMaxBackoff  Duration `yaml:"WorkerEvalDequeueMaxBackoff"`
 }
 
+type PreemptionConfig struct {
+	// Enabled allows higher priority jobs to preempt running executions of lower priority jobs
+	// when the nodes they are placed on do not have enough available capacity.
+	// Executions of namespaces whose quota is marked as non-preemptible are never preempted.
+	Enabled bool `yaml:"Enabled"`
+}
+
+type JobRetentionConfig struct {
+	// MaxAge is how long terminal jobs are kept after they last changed.
+	// Zero keeps them regardless of their age.
+	MaxAge Duration `yaml:"MaxAge"`
+	// KeepLast is how many of the most recently changed terminal jobs are kept in each namespace.
+	// Zero keeps them regardless of their number.
+	KeepLast int `yaml:"KeepLast"`
+	// GroupByLabel keeps the last KeepLast terminal jobs for each value of this label in a namespace.
+	GroupByLabel string `yaml:"GroupByLabel"`
+	// Interval is how often the retention policy is enforced.
+	Interval Duration `yaml:"Interval"`
+	// Archive is where jobs are archived before they are removed from the job store.
+	Archive JobArchiveConfig `yaml:"Archive"`
+}
+
+type JobArchiveConfig struct {
+	// Type is either "local" to archive jobs to compressed files in Path, "s3" to archive them
+	// to objects in an S3 bucket, or empty to remove jobs without archiving them.
+	Type string `yaml:"Type"`
+	// Path is the directory of the local archive
+	Path string `yaml:"Path"`
+	// Bucket, Prefix, Endpoint and Region locate the S3 archive
+	Bucket   string `yaml:"Bucket"`
+	Prefix   string `yaml:"Prefix"`
+	Endpoint string `yaml:"Endpoint"`
+	Region   string `yaml:"Region"`
+}
+
 type StorageProviderConfig struct {
 	S3 S3StorageProviderConfig `yaml:"S3"`
 }
@@ -10,12 +10,14 @@ import (
 
 type JobStoreConfig struct {
 	Type StorageType `yaml:"Type"`
-	Path string      `yaml:"Path"`
+	// Path is the path of the database file for BoltDB and SQLite,
+	// and the connection string of the database for Postgres.
+	Path string `yaml:"Path"`
 }
 
 func (cfg JobStoreConfig) Validate() error {
 	var err error
-	if cfg.Type <= UnknownStorage || cfg.Type > BoltDB {
+	if cfg.Type <= UnknownStorage || cfg.Type > Postgres {
 		err = errors.Join(err, fmt.Errorf("unknown execution store type: %q", cfg.Type.String()))
 	}
 
@@ -26,12 +28,26 @@ func (cfg JobStoreConfig) Validate() error {
 	return err
 }
 
+// ValidateExecutionStore validates the config of a compute node's execution store,
+// which only supports BoltDB. SQLite and Postgres are only supported by the job store.
+func (cfg JobStoreConfig) ValidateExecutionStore() error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.Type != BoltDB {
+		return fmt.Errorf("unsupported execution store type: %q (compute nodes only support %q)", cfg.Type.String(), BoltDB.String())
+	}
+	return nil
+}
+
 //go:generate stringer -type=StorageType
 type StorageType int64
 
 const (
 	UnknownStorage StorageType = 0
 	BoltDB         StorageType = 1
+	SQLite         StorageType = 2
+	Postgres       StorageType = 3
 )
 
 func (j *StorageType) UnmarshalText(text []byte) error {
@@ -57,13 +73,13 @@ func (j *StorageType) UnmarshalYAML(value *yaml.Node) error {
 }
 
 func ParseStorageType(s string) (ret StorageType, err error) {
-	for typ := UnknownStorage; typ <= BoltDB; typ++ {
+	for typ := UnknownStorage; typ <= Postgres; typ++ {
 		if equal(typ.String(), s) {
 			return typ, nil
 		}
 	}
 
-	return UnknownStorage, fmt.Errorf("StorageType: unknown type '%s' (valid types: %q)", s, []StorageType{BoltDB})
+	return UnknownStorage, fmt.Errorf("StorageType: unknown type '%s' (valid types: %q)", s, []StorageType{BoltDB, SQLite, Postgres})
 }
 
 func equal(a, b string) bool {
@@ -0,0 +1,17 @@
+//go:build unit || !integration
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobStoreConfigValidateExecutionStore(t *testing.T) {
+	assert.NoError(t, JobStoreConfig{Type: BoltDB, Path: "executions.db"}.ValidateExecutionStore())
+	assert.Error(t, JobStoreConfig{Type: BoltDB}.ValidateExecutionStore())
+	for _, typ := range []StorageType{UnknownStorage, SQLite, Postgres} {
+		assert.Error(t, JobStoreConfig{Type: typ, Path: "executions.db"}.ValidateExecutionStore(), typ.String())
+	}
+}
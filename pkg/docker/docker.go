@@ -136,6 +136,16 @@ func (c *Client) removeNetworks(ctx context.Context, filterz filters.Args) error
 		network := network
 		wg.Go(func() error {
 			log.Ctx(ctx).Debug().Str("Network", network.ID).Msg("Network Stop")
+			// disconnect the containers left on the network, such as the compute node when it runs in a container
+			details, err := c.NetworkInspect(ctx, network.ID, types.NetworkInspectOptions{})
+			if err != nil {
+				return err
+			}
+			for containerID := range details.Containers {
+				if err = c.NetworkDisconnect(ctx, network.ID, containerID, true); err != nil {
+					return err
+				}
+			}
 			return c.NetworkRemove(ctx, network.ID)
 		})
 	}
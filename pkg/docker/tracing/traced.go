@@ -100,6 +100,64 @@ func (c TracedClient) ContainerStop(ctx context.Context, containerID string, tim
 	}))
 }
 
+func (c TracedClient) ContainerPause(ctx context.Context, containerID string) error {
+	ctx, span := c.span(ctx, "container.pause")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerPause(ctx, containerID))
+}
+
+func (c TracedClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	ctx, span := c.span(ctx, "container.unpause")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerUnpause(ctx, containerID))
+}
+
+func (c TracedClient) ContainerStatsOneShot(ctx context.Context, containerID string) (types.ContainerStats, error) {
+	ctx, span := c.span(ctx, "container.stats")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.ContainerStats](span)(c.client.ContainerStatsOneShot(ctx, containerID))
+}
+
+func (c TracedClient) ContainerAttach(
+	ctx context.Context, containerID string, options container.AttachOptions) (types.HijackedResponse, error) {
+	ctx, span := c.span(ctx, "container.attach")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.HijackedResponse](span)(c.client.ContainerAttach(ctx, containerID, options))
+}
+
+func (c TracedClient) ContainerExecCreate(ctx context.Context, containerID string, config types.ExecConfig) (types.IDResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.create")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.IDResponse](span)(c.client.ContainerExecCreate(ctx, containerID, config))
+}
+
+func (c TracedClient) ContainerExecAttach(
+	ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.attach")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.HijackedResponse](span)(c.client.ContainerExecAttach(ctx, execID, config))
+}
+
+func (c TracedClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	ctx, span := c.span(ctx, "container.exec.inspect")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.ContainerExecInspect](span)(c.client.ContainerExecInspect(ctx, execID))
+}
+
+func (c TracedClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	ctx, span := c.span(ctx, "container.exec.resize")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerExecResize(ctx, execID, options))
+}
+
 func (c TracedClient) ContainerWait(
 	ctx context.Context,
 	containerID string,
@@ -150,6 +208,13 @@ func (c TracedClient) NetworkConnect(ctx context.Context, networkID, containerID
 	return telemetry.RecordErrorOnSpan(span)(c.client.NetworkConnect(ctx, networkID, containerID, config))
 }
 
+func (c TracedClient) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	ctx, span := c.span(ctx, "network.disconnect")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.NetworkDisconnect(ctx, networkID, containerID, force))
+}
+
 func (c TracedClient) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
 	ctx, span := c.span(ctx, "network.create")
 	defer span.End()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/encryption"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
@@ -89,6 +90,9 @@ func DownloadResults( //nolint:funlen,gocyclo
 		if err != nil {
 			return err
 		}
+		if settings.SingleFile != "" && settings.Encrypted {
+			fetchEnvelope(ctx, downloader, publishedResult, rawParentDir)
+		}
 		downloadedResults[resultPath] = struct{}{}
 	}
 
@@ -121,6 +125,20 @@ func DownloadResults( //nolint:funlen,gocyclo
 				resultPath = newResultPath
 			}
 
+			// only results published encrypted are decrypted, so that results that happen to contain
+			// an envelope file are left as they are
+			if settings.Encrypted {
+				decrypted, err := encryption.DecryptDirectory(resultPath, settings.DecryptionKey)
+				if err != nil {
+					return errors.Wrap(err, "failed to decrypt result")
+				}
+				if !decrypted {
+					return errors.New("failed to decrypt result: the result is expected to be encrypted but has no " +
+						encryption.EnvelopeName)
+				}
+				log.Ctx(ctx).Debug().Str("Source", resultPath).Msg("Decrypted downloaded data")
+			}
+
 			err = moveData(ctx, resultPath, resultsOutputDir, len(downloadedResults) > 1)
 			if err != nil {
 				return err
@@ -131,6 +149,19 @@ func DownloadResults( //nolint:funlen,gocyclo
 	}
 }
 
+// fetchEnvelope fetches the key of an encrypted result along with a single file of it, which is otherwise not
+// downloaded.
+func fetchEnvelope(ctx context.Context, downloader Downloader, result *models.SpecConfig, parentPath string) {
+	_, err := downloader.FetchResult(ctx, DownloadItem{
+		Result:     result,
+		SingleFile: encryption.EnvelopeName,
+		ParentPath: parentPath,
+	})
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("No encryption key fetched with the downloaded file")
+	}
+}
+
 func moveData(
 	ctx context.Context,
 	fromFolder string,
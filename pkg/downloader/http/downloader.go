@@ -60,11 +60,11 @@ func (httpDownloader *Downloader) FetchResult(ctx context.Context, item download
 		return localPath, nil
 	}
 
-	return localPath, httpDownloader.fetch(ctx, sourceSpec.URL, localPath)
+	return localPath, httpDownloader.Fetch(ctx, sourceSpec.URL, localPath)
 }
 
-// fetch makes an HTTP GET request to the given URL and writes the response to the given filepath.
-func (httpDownloader *Downloader) fetch(ctx context.Context, url string, filepath string) error {
+// Fetch makes an HTTP GET request to the given URL and writes the response to the given filepath.
+func (httpDownloader *Downloader) Fetch(ctx context.Context, url string, filepath string) error {
 	out, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, downloader.DownloadFilePerm)
 	if err != nil {
 		return err
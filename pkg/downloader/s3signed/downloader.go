@@ -3,6 +3,11 @@ package s3signed
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/downloader"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader/http"
@@ -30,14 +35,17 @@ func (d *Downloader) IsInstalled(ctx context.Context) (bool, error) {
 }
 
 func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadItem) (string, error) {
-	if item.SingleFile != "" {
-		return "", errors.New("s3signed downloader does not support single file downloads")
-	}
-
 	sourceSpec, err := s3.DecodePreSignedResultSpec(item.Result)
 	if err != nil {
 		return "", err
 	}
+	if len(sourceSpec.PreSignedURLs) > 0 {
+		return d.fetchFiles(ctx, sourceSpec, item)
+	}
+
+	if item.SingleFile != "" {
+		return "", errors.New("s3signed downloader does not support single file downloads of archived results")
+	}
 
 	urlSourceSpec := &models.SpecConfig{
 		Type: models.StorageSourceURL,
@@ -52,3 +60,45 @@ func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadIt
 		ParentPath: item.ParentPath,
 	})
 }
+
+// fetchFiles downloads the files of a result published as individual objects to a directory named after
+// the bucket and prefix of the result, or only the file, or directory, selected by item.SingleFile.
+func (d *Downloader) fetchFiles(ctx context.Context, sourceSpec s3.PreSignedResultSpec, item downloader.DownloadItem) (string, error) {
+	dirName, err := http.SanitizeFileName(fmt.Sprintf("s3://%s/%s", sourceSpec.Bucket, sourceSpec.Key))
+	if err != nil {
+		return "", err
+	}
+	resultPath := filepath.Join(item.ParentPath, dirName)
+
+	paths := make([]string, 0, len(sourceSpec.PreSignedURLs))
+	for path := range sourceSpec.PreSignedURLs {
+		if item.SingleFile == "" || path == item.SingleFile || strings.HasPrefix(path, item.SingleFile+"/") {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("failed to find %s in result s3://%s/%s", item.SingleFile, sourceSpec.Bucket, sourceSpec.Key)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		localPath := filepath.Join(resultPath, filepath.FromSlash(path))
+		if !strings.HasPrefix(localPath, resultPath+string(filepath.Separator)) {
+			return "", fmt.Errorf("invalid path %s in result s3://%s/%s", path, sourceSpec.Bucket, sourceSpec.Key)
+		}
+		alreadyExists, err := downloader.IsAlreadyDownloaded(localPath)
+		if err != nil {
+			return "", err
+		}
+		if alreadyExists {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(localPath), downloader.DownloadFolderPerm); err != nil {
+			return "", err
+		}
+		if err = d.httpDownloader.Fetch(ctx, sourceSpec.PreSignedURLs[path], localPath); err != nil {
+			return "", err
+		}
+	}
+	return resultPath, nil
+}
@@ -5,6 +5,7 @@ package s3signed
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -78,20 +79,39 @@ func (s *DownloaderTestSuite) TestDownloadCompressed() {
 	s3test.AssertEqualDirectories(s.T(), resultPath, decompressedPath)
 }
 
-func (s *DownloaderTestSuite) TestDownloadUnCompressedFail() {
-	storageSpec, _ := s.PrepareAndPublish(false)
+func (s *DownloaderTestSuite) TestDownloadUncompressed() {
+	storageSpec, resultPath := s.PrepareAndPublish(false)
 
-	// attempting to get a signed url for uncompressed data should have no impact and return the same result
+	// results published as individual objects are signed file by file
 	s.Require().NoError(s.signer.Transform(s.Ctx, &storageSpec))
-	s.Require().Equal(models.StorageSourceS3, storageSpec.Type)
+	s.Require().Equal(models.StorageSourceS3PreSigned, storageSpec.Type)
 
-	// download unsigned result should fail
 	downloadParentPath, err := os.MkdirTemp(s.TempDir, "")
 	s.Require().NoError(err)
 
-	_, err = s.downloader.FetchResult(s.Ctx, downloader.DownloadItem{
+	downloadedDir, err := s.downloader.FetchResult(s.Ctx, downloader.DownloadItem{
 		Result:     &storageSpec,
 		ParentPath: downloadParentPath,
 	})
-	s.Require().Error(err)
+	s.Require().NoError(err)
+	s3test.AssertEqualDirectories(s.T(), resultPath, downloadedDir)
+}
+
+func (s *DownloaderTestSuite) TestDownloadUncompressedSingleFile() {
+	storageSpec, resultPath := s.PrepareAndPublish(false)
+	s.Require().NoError(s.signer.Transform(s.Ctx, &storageSpec))
+
+	downloadParentPath, err := os.MkdirTemp(s.TempDir, "")
+	s.Require().NoError(err)
+
+	downloadedDir, err := s.downloader.FetchResult(s.Ctx, downloader.DownloadItem{
+		Result:     &storageSpec,
+		SingleFile: "outputs/nested/3.txt",
+		ParentPath: downloadParentPath,
+	})
+	s.Require().NoError(err)
+	s3test.AssertEqualFiles(s.T(),
+		filepath.Join(resultPath, "outputs", "nested", "3.txt"),
+		filepath.Join(downloadedDir, "outputs", "nested", "3.txt"))
+	s.NoFileExists(filepath.Join(downloadedDir, "outputs", "1.txt"))
 }
@@ -2,6 +2,7 @@ package downloader
 
 import (
 	"context"
+	"crypto/rsa"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
@@ -34,6 +35,10 @@ type DownloaderSettings struct {
 	OutputDir  string
 	SingleFile string
 	Raw        bool
+	// DecryptionKey decrypts results that were encrypted for it when they were published
+	DecryptionKey *rsa.PrivateKey
+	// Encrypted is set when results were encrypted by the publisher, and are decrypted with DecryptionKey
+	Encrypted bool
 }
 
 type DownloadItem struct {
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/system"
+)
+
+// cgroupRoot is where the cgroup filesystem of the host is mounted
+var cgroupRoot = "/sys/fs/cgroup"
+
+// executionCgroup is the parent cgroup of the container of an execution.
+// Docker deletes the cgroup of a container once it exits, so the container is placed
+// in a parent cgroup of its own, whose stats can still be read after the container exited.
+// It is opt-in with config.ShouldUseDockerExecutionCgroups, as it overrides the cgroup parent
+// of the docker daemon, and its stats can only be read where the host's cgroups are mounted.
+// When they can't be read, the peak memory sampled from the container stats is kept.
+type executionCgroup struct {
+	// parent is the cgroup parent passed to docker when creating the container
+	parent string
+	// path is the path of the cgroup relative to the root of the hierarchy
+	path string
+	// v1 is true if the host uses cgroup v1
+	v1 bool
+}
+
+// newExecutionCgroup returns the parent cgroup of the container of the execution, or nil if
+// the cgroup driver of the docker daemon is unknown.
+func newExecutionCgroup(info system.Info, executionID string) *executionCgroup {
+	// a dash in a systemd slice name denotes nesting
+	id := strings.ReplaceAll(executionID, "-", "_")
+	cgroup := &executionCgroup{v1: info.CgroupVersion == "1"}
+	switch info.CgroupDriver {
+	case "systemd":
+		cgroup.parent = fmt.Sprintf("bacalhau-%s.slice", id)
+		cgroup.path = filepath.Join("bacalhau.slice", cgroup.parent)
+	case "cgroupfs":
+		cgroup.parent = filepath.Join("/bacalhau", id)
+		cgroup.path = cgroup.parent
+	default:
+		return nil
+	}
+	return cgroup
+}
+
+// peakMemory returns the highest memory usage of the cgroup in bytes
+func (c *executionCgroup) peakMemory() (uint64, error) {
+	file := filepath.Join(cgroupRoot, c.path, "memory.peak")
+	if c.v1 {
+		file = filepath.Join(cgroupRoot, "memory", c.path, "memory.max_usage_in_bytes")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// remove deletes the cgroup once the container in it has been removed
+func (c *executionCgroup) remove() error {
+	dirs := []string{filepath.Join(cgroupRoot, c.path)}
+	if c.v1 {
+		// there is one hierarchy per controller
+		var err error
+		if dirs, err = filepath.Glob(filepath.Join(cgroupRoot, "*", c.path)); err != nil {
+			return err
+		}
+	}
+	var errs error
+	for _, dir := range dirs {
+		// cgroups are removed with rmdir, as their files cannot be deleted
+		if err := os.Remove(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
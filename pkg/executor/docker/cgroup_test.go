@@ -0,0 +1,88 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestNewExecutionCgroup(t *testing.T) {
+	cgroup := newExecutionCgroup(system.Info{CgroupDriver: "systemd", CgroupVersion: "2"}, "e-123")
+	require.Equal(t, &executionCgroup{
+		parent: "bacalhau-e_123.slice",
+		path:   "bacalhau.slice/bacalhau-e_123.slice",
+	}, cgroup)
+
+	cgroup = newExecutionCgroup(system.Info{CgroupDriver: "cgroupfs", CgroupVersion: "1"}, "e-123")
+	require.Equal(t, &executionCgroup{parent: "/bacalhau/e_123", path: "/bacalhau/e_123", v1: true}, cgroup)
+
+	require.Nil(t, newExecutionCgroup(system.Info{CgroupDriver: "none"}, "e-123"))
+}
+
+func TestExecutionCgroup_PeakMemory(t *testing.T) {
+	setCgroupRoot(t)
+	writeCgroupFile(t, "bacalhau.slice/bacalhau-e_1.slice/memory.peak", "1024\n")
+	writeCgroupFile(t, "memory/bacalhau/e_1/memory.max_usage_in_bytes", "2048\n")
+
+	peak, err := (&executionCgroup{path: "bacalhau.slice/bacalhau-e_1.slice"}).peakMemory()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1024), peak)
+
+	peak, err = (&executionCgroup{path: "/bacalhau/e_1", v1: true}).peakMemory()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2048), peak)
+
+	_, err = (&executionCgroup{path: "/bacalhau/e_2"}).peakMemory()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestExecutionCgroup_Remove(t *testing.T) {
+	root := setCgroupRoot(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "memory/bacalhau/e_1"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "cpu/bacalhau/e_1"), 0755))
+
+	cgroup := &executionCgroup{path: "/bacalhau/e_1", v1: true}
+	require.NoError(t, cgroup.remove())
+	require.NoDirExists(t, filepath.Join(root, "memory/bacalhau/e_1"))
+	require.NoDirExists(t, filepath.Join(root, "cpu/bacalhau/e_1"))
+
+	// removing it again is a no-op
+	require.NoError(t, cgroup.remove())
+}
+
+func TestReadPeakMemory_KeepsHighest(t *testing.T) {
+	setCgroupRoot(t)
+	writeCgroupFile(t, "bacalhau/e_1/memory.peak", "1024")
+	h := &executionHandler{logger: zerolog.Nop(), cgroup: &executionCgroup{path: "/bacalhau/e_1"}}
+
+	// the container exited before it was sampled
+	usage := &models.ResourceUsage{}
+	h.readPeakMemory(usage)
+	require.Equal(t, uint64(1024), usage.PeakMemory)
+
+	usage = &models.ResourceUsage{PeakMemory: 4096}
+	h.readPeakMemory(usage)
+	require.Equal(t, uint64(4096), usage.PeakMemory)
+}
+
+func setCgroupRoot(t *testing.T) string {
+	root := t.TempDir()
+	previous := cgroupRoot
+	cgroupRoot = root
+	t.Cleanup(func() { cgroupRoot = previous })
+	return root
+}
+
+func writeCgroupFile(t *testing.T, name, content string) {
+	path := filepath.Join(cgroupRoot, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+)
+
+// Exec runs a command in the container of an ongoing execution using the Docker exec API,
+// or attaches to the output of its main process if request.Attach is set.
+func (e *Executor) Exec(ctx context.Context, request executor.ExecRequest, streams executor.ExecStreams) (int, error) {
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return 0, fmt.Errorf("exec into execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
+	}
+	if !handler.active() {
+		return 0, fmt.Errorf("exec into execution (%s): %w", request.ExecutionID, executor.ErrAlreadyComplete)
+	}
+	if request.Attach {
+		return handler.attach(ctx, streams)
+	}
+	return handler.exec(ctx, request, streams)
+}
+
+// exec runs a command in the container, and returns its exit code once it exits.
+func (h *executionHandler) exec(ctx context.Context, request executor.ExecRequest, streams executor.ExecStreams) (int, error) {
+	if len(request.Command) == 0 {
+		return 0, fmt.Errorf("no command to exec in execution (%s)", h.executionID)
+	}
+	created, err := h.client.ContainerExecCreate(ctx, h.containerID, types.ExecConfig{
+		Cmd:          request.Command,
+		Tty:          request.TTY,
+		AttachStdin:  request.Stdin,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create exec in container (%s): %w", h.containerID, err)
+	}
+	h.logger.Info().Str("exec", created.ID).Strs("command", request.Command).Msg("running command in container")
+
+	resp, err := h.client.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{Tty: request.TTY})
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach to exec (%s): %w", created.ID, err)
+	}
+	defer resp.Close()
+
+	if request.TTY && streams.Resize != nil {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case size, ok := <-streams.Resize:
+					if !ok {
+						return
+					}
+					err := h.client.ContainerExecResize(ctx, created.ID, container.ResizeOptions{
+						Height: size.Height,
+						Width:  size.Width,
+					})
+					if err != nil {
+						h.logger.Debug().Err(err).Msg("failed to resize exec terminal")
+					}
+				}
+			}
+		}()
+	}
+	if request.Stdin && streams.Stdin != nil {
+		go func() {
+			if _, err := io.Copy(resp.Conn, streams.Stdin); err != nil {
+				h.logger.Debug().Err(err).Msg("failed to copy stdin to exec")
+			}
+			_ = resp.CloseWrite()
+		}()
+	}
+
+	if err = copyOutput(ctx, resp.Reader, streams, request.TTY); err != nil {
+		return 0, err
+	}
+	inspect, err := h.client.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect exec (%s): %w", created.ID, err)
+	}
+	return inspect.ExitCode, nil
+}
+
+// attach copies the output of the main process of the container until it exits, and returns its exit code.
+// Containers of executions are created without an open stdin, so the input of the main process is not attached.
+func (h *executionHandler) attach(ctx context.Context, streams executor.ExecStreams) (int, error) {
+	resp, err := h.client.ContainerAttach(ctx, h.containerID, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach to container (%s): %w", h.containerID, err)
+	}
+	defer resp.Close()
+
+	if err = copyOutput(ctx, resp.Reader, streams, false); err != nil {
+		return 0, err
+	}
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-h.waitCh:
+		if h.result == nil {
+			return 0, nil
+		}
+		return h.result.ExitCode, nil
+	}
+}
+
+// copyOutput copies the output of an exec or attach session until it ends or ctx is done.
+// Output of sessions without a TTY multiplexes stdout and stderr.
+func copyOutput(ctx context.Context, reader io.Reader, streams executor.ExecStreams, tty bool) error {
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		if tty {
+			_, err = io.Copy(streams.Stdout, reader)
+		} else {
+			_, err = stdcopy.StdCopy(streams.Stdout, streams.Stderr, reader)
+		}
+		done <- err
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// compile-time check that the executor can exec into its executions
+var _ executor.Execer = (*Executor)(nil)
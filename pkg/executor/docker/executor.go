@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"go.uber.org/atomic"
 
 	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/egress"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	pkgUtil "github.com/bacalhau-project/bacalhau/pkg/util"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/executor/docker/bidstrategy/semantic"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+	"github.com/bacalhau-project/bacalhau/pkg/util/filecopy"
 	"github.com/bacalhau-project/bacalhau/pkg/util/generic"
 )
 
@@ -49,6 +52,9 @@ type Executor struct {
 	// handlers is a map of executionID to its handler.
 	handlers generic.SyncMap[string, *executionHandler]
 
+	// proxies is a map of executionID to the egress proxy of its container, until its handler takes it over.
+	proxies generic.SyncMap[string, *egress.Proxy]
+
 	activeFlags map[string]chan struct{}
 	complete    map[string]chan struct{}
 	client      *docker.Client
@@ -121,6 +127,7 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	// bacalhau execution label _before_ we do anything else.  If we are able to find one then we
 	// will use that container in the executionHandler that we create.
 	containerID, err := e.FindRunningContainer(ctx, request.ExecutionID)
+	cgroup := e.executionCgroup(ctx, request.ExecutionID)
 
 	if err != nil {
 		// Unable to find a running container for this execution, we will instead check for a handler, and
@@ -137,11 +144,14 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 			ExecutionID:   request.ExecutionID,
 			JobID:         request.JobID,
 			EngineSpec:    request.EngineParams,
+			Env:           request.Env,
 			NetworkConfig: request.Network,
 			Resources:     request.Resources,
 			Inputs:        request.Inputs,
 			Outputs:       request.Outputs,
 			ResultsDir:    request.ResultsDir,
+			Checkpoint:    request.Checkpoint,
+			Cgroup:        cgroup,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create docker job container: %w", err)
@@ -161,8 +171,12 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		executionID: request.ExecutionID,
 		containerID: containerID,
 		resultsDir:  request.ResultsDir,
+		outputs:     request.Outputs,
+		checkpoint:  request.Checkpoint,
 		limits:      request.OutputLimits,
 		keepStack:   config.ShouldKeepStack(),
+		proxy:       e.takeProxy(request.ExecutionID),
+		cgroup:      cgroup,
 		waitCh:      make(chan bool),
 		activeCh:    make(chan bool),
 		running:     atomic.NewBool(false),
@@ -234,6 +248,17 @@ func (e *Executor) Cancel(ctx context.Context, executionID string) error {
 	return handler.kill(ctx)
 }
 
+// Checkpoint copies the result paths and checkpoint directory of an execution into dir,
+// pausing its container while they are copied if it is still running.
+// It returns an error if the execution is not found or is not checkpointed.
+func (e *Executor) Checkpoint(ctx context.Context, executionID string, dir string) error {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return fmt.Errorf("checkpointing execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	return handler.snapshot(ctx, dir)
+}
+
 // GetLogStream provides a stream of output logs for a specific execution.
 // Parameters 'withHistory' and 'follow' control whether to include past logs
 // and whether to keep the stream open for new logs, respectively.
@@ -306,11 +331,40 @@ type dockerJobContainerParams struct {
 	ExecutionID   string
 	JobID         string
 	EngineSpec    *models.SpecConfig
+	Env           map[string]string
 	NetworkConfig *models.NetworkConfig
 	Resources     *models.Resources
 	Inputs        []storage.PreparedStorage
 	Outputs       []*models.ResultPath
 	ResultsDir    string
+	Checkpoint    *executor.CheckpointParams
+	// Cgroup is the parent cgroup of the container, if any
+	Cgroup *executionCgroup
+}
+
+// executionCgroup returns the parent cgroup of the container of the execution, or nil if execution
+// cgroups are not enabled or the cgroup driver of the docker daemon is unknown.
+func (e *Executor) executionCgroup(ctx context.Context, executionID string) *executionCgroup {
+	if !config.ShouldUseDockerExecutionCgroups() {
+		return nil
+	}
+	info, err := e.client.Info(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to read docker info, the peak memory of the container will be sampled")
+		return nil
+	}
+	return newExecutionCgroup(info, executionID)
+}
+
+// containerEnv returns the environment variables of the container, where the task's
+// environment variables are overridden by the ones in the docker engine spec.
+func containerEnv(taskEnv map[string]string, engineEnv []string) []string {
+	env := make([]string, 0, len(taskEnv)+len(engineEnv))
+	for _, key := range lo.Keys(taskEnv) {
+		env = append(env, fmt.Sprintf("%s=%s", key, taskEnv[key]))
+	}
+	slices.Sort(env)
+	return append(env, engineEnv...)
 }
 
 // newDockerJobContainer is an internal method called by Start to set up a new Docker container
@@ -327,7 +381,7 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 	containerConfig := &container.Config{
 		Image:      dockerArgs.Image,
 		Tty:        false,
-		Env:        dockerArgs.EnvironmentVariables,
+		Env:        containerEnv(params.Env, dockerArgs.EnvironmentVariables),
 		Entrypoint: dockerArgs.Entrypoint,
 		Cmd:        dockerArgs.Parameters,
 		Labels:     e.containerLabels(params.ExecutionID, params.JobID),
@@ -338,14 +392,25 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 	if err != nil {
 		return container.CreateResponse{}, fmt.Errorf("creating container mounts: %w", err)
 	}
+	if params.Checkpoint != nil {
+		checkpointMount, err := prepareCheckpointMount(params.Checkpoint, params.Outputs, params.ResultsDir)
+		if err != nil {
+			return container.CreateResponse{}, fmt.Errorf("preparing checkpoint: %w", err)
+		}
+		mounts = append(mounts, checkpointMount)
+	}
 
-	// Create GPU request if the job requests it
-	// TODO we need to use the resource units requested by for the GPU.
+	// Create GPU request if the job requests it, pinned to the devices allocated to the execution
 	deviceRequests, deviceMappings, err := configureDevices(ctx, params.Resources)
 	if err != nil {
 		return container.CreateResponse{}, fmt.Errorf("creating container devices: %w", err)
 	}
-	log.Ctx(ctx).Trace().Msgf("Adding %d GPUs to request", params.Resources.GPU)
+	if len(params.Resources.GPUs) > 0 {
+		log.Ctx(ctx).Debug().Msgf("Adding GPUs %s to request",
+			strings.Join(lo.Map(params.Resources.GPUs, func(gpu models.GPU, _ int) string {
+				return fmt.Sprintf("%d (%s)", gpu.Index, gpu.Name)
+			}), ", "))
+	}
 
 	hostConfig := &container.HostConfig{
 		Mounts: mounts,
@@ -356,6 +421,9 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 			Devices:        deviceMappings,
 		},
 	}
+	if params.Cgroup != nil {
+		hostConfig.CgroupParent = params.Cgroup.parent
+	}
 
 	if _, set := os.LookupEnv("SKIP_IMAGE_PULL"); !set {
 		dockerCreds := config.GetDockerCredentials()
@@ -380,11 +448,22 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 		e.containerName(params.ExecutionID, params.JobID),
 	)
 	if err != nil {
+		if proxy := e.takeProxy(params.ExecutionID); proxy != nil {
+			_ = proxy.Close()
+		}
 		return container.CreateResponse{}, fmt.Errorf("creating container: %w", err)
 	}
 	return jobContainer, nil
 }
 
+// takeProxy removes the egress proxy of the execution from the executor and returns it, if any.
+func (e *Executor) takeProxy(executionID string) *egress.Proxy {
+	if proxy, found := e.proxies.LoadAndDelete(executionID); found {
+		return proxy.(*egress.Proxy)
+	}
+	return nil
+}
+
 func configureDevices(ctx context.Context, resources *models.Resources) ([]container.DeviceRequest, []container.DeviceMapping, error) {
 	requests := []container.DeviceRequest{}
 	mappings := []container.DeviceMapping{}
@@ -488,6 +567,38 @@ func makeContainerMounts(
 	return mounts, nil
 }
 
+// prepareCheckpointMount restores the checkpoint the execution resumes from, if any, into its
+// result paths and checkpoint directory, and returns the mount of its checkpoint directory.
+func prepareCheckpointMount(
+	checkpoint *executor.CheckpointParams, outputs []*models.ResultPath, resultsDir string) (mount.Mount, error) {
+	if err := os.MkdirAll(checkpoint.StateDir, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W); err != nil {
+		return mount.Mount{}, fmt.Errorf("failed to create checkpoint dir for execution: %w", err)
+	}
+	if checkpoint.RestoreDir != "" {
+		for _, output := range outputs {
+			restoredOutput := filepath.Join(checkpoint.RestoreDir, executor.CheckpointOutputsDir, output.Name)
+			if _, err := os.Stat(restoredOutput); os.IsNotExist(err) {
+				continue
+			}
+			if err := filecopy.CopyDir(restoredOutput, filepath.Join(resultsDir, output.Name)); err != nil {
+				return mount.Mount{}, fmt.Errorf("failed to restore output %s: %w", output.Name, err)
+			}
+		}
+		restoredState := filepath.Join(checkpoint.RestoreDir, executor.CheckpointStateDir)
+		if _, err := os.Stat(restoredState); err == nil {
+			if err = filecopy.CopyDir(restoredState, checkpoint.StateDir); err != nil {
+				return mount.Mount{}, fmt.Errorf("failed to restore %s: %w", checkpoint.Path, err)
+			}
+		}
+	}
+	return mount.Mount{
+		Type:     mount.TypeBind,
+		ReadOnly: false,
+		Source:   checkpoint.StateDir,
+		Target:   checkpoint.Path,
+	}, nil
+}
+
 func (e *Executor) dockerObjectName(executionID string, jobID string, parts ...string) string {
 	strs := []string{"bacalhau", e.ID, jobID, executionID}
 	strs = append(strs, parts...)
@@ -516,6 +627,7 @@ func labelExecutionValue(executorID string, executionID string) string {
 
 // Compile-time interface check:
 var _ executor.Executor = (*Executor)(nil)
+var _ executor.Checkpointer = (*Executor)(nil)
 
 // FindRunningContainer, not part of the Executor interface, is a utility function that
 // helps locate a container durin a restart check.
@@ -47,6 +47,21 @@ func TestExecutorTestSuite(t *testing.T) {
 	suite.Run(t, new(ExecutorTestSuite))
 }
 
+func TestConfigureDevicesPinsAllocatedGPUs(t *testing.T) {
+	requests, mappings, err := configureDevices(context.Background(), &models.Resources{
+		GPU: 2,
+		GPUs: []models.GPU{
+			{Index: 1, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+			{Index: 3, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, mappings)
+	require.Len(t, requests, 1)
+	require.Equal(t, []string{"1", "3"}, requests[0].DeviceIDs)
+	require.Zero(t, requests[0].Count, "devices must be pinned by ID rather than by count")
+}
+
 func (s *ExecutorTestSuite) SetupTest() {
 	docker.MustHaveDocker(s.T())
 
@@ -108,6 +123,21 @@ func (s *ExecutorTestSuite) containerHttpURL() *url.URL {
 	return url
 }
 
+// proxiedHttpURL returns the URL of the test server for containers with HTTP networking. Their requests are sent by
+// the egress proxy of the compute node, which reaches the server at its address rather than through the hostname
+// of the Docker host.
+func (s *ExecutorTestSuite) proxiedHttpURL() *url.URL {
+	url, err := url.Parse("http://" + s.server.Addr)
+	require.NoError(s.T(), err)
+	return url
+}
+
+func (s *ExecutorTestSuite) proxiedCurlTask() *models.SpecConfig {
+	return dockermodels.NewDockerEngineBuilder(CurlDockerImage).
+		WithEntrypoint("curl", "--fail-with-body", s.proxiedHttpURL().JoinPath("hello.txt").String()).
+		Build()
+}
+
 func (s *ExecutorTestSuite) curlTask() *models.SpecConfig {
 	return dockermodels.NewDockerEngineBuilder(CurlDockerImage).
 		WithEntrypoint("curl", "--fail-with-body", s.containerHttpURL().JoinPath("hello.txt").String()).
@@ -314,9 +344,9 @@ func (s *ExecutorTestSuite) TestDockerNetworkingHTTP() {
 	task := mock.TaskBuilder().
 		Network(models.NewNetworkConfigBuilder().
 			Type(models.NetworkHTTP).
-			Domains(s.containerHttpURL().Hostname()).
+			Domains(s.proxiedHttpURL().Hostname()).
 			BuildOrDie()).
-		Engine(s.curlTask()).
+		Engine(s.proxiedCurlTask()).
 		BuildOrDie()
 
 	result, err := s.runJob(task, uuid.New().String())
@@ -329,9 +359,9 @@ func (s *ExecutorTestSuite) TestDockerNetworkingHTTPWithMultipleDomains() {
 	task := mock.TaskBuilder().
 		Network(models.NewNetworkConfigBuilder().
 			Type(models.NetworkHTTP).
-			Domains(s.containerHttpURL().Hostname(), "bacalhau.org").
+			Domains(s.proxiedHttpURL().Hostname(), "bacalhau.org").
 			BuildOrDie()).
-		Engine(s.curlTask()).
+		Engine(s.proxiedCurlTask()).
 		BuildOrDie()
 
 	result, err := s.runJob(task, uuid.New().String())
@@ -365,21 +395,23 @@ func (s *ExecutorTestSuite) TestDockerNetworkingFiltersHTTP() {
 			Type(models.NetworkHTTP).
 			Domains("bacalhau.org").
 			BuildOrDie()).
-		Engine(s.curlTask()).
+		Engine(s.proxiedCurlTask()).
 		BuildOrDie()
 
 	result, err := s.runJob(task, uuid.New().String())
 	// The curl will succeed but should return a non-zero exit code and error page.
 	require.NoError(s.T(), err)
 	require.NotZero(s.T(), result.ExitCode)
-	require.Contains(s.T(), result.STDOUT, "ERROR: The requested URL could not be retrieved")
+	host := s.proxiedHttpURL().Hostname()
+	require.Contains(s.T(), result.STDOUT, "request to "+host+" denied by the network config of the job")
+	require.Equal(s.T(), &models.EgressLog{Denied: 1, DeniedHosts: []string{host}}, result.Egress)
 }
 
 func (s *ExecutorTestSuite) TestDockerNetworkingFiltersHTTPS() {
 	task := mock.TaskBuilder().
 		Network(models.NewNetworkConfigBuilder().
 			Type(models.NetworkHTTP).
-			Domains(s.containerHttpURL().Hostname()).
+			Domains(s.proxiedHttpURL().Hostname()).
 			BuildOrDie()).
 		Engine(dockermodels.NewDockerEngineBuilder(CurlDockerImage).
 			WithEntrypoint("curl", "--fail-with-body", "https://www.bacalhau.org").
@@ -400,8 +432,8 @@ func (s *ExecutorTestSuite) TestDockerNetworkingAppendsHTTPHeader() {
 		s.Require().NoError(err)
 	})
 	task := mock.TaskBuilder().
-		Network(models.NewNetworkConfigBuilder().Type(models.NetworkHTTP).Domains(s.containerHttpURL().Hostname()).BuildOrDie()).
-		Engine(s.curlTask()).
+		Network(models.NewNetworkConfigBuilder().Type(models.NetworkHTTP).Domains(s.proxiedHttpURL().Hostname()).BuildOrDie()).
+		Engine(s.proxiedCurlTask()).
 		BuildOrDie()
 
 	executionID := uuid.New().String()
@@ -539,4 +571,14 @@ func (s *ExecutorTestSuite) TestDockerOOM() {
 	result, err := s.runJob(task, uuid.New().String())
 	require.NoError(s.T(), err)
 	require.Contains(s.T(), result.ErrorMsg, "memory limit exceeded")
+	require.Equal(s.T(), models.FailureReasonOOMKilled, result.FailureReason)
+}
+
+func TestContainerEnv(t *testing.T) {
+	// containers of tasks without environment variables get the engine's environment variables unchanged
+	require.Equal(t, []string{"A=engine"}, containerEnv(nil, []string{"A=engine"}))
+
+	// the engine's environment variables come last, so they override the task's
+	require.Equal(t, []string{"A=task", "B=task", "A=engine"},
+		containerEnv(map[string]string{"B": "task", "A": "task"}, []string{"A=engine"}))
 }
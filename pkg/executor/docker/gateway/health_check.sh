@@ -1,14 +0,0 @@
-#!/usr/bin/env bash
-
-# Exit on error. Append || true if you expect an error.
-set -o errexit
-# Exit on error inside any functions or subshells.
-set -o errtrace
-# Do not allow use of undefined vars. Use ${VAR:-} to use an undefined VAR
-set -o nounset
-# Catch the error in case mysqldump fails (but gzip succeeds) in `mysqldump |gzip`
-set -o pipefail
-# Turn on traces, useful while debugging but commented out by default
-#set -o xtrace
-
-[ "$(curl --max-time 1 --output /dev/null --silent --header 'Docker-Health-Check: true' localhost:8080 --write-out '%{http_code}')" == "400" ]
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -17,7 +18,9 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/docker"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/egress"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/filecopy"
 )
 
 type executionHandler struct {
@@ -33,8 +36,14 @@ type executionHandler struct {
 	executionID string
 	containerID string
 	resultsDir  string
+	outputs     []*models.ResultPath
+	checkpoint  *executor.CheckpointParams
 	limits      executor.OutputLimits
 	keepStack   bool
+	// proxy filters the outbound requests of the container, if it has HTTP networking
+	proxy *egress.Proxy
+	// cgroup is the parent cgroup of the container, if any
+	cgroup *executionCgroup
 
 	//
 	// synchronization
@@ -59,6 +68,7 @@ func (h *executionHandler) run(ctx context.Context) {
 		if err := h.destroy(destroyTimeout); err != nil {
 			log.Warn().Err(err).Msg("failed to cleanup container")
 		}
+		h.closeProxy()
 		h.running.Store(false)
 		close(h.waitCh)
 		ActiveExecutions.Dec(ctx, attribute.String("executor_id", h.ID))
@@ -81,6 +91,18 @@ func (h *executionHandler) run(ctx context.Context) {
 	// The container is now active
 	close(h.activeCh)
 
+	// sample the resources used by the container until it exits, and record them on the result
+	usageCtx, stopUsage := context.WithCancel(ctx)
+	usageCh := h.sampleUsage(usageCtx)
+	defer func() {
+		stopUsage()
+		usage := <-usageCh
+		h.readPeakMemory(usage)
+		if h.result != nil && !usage.IsZero() {
+			h.result.ResourceUsage = usage
+		}
+	}()
+
 	// the idea here is even if the container errors
 	// we want to capture stdout, stderr and feed it back to the user
 	var containerError error
@@ -92,6 +114,9 @@ func (h *executionHandler) run(ctx context.Context) {
 		reason := fmt.Errorf("context canceled while waiting on container status: %w", ctx.Err())
 		h.logger.Err(reason).Msg("cancel waiting on container status")
 		h.result = executor.NewFailedResult(reason.Error())
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			h.result.FailureReason = models.FailureReasonTimeout
+		}
 		// the context was canceled, bail.
 		return
 	case err := <-errCh:
@@ -116,8 +141,9 @@ func (h *executionHandler) run(ctx context.Context) {
 		if containerJSON.ContainerJSONBase.State.OOMKilled {
 			containerError = errors.New(`memory limit exceeded. Please refer to https://docs.bacalhau.org/getting-started/resources/#docker-executor for more information`) //nolint:lll
 			h.result = &models.RunCommandResult{
-				ExitCode: int(containerExitStatusCode),
-				ErrorMsg: containerError.Error(),
+				ExitCode:      int(containerExitStatusCode),
+				ErrorMsg:      containerError.Error(),
+				FailureReason: models.FailureReasonOOMKilled,
 			}
 			return
 		}
@@ -160,12 +186,28 @@ func (h *executionHandler) run(ctx context.Context) {
 	// along with a truncated version of the logs.
 	// persist stderr/out to the results directory, and store the metadata in the handler.
 	h.result = executor.WriteJobResults(h.resultsDir, stdoutPipe, stderrPipe, int(containerExitStatusCode), containerError, h.limits)
+	if containerExitStatusCode != 0 && isDiskQuotaExceeded(h.result.STDERR) {
+		h.result.FailureReason = models.FailureReasonDiskQuotaExceeded
+	}
 
 	h.logger.Info().
 		Int64("status", containerExitStatusCode).
 		Msg("container execution ended")
 }
 
+// closeProxy stops the egress proxy of the container and records its egress log on the result.
+func (h *executionHandler) closeProxy() {
+	if h.proxy == nil {
+		return
+	}
+	if err := h.proxy.Close(); err != nil {
+		h.logger.Warn().Err(err).Msg("failed to close egress proxy")
+	}
+	if h.result != nil {
+		h.result.Egress = h.proxy.Log()
+	}
+}
+
 func (h *executionHandler) kill(ctx context.Context) error {
 	// TODO pass a signal, which we can do by modifying this client wrapper to accept one.
 	// the wrapped docker client supports such params.
@@ -190,11 +232,46 @@ func (h *executionHandler) destroy(timeout time.Duration) error {
 		if err := h.client.RemoveContainer(ctx, h.containerID); err != nil {
 			return err
 		}
+		if h.cgroup != nil {
+			if err := h.cgroup.remove(); err != nil {
+				h.logger.Debug().Err(err).Msg("failed to remove the cgroup of the container")
+			}
+		}
 		return h.client.RemoveObjectsWithLabel(ctx, labelExecutionID, labelExecutionValue(h.ID, h.executionID))
 	}
 	return nil
 }
 
+// snapshot copies the result paths and checkpoint directory of the execution into dir.
+// The container is paused while they are copied, so that the snapshot is consistent.
+func (h *executionHandler) snapshot(ctx context.Context, dir string) error {
+	if h.checkpoint == nil {
+		return fmt.Errorf("execution (%s) is not checkpointed", h.executionID)
+	}
+	if h.active() {
+		if err := h.client.ContainerPause(ctx, h.containerID); err != nil {
+			return fmt.Errorf("failed to pause container (%s): %w", h.containerID, err)
+		}
+		defer func() {
+			if err := h.client.ContainerUnpause(ctx, h.containerID); err != nil {
+				h.logger.Warn().Err(err).Msg("failed to unpause container after checkpoint")
+			}
+		}()
+	}
+
+	for _, output := range h.outputs {
+		err := filecopy.CopyDir(
+			filepath.Join(h.resultsDir, output.Name), filepath.Join(dir, executor.CheckpointOutputsDir, output.Name))
+		if err != nil {
+			return fmt.Errorf("failed to checkpoint output %s: %w", output.Name, err)
+		}
+	}
+	if err := filecopy.CopyDir(h.checkpoint.StateDir, filepath.Join(dir, executor.CheckpointStateDir)); err != nil {
+		return fmt.Errorf("failed to checkpoint %s: %w", h.checkpoint.Path, err)
+	}
+	return nil
+}
+
 func (h *executionHandler) outputStream(ctx context.Context, request executor.LogStreamRequest) (io.ReadCloser, error) {
 	since := "1"
 	if request.Tail {
@@ -216,3 +293,21 @@ func (h *executionHandler) outputStream(ctx context.Context, request executor.Lo
 func (h *executionHandler) active() bool {
 	return h.running.Load()
 }
+
+// diskFullMessages are the messages of the errors returned by the kernel when a filesystem or
+// a disk quota is full, as printed by most programs and runtimes.
+var diskFullMessages = []string{
+	"no space left on device",
+	"disk quota exceeded",
+}
+
+// isDiskQuotaExceeded returns true if stderr reports the container ran out of disk space.
+func isDiskQuotaExceeded(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, msg := range diskFullMessages {
+		if strings.Contains(stderr, msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDiskQuotaExceeded(t *testing.T) {
+	require.True(t, isDiskQuotaExceeded("cp: error writing '/outputs/big': No space left on device"))
+	require.True(t, isDiskQuotaExceeded("write /data/file: disk quota exceeded"))
+	require.False(t, isDiskQuotaExceeded("Segmentation fault"))
+}
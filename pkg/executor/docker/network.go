@@ -2,35 +2,27 @@ package docker
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
-	"time"
+	"os"
+	"slices"
+	"syscall"
 
-	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	pkgerrors "github.com/pkg/errors"
-	"github.com/rs/zerolog/log"
 
-	"github.com/bacalhau-project/bacalhau/pkg/config"
-	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/egress"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
 const (
-	dockerNetworkNone   = container.NetworkMode("none")
-	dockerNetworkHost   = container.NetworkMode("host")
-	dockerNetworkBridge = container.NetworkMode("bridge")
+	dockerNetworkNone = container.NetworkMode("none")
+	dockerNetworkHost = container.NetworkMode("host")
 )
 
 const (
-	// The Docker image used to provide HTTP filtering and throttling. See
-	// pkg/executor/docker/gateway/Dockerfile for design notes. We specify this
-	// using a fully-versioned tag so that the interface between code and image
-	// stay in sync.
-	httpGatewayImage = "ghcr.io/bacalhau-project/http-gateway:v0.3.17"
-
 	// The hostname used by Mac OS X and Windows hosts to refer to the Docker
 	// host in a network context. Linux hosts can use this hostname if they
 	// are set up using the `dockerHostAddCommand` as an extra host.
@@ -44,20 +36,6 @@ const (
 	// command that will ensure the host is visible on the network from within
 	// the container, even on a Linux host where localhost is sufficient.
 	dockerHostAddCommand = dockerHostHostname + ":" + dockerHostIPAddressMagicWord
-
-	// This time should match the --interval= option specified on the container
-	// HEALTHCHECK (as the health status only updates this frequently so more
-	// frequent calls are useless)
-	httpGatewayHealthcheckInterval = time.Second
-
-	// The port used by the proxy server within the HTTP gateway container. This
-	// is also specified in squid.conf and gateway.sh.
-	httpProxyPort = 8080
-)
-
-var (
-	// The capabilities that the gateway container needs. See the Dockerfile.
-	gatewayCapabilities = []string{"NET_ADMIN"}
 )
 
 //nolint:nakedret
@@ -78,15 +56,16 @@ func (e *Executor) setupNetworkForJob(
 		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, dockerHostAddCommand)
 	case models.NetworkHTTP:
 		var internalNetwork *types.NetworkResource
-		var proxyAddr *net.TCPAddr
-		internalNetwork, proxyAddr, err = e.createHTTPGateway(ctx, job, executionID, network)
+		var proxy *egress.Proxy
+		internalNetwork, proxy, err = e.createEgressProxy(ctx, job, executionID, network)
 		if err != nil {
 			return
 		}
+		e.proxies.Put(executionID, proxy)
 		hostConfig.NetworkMode = container.NetworkMode(internalNetwork.Name)
 		containerConfig.Env = append(containerConfig.Env,
-			fmt.Sprintf("http_proxy=%s", proxyAddr.String()),
-			fmt.Sprintf("https_proxy=%s", proxyAddr.String()),
+			fmt.Sprintf("http_proxy=%s", proxy.Addr().String()),
+			fmt.Sprintf("https_proxy=%s", proxy.Addr().String()),
 		)
 	default:
 		err = fmt.Errorf("unsupported network type %q", network.Type.String())
@@ -94,20 +73,25 @@ func (e *Executor) setupNetworkForJob(
 	return
 }
 
-//nolint:funlen,gocyclo
-func (e *Executor) createHTTPGateway(
+// createEgressProxy creates an internal only bridge network for the job container, which can't reach any other
+// network, and starts an egress proxy for the execution listening on the address of the host on that network.
+// The proxy is then the only way out of the network, and only lets through HTTP requests to the allowed domains.
+//
+// If the compute node itself runs in a container of the Docker host, it can't listen on the address of the host on
+// the network. The container of the node is then connected to the network instead, and the proxy listens on the
+// address of the container on that network.
+func (e *Executor) createEgressProxy(
 	ctx context.Context,
 	job string,
 	executionID string,
 	network *models.NetworkConfig,
-) (*types.NetworkResource, *net.TCPAddr, error) {
-	// Get the gateway image if we don't have it already
-	err := e.client.PullImage(ctx, httpGatewayImage, config.GetDockerCredentials())
-	if err != nil {
-		return nil, nil, pkgerrors.Wrap(err, "error pulling gateway image")
+) (*types.NetworkResource, *egress.Proxy, error) {
+	if len(network.DomainSet()) == 0 {
+		return nil,
+			nil,
+			fmt.Errorf("invalid networking configuration, at least one domain is required when %s networking is enabled", models.NetworkHTTP)
 	}
 
-	// Create an internal only bridge network to join our gateway and job container
 	networkResp, err := e.client.NetworkCreate(ctx, e.dockerObjectName(executionID, job, "network"), types.NetworkCreate{
 		Driver:     "bridge",
 		Scope:      "local",
@@ -119,90 +103,78 @@ func (e *Executor) createHTTPGateway(
 		return nil, nil, pkgerrors.Wrap(err, "error creating network")
 	}
 
-	// Get the subnet that Docker has picked for the newly created network
+	// Get the subnet and gateway that Docker has picked for the newly created network
 	internalNetwork, err := e.client.NetworkInspect(ctx, networkResp.ID, types.NetworkInspectOptions{})
-	if err != nil || len(internalNetwork.IPAM.Config) < 1 {
+	if err != nil {
 		return nil, nil, pkgerrors.Wrap(err, "error getting network subnet")
 	}
-	subnet := internalNetwork.IPAM.Config[0].Subnet
-
-	if len(network.DomainSet()) == 0 {
-		return nil,
-			nil,
-			fmt.Errorf("invalid networking configuration, at least one domain is required when %s networking is enabled", models.NetworkHTTP)
+	if len(internalNetwork.IPAM.Config) < 1 {
+		return nil, nil, fmt.Errorf("network %s has no subnet", internalNetwork.Name)
 	}
-
-	// Create the gateway container initially attached to the *host* network
-	domainList, derr := json.Marshal(network.DomainSet())
-	clientList, cerr := json.Marshal([]string{subnet})
-	if derr != nil || cerr != nil {
-		return nil, nil, pkgerrors.Wrap(errors.Join(derr, cerr), "error preparing gateway config")
+	_, subnet, err := net.ParseCIDR(internalNetwork.IPAM.Config[0].Subnet)
+	if err != nil {
+		return nil, nil, pkgerrors.Wrap(err, "error parsing network subnet")
+	}
+	gateway := net.ParseIP(internalNetwork.IPAM.Config[0].Gateway)
+	if gateway == nil {
+		// Docker assigns the first address of the subnet to the bridge by default
+		gateway = slices.Clone(subnet.IP.To4())
+		if gateway == nil {
+			return nil, nil, fmt.Errorf("network %s has no IPv4 gateway", internalNetwork.Name)
+		}
+		gateway[len(gateway)-1]++
 	}
 
-	gatewayContainer, err := e.client.ContainerCreate(ctx, &container.Config{
-		Image: httpGatewayImage,
-		Env: []string{
-			fmt.Sprintf("BACALHAU_HTTP_CLIENTS=%s", clientList),
-			fmt.Sprintf("BACALHAU_HTTP_DOMAINS=%s", domainList),
-			fmt.Sprintf("BACALHAU_JOB_ID=%s", job),
-			fmt.Sprintf("BACALHAU_EXECUTION_ID=%s", executionID),
-		},
-		Healthcheck:     &container.HealthConfig{}, //TODO
-		NetworkDisabled: false,
-		Labels:          e.containerLabels(executionID, job),
-	}, &container.HostConfig{
-		NetworkMode: dockerNetworkBridge,
-		CapAdd:      gatewayCapabilities,
-		ExtraHosts:  []string{dockerHostAddCommand},
-	}, nil, nil, e.dockerObjectName(executionID, job, "gateway"))
+	params := egress.ProxyParams{
+		JobID:       job,
+		ExecutionID: executionID,
+		Network:     network,
+		Addr:        net.JoinHostPort(gateway.String(), "0"),
+		Clients:     subnet,
+		RateLimit:   egress.DefaultRateLimit,
+	}
+	proxy, err := egress.NewProxy(ctx, params)
+	if errors.Is(err, syscall.EADDRNOTAVAIL) {
+		var addr net.IP
+		addr, err = e.connectNodeContainer(ctx, &internalNetwork)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Addr = net.JoinHostPort(addr.String(), "0")
+		proxy, err = egress.NewProxy(ctx, params)
+	}
 	if err != nil {
-		return nil, nil, pkgerrors.Wrap(err, "error creating gateway container")
+		return nil, nil, err
 	}
+	return &internalNetwork, proxy, nil
+}
 
-	// Attach the bridge network to the container
-	err = e.client.NetworkConnect(ctx, internalNetwork.ID, gatewayContainer.ID, nil)
+// connectNodeContainer connects the container the compute node runs in to the network, and returns the address of
+// the container on the network. It fails if the node doesn't run in a container of the Docker host.
+func (e *Executor) connectNodeContainer(ctx context.Context, network *types.NetworkResource) (net.IP, error) {
+	// Docker sets the hostname of containers to their ID, unless it was overridden
+	hostname, err := os.Hostname()
 	if err != nil {
-		return nil, nil, pkgerrors.Wrap(err, "error attaching network to gateway")
+		return nil, pkgerrors.Wrap(err, "error getting hostname")
 	}
-
-	// Start the container and wait for it to come up
-	err = e.client.ContainerStart(ctx, gatewayContainer.ID, container.StartOptions{})
+	node, err := e.client.ContainerInspect(ctx, hostname)
 	if err != nil {
-		return nil, nil, pkgerrors.Wrap(err, "failed to start network gateway container")
+		return nil, fmt.Errorf("%s networking requires the compute node to run on the Docker host, "+
+			"or in a container of the Docker host with its default hostname: %w", models.NetworkHTTP, err)
 	}
-
-	stdout, stderr, err := e.client.FollowLogs(ctx, gatewayContainer.ID)
+	if err = e.client.NetworkConnect(ctx, network.ID, node.ID, nil); err != nil {
+		return nil, pkgerrors.Wrap(err, "error connecting the compute node to the network")
+	}
+	node, err = e.client.ContainerInspect(ctx, node.ID)
 	if err != nil {
-		return nil, nil, pkgerrors.Wrap(err, "failed to get gateway container logs")
+		return nil, pkgerrors.Wrap(err, "error getting the address of the compute node on the network")
 	}
-	go logger.LogStream(log.Ctx(ctx).With().Str("Source", "stdout").Logger().WithContext(ctx), stdout)
-	go logger.LogStream(log.Ctx(ctx).With().Str("Source", "stderr").Logger().WithContext(ctx), stderr)
-
-	// Look up the IP address of the gateway container and attach it to the spec
-	var containerDetails types.ContainerJSON
-	for {
-		containerDetails, err = e.client.ContainerInspect(ctx, gatewayContainer.ID)
-		if err != nil {
-			return nil, nil, pkgerrors.Wrap(err, "error getting gateway container details")
+	if node.NetworkSettings != nil {
+		if endpoint, ok := node.NetworkSettings.Networks[network.Name]; ok {
+			if addr := net.ParseIP(endpoint.IPAddress); addr != nil {
+				return addr, nil
+			}
 		}
-		switch containerDetails.State.Health.Status {
-		case types.NoHealthcheck:
-			return nil, nil, errors.New("expecting gateway image to have healthcheck defined")
-		case types.Unhealthy:
-			return nil, nil, errors.New("gateway container failed to start")
-		case types.Starting:
-			time.Sleep(httpGatewayHealthcheckInterval)
-			continue
-		}
-
-		break
-	}
-
-	networkAttachment, ok := containerDetails.NetworkSettings.Networks[internalNetwork.Name]
-	if !ok || networkAttachment.IPAddress == "" {
-		return nil, nil, fmt.Errorf("gateway does not appear to be attached to internal network")
 	}
-	proxyIP := net.ParseIP(networkAttachment.IPAddress)
-	proxyAddr := net.TCPAddr{IP: proxyIP, Port: httpProxyPort}
-	return &internalNetwork, &proxyAddr, err
+	return nil, fmt.Errorf("compute node has no address on network %s", network.Name)
 }
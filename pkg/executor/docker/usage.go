@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// usageSampleInterval is how often the resource usage of a running container is sampled
+const usageSampleInterval = 2 * time.Second
+
+// sampleUsage samples the cgroup stats of the container until ctx is done, and then sends the usage it observed.
+// Stats of a container are reset once it exits, so the highest value of each counter is kept.
+// Short lived containers may exit before they are sampled, so their peak memory is read by readPeakMemory.
+func (h *executionHandler) sampleUsage(ctx context.Context) <-chan *models.ResourceUsage {
+	usageCh := make(chan *models.ResourceUsage, 1)
+	go func() {
+		usage := new(models.ResourceUsage)
+		defer func() {
+			usageCh <- usage
+		}()
+		ticker := time.NewTicker(usageSampleInterval)
+		defer ticker.Stop()
+		for {
+			if sample, err := h.statsSample(ctx); err == nil {
+				mergeUsageSample(usage, sample)
+			} else if ctx.Err() == nil {
+				h.logger.Debug().Err(err).Msg("failed to sample container stats")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return usageCh
+}
+
+// readPeakMemory records the peak memory of the parent cgroup of the container, which outlives the container
+// and so can be read once it exited.
+func (h *executionHandler) readPeakMemory(usage *models.ResourceUsage) {
+	if h.cgroup == nil {
+		return
+	}
+	peak, err := h.cgroup.peakMemory()
+	if err != nil {
+		h.logger.Debug().Err(err).Msg("failed to read the peak memory of the container")
+		return
+	}
+	usage.PeakMemory = max(usage.PeakMemory, peak)
+}
+
+func (h *executionHandler) statsSample(ctx context.Context) (models.ResourceUsage, error) {
+	resp, err := h.client.ContainerStatsOneShot(ctx, h.containerID)
+	if err != nil {
+		return models.ResourceUsage{}, err
+	}
+	defer resp.Body.Close()
+
+	var stats types.StatsJSON
+	if err = json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return models.ResourceUsage{}, err
+	}
+	return usageFromStats(stats), nil
+}
+
+// usageFromStats converts the cgroup stats of a container to the resources it used since it started
+func usageFromStats(stats types.StatsJSON) models.ResourceUsage {
+	usage := models.ResourceUsage{
+		CPUSeconds: float64(stats.CPUStats.CPUUsage.TotalUsage) / float64(time.Second),
+		// max_usage is only reported by cgroup v1
+		PeakMemory: max(stats.MemoryStats.MaxUsage, stats.MemoryStats.Usage),
+	}
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			usage.DiskReadBytes += entry.Value
+		case "write":
+			usage.DiskWriteBytes += entry.Value
+		}
+	}
+	for _, network := range stats.Networks {
+		usage.NetworkRxBytes += network.RxBytes
+		usage.NetworkTxBytes += network.TxBytes
+	}
+	return usage
+}
+
+// mergeUsageSample keeps the highest value of each counter of the usage
+func mergeUsageSample(usage *models.ResourceUsage, sample models.ResourceUsage) {
+	usage.CPUSeconds = max(usage.CPUSeconds, sample.CPUSeconds)
+	usage.PeakMemory = max(usage.PeakMemory, sample.PeakMemory)
+	usage.DiskReadBytes = max(usage.DiskReadBytes, sample.DiskReadBytes)
+	usage.DiskWriteBytes = max(usage.DiskWriteBytes, sample.DiskWriteBytes)
+	usage.NetworkRxBytes = max(usage.NetworkRxBytes, sample.NetworkRxBytes)
+	usage.NetworkTxBytes = max(usage.NetworkTxBytes, sample.NetworkTxBytes)
+}
@@ -0,0 +1,57 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestUsageFromStats(t *testing.T) {
+	stats := types.StatsJSON{
+		Stats: types.Stats{
+			CPUStats: types.CPUStats{CPUUsage: types.CPUUsage{TotalUsage: 1_500_000_000}},
+			MemoryStats: types.MemoryStats{
+				Usage:    100,
+				MaxUsage: 300,
+			},
+			BlkioStats: types.BlkioStats{IoServiceBytesRecursive: []types.BlkioStatEntry{
+				{Op: "Read", Value: 10},
+				{Op: "read", Value: 5},
+				{Op: "Write", Value: 20},
+				{Op: "total", Value: 35},
+			}},
+		},
+		Networks: map[string]types.NetworkStats{
+			"eth0": {RxBytes: 1, TxBytes: 2},
+			"eth1": {RxBytes: 3, TxBytes: 4},
+		},
+	}
+
+	require.Equal(t, models.ResourceUsage{
+		CPUSeconds:     1.5,
+		PeakMemory:     300,
+		DiskReadBytes:  15,
+		DiskWriteBytes: 20,
+		NetworkRxBytes: 4,
+		NetworkTxBytes: 6,
+	}, usageFromStats(stats))
+}
+
+func TestMergeUsageSample_KeepsHighestCounters(t *testing.T) {
+	usage := &models.ResourceUsage{CPUSeconds: 2, PeakMemory: 500, DiskReadBytes: 10}
+	// stats of a container are zero once it exits
+	mergeUsageSample(usage, models.ResourceUsage{})
+	mergeUsageSample(usage, models.ResourceUsage{CPUSeconds: 1, PeakMemory: 600, DiskWriteBytes: 5})
+
+	require.Equal(t, &models.ResourceUsage{
+		CPUSeconds:     2,
+		PeakMemory:     600,
+		DiskReadBytes:  10,
+		DiskWriteBytes: 5,
+	}, usage)
+}
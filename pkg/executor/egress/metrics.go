@@ -0,0 +1,20 @@
+package egress
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+
+	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
+)
+
+var (
+	egressMeter = otel.GetMeterProvider().Meter("egress-proxy")
+)
+
+var (
+	EgressRequests = lo.Must(telemetry.NewCounter(
+		egressMeter,
+		"egress_requests",
+		"Number of outbound requests of executions allowed or denied by their network config",
+	))
+)
@@ -0,0 +1,364 @@
+// Package egress provides the proxy that filters the outbound HTTP requests of executions with HTTP networking.
+package egress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// DefaultRateLimit is the default bandwidth of a proxy in bytes per second, 10Mbit/s.
+	DefaultRateLimit = rate.Limit(10 * 1000 * 1000 / 8)
+
+	// rateLimitBurst is the most bytes transferred at once when the bandwidth is limited.
+	rateLimitBurst = 32 * 1024
+
+	// jobIDHeader is added to the HTTP requests of jobs, so that requester nodes can reject jobs submitted by jobs.
+	jobIDHeader = "X-Bacalhau-Job-ID"
+
+	dialTimeout = 30 * time.Second
+)
+
+// hopHeaders are the headers of a connection that are not forwarded by proxies.
+var hopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type ProxyParams struct {
+	JobID       string
+	ExecutionID string
+	Network     *models.NetworkConfig
+
+	// Addr is the address the proxy listens on.
+	Addr string
+
+	// Clients is the subnet of the clients allowed to use the proxy, or nil to allow all clients.
+	Clients *net.IPNet
+
+	// RateLimit is the bandwidth of the proxy in bytes per second, shared by all its requests in both directions.
+	// Zero means no limit.
+	RateLimit rate.Limit
+
+	// allowLocal lets the proxy connect to loopback and link-local addresses, for testing.
+	allowLocal bool
+}
+
+// errAddressNotAllowed is returned when dialing a loopback or link-local address, which would give jobs access to
+// the services of the compute node, or of its cloud provider such as its metadata service.
+var errAddressNotAllowed = errors.New("loopback and link-local addresses are not allowed")
+
+// Proxy is an HTTP proxy for the outbound requests of an execution. It only forwards requests to the hosts allowed
+// by the network config of the execution, tunneling HTTPS requests with CONNECT, and records every request it
+// allows and denies in the egress log of the execution.
+type Proxy struct {
+	params    ProxyParams
+	listener  net.Listener
+	server    *http.Server
+	transport *http.Transport
+	dialer    *net.Dialer
+	limiter   *rate.Limiter
+	logger    zerolog.Logger
+
+	// ctx is canceled when the proxy is closed, closing the tunnels it opened.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mtx sync.Mutex
+	log models.EgressLog
+}
+
+// NewProxy starts a proxy listening on the address of the params.
+func NewProxy(ctx context.Context, params ProxyParams) (*Proxy, error) {
+	if params.Network == nil || params.Network.Type != models.NetworkHTTP {
+		return nil, fmt.Errorf("egress proxy requires %s networking", models.NetworkHTTP)
+	}
+	listener, err := net.Listen("tcp", params.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("egress proxy failed to listen on %s: %w", params.Addr, err)
+	}
+
+	proxy := &Proxy{
+		params:   params,
+		listener: listener,
+		limiter:  rate.NewLimiter(rate.Inf, rateLimitBurst),
+		logger: log.Ctx(ctx).With().
+			Str("job", params.JobID).
+			Str("execution", params.ExecutionID).
+			Logger(),
+	}
+	if params.RateLimit > 0 {
+		proxy.limiter.SetLimit(params.RateLimit)
+	}
+	// the addresses are checked once resolved, so that domains resolving to local addresses are rejected as well
+	proxy.dialer = &net.Dialer{Timeout: dialTimeout, Control: proxy.checkAddress}
+	proxy.ctx, proxy.cancel = context.WithCancel(context.Background())
+	proxy.transport = &http.Transport{
+		DialContext:         proxy.dial,
+		MaxIdleConns:        16,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	proxy.server = &http.Server{
+		Handler:           proxy,
+		ReadHeaderTimeout: dialTimeout,
+		BaseContext:       func(net.Listener) context.Context { return proxy.ctx },
+	}
+
+	go func() {
+		if err := proxy.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			proxy.logger.Error().Err(err).Msg("egress proxy stopped")
+		}
+	}()
+	proxy.logger.Debug().Stringer("addr", listener.Addr()).Msg("started egress proxy")
+	return proxy, nil
+}
+
+// Addr returns the address the proxy listens on.
+func (p *Proxy) Addr() *net.TCPAddr {
+	return p.listener.Addr().(*net.TCPAddr)
+}
+
+// Log returns a copy of the egress log of the execution.
+func (p *Proxy) Log() *models.EgressLog {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.log.Copy()
+}
+
+// Close stops the proxy and closes its connections.
+func (p *Proxy) Close() error {
+	p.cancel()
+	p.transport.CloseIdleConnections()
+	return p.server.Close()
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.allowsClient(r.RemoteAddr) {
+		p.logger.Warn().Str("client", r.RemoteAddr).Msg("egress proxy rejected request from unknown client")
+		http.Error(w, "client not allowed", http.StatusForbidden)
+		return
+	}
+	if r.URL.Host == "" {
+		http.Error(w, "request is not a proxy request", http.StatusBadRequest)
+		return
+	}
+
+	host := r.URL.Hostname()
+	allowed := p.params.Network.AllowsHost(host)
+	local := p.isLocalHost(host)
+	p.record(r, host, allowed && !local)
+	if !allowed {
+		http.Error(w, fmt.Sprintf("request to %s denied by the network config of the job, which allows %s",
+			host, strings.Join(p.params.Network.DomainSet(), ", ")), http.StatusForbidden)
+		return
+	} else if local {
+		http.Error(w, fmt.Sprintf("request to %s denied: %s", host, errAddressNotAllowed), http.StatusForbidden)
+		return
+	}
+
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+	} else {
+		p.forward(w, r)
+	}
+}
+
+func (p *Proxy) allowsClient(remoteAddr string) bool {
+	if p.params.Clients == nil {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && p.params.Clients.Contains(ip)
+}
+
+func (p *Proxy) record(r *http.Request, host string, allowed bool) {
+	p.mtx.Lock()
+	p.log.Record(host, allowed)
+	p.mtx.Unlock()
+
+	EgressRequests.Inc(r.Context(), attribute.Bool("allowed", allowed))
+	if allowed {
+		p.logger.Debug().Str("method", r.Method).Str("host", host).Msg("allowed outbound request")
+	} else {
+		p.logger.Info().Str("method", r.Method).Str("host", host).Msg("denied outbound request")
+	}
+}
+
+// isLocalHost returns whether the host is the name or an address of a loopback or link-local interface.
+func (p *Proxy) isLocalHost(host string) bool {
+	if p.params.allowLocal {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return isLocalIP(ip)
+	}
+	return strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost")
+}
+
+// checkAddress rejects connections to loopback and link-local addresses.
+func (p *Proxy) checkAddress(_, address string, _ syscall.RawConn) error {
+	if p.params.allowLocal {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isLocalIP(ip) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, host)
+	}
+	return nil
+}
+
+func isLocalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// dial connects to the address, unless it resolves to a loopback or link-local address.
+func (p *Proxy) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	return p.dialer.DialContext(ctx, network, addr)
+}
+
+// dialError writes the response to a request the proxy couldn't connect to the destination of.
+func (p *Proxy) dialError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, errAddressNotAllowed) {
+		p.logger.Info().Err(err).Str("host", r.URL.Hostname()).Msg("denied outbound request to a local address")
+		http.Error(w, fmt.Sprintf("request to %s denied: %s", r.URL.Hostname(), errAddressNotAllowed), http.StatusForbidden)
+		return
+	}
+	p.logger.Debug().Err(err).Str("host", r.URL.Hostname()).Msg("outbound request failed")
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
+
+// forward sends a plain HTTP request and copies its response back to the client.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request) {
+	request := r.Clone(r.Context())
+	request.RequestURI = ""
+	removeHopHeaders(request.Header)
+	request.Header.Set(jobIDHeader, p.params.JobID)
+	if r.Body != nil {
+		request.Body = io.NopCloser(p.limit(r.Body))
+	}
+
+	response, err := p.transport.RoundTrip(request)
+	if err != nil {
+		p.dialError(w, r, err)
+		return
+	}
+	defer response.Body.Close()
+
+	removeHopHeaders(response.Header)
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(response.StatusCode)
+	_, _ = io.Copy(w, p.limit(response.Body))
+}
+
+// tunnel connects the client to the host of a CONNECT request, until either of them closes the connection or the
+// proxy is closed.
+func (p *Proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := p.dial(r.Context(), "tcp", r.URL.Host)
+	if err != nil {
+		p.dialError(w, r, err)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = upstream.Close()
+		http.Error(w, "tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stop := context.AfterFunc(p.ctx, func() {
+		_ = client.Close()
+		_ = upstream.Close()
+	})
+	defer func() {
+		stop()
+		_ = client.Close()
+		_ = upstream.Close()
+	}()
+
+	if _, err = client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+		return
+	}
+	go func() {
+		_, _ = io.Copy(upstream, p.limit(buffered))
+		if conn, ok := upstream.(*net.TCPConn); ok {
+			_ = conn.CloseWrite()
+		}
+	}()
+	_, _ = io.Copy(client, p.limit(upstream))
+}
+
+// limit returns a reader of r limited to the bandwidth of the proxy.
+func (p *Proxy) limit(r io.Reader) io.Reader {
+	if p.limiter.Limit() == rate.Inf {
+		return r
+	}
+	return &limitedReader{ctx: p.ctx, reader: r, limiter: p.limiter}
+}
+
+type limitedReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rate.Limiter
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	if len(b) > l.limiter.Burst() {
+		b = b[:l.limiter.Burst()]
+	}
+	n, err := l.reader.Read(b)
+	if n > 0 {
+		if waitErr := l.limiter.WaitN(l.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, name := range header.Values("Connection") {
+		for _, field := range strings.Split(name, ",") {
+			header.Del(strings.TrimSpace(field))
+		}
+	}
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
@@ -0,0 +1,160 @@
+//go:build unit || !integration
+
+package egress
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ProxyTestSuite struct {
+	suite.Suite
+	server    *httptest.Server
+	tlsServer *httptest.Server
+}
+
+func TestProxyTestSuite(t *testing.T) {
+	suite.Run(t, new(ProxyTestSuite))
+}
+
+func (s *ProxyTestSuite) SetupTest() {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello " + r.Header.Get(jobIDHeader)))
+	})
+	s.server = httptest.NewServer(handler)
+	s.tlsServer = httptest.NewTLSServer(handler)
+}
+
+func (s *ProxyTestSuite) TearDownTest() {
+	s.server.Close()
+	s.tlsServer.Close()
+}
+
+// proxy starts a proxy for the domains. The proxy can connect to local addresses, where the test servers listen.
+func (s *ProxyTestSuite) proxy(params ProxyParams, domains ...string) *Proxy {
+	params.allowLocal = true
+	params.JobID = "job"
+	params.ExecutionID = "execution"
+	params.Network = &models.NetworkConfig{Type: models.NetworkHTTP, Domains: domains}
+	params.Addr = "127.0.0.1:0"
+	proxy, err := NewProxy(context.Background(), params)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = proxy.Close() })
+	return proxy
+}
+
+// get sends a request through the proxy and returns its status code and body
+func (s *ProxyTestSuite) get(proxy *Proxy, target string) (int, string) {
+	transport := s.tlsServer.Client().Transport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(&url.URL{Scheme: "http", Host: proxy.Addr().String()})
+	response, err := (&http.Client{Transport: transport}).Get(target)
+	if err != nil {
+		// the client returns an error when the proxy refuses to tunnel a request
+		return http.StatusForbidden, err.Error()
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	s.Require().NoError(err)
+	return response.StatusCode, string(body)
+}
+
+func (s *ProxyTestSuite) TestAllowed() {
+	proxy := s.proxy(ProxyParams{RateLimit: DefaultRateLimit}, "127.0.0.1")
+
+	status, body := s.get(proxy, s.server.URL)
+	s.Equal(http.StatusOK, status)
+	s.Equal("hello job", body)
+
+	status, body = s.get(proxy, s.tlsServer.URL)
+	s.Equal(http.StatusOK, status)
+	s.Equal("hello ", body, "the header can't be added to tunneled requests")
+
+	s.Equal(&models.EgressLog{Allowed: 2}, proxy.Log())
+}
+
+func (s *ProxyTestSuite) TestDenied() {
+	proxy := s.proxy(ProxyParams{}, "example.com")
+
+	status, body := s.get(proxy, s.server.URL)
+	s.Equal(http.StatusForbidden, status)
+	s.Contains(body, "request to 127.0.0.1 denied by the network config of the job, which allows example.com")
+
+	status, _ = s.get(proxy, s.tlsServer.URL)
+	s.Equal(http.StatusForbidden, status)
+
+	s.Equal(&models.EgressLog{Denied: 2, DeniedHosts: []string{"127.0.0.1"}}, proxy.Log())
+}
+
+func (s *ProxyTestSuite) TestLocalAddressesDenied() {
+	proxy, err := NewProxy(context.Background(), ProxyParams{
+		JobID:   "job",
+		Network: &models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1", "localhost", "169.254.169.254"}},
+		Addr:    "127.0.0.1:0",
+	})
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = proxy.Close() })
+
+	serverURL, err := url.Parse(s.server.URL)
+	s.Require().NoError(err)
+	for _, host := range []string{"127.0.0.1", "localhost", "169.254.169.254"} {
+		s.Run(host, func() {
+			target := *serverURL
+			target.Host = net.JoinHostPort(host, serverURL.Port())
+			status, body := s.get(proxy, target.String())
+			s.Equal(http.StatusForbidden, status)
+			s.Contains(body, "denied")
+		})
+	}
+	s.Equal(uint64(3), proxy.Log().Denied)
+}
+
+func (s *ProxyTestSuite) TestLocalAddressesDeniedOnceResolved() {
+	proxy := s.proxy(ProxyParams{}, "127.0.0.1")
+	proxy.params.allowLocal = false
+
+	serverURL, err := url.Parse(s.server.URL)
+	s.Require().NoError(err)
+	_, err = proxy.dial(context.Background(), "tcp", serverURL.Host)
+	s.ErrorIs(err, errAddressNotAllowed)
+
+	s.ErrorIs(proxy.checkAddress("tcp", "[::1]:80", nil), errAddressNotAllowed)
+	s.ErrorIs(proxy.checkAddress("tcp", "169.254.169.254:80", nil), errAddressNotAllowed)
+	s.ErrorIs(proxy.checkAddress("tcp", "0.0.0.0:80", nil), errAddressNotAllowed)
+	s.NoError(proxy.checkAddress("tcp", "93.184.216.34:443", nil))
+}
+
+func (s *ProxyTestSuite) TestClients() {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	s.Require().NoError(err)
+	proxy := s.proxy(ProxyParams{Clients: subnet}, "127.0.0.1")
+
+	status, _ := s.get(proxy, s.server.URL)
+	s.Equal(http.StatusForbidden, status)
+	s.True(proxy.Log().IsEmpty())
+}
+
+func (s *ProxyTestSuite) TestNotProxyRequest() {
+	proxy := s.proxy(ProxyParams{}, "127.0.0.1")
+
+	response, err := http.Get("http://" + proxy.Addr().String())
+	s.Require().NoError(err)
+	defer response.Body.Close()
+	s.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func (s *ProxyTestSuite) TestRequiresHTTPNetworking() {
+	_, err := NewProxy(context.Background(), ProxyParams{
+		Network: &models.NetworkConfig{Type: models.NetworkFull},
+		Addr:    "127.0.0.1:0",
+	})
+	s.Error(err)
+}
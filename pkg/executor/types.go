@@ -52,6 +52,49 @@ type Executor interface {
 	GetLogStream(ctx context.Context, request LogStreamRequest) (io.ReadCloser, error)
 }
 
+// Checkpointer is an optional capability of executors that can checkpoint their executions,
+// so that a later execution of the same job can resume from where a stopped execution left off.
+type Checkpointer interface {
+	// Checkpoint exports a snapshot of the result paths and checkpoint directory of an ongoing execution,
+	// identified by its executionID, into dir. The snapshot is laid out as CheckpointOutputsDir and
+	// CheckpointStateDir sub-directories of dir, which is also how it is expected when restoring it.
+	// Returns an error if the execution does not exist, or if it is not checkpointed.
+	Checkpoint(ctx context.Context, executionID string, dir string) error
+}
+
+// Execer is an optional capability of executors that can run commands in, or attach to, their ongoing executions,
+// which is useful to debug them interactively.
+type Execer interface {
+	// Exec runs a command in the ongoing execution identified by request.ExecutionID, or attaches to its
+	// main process if request.Attach is set, and copies the given streams until it exits or ctx is done.
+	// Returns the exit code of the command, or an error if the execution does not exist or is not running.
+	Exec(ctx context.Context, request ExecRequest, streams ExecStreams) (int, error)
+}
+
+// ExecRequest encapsulates the parameters required to run a command in an ongoing execution.
+type ExecRequest struct {
+	ExecutionID string
+	Command     []string // Command to run. It is ignored when attaching to the main process.
+	Attach      bool     // Attach to the main process of the execution instead of running a command.
+	TTY         bool     // Allocate a pseudo-terminal for the command.
+	Stdin       bool     // Keep the standard input of the command open.
+}
+
+// ExecStreams are the streams of a command running in an ongoing execution.
+type ExecStreams struct {
+	Stdin  io.Reader                  // Standard input of the command, if request.Stdin is set.
+	Stdout io.Writer                  // Standard output of the command, or its terminal if request.TTY is set.
+	Stderr io.Writer                  // Standard error of the command. Unused if request.TTY is set.
+	Resize <-chan models.TerminalSize // Resizes of the terminal, if request.TTY is set.
+}
+
+const (
+	// CheckpointOutputsDir is the directory of a checkpoint with the content of the result paths of the execution
+	CheckpointOutputsDir = "outputs"
+	// CheckpointStateDir is the directory of a checkpoint with the content of the checkpoint path of the execution
+	CheckpointStateDir = "state"
+)
+
 // LogStreamRequest encapsulates the parameters required to retrieve a log stream.
 type LogStreamRequest struct {
 	JobID       string
@@ -71,7 +114,17 @@ type RunCommandRequest struct {
 	Inputs       []storage.PreparedStorage // Prepared storage elements that are used as inputs.
 	ResultsDir   string                    // Directory where results should be stored.
 	EngineParams *models.SpecConfig        // Engine-specific configuration parameters.
+	Env          map[string]string         // Environment variables of the task, such as the array index of an array job.
 	OutputLimits OutputLimits              // Output size limits for the execution.
+	Checkpoint   *CheckpointParams         // Checkpointing of the execution, if its task is checkpointed.
+}
+
+// CheckpointParams encapsulates the parameters required to checkpoint an execution, and to restore
+// the checkpoint of a previous execution of the same job before starting it.
+type CheckpointParams struct {
+	Path       string // Directory inside the execution where it keeps the state it resumes from.
+	StateDir   string // Local directory that is mounted at Path.
+	RestoreDir string // Local directory of the checkpoint to restore before starting, if any.
 }
 
 // Error variables for execution states.
@@ -16,10 +16,10 @@ type bidStrategyFromExecutor struct {
 func NewExecutorSpecificBidStrategy(provider executor.ExecutorProvider) bidstrategy.BidStrategy {
 	return bidstrategy.NewChainedBidStrategy(
 		bidstrategy.WithSemantics(
-			semantic.NewProviderInstalledStrategy[executor.Executor](
+			semantic.NewProviderInstalledArrayStrategy[executor.Executor](
 				provider,
-				func(j *models.Job) string {
-					return j.Task().Engine.Type
+				func(j *models.Job) []string {
+					return j.AllEngineTypes()
 				},
 			),
 			&bidStrategyFromExecutor{
@@ -3,11 +3,14 @@ package util
 import (
 	"context"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/docker"
 	noop_executor "github.com/bacalhau-project/bacalhau/pkg/executor/noop"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/plugin"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
@@ -16,6 +19,7 @@ import (
 	ipfs_storage "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
 	localdirectory "github.com/bacalhau-project/bacalhau/pkg/storage/local_directory"
 	noop_storage "github.com/bacalhau-project/bacalhau/pkg/storage/noop"
+	storage_grpc "github.com/bacalhau-project/bacalhau/pkg/storage/plugins/grpc"
 	repo "github.com/bacalhau-project/bacalhau/pkg/storage/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/tracing"
@@ -94,6 +98,29 @@ func configureS3StorageProvider(cm *system.CleanupManager) (*s3.StorageProvider,
 	return s3Storage, nil
 }
 
+type PluginStorageOptions struct {
+	Plugins []plugin.Config
+}
+
+// NewPluginStorageProvider starts the storage plugins and returns a provider of their storages.
+// Plugins that are missing or fail to start are logged and skipped.
+func NewPluginStorageProvider(
+	ctx context.Context,
+	cm *system.CleanupManager,
+	pluginOptions PluginStorageOptions,
+) (storage.StorageProvider, error) {
+	pm := plugin.NewManager[storage.Storage](
+		storage_grpc.PluggableStoragePluginName, &storage_grpc.StorageGRPCPlugin{})
+	for _, cfg := range pluginOptions.Plugins {
+		if err := pm.RegisterPlugin(cfg); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("skipping storage plugin %s", cfg.Name)
+		}
+	}
+	cm.RegisterCallbackWithContext(pm.Stop)
+	pm.StartAvailable(ctx)
+	return pm, nil
+}
+
 func NewNoopStorageProvider(
 	ctx context.Context,
 	cm *system.CleanupManager,
@@ -0,0 +1,56 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
+	"go.uber.org/atomic"
+)
+
+// ErrCallLimitExceeded is returned when the modules of an execution make more function calls than their limit.
+var ErrCallLimitExceeded = errors.New("execution exceeded its function call limit")
+
+// callLimiter counts the function calls made by the modules of an execution and stops them once they exceed
+// their limit, if they have one. It is not instruction metering: wazero doesn't meter instructions, so loops that
+// don't call functions are only bounded by the timeout of the execution, but counting calls is deterministic,
+// unlike timeouts, so the count is also reported as the usage of the execution.
+type callLimiter struct {
+	// limit is the maximum number of calls, or zero if calls are only counted
+	limit uint64
+	calls *atomic.Uint64
+}
+
+func newCallLimiter(limit uint64) *callLimiter {
+	return &callLimiter{limit: limit, calls: atomic.NewUint64(0)}
+}
+
+// count returns the number of function calls made so far
+func (l *callLimiter) count() uint64 {
+	return l.calls.Load()
+}
+
+// limitCalls returns a context that counts and limits the function calls of the modules compiled with it.
+func (l *callLimiter) limitCalls(ctx context.Context) context.Context {
+	return context.WithValue(ctx, experimental.FunctionListenerFactoryKey{}, l)
+}
+
+func (l *callLimiter) NewFunctionListener(api.FunctionDefinition) experimental.FunctionListener {
+	return l
+}
+
+// Before panics once the limit is exceeded, which wazero recovers from by failing the call with the panic's error.
+func (l *callLimiter) Before(context.Context, api.Module, api.FunctionDefinition, []uint64, experimental.StackIterator) {
+	if l.calls.Inc() > l.limit && l.limit > 0 {
+		panic(ErrCallLimitExceeded)
+	}
+}
+
+func (l *callLimiter) After(context.Context, api.Module, api.FunctionDefinition, []uint64) {}
+
+func (l *callLimiter) Abort(context.Context, api.Module, api.FunctionDefinition, error) {}
+
+// compile-time interface checks
+var _ experimental.FunctionListenerFactory = (*callLimiter)(nil)
+var _ experimental.FunctionListener = (*callLimiter)(nil)
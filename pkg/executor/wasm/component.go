@@ -0,0 +1,99 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// ErrComponentNotSupported is returned when loading a WebAssembly component that doesn't embed a core module
+// of a program that can be run, such as components composed of other components.
+var ErrComponentNotSupported = errors.New("WebAssembly component is not supported")
+
+// componentCoreModuleSection is the id of the sections of a component that hold a core module.
+const componentCoreModuleSection = 1
+
+// runExportPrefix is the prefix of the export of the run function of the wasi:cli/run interface, which is
+// the entrypoint of WASI 0.2 programs that don't export _start.
+const runExportPrefix = "wasi:cli/run@0.2."
+
+// isComponent returns whether the binary is a WebAssembly component. The preamble of components has a layer
+// field of 1 where the preamble of modules has the upper bytes of their version, which are 0.
+func isComponent(binary []byte) bool {
+	return len(binary) >= 8 && bytes.Equal(binary[:4], []byte("\x00asm")) && binary[6] == 1 && binary[7] == 0
+}
+
+// componentCoreModules returns the core modules embedded at the top level of a component, in order.
+func componentCoreModules(binary []byte) ([][]byte, error) {
+	var modules [][]byte
+	for rest := binary[8:]; len(rest) > 0; {
+		id := rest[0]
+		size, n := readULEB128(rest[1:])
+		if n == 0 || uint64(len(rest)-1-n) < size {
+			return nil, fmt.Errorf("%w: malformed section", ErrComponentNotSupported)
+		}
+		content := rest[1+n : 1+n+int(size)]
+		if id == componentCoreModuleSection {
+			modules = append(modules, content)
+		}
+		rest = rest[1+n+int(size):]
+	}
+	return modules, nil
+}
+
+// loadComponent compiles the core module of the program a component embeds. Components built from WASI programs
+// embed the program as a core module that defines the memory, and adapters or shims that import it. The program
+// runs with the WASI preview 1 functions it imports, and the WASI 0.2 interfaces are linked by name, so only the
+// interfaces the loader provides can be imported.
+func (loader *ModuleLoader) loadComponent(ctx context.Context, binary []byte) (wazero.CompiledModule, error) {
+	modules, err := componentCoreModules(binary)
+	if err != nil {
+		return nil, err
+	}
+	var program wazero.CompiledModule
+	for _, module := range modules {
+		compiled, err := loader.runtime.CompileModule(ctx, module)
+		if err != nil {
+			return nil, err
+		}
+		if program == nil && len(compiled.ExportedMemories()) > 0 {
+			program = compiled
+		} else if err = compiled.Close(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if program == nil {
+		return nil, fmt.Errorf("%w: it has no core module defining a memory", ErrComponentNotSupported)
+	}
+	return program, nil
+}
+
+// componentEntryPoint returns the export of the run function of wasi:cli/run, which is the entrypoint of
+// components that don't export the _start function of WASI preview 1 commands.
+func componentEntryPoint(definitions map[string]api.FunctionDefinition) (string, bool) {
+	for name, definition := range definitions {
+		if strings.HasPrefix(name, runExportPrefix) && strings.HasSuffix(name, "#run") &&
+			len(definition.ParamTypes()) == 0 && len(definition.ResultTypes()) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// readULEB128 reads an unsigned LEB128 number of at most 32 bits, and returns it with the number of bytes read,
+// which is 0 if the number is malformed.
+func readULEB128(b []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < len(b) && i < 5; i++ {
+		value |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,82 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero"
+)
+
+// component returns a component embedding the core modules, which is only the part of a component that
+// the loader reads.
+func component(modules ...[]byte) []byte {
+	var sections [][]byte
+	for _, module := range modules {
+		sections = append(sections, wasmSection(componentCoreModuleSection, module))
+	}
+	sections = append(sections, wasmSection(0, wasmName("custom")))
+	return append([]byte("\x00asm\x0d\x00\x01\x00"), concat(sections...)...)
+}
+
+// adapterModule imports its memory, like the adapters that components embed besides the program.
+var adapterModule = wasmBinary(
+	wasmSection(2, []byte{1}, wasmName("env"), wasmName("memory"), []byte{2, 0, 1}),
+)
+
+// runModule exports the run function of wasi:cli/run.
+var runModule = wasmBinary(
+	wasmSection(1, []byte{1, 0x60, 0, 1, 0x7f}),
+	wasmSection(3, []byte{1, 0}),
+	wasmSection(7, []byte{1}, wasmName("wasi:cli/run@0.2.0#run"), []byte{0, 0}),
+	wasmSection(10, []byte{1, 4, 0, 0x41, 0, 0x0b}),
+)
+
+func TestLoadComponent(t *testing.T) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer func() { require.NoError(t, runtime.Close(ctx)) }()
+	loader := NewModuleLoader(runtime, wazero.NewModuleConfig())
+
+	write := func(binary []byte) string {
+		path := filepath.Join(t.TempDir(), "component.wasm")
+		require.NoError(t, os.WriteFile(path, binary, 0644))
+		return path
+	}
+
+	module, err := loader.Load(ctx, write(component(adapterModule, memoryModule)))
+	require.NoError(t, err)
+	require.Contains(t, module.ExportedMemories(), "memory", "the program is the core module defining the memory")
+	require.Empty(t, module.ImportedMemories())
+
+	for name, binary := range map[string][]byte{
+		"no program": component(adapterModule),
+		"malformed":  []byte("\x00asm\x0d\x00\x01\x00\x01\x10"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loader.Load(ctx, write(binary))
+			require.ErrorIs(t, err, ErrComponentNotSupported)
+		})
+	}
+}
+
+func TestComponentEntryPoint(t *testing.T) {
+	ctx := context.Background()
+	runtime := wazero.NewRuntime(ctx)
+	defer func() { require.NoError(t, runtime.Close(ctx)) }()
+
+	module, err := runtime.CompileModule(ctx, runModule)
+	require.NoError(t, err)
+	entryPoint, found := componentEntryPoint(module.ExportedFunctions())
+	require.True(t, found)
+	require.Equal(t, "wasi:cli/run@0.2.0#run", entryPoint)
+
+	module, err = runtime.CompileModule(ctx, memoryModule)
+	require.NoError(t, err)
+	_, found = componentEntryPoint(module.ExportedFunctions())
+	require.False(t, found)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package wasm
+
+import "time"
+
+// threadCPUTime returns the CPU time used by the calling thread, which is not
+// measured on this platform.
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
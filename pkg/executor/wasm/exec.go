@@ -0,0 +1,58 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+)
+
+// ErrExecNotSupported is returned when running a command in a WASM execution, as modules have no
+// environment to run other commands in. Attaching to the output of the module is supported.
+var ErrExecNotSupported = errors.New("wasm executions do not support running commands, only attaching to them")
+
+// Exec attaches to the output of the module of an ongoing execution until it exits.
+// Running other commands in the execution is not supported.
+func (e *Executor) Exec(ctx context.Context, request executor.ExecRequest, streams executor.ExecStreams) (int, error) {
+	if !request.Attach {
+		return 0, ErrExecNotSupported
+	}
+	handler, found := e.handlers.Get(request.ExecutionID)
+	if !found {
+		return 0, fmt.Errorf("attaching to execution (%s): %w", request.ExecutionID, executor.ErrNotFound)
+	}
+	if !handler.active() {
+		return 0, fmt.Errorf("attaching to execution (%s): %w", request.ExecutionID, executor.ErrAlreadyComplete)
+	}
+	return handler.attach(ctx, streams)
+}
+
+// attach copies the output of the module until it exits, and returns its exit code.
+func (h *executionHandler) attach(ctx context.Context, streams executor.ExecStreams) (int, error) {
+	stdout, stderr := h.logManager.GetDefaultReaders(true)
+	go func() {
+		if _, err := io.Copy(streams.Stdout, stdout); err != nil {
+			h.logger.Debug().Err(err).Msg("failed to copy stdout of attached module")
+		}
+	}()
+	go func() {
+		if _, err := io.Copy(streams.Stderr, stderr); err != nil {
+			h.logger.Debug().Err(err).Msg("failed to copy stderr of attached module")
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-h.waitCh:
+		if h.result == nil {
+			return 0, nil
+		}
+		return h.result.ExitCode, nil
+	}
+}
+
+// compile-time check that the executor can attach to its executions
+var _ executor.Execer = (*Executor)(nil)
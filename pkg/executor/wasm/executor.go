@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/tetratelabs/wazero"
 	"go.uber.org/atomic"
+	"golang.org/x/exp/maps"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
@@ -52,6 +53,13 @@ func (*Executor) ShouldBidBasedOnUsage(
 	request bidstrategy.BidStrategyRequest,
 	usage models.Resources,
 ) (bidstrategy.BidStrategyResponse, error) {
+	if usage.Memory > WasmMaxPagesLimit*WasmPageSize {
+		return bidstrategy.NewBidResponse(false, "run WASM jobs requiring more than the %d bytes of memory a module can address",
+			uint64(WasmMaxPagesLimit*WasmPageSize)), nil
+	}
+	if usage.GPU > 0 {
+		return bidstrategy.NewBidResponse(false, "run jobs requiring GPUs as WASM"), nil
+	}
 	return bidstrategy.NewBidResponse(true, "not place additional requirements on WASM jobs"), nil
 }
 
@@ -95,6 +103,17 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	if err != nil {
 		return fmt.Errorf("decoding wasm arguments: %w", err)
 	}
+	// compute nodes don't bid on such jobs, as the outputs would depend on the HTTP responses
+	if engineParams.Deterministic && request.Network != nil && !request.Network.Disabled() {
+		return fmt.Errorf("starting execution (%s): deterministic WASM executions can't access the network", request.ExecutionID)
+	}
+
+	// the task's environment variables are overridden by the ones in the wasm engine spec
+	if len(request.Env) > 0 {
+		env := maps.Clone(request.Env)
+		maps.Copy(env, engineParams.EnvironmentVariables)
+		engineParams.EnvironmentVariables = env
+	}
 
 	rootFs, err := e.makeFsFromStorage(ctx, request.ResultsDir, request.Inputs, request.Outputs)
 	if err != nil {
@@ -116,6 +135,8 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		executionID: request.ExecutionID,
 		resultsDir:  request.ResultsDir,
 		limits:      request.OutputLimits,
+		cpu:         request.Resources.CPU,
+		network:     request.Network,
 		logger: log.With().
 			Str("execution", request.ExecutionID).
 			Str("job", request.JobID).
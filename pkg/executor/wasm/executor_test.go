@@ -4,13 +4,20 @@ package wasm
 
 import (
 	"context"
+	"runtime"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/system"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/deterministic"
 )
 
 type ExecutorTestSuite struct {
@@ -51,3 +58,124 @@ func (s *ExecutorTestSuite) TestFailingRequestedMemGreaterThan4GB() {
 
 	assert.Contains(s.T(), err.Error(), "requested memory exceeds the wasm limit")
 }
+
+func (s *ExecutorTestSuite) TestShouldBidBasedOnUsage() {
+	e, err := NewExecutor()
+	s.Require().NoError(err)
+
+	for name, tc := range map[string]struct {
+		usage     models.Resources
+		shouldBid bool
+	}{
+		"within the wasm limits":      {usage: models.Resources{CPU: 1, Memory: 1 << 30}, shouldBid: true},
+		"memory over the wasm limits": {usage: models.Resources{Memory: 5 << 30}, shouldBid: false},
+		"gpu":                         {usage: models.Resources{GPU: 1}, shouldBid: false},
+	} {
+		s.Run(name, func() {
+			response, err := e.ShouldBidBasedOnUsage(context.Background(), bidstrategy.BidStrategyRequest{}, tc.usage)
+			s.Require().NoError(err)
+			s.Equal(tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+}
+
+// run runs the entrypoint of the deterministic test program with the given arguments.
+func (s *ExecutorTestSuite) run(ctx context.Context, arguments wasmmodels.EngineArguments, cpu float64) *models.RunCommandResult {
+	e, err := NewExecutor()
+	s.Require().NoError(err)
+
+	arguments.EntryModule = prepareModule(s.T(), "", deterministic.Program())
+	result, err := e.Run(ctx, &executor.RunCommandRequest{
+		JobID:       "job",
+		ExecutionID: uuid.NewString(),
+		Resources:   &models.Resources{CPU: cpu},
+		ResultsDir:  s.T().TempDir(),
+		EngineParams: &models.SpecConfig{
+			Type:   models.EngineWasm,
+			Params: arguments.ToMap(),
+		},
+		OutputLimits: executor.OutputLimits{
+			MaxStdoutFileLength:   system.MaxStdoutFileLength,
+			MaxStdoutReturnLength: system.MaxStdoutReturnLength,
+			MaxStderrFileLength:   system.MaxStderrFileLength,
+			MaxStderrReturnLength: system.MaxStderrReturnLength,
+		},
+	})
+	s.Require().NoError(err)
+	return result
+}
+
+func (s *ExecutorTestSuite) TestDeterministic() {
+	ctx := context.Background()
+	deterministicArguments := wasmmodels.EngineArguments{EntryPoint: "_start", Deterministic: true, Seed: 42}
+
+	first := s.run(ctx, deterministicArguments, 0)
+	s.Require().Empty(first.ErrorMsg)
+	s.Len(first.STDOUT, 32)
+	s.Equal(first.STDOUT, s.run(ctx, deterministicArguments, 0).STDOUT)
+
+	deterministicArguments.Seed = 43
+	s.NotEqual(first.STDOUT, s.run(ctx, deterministicArguments, 0).STDOUT)
+
+	arguments := wasmmodels.EngineArguments{EntryPoint: "_start"}
+	s.NotEqual(s.run(ctx, arguments, 0).STDOUT, s.run(ctx, arguments, 0).STDOUT)
+}
+
+func (s *ExecutorTestSuite) TestDeterministicWithNetworking() {
+	e, err := NewExecutor()
+	s.Require().NoError(err)
+
+	arguments := wasmmodels.EngineArguments{EntryPoint: "_start", Deterministic: true}
+	arguments.EntryModule = prepareModule(s.T(), "", deterministic.Program())
+	_, err = e.Run(context.Background(), &executor.RunCommandRequest{
+		JobID:        "job",
+		ExecutionID:  uuid.NewString(),
+		Resources:    &models.Resources{},
+		Network:      &models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"example.com"}},
+		ResultsDir:   s.T().TempDir(),
+		EngineParams: &models.SpecConfig{Type: models.EngineWasm, Params: arguments.ToMap()},
+	})
+	s.ErrorContains(err, "can't access the network")
+}
+
+func (s *ExecutorTestSuite) TestCallLimit() {
+	ctx := context.Background()
+
+	result := s.run(ctx, wasmmodels.EngineArguments{EntryPoint: "_start", CallLimit: 100}, 0)
+	s.Empty(result.ErrorMsg)
+
+	result = s.run(ctx, wasmmodels.EngineArguments{EntryPoint: "spin", CallLimit: 100}, 0)
+	s.Contains(result.ErrorMsg, ErrCallLimitExceeded.Error())
+	s.Equal(1, result.ExitCode)
+	// the call that exceeded the limit is counted
+	s.Equal(uint64(101), result.ResourceUsage.FunctionCalls)
+}
+
+func (s *ExecutorTestSuite) TestResourceUsage() {
+	ctx := context.Background()
+	arguments := wasmmodels.EngineArguments{EntryPoint: "_start", Deterministic: true, Seed: 42}
+
+	first := s.run(ctx, arguments, 0)
+	s.Require().Empty(first.ErrorMsg)
+	s.Require().NotNil(first.ResourceUsage)
+	s.Positive(first.ResourceUsage.FunctionCalls)
+	s.Positive(first.ResourceUsage.PeakMemory)
+
+	// function calls are deterministic, unlike CPU time
+	second := s.run(ctx, arguments, 0)
+	s.Equal(first.ResourceUsage.FunctionCalls, second.ResourceUsage.FunctionCalls)
+}
+
+func (s *ExecutorTestSuite) TestScaledTimeout() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result := s.run(ctx, wasmmodels.EngineArguments{EntryPoint: "spin"}, 0.05)
+	s.Contains(result.ErrorMsg, "scaled to its share of 0.05 CPU")
+	s.Equal(1, result.ExitCode)
+	s.NoError(ctx.Err())
+	if runtime.GOOS == "linux" {
+		// the module spun for its whole timeout
+		s.Positive(result.ResourceUsage.CPUSeconds)
+	}
+}
@@ -2,16 +2,20 @@ package wasm
 
 import (
 	"context"
+	crand "crypto/rand"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"math/rand"
+	"runtime"
 	"sort"
 	"time"
 
 	"github.com/dylibso/observe-sdk/go/adapter/opentelemetry"
 	"github.com/rs/zerolog"
 	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/sys"
 	"go.uber.org/atomic"
 	"golang.org/x/exp/maps"
@@ -38,6 +42,10 @@ type executionHandler struct {
 	executionID string
 	resultsDir  string
 	limits      executor.OutputLimits
+	// share of a CPU that the execution requested, which bounds how long it can run before it times out
+	cpu float64
+	// network config of the task, which allows HTTP requests to its domains
+	network *models.NetworkConfig
 
 	// cancellation
 	cancel func()
@@ -88,6 +96,17 @@ func (h *executionHandler) run(ctx context.Context) {
 		h.cancel()
 	}()
 
+	timeout, hasScaledTimeout := scaledTimeout(ctx, h.cpu)
+	if hasScaledTimeout {
+		var cancelTimeout context.CancelFunc
+		wasmCtx, cancelTimeout = context.WithTimeout(wasmCtx, timeout)
+		defer cancelTimeout()
+	}
+
+	// Function calls are counted from compilation, so the modules must be compiled with the counting context.
+	calls := newCallLimiter(h.arguments.CallLimit)
+	loadCtx := calls.limitCalls(ctx)
+
 	var adapter *opentelemetry.OTelAdapter
 	conf := opentelemetry.OTelConfig{
 		ServiceName:        "bacalhau",
@@ -119,10 +138,19 @@ func (h *executionHandler) run(ctx context.Context) {
 		WithStdout(stdout).
 		WithStderr(stderr).
 		WithArgs(args...).
-		WithSysNanosleep().
-		WithSysNanotime().
-		WithSysWalltime().
 		WithFS(h.fs)
+	if h.arguments.Deterministic {
+		// Without system clocks, wazero uses a clock that starts at a fixed time and advances at each reading,
+		// and sleeps return immediately.
+		//nolint:gosec // randomness must be reproducible in deterministic mode
+		config = config.WithRandSource(rand.New(rand.NewSource(h.arguments.Seed)))
+	} else {
+		config = config.
+			WithRandSource(crand.Reader).
+			WithSysNanosleep().
+			WithSysNanotime().
+			WithSysWalltime()
+	}
 	keys := maps.Keys(h.arguments.EnvironmentVariables)
 	sort.Strings(keys)
 	for _, key := range keys {
@@ -132,12 +160,24 @@ func (h *executionHandler) run(ctx context.Context) {
 
 	h.logger.Info().Msg("instantiating wasm modules")
 	loader := NewModuleLoader(tracingEngine, config, h.inputs...)
+	var httpHost *HTTPHost
+	if h.network != nil && !h.network.Disabled() {
+		// requests time out with the task, including its timeout scaled to its CPU share
+		var httpTimeout time.Duration
+		if deadline, ok := wasmCtx.Deadline(); ok {
+			httpTimeout = time.Until(deadline)
+		}
+		httpHost = NewHTTPHost(h.network, httpTimeout, h.logger)
+		defer closer.CloseWithLogOnError("http host", httpHost)
+		loader = loader.WithHTTPHost(httpHost)
+	}
 
 	// TODO we have been ignoring errors from this method for ages. Now that we actually check them tests fail! nice..
 	// v1.0.3: https://github.com/bacalhau-project/bacalhau/blob/v1.0.3/pkg/executor/wasm/executor.go#L243
 	// current: https://github.com/bacalhau-project/bacalhau/blob/ff1bd9cb1c09fa3652c4a68943a97476340dbe33/pkg/executor/wasm/executor.go#L216
+	modules := make([]api.Module, 0, len(h.arguments.ImportModules)+1)
 	for _, importModule := range h.arguments.ImportModules {
-		_, err := loader.InstantiateRemoteModule(ctx, importModule)
+		module, err := loader.InstantiateRemoteModule(loadCtx, importModule)
 		if err != nil {
 			h.logger.Warn().
 				Str("input_source", importModule.InputSource.Source.Type).
@@ -153,11 +193,12 @@ func (h *executionHandler) run(ctx context.Context) {
 					importModule.InputSource.Source.Type, err).Error())
 			return
 		}
+		modules = append(modules, module)
 	}
 
 	// Load and instantiate the entry module.
 	entryModule := h.arguments.EntryModule
-	instance, err := loader.InstantiateRemoteModule(ctx, entryModule)
+	instance, err := loader.InstantiateRemoteModule(loadCtx, entryModule)
 	if err != nil {
 		h.logger.Warn().
 			Str("input_source", entryModule.InputSource.Source.Type).
@@ -181,7 +222,14 @@ func (h *executionHandler) run(ctx context.Context) {
 	// see if the entry point is there and if not we will not attempt to look
 	// for it.
 	definitions := instance.ExportedFunctionDefinitions()
-	_, found := definitions[h.arguments.EntryPoint]
+	entryPoint := h.arguments.EntryPoint
+	_, found := definitions[entryPoint]
+	// components of WASI 0.2 programs can export the run function of wasi:cli/run rather than _start
+	runExport := false
+	if !found && entryPoint == "_start" {
+		entryPoint, runExport = componentEntryPoint(definitions)
+		found = runExport
+	}
 
 	if !found {
 		h.result = executor.NewFailedResult(
@@ -190,7 +238,8 @@ func (h *executionHandler) run(ctx context.Context) {
 		return
 	}
 
-	entryFunc := instance.ExportedFunction(h.arguments.EntryPoint)
+	modules = append(modules, instance)
+	entryFunc := instance.ExportedFunction(entryPoint)
 	h.logger.Info().Msg("running execution")
 
 	// TODO(forrest): this is a bit of a race condition as the operation has not started when these lines are called.
@@ -200,15 +249,25 @@ func (h *executionHandler) run(ctx context.Context) {
 	// The function should exit which results in a sys.ExitError. So we capture
 	// the exit code for inclusion in the job output, and ignore the return code
 	// from the function (most WASI compilers will not give one). Some compilers
-	// though do not set an exit code, so we use a default of -1.
-	_, wasmErr := entryFunc.Call(wasmCtx)
+	// though do not set an exit code, so we use a default of -1. The run function
+	// of wasi:cli/run returns a result, which is an error if it is not 0.
+	results, cpuTime, wasmErr := callOnThread(wasmCtx, entryFunc)
+	usage := moduleUsage(cpuTime, calls.count(), modules)
 	exitCode := int64(-1)
+	if runExport && wasmErr == nil {
+		exitCode = int64(min(results[0], 1))
+	}
 	var errExit *sys.ExitError
 	if errors.As(wasmErr, &errExit) {
 		exitCode = int64(errExit.ExitCode())
 		wasmErr = nil
 		h.logger.Info().Int64("exit_code", exitCode).Msg("execution ended")
 	}
+	if hasScaledTimeout && errors.Is(wasmCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		wasmErr = fmt.Errorf("execution exceeded its timeout of %s, scaled to its share of %g CPU", timeout, h.cpu)
+	} else if errors.Is(wasmErr, ErrCallLimitExceeded) {
+		wasmErr = fmt.Errorf("%w of %d", ErrCallLimitExceeded, h.arguments.CallLimit)
+	}
 	if wasmErr != nil {
 		// in the event that an error is returned without an exist code we'll assume the operation
 		// failed and set the exit code to 1
@@ -222,6 +281,50 @@ func (h *executionHandler) run(ctx context.Context) {
 	stdoutReader, stderrReader := h.logManager.GetDefaultReaders(false)
 
 	h.result = executor.WriteJobResults(h.resultsDir, stdoutReader, stderrReader, int(exitCode), wasmErr, h.limits)
+	h.result.ResourceUsage = usage
+	if httpHost != nil {
+		h.result.Egress = httpHost.Egress()
+	}
+}
+
+// callOnThread calls the function with the goroutine locked to its thread, and returns its results and the CPU
+// time the thread used. Modules run on the thread that calls them, so unlike the wall clock time, the CPU time of
+// the thread doesn't include the time spent waiting for other goroutines and processes. It is zero where it isn't measured.
+func callOnThread(ctx context.Context, function api.Function) ([]uint64, time.Duration, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	start, measured := threadCPUTime()
+	results, err := function.Call(ctx)
+	end, _ := threadCPUTime()
+	if !measured {
+		return results, 0, err
+	}
+	return results, end - start, err
+}
+
+// moduleUsage returns the resources used by modules that ran for the given CPU time and made the given number
+// of function calls. Their linear memory can only grow, so its current size is its peak.
+func moduleUsage(cpuTime time.Duration, calls uint64, modules []api.Module) *models.ResourceUsage {
+	usage := &models.ResourceUsage{CPUSeconds: cpuTime.Seconds(), FunctionCalls: calls}
+	for _, module := range modules {
+		// Memory() returns a typed nil for modules without memory, so only exported memories are measured.
+		// Modules have at most one memory, which can be exported under several names.
+		if names := maps.Keys(module.ExportedMemoryDefinitions()); len(names) > 0 {
+			usage.PeakMemory += uint64(module.ExportedMemory(names[0]).Size())
+		}
+	}
+	return usage
+}
+
+// scaledTimeout returns the remaining timeout of ctx scaled to the given share of a CPU. It is not CPU accounting:
+// modules are stopped after running for the scaled wall clock time, whatever CPU time they actually used.
+// Modules run on a single thread, so only executions that requested less than a CPU have a shorter timeout.
+func scaledTimeout(ctx context.Context, cpu float64) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok || cpu <= 0 || cpu >= 1 {
+		return 0, false
+	}
+	return time.Duration(float64(time.Until(deadline)) * cpu), true
 }
 
 func (h *executionHandler) active() bool {
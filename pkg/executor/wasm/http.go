@@ -0,0 +1,333 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// HTTPModuleName is the name of the host module that lets WASM modules make outbound HTTP requests. It implements
+// the wasi_experimental_http ABI, for which there are client libraries such as the wasi-experimental-http crate.
+const HTTPModuleName = "wasi_experimental_http"
+
+// maxHTTPResponses is the maximum number of responses a module can keep open at the same time.
+const maxHTTPResponses = 16
+
+// httpBodyChunkSize is the size of the chunks the body of a response is read in, so that the memory used by the host
+// doesn't depend on the size of the buffers of the module.
+const httpBodyChunkSize = 64 * 1024
+
+// DefaultHTTPTimeout is the timeout of requests of tasks without an execution timeout.
+const DefaultHTTPTimeout = 5 * time.Minute
+
+// httpErrno are the error codes returned by the functions of the HTTP host module. They are the codes of the ABI,
+// some of which this implementation never returns.
+type httpErrno = uint32
+
+//nolint:unused
+const (
+	httpSuccess httpErrno = iota
+	httpInvalidHandle
+	httpMemoryNotFound
+	httpMemoryAccessError
+	httpBufferTooSmall
+	httpHeaderNotFound
+	httpUtf8Error
+	httpDestinationNotAllowed
+	httpInvalidMethod
+	httpInvalidEncoding
+	httpInvalidURL
+	httpRequestError
+	httpRuntimeError
+	httpTooManySessions
+)
+
+var errDestinationNotAllowed = errors.New("destination not allowed by the network config of the task")
+
+// HTTPHost implements the HTTP host module, allowing requests only to the hosts allowed by the network config
+// of the task, including when following redirects. It also implements the wasi:http interfaces for the core
+// modules of components, with the same network config and limits.
+type HTTPHost struct {
+	network *models.NetworkConfig
+	client  *http.Client
+	logger  zerolog.Logger
+	wasi    *wasiHTTP
+
+	mtx       sync.Mutex
+	responses map[uint32]*http.Response
+	// pending is the number of requests being sent, which count towards the open responses
+	pending int
+	next    uint32
+	egress  models.EgressLog
+}
+
+// NewHTTPHost creates an HTTP host for the network config of a task. Requests time out after the given timeout,
+// which should be the remaining execution timeout of the task, or DefaultHTTPTimeout if it is not positive.
+func NewHTTPHost(network *models.NetworkConfig, timeout time.Duration, logger zerolog.Logger) *HTTPHost {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	host := &HTTPHost{
+		network:   network,
+		logger:    logger,
+		responses: make(map[uint32]*http.Response),
+	}
+	host.client = &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return host.checkDestination(req.URL)
+		},
+	}
+	host.wasi = newWASIHTTP(host)
+	return host
+}
+
+// Builder returns a builder of the HTTP host module for the runtime.
+func (h *HTTPHost) Builder(runtime wazero.Runtime) wazero.HostModuleBuilder {
+	return runtime.NewHostModuleBuilder(HTTPModuleName).
+		NewFunctionBuilder().WithFunc(h.req).Export("req").
+		NewFunctionBuilder().WithFunc(h.close).Export("close").
+		NewFunctionBuilder().WithFunc(h.headerGet).Export("header_get").
+		NewFunctionBuilder().WithFunc(h.headersGetAll).Export("headers_get_all").
+		NewFunctionBuilder().WithFunc(h.bodyRead).Export("body_read")
+}
+
+// wasiBuilder returns a builder of the host module of a WASI 0.2 interface, such as wasi:http/types@0.2.0.
+func (h *HTTPHost) wasiBuilder(runtime wazero.Runtime, moduleName string) (wazero.HostModuleBuilder, error) {
+	return h.wasi.builder(runtime, moduleName)
+}
+
+// Egress returns a copy of the log of the requests the modules made.
+func (h *HTTPHost) Egress() *models.EgressLog {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.egress.Copy()
+}
+
+// Close closes the responses that the modules left open.
+func (h *HTTPHost) Close() error {
+	err := h.wasi.Close()
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for handle, response := range h.responses {
+		err = errors.Join(err, response.Body.Close())
+		delete(h.responses, handle)
+	}
+	return err
+}
+
+func (h *HTTPHost) checkDestination(u *url.URL) error {
+	allowed := h.network != nil && h.network.AllowsHost(u.Hostname())
+	h.mtx.Lock()
+	h.egress.Record(u.Hostname(), allowed)
+	h.mtx.Unlock()
+	if !allowed {
+		h.logger.Info().Str("host", u.Hostname()).Msg("denied outbound HTTP request")
+		return fmt.Errorf("%w: %s", errDestinationNotAllowed, u.Hostname())
+	}
+	return nil
+}
+
+// acquire takes a slot of the open responses, unless the modules already have as many open as they can.
+func (h *HTTPHost) acquire() bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if len(h.responses)+h.pending >= maxHTTPResponses {
+		return false
+	}
+	h.pending++
+	return true
+}
+
+func (h *HTTPHost) release() {
+	h.mtx.Lock()
+	h.pending--
+	h.mtx.Unlock()
+}
+
+//nolint:funlen
+func (h *HTTPHost) req(
+	ctx context.Context, mod api.Module,
+	urlPtr, urlLen, methodPtr, methodLen, headersPtr, headersLen, bodyPtr, bodyLen, statusPtr, handlePtr uint32,
+) httpErrno {
+	memory := mod.Memory()
+	rawURL, urlOK := memory.Read(urlPtr, urlLen)
+	method, methodOK := memory.Read(methodPtr, methodLen)
+	headers, headersOK := memory.Read(headersPtr, headersLen)
+	body, bodyOK := memory.Read(bodyPtr, bodyLen)
+	if !urlOK || !methodOK || !headersOK || !bodyOK {
+		return httpMemoryAccessError
+	}
+
+	u, err := url.Parse(string(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return httpInvalidURL
+	}
+	if err = h.checkDestination(u); err != nil {
+		return httpDestinationNotAllowed
+	}
+
+	// the body is copied as the memory of the module can change while the request is sent
+	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(string(method)), u.String(), bytes.NewReader(bytes.Clone(body)))
+	if err != nil {
+		return httpInvalidMethod
+	}
+	for _, line := range strings.Split(string(headers), "\n") {
+		if line == "" {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return httpInvalidEncoding
+		}
+		request.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	// the request takes a slot of the open responses until it completes, so concurrent requests can't exceed the limit
+	if !h.acquire() {
+		return httpTooManySessions
+	}
+
+	response, err := h.client.Do(request)
+	h.mtx.Lock()
+	h.pending--
+	if err == nil {
+		h.next++
+		h.responses[h.next] = response
+	}
+	handle := h.next
+	h.mtx.Unlock()
+	if err != nil {
+		if errors.Is(err, errDestinationNotAllowed) {
+			return httpDestinationNotAllowed
+		}
+		h.logger.Debug().Err(err).Str("host", u.Hostname()).Msg("outbound HTTP request failed")
+		return httpRequestError
+	}
+	h.logger.Debug().Str("host", u.Hostname()).Int("status", response.StatusCode).Msg("sent outbound HTTP request")
+
+	if !memory.WriteUint32Le(handlePtr, handle) || !memory.WriteUint16Le(statusPtr, uint16(response.StatusCode)) {
+		return httpMemoryAccessError
+	}
+	return httpSuccess
+}
+
+func (h *HTTPHost) close(_ context.Context, handle uint32) httpErrno {
+	h.mtx.Lock()
+	response, found := h.responses[handle]
+	delete(h.responses, handle)
+	h.mtx.Unlock()
+	if !found {
+		return httpInvalidHandle
+	}
+	_ = response.Body.Close()
+	return httpSuccess
+}
+
+func (h *HTTPHost) response(handle uint32) (*http.Response, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	response, found := h.responses[handle]
+	return response, found
+}
+
+func (h *HTTPHost) headerGet(
+	_ context.Context, mod api.Module, handle, namePtr, nameLen, valuePtr, valueLen, writtenPtr uint32,
+) httpErrno {
+	response, found := h.response(handle)
+	if !found {
+		return httpInvalidHandle
+	}
+	memory := mod.Memory()
+	name, ok := memory.Read(namePtr, nameLen)
+	if !ok {
+		return httpMemoryAccessError
+	}
+	values := response.Header.Values(string(name))
+	if len(values) == 0 {
+		return httpHeaderNotFound
+	}
+	return writeBuffer(memory, []byte(values[0]), valuePtr, valueLen, writtenPtr)
+}
+
+func (h *HTTPHost) headersGetAll(_ context.Context, mod api.Module, handle, bufPtr, bufLen, writtenPtr uint32) httpErrno {
+	response, found := h.response(handle)
+	if !found {
+		return httpInvalidHandle
+	}
+	memory := mod.Memory()
+
+	// headers are encoded as one "name:value" line per value, in a consistent order
+	names := make([]string, 0, len(response.Header))
+	for name := range response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var headers strings.Builder
+	for _, name := range names {
+		for _, value := range response.Header.Values(name) {
+			headers.WriteString(name + ":" + value + "\n")
+		}
+	}
+	return writeBuffer(memory, []byte(headers.String()), bufPtr, bufLen, writtenPtr)
+}
+
+func (h *HTTPHost) bodyRead(_ context.Context, mod api.Module, handle, bufPtr, bufLen, readPtr uint32) httpErrno {
+	response, found := h.response(handle)
+	if !found {
+		return httpInvalidHandle
+	}
+	memory := mod.Memory()
+	if uint64(bufPtr)+uint64(bufLen) > uint64(memory.Size()) {
+		return httpMemoryAccessError
+	}
+
+	// the body is read in chunks into the buffer of the module until it is full or the body is read entirely
+	chunk := make([]byte, min(bufLen, httpBodyChunkSize))
+	var read uint32
+	for read < bufLen {
+		n, err := io.ReadFull(response.Body, chunk[:min(bufLen-read, uint32(len(chunk)))])
+		if !memory.Write(bufPtr+read, chunk[:n]) {
+			return httpMemoryAccessError
+		}
+		read += uint32(n)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			h.logger.Debug().Err(err).Msg("failed to read outbound HTTP response")
+			return httpRuntimeError
+		}
+	}
+	if !memory.WriteUint32Le(readPtr, read) {
+		return httpMemoryAccessError
+	}
+	return httpSuccess
+}
+
+// writeBuffer writes data to the buffer of the module if it fits, and the number of bytes written
+func writeBuffer(memory api.Memory, data []byte, bufPtr, bufLen, writtenPtr uint32) httpErrno {
+	if uint32(len(data)) > bufLen {
+		return httpBufferTooSmall
+	}
+	if !memory.Write(bufPtr, data) || !memory.WriteUint32Le(writtenPtr, uint32(len(data))) {
+		return httpMemoryAccessError
+	}
+	return httpSuccess
+}
@@ -0,0 +1,269 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type HTTPHostTestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	runtime wazero.Runtime
+	module  api.Module
+}
+
+func TestHTTPHostTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPHostTestSuite))
+}
+
+// wasmBinary returns a module made of the given sections.
+func wasmBinary(sections ...[]byte) []byte {
+	return append([]byte("\x00asm\x01\x00\x00\x00"), concat(sections...)...)
+}
+
+func wasmSection(id byte, content ...[]byte) []byte {
+	body := concat(content...)
+	return concat([]byte{id}, uleb(uint32(len(body))), body)
+}
+
+// uleb encodes an unsigned LEB128 number.
+func uleb(value uint32) []byte {
+	var out []byte
+	for value >= 0x80 {
+		out = append(out, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(out, byte(value))
+}
+
+func wasmName(name string) []byte {
+	return append([]byte{byte(len(name))}, name...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+// memoryModule exports a memory of one page.
+var memoryModule = wasmBinary(
+	wasmSection(5, []byte{1, 0, 1}),
+	wasmSection(7, []byte{1}, wasmName("memory"), []byte{2, 0}),
+)
+
+// httpModule imports the close function of the HTTP module.
+var httpModule = wasmBinary(
+	wasmSection(1, []byte{1, 0x60, 1, 0x7f, 1, 0x7f}),
+	wasmSection(2, []byte{1}, wasmName(HTTPModuleName), wasmName("close"), []byte{0, 0}),
+)
+
+func (s *HTTPHostTestSuite) SetupTest() {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "http://localhost/", http.StatusFound)
+			return
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 3*httpBodyChunkSize)))
+			return
+		case "/slow":
+			time.Sleep(time.Second)
+		case "/echo":
+			_, _ = io.Copy(w, r.Body)
+			return
+		}
+		w.Header().Set("X-Test", r.Header.Get("X-Test")+r.Method)
+		_, _ = w.Write([]byte("hello"))
+	}))
+	s.runtime = wazero.NewRuntime(context.Background())
+
+	var err error
+	s.module, err = s.runtime.Instantiate(context.Background(), memoryModule)
+	s.Require().NoError(err)
+}
+
+func (s *HTTPHostTestSuite) TearDownTest() {
+	s.server.Close()
+	s.Require().NoError(s.runtime.Close(context.Background()))
+}
+
+func (s *HTTPHostTestSuite) host(domains ...string) *HTTPHost {
+	return NewHTTPHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: domains}, time.Minute, log.Logger)
+}
+
+// write writes the value at the offset of the memory and returns its pointer and length
+func (s *HTTPHostTestSuite) write(offset uint32, value string) (uint32, uint32) {
+	s.Require().True(s.module.Memory().Write(offset, []byte(value)))
+	return offset, uint32(len(value))
+}
+
+// req sends a request through the host and returns its error code, status code and response handle
+func (s *HTTPHostTestSuite) req(host *HTTPHost, url, method, headers string) (httpErrno, uint16, uint32) {
+	urlPtr, urlLen := s.write(0, url)
+	methodPtr, methodLen := s.write(1024, method)
+	headersPtr, headersLen := s.write(2048, headers)
+	errno := host.req(context.Background(), s.module,
+		urlPtr, urlLen, methodPtr, methodLen, headersPtr, headersLen, 3072, 0, 4000, 4004)
+	status, _ := s.module.Memory().ReadUint16Le(4000)
+	handle, _ := s.module.Memory().ReadUint32Le(4004)
+	return errno, status, handle
+}
+
+func (s *HTTPHostTestSuite) read(ptr, writtenPtr uint32) string {
+	written, ok := s.module.Memory().ReadUint32Le(writtenPtr)
+	s.Require().True(ok)
+	value, ok := s.module.Memory().Read(ptr, written)
+	s.Require().True(ok)
+	return string(value)
+}
+
+func (s *HTTPHostTestSuite) TestAllowed() {
+	host := s.host("127.0.0.1")
+	errno, status, handle := s.req(host, s.server.URL, "post", "X-Test: value\n")
+	s.Require().Equal(httpSuccess, errno)
+	s.Equal(uint16(http.StatusOK), status)
+
+	namePtr, nameLen := s.write(5000, "x-test")
+	s.Equal(httpSuccess, host.headerGet(context.Background(), s.module, handle, namePtr, nameLen, 6000, 100, 6100))
+	s.Equal("valuePOST", s.read(6000, 6100))
+	s.Equal(httpBufferTooSmall, host.headerGet(context.Background(), s.module, handle, namePtr, nameLen, 6000, 2, 6100))
+	missingPtr, missingLen := s.write(5000, "missing")
+	s.Equal(httpHeaderNotFound, host.headerGet(context.Background(), s.module, handle, missingPtr, missingLen, 6000, 100, 6100))
+
+	s.Equal(httpSuccess, host.headersGetAll(context.Background(), s.module, handle, 6000, 1000, 7000))
+	s.Contains(s.read(6000, 7000), "X-Test:valuePOST\n")
+
+	s.Equal(httpSuccess, host.bodyRead(context.Background(), s.module, handle, 8000, 3, 9000))
+	s.Equal("hel", s.read(8000, 9000))
+	s.Equal(httpSuccess, host.bodyRead(context.Background(), s.module, handle, 8000, 100, 9000))
+	s.Equal("lo", s.read(8000, 9000))
+	s.Equal(httpSuccess, host.bodyRead(context.Background(), s.module, handle, 8000, 100, 9000))
+	s.Equal("", s.read(8000, 9000))
+
+	s.Equal(httpSuccess, host.close(context.Background(), handle))
+	s.Equal(httpInvalidHandle, host.close(context.Background(), handle))
+	s.Equal(httpInvalidHandle, host.bodyRead(context.Background(), s.module, handle, 8000, 100, 9000))
+	s.Equal(&models.EgressLog{Allowed: 1}, host.Egress())
+}
+
+func (s *HTTPHostTestSuite) TestBodyRead() {
+	host := s.host("127.0.0.1")
+	errno, _, handle := s.req(host, s.server.URL+"/large", "GET", "")
+	s.Require().Equal(httpSuccess, errno)
+
+	pageSize := s.module.Memory().Size()
+	s.Equal(httpMemoryAccessError, host.bodyRead(context.Background(), s.module, handle, 8000, pageSize, 9000),
+		"buffers beyond the memory of the module are rejected before reading")
+	s.Equal(httpMemoryAccessError, host.bodyRead(context.Background(), s.module, handle, 8000, ^uint32(0), 9000))
+
+	var body strings.Builder
+	for {
+		s.Require().Equal(httpSuccess, host.bodyRead(context.Background(), s.module, handle, 10000, pageSize-10000, 9000))
+		read := s.read(10000, 9000)
+		if read == "" {
+			break
+		}
+		body.WriteString(read)
+	}
+	s.Equal(strings.Repeat("a", 3*httpBodyChunkSize), body.String())
+}
+
+func (s *HTTPHostTestSuite) TestTimeout() {
+	host := NewHTTPHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1"}},
+		100*time.Millisecond, log.Logger)
+	errno, _, _ := s.req(host, s.server.URL+"/slow", "GET", "")
+	s.Equal(httpRequestError, errno)
+}
+
+func (s *HTTPHostTestSuite) TestDenied() {
+	for name, tc := range map[string]struct {
+		host  *HTTPHost
+		url   string
+		errno httpErrno
+	}{
+		"domain not allowed":          {s.host("example.com"), s.server.URL, httpDestinationNotAllowed},
+		"redirect to a denied domain": {s.host("127.0.0.1"), s.server.URL + "/redirect", httpDestinationNotAllowed},
+		"no network":                  {NewHTTPHost(nil, 0, log.Logger), s.server.URL, httpDestinationNotAllowed},
+		"not http":                    {s.host("127.0.0.1"), "file:///etc/passwd", httpInvalidURL},
+	} {
+		s.Run(name, func() {
+			errno, _, _ := s.req(tc.host, tc.url, "GET", "")
+			s.Equal(tc.errno, errno)
+			if tc.errno == httpDestinationNotAllowed {
+				s.Equal(uint64(1), tc.host.Egress().Denied)
+			}
+		})
+	}
+
+	errno, _, _ := s.req(s.host("127.0.0.1"), s.server.URL, "GET", "no separator")
+	s.Equal(httpInvalidEncoding, errno)
+}
+
+func (s *HTTPHostTestSuite) TestClose() {
+	host := s.host("127.0.0.1")
+	for i := 0; i < maxHTTPResponses; i++ {
+		errno, _, _ := s.req(host, s.server.URL, "GET", "")
+		s.Require().Equal(httpSuccess, errno)
+	}
+	errno, _, _ := s.req(host, s.server.URL, "GET", "")
+	s.Equal(httpTooManySessions, errno)
+
+	s.Require().NoError(host.Close())
+	errno, _, _ = s.req(host, s.server.URL, "GET", "")
+	s.Equal(httpSuccess, errno)
+}
+
+func (s *HTTPHostTestSuite) TestConcurrentRequests() {
+	host := s.host("127.0.0.1")
+	urlPtr, urlLen := s.write(0, s.server.URL+"/slow")
+	methodPtr, methodLen := s.write(1024, "GET")
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	counts := make(map[httpErrno]int)
+	for i := 0; i < 2*maxHTTPResponses; i++ {
+		wg.Add(1)
+		go func(i uint32) {
+			defer wg.Done()
+			errno := host.req(context.Background(), s.module,
+				urlPtr, urlLen, methodPtr, methodLen, 2048, 0, 3072, 0, 4000+8*i, 4004+8*i)
+			mtx.Lock()
+			counts[errno]++
+			mtx.Unlock()
+		}(uint32(i))
+	}
+	wg.Wait()
+	s.Equal(map[httpErrno]int{httpSuccess: maxHTTPResponses, httpTooManySessions: maxHTTPResponses}, counts)
+}
+
+func (s *HTTPHostTestSuite) TestLoader() {
+	entryModule := prepareModule(s.T(), "", httpModule)
+
+	_, err := NewModuleLoader(s.runtime, wazero.NewModuleConfig(), entryModule).
+		InstantiateRemoteModule(context.Background(), entryModule)
+	s.ErrorContains(err, "only available to jobs with HTTP networking")
+
+	module, err := NewModuleLoader(s.runtime, wazero.NewModuleConfig(), entryModule).
+		WithHTTPHost(s.host("127.0.0.1")).
+		InstantiateRemoteModule(context.Background(), entryModule)
+	s.Require().NoError(err)
+	s.NotNil(module)
+}
@@ -34,6 +34,8 @@ type ModuleLoader struct {
 	runtime  wazero.Runtime
 	config   wazero.ModuleConfig
 	storages []storage.PreparedStorage
+	// host of the HTTP module, which is only available to modules with networking
+	http *HTTPHost
 
 	// Runtime will throw an error if the same module is instantiated more than
 	// once. So we use this mutex around checking for modules and instantiating
@@ -44,19 +46,29 @@ func NewModuleLoader(runtime wazero.Runtime, config wazero.ModuleConfig, storage
 	return &ModuleLoader{runtime: runtime, config: config, storages: storages}
 }
 
-// Load compiles and returns a module located at the passed path.
+// WithHTTPHost makes the HTTP module available to the loaded modules.
+func (loader *ModuleLoader) WithHTTPHost(host *HTTPHost) *ModuleLoader {
+	loader.http = host
+	return loader
+}
+
+// Load compiles and returns a module located at the passed path. If the file is a WebAssembly component, such
+// as a WASI 0.2 program, the core module of the program it embeds is returned.
 func (loader *ModuleLoader) Load(ctx context.Context, path string) (wazero.CompiledModule, error) {
 	ctx, span := system.NewSpan(ctx, system.GetTracer(), "pkg/executor/wasm.ModuleLoader.Load")
 	span.SetAttributes(attribute.String("Path", path))
 	defer span.End()
 
 	log.Ctx(ctx).Debug().Str("Path", path).Msg("Loading WASM module")
-	bytes, err := os.ReadFile(path)
+	binary, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
+	if isComponent(binary) {
+		return loader.loadComponent(ctx, binary)
+	}
 
-	module, err := loader.runtime.CompileModule(ctx, bytes)
+	module, err := loader.runtime.CompileModule(ctx, binary)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +179,30 @@ func (loader *ModuleLoader) loadModuleByName(ctx context.Context, moduleName str
 			return loader.runtime.Module(moduleName), err
 		}
 
+		if isWASIInterface(moduleName) {
+			if !supportedWASIInterface(moduleName) {
+				return nil, fmt.Errorf("WASI interface %q is not supported, components can only import "+
+					"the wasi:http and wasi:io 0.2 interfaces besides WASI preview 1", moduleName)
+			}
+			if loader.http == nil {
+				return nil, fmt.Errorf("%q is only available to jobs with HTTP networking", moduleName)
+			}
+			builder, err := loader.http.wasiBuilder(loader.runtime, moduleName)
+			if err != nil {
+				return nil, err
+			}
+			_, err = builder.Instantiate(ctx)
+			return loader.runtime.Module(moduleName), err
+		}
+
+		if moduleName == HTTPModuleName {
+			if loader.http == nil {
+				return nil, fmt.Errorf("%q is only available to jobs with HTTP networking", HTTPModuleName)
+			}
+			_, err := loader.http.Builder(loader.runtime).Instantiate(ctx)
+			return loader.runtime.Module(moduleName), err
+		}
+
 		return nil, nil
 	}(); module != nil || err != nil {
 		return module, err
@@ -31,6 +31,18 @@ type EngineSpec struct {
 	// ImportModules is a slice of StorageSpec's containing WASM modules whose exports will be available as imports
 	// to the EntryModule.
 	ImportModules []*models.InputSource `json:"ImportModules,omitempty"`
+
+	// CallLimit is the maximum number of function calls the program can make, including calls to WASI functions,
+	// after which it fails. It is not instruction metering, but unlike the timeout of the task, it is deterministic.
+	// Zero means no limit.
+	CallLimit uint64 `json:"CallLimit,omitempty"`
+
+	// Deterministic runs the program with a fixed clock, randomness seeded with Seed and sleeps that return
+	// immediately, so that running it with the same inputs gives the same outputs.
+	Deterministic bool `json:"Deterministic,omitempty"`
+
+	// Seed is the seed of the randomness available to the program in deterministic mode.
+	Seed int64 `json:"Seed,omitempty"`
 }
 
 func (c EngineSpec) Validate() error {
@@ -51,6 +63,9 @@ func (c EngineSpec) ToArguments(entryModule storage.PreparedStorage, importModul
 		Parameters:           c.Parameters,
 		EnvironmentVariables: c.EnvironmentVariables,
 		ImportModules:        importModules,
+		CallLimit:            c.CallLimit,
+		Deterministic:        c.Deterministic,
+		Seed:                 c.Seed,
 	}
 }
 
@@ -149,6 +164,9 @@ type EngineArguments struct {
 	EnvironmentVariables map[string]string
 	EntryModule          storage.PreparedStorage
 	ImportModules        []storage.PreparedStorage
+	CallLimit            uint64
+	Deterministic        bool
+	Seed                 int64
 }
 
 func (c EngineArguments) Validate() error {
@@ -0,0 +1,1190 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// wasiInterfaces are the WASI 0.2 interfaces available to modules with HTTP networking, which are the
+// wasi:http interfaces to send requests and the wasi:io interfaces they use.
+var wasiInterfaces = []string{
+	"wasi:http/types",
+	"wasi:http/outgoing-handler",
+	"wasi:io/error",
+	"wasi:io/poll",
+	"wasi:io/streams",
+}
+
+// isWASIInterface returns whether the module name is the name of a WASI 0.2 interface, such as
+// wasi:http/types@0.2.0, which core modules of components import.
+func isWASIInterface(moduleName string) bool {
+	return strings.HasPrefix(moduleName, "wasi:")
+}
+
+// supportedWASIInterface returns whether the module name is one of wasiInterfaces, at any 0.2 version.
+func supportedWASIInterface(moduleName string) bool {
+	name, version, _ := strings.Cut(moduleName, "@")
+	return slices.Contains(wasiInterfaces, name) && strings.HasPrefix(version, "0.2.")
+}
+
+// wasiBodyBufferSize is the size of the body of a request the host buffers before writes of the module block.
+const wasiBodyBufferSize = httpBodyChunkSize
+
+// codes of the error-code variant of wasi:http/types that the host returns
+const (
+	errorCodeDNSTimeout             = 0
+	errorCodeDNSError               = 1
+	errorCodeConnectionRefused      = 6
+	errorCodeConnectionTimeout      = 8
+	errorCodeConnectionLimitReached = 11
+	errorCodeHTTPRequestDenied      = 15
+	errorCodeHTTPRequestMethod      = 18
+	errorCodeHTTPRequestURIInvalid  = 19
+	errorCodeHTTPResponseTimeout    = 33
+	errorCodeInternalError          = 38
+)
+
+// codes of the header-error variant of wasi:http/types
+const (
+	headerErrorInvalidSyntax = 0
+	headerErrorForbidden     = 1
+	headerErrorImmutable     = 2
+)
+
+// forbiddenHeaders are the headers that modules can't set, as they are managed by the host.
+var forbiddenHeaders = []string{
+	"connection", "host", "http2-settings", "keep-alive", "proxy-connection", "te", "transfer-encoding", "upgrade",
+}
+
+var (
+	methods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete,
+		http.MethodConnect, http.MethodOptions, http.MethodTrace, http.MethodPatch,
+	}
+	schemes = []string{"http", "https"}
+)
+
+var errResponseTimeout = errors.New("timed out waiting for the response")
+
+// wasiHTTP implements the wasi:http interfaces to send requests, which are subject to the same network config,
+// limits and timeout as requests of the HTTP host module. Redirects are returned to the module rather than
+// followed, as wasi:http expects.
+type wasiHTTP struct {
+	wasiIO
+	host   *HTTPHost
+	client *http.Client
+
+	mtx       sync.Mutex
+	exchanges map[*wasiExchange]struct{}
+}
+
+func newWASIHTTP(host *HTTPHost) *wasiHTTP {
+	return &wasiHTTP{
+		wasiIO: wasiIO{table: &wasiTable{}, notifier: &wasiNotifier{}},
+		host:   host,
+		client: &http.Client{
+			Timeout: host.client.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		exchanges: make(map[*wasiExchange]struct{}),
+	}
+}
+
+// builder returns a builder of the host module of the WASI 0.2 interface imported as moduleName.
+func (w *wasiHTTP) builder(runtime wazero.Runtime, moduleName string) (wazero.HostModuleBuilder, error) {
+	name, _, _ := strings.Cut(moduleName, "@")
+	functions, found := w.interfaces()[name]
+	if !found || !supportedWASIInterface(moduleName) {
+		return nil, fmt.Errorf("WASI interface %q is not supported", moduleName)
+	}
+	builder := runtime.NewHostModuleBuilder(moduleName)
+	for functionName, function := range functions {
+		builder = builder.NewFunctionBuilder().
+			WithGoModuleFunction(function.fn, function.params, function.results).
+			Export(functionName)
+	}
+	return builder, nil
+}
+
+//nolint:funlen
+func (w *wasiHTTP) interfaces() map[string]map[string]wasiFunc {
+	interfaces := w.wasiIO.interfaces()
+	interfaces["wasi:http/types"] = map[string]wasiFunc{
+		"[constructor]fields":             {results: params(i32), fn: w.newFields},
+		"[static]fields.from-list":        {params: params(i32, i32, i32), fn: w.fieldsFromList},
+		"[method]fields.get":              {params: params(i32, i32, i32, i32), fn: w.fieldsGet},
+		"[method]fields.has":              {params: params(i32, i32, i32), results: params(i32), fn: w.fieldsHas},
+		"[method]fields.set":              {params: params(i32, i32, i32, i32, i32, i32), fn: w.fieldsSet},
+		"[method]fields.delete":           {params: params(i32, i32, i32, i32), fn: w.fieldsDelete},
+		"[method]fields.append":           {params: params(i32, i32, i32, i32, i32, i32), fn: w.fieldsAppend},
+		"[method]fields.entries":          {params: params(i32, i32), fn: w.fieldsEntries},
+		"[method]fields.clone":            {params: params(i32), results: params(i32), fn: w.fieldsClone},
+		"[resource-drop]fields":           w.table.dropResource(),
+		"[constructor]outgoing-request":   {params: params(i32), results: params(i32), fn: w.newOutgoingRequest},
+		"[method]outgoing-request.body":   {params: params(i32, i32), fn: w.outgoingRequestBody},
+		"[method]outgoing-request.method": {params: params(i32, i32), fn: w.requestMethod},
+		"[method]outgoing-request.set-method": {
+			params: params(i32, i32, i32, i32), results: params(i32), fn: w.requestSetMethod,
+		},
+		"[method]outgoing-request.path-with-query": {params: params(i32, i32), fn: w.requestOption(pathWithQuery)},
+		"[method]outgoing-request.set-path-with-query": {
+			params: params(i32, i32, i32, i32), results: params(i32), fn: w.requestSetOption(pathWithQuery),
+		},
+		"[method]outgoing-request.scheme": {params: params(i32, i32), fn: w.requestScheme},
+		"[method]outgoing-request.set-scheme": {
+			params: params(i32, i32, i32, i32, i32), results: params(i32), fn: w.requestSetScheme,
+		},
+		"[method]outgoing-request.authority": {params: params(i32, i32), fn: w.requestOption(authority)},
+		"[method]outgoing-request.set-authority": {
+			params: params(i32, i32, i32, i32), results: params(i32), fn: w.requestSetOption(authority),
+		},
+		"[method]outgoing-request.headers":               {params: params(i32), results: params(i32), fn: w.requestHeaders},
+		"[resource-drop]outgoing-request":                w.table.dropResource(),
+		"[constructor]request-options":                   {results: params(i32), fn: w.newRequestOptions},
+		"[method]request-options.connect-timeout":        {params: params(i32, i32), fn: w.timeout(connectTimeout)},
+		"[method]request-options.set-connect-timeout":    w.setTimeout(connectTimeout),
+		"[method]request-options.first-byte-timeout":     {params: params(i32, i32), fn: w.timeout(firstByteTimeout)},
+		"[method]request-options.set-first-byte-timeout": w.setTimeout(firstByteTimeout),
+		"[method]request-options.between-bytes-timeout": {
+			params: params(i32, i32), fn: w.timeout(betweenBytesTimeout),
+		},
+		"[method]request-options.set-between-bytes-timeout": w.setTimeout(betweenBytesTimeout),
+		"[resource-drop]request-options":                    w.table.dropResource(),
+		"[method]outgoing-body.write":                       {params: params(i32, i32), fn: w.outgoingBodyWrite},
+		"[static]outgoing-body.finish":                      {params: params(i32, i32, i32, i32), fn: w.outgoingBodyFinish},
+		"[resource-drop]outgoing-body":                      w.table.dropResource(),
+		"[method]future-incoming-response.subscribe": {
+			params: params(i32), results: params(i32), fn: w.futureResponseSubscribe,
+		},
+		"[method]future-incoming-response.get":    {params: params(i32, i32), fn: w.futureResponseGet},
+		"[resource-drop]future-incoming-response": w.table.dropResource(),
+		"[method]incoming-response.status":        {params: params(i32), results: params(i32), fn: w.responseStatus},
+		"[method]incoming-response.headers":       {params: params(i32), results: params(i32), fn: w.responseHeaders},
+		"[method]incoming-response.consume":       {params: params(i32, i32), fn: w.responseConsume},
+		"[resource-drop]incoming-response":        w.table.dropResource(),
+		"[method]incoming-body.stream":            {params: params(i32, i32), fn: w.incomingBodyStream},
+		"[static]incoming-body.finish":            {params: params(i32), results: params(i32), fn: w.incomingBodyFinish},
+		"[resource-drop]incoming-body":            w.table.dropResource(),
+		"[method]future-trailers.subscribe":       {params: params(i32), results: params(i32), fn: w.futureTrailersSubscribe},
+		"[method]future-trailers.get":             {params: params(i32, i32), fn: w.futureTrailersGet},
+		"[resource-drop]future-trailers":          w.table.dropResource(),
+		"http-error-code":                         {params: params(i32, i32), fn: w.httpErrorCode},
+	}
+	interfaces["wasi:http/outgoing-handler"] = map[string]wasiFunc{
+		"handle": {params: params(i32, i32, i32, i32), fn: w.handle},
+	}
+	return interfaces
+}
+
+// Close cancels the requests the modules sent, and closes their responses.
+func (w *wasiHTTP) Close() error {
+	w.mtx.Lock()
+	exchanges := make([]*wasiExchange, 0, len(w.exchanges))
+	for exchange := range w.exchanges {
+		exchanges = append(exchanges, exchange)
+	}
+	w.mtx.Unlock()
+	for _, exchange := range exchanges {
+		exchange.release()
+	}
+	return nil
+}
+
+// wasiField is a field of headers or trailers, whose name is lowercase.
+type wasiField struct {
+	name  string
+	value []byte
+}
+
+// wasiFields are the headers or trailers of wasi:http/types, which are immutable once they belong to a request
+// or response.
+type wasiFields struct {
+	entries   []wasiField
+	immutable bool
+}
+
+// check returns the header-error of setting the field to the values, if any.
+func (f *wasiFields) check(name string, values ...[]byte) (byte, bool) {
+	if f.immutable {
+		return headerErrorImmutable, false
+	}
+	if !validToken(name) {
+		return headerErrorInvalidSyntax, false
+	}
+	for _, value := range values {
+		if strings.ContainsAny(string(value), "\r\n\x00") {
+			return headerErrorInvalidSyntax, false
+		}
+	}
+	if slices.Contains(forbiddenHeaders, strings.ToLower(name)) {
+		return headerErrorForbidden, false
+	}
+	return 0, true
+}
+
+func (f *wasiFields) values(name string) [][]byte {
+	var values [][]byte
+	for _, field := range f.entries {
+		if field.name == strings.ToLower(name) {
+			values = append(values, field.value)
+		}
+	}
+	return values
+}
+
+func (f *wasiFields) delete(name string) {
+	f.entries = slices.DeleteFunc(f.entries, func(field wasiField) bool {
+		return field.name == strings.ToLower(name)
+	})
+}
+
+func (f *wasiFields) append(name string, values ...[]byte) {
+	for _, value := range values {
+		f.entries = append(f.entries, wasiField{name: strings.ToLower(name), value: value})
+	}
+}
+
+// fieldsFromHeader returns immutable fields of a header of a response, in a consistent order.
+func fieldsFromHeader(header http.Header) *wasiFields {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := &wasiFields{immutable: true}
+	for _, name := range names {
+		for _, value := range header[name] {
+			fields.append(name, []byte(value))
+		}
+	}
+	return fields
+}
+
+func (w *wasiHTTP) newFields(_ context.Context, _ api.Module, stack []uint64) {
+	stack[0] = api.EncodeU32(w.table.add(&wasiFields{}))
+}
+
+func (w *wasiHTTP) fieldsFromList(_ context.Context, mod api.Module, stack []uint64) {
+	entries := readBytes(mod, api.DecodeU32(stack[0]), 16*api.DecodeU32(stack[1]))
+	ret := api.DecodeU32(stack[2])
+	fields := &wasiFields{}
+	for i := 0; i < len(entries); i += 16 {
+		name := readString(mod, leUint32(entries[i:]), leUint32(entries[i+4:]))
+		value := readBytes(mod, leUint32(entries[i+8:]), leUint32(entries[i+12:]))
+		if code, ok := fields.check(name, value); !ok {
+			writeU8(mod, ret, 1)
+			writeU8(mod, ret+4, code)
+			return
+		}
+		fields.append(name, value)
+	}
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+4, w.table.add(fields))
+}
+
+func (w *wasiHTTP) fieldsGet(ctx context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	name := readString(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+	writeByteLists(ctx, mod, api.DecodeU32(stack[3]), fields.values(name))
+}
+
+func (w *wasiHTTP) fieldsHas(_ context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	name := readString(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+	stack[0] = encodeBool(len(fields.values(name)) > 0)
+}
+
+func (w *wasiHTTP) fieldsSet(_ context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	name := readString(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+	elements := readBytes(mod, api.DecodeU32(stack[3]), 8*api.DecodeU32(stack[4]))
+	values := make([][]byte, 0, len(elements)/8)
+	for i := 0; i < len(elements); i += 8 {
+		values = append(values, readBytes(mod, leUint32(elements[i:]), leUint32(elements[i+4:])))
+	}
+	w.writeHeaderResult(mod, api.DecodeU32(stack[5]), fields, name, values, func() {
+		fields.delete(name)
+		fields.append(name, values...)
+	})
+}
+
+func (w *wasiHTTP) fieldsDelete(_ context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	name := readString(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+	w.writeHeaderResult(mod, api.DecodeU32(stack[3]), fields, name, nil, func() {
+		fields.delete(name)
+	})
+}
+
+func (w *wasiHTTP) fieldsAppend(_ context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	name := readString(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+	value := readBytes(mod, api.DecodeU32(stack[3]), api.DecodeU32(stack[4]))
+	w.writeHeaderResult(mod, api.DecodeU32(stack[5]), fields, name, [][]byte{value}, func() {
+		fields.append(name, value)
+	})
+}
+
+// writeHeaderResult changes the fields if the values are valid, and writes the result<_, header-error>
+func (w *wasiHTTP) writeHeaderResult(mod api.Module, ret uint32, fields *wasiFields, name string, values [][]byte, change func()) {
+	if code, ok := fields.check(name, values...); !ok {
+		writeU8(mod, ret, 1)
+		writeU8(mod, ret+1, code)
+		return
+	}
+	change()
+	writeU8(mod, ret, 0)
+}
+
+func (w *wasiHTTP) fieldsEntries(ctx context.Context, mod api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	elements := make([]byte, 0, 16*len(fields.entries))
+	for _, field := range fields.entries {
+		name := allocateBytes(ctx, mod, []byte(field.name))
+		value := allocateBytes(ctx, mod, field.value)
+		elements = appendUint32(elements, name)
+		elements = appendUint32(elements, uint32(len(field.name)))
+		elements = appendUint32(elements, value)
+		elements = appendUint32(elements, uint32(len(field.value)))
+	}
+	writeList(ctx, mod, api.DecodeU32(stack[1]), 4, elements, len(fields.entries))
+}
+
+func (w *wasiHTTP) fieldsClone(_ context.Context, _ api.Module, stack []uint64) {
+	fields := getResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(&wasiFields{entries: slices.Clone(fields.entries)}))
+}
+
+// wasiOutgoingRequest is an outgoing-request of wasi:http/types. Unset options are nil.
+type wasiOutgoingRequest struct {
+	headers       *wasiFields
+	method        string
+	scheme        *string
+	authority     *string
+	pathWithQuery *string
+	body          *wasiOutgoingBody
+}
+
+// url returns the URL of the request, whose scheme is https unless set otherwise.
+func (r *wasiOutgoingRequest) url() (*url.URL, error) {
+	if r.authority == nil || *r.authority == "" {
+		return nil, errors.New("request has no authority")
+	}
+	scheme, path := "https", "/"
+	if r.scheme != nil {
+		scheme = *r.scheme
+	}
+	if r.pathWithQuery != nil && *r.pathWithQuery != "" {
+		path = *r.pathWithQuery
+	}
+	if !slices.Contains(schemes, scheme) {
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+	return url.Parse(scheme + "://" + *r.authority + path)
+}
+
+func (w *wasiHTTP) newOutgoingRequest(_ context.Context, _ api.Module, stack []uint64) {
+	headers := takeResource[*wasiFields](w.table, api.DecodeU32(stack[0]))
+	headers.immutable = true
+	stack[0] = api.EncodeU32(w.table.add(&wasiOutgoingRequest{headers: headers, method: http.MethodGet}))
+}
+
+func (w *wasiHTTP) outgoingRequestBody(_ context.Context, mod api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	if request.body != nil {
+		writeU8(mod, ret, 1)
+		return
+	}
+	request.body = &wasiOutgoingBody{writer: &wasiBodyWriter{notifier: w.notifier}}
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+4, w.table.add(request.body))
+}
+
+func (w *wasiHTTP) requestMethod(ctx context.Context, mod api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	writeVariant(ctx, mod, api.DecodeU32(stack[1]), methods, request.method)
+}
+
+func (w *wasiHTTP) requestSetMethod(_ context.Context, mod api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	method, ok := readVariant(mod, methods, stack[1:4])
+	if !ok || !validToken(method) {
+		stack[0] = 1
+		return
+	}
+	request.method = method
+	stack[0] = 0
+}
+
+func (w *wasiHTTP) requestScheme(ctx context.Context, mod api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	if request.scheme == nil {
+		writeU8(mod, ret, 0)
+		return
+	}
+	writeU8(mod, ret, 1)
+	writeVariant(ctx, mod, ret+4, schemes, *request.scheme)
+}
+
+func (w *wasiHTTP) requestSetScheme(_ context.Context, mod api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	if api.DecodeU32(stack[1]) == 0 {
+		request.scheme = nil
+		stack[0] = 0
+		return
+	}
+	scheme, ok := readVariant(mod, schemes, stack[2:5])
+	if u, err := url.Parse(scheme + ":"); !ok || err != nil || u.Scheme != strings.ToLower(scheme) {
+		stack[0] = 1
+		return
+	}
+	scheme = strings.ToLower(scheme)
+	request.scheme = &scheme
+	stack[0] = 0
+}
+
+// requestField is an option<string> of outgoing-request, with how to validate it
+type requestField struct {
+	get   func(*wasiOutgoingRequest) **string
+	valid func(string) bool
+}
+
+var (
+	pathWithQuery = requestField{
+		get: func(r *wasiOutgoingRequest) **string { return &r.pathWithQuery },
+		valid: func(value string) bool {
+			u, err := url.ParseRequestURI(value)
+			return err == nil && u.Scheme == "" && u.Host == ""
+		},
+	}
+	authority = requestField{
+		get: func(r *wasiOutgoingRequest) **string { return &r.authority },
+		valid: func(value string) bool {
+			u, err := url.Parse("http://" + value)
+			return err == nil && u.Host == value && u.User == nil
+		},
+	}
+)
+
+func (w *wasiHTTP) requestOption(field requestField) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+		ret := api.DecodeU32(stack[1])
+		value := *field.get(request)
+		if value == nil {
+			writeU8(mod, ret, 0)
+			return
+		}
+		writeU8(mod, ret, 1)
+		writeString(ctx, mod, ret+4, *value)
+	}
+}
+
+func (w *wasiHTTP) requestSetOption(field requestField) api.GoModuleFunc {
+	return func(_ context.Context, mod api.Module, stack []uint64) {
+		request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+		if api.DecodeU32(stack[1]) == 0 {
+			*field.get(request) = nil
+			stack[0] = 0
+			return
+		}
+		value := readString(mod, api.DecodeU32(stack[2]), api.DecodeU32(stack[3]))
+		if !field.valid(value) {
+			stack[0] = 1
+			return
+		}
+		*field.get(request) = &value
+		stack[0] = 0
+	}
+}
+
+func (w *wasiHTTP) requestHeaders(_ context.Context, _ api.Module, stack []uint64) {
+	request := getResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(request.headers))
+}
+
+// wasiRequestOptions are the request-options of wasi:http/types. Unset timeouts are nil.
+type wasiRequestOptions struct {
+	timeouts [3]*time.Duration
+}
+
+const (
+	connectTimeout = iota
+	firstByteTimeout
+	betweenBytesTimeout
+)
+
+// headerTimeout returns how long to wait for the headers of the response, which is 0 if there is no timeout.
+// Timeouts between bytes of the body are subject to the timeout of the execution only.
+func (o *wasiRequestOptions) headerTimeout() time.Duration {
+	var timeout time.Duration
+	if o != nil {
+		for _, t := range o.timeouts[:betweenBytesTimeout] {
+			if t != nil {
+				timeout += *t
+			}
+		}
+	}
+	return timeout
+}
+
+func (w *wasiHTTP) newRequestOptions(_ context.Context, _ api.Module, stack []uint64) {
+	stack[0] = api.EncodeU32(w.table.add(&wasiRequestOptions{}))
+}
+
+func (w *wasiHTTP) timeout(kind int) api.GoModuleFunc {
+	return func(_ context.Context, mod api.Module, stack []uint64) {
+		options := getResource[*wasiRequestOptions](w.table, api.DecodeU32(stack[0]))
+		ret := api.DecodeU32(stack[1])
+		if options.timeouts[kind] == nil {
+			writeU8(mod, ret, 0)
+			return
+		}
+		writeU8(mod, ret, 1)
+		writeU64(mod, ret+8, uint64(*options.timeouts[kind]))
+	}
+}
+
+func (w *wasiHTTP) setTimeout(kind int) wasiFunc {
+	return wasiFunc{params: params(i32, i32, i64), results: params(i32), fn: func(_ context.Context, _ api.Module, stack []uint64) {
+		options := getResource[*wasiRequestOptions](w.table, api.DecodeU32(stack[0]))
+		options.timeouts[kind] = nil
+		if api.DecodeU32(stack[1]) != 0 {
+			timeout := time.Duration(min(stack[2], uint64(time.Duration(1<<63-1))))
+			options.timeouts[kind] = &timeout
+		}
+		stack[0] = 0
+	}}
+}
+
+// wasiOutgoingBody is an outgoing-body of wasi:http/types.
+type wasiOutgoingBody struct {
+	writer      *wasiBodyWriter
+	streamTaken bool
+}
+
+// Close fails the request if the module dropped the body without finishing it.
+func (b *wasiOutgoingBody) Close() error {
+	b.writer.abort(errors.New("body of the request was dropped before it was finished"))
+	return nil
+}
+
+func (w *wasiHTTP) outgoingBodyWrite(_ context.Context, mod api.Module, stack []uint64) {
+	body := getResource[*wasiOutgoingBody](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	if body.streamTaken {
+		writeU8(mod, ret, 1)
+		return
+	}
+	body.streamTaken = true
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+4, w.table.add(body.writer))
+}
+
+// outgoingBodyFinish ends the body of the request. Trailers of requests are dropped, as the host doesn't send them.
+func (w *wasiHTTP) outgoingBodyFinish(_ context.Context, mod api.Module, stack []uint64) {
+	body := takeResource[*wasiOutgoingBody](w.table, api.DecodeU32(stack[0]))
+	if api.DecodeU32(stack[1]) != 0 {
+		takeResource[*wasiFields](w.table, api.DecodeU32(stack[2]))
+	}
+	body.writer.finish()
+	writeU8(mod, api.DecodeU32(stack[3]), 0)
+}
+
+// wasiBodyWriter is the output-stream of the body of a request, which the request reads from as it is sent.
+type wasiBodyWriter struct {
+	notifier *wasiNotifier
+	// ctx is the context of the request, once it is sent
+	ctx context.Context
+
+	mtx      sync.Mutex
+	buf      []byte
+	finished bool
+	// err is set once the body is dropped before it was finished, or the request failed
+	err error
+}
+
+func (b *wasiBodyWriter) checkWrite() (uint64, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.finished {
+		return 0, errStreamClosed
+	}
+	return uint64(wasiBodyBufferSize - len(b.buf)), nil
+}
+
+func (b *wasiBodyWriter) write(data []byte) error {
+	permit, err := b.checkWrite()
+	if err != nil {
+		return err
+	}
+	if uint64(len(data)) > permit {
+		return fmt.Errorf("write of %d bytes exceeds the %d bytes permitted", len(data), permit)
+	}
+	b.mtx.Lock()
+	b.buf = append(b.buf, data...)
+	b.mtx.Unlock()
+	b.notifier.notify()
+	return nil
+}
+
+func (b *wasiBodyWriter) ready() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.err != nil || b.finished || len(b.buf) < wasiBodyBufferSize
+}
+
+// Close is called when the module drops the stream, which doesn't end the body.
+func (b *wasiBodyWriter) Close() error {
+	return nil
+}
+
+func (b *wasiBodyWriter) finish() {
+	b.mtx.Lock()
+	b.finished = true
+	b.mtx.Unlock()
+	b.notifier.notify()
+}
+
+func (b *wasiBodyWriter) abort(err error) {
+	b.mtx.Lock()
+	if !b.finished && b.err == nil {
+		b.err = err
+	}
+	b.mtx.Unlock()
+	b.notifier.notify()
+}
+
+// Read implements io.Reader for the request, and blocks until the module wrote to or finished the body.
+func (b *wasiBodyWriter) Read(p []byte) (int, error) {
+	err := b.notifier.wait(b.ctx, func() bool {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		return len(b.buf) > 0 || b.finished || b.err != nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	b.mtx.Lock()
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	switch {
+	case n > 0:
+		err = nil
+	case b.err != nil:
+		err = b.err
+	default:
+		err = io.EOF
+	}
+	b.mtx.Unlock()
+	b.notifier.notify()
+	return n, err
+}
+
+// wasiExchange is a request sent by a module and its response. It holds a slot of the open responses of the
+// host until the module dropped the resources of the response, or the host is closed.
+type wasiExchange struct {
+	host   *wasiHTTP
+	cancel context.CancelFunc
+
+	mtx      sync.Mutex
+	refs     int
+	released bool
+	done     bool
+	response *http.Response
+	err      error
+}
+
+func (e *wasiExchange) ref() {
+	e.mtx.Lock()
+	e.refs++
+	e.mtx.Unlock()
+}
+
+// Close is called when the module drops a resource of the exchange, which is released with the last one.
+func (e *wasiExchange) Close() error {
+	e.mtx.Lock()
+	e.refs--
+	last := e.refs == 0
+	e.mtx.Unlock()
+	if last {
+		e.release()
+	}
+	return nil
+}
+
+// release cancels the request, closes the response and frees the slot of the exchange.
+func (e *wasiExchange) release() {
+	e.mtx.Lock()
+	if e.released {
+		e.mtx.Unlock()
+		return
+	}
+	e.released = true
+	response := e.response
+	e.mtx.Unlock()
+
+	e.cancel()
+	if response != nil {
+		_ = response.Body.Close()
+	}
+	e.host.host.release()
+	e.host.mtx.Lock()
+	delete(e.host.exchanges, e)
+	e.host.mtx.Unlock()
+	e.host.notifier.notify()
+}
+
+func (e *wasiExchange) complete(response *http.Response, err error) {
+	e.mtx.Lock()
+	e.done, e.response, e.err = true, response, err
+	released := e.released
+	e.mtx.Unlock()
+	if released && response != nil {
+		_ = response.Body.Close()
+	}
+	e.host.notifier.notify()
+}
+
+func (e *wasiExchange) isReleased() bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.released
+}
+
+func (e *wasiExchange) isDone() bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.done
+}
+
+// handle sends the request, and returns a future-incoming-response or an error-code.
+func (w *wasiHTTP) handle(ctx context.Context, mod api.Module, stack []uint64) {
+	request := takeResource[*wasiOutgoingRequest](w.table, api.DecodeU32(stack[0]))
+	var options *wasiRequestOptions
+	if api.DecodeU32(stack[1]) != 0 {
+		options = takeResource[*wasiRequestOptions](w.table, api.DecodeU32(stack[2]))
+	}
+	ret := api.DecodeU32(stack[3])
+	exchange, err := w.send(ctx, request, options)
+	if err != nil {
+		writeU8(mod, ret, 1)
+		writeErrorCode(ctx, mod, ret+8, err)
+		return
+	}
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+8, w.table.add(&wasiFutureResponse{exchange: exchange}))
+}
+
+// send starts sending the request in the background, after checking it is allowed by the network config
+// and the limit of open responses.
+func (w *wasiHTTP) send(ctx context.Context, request *wasiOutgoingRequest, options *wasiRequestOptions) (*wasiExchange, error) {
+	u, err := request.url()
+	if err != nil {
+		return nil, &wasiErrorCode{code: errorCodeHTTPRequestURIInvalid}
+	}
+	if err = w.host.checkDestination(u); err != nil {
+		return nil, err
+	}
+
+	// requests are canceled with the execution, or when the module drops the response
+	ctx, cancel := context.WithCancel(ctx)
+	var body io.Reader = http.NoBody
+	if request.body != nil {
+		request.body.writer.ctx = ctx
+		body = request.body.writer
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, request.method, u.String(), body)
+	if err != nil {
+		cancel()
+		return nil, &wasiErrorCode{code: errorCodeHTTPRequestMethod}
+	}
+	for _, field := range request.headers.entries {
+		httpRequest.Header.Add(field.name, string(field.value))
+	}
+	if length := httpRequest.Header.Get("Content-Length"); length != "" {
+		if httpRequest.ContentLength, err = strconv.ParseInt(length, 10, 64); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid content-length %q", length)
+		}
+	}
+
+	if !w.host.acquire() {
+		cancel()
+		return nil, &wasiErrorCode{code: errorCodeConnectionLimitReached}
+	}
+	exchange := &wasiExchange{host: w, cancel: cancel, refs: 1}
+	w.mtx.Lock()
+	w.exchanges[exchange] = struct{}{}
+	w.mtx.Unlock()
+
+	go func() {
+		var timer *time.Timer
+		if timeout := options.headerTimeout(); timeout > 0 {
+			timer = time.AfterFunc(timeout, cancel)
+		}
+		response, err := w.client.Do(httpRequest)
+		if timer != nil && !timer.Stop() && err != nil {
+			err = errResponseTimeout
+		}
+		if err != nil {
+			w.host.logger.Debug().Err(err).Str("host", u.Hostname()).Msg("outbound HTTP request failed")
+			if request.body != nil {
+				request.body.writer.abort(err)
+			}
+		} else {
+			w.host.logger.Debug().Str("host", u.Hostname()).Int("status", response.StatusCode).Msg("sent outbound HTTP request")
+		}
+		exchange.complete(response, err)
+	}()
+	return exchange, nil
+}
+
+// wasiFutureResponse is a future-incoming-response of wasi:http/types.
+type wasiFutureResponse struct {
+	exchange *wasiExchange
+	taken    bool
+}
+
+func (f *wasiFutureResponse) Close() error {
+	return f.exchange.Close()
+}
+
+func (w *wasiHTTP) futureResponseSubscribe(_ context.Context, _ api.Module, stack []uint64) {
+	future := getResource[*wasiFutureResponse](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(&wasiPollable{ready: future.exchange.isDone}))
+}
+
+// futureResponseGet writes option<result<result<own<incoming-response>, error-code>>>, which is none until the
+// response arrived, and an error once it was taken.
+func (w *wasiHTTP) futureResponseGet(ctx context.Context, mod api.Module, stack []uint64) {
+	future := getResource[*wasiFutureResponse](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	exchange := future.exchange
+	exchange.mtx.Lock()
+	done, response, err := exchange.done, exchange.response, exchange.err
+	exchange.mtx.Unlock()
+	if !done {
+		writeU8(mod, ret, 0)
+		return
+	}
+	writeU8(mod, ret, 1)
+	if future.taken {
+		writeU8(mod, ret+8, 1)
+		return
+	}
+	future.taken = true
+	writeU8(mod, ret+8, 0)
+	if err != nil {
+		writeU8(mod, ret+16, 1)
+		writeErrorCode(ctx, mod, ret+24, err)
+		return
+	}
+	exchange.ref()
+	writeU8(mod, ret+16, 0)
+	writeU32(mod, ret+24, w.table.add(&wasiIncomingResponse{exchange: exchange, response: response}))
+}
+
+// wasiIncomingResponse is an incoming-response of wasi:http/types.
+type wasiIncomingResponse struct {
+	exchange *wasiExchange
+	response *http.Response
+	consumed bool
+}
+
+func (r *wasiIncomingResponse) Close() error {
+	return r.exchange.Close()
+}
+
+func (w *wasiHTTP) responseStatus(_ context.Context, _ api.Module, stack []uint64) {
+	response := getResource[*wasiIncomingResponse](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(uint32(response.response.StatusCode))
+}
+
+func (w *wasiHTTP) responseHeaders(_ context.Context, _ api.Module, stack []uint64) {
+	response := getResource[*wasiIncomingResponse](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(fieldsFromHeader(response.response.Header)))
+}
+
+func (w *wasiHTTP) responseConsume(_ context.Context, mod api.Module, stack []uint64) {
+	response := getResource[*wasiIncomingResponse](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	if response.consumed {
+		writeU8(mod, ret, 1)
+		return
+	}
+	response.consumed = true
+	response.exchange.ref()
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+4, w.table.add(&wasiIncomingBody{exchange: response.exchange, response: response.response}))
+}
+
+// wasiIncomingBody is an incoming-body of wasi:http/types.
+type wasiIncomingBody struct {
+	exchange    *wasiExchange
+	response    *http.Response
+	streamTaken bool
+}
+
+func (b *wasiIncomingBody) Close() error {
+	return b.exchange.Close()
+}
+
+func (w *wasiHTTP) incomingBodyStream(_ context.Context, mod api.Module, stack []uint64) {
+	body := getResource[*wasiIncomingBody](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	if body.streamTaken {
+		writeU8(mod, ret, 1)
+		return
+	}
+	body.streamTaken = true
+	body.exchange.ref()
+	reader := &wasiBodyReader{notifier: w.notifier, exchange: body.exchange, body: body.response.Body}
+	go reader.run()
+	writeU8(mod, ret, 0)
+	writeU32(mod, ret+4, w.table.add(reader))
+}
+
+func (w *wasiHTTP) incomingBodyFinish(_ context.Context, _ api.Module, stack []uint64) {
+	body := takeResource[*wasiIncomingBody](w.table, api.DecodeU32(stack[0]))
+	_ = body.Close()
+	stack[0] = api.EncodeU32(w.table.add(&wasiFutureTrailers{response: body.response}))
+}
+
+// wasiFutureTrailers is a future-trailers of wasi:http/types, which has the trailers received with the body so far.
+type wasiFutureTrailers struct {
+	response *http.Response
+	taken    bool
+}
+
+func (w *wasiHTTP) futureTrailersSubscribe(_ context.Context, _ api.Module, stack []uint64) {
+	getResource[*wasiFutureTrailers](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(&wasiPollable{ready: func() bool { return true }}))
+}
+
+func (w *wasiHTTP) futureTrailersGet(_ context.Context, mod api.Module, stack []uint64) {
+	trailers := getResource[*wasiFutureTrailers](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	writeU8(mod, ret, 1)
+	if trailers.taken {
+		writeU8(mod, ret+8, 1)
+		return
+	}
+	trailers.taken = true
+	writeU8(mod, ret+8, 0)
+	writeU8(mod, ret+16, 0)
+	if len(trailers.response.Trailer) == 0 {
+		writeU8(mod, ret+24, 0)
+		return
+	}
+	writeU8(mod, ret+24, 1)
+	writeU32(mod, ret+28, w.table.add(fieldsFromHeader(trailers.response.Trailer)))
+}
+
+func (w *wasiHTTP) httpErrorCode(ctx context.Context, mod api.Module, stack []uint64) {
+	e := getResource[*wasiError](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	writeU8(mod, ret, 1)
+	writeErrorCode(ctx, mod, ret+8, e.err)
+}
+
+// wasiBodyReader is the input-stream of the body of a response, which it reads in the background one chunk
+// at a time.
+type wasiBodyReader struct {
+	notifier *wasiNotifier
+	exchange *wasiExchange
+	body     io.Reader
+
+	mtx    sync.Mutex
+	buf    []byte
+	err    error
+	closed bool
+}
+
+func (r *wasiBodyReader) run() {
+	chunk := make([]byte, httpBodyChunkSize)
+	for {
+		// the next chunk is read once the module read the previous one
+		_ = r.notifier.wait(context.Background(), func() bool {
+			r.mtx.Lock()
+			defer r.mtx.Unlock()
+			return len(r.buf) == 0 || r.closed || r.exchange.isReleased()
+		})
+		r.mtx.Lock()
+		closed := r.closed
+		r.mtx.Unlock()
+		if closed || r.exchange.isReleased() {
+			return
+		}
+
+		n, err := r.body.Read(chunk)
+		r.mtx.Lock()
+		r.buf = append(r.buf, chunk[:n]...)
+		r.err = err
+		r.mtx.Unlock()
+		r.notifier.notify()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (r *wasiBodyReader) read(n uint64) ([]byte, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if len(r.buf) > 0 {
+		data := r.buf[:min(n, uint64(len(r.buf)))]
+		r.buf = r.buf[len(data):]
+		if len(r.buf) == 0 {
+			defer r.notifier.notify()
+		}
+		return data, nil
+	}
+	if r.closed {
+		return nil, errStreamClosed
+	}
+	return nil, r.err
+}
+
+func (r *wasiBodyReader) ready() bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return len(r.buf) > 0 || r.err != nil || r.closed
+}
+
+func (r *wasiBodyReader) Close() error {
+	r.mtx.Lock()
+	r.closed = true
+	r.mtx.Unlock()
+	r.notifier.notify()
+	return r.exchange.Close()
+}
+
+// wasiErrorCode is an error-code of wasi:http/types, which is internal-error with the message of the error
+// for errors without a more specific code.
+type wasiErrorCode struct {
+	code uint8
+}
+
+func (e *wasiErrorCode) Error() string {
+	return fmt.Sprintf("HTTP error code %d", e.code)
+}
+
+func errorCode(err error) uint8 {
+	var code *wasiErrorCode
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &code):
+		return code.code
+	case errors.Is(err, errDestinationNotAllowed):
+		return errorCodeHTTPRequestDenied
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsTimeout {
+			return errorCodeDNSTimeout
+		}
+		return errorCodeDNSError
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return errorCodeConnectionRefused
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return errorCodeConnectionTimeout
+	case errors.Is(err, errResponseTimeout), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return errorCodeHTTPResponseTimeout
+	default:
+		return errorCodeInternalError
+	}
+}
+
+// writeErrorCode writes the error-code of the error, whose payload is 8 bytes after its discriminant.
+func writeErrorCode(ctx context.Context, mod api.Module, ptr uint32, err error) {
+	code := errorCode(err)
+	writeU8(mod, ptr, code)
+	switch code {
+	case errorCodeDNSError:
+		// the rcode and info-code of the payload are unknown
+		writeU8(mod, ptr+8, 0)
+		writeU8(mod, ptr+20, 0)
+	case errorCodeInternalError:
+		writeU8(mod, ptr+8, 1)
+		writeString(ctx, mod, ptr+12, err.Error())
+	}
+}
+
+// writeVariant writes a variant such as method, whose cases are the known values and a last case with any
+// other value as a string.
+func writeVariant(ctx context.Context, mod api.Module, ptr uint32, cases []string, value string) {
+	if i := slices.Index(cases, value); i >= 0 {
+		writeU8(mod, ptr, byte(i))
+		return
+	}
+	writeU8(mod, ptr, byte(len(cases)))
+	writeString(ctx, mod, ptr+4, value)
+}
+
+// readVariant reads a variant such as method from its flattened discriminant, pointer and length.
+func readVariant(mod api.Module, cases []string, flat []uint64) (string, bool) {
+	switch i := int(api.DecodeU32(flat[0])); {
+	case i < len(cases):
+		return cases[i], true
+	case i == len(cases):
+		return readString(mod, api.DecodeU32(flat[1]), api.DecodeU32(flat[2])), true
+	default:
+		return "", false
+	}
+}
+
+// writeByteLists writes a list<list<u8>>, allocating each of the lists.
+func writeByteLists(ctx context.Context, mod api.Module, ptr uint32, values [][]byte) {
+	elements := make([]byte, 0, 8*len(values))
+	for _, value := range values {
+		elements = appendUint32(elements, allocateBytes(ctx, mod, value))
+		elements = appendUint32(elements, uint32(len(value)))
+	}
+	writeList(ctx, mod, ptr, 4, elements, len(values))
+}
+
+// allocateBytes allocates and writes the data in the module, and returns its address.
+func allocateBytes(ctx context.Context, mod api.Module, data []byte) uint32 {
+	address := allocate(ctx, mod, 1, uint32(len(data)))
+	if !mod.Memory().Write(address, data) {
+		panic(errMemoryAccess)
+	}
+	return address
+}
+
+// validToken returns whether the value is a token of RFC 9110, which names of fields and methods are.
+func validToken(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range []byte(value) {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0) {
+			return false
+		}
+	}
+	return true
+}
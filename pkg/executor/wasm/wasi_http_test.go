@@ -0,0 +1,363 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// sleb encodes a signed LEB128 number, as i32.const takes.
+func sleb(value int32) []byte {
+	var out []byte
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if (value == 0 && b&0x40 == 0) || (value == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func i32Const(value int32) []byte {
+	return append([]byte{0x41}, sleb(value)...)
+}
+
+func call(index byte) []byte {
+	return []byte{0x10, index}
+}
+
+func wasmImport(module, name string, typeIndex byte) []byte {
+	return concat(wasmName(module), wasmName(name), []byte{0, typeIndex})
+}
+
+func wasmFunction(body ...[]byte) []byte {
+	code := concat(body...)
+	return append(uleb(uint32(len(code))), code...)
+}
+
+// reallocFunction is a cabi_realloc that allocates from a bump pointer in the first global, and never frees.
+var reallocFunction = wasmFunction([]byte{
+	0,
+	0x23, 0, 0x20, 2, 0x6a, 0x41, 1, 0x6b, // heap + align - 1
+	0x41, 0, 0x20, 2, 0x6b, 0x71, // & -align
+	0x22, 0, 0x20, 3, 0x6a, 0x24, 0, // heap = address + size
+	0x20, 0, 0x0b,
+})
+
+// heapGlobal is the global of reallocFunction, which starts allocating at 1024.
+var heapGlobal = wasmSection(6, []byte{1, 0x7f, 1, 0x41, 0x80, 0x08, 0x0b})
+
+// reallocModule exports a memory of one page and cabi_realloc.
+var reallocModule = wasmBinary(
+	wasmSection(1, []byte{1, 0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 1, 0x7f}),
+	wasmSection(3, []byte{1, 0}),
+	wasmSection(5, []byte{1, 0, 1}),
+	heapGlobal,
+	wasmSection(7, []byte{2}, wasmName("memory"), []byte{2, 0}, wasmName("cabi_realloc"), []byte{0, 0}),
+	wasmSection(10, []byte{1}, reallocFunction),
+)
+
+// httpGuest returns a program that sends a GET request to the authority with wasi:http, and exits with the status
+// of the response, 1000 plus the error-code if sending fails, or 2000 plus the error-code if the response fails.
+func httpGuest(authority string) []byte {
+	const types, handler = "wasi:http/types@0.2.0", "wasi:http/outgoing-handler@0.2.0"
+	load8 := []byte{0x2d, 0, 0}
+	load32 := []byte{0x28, 2, 0}
+	exitWithCode := func(ret, code, offset int32) []byte {
+		return concat(i32Const(ret), load8, []byte{0x04, 0x40},
+			i32Const(code), load8, i32Const(offset), []byte{0x6a}, call(9), []byte{0x0b})
+	}
+	start := wasmFunction(
+		[]byte{1, 1, 0x7f},
+		call(0), call(1), []byte{0x21, 0},
+		[]byte{0x20, 0}, i32Const(1), i32Const(0), i32Const(0), i32Const(0), call(2), []byte{0x1a},
+		[]byte{0x20, 0}, i32Const(1), i32Const(16), i32Const(int32(len(authority))), call(3), []byte{0x1a},
+		[]byte{0x20, 0}, i32Const(0), i32Const(0), i32Const(256), call(4),
+		exitWithCode(256, 264, 1000),
+		i32Const(264), load32, []byte{0x21, 0},
+		[]byte{0x20, 0}, call(5), call(6),
+		[]byte{0x20, 0}, i32Const(512), call(7),
+		exitWithCode(528, 536, 2000),
+		i32Const(536), load32, call(8), call(9),
+		[]byte{0x0b},
+	)
+	return wasmBinary(
+		wasmSection(1, []byte{8},
+			[]byte{0x60, 0, 1, 0x7f},
+			[]byte{0x60, 1, 0x7f, 1, 0x7f},
+			[]byte{0x60, 5, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 1, 0x7f},
+			[]byte{0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 1, 0x7f},
+			[]byte{0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 0},
+			[]byte{0x60, 1, 0x7f, 0},
+			[]byte{0x60, 2, 0x7f, 0x7f, 0},
+			[]byte{0x60, 0, 0},
+		),
+		wasmSection(2, []byte{10},
+			wasmImport(types, "[constructor]fields", 0),
+			wasmImport(types, "[constructor]outgoing-request", 1),
+			wasmImport(types, "[method]outgoing-request.set-scheme", 2),
+			wasmImport(types, "[method]outgoing-request.set-authority", 3),
+			wasmImport(handler, "handle", 4),
+			wasmImport(types, "[method]future-incoming-response.subscribe", 1),
+			wasmImport("wasi:io/poll@0.2.0", "[method]pollable.block", 5),
+			wasmImport(types, "[method]future-incoming-response.get", 6),
+			wasmImport(types, "[method]incoming-response.status", 1),
+			wasmImport("wasi_snapshot_preview1", "proc_exit", 5),
+		),
+		wasmSection(3, []byte{2, 3, 7}),
+		wasmSection(5, []byte{1, 0, 1}),
+		heapGlobal,
+		wasmSection(7, []byte{3},
+			wasmName("memory"), []byte{2, 0},
+			wasmName("cabi_realloc"), []byte{0, 10},
+			wasmName("_start"), []byte{0, 11},
+		),
+		wasmSection(10, []byte{2}, reallocFunction, start),
+		wasmSection(11, []byte{1, 0}, i32Const(16), []byte{0x0b}, wasmName(authority)),
+	)
+}
+
+// runComponent runs a component of the program through the loader, and returns its exit code.
+func (s *HTTPHostTestSuite) runComponent(host *HTTPHost, program []byte) (uint32, error) {
+	entryModule := prepareModule(s.T(), "", component(adapterModule, program))
+	loader := NewModuleLoader(s.runtime, wazero.NewModuleConfig().WithStartFunctions(), entryModule)
+	if host != nil {
+		loader = loader.WithHTTPHost(host)
+	}
+	module, err := loader.InstantiateRemoteModule(context.Background(), entryModule)
+	if err != nil {
+		return 0, err
+	}
+	_, err = module.ExportedFunction("_start").Call(context.Background())
+	var exitErr *sys.ExitError
+	s.Require().True(errors.As(err, &exitErr), "program should exit, got %v", err)
+	return exitErr.ExitCode(), nil
+}
+
+func (s *HTTPHostTestSuite) TestWASIHTTPComponent() {
+	authority := strings.TrimPrefix(s.server.URL, "http://")
+
+	_, err := s.runComponent(nil, httpGuest(authority))
+	s.ErrorContains(err, "only available to jobs with HTTP networking")
+
+	host := s.host("127.0.0.1")
+	code, err := s.runComponent(host, httpGuest(authority))
+	s.Require().NoError(err)
+	s.Equal(uint32(http.StatusOK), code)
+	s.Equal(&models.EgressLog{Allowed: 1}, host.Egress())
+}
+
+func (s *HTTPHostTestSuite) TestWASIHTTPComponentDenied() {
+	host := s.host("example.com")
+	code, err := s.runComponent(host, httpGuest(strings.TrimPrefix(s.server.URL, "http://")))
+	s.Require().NoError(err)
+	s.Equal(uint32(1000+errorCodeHTTPRequestDenied), code)
+	s.Equal(uint64(1), host.Egress().Denied)
+}
+
+func (s *HTTPHostTestSuite) TestWASIInterfaceNotSupported() {
+	program := wasmBinary(
+		wasmSection(1, []byte{1, 0x60, 0, 0}),
+		wasmSection(2, []byte{1}, wasmImport("wasi:cli/environment@0.2.0", "get-environment", 0)),
+		wasmSection(5, []byte{1, 0, 1}),
+		wasmSection(7, []byte{1}, wasmName("memory"), []byte{2, 0}),
+	)
+	_, err := s.runComponent(s.host("127.0.0.1"), program)
+	s.ErrorContains(err, `WASI interface "wasi:cli/environment@0.2.0" is not supported`)
+}
+
+// wasiCaller calls the host functions of WASI 0.2 interfaces with a module exporting cabi_realloc.
+type wasiCaller struct {
+	s      *HTTPHostTestSuite
+	host   *HTTPHost
+	module api.Module
+}
+
+func (s *HTTPHostTestSuite) wasiCaller(host *HTTPHost) *wasiCaller {
+	module, err := s.runtime.InstantiateWithConfig(context.Background(), reallocModule, wazero.NewModuleConfig().WithName(""))
+	s.Require().NoError(err)
+	return &wasiCaller{s: s, host: host, module: module}
+}
+
+func (c *wasiCaller) call(name string, args ...uint64) []uint64 {
+	iface, function, _ := strings.Cut(name, "#")
+	f, found := c.host.wasi.interfaces()[iface][function]
+	c.s.Require().True(found, name)
+	stack := make([]uint64, max(len(f.params), len(f.results)))
+	copy(stack, args)
+	f.fn(context.Background(), c.module, stack)
+	return stack[:len(f.results)]
+}
+
+func (c *wasiCaller) write(offset uint32, value string) (uint64, uint64) {
+	c.s.Require().True(c.module.Memory().Write(offset, []byte(value)))
+	return uint64(offset), uint64(len(value))
+}
+
+func (c *wasiCaller) u8(ptr uint32) byte {
+	value, ok := c.module.Memory().ReadByte(ptr)
+	c.s.Require().True(ok)
+	return value
+}
+
+func (c *wasiCaller) u32(ptr uint32) uint32 {
+	value, ok := c.module.Memory().ReadUint32Le(ptr)
+	c.s.Require().True(ok)
+	return value
+}
+
+// list reads the list at ptr, whose elements are bytes
+func (c *wasiCaller) list(ptr uint32) string {
+	value, ok := c.module.Memory().Read(c.u32(ptr), c.u32(ptr+4))
+	c.s.Require().True(ok)
+	return string(value)
+}
+
+// request returns an outgoing-request to the path of the test server
+func (c *wasiCaller) request(method uint64, path string) uint64 {
+	fields := c.call("wasi:http/types#[constructor]fields")[0]
+	request := c.call("wasi:http/types#[constructor]outgoing-request", fields)[0]
+	c.s.Require().Equal([]uint64{0}, c.call("wasi:http/types#[method]outgoing-request.set-scheme", request, 1, 0, 0, 0))
+	ptr, length := c.write(100, strings.TrimPrefix(c.s.server.URL, "http://"))
+	c.s.Require().Equal([]uint64{0}, c.call("wasi:http/types#[method]outgoing-request.set-authority", request, 1, ptr, length))
+	ptr, length = c.write(200, path)
+	c.s.Require().Equal([]uint64{0}, c.call("wasi:http/types#[method]outgoing-request.set-path-with-query", request, 1, ptr, length))
+	c.s.Require().Equal([]uint64{0}, c.call("wasi:http/types#[method]outgoing-request.set-method", request, method, 0, 0))
+	return request
+}
+
+func (s *HTTPHostTestSuite) TestWASIHTTPStreams() {
+	host := s.host("127.0.0.1")
+	c := s.wasiCaller(host)
+
+	request := c.request(2, "/echo")
+	c.call("wasi:http/types#[method]outgoing-request.body", request, 300)
+	s.Require().Equal(byte(0), c.u8(300))
+	body := uint64(c.u32(304))
+	c.call("wasi:http/types#[method]outgoing-request.body", request, 300)
+	s.Equal(byte(1), c.u8(300), "the body can only be taken once")
+	c.call("wasi:http/types#[method]outgoing-body.write", body, 310)
+	s.Require().Equal(byte(0), c.u8(310))
+	stream := uint64(c.u32(314))
+
+	c.call("wasi:http/outgoing-handler#handle", request, 0, 0, 320)
+	s.Require().Equal(byte(0), c.u8(320))
+	future := uint64(c.u32(328))
+
+	for _, chunk := range []string{"hello ", "world"} {
+		ptr, length := c.write(400, chunk)
+		c.call("wasi:io/streams#[method]output-stream.blocking-write-and-flush", stream, ptr, length, 360)
+		s.Require().Equal(byte(0), c.u8(360))
+	}
+	c.call("wasi:io/streams#[method]output-stream.check-write", stream, 360)
+	s.Require().Equal(byte(0), c.u8(360))
+	c.call("wasi:io/streams#[resource-drop]output-stream", stream)
+	c.call("wasi:http/types#[static]outgoing-body.finish", body, 0, 0, 370)
+	s.Require().Equal(byte(0), c.u8(370))
+
+	pollable := c.call("wasi:http/types#[method]future-incoming-response.subscribe", future)[0]
+	c.call("wasi:io/poll#[method]pollable.block", pollable)
+	c.call("wasi:http/types#[method]future-incoming-response.get", future, 512)
+	s.Require().Equal([]byte{1, 0, 0}, []byte{c.u8(512), c.u8(520), c.u8(528)})
+	response := uint64(c.u32(536))
+	s.Equal([]uint64{http.StatusOK}, c.call("wasi:http/types#[method]incoming-response.status", response))
+	c.call("wasi:http/types#[method]future-incoming-response.get", future, 512)
+	s.Equal([]byte{1, 1}, []byte{c.u8(512), c.u8(520)}, "the response can only be taken once")
+
+	c.call("wasi:http/types#[method]incoming-response.consume", response, 600)
+	s.Require().Equal(byte(0), c.u8(600))
+	incomingBody := uint64(c.u32(604))
+	c.call("wasi:http/types#[method]incoming-body.stream", incomingBody, 610)
+	s.Require().Equal(byte(0), c.u8(610))
+	input := uint64(c.u32(614))
+
+	var read strings.Builder
+	for {
+		c.call("wasi:io/streams#[method]input-stream.blocking-read", input, 4, 620)
+		if c.u8(620) != 0 {
+			s.Equal(byte(1), c.u8(624), "the stream is closed at the end of the body")
+			break
+		}
+		read.WriteString(c.list(624))
+	}
+	s.Equal("hello world", read.String())
+
+	c.call("wasi:io/streams#[resource-drop]input-stream", input)
+	trailers := c.call("wasi:http/types#[static]incoming-body.finish", incomingBody)[0]
+	c.call("wasi:http/types#[method]future-trailers.get", trailers, 700)
+	s.Equal([]byte{1, 0, 0, 0}, []byte{c.u8(700), c.u8(708), c.u8(716), c.u8(724)})
+	c.call("wasi:http/types#[resource-drop]incoming-response", response)
+	c.call("wasi:http/types#[resource-drop]future-incoming-response", future)
+	s.Zero(host.pending, "the slot of the response is released once its resources are dropped")
+}
+
+func (s *HTTPHostTestSuite) TestWASIHTTPFields() {
+	c := s.wasiCaller(s.host("127.0.0.1"))
+	fields := c.call("wasi:http/types#[constructor]fields")[0]
+
+	name, nameLen := c.write(100, "X-Test")
+	value, valueLen := c.write(200, "value")
+	c.call("wasi:http/types#[method]fields.append", fields, name, nameLen, value, valueLen, 300)
+	s.Require().Equal(byte(0), c.u8(300))
+	s.Equal([]uint64{1}, c.call("wasi:http/types#[method]fields.has", fields, name, nameLen))
+	c.call("wasi:http/types#[method]fields.get", fields, name, nameLen, 310)
+	s.Require().Equal(uint32(1), c.u32(314))
+	s.Equal("value", c.list(c.u32(310)))
+	c.call("wasi:http/types#[method]fields.entries", fields, 320)
+	s.Require().Equal(uint32(1), c.u32(324))
+	s.Equal("x-test", c.list(c.u32(320)))
+	s.Equal("value", c.list(c.u32(320)+8))
+
+	for header, code := range map[string]byte{"Host": headerErrorForbidden, "bad name": headerErrorInvalidSyntax} {
+		name, nameLen := c.write(100, header)
+		c.call("wasi:http/types#[method]fields.append", fields, name, nameLen, value, valueLen, 300)
+		s.Equal([]byte{1, code}, []byte{c.u8(300), c.u8(301)}, header)
+	}
+	invalid, invalidLen := c.write(200, "a\r\nb")
+	c.call("wasi:http/types#[method]fields.append", fields, name, nameLen, invalid, invalidLen, 300)
+	s.Equal([]byte{1, headerErrorInvalidSyntax}, []byte{c.u8(300), c.u8(301)})
+
+	request := c.call("wasi:http/types#[constructor]outgoing-request", fields)[0]
+	headers := c.call("wasi:http/types#[method]outgoing-request.headers", request)[0]
+	c.call("wasi:http/types#[method]fields.delete", headers, name, nameLen, 300)
+	s.Equal([]byte{1, headerErrorImmutable}, []byte{c.u8(300), c.u8(301)}, "headers of requests are immutable")
+
+	s.Panics(func() { c.call("wasi:http/types#[method]fields.has", fields, name, nameLen) },
+		"the fields were moved to the request")
+}
+
+func (s *HTTPHostTestSuite) TestWASIHTTPErrors() {
+	host := s.host("127.0.0.1")
+	c := s.wasiCaller(host)
+
+	request := c.call("wasi:http/types#[constructor]outgoing-request", c.call("wasi:http/types#[constructor]fields")[0])[0]
+	c.call("wasi:http/outgoing-handler#handle", request, 0, 0, 320)
+	s.Equal([]byte{1, errorCodeHTTPRequestURIInvalid}, []byte{c.u8(320), c.u8(328)}, "requests need an authority")
+
+	futures := make([]uint64, 0, maxHTTPResponses)
+	for i := 0; i < maxHTTPResponses; i++ {
+		c.call("wasi:http/outgoing-handler#handle", c.request(0, "/slow"), 0, 0, 320)
+		s.Require().Equal(byte(0), c.u8(320))
+		futures = append(futures, uint64(c.u32(328)))
+	}
+	c.call("wasi:http/outgoing-handler#handle", c.request(0, "/"), 0, 0, 320)
+	s.Equal([]byte{1, errorCodeConnectionLimitReached}, []byte{c.u8(320), c.u8(328)})
+
+	// dropping a future cancels its request and releases its slot
+	c.call("wasi:http/types#[resource-drop]future-incoming-response", futures[0])
+	c.call("wasi:http/outgoing-handler#handle", c.request(0, "/"), 0, 0, 320)
+	s.Equal(byte(0), c.u8(320))
+
+	s.Require().NoError(host.Close())
+	s.Zero(host.pending, "closing the host releases the slots of the requests")
+}
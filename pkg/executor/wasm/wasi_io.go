@@ -0,0 +1,477 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// The host functions of WASI 0.2 interfaces are called by core modules extracted from components, with the
+// arguments and results of the functions lowered to core values following the canonical ABI of the component
+// model. Results that don't fit in a single core value are written to a return area allocated by the module,
+// whose pointer is the last argument, and lists and strings returned to the module are allocated with the
+// cabi_realloc function it exports. Resources are passed as handles to the table of the host.
+
+const (
+	i32 = api.ValueTypeI32
+	i64 = api.ValueTypeI64
+)
+
+var (
+	errInvalidHandle = errors.New("invalid resource handle")
+	errMemoryAccess  = errors.New("out of bounds memory access")
+	errNoRealloc     = errors.New("module doesn't export cabi_realloc, which WASI 0.2 interfaces need")
+	// errStreamClosed is returned by streams that have been closed, which is not a failure of the stream
+	errStreamClosed = errors.New("stream closed")
+)
+
+// wasiFunc is a host function of a WASI 0.2 interface, with the core signature it is lowered to.
+type wasiFunc struct {
+	params  []api.ValueType
+	results []api.ValueType
+	fn      api.GoModuleFunc
+}
+
+func params(types ...api.ValueType) []api.ValueType {
+	return types
+}
+
+// wasiTable holds the resources that modules have handles to. Handles start at 1, as 0 is never a valid handle.
+type wasiTable struct {
+	mtx       sync.Mutex
+	resources map[uint32]any
+	next      uint32
+}
+
+func (t *wasiTable) add(resource any) uint32 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.resources == nil {
+		t.resources = make(map[uint32]any)
+	}
+	t.next++
+	t.resources[t.next] = resource
+	return t.next
+}
+
+func (t *wasiTable) remove(handle uint32) (any, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	resource, found := t.resources[handle]
+	delete(t.resources, handle)
+	return resource, found
+}
+
+// getResource returns the resource of the handle, and traps if there is no resource of that type.
+func getResource[T any](t *wasiTable, handle uint32) T {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	resource, ok := t.resources[handle].(T)
+	if !ok {
+		panic(fmt.Errorf("%w: %d", errInvalidHandle, handle))
+	}
+	return resource
+}
+
+// takeResource removes the resource of the handle from the table, as its ownership is passed to the host,
+// and traps if there is no resource of that type.
+func takeResource[T any](t *wasiTable, handle uint32) T {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	resource, ok := t.resources[handle].(T)
+	if !ok {
+		panic(fmt.Errorf("%w: %d", errInvalidHandle, handle))
+	}
+	delete(t.resources, handle)
+	return resource
+}
+
+// dropResource returns a host function dropping a resource, which closes it if it is an io.Closer.
+func (t *wasiTable) dropResource() wasiFunc {
+	return wasiFunc{params: params(i32), fn: func(_ context.Context, _ api.Module, stack []uint64) {
+		if resource, found := t.remove(api.DecodeU32(stack[0])); found {
+			if closer, ok := resource.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		}
+	}}
+}
+
+// wasiNotifier wakes up modules blocked on pollables whenever the state of a resource changes.
+type wasiNotifier struct {
+	mtx sync.Mutex
+	ch  chan struct{}
+}
+
+func (n *wasiNotifier) changed() <-chan struct{} {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+func (n *wasiNotifier) notify() {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
+
+// wait blocks until ready returns true, or ctx is done.
+func (n *wasiNotifier) wait(ctx context.Context, ready func() bool) error {
+	for {
+		// the channel is taken before checking, so that a change in between isn't missed
+		changed := n.changed()
+		if ready() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// block is wait for host functions, which trap if ctx is done, such as when the execution times out.
+func (n *wasiNotifier) block(ctx context.Context, ready func() bool) {
+	if err := n.wait(ctx, ready); err != nil {
+		panic(err)
+	}
+}
+
+// wasiPollable is a pollable of wasi:io/poll, which is ready once ready returns true.
+type wasiPollable struct {
+	ready func() bool
+}
+
+// wasiError is an error of wasi:io/error.
+type wasiError struct {
+	err error
+}
+
+// wasiInputStream is an input-stream of wasi:io/streams.
+type wasiInputStream interface {
+	// read returns at most n bytes without blocking, which are none if none are available yet. It returns
+	// io.EOF once the stream ended.
+	read(n uint64) ([]byte, error)
+	// ready returns true if read would return bytes or an error
+	ready() bool
+	io.Closer
+}
+
+// wasiOutputStream is an output-stream of wasi:io/streams.
+type wasiOutputStream interface {
+	// checkWrite returns how many bytes can be written without blocking
+	checkWrite() (uint64, error)
+	write(data []byte) error
+	// ready returns true if bytes can be written, or the stream failed
+	ready() bool
+	io.Closer
+}
+
+// wasiIO implements the wasi:io interfaces.
+type wasiIO struct {
+	table    *wasiTable
+	notifier *wasiNotifier
+}
+
+func (w *wasiIO) interfaces() map[string]map[string]wasiFunc {
+	return map[string]map[string]wasiFunc{
+		"wasi:io/error": {
+			"[resource-drop]error":          w.table.dropResource(),
+			"[method]error.to-debug-string": {params: params(i32, i32), fn: w.errorToDebugString},
+		},
+		"wasi:io/poll": {
+			"[resource-drop]pollable": w.table.dropResource(),
+			"[method]pollable.ready":  {params: params(i32), results: params(i32), fn: w.pollableReady},
+			"[method]pollable.block":  {params: params(i32), fn: w.pollableBlock},
+			"poll":                    {params: params(i32, i32, i32), fn: w.poll},
+		},
+		"wasi:io/streams": {
+			"[resource-drop]input-stream":                           w.table.dropResource(),
+			"[method]input-stream.read":                             {params: params(i32, i64, i32), fn: w.read(false)},
+			"[method]input-stream.blocking-read":                    {params: params(i32, i64, i32), fn: w.read(true)},
+			"[method]input-stream.skip":                             {params: params(i32, i64, i32), fn: w.skip(false)},
+			"[method]input-stream.blocking-skip":                    {params: params(i32, i64, i32), fn: w.skip(true)},
+			"[method]input-stream.subscribe":                        {params: params(i32), results: params(i32), fn: w.subscribeInput},
+			"[resource-drop]output-stream":                          w.table.dropResource(),
+			"[method]output-stream.check-write":                     {params: params(i32, i32), fn: w.checkWrite},
+			"[method]output-stream.write":                           {params: params(i32, i32, i32, i32), fn: w.write(false)},
+			"[method]output-stream.blocking-write-and-flush":        {params: params(i32, i32, i32, i32), fn: w.write(true)},
+			"[method]output-stream.flush":                           {params: params(i32, i32), fn: w.flush(false)},
+			"[method]output-stream.blocking-flush":                  {params: params(i32, i32), fn: w.flush(true)},
+			"[method]output-stream.write-zeroes":                    {params: params(i32, i64, i32), fn: w.writeZeroes(false)},
+			"[method]output-stream.blocking-write-zeroes-and-flush": {params: params(i32, i64, i32), fn: w.writeZeroes(true)},
+			"[method]output-stream.splice":                          {params: params(i32, i32, i64, i32), fn: w.splice(false)},
+			"[method]output-stream.blocking-splice":                 {params: params(i32, i32, i64, i32), fn: w.splice(true)},
+			"[method]output-stream.subscribe":                       {params: params(i32), results: params(i32), fn: w.subscribeOutput},
+		},
+	}
+}
+
+func (w *wasiIO) errorToDebugString(ctx context.Context, mod api.Module, stack []uint64) {
+	e := getResource[*wasiError](w.table, api.DecodeU32(stack[0]))
+	writeString(ctx, mod, api.DecodeU32(stack[1]), e.err.Error())
+}
+
+func (w *wasiIO) pollableReady(_ context.Context, _ api.Module, stack []uint64) {
+	pollable := getResource[*wasiPollable](w.table, api.DecodeU32(stack[0]))
+	stack[0] = encodeBool(pollable.ready())
+}
+
+func (w *wasiIO) pollableBlock(ctx context.Context, _ api.Module, stack []uint64) {
+	pollable := getResource[*wasiPollable](w.table, api.DecodeU32(stack[0]))
+	w.notifier.block(ctx, pollable.ready)
+}
+
+// poll blocks until at least one of the pollables is ready, and returns the indexes of the ready ones
+func (w *wasiIO) poll(ctx context.Context, mod api.Module, stack []uint64) {
+	handles := readBytes(mod, api.DecodeU32(stack[0]), 4*api.DecodeU32(stack[1]))
+	pollables := make([]*wasiPollable, 0, len(handles)/4)
+	for i := 0; i < len(handles); i += 4 {
+		pollables = append(pollables, getResource[*wasiPollable](w.table, leUint32(handles[i:])))
+	}
+	var ready []byte
+	w.notifier.block(ctx, func() bool {
+		ready = ready[:0]
+		for i, pollable := range pollables {
+			if pollable.ready() {
+				ready = appendUint32(ready, uint32(i))
+			}
+		}
+		return len(ready) > 0
+	})
+	writeList(ctx, mod, api.DecodeU32(stack[2]), 4, ready, len(ready)/4)
+}
+
+func (w *wasiIO) read(blocking bool) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		stream := getResource[wasiInputStream](w.table, api.DecodeU32(stack[0]))
+		if blocking {
+			w.notifier.block(ctx, stream.ready)
+		}
+		data, err := stream.read(stack[1])
+		ret := api.DecodeU32(stack[2])
+		if err != nil {
+			writeU8(mod, ret, 1)
+			w.writeStreamError(mod, ret+4, err)
+			return
+		}
+		writeU8(mod, ret, 0)
+		writeList(ctx, mod, ret+4, 1, data, len(data))
+	}
+}
+
+func (w *wasiIO) skip(blocking bool) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		stream := getResource[wasiInputStream](w.table, api.DecodeU32(stack[0]))
+		if blocking {
+			w.notifier.block(ctx, stream.ready)
+		}
+		data, err := stream.read(stack[1])
+		ret := api.DecodeU32(stack[2])
+		if err != nil {
+			// the payload of result<u64, stream-error> is aligned to 8 bytes
+			w.writeStreamError(mod, ret+8, err)
+			writeU8(mod, ret, 1)
+			return
+		}
+		writeU8(mod, ret, 0)
+		writeU64(mod, ret+8, uint64(len(data)))
+	}
+}
+
+func (w *wasiIO) subscribeInput(_ context.Context, _ api.Module, stack []uint64) {
+	stream := getResource[wasiInputStream](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(&wasiPollable{ready: stream.ready}))
+}
+
+func (w *wasiIO) checkWrite(_ context.Context, mod api.Module, stack []uint64) {
+	stream := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+	ret := api.DecodeU32(stack[1])
+	permit, err := stream.checkWrite()
+	if err != nil {
+		w.writeStreamError(mod, ret+8, err)
+		writeU8(mod, ret, 1)
+		return
+	}
+	writeU8(mod, ret, 0)
+	writeU64(mod, ret+8, permit)
+}
+
+// write writes to the stream. Writes that block wait until the stream accepts the data, which is then flushed,
+// as streams of the host accept data once it is on its way.
+func (w *wasiIO) write(blocking bool) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		stream := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+		data := readBytes(mod, api.DecodeU32(stack[1]), api.DecodeU32(stack[2]))
+		w.writeResult(ctx, mod, api.DecodeU32(stack[3]), stream, data, blocking)
+	}
+}
+
+func (w *wasiIO) writeZeroes(blocking bool) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		stream := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+		w.writeResult(ctx, mod, api.DecodeU32(stack[2]), stream, make([]byte, min(stack[1], httpBodyChunkSize)), blocking)
+	}
+}
+
+func (w *wasiIO) writeResult(ctx context.Context, mod api.Module, ret uint32, stream wasiOutputStream, data []byte, blocking bool) {
+	if blocking {
+		w.notifier.block(ctx, func() bool {
+			permit, err := stream.checkWrite()
+			return err != nil || permit >= uint64(len(data))
+		})
+	}
+	if err := stream.write(data); err != nil {
+		writeU8(mod, ret, 1)
+		w.writeStreamError(mod, ret+4, err)
+		return
+	}
+	writeU8(mod, ret, 0)
+}
+
+// flush only reports whether the stream failed, as streams of the host accept data once it is on its way.
+func (w *wasiIO) flush(bool) api.GoModuleFunc {
+	return func(_ context.Context, mod api.Module, stack []uint64) {
+		stream := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+		ret := api.DecodeU32(stack[1])
+		if _, err := stream.checkWrite(); err != nil {
+			writeU8(mod, ret, 1)
+			w.writeStreamError(mod, ret+4, err)
+			return
+		}
+		writeU8(mod, ret, 0)
+	}
+}
+
+func (w *wasiIO) splice(blocking bool) api.GoModuleFunc {
+	return func(ctx context.Context, mod api.Module, stack []uint64) {
+		output := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+		input := getResource[wasiInputStream](w.table, api.DecodeU32(stack[1]))
+		ret := api.DecodeU32(stack[3])
+		if blocking {
+			w.notifier.block(ctx, input.ready)
+			w.notifier.block(ctx, output.ready)
+		}
+		permit, err := output.checkWrite()
+		var data []byte
+		if err == nil {
+			data, err = input.read(min(stack[2], permit))
+		}
+		if err == nil {
+			err = output.write(data)
+		}
+		if err != nil {
+			writeU8(mod, ret, 1)
+			w.writeStreamError(mod, ret+8, err)
+			return
+		}
+		writeU8(mod, ret, 0)
+		writeU64(mod, ret+8, uint64(len(data)))
+	}
+}
+
+func (w *wasiIO) subscribeOutput(_ context.Context, _ api.Module, stack []uint64) {
+	stream := getResource[wasiOutputStream](w.table, api.DecodeU32(stack[0]))
+	stack[0] = api.EncodeU32(w.table.add(&wasiPollable{ready: stream.ready}))
+}
+
+// writeStreamError writes a stream-error, which is closed once the stream ended, or last-operation-failed
+// with an error resource otherwise.
+func (w *wasiIO) writeStreamError(mod api.Module, ptr uint32, err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, errStreamClosed) {
+		writeU8(mod, ptr, 1)
+		return
+	}
+	writeU8(mod, ptr, 0)
+	writeU32(mod, ptr+4, w.table.add(&wasiError{err: err}))
+}
+
+func readBytes(mod api.Module, ptr, length uint32) []byte {
+	data, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		panic(errMemoryAccess)
+	}
+	// the memory of the module can change once the host function returns
+	return append([]byte(nil), data...)
+}
+
+func readString(mod api.Module, ptr, length uint32) string {
+	return string(readBytes(mod, ptr, length))
+}
+
+func writeU8(mod api.Module, ptr uint32, value byte) {
+	if !mod.Memory().WriteByte(ptr, value) {
+		panic(errMemoryAccess)
+	}
+}
+
+func writeU16(mod api.Module, ptr uint32, value uint16) {
+	if !mod.Memory().WriteUint16Le(ptr, value) {
+		panic(errMemoryAccess)
+	}
+}
+
+func writeU32(mod api.Module, ptr uint32, value uint32) {
+	if !mod.Memory().WriteUint32Le(ptr, value) {
+		panic(errMemoryAccess)
+	}
+}
+
+func writeU64(mod api.Module, ptr uint32, value uint64) {
+	if !mod.Memory().WriteUint64Le(ptr, value) {
+		panic(errMemoryAccess)
+	}
+}
+
+// allocate allocates memory in the module with the cabi_realloc function it exports
+func allocate(ctx context.Context, mod api.Module, align, size uint32) uint32 {
+	realloc := mod.ExportedFunction("cabi_realloc")
+	if realloc == nil {
+		panic(errNoRealloc)
+	}
+	results, err := realloc.Call(ctx, 0, 0, api.EncodeU32(align), api.EncodeU32(size))
+	if err != nil {
+		panic(err)
+	}
+	return api.DecodeU32(results[0])
+}
+
+// writeList allocates the encoded elements of a list in the module, and writes its pointer and length at ptr
+func writeList(ctx context.Context, mod api.Module, ptr, align uint32, data []byte, length int) {
+	address := allocate(ctx, mod, align, uint32(len(data)))
+	if !mod.Memory().Write(address, data) {
+		panic(errMemoryAccess)
+	}
+	writeU32(mod, ptr, address)
+	writeU32(mod, ptr+4, uint32(length))
+}
+
+func writeString(ctx context.Context, mod api.Module, ptr uint32, value string) {
+	writeList(ctx, mod, ptr, 1, []byte(value), len(value))
+}
+
+func encodeBool(value bool) uint64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func leUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+func appendUint32(b []byte, value uint32) []byte {
+	return append(b, byte(value), byte(value>>8), byte(value>>16), byte(value>>24))
+}
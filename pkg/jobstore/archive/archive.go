@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// FileExtension is the extension of archived jobs, which are stored as gzip compressed JSON documents
+const FileExtension = ".json.gz"
+
+// ArchivedJob is a job that was removed from the job store, along with its executions and history
+type ArchivedJob struct {
+	Job         models.Job          `json:"Job"`
+	Executions  []models.Execution  `json:"Executions"`
+	History     []models.JobHistory `json:"History"`
+	ArchiveTime int64               `json:"ArchiveTime"`
+}
+
+// Archive holds jobs that were removed from the job store so that they can still be described.
+type Archive interface {
+	// Put archives the job, replacing any previous archive of the same job
+	Put(ctx context.Context, job ArchivedJob) error
+
+	// Get retrieves an archived job by its full or short ID. It returns a
+	// bacerrors.JobNotFound error if the job was not archived.
+	Get(ctx context.Context, jobID string) (ArchivedJob, error)
+}
+
+// GetHistory returns the history of the archived job that matches the filter options,
+// the same way jobstore.Store.GetJobHistory does for jobs in the store.
+func (a ArchivedJob) GetHistory(options jobstore.JobHistoryFilterOptions) []models.JobHistory {
+	history := lo.Filter(a.History, func(event models.JobHistory, _ int) bool {
+		if options.ExcludeJobLevel && event.Type == models.JobHistoryTypeJobLevel {
+			return false
+		}
+		if options.ExcludeExecutionLevel && event.Type == models.JobHistoryTypeExecutionLevel {
+			return false
+		}
+		if options.ExecutionID != "" && !strings.HasPrefix(event.ExecutionID, options.ExecutionID) {
+			return false
+		}
+		if options.NodeID != "" && !strings.HasPrefix(event.NodeID, options.NodeID) {
+			return false
+		}
+		return event.Time.Unix() >= options.Since
+	})
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+	return history
+}
+
+// encode serializes the archived job to compressed JSON
+func encode(job ArchivedJob) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(writer).Encode(job); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode deserializes an archived job from compressed JSON
+func decode(r io.Reader) (ArchivedJob, error) {
+	var job ArchivedJob
+	reader, err := gzip.NewReader(r)
+	if err != nil {
+		return job, err
+	}
+	defer reader.Close()
+
+	err = json.NewDecoder(reader).Decode(&job)
+	return job, err
+}
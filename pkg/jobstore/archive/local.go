@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+const (
+	archiveDirPermissions  = 0755
+	archiveFilePermissions = 0600
+)
+
+// LocalArchive archives each job to a compressed file named after the job in a local directory
+type LocalArchive struct {
+	dir string
+}
+
+// NewLocalArchive creates an archive in the given directory, creating the directory if needed
+func NewLocalArchive(dir string) (*LocalArchive, error) {
+	if dir == "" {
+		return nil, errors.New("job archive directory is missing")
+	}
+	if err := os.MkdirAll(dir, archiveDirPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create job archive directory %s: %w", dir, err)
+	}
+	return &LocalArchive{dir: dir}, nil
+}
+
+// Put archives the job, replacing any previous archive of the same job
+func (a *LocalArchive) Put(ctx context.Context, job ArchivedJob) error {
+	path, err := a.path(job.Job.ID)
+	if err != nil {
+		return err
+	}
+	data, err := encode(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive of job %s: %w", job.Job.ID, err)
+	}
+
+	// write to a temporary file first so that a partially written archive is never read
+	tmp, err := os.CreateTemp(a.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), archiveFilePermissions); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get retrieves an archived job by its full or short ID
+func (a *LocalArchive) Get(ctx context.Context, jobID string) (ArchivedJob, error) {
+	path, err := a.resolve(jobID)
+	if err != nil {
+		return ArchivedJob{}, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ArchivedJob{}, bacerrors.NewJobNotFound(jobID)
+	} else if err != nil {
+		return ArchivedJob{}, err
+	}
+	defer file.Close()
+
+	job, err := decode(file)
+	if err != nil {
+		return ArchivedJob{}, fmt.Errorf("failed to decode archive of job %s: %w", jobID, err)
+	}
+	return job, nil
+}
+
+// resolve returns the path of the archive of the job, resolving short IDs to a single archived job
+func (a *LocalArchive) resolve(jobID string) (string, error) {
+	if idgen.ShortUUID(jobID) != jobID {
+		return a.path(jobID)
+	}
+
+	prefix, err := a.path(jobID)
+	if err != nil {
+		return "", err
+	}
+	matches, err := filepath.Glob(strings.TrimSuffix(prefix, FileExtension) + "*" + FileExtension)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", bacerrors.NewJobNotFound(jobID)
+	case 1:
+		return matches[0], nil
+	default:
+		found := make([]string, len(matches))
+		for i, match := range matches {
+			found[i] = strings.TrimSuffix(filepath.Base(match), FileExtension)
+		}
+		return "", bacerrors.NewMultipleJobsFound(jobID, found)
+	}
+}
+
+func (a *LocalArchive) path(jobID string) (string, error) {
+	if jobID == "" || filepath.Base(jobID) != jobID || strings.ContainsAny(jobID, `*?[\`) {
+		return "", fmt.Errorf("invalid job ID %q", jobID)
+	}
+	return filepath.Join(a.dir, jobID+FileExtension), nil
+}
+
+// Static check to ensure that LocalArchive implements Archive
+var _ Archive = (*LocalArchive)(nil)
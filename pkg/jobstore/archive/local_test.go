@@ -0,0 +1,97 @@
+//go:build unit || !integration
+
+package archive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type LocalArchiveTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	archive *LocalArchive
+}
+
+func TestLocalArchiveTestSuite(t *testing.T) {
+	suite.Run(t, new(LocalArchiveTestSuite))
+}
+
+func (s *LocalArchiveTestSuite) SetupTest() {
+	var err error
+	s.ctx = context.Background()
+	s.archive, err = NewLocalArchive(s.T().TempDir())
+	s.Require().NoError(err)
+}
+
+func (s *LocalArchiveTestSuite) archivedJob(id string) ArchivedJob {
+	job := mock.Job()
+	job.ID = id
+	execution := mock.ExecutionForJob(job)
+	return ArchivedJob{
+		Job:        *job,
+		Executions: []models.Execution{*execution},
+		History: []models.JobHistory{
+			{Type: models.JobHistoryTypeExecutionLevel, JobID: id, ExecutionID: execution.ID, Time: time.Unix(2, 0)},
+			{Type: models.JobHistoryTypeJobLevel, JobID: id, Time: time.Unix(1, 0)},
+		},
+		ArchiveTime: time.Now().UnixNano(),
+	}
+}
+
+func (s *LocalArchiveTestSuite) TestPutGet() {
+	job := s.archivedJob("j-9308d0d2-d93c-4e22-8a5b-c392e614922e")
+	s.Require().NoError(s.archive.Put(s.ctx, job))
+
+	archived, err := s.archive.Get(s.ctx, job.Job.ID)
+	s.Require().NoError(err)
+	s.Equal(job.Job.ID, archived.Job.ID)
+	s.Equal(job.Executions[0].ID, archived.Executions[0].ID)
+	s.Len(archived.History, 2)
+
+	// replacing the archive of a job
+	job.Job.Name = "renamed"
+	s.Require().NoError(s.archive.Put(s.ctx, job))
+	archived, err = s.archive.Get(s.ctx, job.Job.ID)
+	s.Require().NoError(err)
+	s.Equal("renamed", archived.Job.Name)
+}
+
+func (s *LocalArchiveTestSuite) TestShortIDs() {
+	_, err := s.archive.Get(s.ctx, "j-9308d0d2")
+	s.ErrorAs(err, new(*bacerrors.JobNotFound))
+
+	s.Require().NoError(s.archive.Put(s.ctx, s.archivedJob("j-9308d0d2-d93c-4e22-8a5b-c392e614922e")))
+	archived, err := s.archive.Get(s.ctx, "j-9308d0d2")
+	s.Require().NoError(err)
+	s.Equal("j-9308d0d2-d93c-4e22-8a5b-c392e614922e", archived.Job.ID)
+
+	s.Require().NoError(s.archive.Put(s.ctx, s.archivedJob("j-9308d0d2-d93c-4e22-8a5b-c392e614922f")))
+	_, err = s.archive.Get(s.ctx, "j-9308d0d2")
+	s.ErrorAs(err, new(*bacerrors.MultipleJobsFound))
+}
+
+func (s *LocalArchiveTestSuite) TestInvalidJobID() {
+	s.Error(s.archive.Put(s.ctx, s.archivedJob("../escape")))
+	_, err := s.archive.Get(s.ctx, "../escape")
+	s.Error(err)
+}
+
+func (s *LocalArchiveTestSuite) TestGetHistory() {
+	job := s.archivedJob("j-1")
+	history := job.GetHistory(jobstore.JobHistoryFilterOptions{})
+	s.Require().Len(history, 2)
+	s.Equal(models.JobHistoryTypeJobLevel, history[0].Type)
+
+	s.Len(job.GetHistory(jobstore.JobHistoryFilterOptions{ExcludeJobLevel: true}), 1)
+	s.Len(job.GetHistory(jobstore.JobHistoryFilterOptions{ExcludeExecutionLevel: true}), 1)
+	s.Len(job.GetHistory(jobstore.JobHistoryFilterOptions{Since: 2}), 1)
+}
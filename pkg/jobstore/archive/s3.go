@@ -0,0 +1,135 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+type S3ArchiveParams struct {
+	ClientProvider *s3helper.ClientProvider
+	Bucket         string
+	// Prefix is prepended to the key of each archived job
+	Prefix   string
+	Endpoint string
+	Region   string
+}
+
+// S3Archive archives each job to a compressed object named after the job in an S3 bucket
+type S3Archive struct {
+	client *s3helper.ClientWrapper
+	bucket string
+	prefix string
+}
+
+func NewS3Archive(params S3ArchiveParams) (*S3Archive, error) {
+	if params.ClientProvider == nil {
+		return nil, errors.New("s3 client provider is missing")
+	}
+	if params.Bucket == "" {
+		return nil, errors.New("job archive bucket is missing")
+	}
+	return &S3Archive{
+		client: params.ClientProvider.GetClient(params.Endpoint, params.Region),
+		bucket: params.Bucket,
+		prefix: params.Prefix,
+	}, nil
+}
+
+// Put archives the job, replacing any previous archive of the same job
+func (a *S3Archive) Put(ctx context.Context, job ArchivedJob) error {
+	data, err := encode(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive of job %s: %w", job.Job.ID, err)
+	}
+	_, err = a.client.S3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(a.key(job.Job.ID)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload archive of job %s: %w", job.Job.ID, err)
+	}
+	return nil
+}
+
+// Get retrieves an archived job by its full or short ID
+func (a *S3Archive) Get(ctx context.Context, jobID string) (ArchivedJob, error) {
+	key, err := a.resolve(ctx, jobID)
+	if err != nil {
+		return ArchivedJob{}, err
+	}
+
+	resp, err := a.client.S3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return ArchivedJob{}, bacerrors.NewJobNotFound(jobID)
+		}
+		return ArchivedJob{}, fmt.Errorf("failed to download archive of job %s: %w", jobID, err)
+	}
+	defer resp.Body.Close()
+
+	job, err := decode(resp.Body)
+	if err != nil {
+		return ArchivedJob{}, fmt.Errorf("failed to decode archive of job %s: %w", jobID, err)
+	}
+	return job, nil
+}
+
+// resolve returns the key of the archive of the job, resolving short IDs to a single archived job
+func (a *S3Archive) resolve(ctx context.Context, jobID string) (string, error) {
+	if idgen.ShortUUID(jobID) != jobID {
+		return a.key(jobID), nil
+	}
+
+	var found []string
+	paginator := s3.NewListObjectsV2Paginator(a.client.S3, &s3.ListObjectsV2Input{
+		Bucket: aws.String(a.bucket),
+		Prefix: aws.String(a.prefix + jobID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list archived jobs: %w", err)
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if strings.HasSuffix(key, FileExtension) {
+				found = append(found, strings.TrimSuffix(strings.TrimPrefix(key, a.prefix), FileExtension))
+			}
+		}
+	}
+	sort.Strings(found)
+
+	switch len(found) {
+	case 0:
+		return "", bacerrors.NewJobNotFound(jobID)
+	case 1:
+		return a.key(found[0]), nil
+	default:
+		return "", bacerrors.NewMultipleJobsFound(jobID, found)
+	}
+}
+
+func (a *S3Archive) key(jobID string) string {
+	return a.prefix + jobID + FileExtension
+}
+
+// Static check to ensure that S3Archive implements Archive
+var _ Archive = (*S3Archive)(nil)
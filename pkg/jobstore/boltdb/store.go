@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -30,12 +31,20 @@ const (
 	BucketJobEvaluations   = "evaluations"
 	BucketJobHistory       = "job_history"
 	BucketExecutionHistory = "execution_history"
-
-	BucketTagsIndex        = "idx_tags"        // tag -> Job id
-	BucketProgressIndex    = "idx_inprogress"  // job-id -> {}
-	BucketNamespacesIndex  = "idx_namespaces"  // namespace -> Job id
-	BucketExecutionsIndex  = "idx_executions"  // execution-id -> Job id
-	BucketEvaluationsIndex = "idx_evaluations" // evaluation-id -> Job id
+	BucketNamespaceQuotas  = "namespace_quotas"
+	BucketResultCache      = "result_cache"
+	BucketSecrets          = "secrets"
+
+	BucketTagsIndex              = "idx_tags"                 // tag -> Job id
+	BucketProgressIndex          = "idx_inprogress"           // job-id -> {}
+	BucketNamespaceProgressIndex = "idx_namespace_inprogress" // namespace -> in progress Job id
+	BucketNamespacesIndex        = "idx_namespaces"           // namespace -> Job id
+	BucketExecutionsIndex        = "idx_executions"           // execution-id -> Job id
+	BucketNodeExecutionsIndex    = "idx_node_executions"      // node-id -> active Execution id
+	BucketScheduledRunsIndex     = "idx_scheduled_runs"       // scheduled Job id -> run Job id
+	BucketDependentsIndex        = "idx_dependents"           // upstream Job id -> dependent Job id
+	BucketEvaluationsIndex       = "idx_evaluations"          // evaluation-id -> Job id
+	BucketPendingEvalsIndex      = "idx_pending_evaluations"  // pending Evaluation id
 )
 
 var SpecKey = []byte("spec")
@@ -47,11 +56,16 @@ type BoltJobStore struct {
 	watchers    []*jobstore.Watcher
 	watcherLock sync.Mutex
 
-	inProgressIndex  *Index
-	namespacesIndex  *Index
-	tagsIndex        *Index
-	executionsIndex  *Index
-	evaluationsIndex *Index
+	inProgressIndex          *Index
+	namespaceInProgressIndex *Index
+	namespacesIndex          *Index
+	tagsIndex                *Index
+	executionsIndex          *Index
+	nodeExecutionsIndex      *Index
+	scheduledRunsIndex       *Index
+	dependentsIndex          *Index
+	evaluationsIndex         *Index
+	pendingEvalsIndex        *Index
 }
 
 type Option func(store *BoltJobStore)
@@ -78,11 +92,15 @@ func WithClock(clock clock.Clock) Option {
 //
 // Indexes are structured as :
 //
-//	TagsIndex        = tag -> Job id
-//	ProgressIndex    = job-id -> {}
-//	NamespacesIndex  = namespace -> Job id
-//	ExecutionsIndex  = execution-id -> Job id
-//	EvaluationsIndex = evaluation-id -> Job id
+//	TagsIndex           = tag -> Job id
+//	ProgressIndex       = job-id -> {}
+//	NamespacesIndex     = namespace -> Job id
+//	ExecutionsIndex     = execution-id -> Job id
+//	NodeExecutionsIndex = node-id -> active Execution id
+//	ScheduledRunsIndex  = scheduled Job id -> run Job id
+//	DependentsIndex     = upstream Job id -> dependent Job id
+//	EvaluationsIndex    = evaluation-id -> Job id
+//	PendingEvalsIndex   = pending Evaluation id
 func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 	db, err := GetDatabase(dbPath)
 	if err != nil {
@@ -100,21 +118,60 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 		opt(store)
 	}
 
+	store.inProgressIndex = NewIndex(BucketProgressIndex)
+	store.namespaceInProgressIndex = NewIndex(BucketNamespaceProgressIndex)
+	store.namespacesIndex = NewIndex(BucketNamespacesIndex)
+	store.tagsIndex = NewIndex(BucketTagsIndex)
+	store.executionsIndex = NewIndex(BucketExecutionsIndex)
+	store.nodeExecutionsIndex = NewIndex(BucketNodeExecutionsIndex)
+	store.scheduledRunsIndex = NewIndex(BucketScheduledRunsIndex)
+	store.dependentsIndex = NewIndex(BucketDependentsIndex)
+	store.evaluationsIndex = NewIndex(BucketEvaluationsIndex)
+	store.pendingEvalsIndex = NewIndex(BucketPendingEvalsIndex)
+
 	// Create the top level buckets ready for use as they
 	// will definitely be required
 	err = db.Update(func(tx *bolt.Tx) (err error) {
+		// Stores created before the namespace in progress index was added need it built
+		// from the in progress index, so that their running jobs count towards namespace quotas
+		backfill := tx.Bucket([]byte(BucketNamespaceProgressIndex)) == nil
+		// and the node executions index built from the executions of in progress jobs
+		backfillNodes := tx.Bucket([]byte(BucketNodeExecutionsIndex)) == nil
+		// and the pending evaluations index built from the evaluations of all jobs
+		backfillEvals := tx.Bucket([]byte(BucketPendingEvalsIndex)) == nil
+
 		// Create the top level jobs bucket, and the
 		_, err = tx.CreateBucketIfNotExists([]byte(BucketJobs))
 		if err != nil {
 			return err
 		}
 
+		_, err = tx.CreateBucketIfNotExists([]byte(BucketNamespaceQuotas))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists([]byte(BucketResultCache))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists([]byte(BucketSecrets))
+		if err != nil {
+			return err
+		}
+
 		indexBuckets := []string{
 			BucketTagsIndex,
 			BucketProgressIndex,
+			BucketNamespaceProgressIndex,
 			BucketNamespacesIndex,
 			BucketExecutionsIndex,
+			BucketNodeExecutionsIndex,
+			BucketScheduledRunsIndex,
+			BucketDependentsIndex,
 			BucketEvaluationsIndex,
+			BucketPendingEvalsIndex,
 		}
 		for _, ib := range indexBuckets {
 			_, err = tx.CreateBucketIfNotExists([]byte(ib))
@@ -123,18 +180,82 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			}
 		}
 
+		if backfill {
+			if err = store.backfillNamespaceInProgressIndex(tx); err != nil {
+				return err
+			}
+		}
+		if backfillNodes {
+			if err = store.backfillNodeExecutionsIndex(tx); err != nil {
+				return err
+			}
+		}
+		if backfillEvals {
+			return store.backfillPendingEvalsIndex(tx)
+		}
 		return nil
 	})
 
-	store.inProgressIndex = NewIndex(BucketProgressIndex)
-	store.namespacesIndex = NewIndex(BucketNamespacesIndex)
-	store.tagsIndex = NewIndex(BucketTagsIndex)
-	store.executionsIndex = NewIndex(BucketExecutionsIndex)
-	store.evaluationsIndex = NewIndex(BucketEvaluationsIndex)
-
 	return store, err
 }
 
+// backfillNamespaceInProgressIndex adds the jobs of the in progress index to the namespace in progress index
+func (b *BoltJobStore) backfillNamespaceInProgressIndex(tx *bolt.Tx) error {
+	jobs, err := b.getInProgressJobs(tx, "")
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if err = b.namespaceInProgressIndex.Add(tx, []byte(jobs[i].ID), []byte(jobs[i].Namespace)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillNodeExecutionsIndex adds the active executions of in progress jobs to the node executions index
+func (b *BoltJobStore) backfillNodeExecutionsIndex(tx *bolt.Tx) error {
+	jobs, err := b.getInProgressJobs(tx, "")
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		executions, err := b.getExecutions(tx, jobstore.GetExecutionsOptions{JobID: jobs[i].ID})
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		for j := range executions {
+			if err = b.updateNodeExecutionsIndex(tx, nil, &executions[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backfillPendingEvalsIndex adds the pending evaluations of all jobs to the pending evaluations index
+func (b *BoltJobStore) backfillPendingEvalsIndex(tx *bolt.Tx) error {
+	jobsBkt, err := NewBucketPath(BucketJobs).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return jobsBkt.ForEach(func(jobID []byte, _ []byte) error {
+		evals, err := b.getEvaluations(tx, string(jobID))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		for i := range evals {
+			if evals[i].Status != models.EvalStatusPending {
+				continue
+			}
+			if err = b.pendingEvalsIndex.Add(tx, []byte(evals[i].ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (b *BoltJobStore) Watch(ctx context.Context,
 	types jobstore.StoreWatcherType,
 	events jobstore.StoreEventType) chan jobstore.WatchEvent {
@@ -533,6 +654,34 @@ func (b *BoltJobStore) GetExecutions(ctx context.Context, options jobstore.GetEx
 	return state, err
 }
 
+// GetExecutionsForJobs gets the executions of the jobs with the given full IDs, keyed by job ID
+func (b *BoltJobStore) GetExecutionsForJobs(ctx context.Context, jobIDs []string) (map[string][]models.Execution, error) {
+	executions := make(map[string][]models.Execution, len(jobIDs))
+	err := b.database.View(func(tx *bolt.Tx) error {
+		for _, jobID := range jobIDs {
+			bkt, err := NewBucketPath(BucketJobs, jobID, BucketJobExecutions).Get(tx, false)
+			if errors.Is(err, bolt.ErrBucketNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			err = bkt.ForEach(func(_ []byte, v []byte) error {
+				var execution models.Execution
+				if err := b.marshaller.Unmarshal(v, &execution); err != nil {
+					return err
+				}
+				executions[jobID] = append(executions[jobID], execution)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return executions, err
+}
+
 // GetInProgressJobs gets a list of the currently in-progress jobs, if a job type is supplied then
 // only jobs of that type will be retrieved
 func (b *BoltJobStore) GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error) {
@@ -571,6 +720,108 @@ func (b *BoltJobStore) getInProgressJobs(tx *bolt.Tx, jobType string) ([]models.
 	return infos, nil
 }
 
+// GetInProgressJobsInNamespace gets the jobs of a namespace that are not in a terminal state
+func (b *BoltJobStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error) {
+	var infos []models.Job
+	err := b.database.View(func(tx *bolt.Tx) error {
+		ids, err := b.namespaceInProgressIndex.List(tx, []byte(namespace))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			job, err := b.getJob(tx, string(id))
+			if err != nil {
+				return err
+			}
+			infos = append(infos, job)
+		}
+		return nil
+	})
+	return infos, err
+}
+
+// GetActiveExecutionsOnNode gets the executions placed on a node that are not in a terminal state
+func (b *BoltJobStore) GetActiveExecutionsOnNode(ctx context.Context, nodeID string) ([]models.Execution, error) {
+	var executions []models.Execution
+	err := b.database.View(func(tx *bolt.Tx) error {
+		ids, err := b.nodeExecutionsIndex.List(tx, []byte(nodeID))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			execution, err := b.getExecution(tx, string(id))
+			if err != nil {
+				return err
+			}
+			executions = append(executions, execution)
+		}
+		return nil
+	})
+	return executions, err
+}
+
+// GetDependentJobs gets the jobs that depend on an upstream job
+func (b *BoltJobStore) GetDependentJobs(ctx context.Context, upstreamJobID string) ([]models.Job, error) {
+	var dependents []models.Job
+	err := b.database.View(func(tx *bolt.Tx) error {
+		ids, err := b.dependentsIndex.List(tx, []byte(upstreamJobID))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			dependent, err := b.getJob(tx, string(id))
+			if err != nil {
+				return err
+			}
+			dependents = append(dependents, dependent)
+		}
+		return nil
+	})
+	return dependents, err
+}
+
+// GetScheduledRuns gets the runs spawned by a scheduled job, sorted by creation time
+func (b *BoltJobStore) GetScheduledRuns(ctx context.Context, jobID string) ([]models.Job, error) {
+	var runs []models.Job
+	err := b.database.View(func(tx *bolt.Tx) error {
+		ids, err := b.scheduledRunsIndex.List(tx, []byte(jobID))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			run, err := b.getJob(tx, string(id))
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].CreateTime < runs[j].CreateTime })
+	return runs, err
+}
+
+// isNodeIndexed returns true if the execution belongs in the node executions index, which only holds
+// the executions placed on a node that are not in a terminal state.
+func isNodeIndexed(execution *models.Execution) bool {
+	return execution != nil && execution.NodeID != "" && !execution.IsTerminalComputeState()
+}
+
+// updateNodeExecutionsIndex adds an execution to the node executions index, or removes it once it reaches
+// a terminal state. previous is nil for new executions, and updated is nil for deleted ones.
+func (b *BoltJobStore) updateNodeExecutionsIndex(tx *bolt.Tx, previous, updated *models.Execution) error {
+	if isNodeIndexed(previous) && !isNodeIndexed(updated) {
+		err := b.nodeExecutionsIndex.Remove(tx, []byte(previous.ID), []byte(previous.NodeID))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+	}
+	if !isNodeIndexed(previous) && isNodeIndexed(updated) {
+		return b.nodeExecutionsIndex.Add(tx, []byte(updated.ID), []byte(updated.NodeID))
+	}
+	return nil
+}
+
 // splitInProgressIndexKey returns the job type and the job index from
 // the in-progress index key. If no delimiter is found, then this index
 // was created before this feature was implemented, and we are unable
@@ -744,10 +995,26 @@ func (b *BoltJobStore) createJob(tx *bolt.Tx, job models.Job, event models.Event
 		return err
 	}
 
+	if err = b.namespaceInProgressIndex.Add(tx, jobIDKey, []byte(job.Namespace)); err != nil {
+		return err
+	}
+
 	if err = b.namespacesIndex.Add(tx, jobIDKey, []byte(job.Namespace)); err != nil {
 		return err
 	}
 
+	if scheduledBy := job.ScheduledBy(); scheduledBy != "" {
+		if err = b.scheduledRunsIndex.Add(tx, jobIDKey, []byte(scheduledBy)); err != nil {
+			return err
+		}
+	}
+
+	for _, dependency := range job.DependsOn {
+		if err = b.dependentsIndex.Add(tx, jobIDKey, []byte(dependency.JobID)); err != nil {
+			return err
+		}
+	}
+
 	// Write sentinels keys for specific tags
 	for tag := range job.Labels {
 		tagBytes := []byte(strings.ToLower(tag))
@@ -778,6 +1045,28 @@ func (b *BoltJobStore) deleteJob(tx *bolt.Tx, jobID string) error {
 		b.triggerEvent(jobstore.JobWatcher, jobstore.DeleteEvent, job)
 	})
 
+	// Remove the job's executions from the node executions index before deleting them
+	executions, err := b.getExecutions(tx, jobstore.GetExecutionsOptions{JobID: job.ID})
+	if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+		return err
+	}
+	for i := range executions {
+		if err = b.updateNodeExecutionsIndex(tx, &executions[i], nil); err != nil {
+			return err
+		}
+	}
+
+	// Remove the job's evaluations from the pending evaluations index before deleting them
+	evals, err := b.getEvaluations(tx, job.ID)
+	if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+		return err
+	}
+	for i := range evals {
+		if err = b.pendingEvalsIndex.Remove(tx, []byte(evals[i].ID)); err != nil {
+			return err
+		}
+	}
+
 	// Delete the Job bucket (and everything within it)
 	if bkt, err := NewBucketPath(BucketJobs).Get(tx, false); err != nil {
 		return err
@@ -796,10 +1085,28 @@ func (b *BoltJobStore) deleteJob(tx *bolt.Tx, jobID string) error {
 		return err
 	}
 
+	if err = b.removeFromNamespaceInProgressIndex(tx, &job); err != nil {
+		return err
+	}
+
 	if err = b.namespacesIndex.Remove(tx, jobIDKey, []byte(job.Namespace)); err != nil {
 		return err
 	}
 
+	if scheduledBy := job.ScheduledBy(); scheduledBy != "" {
+		err = b.scheduledRunsIndex.Remove(tx, jobIDKey, []byte(scheduledBy))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+	}
+
+	for _, dependency := range job.DependsOn {
+		err = b.dependentsIndex.Remove(tx, jobIDKey, []byte(dependency.JobID))
+		if err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+	}
+
 	// Delete sentinels keys for specific tags
 	for tag := range job.Labels {
 		tagBytes := []byte(strings.ToLower(tag))
@@ -808,7 +1115,7 @@ func (b *BoltJobStore) deleteJob(tx *bolt.Tx, jobID string) error {
 		}
 	}
 
-	return nil
+	return b.deleteResultCacheEntries(tx, job.Namespace, job.ID)
 }
 
 // UpdateJobState updates the current state for a single Job, appending an entry to
@@ -873,11 +1180,25 @@ func (b *BoltJobStore) updateJobState(tx *bolt.Tx, request jobstore.UpdateJobSta
 		if err != nil {
 			return err
 		}
+
+		if err = b.removeFromNamespaceInProgressIndex(tx, &job); err != nil {
+			return err
+		}
 	}
 
 	return b.appendJobHistory(tx, job, previousState, request.Event)
 }
 
+// removeFromNamespaceInProgressIndex removes a job from the namespace in progress index,
+// which does not have a bucket for the job's namespace if none of its jobs are in progress.
+func (b *BoltJobStore) removeFromNamespaceInProgressIndex(tx *bolt.Tx, job *models.Job) error {
+	err := b.namespaceInProgressIndex.Remove(tx, []byte(job.ID), []byte(job.Namespace))
+	if errors.Is(err, bolt.ErrBucketNotFound) {
+		return nil
+	}
+	return err
+}
+
 func (b *BoltJobStore) appendJobHistory(tx *bolt.Tx, updateJob models.Job, previousState models.JobStateType, event models.Event) error {
 	historyEntry := models.JobHistory{
 		Type:  models.JobHistoryTypeJobLevel,
@@ -967,6 +1288,10 @@ func (b *BoltJobStore) createExecution(tx *bolt.Tx, execution models.Execution,
 		if err = b.executionsIndex.Add(tx, []byte(execution.JobID), []byte(execution.ID)); err != nil {
 			return err
 		}
+
+		if err = b.updateNodeExecutionsIndex(tx, nil, &execution); err != nil {
+			return err
+		}
 	}
 
 	return b.appendExecutionHistory(tx, execution, models.ExecutionStateNew, event)
@@ -990,9 +1315,8 @@ func (b *BoltJobStore) updateExecution(tx *bolt.Tx, request jobstore.UpdateExecu
 	if err := request.Condition.Validate(existingExecution); err != nil {
 		return err
 	}
-	if existingExecution.IsTerminalComputeState() {
-		return jobstore.NewErrExecutionAlreadyTerminal(
-			request.ExecutionID, existingExecution.ComputeState.StateType, request.NewValues.ComputeState.StateType)
+	if err := request.Condition.ValidateTerminal(existingExecution, request.NewValues.ComputeState.StateType); err != nil {
+		return err
 	}
 
 	// populate default values, maintain existing execution createTime
@@ -1030,7 +1354,19 @@ func (b *BoltJobStore) updateExecution(tx *bolt.Tx, request jobstore.UpdateExecu
 		}
 	}
 
-	return b.appendExecutionHistory(tx, newExecution, existingExecution.ComputeState.StateType, request.Event)
+	if err = b.updateNodeExecutionsIndex(tx, &existingExecution, &newExecution); err != nil {
+		return err
+	}
+
+	if err = b.appendExecutionHistory(tx, newExecution, existingExecution.ComputeState.StateType, request.Event); err != nil {
+		return err
+	}
+	for _, event := range request.Events {
+		if err = b.appendExecutionHistory(tx, newExecution, newExecution.ComputeState.StateType, event); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (b *BoltJobStore) appendExecutionHistory(tx *bolt.Tx, updated models.Execution,
@@ -1109,6 +1445,9 @@ func (b *BoltJobStore) createEvaluation(tx *bolt.Tx, eval models.Evaluation) err
 	if err != nil {
 		return err
 	}
+	if eval.Status == models.EvalStatusPending {
+		return b.pendingEvalsIndex.Add(tx, []byte(eval.ID))
+	}
 	return nil
 }
 
@@ -1148,12 +1487,121 @@ func (b *BoltJobStore) getEvaluation(tx *bolt.Tx, id string) (models.Evaluation,
 	return eval, nil
 }
 
+// GetEvaluations retrieves all evaluations for the specified job
+func (b *BoltJobStore) GetEvaluations(ctx context.Context, jobID string) ([]models.Evaluation, error) {
+	var evals []models.Evaluation
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		evals, err = b.getEvaluations(tx, jobID)
+		return
+	})
+
+	return evals, err
+}
+
+func (b *BoltJobStore) getEvaluations(tx *bolt.Tx, jobID string) ([]models.Evaluation, error) {
+	if _, err := b.getJob(tx, jobID); err != nil {
+		return nil, err
+	}
+
+	var evals []models.Evaluation
+	if bkt, err := NewBucketPath(BucketJobs, jobID, BucketJobEvaluations).Get(tx, false); err != nil {
+		return nil, err
+	} else {
+		err = bkt.ForEach(func(_ []byte, data []byte) error {
+			var eval models.Evaluation
+			if err := b.marshaller.Unmarshal(data, &eval); err != nil {
+				return err
+			}
+			evals = append(evals, eval)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return evals, nil
+}
+
+// GetPendingEvaluations retrieves all evaluations in pending status, across all jobs
+func (b *BoltJobStore) GetPendingEvaluations(ctx context.Context) ([]models.Evaluation, error) {
+	var evals []models.Evaluation
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		evals, err = b.getPendingEvaluations(tx)
+		return
+	})
+
+	return evals, err
+}
+
+func (b *BoltJobStore) getPendingEvaluations(tx *bolt.Tx) ([]models.Evaluation, error) {
+	ids, err := b.pendingEvalsIndex.List(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	evals := make([]models.Evaluation, 0, len(ids))
+	for _, id := range ids {
+		eval, err := b.getEvaluation(tx, string(id))
+		if err != nil {
+			return nil, err
+		}
+		evals = append(evals, eval)
+	}
+	return evals, nil
+}
+
+// UpdateEvaluationStatus updates the status of the specified evaluation
+func (b *BoltJobStore) UpdateEvaluationStatus(ctx context.Context, id string, status string) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.updateEvaluationStatus(tx, id, status)
+	})
+}
+
+func (b *BoltJobStore) updateEvaluationStatus(tx *bolt.Tx, id string, status string) error {
+	eval, err := b.getEvaluation(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if eval.Status == models.EvalStatusPending && status != models.EvalStatusPending {
+		if err = b.pendingEvalsIndex.Remove(tx, []byte(eval.ID)); err != nil {
+			return err
+		}
+	} else if eval.Status != models.EvalStatusPending && status == models.EvalStatusPending {
+		if err = b.pendingEvalsIndex.Add(tx, []byte(eval.ID)); err != nil {
+			return err
+		}
+	}
+
+	eval.Status = status
+	eval.ModifyTime = b.clock.Now().UTC().UnixNano()
+
+	tx.OnCommit(func() {
+		b.triggerEvent(jobstore.EvaluationWatcher, jobstore.UpdateEvent, eval)
+	})
+
+	data, err := b.marshaller.Marshal(eval)
+	if err != nil {
+		return err
+	}
+
+	bkt, err := NewBucketPath(BucketJobs, eval.JobID, BucketJobEvaluations).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.Put([]byte(eval.ID), data)
+}
+
 func (b *BoltJobStore) getEvaluationJobID(tx *bolt.Tx, id string) (string, error) {
 	keys, err := b.evaluationsIndex.List(tx, []byte(id))
 	if err != nil {
 		return "", err
 	}
 
+	if len(keys) == 0 {
+		return "", bacerrors.NewEvaluationNotFound(id)
+	}
 	if len(keys) != 1 {
 		return "", fmt.Errorf("too many leaf nodes in evaluation index")
 	}
@@ -1192,9 +1640,296 @@ func (b *BoltJobStore) deleteEvaluation(tx *bolt.Tx, id string) error {
 		}
 	}
 
+	return b.pendingEvalsIndex.Remove(tx, []byte(id))
+}
+
+// GetNamespaceQuota retrieves the quota of the specified namespace
+func (b *BoltJobStore) GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		quota, err = b.getNamespaceQuota(tx, namespace)
+		return
+	})
+
+	return quota, err
+}
+
+func (b *BoltJobStore) getNamespaceQuota(tx *bolt.Tx, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+
+	data := GetBucketData(tx, NewBucketPath(BucketNamespaceQuotas), []byte(namespace))
+	if data == nil {
+		return quota, jobstore.NewErrNamespaceQuotaNotFound(namespace)
+	}
+
+	err := b.marshaller.Unmarshal(data, &quota)
+	return quota, err
+}
+
+// GetNamespaceQuotas retrieves the quotas of all namespaces that have one
+func (b *BoltJobStore) GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error) {
+	var quotas []models.NamespaceQuota
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		quotas, err = b.getNamespaceQuotas(tx)
+		return
+	})
+
+	return quotas, err
+}
+
+func (b *BoltJobStore) getNamespaceQuotas(tx *bolt.Tx) ([]models.NamespaceQuota, error) {
+	bkt, err := NewBucketPath(BucketNamespaceQuotas).Get(tx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var quotas []models.NamespaceQuota
+	err = bkt.ForEach(func(_ []byte, data []byte) error {
+		var quota models.NamespaceQuota
+		if err := b.marshaller.Unmarshal(data, &quota); err != nil {
+			return err
+		}
+		quotas = append(quotas, quota)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quotas, nil
+}
+
+// PutNamespaceQuota creates or replaces the quota of a namespace
+func (b *BoltJobStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.putNamespaceQuota(tx, quota)
+	})
+}
+
+func (b *BoltJobStore) putNamespaceQuota(tx *bolt.Tx, quota models.NamespaceQuota) error {
+	quota.Normalize()
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+
+	now := b.clock.Now().UTC().UnixNano()
+	quota.CreateTime = now
+	if existing, err := b.getNamespaceQuota(tx, quota.Namespace); err == nil {
+		quota.CreateTime = existing.CreateTime
+	}
+	quota.ModifyTime = now
+
+	data, err := b.marshaller.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	bkt, err := NewBucketPath(BucketNamespaceQuotas).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.Put([]byte(quota.Namespace), data)
+}
+
+// DeleteNamespaceQuota removes the quota of the specified namespace
+func (b *BoltJobStore) DeleteNamespaceQuota(ctx context.Context, namespace string) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.deleteNamespaceQuota(tx, namespace)
+	})
+}
+
+func (b *BoltJobStore) deleteNamespaceQuota(tx *bolt.Tx, namespace string) error {
+	if _, err := b.getNamespaceQuota(tx, namespace); err != nil {
+		return err
+	}
+
+	bkt, err := NewBucketPath(BucketNamespaceQuotas).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.Delete([]byte(namespace))
+}
+
+// GetResultCacheEntry retrieves the result cache entry with the specified key in a namespace
+func (b *BoltJobStore) GetResultCacheEntry(
+	ctx context.Context, namespace string, key string) (models.ResultCacheEntry, error) {
+	var entry models.ResultCacheEntry
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		entry, err = b.getResultCacheEntry(tx, namespace, key)
+		return
+	})
+	return entry, err
+}
+
+func (b *BoltJobStore) getResultCacheEntry(tx *bolt.Tx, namespace string, key string) (models.ResultCacheEntry, error) {
+	var entry models.ResultCacheEntry
+
+	data := GetBucketData(tx, NewBucketPath(BucketResultCache), resultCacheKey(namespace, key))
+	if data == nil {
+		return entry, jobstore.NewErrResultCacheEntryNotFound(namespace, key)
+	}
+
+	err := b.marshaller.Unmarshal(data, &entry)
+	return entry, err
+}
+
+// PutResultCacheEntry creates or replaces the result cache entry with the same namespace and key
+func (b *BoltJobStore) PutResultCacheEntry(ctx context.Context, entry models.ResultCacheEntry) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.putResultCacheEntry(tx, entry)
+	})
+}
+
+func (b *BoltJobStore) putResultCacheEntry(tx *bolt.Tx, entry models.ResultCacheEntry) error {
+	entry.Normalize()
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	entry.CreateTime = b.clock.Now().UTC().UnixNano()
+
+	data, err := b.marshaller.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	bkt, err := NewBucketPath(BucketResultCache).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(resultCacheKey(entry.Namespace, entry.Key), data)
+}
+
+// deleteResultCacheEntries deletes the result cache entries of a namespace that reuse the results of a job
+func (b *BoltJobStore) deleteResultCacheEntries(tx *bolt.Tx, namespace string, jobID string) error {
+	bkt, err := NewBucketPath(BucketResultCache).Get(tx, false)
+	if err != nil {
+		return err
+	}
+
+	var stale [][]byte
+	prefix := resultCacheKey(namespace, "")
+	cursor := bkt.Cursor()
+	for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+		var entry models.ResultCacheEntry
+		if err = b.marshaller.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if entry.JobID == jobID {
+			stale = append(stale, k)
+		}
+	}
+	// keys are deleted after iterating, as deleting them moves the cursor
+	for _, k := range stale {
+		if err = bkt.Delete(k); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// resultCacheKey returns the key of a result cache entry, which is scoped to its namespace
+func resultCacheKey(namespace string, key string) []byte {
+	return []byte(namespace + "/" + key)
+}
+
+// GetSecret retrieves the secret with the specified name in a namespace
+func (b *BoltJobStore) GetSecret(ctx context.Context, namespace string, name string) (models.Secret, error) {
+	var secret models.Secret
+	err := b.database.View(func(tx *bolt.Tx) (err error) {
+		secret, err = b.getSecret(tx, namespace, name)
+		return
+	})
+	return secret, err
+}
+
+func (b *BoltJobStore) getSecret(tx *bolt.Tx, namespace string, name string) (models.Secret, error) {
+	var secret models.Secret
+
+	data := GetBucketData(tx, NewBucketPath(BucketSecrets), secretKey(namespace, name))
+	if data == nil {
+		return secret, jobstore.NewErrSecretNotFound(namespace, name)
+	}
+
+	err := b.marshaller.Unmarshal(data, &secret)
+	return secret, err
+}
+
+// GetSecrets retrieves all the secrets of a namespace
+func (b *BoltJobStore) GetSecrets(ctx context.Context, namespace string) ([]models.Secret, error) {
+	var secrets []models.Secret
+	err := b.database.View(func(tx *bolt.Tx) error {
+		bkt, err := NewBucketPath(BucketSecrets).Get(tx, false)
+		if err != nil {
+			return err
+		}
+
+		// keys are sorted, so the secrets of the namespace are returned sorted by name
+		prefix := secretKey(namespace, "")
+		cursor := bkt.Cursor()
+		for k, data := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, data = cursor.Next() {
+			var secret models.Secret
+			if err = b.marshaller.Unmarshal(data, &secret); err != nil {
+				return err
+			}
+			secrets = append(secrets, secret)
+		}
+		return nil
+	})
+	return secrets, err
+}
+
+// PutSecret creates or replaces the secret with the same namespace and name
+func (b *BoltJobStore) PutSecret(ctx context.Context, secret models.Secret) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		return b.putSecret(tx, secret)
+	})
+}
+
+func (b *BoltJobStore) putSecret(tx *bolt.Tx, secret models.Secret) error {
+	secret.Normalize()
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+
+	now := b.clock.Now().UTC().UnixNano()
+	secret.CreateTime = now
+	if existing, err := b.getSecret(tx, secret.Namespace, secret.Name); err == nil {
+		secret.CreateTime = existing.CreateTime
+	}
+	secret.ModifyTime = now
+
+	data, err := b.marshaller.Marshal(secret)
+	if err != nil {
+		return err
+	}
+
+	bkt, err := NewBucketPath(BucketSecrets).Get(tx, false)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(secretKey(secret.Namespace, secret.Name), data)
+}
+
+// DeleteSecret removes the secret with the specified name in a namespace
+func (b *BoltJobStore) DeleteSecret(ctx context.Context, namespace string, name string) error {
+	return b.database.Update(func(tx *bolt.Tx) (err error) {
+		if _, err = b.getSecret(tx, namespace, name); err != nil {
+			return err
+		}
+
+		bkt, err := NewBucketPath(BucketSecrets).Get(tx, false)
+		if err != nil {
+			return err
+		}
+		return bkt.Delete(secretKey(namespace, name))
+	})
+}
+
+// secretKey returns the key of a secret, which is scoped to its namespace
+func secretKey(namespace string, name string) []byte {
+	return []byte(namespace + "/" + name)
+}
+
 func (b *BoltJobStore) Close(ctx context.Context) error {
 	for _, w := range b.watchers {
 		w.Close()
@@ -157,3 +157,44 @@ func (e ErrExecutionAlreadyTerminal) Error() string {
 	return fmt.Sprintf("execution %s is in terminal state %s and cannot transition to %s",
 		e.ExecutionID, e.Actual, e.NewState)
 }
+
+// ErrNamespaceQuotaNotFound is returned when a namespace has no quota
+type ErrNamespaceQuotaNotFound struct {
+	Namespace string
+}
+
+func NewErrNamespaceQuotaNotFound(namespace string) ErrNamespaceQuotaNotFound {
+	return ErrNamespaceQuotaNotFound{Namespace: namespace}
+}
+
+func (e ErrNamespaceQuotaNotFound) Error() string {
+	return "namespace quota not found: " + e.Namespace
+}
+
+// ErrResultCacheEntryNotFound is returned when there is no result cache entry with a key
+type ErrResultCacheEntryNotFound struct {
+	Namespace string
+	Key       string
+}
+
+func NewErrResultCacheEntryNotFound(namespace string, key string) ErrResultCacheEntryNotFound {
+	return ErrResultCacheEntryNotFound{Namespace: namespace, Key: key}
+}
+
+func (e ErrResultCacheEntryNotFound) Error() string {
+	return fmt.Sprintf("result cache entry not found: %s in namespace %s", e.Key, e.Namespace)
+}
+
+// ErrSecretNotFound is returned when a namespace has no secret with a name
+type ErrSecretNotFound struct {
+	Namespace string
+	Name      string
+}
+
+func NewErrSecretNotFound(namespace string, name string) ErrSecretNotFound {
+	return ErrSecretNotFound{Namespace: namespace, Name: name}
+}
+
+func (e ErrSecretNotFound) Error() string {
+	return fmt.Sprintf("secret not found: %s in namespace %s", e.Name, e.Namespace)
+}
@@ -124,6 +124,64 @@ func (mr *MockStoreMockRecorder) DeleteJob(ctx, jobID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockStore)(nil).DeleteJob), ctx, jobID)
 }
 
+// DeleteNamespaceQuota mocks base method.
+func (m *MockStore) DeleteNamespaceQuota(ctx context.Context, namespace string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespaceQuota", ctx, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNamespaceQuota indicates an expected call of DeleteNamespaceQuota.
+func (mr *MockStoreMockRecorder) DeleteNamespaceQuota(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespaceQuota", reflect.TypeOf((*MockStore)(nil).DeleteNamespaceQuota), ctx, namespace)
+}
+
+// DeleteSecret mocks base method.
+func (m *MockStore) DeleteSecret(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecret", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecret indicates an expected call of DeleteSecret.
+func (mr *MockStoreMockRecorder) DeleteSecret(ctx, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockStore)(nil).DeleteSecret), ctx, namespace, name)
+}
+
+// GetActiveExecutionsOnNode mocks base method.
+func (m *MockStore) GetActiveExecutionsOnNode(ctx context.Context, nodeID string) ([]models.Execution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveExecutionsOnNode", ctx, nodeID)
+	ret0, _ := ret[0].([]models.Execution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveExecutionsOnNode indicates an expected call of GetActiveExecutionsOnNode.
+func (mr *MockStoreMockRecorder) GetActiveExecutionsOnNode(ctx, nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveExecutionsOnNode", reflect.TypeOf((*MockStore)(nil).GetActiveExecutionsOnNode), ctx, nodeID)
+}
+
+// GetDependentJobs mocks base method.
+func (m *MockStore) GetDependentJobs(ctx context.Context, upstreamJobID string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependentJobs", ctx, upstreamJobID)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependentJobs indicates an expected call of GetDependentJobs.
+func (mr *MockStoreMockRecorder) GetDependentJobs(ctx, upstreamJobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependentJobs", reflect.TypeOf((*MockStore)(nil).GetDependentJobs), ctx, upstreamJobID)
+}
+
 // GetEvaluation mocks base method.
 func (m *MockStore) GetEvaluation(ctx context.Context, id string) (models.Evaluation, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +197,21 @@ func (mr *MockStoreMockRecorder) GetEvaluation(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvaluation", reflect.TypeOf((*MockStore)(nil).GetEvaluation), ctx, id)
 }
 
+// GetEvaluations mocks base method.
+func (m *MockStore) GetEvaluations(ctx context.Context, jobID string) ([]models.Evaluation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvaluations", ctx, jobID)
+	ret0, _ := ret[0].([]models.Evaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvaluations indicates an expected call of GetEvaluations.
+func (mr *MockStoreMockRecorder) GetEvaluations(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvaluations", reflect.TypeOf((*MockStore)(nil).GetEvaluations), ctx, jobID)
+}
+
 // GetExecutions mocks base method.
 func (m *MockStore) GetExecutions(ctx context.Context, options GetExecutionsOptions) ([]models.Execution, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +227,21 @@ func (mr *MockStoreMockRecorder) GetExecutions(ctx, options any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutions", reflect.TypeOf((*MockStore)(nil).GetExecutions), ctx, options)
 }
 
+// GetExecutionsForJobs mocks base method.
+func (m *MockStore) GetExecutionsForJobs(ctx context.Context, jobIDs []string) (map[string][]models.Execution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExecutionsForJobs", ctx, jobIDs)
+	ret0, _ := ret[0].(map[string][]models.Execution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExecutionsForJobs indicates an expected call of GetExecutionsForJobs.
+func (mr *MockStoreMockRecorder) GetExecutionsForJobs(ctx, jobIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExecutionsForJobs", reflect.TypeOf((*MockStore)(nil).GetExecutionsForJobs), ctx, jobIDs)
+}
+
 // GetInProgressJobs mocks base method.
 func (m *MockStore) GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +257,21 @@ func (mr *MockStoreMockRecorder) GetInProgressJobs(ctx, jobType any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInProgressJobs", reflect.TypeOf((*MockStore)(nil).GetInProgressJobs), ctx, jobType)
 }
 
+// GetInProgressJobsInNamespace mocks base method.
+func (m *MockStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInProgressJobsInNamespace", ctx, namespace)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInProgressJobsInNamespace indicates an expected call of GetInProgressJobsInNamespace.
+func (mr *MockStoreMockRecorder) GetInProgressJobsInNamespace(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInProgressJobsInNamespace", reflect.TypeOf((*MockStore)(nil).GetInProgressJobsInNamespace), ctx, namespace)
+}
+
 // GetJob mocks base method.
 func (m *MockStore) GetJob(ctx context.Context, id string) (models.Job, error) {
 	m.ctrl.T.Helper()
@@ -214,6 +317,167 @@ func (mr *MockStoreMockRecorder) GetJobs(ctx, query any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobs", reflect.TypeOf((*MockStore)(nil).GetJobs), ctx, query)
 }
 
+// GetNamespaceQuota mocks base method.
+func (m *MockStore) GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceQuota", ctx, namespace)
+	ret0, _ := ret[0].(models.NamespaceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceQuota indicates an expected call of GetNamespaceQuota.
+func (mr *MockStoreMockRecorder) GetNamespaceQuota(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceQuota", reflect.TypeOf((*MockStore)(nil).GetNamespaceQuota), ctx, namespace)
+}
+
+// GetNamespaceQuotas mocks base method.
+func (m *MockStore) GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNamespaceQuotas", ctx)
+	ret0, _ := ret[0].([]models.NamespaceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNamespaceQuotas indicates an expected call of GetNamespaceQuotas.
+func (mr *MockStoreMockRecorder) GetNamespaceQuotas(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaceQuotas", reflect.TypeOf((*MockStore)(nil).GetNamespaceQuotas), ctx)
+}
+
+// GetPendingEvaluations mocks base method.
+func (m *MockStore) GetPendingEvaluations(ctx context.Context) ([]models.Evaluation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingEvaluations", ctx)
+	ret0, _ := ret[0].([]models.Evaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingEvaluations indicates an expected call of GetPendingEvaluations.
+func (mr *MockStoreMockRecorder) GetPendingEvaluations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingEvaluations", reflect.TypeOf((*MockStore)(nil).GetPendingEvaluations), ctx)
+}
+
+// GetResultCacheEntry mocks base method.
+func (m *MockStore) GetResultCacheEntry(ctx context.Context, namespace, key string) (models.ResultCacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResultCacheEntry", ctx, namespace, key)
+	ret0, _ := ret[0].(models.ResultCacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResultCacheEntry indicates an expected call of GetResultCacheEntry.
+func (mr *MockStoreMockRecorder) GetResultCacheEntry(ctx, namespace, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultCacheEntry", reflect.TypeOf((*MockStore)(nil).GetResultCacheEntry), ctx, namespace, key)
+}
+
+// GetScheduledRuns mocks base method.
+func (m *MockStore) GetScheduledRuns(ctx context.Context, jobID string) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledRuns", ctx, jobID)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledRuns indicates an expected call of GetScheduledRuns.
+func (mr *MockStoreMockRecorder) GetScheduledRuns(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledRuns", reflect.TypeOf((*MockStore)(nil).GetScheduledRuns), ctx, jobID)
+}
+
+// GetSecret mocks base method.
+func (m *MockStore) GetSecret(ctx context.Context, namespace, name string) (models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", ctx, namespace, name)
+	ret0, _ := ret[0].(models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *MockStoreMockRecorder) GetSecret(ctx, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockStore)(nil).GetSecret), ctx, namespace, name)
+}
+
+// GetSecrets mocks base method.
+func (m *MockStore) GetSecrets(ctx context.Context, namespace string) ([]models.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecrets", ctx, namespace)
+	ret0, _ := ret[0].([]models.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecrets indicates an expected call of GetSecrets.
+func (mr *MockStoreMockRecorder) GetSecrets(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecrets", reflect.TypeOf((*MockStore)(nil).GetSecrets), ctx, namespace)
+}
+
+// PutNamespaceQuota mocks base method.
+func (m *MockStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutNamespaceQuota", ctx, quota)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutNamespaceQuota indicates an expected call of PutNamespaceQuota.
+func (mr *MockStoreMockRecorder) PutNamespaceQuota(ctx, quota any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutNamespaceQuota", reflect.TypeOf((*MockStore)(nil).PutNamespaceQuota), ctx, quota)
+}
+
+// PutResultCacheEntry mocks base method.
+func (m *MockStore) PutResultCacheEntry(ctx context.Context, entry models.ResultCacheEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutResultCacheEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutResultCacheEntry indicates an expected call of PutResultCacheEntry.
+func (mr *MockStoreMockRecorder) PutResultCacheEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutResultCacheEntry", reflect.TypeOf((*MockStore)(nil).PutResultCacheEntry), ctx, entry)
+}
+
+// PutSecret mocks base method.
+func (m *MockStore) PutSecret(ctx context.Context, secret models.Secret) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutSecret", ctx, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutSecret indicates an expected call of PutSecret.
+func (mr *MockStoreMockRecorder) PutSecret(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSecret", reflect.TypeOf((*MockStore)(nil).PutSecret), ctx, secret)
+}
+
+// UpdateEvaluationStatus mocks base method.
+func (m *MockStore) UpdateEvaluationStatus(ctx context.Context, id, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvaluationStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEvaluationStatus indicates an expected call of UpdateEvaluationStatus.
+func (mr *MockStoreMockRecorder) UpdateEvaluationStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvaluationStatus", reflect.TypeOf((*MockStore)(nil).UpdateEvaluationStatus), ctx, id, status)
+}
+
 // UpdateExecution mocks base method.
 func (m *MockStore) UpdateExecution(ctx context.Context, request UpdateExecutionRequest) error {
 	m.ctrl.T.Helper()
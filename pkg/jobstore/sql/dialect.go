@@ -0,0 +1,167 @@
+package sqljobstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Register the pgx driver used for Postgres
+	_ "github.com/jackc/pgx/v5/stdlib"
+	// Register the pure Go SQLite driver
+	_ "modernc.org/sqlite"
+)
+
+const (
+	TableJobs             = "jobs"
+	TableJobTags          = "job_tags"
+	TableJobDependencies  = "job_dependencies"
+	TableExecutions       = "executions"
+	TableEvaluations      = "evaluations"
+	TableJobHistory       = "job_history"
+	TableExecutionHistory = "execution_history"
+	TableNamespaceQuotas  = "namespace_quotas"
+	TableResultCache      = "result_cache"
+	TableSecrets          = "secrets"
+)
+
+// dialect captures the differences between the SQL databases supported by the job store.
+type dialect struct {
+	// name is the human-readable name of the database
+	name string
+	// driver is the name of the database/sql driver
+	driver string
+	// sequenceColumn is the column definition of an auto-incrementing primary key
+	sequenceColumn string
+	// lockRows is appended to queries that read rows which the transaction is about to update,
+	// so that concurrent transactions cannot update them in between.
+	lockRows string
+	// numberedParams is true if the database expects $1, $2... placeholders rather than ?
+	numberedParams bool
+	// noLimit is the LIMIT clause of queries with an OFFSET but no limit, for databases that require one.
+	noLimit string
+	// maxOpenConns limits the connections opened to the database, zero means no limit.
+	maxOpenConns int
+}
+
+var (
+	sqliteDialect = dialect{
+		name:           "SQLite",
+		driver:         "sqlite",
+		sequenceColumn: "INTEGER PRIMARY KEY AUTOINCREMENT",
+		noLimit:        " LIMIT -1",
+		// SQLite only allows a single writer, so a single connection serializes the transactions
+		// instead of failing them with SQLITE_BUSY.
+		maxOpenConns: 1,
+	}
+
+	postgresDialect = dialect{
+		name:           "Postgres",
+		driver:         "pgx",
+		sequenceColumn: "BIGSERIAL PRIMARY KEY",
+		lockRows:       " FOR UPDATE",
+		numberedParams: true,
+	}
+)
+
+// rebind rewrites the ? placeholders of a query to the placeholders expected by the database.
+func (d dialect) rebind(query string) string {
+	if !d.numberedParams {
+		return query
+	}
+	var sb strings.Builder
+	param := 0
+	for _, r := range query {
+		if r == '?' {
+			param++
+			sb.WriteString("$" + strconv.Itoa(param))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// schema returns the statements that create the tables and indexes used by the job store.
+// They are idempotent so that they can be run each time the store is opened.
+func (d dialect) schema() []string {
+	return []string{
+		`CREATE TABLE IF NOT EXISTS ` + TableJobs + ` (
+			id           TEXT PRIMARY KEY,
+			namespace    TEXT NOT NULL,
+			type         TEXT NOT NULL,
+			in_progress  INTEGER NOT NULL,
+			scheduled_by TEXT NOT NULL,
+			create_time  BIGINT NOT NULL,
+			modify_time  BIGINT NOT NULL,
+			data         TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_namespace ON ` + TableJobs + ` (namespace)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_in_progress ON ` + TableJobs + ` (in_progress, type)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_namespace_in_progress ON ` + TableJobs + ` (namespace, in_progress)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_scheduled_by ON ` + TableJobs + ` (scheduled_by)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableJobTags + ` (
+			job_id TEXT NOT NULL,
+			tag    TEXT NOT NULL,
+			PRIMARY KEY (job_id, tag)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_job_tags_tag ON ` + TableJobTags + ` (tag)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableJobDependencies + ` (
+			job_id          TEXT NOT NULL,
+			upstream_job_id TEXT NOT NULL,
+			PRIMARY KEY (job_id, upstream_job_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_job_dependencies_upstream ON ` + TableJobDependencies + ` (upstream_job_id)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableExecutions + ` (
+			id      TEXT PRIMARY KEY,
+			job_id  TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			active  INTEGER NOT NULL,
+			data    TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_executions_job_id ON ` + TableExecutions + ` (job_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_executions_node_active ON ` + TableExecutions + ` (node_id, active)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableEvaluations + ` (
+			id     TEXT PRIMARY KEY,
+			job_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			data   TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_evaluations_job_id ON ` + TableEvaluations + ` (job_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_evaluations_status ON ` + TableEvaluations + ` (status)`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			seq    %s,
+			job_id TEXT NOT NULL,
+			data   TEXT NOT NULL
+		)`, TableJobHistory, d.sequenceColumn),
+		`CREATE INDEX IF NOT EXISTS idx_job_history_job_id ON ` + TableJobHistory + ` (job_id)`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			seq    %s,
+			job_id TEXT NOT NULL,
+			data   TEXT NOT NULL
+		)`, TableExecutionHistory, d.sequenceColumn),
+		`CREATE INDEX IF NOT EXISTS idx_execution_history_job_id ON ` + TableExecutionHistory + ` (job_id)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableNamespaceQuotas + ` (
+			namespace TEXT PRIMARY KEY,
+			data      TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableResultCache + ` (
+			namespace TEXT NOT NULL,
+			cache_key TEXT NOT NULL,
+			job_id    TEXT NOT NULL,
+			data      TEXT NOT NULL,
+			PRIMARY KEY (namespace, cache_key)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_result_cache_job_id ON ` + TableResultCache + ` (job_id)`,
+		`CREATE TABLE IF NOT EXISTS ` + TableSecrets + ` (
+			namespace TEXT NOT NULL,
+			name      TEXT NOT NULL,
+			data      TEXT NOT NULL,
+			PRIMARY KEY (namespace, name)
+		)`,
+	}
+}
+
+// placeholders returns a comma separated list of n ? placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,1323 @@
+package sqljobstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/imdario/mergo"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+// sqliteBusyTimeout is how long SQLite waits for a lock held by another process before failing
+const sqliteBusyTimeout = 5 * time.Second
+
+// errStopQuery is returned by the callbacks of queryDocuments that don't need the remaining documents
+var errStopQuery = errors.New("stop query")
+
+type SQLJobStore struct {
+	database    *sql.DB
+	dialect     dialect
+	clock       clock.Clock
+	marshaller  marshaller.Marshaller
+	watchers    []*jobstore.Watcher
+	watcherLock sync.Mutex
+}
+
+type Option func(store *SQLJobStore)
+
+func WithClock(clock clock.Clock) Option {
+	return func(store *SQLJobStore) {
+		store.clock = clock
+	}
+}
+
+// NewSQLiteJobStore creates a job store embedded in the SQLite database file at the given path,
+// creating the file if it does not exist.
+func NewSQLiteJobStore(path string, options ...Option) (*SQLJobStore, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)",
+		(&url.URL{Path: path}).EscapedPath(), sqliteBusyTimeout.Milliseconds())
+	return newSQLJobStore(sqliteDialect, dsn, options...)
+}
+
+// NewPostgresJobStore creates a job store backed by the Postgres database identified by the
+// connection string, which can either be a URL or a list of key=value settings.
+func NewPostgresJobStore(connString string, options ...Option) (*SQLJobStore, error) {
+	return newSQLJobStore(postgresDialect, connString, options...)
+}
+
+// newSQLJobStore creates a job store where jobs, executions, evaluations and their history are
+// held as JSON documents in SQL tables, alongside the columns that are needed to query them.
+// Tables are structured as follows:
+//
+//	jobs              = id, namespace, type, in_progress, scheduled_by, create_time, modify_time -> Job
+//	job_tags          = job_id, tag
+//	job_dependencies  = job_id, upstream_job_id
+//	executions        = id, job_id, node_id, active -> Execution
+//	evaluations       = id, job_id, status -> Evaluation
+//	job_history       = seq, job_id -> JobHistory
+//	execution_history = seq, job_id -> JobHistory
+//	namespace_quotas  = namespace -> NamespaceQuota
+//	result_cache      = namespace, cache_key, job_id -> ResultCacheEntry
+//	secrets           = namespace, name -> Secret
+func newSQLJobStore(d dialect, dsn string, options ...Option) (*SQLJobStore, error) {
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s job store: %w", d.name, err)
+	}
+	if d.maxOpenConns > 0 {
+		db.SetMaxOpenConns(d.maxOpenConns)
+	}
+
+	store := &SQLJobStore{
+		database:   db,
+		dialect:    d,
+		clock:      clock.New(),
+		marshaller: marshaller.NewJSONMarshaller(),
+		watchers:   make([]*jobstore.Watcher, 0), //nolint:gomnd
+	}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	// Create the tables ready for use as they will definitely be required
+	err = store.transact(context.Background(), func(tx *txContext) error {
+		for _, statement := range d.schema() {
+			if _, err := tx.exec(statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create %s job store schema: %w", d.name, err)
+	}
+
+	return store, nil
+}
+
+// txContext is a transaction along with the context it runs in, and the handlers to call
+// once it has been committed.
+type txContext struct {
+	ctx      context.Context //nolint:containedctx
+	tx       *sql.Tx
+	dialect  dialect
+	onCommit []func()
+}
+
+// OnCommit registers a handler to call once the transaction has been committed
+func (tx *txContext) OnCommit(fn func()) {
+	tx.onCommit = append(tx.onCommit, fn)
+}
+
+func (tx *txContext) exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.tx.ExecContext(tx.ctx, tx.dialect.rebind(query), args...)
+}
+
+func (tx *txContext) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.tx.QueryContext(tx.ctx, tx.dialect.rebind(query), args...)
+}
+
+func (tx *txContext) queryRow(query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRowContext(tx.ctx, tx.dialect.rebind(query), args...)
+}
+
+// transact runs fn in a transaction that is committed if fn succeeds, and rolled back otherwise.
+// The handlers registered with OnCommit are called once the transaction has been committed.
+func (s *SQLJobStore) transact(ctx context.Context, fn func(tx *txContext) error) error {
+	sqlTx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	tx := &txContext{ctx: ctx, tx: sqlTx, dialect: s.dialect}
+	if err = fn(tx); err != nil {
+		_ = sqlTx.Rollback()
+		return err
+	}
+	if err = sqlTx.Commit(); err != nil {
+		return err
+	}
+
+	for _, handler := range tx.onCommit {
+		handler()
+	}
+	return nil
+}
+
+func (s *SQLJobStore) Watch(ctx context.Context,
+	types jobstore.StoreWatcherType,
+	events jobstore.StoreEventType) chan jobstore.WatchEvent {
+	w := jobstore.NewWatcher(types, events)
+
+	s.watcherLock.Lock() // keep the watchers lock as narrow as possible
+	s.watchers = append(s.watchers, w)
+	s.watcherLock.Unlock()
+
+	return w.Channel()
+}
+
+func (s *SQLJobStore) triggerEvent(t jobstore.StoreWatcherType, e jobstore.StoreEventType, object interface{}) {
+	data, _ := json.Marshal(object)
+
+	s.watcherLock.Lock()
+	defer s.watcherLock.Unlock()
+	for _, w := range s.watchers {
+		if !w.IsWatchingEvent(e) || !w.IsWatchingType(t) {
+			continue
+		}
+
+		_ = w.WriteEvent(t, e, data, false) // Do not block
+	}
+}
+
+// GetJob retrieves the Job identified by the id string. If the job isn't found it will
+// return an indicating the error.
+func (s *SQLJobStore) GetJob(ctx context.Context, id string) (models.Job, error) {
+	var job models.Job
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		job, err = s.getJob(tx, id, false)
+		return
+	})
+	return job, err
+}
+
+// getJob retrieves the job identified by the full or short jobID. If lock is true, the job
+// cannot be updated by other transactions until this one completes.
+func (s *SQLJobStore) getJob(tx *txContext, jobID string, lock bool) (models.Job, error) {
+	var job models.Job
+
+	jobID, err := s.reifyJobID(tx, jobID)
+	if err != nil {
+		return job, err
+	}
+
+	query := `SELECT data FROM ` + TableJobs + ` WHERE id = ?`
+	if lock {
+		query += s.dialect.lockRows
+	}
+	var data []byte
+	err = tx.queryRow(query, jobID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, bacerrors.NewJobNotFound(jobID)
+	} else if err != nil {
+		return job, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &job)
+	return job, err
+}
+
+// reifyJobID ensures the provided job ID is a full-length ID. This is either through
+// returning the ID, or resolving the short ID to a single job id.
+func (s *SQLJobStore) reifyJobID(tx *txContext, jobID string) (string, error) {
+	if idgen.ShortUUID(jobID) != jobID {
+		// Return what we were given
+		return jobID, nil
+	}
+
+	found, err := s.queryStrings(tx,
+		`SELECT id FROM `+TableJobs+` WHERE substr(id, 1, ?) = ? ORDER BY id`, len(jobID), jobID)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		return "", bacerrors.NewJobNotFound(jobID)
+	case 1:
+		return found[0], nil
+	default:
+		return "", bacerrors.NewMultipleJobsFound(jobID, found)
+	}
+}
+
+func (s *SQLJobStore) jobExists(tx *txContext, jobID string) (bool, error) {
+	var count int
+	err := tx.queryRow(`SELECT COUNT(*) FROM `+TableJobs+` WHERE id = ?`, jobID).Scan(&count)
+	return count > 0, err
+}
+
+func (s *SQLJobStore) getExecution(tx *txContext, id string, lock bool) (models.Execution, error) {
+	var exec models.Execution
+
+	query := `SELECT data FROM ` + TableExecutions + ` WHERE id = ?`
+	if lock {
+		query += s.dialect.lockRows
+	}
+	var data []byte
+	err := tx.queryRow(query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return exec, jobstore.NewErrExecutionNotFound(id)
+	} else if err != nil {
+		return exec, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &exec)
+	return exec, err
+}
+
+func (s *SQLJobStore) getExecutions(tx *txContext, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	j, err := s.getJob(tx, options.JobID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// include latest job state if requested
+	var job *models.Job
+	if options.IncludeJob {
+		job = &j
+	}
+
+	var execs []models.Execution
+	err = s.queryDocuments(tx, func(data []byte) error {
+		var es models.Execution
+		if err := s.marshaller.Unmarshal(data, &es); err != nil {
+			return err
+		}
+
+		es.Job = job
+		execs = append(execs, es)
+		return nil
+	}, `SELECT data FROM `+TableExecutions+` WHERE job_id = ? ORDER BY id`, j.ID)
+
+	return execs, err
+}
+
+// GetJobs returns all Jobs that match the provided query
+func (s *SQLJobStore) GetJobs(ctx context.Context, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	var response *jobstore.JobQueryResponse
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		response, err = s.getJobs(tx, query)
+		return
+	})
+	return response, err
+}
+
+func (s *SQLJobStore) getJobs(tx *txContext, query jobstore.JobQuery) (*jobstore.JobQueryResponse, error) {
+	var conditions []string
+	var args []interface{}
+
+	if !query.ReturnAll && query.Namespace != "" {
+		conditions = append(conditions, "namespace = ?")
+		args = append(args, query.Namespace)
+	}
+
+	// keep jobs that have ANY of the included tags, and drop jobs that have ANY of the excluded tags
+	if len(query.IncludeTags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT job_id FROM %s WHERE tag IN (%s))", TableJobTags, placeholders(len(query.IncludeTags))))
+		args = append(args, lowerTags(query.IncludeTags)...)
+	}
+	if len(query.ExcludeTags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"id NOT IN (SELECT job_id FROM %s WHERE tag IN (%s))", TableJobTags, placeholders(len(query.ExcludeTags))))
+		args = append(args, lowerTags(query.ExcludeTags)...)
+	}
+
+	statement := `SELECT data FROM ` + TableJobs
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// We apply created_at as a default sort so that we can use it for pagination.
+	// Without a known default we won't have a stable sort that makes sense for
+	// offsets/limits.
+	orderBy := "create_time"
+	if query.SortBy == "modified_at" {
+		orderBy = "modify_time"
+	}
+	direction := "ASC"
+	if query.SortReverse {
+		direction = "DESC"
+	}
+	statement += fmt.Sprintf(" ORDER BY %s %s, id %s", orderBy, direction, direction)
+
+	// Without a selector, the database selects the page of jobs. Selectors match the labels of decoded jobs,
+	// so the page is then selected while reading the ordered rows instead, which stops once the page is full.
+	// In both cases, one job more than the limit is read to know whether there are more jobs.
+	skip := query.Offset
+	if query.Selector == nil {
+		if query.Limit > 0 {
+			statement += " LIMIT ?"
+			args = append(args, int64(query.Limit)+1)
+		} else if query.Offset > 0 {
+			statement += s.dialect.noLimit
+		}
+		if query.Offset > 0 {
+			statement += " OFFSET ?"
+			args = append(args, int64(query.Offset))
+		}
+		skip = 0
+	}
+
+	jobs := make([]models.Job, 0)
+	err := s.queryDocuments(tx, func(data []byte) error {
+		var job models.Job
+		if err := s.marshaller.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		if query.Selector != nil && !query.Selector.Matches(labels.Set(job.Labels)) {
+			return nil
+		}
+		if skip > 0 {
+			skip--
+			return nil
+		}
+		jobs = append(jobs, job)
+		if query.Limit > 0 && uint32(len(jobs)) > query.Limit {
+			return errStopQuery
+		}
+		return nil
+	}, statement, args...)
+	if err != nil && !errors.Is(err, errStopQuery) {
+		return nil, err
+	}
+
+	more := query.Limit > 0 && uint32(len(jobs)) > query.Limit
+	if more {
+		jobs = jobs[:query.Limit]
+	}
+
+	response := &jobstore.JobQueryResponse{
+		Jobs:   jobs,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}
+
+	// If we don't have 'limit' jobs, then there definitely aren't any more
+	if more {
+		response.NextOffset = query.Offset + query.Limit
+	}
+
+	return response, nil
+}
+
+func lowerTags(tags []string) []interface{} {
+	return lo.Map(tags, func(tag string, _ int) interface{} {
+		return strings.ToLower(tag)
+	})
+}
+
+// GetExecutions returns the current job state for the provided job id
+func (s *SQLJobStore) GetExecutions(ctx context.Context, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	var state []models.Execution
+
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		state, err = s.getExecutions(tx, options)
+		return
+	})
+
+	return state, err
+}
+
+// GetExecutionsForJobs gets the executions of the jobs with the given full IDs, keyed by job ID
+func (s *SQLJobStore) GetExecutionsForJobs(ctx context.Context, jobIDs []string) (map[string][]models.Execution, error) {
+	executions := make(map[string][]models.Execution, len(jobIDs))
+	if len(jobIDs) == 0 {
+		return executions, nil
+	}
+	args := make([]interface{}, len(jobIDs))
+	for i, jobID := range jobIDs {
+		args[i] = jobID
+	}
+	err := s.transact(ctx, func(tx *txContext) error {
+		return s.queryDocuments(tx, func(data []byte) error {
+			var execution models.Execution
+			if err := s.marshaller.Unmarshal(data, &execution); err != nil {
+				return err
+			}
+			executions[execution.JobID] = append(executions[execution.JobID], execution)
+			return nil
+		}, `SELECT data FROM `+TableExecutions+` WHERE job_id IN (`+placeholders(len(jobIDs))+`) ORDER BY id`, args...)
+	})
+	return executions, err
+}
+
+// GetInProgressJobs gets a list of the currently in-progress jobs, if a job type is supplied then
+// only jobs of that type will be retrieved
+func (s *SQLJobStore) GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error) {
+	var infos []models.Job
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		infos, err = s.getInProgressJobs(tx, jobType)
+		return
+	})
+	return infos, err
+}
+
+func (s *SQLJobStore) getInProgressJobs(tx *txContext, jobType string) ([]models.Job, error) {
+	statement := `SELECT data FROM ` + TableJobs + ` WHERE in_progress = 1`
+	var args []interface{}
+	if jobType != "" {
+		statement += " AND type = ?"
+		args = append(args, jobType)
+	}
+	statement += " ORDER BY type, id"
+	return s.queryJobs(tx, statement, args...)
+}
+
+// GetInProgressJobsInNamespace gets the jobs of a namespace that are not in a terminal state
+func (s *SQLJobStore) GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error) {
+	var infos []models.Job
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		infos, err = s.queryJobs(tx,
+			`SELECT data FROM `+TableJobs+` WHERE namespace = ? AND in_progress = 1 ORDER BY id`, namespace)
+		return
+	})
+	return infos, err
+}
+
+// GetActiveExecutionsOnNode gets the executions placed on a node that are not in a terminal state
+func (s *SQLJobStore) GetActiveExecutionsOnNode(ctx context.Context, nodeID string) ([]models.Execution, error) {
+	var executions []models.Execution
+	err := s.transact(ctx, func(tx *txContext) error {
+		return s.queryDocuments(tx, func(data []byte) error {
+			var execution models.Execution
+			if err := s.marshaller.Unmarshal(data, &execution); err != nil {
+				return err
+			}
+			executions = append(executions, execution)
+			return nil
+		}, `SELECT data FROM `+TableExecutions+` WHERE node_id = ? AND active = 1 ORDER BY id`, nodeID)
+	})
+	return executions, err
+}
+
+// GetDependentJobs gets the jobs that depend on an upstream job
+func (s *SQLJobStore) GetDependentJobs(ctx context.Context, upstreamJobID string) ([]models.Job, error) {
+	var dependents []models.Job
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		dependents, err = s.queryJobs(tx, `SELECT j.data FROM `+TableJobs+` j JOIN `+TableJobDependencies+
+			` d ON d.job_id = j.id WHERE d.upstream_job_id = ? ORDER BY j.id`, upstreamJobID)
+		return
+	})
+	return dependents, err
+}
+
+// GetScheduledRuns gets the runs spawned by a scheduled job, sorted by creation time
+func (s *SQLJobStore) GetScheduledRuns(ctx context.Context, jobID string) ([]models.Job, error) {
+	var runs []models.Job
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		runs, err = s.queryJobs(tx,
+			`SELECT data FROM `+TableJobs+` WHERE scheduled_by = ? ORDER BY create_time, id`, jobID)
+		return
+	})
+	return runs, err
+}
+
+// queryJobs returns the jobs selected by a statement that selects their data
+func (s *SQLJobStore) queryJobs(tx *txContext, statement string, args ...interface{}) ([]models.Job, error) {
+	var infos []models.Job
+	err := s.queryDocuments(tx, func(data []byte) error {
+		var job models.Job
+		if err := s.marshaller.Unmarshal(data, &job); err != nil {
+			return err
+		}
+		infos = append(infos, job)
+		return nil
+	}, statement, args...)
+
+	return infos, err
+}
+
+// GetJobHistory returns the job (and execution) history for the provided options
+func (s *SQLJobStore) GetJobHistory(ctx context.Context,
+	jobID string,
+	options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error) {
+	var history []models.JobHistory
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		history, err = s.getJobHistory(tx, jobID, options)
+		return
+	})
+
+	return history, err
+}
+
+func (s *SQLJobStore) getJobHistory(tx *txContext, jobID string,
+	options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error) {
+	var history []models.JobHistory
+
+	jobID, err := s.reifyJobID(tx, jobID)
+	if err != nil {
+		return history, err
+	}
+
+	appendHistory := func(data []byte) error {
+		var item models.JobHistory
+		if err := s.marshaller.Unmarshal(data, &item); err != nil {
+			return err
+		}
+		history = append(history, item)
+		return nil
+	}
+
+	if !options.ExcludeJobLevel {
+		err = s.queryDocuments(tx, appendHistory,
+			`SELECT data FROM `+TableJobHistory+` WHERE job_id = ? ORDER BY seq`, jobID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !options.ExcludeExecutionLevel {
+		err = s.queryDocuments(tx, appendHistory,
+			`SELECT data FROM `+TableExecutionHistory+` WHERE job_id = ? ORDER BY seq`, jobID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Filter out anything before the specified Since time, and anything that doesn't match the
+	// specified ExecutionID or NodeID
+	history = lo.Filter(history, func(event models.JobHistory, index int) bool {
+		if options.ExecutionID != "" && !strings.HasPrefix(event.ExecutionID, options.ExecutionID) {
+			return false
+		}
+
+		if options.NodeID != "" && !strings.HasPrefix(event.NodeID, options.NodeID) {
+			return false
+		}
+
+		if event.Time.Unix() < options.Since {
+			return false
+		}
+		return true
+	})
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Time.UTC().Before(history[j].Time.UTC()) })
+
+	return history, nil
+}
+
+// CreateJob creates a new record of a job in the data store
+func (s *SQLJobStore) CreateJob(ctx context.Context, job models.Job, event models.Event) error {
+	job.State = models.NewJobState(models.JobStateTypePending)
+	job.Revision = 1
+	job.CreateTime = s.clock.Now().UTC().UnixNano()
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+	job.Normalize()
+	err := job.Validate()
+	if err != nil {
+		return err
+	}
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.createJob(tx, job, event)
+	})
+}
+
+func (s *SQLJobStore) createJob(tx *txContext, job models.Job, event models.Event) error {
+	if exists, err := s.jobExists(tx, job.ID); err != nil {
+		return err
+	} else if exists {
+		return jobstore.NewErrJobAlreadyExists(job.ID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.CreateEvent, job)
+	})
+
+	jobData, err := s.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`INSERT INTO `+TableJobs+`
+		(id, namespace, type, in_progress, scheduled_by, create_time, modify_time, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Namespace, job.Type, 1, job.ScheduledBy(), job.CreateTime, job.ModifyTime, string(jobData))
+	if err != nil {
+		return err
+	}
+
+	// Write rows for specific tags
+	tags := lo.Uniq(lo.Map(lo.Keys(job.Labels), func(tag string, _ int) string {
+		return strings.ToLower(tag)
+	}))
+	for _, tag := range tags {
+		if _, err = tx.exec(`INSERT INTO `+TableJobTags+` (job_id, tag) VALUES (?, ?)`, job.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	// Write rows for the upstream jobs the job depends on
+	for _, dependency := range job.DependsOn {
+		_, err = tx.exec(`INSERT INTO `+TableJobDependencies+` (job_id, upstream_job_id) VALUES (?, ?)`,
+			job.ID, dependency.JobID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.appendJobHistory(tx, job, models.JobStateTypePending, event)
+}
+
+// DeleteJob removes the specified job from the system entirely
+func (s *SQLJobStore) DeleteJob(ctx context.Context, jobID string) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.deleteJob(tx, jobID)
+	})
+}
+
+func (s *SQLJobStore) deleteJob(tx *txContext, jobID string) error {
+	job, err := s.getJob(tx, jobID, true)
+	if err != nil {
+		return bacerrors.NewJobNotFound(jobID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.DeleteEvent, job)
+	})
+
+	// Delete the job and everything that belongs to it
+	tables := []string{
+		TableJobTags, TableJobDependencies, TableExecutions, TableEvaluations, TableJobHistory, TableExecutionHistory, TableResultCache,
+	}
+	for _, table := range tables {
+		if _, err = tx.exec(`DELETE FROM `+table+` WHERE job_id = ?`, job.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.exec(`DELETE FROM `+TableJobs+` WHERE id = ?`, job.ID)
+	return err
+}
+
+// UpdateJobState updates the current state for a single Job, appending an entry to
+// the history at the same time
+func (s *SQLJobStore) UpdateJobState(ctx context.Context, request jobstore.UpdateJobStateRequest) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.updateJobState(tx, request)
+	})
+}
+
+func (s *SQLJobStore) updateJobState(tx *txContext, request jobstore.UpdateJobStateRequest) error {
+	job, err := s.getJob(tx, request.JobID, true)
+	if err != nil {
+		return err
+	}
+
+	// check the expected state
+	if err := request.Condition.Validate(job); err != nil {
+		return err
+	}
+
+	if job.IsTerminal() {
+		return jobstore.NewErrJobAlreadyTerminal(request.JobID, job.State.StateType, request.NewState)
+	}
+
+	// Setup an oncommit handler after the obvious errors/checks
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.JobWatcher, jobstore.UpdateEvent, job)
+	})
+
+	// update the job state
+	previousState := job.State.StateType
+	job.State.StateType = request.NewState
+	job.State.Message = request.Event.Message
+	job.Revision++
+	job.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+	jobStateData, err := s.marshaller.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	// Re-write the state, and remove the job from the in progress jobs once it is terminal
+	_, err = tx.exec(`UPDATE `+TableJobs+` SET in_progress = ?, modify_time = ?, data = ? WHERE id = ?`,
+		lo.Ternary(job.IsTerminal(), 0, 1), job.ModifyTime, string(jobStateData), job.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.appendJobHistory(tx, job, previousState, request.Event)
+}
+
+func (s *SQLJobStore) appendJobHistory(tx *txContext, updateJob models.Job,
+	previousState models.JobStateType, event models.Event) error {
+	historyEntry := models.JobHistory{
+		Type:  models.JobHistoryTypeJobLevel,
+		JobID: updateJob.ID,
+		JobState: &models.StateChange[models.JobStateType]{
+			Previous: previousState,
+			New:      updateJob.State.StateType,
+		},
+		NewRevision: updateJob.Revision,
+		Comment:     event.Message,
+		Event:       event,
+		Time:        time.Unix(0, updateJob.ModifyTime),
+	}
+	data, err := s.marshaller.Marshal(historyEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`INSERT INTO `+TableJobHistory+` (job_id, data) VALUES (?, ?)`, updateJob.ID, string(data))
+	return err
+}
+
+// CreateExecution creates a record of a new execution
+func (s *SQLJobStore) CreateExecution(ctx context.Context, execution models.Execution, event models.Event) error {
+	if execution.CreateTime == 0 {
+		execution.CreateTime = s.clock.Now().UTC().UnixNano()
+	}
+	if execution.ModifyTime == 0 {
+		execution.ModifyTime = execution.CreateTime
+	}
+	if execution.Revision == 0 {
+		execution.Revision = 1
+	}
+	// Ensure the job is not included in the execution when persisting it
+	execution.Job = nil
+	execution.Normalize()
+	err := execution.Validate()
+	if err != nil {
+		return err
+	}
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.createExecution(tx, execution, event)
+	})
+}
+
+func (s *SQLJobStore) createExecution(tx *txContext, execution models.Execution, event models.Event) error {
+	if exists, err := s.jobExists(tx, execution.JobID); err != nil {
+		return err
+	} else if !exists {
+		return jobstore.NewErrJobNotFound(execution.JobID)
+	}
+
+	if _, err := s.getExecution(tx, execution.ID, false); err == nil {
+		return jobstore.NewErrExecutionAlreadyExists(execution.ID)
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.ExecutionWatcher, jobstore.CreateEvent, execution)
+	})
+
+	data, err := s.marshaller.Marshal(execution)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`INSERT INTO `+TableExecutions+` (id, job_id, node_id, active, data) VALUES (?, ?, ?, ?, ?)`,
+		execution.ID, execution.JobID, execution.NodeID, lo.Ternary(execution.IsTerminalComputeState(), 0, 1), string(data))
+	if err != nil {
+		return err
+	}
+
+	return s.appendExecutionHistory(tx, execution, models.ExecutionStateNew, event)
+}
+
+// UpdateExecution updates the state of a single execution by loading from storage,
+// updating and then writing back in a single transaction
+func (s *SQLJobStore) UpdateExecution(ctx context.Context, request jobstore.UpdateExecutionRequest) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.updateExecution(tx, request)
+	})
+}
+
+func (s *SQLJobStore) updateExecution(tx *txContext, request jobstore.UpdateExecutionRequest) error {
+	existingExecution, err := s.getExecution(tx, request.ExecutionID, true)
+	if err != nil {
+		return jobstore.NewErrExecutionNotFound(request.ExecutionID)
+	}
+
+	// check the expected state
+	if err := request.Condition.Validate(existingExecution); err != nil {
+		return err
+	}
+	if err := request.Condition.ValidateTerminal(existingExecution, request.NewValues.ComputeState.StateType); err != nil {
+		return err
+	}
+
+	// populate default values, maintain existing execution createTime
+	newExecution := request.NewValues
+	newExecution.CreateTime = existingExecution.CreateTime
+	if newExecution.ModifyTime == 0 {
+		newExecution.ModifyTime = s.clock.Now().UTC().UnixNano()
+	}
+	if newExecution.Revision == 0 {
+		newExecution.Revision = existingExecution.Revision + 1
+	}
+	newExecution.Normalize()
+
+	err = mergo.Merge(&newExecution, existingExecution)
+	if err != nil {
+		return err
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.ExecutionWatcher, jobstore.UpdateEvent, newExecution)
+	})
+
+	data, err := s.marshaller.Marshal(newExecution)
+	if err != nil {
+		return err
+	}
+
+	// executions are no longer active on their node once they are terminal
+	_, err = tx.exec(`UPDATE `+TableExecutions+` SET active = ?, data = ? WHERE id = ?`,
+		lo.Ternary(newExecution.IsTerminalComputeState(), 0, 1), string(data), newExecution.ID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.appendExecutionHistory(tx, newExecution, existingExecution.ComputeState.StateType, request.Event); err != nil {
+		return err
+	}
+	for _, event := range request.Events {
+		if err = s.appendExecutionHistory(tx, newExecution, newExecution.ComputeState.StateType, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLJobStore) appendExecutionHistory(tx *txContext, updated models.Execution,
+	previous models.ExecutionStateType, event models.Event) error {
+	historyEntry := models.JobHistory{
+		Type:        models.JobHistoryTypeExecutionLevel,
+		JobID:       updated.JobID,
+		NodeID:      updated.NodeID,
+		ExecutionID: updated.ID,
+		ExecutionState: &models.StateChange[models.ExecutionStateType]{
+			Previous: previous,
+			New:      updated.ComputeState.StateType,
+		},
+		NewRevision: updated.Revision,
+		Comment:     event.Message,
+		Event:       event,
+		Time:        time.Unix(0, updated.ModifyTime),
+	}
+
+	data, err := s.marshaller.Marshal(historyEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`INSERT INTO `+TableExecutionHistory+` (job_id, data) VALUES (?, ?)`, updated.JobID, string(data))
+	return err
+}
+
+// CreateEvaluation creates a new evaluation
+func (s *SQLJobStore) CreateEvaluation(ctx context.Context, eval models.Evaluation) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.createEvaluation(tx, eval)
+	})
+}
+
+func (s *SQLJobStore) createEvaluation(tx *txContext, eval models.Evaluation) error {
+	_, err := s.getJob(tx, eval.JobID, false)
+	if err != nil {
+		return err
+	}
+
+	// If there is no error getting an eval with this ID, then it already exists
+	if _, err = s.getEvaluation(tx, eval.ID); err == nil {
+		return bacerrors.NewAlreadyExists(eval.ID, "Evaluation")
+	}
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.EvaluationWatcher, jobstore.CreateEvent, eval)
+	})
+
+	data, err := s.marshaller.Marshal(eval)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`INSERT INTO `+TableEvaluations+` (id, job_id, status, data) VALUES (?, ?, ?, ?)`,
+		eval.ID, eval.JobID, eval.Status, string(data))
+	return err
+}
+
+// GetEvaluation retrieves the specified evaluation
+func (s *SQLJobStore) GetEvaluation(ctx context.Context, id string) (models.Evaluation, error) {
+	var eval models.Evaluation
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		eval, err = s.getEvaluation(tx, id)
+		return
+	})
+
+	return eval, err
+}
+
+func (s *SQLJobStore) getEvaluation(tx *txContext, id string) (models.Evaluation, error) {
+	var eval models.Evaluation
+
+	var data []byte
+	err := tx.queryRow(`SELECT data FROM `+TableEvaluations+` WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return eval, bacerrors.NewEvaluationNotFound(id)
+	} else if err != nil {
+		return eval, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &eval)
+	return eval, err
+}
+
+// GetEvaluations retrieves all evaluations for the specified job
+func (s *SQLJobStore) GetEvaluations(ctx context.Context, jobID string) ([]models.Evaluation, error) {
+	var evals []models.Evaluation
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		job, err := s.getJob(tx, jobID, false)
+		if err != nil {
+			return err
+		}
+		evals, err = s.queryEvaluations(tx,
+			`SELECT data FROM `+TableEvaluations+` WHERE job_id = ? ORDER BY id`, job.ID)
+		return err
+	})
+
+	return evals, err
+}
+
+// GetPendingEvaluations retrieves all evaluations in pending status, across all jobs
+func (s *SQLJobStore) GetPendingEvaluations(ctx context.Context) ([]models.Evaluation, error) {
+	var evals []models.Evaluation
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		evals, err = s.queryEvaluations(tx,
+			`SELECT data FROM `+TableEvaluations+` WHERE status = ? ORDER BY job_id, id`, models.EvalStatusPending)
+		return
+	})
+
+	return evals, err
+}
+
+func (s *SQLJobStore) queryEvaluations(tx *txContext, query string, args ...interface{}) ([]models.Evaluation, error) {
+	var evals []models.Evaluation
+	err := s.queryDocuments(tx, func(data []byte) error {
+		var eval models.Evaluation
+		if err := s.marshaller.Unmarshal(data, &eval); err != nil {
+			return err
+		}
+		evals = append(evals, eval)
+		return nil
+	}, query, args...)
+
+	return evals, err
+}
+
+// UpdateEvaluationStatus updates the status of the specified evaluation
+func (s *SQLJobStore) UpdateEvaluationStatus(ctx context.Context, id string, status string) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		return s.updateEvaluationStatus(tx, id, status)
+	})
+}
+
+func (s *SQLJobStore) updateEvaluationStatus(tx *txContext, id string, status string) error {
+	eval, err := s.getEvaluation(tx, id)
+	if err != nil {
+		return err
+	}
+
+	eval.Status = status
+	eval.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+	tx.OnCommit(func() {
+		s.triggerEvent(jobstore.EvaluationWatcher, jobstore.UpdateEvent, eval)
+	})
+
+	data, err := s.marshaller.Marshal(eval)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.exec(`UPDATE `+TableEvaluations+` SET status = ?, data = ? WHERE id = ?`, status, string(data), id)
+	return err
+}
+
+// DeleteEvaluation deletes the specified evaluation
+func (s *SQLJobStore) DeleteEvaluation(ctx context.Context, id string) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		eval, err := s.getEvaluation(tx, id)
+		if err != nil {
+			return err
+		}
+
+		tx.OnCommit(func() {
+			s.triggerEvent(jobstore.EvaluationWatcher, jobstore.DeleteEvent, eval)
+		})
+
+		_, err = tx.exec(`DELETE FROM `+TableEvaluations+` WHERE id = ?`, id)
+		return err
+	})
+}
+
+// GetNamespaceQuota retrieves the quota of the specified namespace
+func (s *SQLJobStore) GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		quota, err = s.getNamespaceQuota(tx, namespace)
+		return
+	})
+
+	return quota, err
+}
+
+func (s *SQLJobStore) getNamespaceQuota(tx *txContext, namespace string) (models.NamespaceQuota, error) {
+	var quota models.NamespaceQuota
+
+	var data []byte
+	err := tx.queryRow(`SELECT data FROM `+TableNamespaceQuotas+` WHERE namespace = ?`, namespace).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return quota, jobstore.NewErrNamespaceQuotaNotFound(namespace)
+	} else if err != nil {
+		return quota, err
+	}
+
+	err = s.marshaller.Unmarshal(data, &quota)
+	return quota, err
+}
+
+// GetNamespaceQuotas retrieves the quotas of all namespaces that have one
+func (s *SQLJobStore) GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error) {
+	var quotas []models.NamespaceQuota
+	err := s.transact(ctx, func(tx *txContext) error {
+		return s.queryDocuments(tx, func(data []byte) error {
+			var quota models.NamespaceQuota
+			if err := s.marshaller.Unmarshal(data, &quota); err != nil {
+				return err
+			}
+			quotas = append(quotas, quota)
+			return nil
+		}, `SELECT data FROM `+TableNamespaceQuotas+` ORDER BY namespace`)
+	})
+
+	return quotas, err
+}
+
+// PutNamespaceQuota creates or replaces the quota of a namespace
+func (s *SQLJobStore) PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error {
+	quota.Normalize()
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+
+	return s.transact(ctx, func(tx *txContext) error {
+		now := s.clock.Now().UTC().UnixNano()
+		quota.CreateTime = now
+		existing, err := s.getNamespaceQuota(tx, quota.Namespace)
+		if err == nil {
+			quota.CreateTime = existing.CreateTime
+		}
+		quota.ModifyTime = now
+
+		data, err := s.marshaller.Marshal(quota)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`DELETE FROM `+TableNamespaceQuotas+` WHERE namespace = ?`, quota.Namespace)
+		if err != nil {
+			return err
+		}
+		_, err = tx.exec(`INSERT INTO `+TableNamespaceQuotas+` (namespace, data) VALUES (?, ?)`,
+			quota.Namespace, string(data))
+		return err
+	})
+}
+
+// DeleteNamespaceQuota removes the quota of the specified namespace
+func (s *SQLJobStore) DeleteNamespaceQuota(ctx context.Context, namespace string) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		if _, err := s.getNamespaceQuota(tx, namespace); err != nil {
+			return err
+		}
+
+		_, err := tx.exec(`DELETE FROM `+TableNamespaceQuotas+` WHERE namespace = ?`, namespace)
+		return err
+	})
+}
+
+// GetResultCacheEntry retrieves the result cache entry with the specified key in a namespace
+func (s *SQLJobStore) GetResultCacheEntry(
+	ctx context.Context, namespace string, key string) (models.ResultCacheEntry, error) {
+	var entry models.ResultCacheEntry
+	err := s.transact(ctx, func(tx *txContext) error {
+		var data []byte
+		err := tx.queryRow(`SELECT data FROM `+TableResultCache+` WHERE namespace = ? AND cache_key = ?`,
+			namespace, key).Scan(&data)
+		if errors.Is(err, sql.ErrNoRows) {
+			return jobstore.NewErrResultCacheEntryNotFound(namespace, key)
+		} else if err != nil {
+			return err
+		}
+		return s.marshaller.Unmarshal(data, &entry)
+	})
+
+	return entry, err
+}
+
+// PutResultCacheEntry creates or replaces the result cache entry with the same namespace and key
+func (s *SQLJobStore) PutResultCacheEntry(ctx context.Context, entry models.ResultCacheEntry) error {
+	entry.Normalize()
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	entry.CreateTime = s.clock.Now().UTC().UnixNano()
+
+	data, err := s.marshaller.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.transact(ctx, func(tx *txContext) error {
+		_, err := tx.exec(`DELETE FROM `+TableResultCache+` WHERE namespace = ? AND cache_key = ?`,
+			entry.Namespace, entry.Key)
+		if err != nil {
+			return err
+		}
+		_, err = tx.exec(`INSERT INTO `+TableResultCache+` (namespace, cache_key, job_id, data) VALUES (?, ?, ?, ?)`,
+			entry.Namespace, entry.Key, entry.JobID, string(data))
+		return err
+	})
+}
+
+// GetSecret retrieves the secret with the specified name in a namespace
+func (s *SQLJobStore) GetSecret(ctx context.Context, namespace string, name string) (models.Secret, error) {
+	var secret models.Secret
+	err := s.transact(ctx, func(tx *txContext) (err error) {
+		secret, err = s.getSecret(tx, namespace, name)
+		return err
+	})
+
+	return secret, err
+}
+
+func (s *SQLJobStore) getSecret(tx *txContext, namespace string, name string) (models.Secret, error) {
+	var secret models.Secret
+	var data []byte
+	err := tx.queryRow(`SELECT data FROM `+TableSecrets+` WHERE namespace = ? AND name = ?`,
+		namespace, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return secret, jobstore.NewErrSecretNotFound(namespace, name)
+	} else if err != nil {
+		return secret, err
+	}
+	err = s.marshaller.Unmarshal(data, &secret)
+	return secret, err
+}
+
+// GetSecrets retrieves all the secrets of a namespace
+func (s *SQLJobStore) GetSecrets(ctx context.Context, namespace string) ([]models.Secret, error) {
+	var secrets []models.Secret
+	err := s.transact(ctx, func(tx *txContext) error {
+		return s.queryDocuments(tx, func(data []byte) error {
+			var secret models.Secret
+			if err := s.marshaller.Unmarshal(data, &secret); err != nil {
+				return err
+			}
+			secrets = append(secrets, secret)
+			return nil
+		}, `SELECT data FROM `+TableSecrets+` WHERE namespace = ? ORDER BY name`, namespace)
+	})
+
+	return secrets, err
+}
+
+// PutSecret creates or replaces the secret with the same namespace and name
+func (s *SQLJobStore) PutSecret(ctx context.Context, secret models.Secret) error {
+	secret.Normalize()
+	if err := secret.Validate(); err != nil {
+		return err
+	}
+
+	return s.transact(ctx, func(tx *txContext) error {
+		now := s.clock.Now().UTC().UnixNano()
+		secret.CreateTime = now
+		existing, err := s.getSecret(tx, secret.Namespace, secret.Name)
+		if err == nil {
+			secret.CreateTime = existing.CreateTime
+		}
+		secret.ModifyTime = now
+
+		data, err := s.marshaller.Marshal(secret)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`DELETE FROM `+TableSecrets+` WHERE namespace = ? AND name = ?`,
+			secret.Namespace, secret.Name)
+		if err != nil {
+			return err
+		}
+		_, err = tx.exec(`INSERT INTO `+TableSecrets+` (namespace, name, data) VALUES (?, ?, ?)`,
+			secret.Namespace, secret.Name, string(data))
+		return err
+	})
+}
+
+// DeleteSecret removes the secret with the specified name in a namespace
+func (s *SQLJobStore) DeleteSecret(ctx context.Context, namespace string, name string) error {
+	return s.transact(ctx, func(tx *txContext) error {
+		if _, err := s.getSecret(tx, namespace, name); err != nil {
+			return err
+		}
+
+		_, err := tx.exec(`DELETE FROM `+TableSecrets+` WHERE namespace = ? AND name = ?`, namespace, name)
+		return err
+	})
+}
+
+// queryDocuments runs a query that selects a single column of serialized documents,
+// and calls fn with each of them in turn. fn returns errStopQuery to stop reading the documents.
+func (s *SQLJobStore) queryDocuments(tx *txContext, fn func(data []byte) error, query string, args ...interface{}) error {
+	rows, err := tx.query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data []byte
+		if err = rows.Scan(&data); err != nil {
+			return err
+		}
+		if err = fn(data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// queryStrings runs a query that selects a single text column and returns its values
+func (s *SQLJobStore) queryStrings(tx *txContext, query string, args ...interface{}) ([]string, error) {
+	var values []string
+	err := s.queryDocuments(tx, func(data []byte) error {
+		values = append(values, string(data))
+		return nil
+	}, query, args...)
+	return values, err
+}
+
+func (s *SQLJobStore) Close(ctx context.Context) error {
+	s.watcherLock.Lock()
+	for _, w := range s.watchers {
+		w.Close()
+	}
+	s.watcherLock.Unlock()
+
+	log.Ctx(ctx).Debug().Msgf("closing %s-backed job store", s.dialect.name)
+	return s.database.Close()
+}
+
+// Static check to ensure that SQLJobStore implements jobstore.Store
+var _ jobstore.Store = (*SQLJobStore)(nil)
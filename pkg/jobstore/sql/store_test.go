@@ -0,0 +1,59 @@
+//go:build unit || !integration
+
+package sqljobstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	jobstoretest "github.com/bacalhau-project/bacalhau/pkg/jobstore/test"
+)
+
+// postgresTestDSNEnvVar is the connection string of a Postgres database to run the tests against.
+// The tests remove all the data in the job store tables of that database.
+const postgresTestDSNEnvVar = "BACALHAU_TEST_POSTGRES_DSN"
+
+func TestSQLiteJobStoreTestSuite(t *testing.T) {
+	suite.Run(t, jobstoretest.NewJobStoreSuite(func(t *testing.T, clock clock.Clock) jobstore.Store {
+		store, err := NewSQLiteJobStore(filepath.Join(t.TempDir(), "test.db"), WithClock(clock))
+		require.NoError(t, err)
+		return store
+	}))
+}
+
+func TestPostgresJobStoreTestSuite(t *testing.T) {
+	dsn := os.Getenv(postgresTestDSNEnvVar)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresTestDSNEnvVar)
+	}
+	suite.Run(t, jobstoretest.NewJobStoreSuite(func(t *testing.T, clock clock.Clock) jobstore.Store {
+		store, err := NewPostgresJobStore(dsn, WithClock(clock))
+		require.NoError(t, err)
+
+		// start each test from an empty store
+		err = store.transact(context.Background(), func(tx *txContext) error {
+			for _, table := range []string{TableJobs, TableJobTags, TableJobDependencies, TableExecutions, TableEvaluations,
+				TableJobHistory, TableExecutionHistory, TableNamespaceQuotas, TableResultCache, TableSecrets} {
+				if _, err := tx.exec(`DELETE FROM ` + table); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return store
+	}))
+}
+
+func TestRebind(t *testing.T) {
+	query := `SELECT data FROM jobs WHERE namespace = ? AND id IN (?, ?)`
+	require.Equal(t, query, sqliteDialect.rebind(query))
+	require.Equal(t, `SELECT data FROM jobs WHERE namespace = $1 AND id IN ($2, $3)`, postgresDialect.rebind(query))
+}
@@ -0,0 +1,1084 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+// StoreFactory creates an empty job store that uses the given clock
+type StoreFactory func(t *testing.T, clock clock.Clock) jobstore.Store
+
+// JobStoreSuite is the test suite that every jobstore.Store implementation is expected to pass
+type JobStoreSuite struct {
+	suite.Suite
+	newStore StoreFactory
+	store    jobstore.Store
+	ctx      context.Context
+	clock    *clock.Mock
+}
+
+func NewJobStoreSuite(newStore StoreFactory) *JobStoreSuite {
+	return &JobStoreSuite{newStore: newStore}
+}
+
+func (s *JobStoreSuite) SetupTest() {
+	s.clock = clock.NewMock()
+	s.store = s.newStore(s.T(), s.clock)
+	s.ctx = context.Background()
+
+	jobFixtures := []struct {
+		id              string
+		jobType         string
+		client          string
+		tags            map[string]string
+		jobStates       []models.JobStateType
+		executionStates []models.ExecutionStateType
+	}{
+		{
+			id:              "110",
+			client:          "client1",
+			jobType:         "batch",
+			tags:            map[string]string{"gpu": "true", "fast": "true"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
+		},
+		{
+			id:              "120",
+			client:          "client2",
+			jobType:         "batch",
+			tags:            map[string]string{},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
+		},
+		{
+			id:              "130",
+			client:          "client3",
+			jobType:         "batch",
+			tags:            map[string]string{"slow": "true", "max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+		{
+			id:              "140",
+			client:          "client4",
+			jobType:         "batch",
+			tags:            map[string]string{"max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+		{
+			id:              "150",
+			client:          "client5",
+			jobType:         "daemon",
+			tags:            map[string]string{"max": "10"},
+			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
+			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
+		},
+	}
+
+	for _, fixture := range jobFixtures {
+		s.clock.Add(1 * time.Second)
+		job := makeDockerEngineJob(
+			[]string{"bash", "-c", "echo hello"})
+
+		job.ID = fixture.id
+		job.Type = fixture.jobType
+		job.Labels = fixture.tags
+		job.Namespace = fixture.client
+		err := s.store.CreateJob(s.ctx, *job, models.Event{})
+		s.Require().NoError(err)
+
+		s.clock.Add(1 * time.Second)
+		execution := mock.ExecutionForJob(job)
+		execution.ComputeState.StateType = models.ExecutionStateNew
+		// clear out CreateTime and ModifyTime from the mocked execution to let the job store fill those
+		execution.CreateTime = 0
+		execution.ModifyTime = 0
+		err = s.store.CreateExecution(s.ctx, *execution, models.Event{})
+		s.Require().NoError(err)
+
+		for i, state := range fixture.jobStates {
+			s.clock.Add(1 * time.Second)
+
+			oldState := models.JobStateTypePending
+			if i > 0 {
+				oldState = fixture.jobStates[i-1]
+			}
+
+			request := jobstore.UpdateJobStateRequest{
+				JobID:    fixture.id,
+				NewState: state,
+				Condition: jobstore.UpdateJobCondition{
+					ExpectedState:    oldState,
+					ExpectedRevision: uint64(i + 1),
+				},
+				Event: models.Event{},
+			}
+			err = s.store.UpdateJobState(s.ctx, request)
+			s.Require().NoError(err)
+		}
+
+		for i, state := range fixture.executionStates {
+			s.clock.Add(1 * time.Second)
+
+			oldState := models.ExecutionStateNew
+			if i > 0 {
+				oldState = fixture.executionStates[i-1]
+			}
+
+			// We are pretending this is a new execution struct
+			execution.ComputeState.StateType = state
+			execution.ModifyTime = s.clock.Now().UTC().UnixNano()
+
+			request := jobstore.UpdateExecutionRequest{
+				ExecutionID: execution.ID,
+				Condition: jobstore.UpdateExecutionCondition{
+					ExpectedStates:   []models.ExecutionStateType{oldState},
+					ExpectedRevision: uint64(i + 1),
+				},
+				NewValues: *execution,
+				Event:     models.Event{},
+			}
+
+			err = s.store.UpdateExecution(s.ctx, request)
+			s.Require().NoError(err)
+		}
+
+	}
+}
+
+func (s *JobStoreSuite) TearDownTest() {
+	s.store.Close(s.ctx)
+}
+
+func (s *JobStoreSuite) TestUnfilteredJobHistory() {
+	history, err := s.store.GetJobHistory(s.ctx, "110", jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(8, len(history))
+
+	history, err = s.store.GetJobHistory(s.ctx, "11", jobstore.JobHistoryFilterOptions{})
+	s.Require().NoError(err)
+	s.NotEmpty(history)
+	s.Require().Equal("110", history[0].JobID)
+
+	history, err = s.store.GetJobHistory(s.ctx, "1", jobstore.JobHistoryFilterOptions{})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+	s.Require().Nil(history)
+}
+
+func (s *JobStoreSuite) TestJobHistoryOrdering() {
+	history, err := s.store.GetJobHistory(s.ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err, "failed to get job history")
+
+	// There are 6 history entries that we created directly, and 2 created by
+	// CreateJob and CreateExecution
+	require.Equal(s.T(), 8, len(history))
+
+	// Make sure they come back in order
+	values := make([]int64, len(history))
+	for i, h := range history {
+		values[i] = h.Time.Unix()
+	}
+
+	require.Equal(s.T(), []int64{1, 2, 3, 4, 5, 6, 7, 8}, values)
+}
+
+func (s *JobStoreSuite) TestTimeFilteredJobHistory() {
+	options := jobstore.JobHistoryFilterOptions{
+		Since: 5,
+	}
+
+	history, err := s.store.GetJobHistory(s.ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+	require.Equal(s.T(), 4, len(history))
+}
+
+func (s *JobStoreSuite) TestExecutionFilteredJobHistory() {
+	allHistories, err := s.store.GetJobHistory(s.ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err)
+
+	var executionID string
+	for _, h := range allHistories {
+		if h.ExecutionID != "" {
+			executionID = h.ExecutionID
+			break
+		}
+	}
+	require.NotEmpty(s.T(), executionID, "failed to find execution ID")
+
+	options := jobstore.JobHistoryFilterOptions{
+		ExecutionID: executionID,
+	}
+
+	history, err := s.store.GetJobHistory(s.ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+
+	for _, h := range history {
+		require.Equal(s.T(), executionID, h.ExecutionID)
+	}
+}
+
+func (s *JobStoreSuite) TestNodeFilteredJobHistory() {
+	allHistories, err := s.store.GetJobHistory(s.ctx, "110", jobstore.JobHistoryFilterOptions{})
+	require.NoError(s.T(), err)
+
+	var nodeID string
+	for _, h := range allHistories {
+		if h.NodeID != "" {
+			nodeID = h.NodeID
+			break
+		}
+	}
+	require.NotEmpty(s.T(), nodeID, "failed to find node ID")
+
+	options := jobstore.JobHistoryFilterOptions{
+		NodeID: nodeID,
+	}
+
+	history, err := s.store.GetJobHistory(s.ctx, "110", options)
+	require.NoError(s.T(), err, "failed to get job history")
+
+	for _, h := range history {
+		require.Equal(s.T(), nodeID, h.NodeID)
+	}
+}
+
+func (s *JobStoreSuite) TestLevelFilteredJobHistory() {
+	jobOptions := jobstore.JobHistoryFilterOptions{
+		ExcludeExecutionLevel: true,
+	}
+	execOptions := jobstore.JobHistoryFilterOptions{
+		ExcludeJobLevel: true,
+	}
+
+	history, err := s.store.GetJobHistory(s.ctx, "110", jobOptions)
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(4, len(history))
+	s.Require().Equal(models.JobStateTypePending, history[1].JobState.New)
+
+	count := lo.Reduce(history, func(agg int, item models.JobHistory, _ int) int {
+		if item.Type == models.JobHistoryTypeJobLevel {
+			return agg + 1
+		}
+		return agg
+	}, 0)
+	s.Require().Equal(count, 4)
+
+	history, err = s.store.GetJobHistory(s.ctx, "110", execOptions)
+	s.Require().NoError(err, "failed to get job history")
+	s.Require().Equal(4, len(history))
+	s.Require().Equal(models.ExecutionStateAskForBid, history[1].ExecutionState.New)
+
+	count = lo.Reduce(history, func(agg int, item models.JobHistory, _ int) int {
+		if item.Type == models.JobHistoryTypeExecutionLevel {
+			return agg + 1
+		}
+		return agg
+	}, 0)
+	s.Require().Equal(count, 4)
+}
+
+func (s *JobStoreSuite) TestSearchJobs() {
+	s.T().Run("by client ID and included tags", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			Namespace:   "client1",
+			IncludeTags: []string{"fast", "slow"},
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 1, len(jobs))
+		require.Equal(t, "client1", jobs[0].Namespace)
+		require.Contains(t, jobs[0].Labels, "fast")
+		require.NotContains(t, jobs[0].Labels, "slow")
+	})
+
+	s.T().Run("basic selectors", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			Namespace: "client1",
+			Selector:  s.parseLabels("gpu=true,fast=true"),
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 1, len(jobs))
+		require.Equal(t, "client1", jobs[0].Namespace)
+	})
+
+	s.T().Run("all records with selectors and paging", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			SortBy:   "created_at",
+			Selector: s.parseLabels("max>1"),
+			Limit:    2,
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 2, len(jobs))
+
+		// Having skipped the first two s.ids because of non-matching selectors,
+		// we expect the next two to match
+		require.Equal(t, "130", jobs[0].ID)
+		require.Equal(t, "140", jobs[1].ID)
+
+		response, err = s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			SortBy:   "created_at",
+			Selector: s.parseLabels("max>1"),
+			Limit:    2,
+			Offset:   2,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+	})
+
+	s.T().Run("everything sorted by created_at", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll: true,
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 5, len(jobs))
+		ids := lo.Map(jobs, func(item models.Job, _ int) string {
+			return item.ID
+		})
+		require.EqualValues(t, []string{"110", "120", "130", "140", "150"}, ids)
+
+		response, err = s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll:   true,
+			SortReverse: true,
+		})
+		require.NoError(t, err)
+		jobs = response.Jobs
+		require.Equal(t, 5, len(jobs))
+		ids = lo.Map(jobs, func(item models.Job, _ int) string {
+			return item.ID
+		})
+		require.EqualValues(t, []string{"150", "140", "130", "120", "110"}, ids)
+	})
+
+	s.T().Run("everything", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, len(response.Jobs))
+	})
+
+	s.T().Run("everything offset", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Offset:    1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, len(response.Jobs))
+		require.Equal(t, uint32(1), response.Offset)
+	})
+
+	s.T().Run("everything limit", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Limit:     2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(response.Jobs))
+	})
+
+	s.T().Run("everything offset/limit", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll: true,
+			Offset:    1,
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+	})
+
+	s.T().Run("next offset", func(t *testing.T) {
+		for _, tc := range []struct {
+			query      jobstore.JobQuery
+			ids        []string
+			nextOffset uint32
+		}{
+			{jobstore.JobQuery{ReturnAll: true, Limit: 2}, []string{"110", "120"}, 2},
+			{jobstore.JobQuery{ReturnAll: true, Offset: 2, Limit: 3}, []string{"130", "140", "150"}, 0},
+			{jobstore.JobQuery{ReturnAll: true, Offset: 5, Limit: 2}, []string{}, 0},
+			{jobstore.JobQuery{ReturnAll: true, SortReverse: true, Offset: 1, Limit: 2}, []string{"140", "130"}, 3},
+			{jobstore.JobQuery{ReturnAll: true, Selector: s.parseLabels("max>1"), Limit: 1}, []string{"130"}, 1},
+			{jobstore.JobQuery{ReturnAll: true, Selector: s.parseLabels("max>1"), Offset: 1, Limit: 2}, []string{"140", "150"}, 0},
+		} {
+			response, err := s.store.GetJobs(s.ctx, tc.query)
+			require.NoError(t, err)
+			ids := lo.Map(response.Jobs, func(item models.Job, _ int) string {
+				return item.ID
+			})
+			require.EqualValues(t, tc.ids, ids)
+			require.Equal(t, tc.nextOffset, response.NextOffset)
+		}
+	})
+
+	s.T().Run("include tags", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			IncludeTags: []string{"gpu"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.Jobs))
+		require.Equal(t, "110", response.Jobs[0].ID)
+	})
+
+	s.T().Run("all but exclude tags", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			ReturnAll:   true,
+			ExcludeTags: []string{"fast"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, len(response.Jobs))
+	})
+
+	s.T().Run("include/exclude same tag", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			IncludeTags: []string{"gpu"},
+			ExcludeTags: []string{"fast"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(response.Jobs))
+	})
+}
+
+func (s *JobStoreSuite) TestDeleteJob() {
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.Labels = map[string]string{"tag": "value"}
+	job.ID = "deleteme"
+	job.Namespace = "client1"
+
+	err := s.store.CreateJob(s.ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	err = s.store.DeleteJob(s.ctx, job.ID)
+	s.Require().NoError(err)
+}
+
+func (s *JobStoreSuite) TestGetJob() {
+	job, err := s.store.GetJob(s.ctx, "110")
+	s.Require().NoError(err)
+	s.NotNil(job)
+
+	_, err = s.store.GetJob(s.ctx, "100")
+	s.Require().Error(err)
+}
+
+func (s *JobStoreSuite) TestCreateExecution() {
+	job := mock.Job()
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	s.Require().NoError(s.store.CreateExecution(s.ctx, *execution, models.Event{}))
+
+	// Ensure that the execution is created
+	exec, err := s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID: job.ID,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(exec))
+	s.Require().Nil(exec[0].Job)
+
+	// Ensure that the execution is created and the job is included
+	exec, err = s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID:      job.ID,
+		IncludeJob: true,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(exec))
+	s.Require().NotNil(exec[0].Job)
+	s.Require().Equal(job.ID, exec[0].Job.ID)
+}
+
+func (s *JobStoreSuite) TestUpdateTerminalExecution() {
+	job := mock.Job()
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	s.Require().NoError(s.store.CreateExecution(s.ctx, *execution, models.Event{}))
+
+	s.Require().NoError(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues:   models.Execution{ComputeState: models.NewExecutionState(models.ExecutionStateCancelled)},
+		Event:       models.Event{Message: "canceled"},
+		Events:      []models.Event{{Message: "first"}, {Message: "second"}},
+	}))
+
+	// terminal executions can only be updated if the condition allows it, and without changing their state
+	s.Require().Error(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		Event:       models.Event{Message: "rejected"},
+	}))
+	s.Require().Error(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		Condition:   jobstore.UpdateExecutionCondition{AllowTerminal: true},
+		NewValues:   models.Execution{ComputeState: models.NewExecutionState(models.ExecutionStateCompleted)},
+	}))
+	s.Require().NoError(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		Condition:   jobstore.UpdateExecutionCondition{AllowTerminal: true},
+		Event:       models.Event{Message: "after"},
+	}))
+
+	updated, err := s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+	s.Require().NoError(err)
+	s.Require().Len(updated, 1)
+	s.Equal(models.ExecutionStateCancelled, updated[0].ComputeState.StateType)
+
+	history, err := s.store.GetJobHistory(s.ctx, job.ID, jobstore.JobHistoryFilterOptions{ExecutionID: execution.ID})
+	s.Require().NoError(err)
+	var messages []string
+	for _, h := range history {
+		if h.Event.Message == "" {
+			continue
+		}
+		messages = append(messages, h.Event.Message)
+		if h.Event.Message != "canceled" {
+			s.Equal(models.ExecutionStateCancelled, h.ExecutionState.Previous)
+			s.Equal(models.ExecutionStateCancelled, h.ExecutionState.New)
+		}
+	}
+	s.Equal([]string{"canceled", "first", "second", "after"}, messages)
+}
+
+func (s *JobStoreSuite) TestGetExecutions() {
+	state, err := s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID: "110",
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Equal(len(state), 1)
+	s.Nil(state[0].Job)
+
+	state, err = s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID:      "110",
+		IncludeJob: true,
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Equal(len(state), 1)
+	s.NotNil(state[0].Job)
+	s.Equal("110", state[0].Job.ID)
+
+	state, err = s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID: "100",
+	})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.JobNotFound{})
+	s.Require().Nil(state)
+
+	state, err = s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID: "11",
+	})
+	s.Require().NoError(err)
+	s.NotNil(state)
+	s.Require().Equal("110", state[0].JobID)
+
+	state, err = s.store.GetExecutions(s.ctx, jobstore.GetExecutionsOptions{
+		JobID: "1",
+	})
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+	s.Require().Nil(state)
+
+}
+
+func (s *JobStoreSuite) TestInProgressJobs() {
+	infos, err := s.store.GetInProgressJobs(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Equal(3, len(infos))
+	s.Require().Equal("130", infos[0].ID)
+
+	infos, err = s.store.GetInProgressJobs(s.ctx, "batch")
+	s.Require().NoError(err)
+	s.Require().Equal(2, len(infos))
+	s.Require().Equal("130", infos[0].ID)
+
+	infos, err = s.store.GetInProgressJobs(s.ctx, "daemon")
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(infos))
+	s.Require().Equal("150", infos[0].ID)
+}
+
+func (s *JobStoreSuite) TestGetExecutionsForJobs() {
+	executions, err := s.store.GetExecutionsForJobs(s.ctx, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(executions)
+
+	executions, err = s.store.GetExecutionsForJobs(s.ctx, []string{"110", "130", "missing"})
+	s.Require().NoError(err)
+	s.Require().Len(executions, 2)
+	s.Require().Len(executions["110"], 1)
+	s.Require().Equal("110", executions["110"][0].JobID)
+	s.Require().Len(executions["130"], 1)
+	s.Require().Equal("130", executions["130"][0].JobID)
+}
+
+func (s *JobStoreSuite) TestInProgressJobsInNamespace() {
+	infos, err := s.store.GetInProgressJobsInNamespace(s.ctx, "client3")
+	s.Require().NoError(err)
+	s.Require().Len(infos, 1)
+	s.Require().Equal("130", infos[0].ID)
+
+	// stopped jobs are no longer in progress
+	infos, err = s.store.GetInProgressJobsInNamespace(s.ctx, "client2")
+	s.Require().NoError(err)
+	s.Require().Empty(infos)
+
+	s.Require().NoError(s.store.DeleteJob(s.ctx, "130"))
+	infos, err = s.store.GetInProgressJobsInNamespace(s.ctx, "client3")
+	s.Require().NoError(err)
+	s.Require().Empty(infos)
+}
+
+func (s *JobStoreSuite) TestActiveExecutionsOnNode() {
+	job := mock.Job()
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	running := mock.ExecutionForJob(job)
+	running.NodeID = "node-a"
+	completed := mock.ExecutionForJob(job)
+	completed.NodeID = "node-a"
+	other := mock.ExecutionForJob(job)
+	other.NodeID = "node-b"
+	for _, execution := range []*models.Execution{running, completed, other} {
+		s.Require().NoError(s.store.CreateExecution(s.ctx, *execution, models.Event{}))
+	}
+
+	executions, err := s.store.GetActiveExecutionsOnNode(s.ctx, "node-a")
+	s.Require().NoError(err)
+	s.Require().ElementsMatch([]string{running.ID, completed.ID}, lo.Map(executions,
+		func(e models.Execution, _ int) string { return e.ID }))
+
+	// executions leave their node once they are terminal
+	s.Require().NoError(s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: completed.ID,
+		NewValues:   models.Execution{ComputeState: models.NewExecutionState(models.ExecutionStateCompleted)},
+	}))
+	executions, err = s.store.GetActiveExecutionsOnNode(s.ctx, "node-a")
+	s.Require().NoError(err)
+	s.Require().Len(executions, 1)
+	s.Require().Equal(running.ID, executions[0].ID)
+
+	executions, err = s.store.GetActiveExecutionsOnNode(s.ctx, "missing")
+	s.Require().NoError(err)
+	s.Require().Empty(executions)
+
+	s.Require().NoError(s.store.DeleteJob(s.ctx, job.ID))
+	executions, err = s.store.GetActiveExecutionsOnNode(s.ctx, "node-a")
+	s.Require().NoError(err)
+	s.Require().Empty(executions)
+}
+
+func (s *JobStoreSuite) TestScheduledRuns() {
+	scheduled := mock.Job()
+	s.Require().NoError(s.store.CreateJob(s.ctx, *scheduled, models.Event{}))
+	var runIDs []string
+	for i := 0; i < 2; i++ {
+		s.clock.Add(time.Second)
+		run := mock.Job()
+		run.Meta[models.MetaDerivedFrom] = scheduled.ID
+		run.Meta[models.MetaScheduledTime] = s.clock.Now().UTC().Format(time.RFC3339)
+		s.Require().NoError(s.store.CreateJob(s.ctx, *run, models.Event{}))
+		runIDs = append(runIDs, run.ID)
+	}
+	// jobs derived from the scheduled job without being scheduled, such as translated jobs, are not runs
+	translated := mock.Job()
+	translated.Meta[models.MetaDerivedFrom] = scheduled.ID
+	s.Require().NoError(s.store.CreateJob(s.ctx, *translated, models.Event{}))
+
+	runs, err := s.store.GetScheduledRuns(s.ctx, scheduled.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(runIDs, lo.Map(runs, func(j models.Job, _ int) string { return j.ID }))
+
+	s.Require().NoError(s.store.DeleteJob(s.ctx, runIDs[0]))
+	runs, err = s.store.GetScheduledRuns(s.ctx, scheduled.ID)
+	s.Require().NoError(err)
+	s.Require().Len(runs, 1)
+	s.Require().Equal(runIDs[1], runs[0].ID)
+
+	runs, err = s.store.GetScheduledRuns(s.ctx, translated.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(runs)
+}
+
+func (s *JobStoreSuite) TestDependentJobs() {
+	upstream := mock.Job()
+	s.Require().NoError(s.store.CreateJob(s.ctx, *upstream, models.Event{}))
+	other := mock.Job()
+	s.Require().NoError(s.store.CreateJob(s.ctx, *other, models.Event{}))
+
+	var dependentIDs []string
+	for _, dependsOn := range [][]string{{upstream.ID}, {upstream.ID, other.ID}, {other.ID}} {
+		dependent := mock.Job()
+		for _, upstreamID := range dependsOn {
+			dependent.DependsOn = append(dependent.DependsOn, &models.JobDependency{JobID: upstreamID})
+		}
+		s.Require().NoError(s.store.CreateJob(s.ctx, *dependent, models.Event{}))
+		dependentIDs = append(dependentIDs, dependent.ID)
+	}
+	ids := func(jobs []models.Job) []string {
+		return lo.Map(jobs, func(j models.Job, _ int) string { return j.ID })
+	}
+
+	dependents, err := s.store.GetDependentJobs(s.ctx, upstream.ID)
+	s.Require().NoError(err)
+	s.Require().ElementsMatch(dependentIDs[:2], ids(dependents))
+
+	dependents, err = s.store.GetDependentJobs(s.ctx, other.ID)
+	s.Require().NoError(err)
+	s.Require().ElementsMatch(dependentIDs[1:], ids(dependents))
+
+	s.Require().NoError(s.store.DeleteJob(s.ctx, dependentIDs[1]))
+	dependents, err = s.store.GetDependentJobs(s.ctx, upstream.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(dependentIDs[:1], ids(dependents))
+
+	dependents, err = s.store.GetDependentJobs(s.ctx, dependentIDs[0])
+	s.Require().NoError(err)
+	s.Require().Empty(dependents)
+}
+
+func (s *JobStoreSuite) TestShortIDs() {
+	uuidString := "9308d0d2-d93c-4e22-8a5b-c392e614922e"
+	uuidString2 := "9308d0d2-d93c-4e22-8a5b-c392e614922f"
+	shortString := "9308d0d2"
+
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.ID = uuidString
+	job.Namespace = "110"
+
+	// No matches
+	_, err := s.store.GetJob(s.ctx, shortString)
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.JobNotFound{})
+
+	// Create and fetch the single entry
+	err = s.store.CreateJob(s.ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	j, err := s.store.GetJob(s.ctx, shortString)
+	s.Require().NoError(err)
+	s.Require().Equal(uuidString, j.ID)
+
+	// Add a record that will also match and expect an appropriate error
+	job.ID = uuidString2
+	err = s.store.CreateJob(s.ctx, *job, models.Event{})
+	s.Require().NoError(err)
+
+	_, err = s.store.GetJob(s.ctx, shortString)
+	s.Require().Error(err)
+	s.Require().IsType(err, &bacerrors.MultipleJobsFound{})
+}
+
+func (s *JobStoreSuite) TestEvents() {
+	ch := s.store.Watch(s.ctx,
+		jobstore.JobWatcher|jobstore.ExecutionWatcher,
+		jobstore.CreateEvent|jobstore.UpdateEvent|jobstore.DeleteEvent,
+	)
+
+	job := makeDockerEngineJob(
+		[]string{"bash", "-c", "echo hello"})
+	job.ID = "10"
+	job.Namespace = "110"
+
+	var execution models.Execution
+
+	s.Run("job create event", func() {
+		err := s.store.CreateJob(s.ctx, *job, models.Event{})
+		s.Require().NoError(err)
+
+		// Read an event, it should be a jobcreate
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.CreateEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+
+		var decodedJob models.Job
+		err = json.Unmarshal(ev.Object, &decodedJob)
+		s.Require().NoError(err)
+		s.Require().Equal(decodedJob.ID, job.ID)
+	})
+
+	s.Run("execution create event", func() {
+		s.clock.Add(1 * time.Second)
+		execution = *mock.Execution()
+		execution.JobID = "10"
+		execution.ComputeState = models.State[models.ExecutionStateType]{StateType: models.ExecutionStateNew}
+		err := s.store.CreateExecution(s.ctx, execution, models.Event{})
+		s.Require().NoError(err)
+
+		// Read an event, it should be a ExecutionForJob Create
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.CreateEvent)
+		s.Require().Equal(ev.Kind, jobstore.ExecutionWatcher)
+	})
+
+	s.Run("update job state event", func() {
+		request := jobstore.UpdateJobStateRequest{
+			JobID:    "10",
+			NewState: models.JobStateTypeRunning,
+			Condition: jobstore.UpdateJobCondition{
+				ExpectedState: models.JobStateTypePending,
+			},
+			Event: models.Event{Message: "event test"},
+		}
+		_ = s.store.UpdateJobState(s.ctx, request)
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.UpdateEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+	})
+
+	s.Run("update execution state event", func() {
+		execution.ComputeState.StateType = models.ExecutionStateAskForBid
+		execution.ModifyTime = s.clock.Now().UTC().UnixNano()
+		s.store.UpdateExecution(s.ctx, jobstore.UpdateExecutionRequest{
+			ExecutionID: execution.ID,
+			Condition: jobstore.UpdateExecutionCondition{
+				ExpectedStates: []models.ExecutionStateType{models.ExecutionStateNew},
+			},
+			NewValues: execution,
+			Event:     models.Event{Message: "event test"},
+		})
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.UpdateEvent)
+		s.Require().Equal(ev.Kind, jobstore.ExecutionWatcher)
+
+		var decodedExecution models.Execution
+		err := json.Unmarshal(ev.Object, &decodedExecution)
+		s.Require().NoError(err)
+		s.Require().Equal(decodedExecution.ID, execution.ID)
+	})
+
+	s.Run("delete job event", func() {
+		_ = s.store.DeleteJob(s.ctx, job.ID)
+		ev := <-ch
+		s.Require().Equal(ev.Event, jobstore.DeleteEvent)
+		s.Require().Equal(ev.Kind, jobstore.JobWatcher)
+	})
+}
+
+func (s *JobStoreSuite) TestEvaluations() {
+
+	eval := models.Evaluation{
+		ID:    "e1",
+		JobID: "10",
+	}
+
+	// Wrong job ID means JobNotFound
+	err := s.store.CreateEvaluation(s.ctx, eval)
+	s.Require().Error(err)
+
+	// Correct job ID
+	eval.JobID = "110"
+	err = s.store.CreateEvaluation(s.ctx, eval)
+	s.Require().NoError(err)
+
+	_, err = s.store.GetEvaluation(s.ctx, "missing")
+	s.Require().ErrorAs(err, new(*bacerrors.EvaluationNotFound))
+
+	e, err := s.store.GetEvaluation(s.ctx, eval.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(e, eval)
+
+	_, err = s.store.GetEvaluations(s.ctx, "10")
+	s.Require().Error(err)
+
+	evals, err := s.store.GetEvaluations(s.ctx, eval.JobID)
+	s.Require().NoError(err)
+	s.Require().Equal([]models.Evaluation{eval}, evals)
+
+	pending, err := s.store.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(pending)
+
+	err = s.store.UpdateEvaluationStatus(s.ctx, eval.ID, models.EvalStatusPending)
+	s.Require().NoError(err)
+	pending, err = s.store.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Require().Equal(eval.ID, pending[0].ID)
+
+	err = s.store.UpdateEvaluationStatus(s.ctx, eval.ID, models.EvalStatusComplete)
+	s.Require().NoError(err)
+	pending, err = s.store.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(pending)
+
+	err = s.store.UpdateEvaluationStatus(s.ctx, "missing", models.EvalStatusComplete)
+	s.Require().Error(err)
+
+	err = s.store.DeleteEvaluation(s.ctx, eval.ID)
+	s.Require().NoError(err)
+
+	evals, err = s.store.GetEvaluations(s.ctx, eval.JobID)
+	s.Require().NoError(err)
+	s.Require().Empty(evals)
+
+	// deleting a job removes its pending evaluations
+	eval.ID = "e2"
+	eval.Status = models.EvalStatusPending
+	s.Require().NoError(s.store.CreateEvaluation(s.ctx, eval))
+	pending, err = s.store.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Require().NoError(s.store.DeleteJob(s.ctx, eval.JobID))
+	pending, err = s.store.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(pending)
+}
+
+func (s *JobStoreSuite) TestNamespaceQuotas() {
+	_, err := s.store.GetNamespaceQuota(s.ctx, "team-a")
+	s.Require().ErrorAs(err, new(jobstore.ErrNamespaceQuotaNotFound))
+
+	quotas, err := s.store.GetNamespaceQuotas(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(quotas)
+
+	err = s.store.PutNamespaceQuota(s.ctx, models.NamespaceQuota{Namespace: "team-a", MaxConcurrentExecutions: -1})
+	s.Require().Error(err)
+
+	quota := models.NamespaceQuota{
+		Namespace:               "team-a",
+		MaxConcurrentExecutions: 5,
+		MaxResources:            &models.ResourcesConfig{CPU: "4"},
+	}
+	s.Require().NoError(s.store.PutNamespaceQuota(s.ctx, quota))
+	created, err := s.store.GetNamespaceQuota(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Require().Equal(5, created.MaxConcurrentExecutions)
+	s.Require().Equal("4", created.MaxResources.CPU)
+	s.Require().Equal(s.clock.Now().UTC().UnixNano(), created.CreateTime)
+
+	// replacing the quota keeps its creation time
+	s.clock.Add(1 * time.Second)
+	quota.MaxConcurrentExecutions = 10
+	s.Require().NoError(s.store.PutNamespaceQuota(s.ctx, quota))
+	updated, err := s.store.GetNamespaceQuota(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Require().Equal(10, updated.MaxConcurrentExecutions)
+	s.Require().Equal(created.CreateTime, updated.CreateTime)
+	s.Require().Equal(s.clock.Now().UTC().UnixNano(), updated.ModifyTime)
+
+	s.Require().NoError(s.store.PutNamespaceQuota(s.ctx, models.NamespaceQuota{Namespace: "team-b"}))
+	quotas, err = s.store.GetNamespaceQuotas(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(quotas, 2)
+
+	s.Require().NoError(s.store.DeleteNamespaceQuota(s.ctx, "team-a"))
+	_, err = s.store.GetNamespaceQuota(s.ctx, "team-a")
+	s.Require().ErrorAs(err, new(jobstore.ErrNamespaceQuotaNotFound))
+	s.Require().ErrorAs(s.store.DeleteNamespaceQuota(s.ctx, "team-a"), new(jobstore.ErrNamespaceQuotaNotFound))
+}
+
+func (s *JobStoreSuite) TestResultCacheEntries() {
+	_, err := s.store.GetResultCacheEntry(s.ctx, "team-a", "key")
+	s.Require().ErrorAs(err, new(jobstore.ErrResultCacheEntryNotFound))
+
+	err = s.store.PutResultCacheEntry(s.ctx, models.ResultCacheEntry{Namespace: "team-a", Key: "key"})
+	s.Require().Error(err)
+
+	s.Require().NoError(s.store.PutResultCacheEntry(s.ctx,
+		models.ResultCacheEntry{Namespace: "team-a", Key: "key", JobID: "job-1"}))
+	entry, err := s.store.GetResultCacheEntry(s.ctx, "team-a", "key")
+	s.Require().NoError(err)
+	s.Require().Equal("job-1", entry.JobID)
+	s.Require().Equal(s.clock.Now().UTC().UnixNano(), entry.CreateTime)
+
+	// entries are scoped to their namespace
+	_, err = s.store.GetResultCacheEntry(s.ctx, "team-b", "key")
+	s.Require().ErrorAs(err, new(jobstore.ErrResultCacheEntryNotFound))
+
+	// a later job with the same key replaces the entry
+	s.Require().NoError(s.store.PutResultCacheEntry(s.ctx,
+		models.ResultCacheEntry{Namespace: "team-a", Key: "key", JobID: "job-2"}))
+	entry, err = s.store.GetResultCacheEntry(s.ctx, "team-a", "key")
+	s.Require().NoError(err)
+	s.Require().Equal("job-2", entry.JobID)
+}
+
+func (s *JobStoreSuite) TestDeleteJobDeletesResultCacheEntries() {
+	job := mock.Job()
+	job.Namespace = "team-a"
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	s.Require().NoError(s.store.PutResultCacheEntry(s.ctx,
+		models.ResultCacheEntry{Namespace: "team-a", Key: "key", JobID: job.ID}))
+	s.Require().NoError(s.store.PutResultCacheEntry(s.ctx,
+		models.ResultCacheEntry{Namespace: "team-a", Key: "other", JobID: "other-job"}))
+
+	s.Require().NoError(s.store.DeleteJob(s.ctx, job.ID))
+	_, err := s.store.GetResultCacheEntry(s.ctx, "team-a", "key")
+	s.Require().ErrorAs(err, new(jobstore.ErrResultCacheEntryNotFound))
+	entry, err := s.store.GetResultCacheEntry(s.ctx, "team-a", "other")
+	s.Require().NoError(err)
+	s.Require().Equal("other-job", entry.JobID)
+}
+
+func (s *JobStoreSuite) TestSecrets() {
+	_, err := s.store.GetSecret(s.ctx, "team-a", "token")
+	s.Require().ErrorAs(err, new(jobstore.ErrSecretNotFound))
+	s.Require().ErrorAs(s.store.DeleteSecret(s.ctx, "team-a", "token"), new(jobstore.ErrSecretNotFound))
+
+	err = s.store.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "token"})
+	s.Require().Error(err)
+
+	created := s.clock.Now().UTC().UnixNano()
+	s.Require().NoError(s.store.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "token", Value: "v1"}))
+	s.Require().NoError(s.store.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "key", Value: "k1"}))
+	s.Require().NoError(s.store.PutSecret(s.ctx, models.Secret{Namespace: "team-b", Name: "token", Value: "b1"}))
+
+	// replacing a secret keeps its creation time
+	s.clock.Add(1 * time.Second)
+	s.Require().NoError(s.store.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "token", Value: "v2"}))
+	secret, err := s.store.GetSecret(s.ctx, "team-a", "token")
+	s.Require().NoError(err)
+	s.Require().Equal("v2", secret.Value)
+	s.Require().Equal(created, secret.CreateTime)
+	s.Require().Equal(s.clock.Now().UTC().UnixNano(), secret.ModifyTime)
+
+	// secrets are scoped to their namespace and listed by name
+	secrets, err := s.store.GetSecrets(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Require().Len(secrets, 2)
+	s.Require().Equal("key", secrets[0].Name)
+	s.Require().Equal("token", secrets[1].Name)
+
+	s.Require().NoError(s.store.DeleteSecret(s.ctx, "team-a", "token"))
+	_, err = s.store.GetSecret(s.ctx, "team-a", "token")
+	s.Require().ErrorAs(err, new(jobstore.ErrSecretNotFound))
+	secret, err = s.store.GetSecret(s.ctx, "team-b", "token")
+	s.Require().NoError(err)
+	s.Require().Equal("b1", secret.Value)
+}
+
+func (s *JobStoreSuite) parseLabels(selector string) labels.Selector {
+	req, err := labels.ParseToRequirements(selector)
+	s.NoError(err)
+
+	return labels.NewSelector().Add(req...)
+}
+
+func makeDockerEngineJob(entrypointArray []string) *models.Job {
+	j := mock.Job()
+	j.Task().Engine = &models.SpecConfig{
+		Type: models.EngineDocker,
+		Params: map[string]interface{}{
+			"Image":      "ubuntu:latest",
+			"Entrypoint": entrypointArray,
+		},
+	}
+	return j
+}
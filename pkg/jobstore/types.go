@@ -55,11 +55,31 @@ type Store interface {
 	// [JobQuery]. If it fails, it will return an error
 	GetJobs(ctx context.Context, query JobQuery) (*JobQueryResponse, error)
 
+	// GetExecutionsForJobs retrieves the executions of the jobs with the given full IDs in a single read,
+	// keyed by job ID. Jobs that do not exist have no executions.
+	GetExecutionsForJobs(ctx context.Context, jobIDs []string) (map[string][]models.Execution, error)
+
 	// GetInProgressJobs retrieves all jobs that have a state that can be
 	// considered, 'in progress'. Failure generates an error. If the jobType
 	// is provided, only active jobs of that type will be returned.
 	GetInProgressJobs(ctx context.Context, jobType string) ([]models.Job, error)
 
+	// GetInProgressJobsInNamespace retrieves the jobs of a namespace that have a state
+	// that can be considered 'in progress', without reading the jobs of other namespaces.
+	GetInProgressJobsInNamespace(ctx context.Context, namespace string) ([]models.Job, error)
+
+	// GetActiveExecutionsOnNode retrieves the executions placed on a node that are not in a terminal
+	// state, across all jobs, without reading the executions of other nodes.
+	GetActiveExecutionsOnNode(ctx context.Context, nodeID string) ([]models.Execution, error)
+
+	// GetDependentJobs retrieves the jobs that depend on the upstream job with the given full ID,
+	// whatever their state, without reading the jobs that don't.
+	GetDependentJobs(ctx context.Context, upstreamJobID string) ([]models.Job, error)
+
+	// GetScheduledRuns retrieves the runs spawned by the scheduled job with the given full ID,
+	// sorted by creation time, without reading the other jobs of its namespace.
+	GetScheduledRuns(ctx context.Context, jobID string) ([]models.Job, error)
+
 	// GetJobHistory retrieves the history for the specified job.  The
 	// history returned is filtered by the contents of the provided
 	// [JobHistoryFilterOptions].
@@ -82,7 +102,8 @@ type Store interface {
 	// within [UpdateExecutionRequest].
 	UpdateExecution(ctx context.Context, request UpdateExecutionRequest) error
 
-	// DeleteJob removes all trace of the provided job from storage
+	// DeleteJob removes all trace of the provided job from storage,
+	// including the result cache entries that reuse its results
 	DeleteJob(ctx context.Context, jobID string) error
 
 	// CreateEvaluation creates a new evaluation
@@ -91,9 +112,49 @@ type Store interface {
 	// GetEvaluation retrieves the specified evaluation
 	GetEvaluation(ctx context.Context, id string) (models.Evaluation, error)
 
+	// GetEvaluations retrieves all evaluations for the specified job
+	GetEvaluations(ctx context.Context, jobID string) ([]models.Evaluation, error)
+
+	// GetPendingEvaluations retrieves all evaluations in pending status, across all jobs
+	GetPendingEvaluations(ctx context.Context) ([]models.Evaluation, error)
+
+	// UpdateEvaluationStatus updates the status of the specified evaluation
+	UpdateEvaluationStatus(ctx context.Context, id string, status string) error
+
 	// DeleteEvaluation deletes the specified evaluation
 	DeleteEvaluation(ctx context.Context, id string) error
 
+	// GetNamespaceQuota retrieves the quota of the specified namespace
+	GetNamespaceQuota(ctx context.Context, namespace string) (models.NamespaceQuota, error)
+
+	// GetNamespaceQuotas retrieves the quotas of all namespaces that have one
+	GetNamespaceQuotas(ctx context.Context) ([]models.NamespaceQuota, error)
+
+	// PutNamespaceQuota creates or replaces the quota of a namespace
+	PutNamespaceQuota(ctx context.Context, quota models.NamespaceQuota) error
+
+	// DeleteNamespaceQuota removes the quota of the specified namespace
+	DeleteNamespaceQuota(ctx context.Context, namespace string) error
+
+	// GetResultCacheEntry retrieves the result cache entry with the specified key in a namespace
+	GetResultCacheEntry(ctx context.Context, namespace string, key string) (models.ResultCacheEntry, error)
+
+	// PutResultCacheEntry creates or replaces the result cache entry with the same namespace and key
+	PutResultCacheEntry(ctx context.Context, entry models.ResultCacheEntry) error
+
+	// GetSecret retrieves the secret with the specified name in a namespace. The value of the
+	// secret is returned as it was stored, which is encrypted by the caller.
+	GetSecret(ctx context.Context, namespace string, name string) (models.Secret, error)
+
+	// GetSecrets retrieves all the secrets of a namespace
+	GetSecrets(ctx context.Context, namespace string) ([]models.Secret, error)
+
+	// PutSecret creates or replaces the secret with the same namespace and name
+	PutSecret(ctx context.Context, secret models.Secret) error
+
+	// DeleteSecret removes the secret with the specified name in a namespace
+	DeleteSecret(ctx context.Context, namespace string, name string) error
+
 	// Close provides an interface to cleanup any resources in use when the
 	// store is no longer required
 	Close(ctx context.Context) error
@@ -111,6 +172,8 @@ type UpdateExecutionRequest struct {
 	Condition   UpdateExecutionCondition
 	NewValues   models.Execution
 	Event       models.Event
+	// Events are recorded in the history of the execution after Event, each in its own entry.
+	Events []models.Event
 }
 
 type UpdateJobCondition struct {
@@ -141,6 +204,22 @@ type UpdateExecutionCondition struct {
 	ExpectedStates   []models.ExecutionStateType
 	ExpectedRevision uint64
 	UnexpectedStates []models.ExecutionStateType
+	// AllowTerminal allows updating an execution in a terminal state, such as to record what happened
+	// after it stopped, as long as the update doesn't change its state.
+	AllowTerminal bool
+}
+
+// ValidateTerminal checks if the execution can be updated to the new state, which is only allowed for
+// executions in a terminal state if the condition allows it and the state doesn't change.
+func (condition UpdateExecutionCondition) ValidateTerminal(
+	execution models.Execution, newState models.ExecutionStateType) error {
+	if !execution.IsTerminalComputeState() {
+		return nil
+	}
+	if condition.AllowTerminal && (newState.IsUndefined() || newState == execution.ComputeState.StateType) {
+		return nil
+	}
+	return NewErrExecutionAlreadyTerminal(execution.ID, execution.ComputeState.StateType, newState)
 }
 
 // Validate checks if the condition matches the given execution
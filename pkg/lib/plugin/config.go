@@ -0,0 +1,23 @@
+package plugin
+
+import (
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Configs returns the configuration of the named plugins in dir, whose binaries are named
+// after the plugin with the given prefix. For example, with the prefix "bacalhau-publisher-",
+// the plugin "ftp" is the binary "bacalhau-publisher-ftp".
+func Configs(dir string, prefix string, handshake goplugin.HandshakeConfig, names []string) []Config {
+	configs := make([]Config, 0, len(names))
+	for _, name := range names {
+		configs = append(configs, Config{
+			Name:             name,
+			Path:             dir,
+			Command:          prefix + name,
+			ProtocolVersion:  handshake.ProtocolVersion,
+			MagicCookieKey:   handshake.MagicCookieKey,
+			MagicCookieValue: handshake.MagicCookieValue,
+		})
+	}
+	return configs
+}
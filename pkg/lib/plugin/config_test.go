@@ -0,0 +1,44 @@
+//go:build unit || !integration
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc"
+)
+
+func TestConfigs(t *testing.T) {
+	handshake := goplugin.HandshakeConfig{ProtocolVersion: 1, MagicCookieKey: "KEY", MagicCookieValue: "value"}
+
+	require.Empty(t, Configs("/plugins", "bacalhau-publisher-", handshake, nil))
+	require.Equal(t, []Config{{
+		Name:             "ftp",
+		Path:             "/plugins",
+		Command:          "bacalhau-publisher-ftp",
+		ProtocolVersion:  1,
+		MagicCookieKey:   "KEY",
+		MagicCookieValue: "value",
+	}}, Configs("/plugins", "bacalhau-publisher-", handshake, []string{"ftp"}))
+}
+
+func TestStartAvailable_SkipsBrokenPlugins(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bacalhau-publisher-broken"), []byte("#!/bin/sh\nexit 1\n"), 0700))
+
+	m := NewManager[publisher.Publisher](grpc.PluggablePublisherPluginName, &grpc.PublisherGRPCPlugin{})
+	configs := Configs(dir, "bacalhau-publisher-", grpc.HandshakeConfig, []string{"broken", "missing"})
+	require.NoError(t, m.RegisterPlugin(configs[0]))
+	require.Error(t, m.RegisterPlugin(configs[1]))
+
+	m.StartAvailable(context.Background())
+	require.Empty(t, m.Keys(context.Background()))
+	require.Error(t, m.Start(context.Background()), "Start still fails on broken plugins")
+}
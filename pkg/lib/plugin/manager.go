@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+)
+
+// Config describes a plugin binary and the handshake it expects
+type Config struct {
+	Name             string
+	Path             string
+	Command          string
+	ProtocolVersion  uint
+	MagicCookieKey   string
+	MagicCookieValue string
+}
+
+// Manager launches plugins as separate processes and provides the
+// implementations of P they serve over gRPC, keyed by plugin name.
+type Manager[P provider.Providable] struct {
+	pluginName string
+	plugin     goplugin.Plugin
+	registered map[string]Config
+	active     map[string]*activePlugin[P]
+}
+
+type activePlugin[P provider.Providable] struct {
+	Impl   P
+	Closer func()
+}
+
+// NewManager returns a manager of plugins that serve the given plugin under pluginName
+func NewManager[P provider.Providable](pluginName string, plugin goplugin.Plugin) *Manager[P] {
+	return &Manager[P]{
+		pluginName: pluginName,
+		plugin:     plugin,
+		registered: make(map[string]Config),
+		active:     make(map[string]*activePlugin[P]),
+	}
+}
+
+func (m *Manager[P]) Get(ctx context.Context, key string) (P, error) {
+	active, ok := m.active[key]
+	if !ok {
+		var p P
+		return p, fmt.Errorf("plugin %s not found", key)
+	}
+	return active.Impl, nil
+}
+
+func (m *Manager[P]) Has(ctx context.Context, key string) bool {
+	_, ok := m.active[key]
+	return ok
+}
+
+// Keys returns the keys of the started plugins
+func (m *Manager[P]) Keys(ctx context.Context) []string {
+	keys := make([]string, 0, len(m.active))
+	for k := range m.active {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// compile-time check that Manager implements Provider
+var _ provider.Provider[provider.Providable] = (*Manager[provider.Providable])(nil)
+
+func (m *Manager[P]) RegisterPlugin(config Config) error {
+	_, ok := m.registered[config.Name]
+	if ok {
+		return fmt.Errorf("duplicate registration of plugin %s", config.Name)
+	}
+
+	if pluginBin, err := os.Stat(filepath.Join(config.Path, config.Command)); err != nil {
+		return err
+	} else if pluginBin.IsDir() {
+		return fmt.Errorf("plugin location is directory, expected binary")
+	}
+	// TODO check if binary is executable
+
+	m.registered[config.Name] = config
+	return nil
+}
+
+func (m *Manager[P]) Start(ctx context.Context) error {
+	for name, config := range m.registered {
+		impl, closer, err := m.dispense(config)
+		if err != nil {
+			return fmt.Errorf("failed to start plugin %s: %w", name, err)
+		}
+		m.active[name] = &activePlugin[P]{
+			Impl:   impl,
+			Closer: closer,
+		}
+	}
+	return nil
+}
+
+// StartAvailable starts the registered plugins like Start, but logs and skips plugins
+// that fail to start instead of failing, so one broken plugin does not stop the others.
+func (m *Manager[P]) StartAvailable(ctx context.Context) {
+	for name, config := range m.registered {
+		impl, closer, err := m.dispense(config)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("skipping plugin %s that failed to start", name)
+			continue
+		}
+		m.active[name] = &activePlugin[P]{
+			Impl:   impl,
+			Closer: closer,
+		}
+	}
+}
+
+func (m *Manager[P]) Stop(ctx context.Context) error {
+	for _, active := range m.active {
+		active.Closer()
+	}
+	return nil
+}
+
+func (m *Manager[P]) dispense(config Config) (P, func(), error) {
+	var impl P
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		Plugins: map[string]goplugin.Plugin{
+			m.pluginName: m.plugin,
+		},
+		AllowedProtocols: []goplugin.Protocol{
+			goplugin.ProtocolNetRPC, goplugin.ProtocolGRPC},
+		HandshakeConfig: goplugin.HandshakeConfig{
+			ProtocolVersion:  config.ProtocolVersion,
+			MagicCookieKey:   config.MagicCookieKey,
+			MagicCookieValue: config.MagicCookieValue,
+		},
+		//nolint:gosec
+		Cmd: exec.Command(filepath.Join(config.Path, config.Command)),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return impl, nil, err
+	}
+
+	raw, err := rpcClient.Dispense(m.pluginName)
+	if err != nil {
+		client.Kill()
+		return impl, nil, err
+	}
+
+	impl, ok := raw.(P)
+	if !ok {
+		client.Kill()
+		return impl, nil, fmt.Errorf("plugin %s does not serve a %s", config.Name, m.pluginName)
+	}
+
+	return impl, func() { client.Kill() }, nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// CheckpointConfig enables the checkpointing of a task, so that when its execution is stopped
+// before it completes, such as when it is preempted or its node is drained, a later execution
+// of the same job resumes from the last checkpoint instead of starting from scratch.
+// Checkpoints are stored through the publisher of the task.
+type CheckpointConfig struct {
+	// Path is the directory inside the task where it keeps the state it resumes from.
+	// It is checkpointed along with the result paths of the task.
+	Path string `json:"Path"`
+
+	// Interval is the interval in seconds at which the task is checkpointed while it runs.
+	// Zero only checkpoints the task when its execution is stopped.
+	Interval int64 `json:"Interval,omitempty"`
+}
+
+// GetInterval returns the checkpoint interval duration
+func (c *CheckpointConfig) GetInterval() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Copy returns a deep copy of the checkpoint config.
+func (c *CheckpointConfig) Copy() *CheckpointConfig {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	return &nc
+}
+
+func (c *CheckpointConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	var mErr error
+	if !filepath.IsAbs(c.Path) {
+		mErr = errors.Join(mErr, fmt.Errorf("checkpoint path must be absolute: %q", c.Path))
+	}
+	if c.Interval < 0 {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid checkpoint interval value: %s", c.GetInterval()))
+	}
+	return mErr
+}
+
+// Checkpoint is a checkpoint of an execution, stored through the publisher of its task.
+type Checkpoint struct {
+	// ExecutionID is the ID of the execution that was checkpointed
+	ExecutionID string `json:"ExecutionID"`
+
+	// Result is where the checkpoint was stored, as returned by the publisher.
+	Result *SpecConfig `json:"Result"`
+
+	// Time is when the checkpoint was taken
+	Time int64 `json:"Time"`
+}
+
+// Copy returns a deep copy of the checkpoint.
+func (c *Checkpoint) Copy() *Checkpoint {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	nc.Result = c.Result.Copy()
+	return &nc
+}
@@ -0,0 +1,52 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointConfig_Validate(t *testing.T) {
+	var nilConfig *CheckpointConfig
+	assert.NoError(t, nilConfig.Validate())
+	assert.NoError(t, (&CheckpointConfig{Path: "/checkpoint"}).Validate())
+	assert.NoError(t, (&CheckpointConfig{Path: "/checkpoint", Interval: 600}).Validate())
+	assert.Error(t, (&CheckpointConfig{}).Validate())
+	assert.Error(t, (&CheckpointConfig{Path: "checkpoint"}).Validate())
+	assert.Error(t, (&CheckpointConfig{Path: "/checkpoint", Interval: -1}).Validate())
+}
+
+func TestCheckpointConfig_GetInterval(t *testing.T) {
+	var nilConfig *CheckpointConfig
+	assert.Equal(t, time.Duration(0), nilConfig.GetInterval())
+	assert.Equal(t, 10*time.Minute, (&CheckpointConfig{Path: "/checkpoint", Interval: 600}).GetInterval())
+}
+
+func TestCheckpoint_Copy(t *testing.T) {
+	checkpoint := &Checkpoint{
+		ExecutionID: "e-1",
+		Result:      &SpecConfig{Type: "s3", Params: map[string]interface{}{"Key": "checkpoint"}},
+		Time:        1,
+	}
+	cp := checkpoint.Copy()
+	assert.Equal(t, checkpoint, cp)
+	cp.Result.Params["Key"] = "other"
+	assert.Equal(t, "checkpoint", checkpoint.Result.Params["Key"])
+}
+
+func TestTask_ValidateCheckpoint(t *testing.T) {
+	task := &Task{
+		Name:      "main",
+		Engine:    &SpecConfig{Type: "docker"},
+		Publisher: &SpecConfig{Type: "s3"},
+	}
+	task.Normalize()
+	task.Checkpoint = &CheckpointConfig{Path: "/checkpoint"}
+	assert.NoError(t, task.ValidateSubmission())
+
+	task.Publisher = &SpecConfig{}
+	assert.Error(t, task.ValidateSubmission())
+}
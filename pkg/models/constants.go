@@ -21,6 +21,10 @@ const (
 	// JobTypeOps represents a batch job that runs to completion on all nodes matching
 	// the specified constraints.
 	JobTypeOps = "ops"
+
+	// JobTypeScheduled represents a job that spawns a new batch job each time its
+	// cron schedule fires.
+	JobTypeScheduled = "scheduled"
 )
 
 const (
@@ -71,4 +75,8 @@ const (
 	// it may have been translated from another job.
 	MetaDerivedFrom  = "bacalhau.org/derivedFrom"
 	MetaTranslatedBy = "bacalhau.org/translatedBy"
+
+	// MetaScheduledTime is the time a job spawned by a scheduled job was due to run,
+	// where MetaDerivedFrom holds the ID of the scheduled job.
+	MetaScheduledTime = "bacalhau.org/scheduledTime"
 )
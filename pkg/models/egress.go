@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxEgressDeniedHosts is the maximum number of distinct denied hosts kept in an egress log.
+const maxEgressDeniedHosts = 20
+
+// EgressLog is the outbound requests an execution made through the egress filtering of its network config,
+// so that users can see which requests were denied.
+type EgressLog struct {
+	// Allowed and Denied are the number of requests allowed and denied.
+	Allowed uint64 `json:"Allowed"`
+	Denied  uint64 `json:"Denied"`
+
+	// DeniedHosts are the distinct hosts that requests were denied to, in the order they were first requested,
+	// up to a limit.
+	DeniedHosts []string `json:"DeniedHosts,omitempty"`
+}
+
+// Record records a request to the host.
+func (l *EgressLog) Record(host string, allowed bool) {
+	if allowed {
+		l.Allowed++
+		return
+	}
+	l.Denied++
+	if len(l.DeniedHosts) < maxEgressDeniedHosts && !slices.Contains(l.DeniedHosts, host) {
+		l.DeniedHosts = append(l.DeniedHosts, host)
+	}
+}
+
+// IsEmpty returns true if no requests were recorded.
+func (l *EgressLog) IsEmpty() bool {
+	return l == nil || l.Allowed+l.Denied == 0
+}
+
+// Copy returns a deep copy of the log
+func (l *EgressLog) Copy() *EgressLog {
+	if l == nil {
+		return nil
+	}
+	return &EgressLog{
+		Allowed:     l.Allowed,
+		Denied:      l.Denied,
+		DeniedHosts: slices.Clone(l.DeniedHosts),
+	}
+}
+
+func (l *EgressLog) String() string {
+	if l.Denied == 0 {
+		return fmt.Sprintf("%d outbound requests allowed", l.Allowed)
+	}
+	return fmt.Sprintf("%d outbound requests allowed, %d denied by the network config to %s",
+		l.Allowed, l.Denied, strings.Join(l.DeniedHosts, ", "))
+}
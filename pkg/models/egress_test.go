@@ -0,0 +1,34 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEgressLog(t *testing.T) {
+	var log *EgressLog
+	assert.True(t, log.IsEmpty())
+
+	log = &EgressLog{}
+	log.Record("example.com", true)
+	log.Record("denied.com", false)
+	log.Record("denied.com", false)
+	log.Record("other.com", false)
+	assert.False(t, log.IsEmpty())
+	assert.Equal(t, &EgressLog{Allowed: 1, Denied: 3, DeniedHosts: []string{"denied.com", "other.com"}}, log)
+	assert.Equal(t, "1 outbound requests allowed, 3 denied by the network config to denied.com, other.com", log.String())
+
+	copied := log.Copy()
+	copied.Record("copy.com", false)
+	assert.Len(t, log.DeniedHosts, 2)
+
+	for i := 0; i < 2*maxEgressDeniedHosts; i++ {
+		log.Record(fmt.Sprintf("host%d.com", i), false)
+	}
+	assert.Len(t, log.DeniedHosts, maxEgressDeniedHosts)
+	assert.Equal(t, uint64(3+2*maxEgressDeniedHosts), log.Denied)
+}
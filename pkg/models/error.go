@@ -7,6 +7,7 @@ const (
 	DetailsKeyHint           = "Hint"
 	DetailsKeyRetryable      = "Retryable"
 	DetailsKeyFailsExecution = "FailsExecution"
+	DetailsKeyFailureReason  = "FailureReason"
 )
 
 type HasHint interface {
@@ -88,6 +89,16 @@ func (e *BaseError) WithDetails(details map[string]string) *BaseError {
 	return e
 }
 
+// WithFailureReason is a method that records the failure reason in the
+// details of BaseError and returns the BaseError itself for chaining.
+func (e *BaseError) WithFailureReason(reason FailureReason) *BaseError {
+	if e.details == nil {
+		e.details = make(map[string]string)
+	}
+	e.details[DetailsKeyFailureReason] = string(reason)
+	return e
+}
+
 // Error is a method that returns the message field of BaseError. This
 // method makes BaseError satisfy the error interface.
 func (e *BaseError) Error() string {
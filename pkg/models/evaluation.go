@@ -17,11 +17,18 @@ const (
 )
 
 const (
-	EvalTriggerJobRegister = "job-register"
-	EvalTriggerJobCancel   = "job-cancel"
-	EvalTriggerExecFailure = "exec-failure"
-	EvalTriggerExecUpdate  = "exec-update"
-	EvalTriggerExecTimeout = "exec-timeout"
+	EvalTriggerJobRegister   = "job-register"
+	EvalTriggerJobCancel     = "job-cancel"
+	EvalTriggerExecFailure   = "exec-failure"
+	EvalTriggerExecUpdate    = "exec-update"
+	EvalTriggerExecTimeout   = "exec-timeout"
+	EvalTriggerJobDependency = "job-dependency"
+	EvalTriggerRetryDelay    = "retry-delay"
+	EvalTriggerScheduleTick  = "schedule-tick"
+	EvalTriggerJobRestore    = "job-restore"
+	EvalTriggerPreemption    = "preemption"
+	EvalTriggerQuotaWait     = "quota-wait"
+	EvalTriggerNodeDrain     = "node-drain"
 )
 
 // Evaluation is just to ask the scheduler to reassess if additional job instances must be
@@ -0,0 +1,34 @@
+package models
+
+// ExecInput is a message sent by a client to a command running in, or attached to, an execution.
+type ExecInput struct {
+	// Data is written to the standard input of the command
+	Data []byte `json:"Data,omitempty"`
+
+	// Resize resizes the terminal of the command, if it runs with a TTY
+	Resize *TerminalSize `json:"Resize,omitempty"`
+
+	// CloseStdin closes the standard input of the command
+	CloseStdin bool `json:"CloseStdin,omitempty"`
+}
+
+// TerminalSize is the size of a terminal in characters
+type TerminalSize struct {
+	Height uint `json:"Height"`
+	Width  uint `json:"Width"`
+}
+
+// ExecOutput is a message sent to a client by a command running in, or attached to, an execution.
+type ExecOutput struct {
+	// Type is the stream the data was written to by the command
+	Type ExecutionLogType `json:"Type,omitempty"`
+
+	// Data is the output of the command
+	Data []byte `json:"Data,omitempty"`
+
+	// Exited is set on the last message of the stream, once the command has exited
+	Exited bool `json:"Exited,omitempty"`
+
+	// ExitCode is the exit code of the command, if it has exited
+	ExitCode int `json:"ExitCode,omitempty"`
+}
@@ -81,6 +81,10 @@ type Execution struct {
 	// TODO: evaluate using a copy of the job instead of a pointer
 	Job *Job `json:"Job,omitempty"`
 
+	// ArrayIndex is the index of the execution within an array job.
+	// Only meaningful if the job is an array job.
+	ArrayIndex int `json:"ArrayIndex"`
+
 	// AllocatedResources is the total resources allocated for the execution tasks.
 	AllocatedResources *AllocatedResources `json:"AllocatedResources"`
 
@@ -97,6 +101,18 @@ type Execution struct {
 	// TODO: evaluate removing this from execution spec in favour of calling `bacalhau logs`
 	RunOutput *RunCommandResult `json:"RunOutput"`
 
+	// ResourceUsage is the resources the execution actually used, as measured by its executor.
+	ResourceUsage *ResourceUsage `json:"ResourceUsage,omitempty"`
+
+	// TaskStates is the observed state of each task of the execution, keyed by task name.
+	TaskStates map[string]*TaskState `json:"TaskStates,omitempty"`
+
+	// Checkpoint is the latest checkpoint taken of the execution, if its task is checkpointed.
+	Checkpoint *Checkpoint `json:"Checkpoint,omitempty"`
+
+	// RestoreFrom is the checkpoint of a previous execution of the job that this execution resumes from.
+	RestoreFrom *Checkpoint `json:"RestoreFrom,omitempty"`
+
 	// PreviousExecution is the execution that this execution is replacing
 	PreviousExecution string `json:"PreviousExecution"`
 
@@ -107,6 +123,22 @@ type Execution struct {
 	// that can be rescheduled in the future
 	FollowupEvalID string `json:"FollowupEvalID"`
 
+	// FailureReason classifies why the execution failed, such as being killed for
+	// exceeding its memory limit, if it is known.
+	FailureReason FailureReason `json:"FailureReason,omitempty"`
+
+	// PreemptedBy is the ID of the higher priority job that this execution was
+	// stopped for to make room on its node.
+	PreemptedBy string `json:"PreemptedBy,omitempty"`
+
+	// Drained is true if the execution was stopped to drain its node. Drained executions
+	// are replaced on other nodes rather than counted as failed attempts of the job.
+	Drained bool `json:"Drained,omitempty"`
+
+	// CachedFrom is the ID of the execution of a previous identical job whose published result
+	// this execution reused, instead of running on its node.
+	CachedFrom string `json:"CachedFrom,omitempty"`
+
 	// Revision is increment each time the execution is updated.
 	Revision uint64 `json:"Revision"`
 
@@ -169,6 +201,10 @@ func (e *Execution) Copy() *Execution {
 	na.Job = na.Job.Copy()
 	na.AllocatedResources = na.AllocatedResources.Copy()
 	na.PublishedResult = na.PublishedResult.Copy()
+	na.ResourceUsage = na.ResourceUsage.Copy()
+	na.TaskStates = CopyTaskStates(na.TaskStates)
+	na.Checkpoint = na.Checkpoint.Copy()
+	na.RestoreFrom = na.RestoreFrom.Copy()
 	return na
 }
 
@@ -248,6 +284,15 @@ type RunCommandResult struct {
 
 	// Runner error
 	ErrorMsg string `json:"ErrorMsg"`
+
+	// ResourceUsage is the resources used by the run, if measured by the executor.
+	ResourceUsage *ResourceUsage `json:"ResourceUsage,omitempty"`
+
+	// FailureReason classifies why the run failed, if the executor could tell.
+	FailureReason FailureReason `json:"FailureReason,omitempty"`
+
+	// Egress is the outbound requests of the run, if its network config filters them.
+	Egress *EgressLog `json:"Egress,omitempty"`
 }
 
 func NewRunCommandResult() *RunCommandResult {
@@ -0,0 +1,24 @@
+package models
+
+// FailureReason classifies why an execution failed, when the executor or the orchestrator
+// could tell, so that users and the scheduler don't have to guess from exit codes.
+type FailureReason string
+
+const (
+	// FailureReasonOOMKilled means the execution was killed for exceeding its memory limit.
+	FailureReasonOOMKilled FailureReason = "OOMKilled"
+
+	// FailureReasonDiskQuotaExceeded means the execution ran out of disk space.
+	FailureReasonDiskQuotaExceeded FailureReason = "DiskQuotaExceeded"
+
+	// FailureReasonTimeout means the execution was killed for exceeding its execution timeout.
+	FailureReasonTimeout FailureReason = "Timeout"
+
+	// FailureReasonNodeLost means the execution was stopped because its node disappeared.
+	FailureReasonNodeLost FailureReason = "NodeLost"
+)
+
+// FailureReasonFromEvent returns the failure reason recorded in the details of an event, if any.
+func FailureReasonFromEvent(event Event) FailureReason {
+	return FailureReason(event.Details[DetailsKeyFailureReason])
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// GPUSelector restricts the GPUs a task can be allocated to the devices of a vendor,
+// with a minimum amount of memory, or of a model. Unset fields match any GPU.
+type GPUSelector struct {
+	// Vendor of the GPUs, e.g. NVIDIA, AMD or Intel. Matched case-insensitively against
+	// the start of the vendor name, so that AMD matches AMD/ATI.
+	Vendor string `json:"Vendor,omitempty"`
+
+	// MinMemory is the minimum memory of the GPUs, as a humanize string e.g. 40gb or 80GiB.
+	MinMemory string `json:"MinMemory,omitempty"`
+
+	// Model is a case-insensitive pattern of the model name of the GPUs, e.g. "A100" or "*A100*80GB".
+	// Patterns without wildcards match any model name that contains them.
+	Model string `json:"Model,omitempty"`
+}
+
+// Copy returns a deep copy of the selector
+func (s *GPUSelector) Copy() *GPUSelector {
+	if s == nil {
+		return nil
+	}
+	ns := new(GPUSelector)
+	*ns = *s
+	return ns
+}
+
+// Validate returns an error if the minimum memory or the model pattern are invalid
+func (s *GPUSelector) Validate() error {
+	if s == nil {
+		return nil
+	}
+	var mErr error
+	if s.MinMemory != "" {
+		if _, err := humanize.ParseBytes(s.MinMemory); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid GPU min memory value: %s", s.MinMemory))
+		}
+	}
+	if _, err := path.Match(s.Model, ""); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid GPU model pattern %q: %w", s.Model, err))
+	}
+	return mErr
+}
+
+// Matches returns true if the GPU satisfies the selector
+func (s *GPUSelector) Matches(gpu GPU) bool {
+	if s == nil {
+		return true
+	}
+	if s.Vendor != "" && !strings.HasPrefix(strings.ToLower(string(gpu.Vendor)), strings.ToLower(s.Vendor)) {
+		return false
+	}
+	if s.MinMemory != "" {
+		minMemory, err := humanize.ParseBytes(s.MinMemory)
+		if err != nil || gpu.Memory*humanize.MiByte < minMemory {
+			return false
+		}
+	}
+	if s.Model != "" {
+		pattern := strings.ToLower(s.Model)
+		name := strings.ToLower(gpu.Name)
+		if !strings.ContainsAny(pattern, "*?[") {
+			return strings.Contains(name, pattern)
+		}
+		matched, err := path.Match(pattern, name)
+		return err == nil && matched
+	}
+	return true
+}
+
+func (s *GPUSelector) String() string {
+	if s == nil {
+		return ""
+	}
+	var parts []string
+	if s.Vendor != "" {
+		parts = append(parts, "vendor: "+s.Vendor)
+	}
+	if s.MinMemory != "" {
+		parts = append(parts, "min memory: "+s.MinMemory)
+	}
+	if s.Model != "" {
+		parts = append(parts, "model: "+s.Model)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
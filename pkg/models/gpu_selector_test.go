@@ -0,0 +1,58 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGPUSelectorMatches(t *testing.T) {
+	t4 := GPU{Name: "Tesla T4", Vendor: GPUVendorNvidia, Memory: 15360}
+	a100 := GPU{Name: "NVIDIA A100-SXM4-80GB", Vendor: GPUVendorNvidia, Memory: 81920}
+	mi250 := GPU{Name: "Instinct MI250X", Vendor: GPUVendorAMDATI, Memory: 131072}
+
+	tests := []struct {
+		name     string
+		selector *GPUSelector
+		matches  []GPU
+	}{
+		{name: "nil", selector: nil, matches: []GPU{t4, a100, mi250}},
+		{name: "empty", selector: &GPUSelector{}, matches: []GPU{t4, a100, mi250}},
+		{name: "vendor", selector: &GPUSelector{Vendor: "nvidia"}, matches: []GPU{t4, a100}},
+		{name: "vendor prefix", selector: &GPUSelector{Vendor: "AMD"}, matches: []GPU{mi250}},
+		{name: "min memory", selector: &GPUSelector{MinMemory: "80GiB"}, matches: []GPU{a100, mi250}},
+		{name: "model substring", selector: &GPUSelector{Model: "a100"}, matches: []GPU{a100}},
+		{name: "model pattern", selector: &GPUSelector{Model: "*A100*80GB"}, matches: []GPU{a100}},
+		{name: "model pattern anchored", selector: &GPUSelector{Model: "A100*"}, matches: nil},
+		{name: "all", selector: &GPUSelector{Vendor: "NVIDIA", MinMemory: "40GB", Model: "A100"}, matches: []GPU{a100}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var matches []GPU
+			for _, gpu := range []GPU{t4, a100, mi250} {
+				if tc.selector.Matches(gpu) {
+					matches = append(matches, gpu)
+				}
+			}
+			assert.Equal(t, tc.matches, matches)
+		})
+	}
+}
+
+func TestGPUSelectorValidate(t *testing.T) {
+	assert.NoError(t, (&GPUSelector{Vendor: "NVIDIA", MinMemory: "80GiB", Model: "*A100*"}).Validate())
+	assert.Error(t, (&GPUSelector{MinMemory: "lots"}).Validate())
+	assert.Error(t, (&GPUSelector{Model: "[A100"}).Validate())
+}
+
+func TestResourcesConfigGPUSelector(t *testing.T) {
+	resources, err := (&ResourcesConfig{GPUSelector: &GPUSelector{Vendor: "NVIDIA"}}).ToResources()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resources.GPU, "a selector without a GPU count requests a single GPU")
+	assert.Equal(t, "NVIDIA", resources.GPUSelector.Vendor)
+
+	_, err = (&ResourcesConfig{GPU: "1", GPUSelector: &GPUSelector{MinMemory: "lots"}}).ToResources()
+	assert.Error(t, err)
+}
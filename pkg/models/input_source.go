@@ -1,6 +1,9 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -64,3 +67,24 @@ func (a *InputSource) Validate() error {
 	}
 	return mErr
 }
+
+// CacheKey returns the key the data of the input source is cached by on compute nodes. The key is the hash
+// of the source only, so that inputs mounted on different targets or with different aliases share the data.
+// As sources such as URLs can point to different data over time, cached data is also keyed by the version
+// the source pointed to, such as its CID, version ID or ETag, and revalidated against it on every use.
+func (a *InputSource) CacheKey() (string, error) {
+	if a == nil || a.Source == nil {
+		return "", errors.New("input source has no source")
+	}
+	source := SpecConfig{
+		Type:   strings.ToLower(a.Source.Type),
+		Params: a.Source.Params,
+	}
+	// maps are marshalled with sorted keys, which makes the encoding canonical
+	data, err := json.Marshal(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute cache key of input source %s: %w", a.Source.Type, err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -4,6 +4,7 @@ package models
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -100,6 +101,22 @@ type Job struct {
 
 	Tasks []*Task `json:"Tasks"`
 
+	// DependsOn is a list of upstream jobs that must complete before this job is scheduled.
+	// The job remains pending until all of its dependencies complete, and fails if any of them fails.
+	DependsOn []*JobDependency `json:"DependsOn,omitempty"`
+
+	// RetryPolicy defines how failed executions of the job are retried.
+	// Only applicable to batch and service jobs.
+	RetryPolicy *RetryPolicy `json:"RetryPolicy,omitempty"`
+
+	// Array fans out the job into one execution per array index.
+	// Only applicable to batch jobs.
+	Array *JobArray `json:"Array,omitempty"`
+
+	// Schedule defines when a scheduled job spawns new runs.
+	// Only applicable to scheduled jobs.
+	Schedule *Schedule `json:"Schedule,omitempty"`
+
 	// State is the current state of the job.
 	State State[JobStateType] `json:"State"`
 
@@ -162,13 +179,22 @@ func (j *Job) Normalize() {
 		j.Namespace = DefaultNamespace
 	}
 
-	if (j.Type == JobTypeDaemon || j.Type == JobTypeOps) && j.Count == 0 {
+	if (j.Type == JobTypeDaemon || j.Type == JobTypeOps || j.Type == JobTypeScheduled) && j.Count == 0 {
+		j.Count = 1
+	}
+
+	// array jobs run a single execution per array index
+	if j.Array != nil && j.Count == 0 {
 		j.Count = 1
 	}
 
 	for _, task := range j.Tasks {
 		task.Normalize()
 	}
+	NormalizeSlice(j.DependsOn)
+	j.RetryPolicy.Normalize()
+	j.Array.Normalize()
+	j.Schedule.Normalize()
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -189,6 +215,13 @@ func (j *Job) Copy() *Job {
 		nj.Tasks = tasks
 	}
 
+	if j.DependsOn != nil {
+		nj.DependsOn = CopySlice[*JobDependency](nj.DependsOn)
+	}
+
+	nj.RetryPolicy = j.RetryPolicy.Copy()
+	nj.Array = j.Array.Copy()
+	nj.Schedule = j.Schedule.Copy()
 	nj.Meta = maps.Clone(nj.Meta)
 	return nj
 }
@@ -239,7 +272,7 @@ func (j *Job) ValidateSubmission() error {
 
 	var mErr error
 	switch j.Type {
-	case JobTypeService, JobTypeBatch, JobTypeDaemon, JobTypeOps:
+	case JobTypeService, JobTypeBatch, JobTypeDaemon, JobTypeOps, JobTypeScheduled:
 	case "":
 		mErr = errors.Join(mErr, errors.New("missing job type"))
 	default:
@@ -251,6 +284,8 @@ func (j *Job) ValidateSubmission() error {
 	}
 	if len(j.Tasks) == 0 {
 		mErr = errors.Join(mErr, errors.New("missing job tasks"))
+	} else {
+		mErr = errors.Join(mErr, j.validateTaskLifecycles())
 	}
 	for idx, constr := range j.Constraints {
 		if err := constr.Validate(); err != nil {
@@ -259,6 +294,69 @@ func (j *Job) ValidateSubmission() error {
 		}
 	}
 
+	if len(j.DependsOn) > 0 {
+		if j.Type != JobTypeBatch && j.Type != JobTypeService {
+			mErr = errors.Join(mErr, fmt.Errorf("job dependencies are not supported for %s jobs", j.Type))
+		}
+		seenDependencies := make(map[string]bool)
+		for idx, dep := range j.DependsOn {
+			if err := dep.Validate(); err != nil {
+				mErr = errors.Join(mErr, fmt.Errorf("dependency %d validation failed: %s", idx+1, err))
+				continue
+			}
+			if seenDependencies[dep.JobID] {
+				mErr = errors.Join(mErr, fmt.Errorf("duplicate dependency on job %s", dep.JobID))
+			}
+			if dep.JobID == j.ID {
+				mErr = errors.Join(mErr, errors.New("job cannot depend on itself"))
+			}
+			seenDependencies[dep.JobID] = true
+		}
+	}
+
+	if j.RetryPolicy != nil {
+		// retry policies of scheduled jobs apply to the batch jobs they spawn
+		if j.Type != JobTypeBatch && j.Type != JobTypeService && j.Type != JobTypeScheduled {
+			mErr = errors.Join(mErr, fmt.Errorf("retry policy is not supported for %s jobs", j.Type))
+		}
+		if err := j.RetryPolicy.Validate(); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("retry policy validation failed: %s", err))
+		}
+	}
+
+	if j.Array != nil {
+		// scheduled jobs spawn batch jobs, which may be array jobs
+		if j.Type != JobTypeBatch && j.Type != JobTypeScheduled {
+			mErr = errors.Join(mErr, fmt.Errorf("job arrays are not supported for %s jobs", j.Type))
+		}
+		if j.Count > 1 {
+			mErr = errors.Join(mErr, errors.New("job count must be 1 for array jobs"))
+		}
+		if err := j.Array.Validate(); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("array validation failed: %s", err))
+		} else if _, err = j.ForArrayIndex(j.Array.Indexes()[0]); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+
+	if j.Task().IsCacheable() {
+		// scheduled jobs spawn batch jobs, whose results can be cached
+		if j.Type != JobTypeBatch && j.Type != JobTypeScheduled {
+			mErr = errors.Join(mErr, fmt.Errorf("result caching is not supported for %s jobs", j.Type))
+		}
+		if j.Array != nil {
+			mErr = errors.Join(mErr, errors.New("result caching is not supported for array jobs"))
+		}
+	}
+
+	if j.Type == JobTypeScheduled {
+		if err := j.Schedule.Validate(); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("schedule validation failed: %s", err))
+		}
+	} else if j.Schedule != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("schedule is not supported for %s jobs", j.Type))
+	}
+
 	// Validate the task group
 	for _, task := range j.Tasks {
 		if err := task.ValidateSubmission(); err != nil {
@@ -298,11 +396,6 @@ func (j *Job) SanitizeSubmission() (warnings []string) {
 			j.ID = ""
 		}
 	}
-	// TODO: remove this once we have multiple tasks per job
-	if len(j.Tasks) > 1 {
-		warnings = append(warnings, "only one task is supported per job")
-		j.Tasks = j.Tasks[:1]
-	}
 	for k := range j.Meta {
 		if strings.HasPrefix(k, MetaReservedPrefix) {
 			warnings = append(warnings, fmt.Sprintf("job meta key %q is reserved and will be ignored", k))
@@ -322,15 +415,71 @@ func (j *Job) IsTerminal() bool {
 	}
 }
 
-// Task returns the job task
-// TODO: remove this once we have multiple tasks per job
+// Task returns the main task of the job, which defines the engine, publisher and timeouts of its executions.
 func (j *Job) Task() *Task {
 	if j == nil {
 		return nil
 	}
+	for _, task := range j.Tasks {
+		if task.IsMain() {
+			return task
+		}
+	}
 	return j.Tasks[0]
 }
 
+// TasksWithLifecycle returns the tasks of the job with the given lifecycle, in the order they are defined.
+func (j *Job) TasksWithLifecycle(lifecycle string) []*Task {
+	var tasks []*Task
+	for _, task := range j.Tasks {
+		if task.GetLifecycle() == lifecycle {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// TotalResources returns the resources required by all the tasks of the job, which are co-scheduled
+// on the same node and so are summed.
+func (j *Job) TotalResources() (*Resources, error) {
+	total := &Resources{}
+	for _, task := range j.Tasks {
+		resources, err := task.ResourcesConfig.ToResources()
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		total = total.Add(*resources)
+	}
+	return total, nil
+}
+
+// validateTaskLifecycles checks that the job has a single main task, unique task names,
+// and that only the main task publishes results, as all tasks share its results directory.
+func (j *Job) validateTaskLifecycles() error {
+	var mErr error
+	mainTasks := j.TasksWithLifecycle(TaskLifecycleMain)
+	if len(mainTasks) != 1 {
+		mErr = errors.Join(mErr, fmt.Errorf("job must have exactly one main task, found %d", len(mainTasks)))
+	}
+	seenNames := make(map[string]bool)
+	for _, task := range j.Tasks {
+		if seenNames[task.Name] {
+			mErr = errors.Join(mErr, fmt.Errorf("duplicate task name %s", task.Name))
+		}
+		seenNames[task.Name] = true
+		if task.IsMain() {
+			continue
+		}
+		if !task.Publisher.IsEmpty() {
+			mErr = errors.Join(mErr, fmt.Errorf("task %s: only the main task can define a publisher", task.Name))
+		}
+		if len(task.ResultPaths) > 0 && (len(mainTasks) == 0 || mainTasks[0].Publisher.IsEmpty()) {
+			mErr = errors.Join(mErr, fmt.Errorf("task %s: main task must define a publisher if result paths are set", task.Name))
+		}
+	}
+	return mErr
+}
+
 // GetCreateTime returns the creation time
 func (j *Job) GetCreateTime() time.Time {
 	return time.Unix(0, j.CreateTime).UTC()
@@ -353,7 +502,35 @@ func (j *Job) AllStorageTypes() []string {
 	return storageTypes
 }
 
+// AllEngineTypes returns the distinct engine types required by the job tasks
+func (j *Job) AllEngineTypes() []string {
+	var engineTypes []string
+	if j == nil {
+		return engineTypes
+	}
+	for _, task := range j.Tasks {
+		if !slices.Contains(engineTypes, task.Engine.Type) {
+			engineTypes = append(engineTypes, task.Engine.Type)
+		}
+	}
+	return engineTypes
+}
+
+// HasDependencies returns true if the job depends on other jobs
+func (j *Job) HasDependencies() bool {
+	return len(j.DependsOn) > 0
+}
+
+// ScheduledBy returns the ID of the scheduled job that spawned this job as one of its runs,
+// or an empty string if the job is not a run of a scheduled job.
+func (j *Job) ScheduledBy() string {
+	if j.Meta[MetaScheduledTime] == "" {
+		return ""
+	}
+	return j.Meta[MetaDerivedFrom]
+}
+
 // IsLongRunning returns true if the job is long running
 func (j *Job) IsLongRunning() bool {
-	return j.Type == JobTypeService || j.Type == JobTypeDaemon
+	return j.Type == JobTypeService || j.Type == JobTypeDaemon || j.Type == JobTypeScheduled
 }
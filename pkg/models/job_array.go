@@ -0,0 +1,227 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/template"
+)
+
+const (
+	// EnvArrayIndex is the environment variable holding the index of an array job's execution.
+	EnvArrayIndex = "BACALHAU_ARRAY_INDEX"
+
+	// MaxJobArraySize is the maximum number of indexes an array job can fan out to.
+	MaxJobArraySize = 10000
+)
+
+var envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JobArray fans out a batch job into one execution per index, either over an
+// inclusive range of indexes, or over a list of parameter maps.
+// The index and parameters of each execution are exposed as environment variables
+// of the task, and can be referenced in the engine spec as {{.BACALHAU_ARRAY_INDEX}}
+// or {{.PARAMETER_NAME}}.
+type JobArray struct {
+	// Start is the first index of the range. Ignored if Parameters are set.
+	Start int `json:"Start,omitempty"`
+
+	// End is the last index of the range, inclusive. Ignored if Parameters are set.
+	End int `json:"End,omitempty"`
+
+	// Parameters is a list of parameter maps, one per index starting from zero.
+	// All maps must define the same parameters.
+	Parameters []map[string]string `json:"Parameters,omitempty"`
+}
+
+// Normalize sets default values for the job array
+func (a *JobArray) Normalize() {
+	if a == nil {
+		return
+	}
+	if len(a.Parameters) > 0 {
+		a.Start = 0
+		a.End = len(a.Parameters) - 1
+	}
+}
+
+// Copy returns a deep copy of the job array
+func (a *JobArray) Copy() *JobArray {
+	if a == nil {
+		return nil
+	}
+	na := new(JobArray)
+	*na = *a
+	if a.Parameters != nil {
+		na.Parameters = make([]map[string]string, len(a.Parameters))
+		for i, params := range a.Parameters {
+			na.Parameters[i] = maps.Clone(params)
+		}
+	}
+	return na
+}
+
+// Validate validates the job array
+func (a *JobArray) Validate() error {
+	if a == nil {
+		return nil
+	}
+	var mErr error
+	if len(a.Parameters) == 0 {
+		if a.Start < 0 {
+			mErr = errors.Join(mErr, errors.New("array start index must be >= 0"))
+		}
+		if a.End < a.Start {
+			mErr = errors.Join(mErr, errors.New("array end index must be >= start index"))
+		}
+	}
+	if a.Size() > MaxJobArraySize {
+		mErr = errors.Join(mErr, fmt.Errorf("array size %d exceeds the maximum of %d", a.Size(), MaxJobArraySize))
+	}
+	if len(a.Parameters) > 0 {
+		names := sortedKeys(a.Parameters[0])
+		for _, name := range names {
+			if !envVarNameRegex.MatchString(name) {
+				mErr = errors.Join(mErr, fmt.Errorf("invalid array parameter name %q", name))
+			}
+			if name == EnvArrayIndex {
+				mErr = errors.Join(mErr, fmt.Errorf("array parameter name %q is reserved", name))
+			}
+		}
+		for i, params := range a.Parameters[1:] {
+			if !slices.Equal(names, sortedKeys(params)) {
+				mErr = errors.Join(mErr, fmt.Errorf("array parameters %d do not define the same parameters as the first", i+1))
+			}
+		}
+	}
+	return mErr
+}
+
+// Size returns the number of indexes of the array
+func (a *JobArray) Size() int {
+	if len(a.Parameters) > 0 {
+		return len(a.Parameters)
+	}
+	return math.Max(0, a.End-a.Start+1)
+}
+
+// Indexes returns the indexes of the array in ascending order
+func (a *JobArray) Indexes() []int {
+	start := a.Start
+	if len(a.Parameters) > 0 {
+		start = 0
+	}
+	indexes := make([]int, a.Size())
+	for i := range indexes {
+		indexes[i] = start + i
+	}
+	return indexes
+}
+
+// Values returns the array index and parameter values of the given index,
+// keyed by their environment variable names.
+func (a *JobArray) Values(index int) map[string]string {
+	values := make(map[string]string)
+	if len(a.Parameters) > 0 && index >= 0 && index < len(a.Parameters) {
+		maps.Copy(values, a.Parameters[index])
+	}
+	values[EnvArrayIndex] = strconv.Itoa(index)
+	return values
+}
+
+// sortedKeys returns the keys of the map in ascending order
+func sortedKeys(m map[string]string) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// IsArray returns true if the job fans out into one execution per array index
+func (j *Job) IsArray() bool {
+	return j.Array != nil
+}
+
+// ForArrayIndex returns a copy of the job for the given array index, where the index and
+// parameter values are added to the tasks' environment and rendered in their engine specs.
+func (j *Job) ForArrayIndex(index int) (*Job, error) {
+	nj := j.Copy()
+	if !j.IsArray() {
+		return nj, nil
+	}
+	values := j.Array.Values(index)
+	parser, err := template.NewParser(template.ParserParams{Replacements: values})
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range nj.Tasks {
+		if task.Env == nil {
+			task.Env = make(map[string]string)
+		}
+		maps.Copy(task.Env, values)
+		if task.Engine == nil {
+			continue
+		}
+		params, err := renderParam(parser, task.Engine.Params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render engine spec of task %s for array index %d: %w", task.Name, index, err)
+		}
+		task.Engine.Params, _ = params.(map[string]interface{})
+	}
+	return nj, nil
+}
+
+// renderParam renders the templates in all string values of an engine spec parameter
+func renderParam(parser *template.DefaultParser, param interface{}) (interface{}, error) {
+	switch v := param.(type) {
+	case string:
+		return parser.Parse(v)
+	case []string:
+		rendered := make([]string, len(v))
+		for i, s := range v {
+			r, err := parser.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := renderParam(parser, item)
+			if err != nil {
+				return nil, err
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	case map[string]string:
+		rendered := make(map[string]string, len(v))
+		for k, s := range v {
+			r, err := parser.Parse(s)
+			if err != nil {
+				return nil, err
+			}
+			rendered[k] = r
+		}
+		return rendered, nil
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r, err := renderParam(parser, item)
+			if err != nil {
+				return nil, err
+			}
+			rendered[k] = r
+		}
+		return rendered, nil
+	default:
+		return v, nil
+	}
+}
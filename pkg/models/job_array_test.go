@@ -0,0 +1,70 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobArray_Indexes(t *testing.T) {
+	array := &JobArray{Start: 3, End: 5}
+	assert.Equal(t, 3, array.Size())
+	assert.Equal(t, []int{3, 4, 5}, array.Indexes())
+
+	array = &JobArray{Start: 7, Parameters: []map[string]string{{"LR": "0.1"}, {"LR": "0.2"}}}
+	array.Normalize()
+	assert.Equal(t, 2, array.Size())
+	assert.Equal(t, []int{0, 1}, array.Indexes())
+	assert.Equal(t, map[string]string{"LR": "0.2", EnvArrayIndex: "1"}, array.Values(1))
+}
+
+func TestJobArray_Validate(t *testing.T) {
+	var nilArray *JobArray
+	assert.NoError(t, nilArray.Validate())
+	assert.NoError(t, (&JobArray{Start: 0, End: 0}).Validate())
+	assert.NoError(t, (&JobArray{Parameters: []map[string]string{{"A": "1", "B": "2"}, {"B": "3", "A": "4"}}}).Validate())
+	assert.Error(t, (&JobArray{Start: -1, End: 2}).Validate())
+	assert.Error(t, (&JobArray{Start: 3, End: 2}).Validate())
+	assert.Error(t, (&JobArray{End: MaxJobArraySize}).Validate())
+	assert.Error(t, (&JobArray{Parameters: []map[string]string{{"A": "1"}, {"B": "2"}}}).Validate())
+	assert.Error(t, (&JobArray{Parameters: []map[string]string{{"not valid": "1"}}}).Validate())
+	assert.Error(t, (&JobArray{Parameters: []map[string]string{{EnvArrayIndex: "1"}}}).Validate())
+}
+
+func TestJob_ForArrayIndex(t *testing.T) {
+	job := &Job{
+		Type: JobTypeBatch,
+		Array: &JobArray{Parameters: []map[string]string{
+			{"DATASET": "train"},
+			{"DATASET": "test"},
+		}},
+		Tasks: []*Task{{
+			Name: "task",
+			Engine: &SpecConfig{
+				Type: EngineDocker,
+				Params: map[string]interface{}{
+					"Image":      "ubuntu",
+					"Parameters": []interface{}{"process", "--shard={{.BACALHAU_ARRAY_INDEX}}", "--dataset={{.DATASET}}"},
+				},
+			},
+		}},
+	}
+
+	indexed, err := job.ForArrayIndex(1)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"process", "--shard=1", "--dataset=test"}, indexed.Task().Engine.Params["Parameters"])
+	assert.Equal(t, "ubuntu", indexed.Task().Engine.Params["Image"])
+	assert.Equal(t, map[string]string{"DATASET": "test", EnvArrayIndex: "1"}, indexed.Task().Env)
+
+	// the original job is left untouched
+	assert.Equal(t, "--dataset={{.DATASET}}", job.Task().Engine.Params["Parameters"].([]interface{})[2])
+	assert.Empty(t, job.Task().Env)
+
+	// referencing an unknown parameter fails
+	job.Task().Engine.Params["Image"] = "{{.UNKNOWN}}"
+	_, err = job.ForArrayIndex(0)
+	assert.Error(t, err)
+}
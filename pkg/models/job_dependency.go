@@ -0,0 +1,55 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+// JobDependency describes an upstream job that must complete successfully
+// before the dependent job can be scheduled. If the upstream job fails or
+// is stopped, the dependent job is failed as well.
+type JobDependency struct {
+	// JobID is the ID of the upstream job.
+	JobID string `json:"JobID"`
+
+	// Target is an optional path where the published results of the upstream job
+	// are mounted into each of the dependent job's tasks as input sources.
+	// Results are not injected if the target is empty.
+	Target string `json:"Target,omitempty"`
+}
+
+// Normalize normalizes the dependency to a canonical form
+func (d *JobDependency) Normalize() {
+	if d == nil {
+		return
+	}
+	d.JobID = strings.TrimSpace(d.JobID)
+	d.Target = strings.TrimSpace(d.Target)
+}
+
+// Copy returns a copy of the dependency
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	return &JobDependency{
+		JobID:  d.JobID,
+		Target: d.Target,
+	}
+}
+
+// Validate validates the dependency
+func (d *JobDependency) Validate() error {
+	if d == nil {
+		return errors.New("dependency is nil")
+	}
+	var mErr error
+	if validate.IsBlank(d.JobID) {
+		mErr = errors.Join(mErr, errors.New("missing upstream job ID"))
+	} else if validate.ContainsSpaces(d.JobID) {
+		mErr = errors.Join(mErr, errors.New("upstream job ID contains a space"))
+	}
+	return mErr
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+const cpuEpsilon = 1e-9
+
+// NamespaceQuota limits the executions that jobs in a namespace can run concurrently across the cluster.
+type NamespaceQuota struct {
+	// Namespace is the namespace the quota applies to.
+	Namespace string `json:"Namespace"`
+
+	// MaxConcurrentExecutions is the maximum number of active executions in the namespace.
+	// Zero means no limit.
+	MaxConcurrentExecutions int `json:"MaxConcurrentExecutions,omitempty"`
+
+	// MaxResources is the maximum aggregate CPU, memory and GPU that active executions in the
+	// namespace can be allocated. Resources left empty are not limited.
+	MaxResources *ResourcesConfig `json:"MaxResources,omitempty"`
+
+	// NonPreemptible protects the executions of the namespace from being preempted by higher priority
+	// jobs when the orchestrator has preemption enabled.
+	NonPreemptible bool `json:"NonPreemptible,omitempty"`
+
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// Normalize normalizes the quota
+func (q *NamespaceQuota) Normalize() {
+	if q == nil {
+		return
+	}
+	q.Namespace = strings.TrimSpace(q.Namespace)
+	if q.MaxResources == nil {
+		q.MaxResources = &ResourcesConfig{}
+	}
+	q.MaxResources.Normalize()
+}
+
+// Copy returns a deep copy of the quota
+func (q *NamespaceQuota) Copy() *NamespaceQuota {
+	if q == nil {
+		return nil
+	}
+	nq := new(NamespaceQuota)
+	*nq = *q
+	nq.MaxResources = q.MaxResources.Copy()
+	return nq
+}
+
+// Validate validates the quota
+func (q *NamespaceQuota) Validate() error {
+	if q == nil {
+		return errors.New("missing namespace quota")
+	}
+	var mErr error
+	if validate.IsBlank(q.Namespace) {
+		mErr = errors.Join(mErr, errors.New("namespace must be set"))
+	}
+	if q.MaxConcurrentExecutions < 0 {
+		mErr = errors.Join(mErr, errors.New("max concurrent executions must be >= 0"))
+	}
+	if q.MaxResources != nil {
+		if q.MaxResources.Disk != "" {
+			mErr = errors.Join(mErr, errors.New("disk quotas are not supported"))
+		}
+		if _, err := q.MaxResources.ToResources(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+	}
+	return mErr
+}
+
+// Limits returns the aggregate resource limits of the quota. Zero values are not limited.
+func (q *NamespaceQuota) Limits() Resources {
+	if q == nil || q.MaxResources == nil {
+		return Resources{}
+	}
+	limits, err := q.MaxResources.ToResources()
+	if err != nil {
+		return Resources{}
+	}
+	return *limits
+}
+
+// IsUnlimited returns true if the quota does not limit anything
+func (q *NamespaceQuota) IsUnlimited() bool {
+	limits := q.Limits()
+	return q == nil || (q.MaxConcurrentExecutions == 0 && limits.CPU == 0 && limits.Memory == 0 && limits.GPU == 0)
+}
+
+// AllowedExecutions returns how many of the requested executions, each allocated the given resources,
+// can be started on top of the namespace's current usage without exceeding the quota.
+func (q *NamespaceQuota) AllowedExecutions(usage NamespaceUsage, perExecution Resources, requested int) int {
+	if q.IsUnlimited() || requested <= 0 {
+		return max(requested, 0)
+	}
+	allowed := requested
+	if q.MaxConcurrentExecutions > 0 {
+		allowed = min(allowed, max(q.MaxConcurrentExecutions-usage.Executions, 0))
+	}
+	limits := q.Limits()
+	if limits.CPU > 0 && perExecution.CPU > 0 {
+		// tolerate floating point error when the remaining CPU is an exact multiple of the requested CPU
+		remaining := math.Floor((limits.CPU-usage.Resources.CPU)/perExecution.CPU + cpuEpsilon)
+		allowed = min(allowed, int(math.Max(remaining, 0)))
+	}
+	if limits.Memory > 0 && perExecution.Memory > 0 {
+		allowed = min(allowed, remainingUnits(limits.Memory, usage.Resources.Memory, perExecution.Memory))
+	}
+	if limits.GPU > 0 && perExecution.GPU > 0 {
+		allowed = min(allowed, remainingUnits(limits.GPU, usage.Resources.GPU, perExecution.GPU))
+	}
+	return allowed
+}
+
+// remainingUnits returns how many units of the given size fit in what is left of the limit.
+func remainingUnits(limit, used, unit uint64) int {
+	if used >= limit {
+		return 0
+	}
+	return int(min((limit-used)/unit, math.MaxInt32))
+}
+
+// String returns a human-readable description of the quota limits, and whether it protects
+// the namespace's executions from preemption
+func (q *NamespaceQuota) String() string {
+	var parts []string
+	if q.IsUnlimited() {
+		parts = append(parts, "unlimited")
+	} else {
+		parts = q.limitsStrings()
+	}
+	if q != nil && q.NonPreemptible {
+		parts = append(parts, "non-preemptible")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// limitsStrings returns a human-readable description of each limit of the quota
+func (q *NamespaceQuota) limitsStrings() []string {
+	var parts []string
+	if q.MaxConcurrentExecutions > 0 {
+		parts = append(parts, fmt.Sprintf("executions: %d", q.MaxConcurrentExecutions))
+	}
+	limits := q.Limits()
+	if limits.CPU > 0 {
+		parts = append(parts, fmt.Sprintf("cpu: %g", limits.CPU))
+	}
+	if limits.Memory > 0 {
+		parts = append(parts, fmt.Sprintf("memory: %s", humanize.Bytes(limits.Memory)))
+	}
+	if limits.GPU > 0 {
+		parts = append(parts, fmt.Sprintf("gpu: %d", limits.GPU))
+	}
+	return parts
+}
+
+// NamespaceUsage is the number of active executions in a namespace and the resources allocated to them.
+type NamespaceUsage struct {
+	Namespace  string    `json:"Namespace"`
+	Executions int       `json:"Executions"`
+	Resources  Resources `json:"Resources"`
+}
+
+// Add accounts for an active execution that is allocated the given resources
+func (u *NamespaceUsage) Add(resources Resources) {
+	u.Executions++
+	u.Resources = *u.Resources.Add(resources)
+}
@@ -0,0 +1,94 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceQuota_Validate(t *testing.T) {
+	assert.NoError(t, (&NamespaceQuota{Namespace: "team-a"}).Validate())
+	assert.NoError(t, (&NamespaceQuota{Namespace: "team-a", MaxResources: &ResourcesConfig{CPU: "2", Memory: "4gb", GPU: "1"}}).Validate())
+
+	assert.Error(t, (&NamespaceQuota{}).Validate())
+	assert.Error(t, (&NamespaceQuota{Namespace: "team-a", MaxConcurrentExecutions: -1}).Validate())
+	assert.Error(t, (&NamespaceQuota{Namespace: "team-a", MaxResources: &ResourcesConfig{Disk: "10gb"}}).Validate())
+	assert.Error(t, (&NamespaceQuota{Namespace: "team-a", MaxResources: &ResourcesConfig{CPU: "lots"}}).Validate())
+}
+
+func TestNamespaceQuota_AllowedExecutions(t *testing.T) {
+	perExecution := Resources{CPU: 0.1, Memory: 1 << 30, GPU: 1}
+	tests := []struct {
+		name      string
+		quota     *NamespaceQuota
+		usage     NamespaceUsage
+		requested int
+		expected  int
+	}{
+		{
+			name:      "no quota",
+			requested: 5,
+			expected:  5,
+		},
+		{
+			name:      "unlimited quota",
+			quota:     &NamespaceQuota{Namespace: "default"},
+			requested: 5,
+			expected:  5,
+		},
+		{
+			name:      "max concurrent executions",
+			quota:     &NamespaceQuota{MaxConcurrentExecutions: 4},
+			usage:     NamespaceUsage{Executions: 1},
+			requested: 5,
+			expected:  3,
+		},
+		{
+			name:      "usage above max concurrent executions",
+			quota:     &NamespaceQuota{MaxConcurrentExecutions: 4},
+			usage:     NamespaceUsage{Executions: 6},
+			requested: 5,
+			expected:  0,
+		},
+		{
+			name:      "max cpu",
+			quota:     &NamespaceQuota{MaxResources: &ResourcesConfig{CPU: "0.3"}},
+			requested: 5,
+			expected:  3,
+		},
+		{
+			name:      "max memory",
+			quota:     &NamespaceQuota{MaxResources: &ResourcesConfig{Memory: "4GiB"}},
+			usage:     NamespaceUsage{Executions: 1, Resources: Resources{Memory: 3 << 30}},
+			requested: 5,
+			expected:  1,
+		},
+		{
+			name:      "max gpu",
+			quota:     &NamespaceQuota{MaxResources: &ResourcesConfig{GPU: "2"}},
+			usage:     NamespaceUsage{Executions: 2, Resources: Resources{GPU: 2}},
+			requested: 5,
+			expected:  0,
+		},
+		{
+			name:      "most restrictive limit applies",
+			quota:     &NamespaceQuota{MaxConcurrentExecutions: 4, MaxResources: &ResourcesConfig{GPU: "2"}},
+			requested: 5,
+			expected:  2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.quota.AllowedExecutions(tc.usage, perExecution, tc.requested))
+		})
+	}
+}
+
+func TestNamespaceQuota_String(t *testing.T) {
+	assert.Equal(t, "unlimited", (*NamespaceQuota)(nil).String())
+	assert.Equal(t, "unlimited, non-preemptible", (&NamespaceQuota{NonPreemptible: true}).String())
+	assert.Equal(t, "executions: 4, cpu: 2", (&NamespaceQuota{
+		MaxConcurrentExecutions: 4, MaxResources: &ResourcesConfig{CPU: "2"}}).String())
+}
@@ -197,6 +197,22 @@ func (n *NetworkConfig) DomainSet() []string {
 	return compact(domains)
 }
 
+// AllowsHost returns whether connections to the host are allowed by the network config. Hosts of HTTP networking
+// are matched against its domains the way our Docker HTTP gateway matches them, where domains starting with a dot
+// also match their subdomains.
+func (n *NetworkConfig) AllowsHost(host string) bool {
+	switch n.Type {
+	case NetworkFull:
+		return true
+	case NetworkHTTP:
+		return slices.ContainsFunc(n.Domains, func(domain string) bool {
+			return matchDomain(domain, host) == 0
+		})
+	default:
+		return false
+	}
+}
+
 func matchDomain(left, right string) (diff int) {
 	const wildcard = ""
 	lefts := strings.Split(strings.ToLower(strings.Trim(left, " ")), ".")
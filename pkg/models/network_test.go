@@ -119,3 +119,23 @@ func TestDomainMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowsHost(t *testing.T) {
+	http := NetworkConfig{Type: NetworkHTTP, Domains: []string{"foo.com", ".bar.com", "192.168.0.1"}}
+	for host, allowed := range map[string]bool{
+		"foo.com":     true,
+		"FOO.com":     true,
+		"x.foo.com":   false,
+		"bar.com":     true,
+		"x.bar.com":   true,
+		"baz.com":     false,
+		"192.168.0.1": true,
+		"192.168.0.2": false,
+	} {
+		t.Run(host, func(t *testing.T) {
+			require.Equal(t, allowed, http.AllowsHost(host))
+			require.True(t, (&NetworkConfig{Type: NetworkFull}).AllowsHost(host))
+			require.False(t, (&NetworkConfig{Type: NetworkNone, Domains: http.Domains}).AllowsHost(host))
+		})
+	}
+}
@@ -113,4 +113,7 @@ type ComputeNodeInfo struct {
 	MaxJobRequirements Resources `json:"MaxJobRequirements"`
 	RunningExecutions  int       `json:"RunningExecutions"`
 	EnqueuedExecutions int       `json:"EnqueuedExecutions"`
+	// CachedInputs are the cache keys of the most recently used inputs in the node's input cache,
+	// which let requester nodes prefer nodes that already hold the inputs of a job.
+	CachedInputs []string `json:"CachedInputs,omitempty"`
 }
@@ -1,9 +1,51 @@
 package models
 
+import "time"
+
+// DefaultNodeDrainTimeout is the default time executions running on a node being drained
+// are given to complete before they are stopped and rescheduled on other nodes.
+const DefaultNodeDrainTimeout = time.Hour
+
 // NodeState contains metadata about the state of a node on the network. Requester nodes maintain a NodeState for
 // each node they are aware of. The NodeState represents a Requester nodes view of another node on the network.
 type NodeState struct {
 	Info       NodeInfo            `json:"Info"`
 	Membership NodeMembershipState `json:"Membership"`
 	Connection NodeConnectionState `json:"Connection"`
+	// Cordon is set when the node is cordoned, and no new executions should be placed on it.
+	Cordon *NodeCordon `json:"Cordon,omitempty"`
+}
+
+// IsCordoned returns true if no new executions should be placed on the node
+func (s NodeState) IsCordoned() bool {
+	return s.Cordon != nil
+}
+
+// IsDraining returns true if the executions of the node should be migrated to other nodes
+func (s NodeState) IsDraining() bool {
+	return s.Cordon != nil && s.Cordon.Drain
+}
+
+// NodeCordon records why and when a node was cordoned, and whether it is being drained.
+type NodeCordon struct {
+	// Reason is the message given when cordoning the node, for audit.
+	Reason string `json:"Reason,omitempty"`
+	// CreateTime is the time the node was cordoned.
+	CreateTime time.Time `json:"CreateTime"`
+	// Drain is true if the executions of the node are being stopped and rescheduled on other nodes.
+	Drain bool `json:"Drain,omitempty"`
+	// DrainDeadline is the time by which executions still running on the node are stopped.
+	// Until then, executions of batch jobs are given time to complete.
+	DrainDeadline time.Time `json:"DrainDeadline,omitempty"`
+}
+
+// String returns a short description of the cordon, such as "DRAINING" or "CORDONED"
+func (c *NodeCordon) String() string {
+	if c == nil {
+		return ""
+	}
+	if c.Drain {
+		return "DRAINING"
+	}
+	return "CORDONED"
 }
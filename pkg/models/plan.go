@@ -4,6 +4,18 @@ type PlanExecutionDesiredUpdate struct {
 	Execution    *Execution                `json:"Execution"`
 	DesiredState ExecutionDesiredStateType `json:"DesiredState"`
 	Event        Event                     `json:"Event"`
+	// PreemptedBy is the ID of the job the execution is stopped for, if it is being preempted.
+	PreemptedBy string `json:"PreemptedBy,omitempty"`
+	// Drained is true if the execution is stopped to drain its node.
+	Drained bool `json:"Drained,omitempty"`
+}
+
+// PlanJobDesiredUpdate holds a desired change to a job other than the plan's job,
+// such as a job spawned or replaced by a scheduled job.
+type PlanJobDesiredUpdate struct {
+	Job          *Job         `json:"Job"`
+	DesiredState JobStateType `json:"DesiredState"`
+	Event        Event        `json:"Event"`
 }
 
 // Plan holds actions as a result of processing an evaluation by the scheduler.
@@ -23,6 +35,24 @@ type Plan struct {
 	NewExecutions []*Execution `json:"NewExecutions,omitempty"`
 
 	UpdatedExecutions map[string]*PlanExecutionDesiredUpdate `json:"UpdatedExecutions,omitempty"`
+
+	// NewEvaluations holds follow-up evaluations to be created, such as to retry
+	// the job after a delay.
+	NewEvaluations []*Evaluation `json:"NewEvaluations,omitempty"`
+
+	// NewJobs holds other jobs to be created, such as the jobs spawned by a scheduled job.
+	NewJobs []*PlanJobDesiredUpdate `json:"NewJobs,omitempty"`
+
+	// UpdatedJobs holds state changes to other jobs, such as stopping the jobs replaced by a scheduled job.
+	UpdatedJobs map[string]*PlanJobDesiredUpdate `json:"UpdatedJobs,omitempty"`
+
+	// DeletedJobs holds the IDs of other jobs to be deleted, such as the runs
+	// of a scheduled job that exceed its history limit.
+	DeletedJobs []string `json:"DeletedJobs,omitempty"`
+
+	// ResultCacheEntry records the plan's job as the job whose published results are reused
+	// by later identical jobs, once it has completed.
+	ResultCacheEntry *ResultCacheEntry `json:"ResultCacheEntry,omitempty"`
 }
 
 // NewPlan creates a new Plan instance.
@@ -34,6 +64,7 @@ func NewPlan(eval *Evaluation, job *Job) *Plan {
 		Job:               job,
 		NewExecutions:     []*Execution{},
 		UpdatedExecutions: make(map[string]*PlanExecutionDesiredUpdate),
+		UpdatedJobs:       make(map[string]*PlanJobDesiredUpdate),
 	}
 }
 
@@ -52,6 +83,27 @@ func (p *Plan) AppendStoppedExecution(execution *Execution, event Event) {
 	p.UpdatedExecutions[execution.ID] = updateRequest
 }
 
+// AppendPreemptedExecution marks an execution of another job to be stopped
+// to make room for the plan's job.
+func (p *Plan) AppendPreemptedExecution(execution *Execution, event Event) {
+	p.UpdatedExecutions[execution.ID] = &PlanExecutionDesiredUpdate{
+		Execution:    execution,
+		DesiredState: ExecutionDesiredStateStopped,
+		Event:        event,
+		PreemptedBy:  p.Job.ID,
+	}
+}
+
+// AppendDrainedExecution marks an execution to be stopped because its node is being drained.
+func (p *Plan) AppendDrainedExecution(execution *Execution, event Event) {
+	p.UpdatedExecutions[execution.ID] = &PlanExecutionDesiredUpdate{
+		Execution:    execution,
+		DesiredState: ExecutionDesiredStateStopped,
+		Event:        event,
+		Drained:      true,
+	}
+}
+
 // AppendApprovedExecution marks an execution as accepted and ready to be started.
 func (p *Plan) AppendApprovedExecution(execution *Execution) {
 	updateRequest := &PlanExecutionDesiredUpdate{
@@ -61,11 +113,55 @@ func (p *Plan) AppendApprovedExecution(execution *Execution) {
 	p.UpdatedExecutions[execution.ID] = updateRequest
 }
 
+// AppendEvaluation appends a follow-up evaluation to the plan.
+func (p *Plan) AppendEvaluation(eval *Evaluation) {
+	p.NewEvaluations = append(p.NewEvaluations, eval)
+}
+
+// AppendJob appends a new job to be created, such as a job spawned by a scheduled job.
+func (p *Plan) AppendJob(job *Job, event Event) {
+	p.NewJobs = append(p.NewJobs, &PlanJobDesiredUpdate{
+		Job:          job,
+		DesiredState: JobStateTypePending,
+		Event:        event,
+	})
+}
+
+// AppendStoppedJob marks another job to be stopped.
+func (p *Plan) AppendStoppedJob(job *Job, event Event) {
+	p.UpdatedJobs[job.ID] = &PlanJobDesiredUpdate{
+		Job:          job,
+		DesiredState: JobStateTypeStopped,
+		Event:        event,
+	}
+}
+
+// AppendDeletedJob marks another job to be deleted.
+func (p *Plan) AppendDeletedJob(jobID string) {
+	p.DeletedJobs = append(p.DeletedJobs, jobID)
+}
+
+// MarkJobRetryDelayed defers retrying the job to the given follow-up evaluation,
+// and records the event in the job's history without changing the job's state.
+func (p *Plan) MarkJobRetryDelayed(eval *Evaluation, event Event) {
+	p.DesiredJobState = p.Job.State.StateType
+	p.Event = event
+	p.AppendEvaluation(eval)
+}
+
 func (p *Plan) MarkJobCompleted() {
 	p.DesiredJobState = JobStateTypeCompleted
 	p.NewExecutions = []*Execution{}
 }
 
+// MarkJobCompletedFromCache completes the job with executions that reuse the published results
+// of a previous identical job, instead of running it.
+func (p *Plan) MarkJobCompletedFromCache(executions []*Execution, event Event) {
+	p.MarkJobCompleted()
+	p.Event = event
+	p.NewExecutions = append(p.NewExecutions, executions...)
+}
+
 // MarkJobRunningIfEligible updates the job state to "Running" under certain conditions.
 func (p *Plan) MarkJobRunningIfEligible() {
 	// Exit the function if DesiredJobState is already defined.
@@ -92,6 +188,7 @@ func (p *Plan) MarkJobFailed(event Event) {
 	p.Event = event
 
 	p.NewExecutions = []*Execution{}
+	p.NewEvaluations = nil
 	// drop any update that is not stopping an execution
 	for id, update := range p.UpdatedExecutions {
 		if update.DesiredState != ExecutionDesiredStateStopped {
@@ -3,6 +3,7 @@ package models
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -20,6 +21,8 @@ type ResourcesConfig struct {
 	// Memory github.com/dustin/go-humanize string
 	Disk string `json:"Disk,omitempty"`
 	GPU  string `json:"GPU,omitempty"`
+	// GPUSelector restricts the GPUs the task can be allocated. Requests a single GPU if GPU is not set.
+	GPUSelector *GPUSelector `json:"GPUSelector,omitempty"`
 }
 
 // Normalize normalizes the resources
@@ -48,6 +51,7 @@ func (r *ResourcesConfig) Copy() *ResourcesConfig {
 	}
 	newR := new(ResourcesConfig)
 	*newR = *r
+	newR.GPUSelector = r.GPUSelector.Copy()
 	return newR
 }
 
@@ -100,6 +104,15 @@ func (r *ResourcesConfig) ToResources() (*Resources, error) {
 		}
 		res.GPU = gpu
 	}
+	if r.GPUSelector != nil {
+		if err := r.GPUSelector.Validate(); err != nil {
+			mErr = errors.Join(mErr, err)
+		}
+		res.GPUSelector = r.GPUSelector.Copy()
+		if res.GPU == 0 {
+			res.GPU = 1
+		}
+	}
 
 	return res, mErr
 }
@@ -179,6 +192,8 @@ type Resources struct {
 	GPU uint64 `json:"GPU,omitempty"`
 	// GPU details
 	GPUs []GPU `json:"GPUs,omitempty"`
+	// GPUSelector restricts the GPUs that can be allocated to satisfy the GPU units
+	GPUSelector *GPUSelector `json:"GPUSelector,omitempty"`
 }
 
 // Copy returns a deep copy of the resources
@@ -188,6 +203,8 @@ func (r *Resources) Copy() *Resources {
 	}
 	newR := new(Resources)
 	*newR = *r
+	newR.GPUs = slices.Clone(r.GPUs)
+	newR.GPUSelector = r.GPUSelector.Copy()
 	return newR
 }
 
@@ -232,47 +249,50 @@ func (r *Resources) Merge(other Resources) *Resources {
 	return newR
 }
 
-// Add returns the sum of the resources
+// Add returns the sum of the resources. The GPU selector of the sum is the first one set.
 func (r *Resources) Add(other Resources) *Resources {
+	selector := r.GPUSelector
+	if selector == nil {
+		selector = other.GPUSelector
+	}
 	return &Resources{
-		CPU:    r.CPU + other.CPU,
-		Memory: r.Memory + other.Memory,
-		Disk:   r.Disk + other.Disk,
-		GPU:    r.GPU + other.GPU,
-		GPUs:   append(r.GPUs, other.GPUs...),
+		CPU:         r.CPU + other.CPU,
+		Memory:      r.Memory + other.Memory,
+		Disk:        r.Disk + other.Disk,
+		GPU:         r.GPU + other.GPU,
+		GPUs:        append(slices.Clone(r.GPUs), other.GPUs...),
+		GPUSelector: selector.Copy(),
 	}
 }
 
+// Sub returns the resources left after subtracting other. Each field is clamped to zero
+// separately, as its unsigned fields would otherwise underflow.
 func (r *Resources) Sub(other Resources) *Resources {
 	usage := &Resources{
-		CPU:    r.CPU - other.CPU,
-		Memory: r.Memory - other.Memory,
-		Disk:   r.Disk - other.Disk,
-		GPU:    r.GPU - other.GPU,
+		CPU:    max(r.CPU-other.CPU, 0),
+		Memory: subClamped(r.Memory, other.Memory),
+		Disk:   subClamped(r.Disk, other.Disk),
+		GPU:    subClamped(r.GPU, other.GPU),
 	}
 
 	usage.GPUs, _ = lo.Difference(r.GPUs, other.GPUs)
 
-	if r.LessThan(other) {
+	if other.CPU > r.CPU || other.Memory > r.Memory || other.Disk > r.Disk || other.GPU > r.GPU {
 		log.Warn().Msgf("Subtracting larger resource usage %s from %s. Replacing negative values with zeros",
 			other.String(), r.String())
-		if other.CPU > r.CPU {
-			usage.CPU = 0
-		}
-		if other.Memory > r.Memory {
-			usage.Memory = 0
-		}
-		if other.Disk > r.Disk {
-			usage.Disk = 0
-		}
-		if other.GPU > r.GPU {
-			usage.GPU = 0
-		}
 	}
 
 	return usage
 }
 
+// subClamped returns a-b, or zero if b is larger
+func subClamped(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
 func (r *Resources) LessThan(other Resources) bool {
 	return r.CPU < other.CPU && r.Memory < other.Memory && r.Disk < other.Disk && r.GPU < other.GPU
 }
@@ -299,6 +319,20 @@ func (r *Resources) Max(other Resources) *Resources {
 	return newR
 }
 
+// MatchingGPUs returns the GPUs of the resources that satisfy the selector
+func (r *Resources) MatchingGPUs(selector *GPUSelector) []GPU {
+	return lo.Filter(r.GPUs, func(gpu GPU, _ int) bool { return selector.Matches(gpu) })
+}
+
+// HasGPUsFor returns true if the resources have enough GPUs satisfying the GPU selector of the requested resources.
+// It is always true if no selector is requested, as GPU units are compared by LessThanEq.
+func (r *Resources) HasGPUsFor(requested Resources) bool {
+	if requested.GPUSelector == nil || requested.GPU == 0 {
+		return true
+	}
+	return uint64(len(r.MatchingGPUs(requested.GPUSelector))) >= requested.GPU
+}
+
 func (r *Resources) IsZero() bool {
 	return r.CPU == 0 && r.Memory == 0 && r.Disk == 0 && r.GPU == 0
 }
@@ -307,6 +341,9 @@ func (r *Resources) IsZero() bool {
 func (r *Resources) String() string {
 	mem := humanize.Bytes(r.Memory)
 	disk := humanize.Bytes(r.Disk)
+	if r.GPUSelector != nil {
+		return fmt.Sprintf("{CPU: %f, Memory: %s, Disk: %s, GPU: %d %s}", r.CPU, mem, disk, r.GPU, r.GPUSelector)
+	}
 	return fmt.Sprintf("{CPU: %f, Memory: %s, Disk: %s, GPU: %d}", r.CPU, mem, disk, r.GPU)
 }
 
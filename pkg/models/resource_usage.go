@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// ResourceUsage is the resources an execution actually used while it ran, as measured by its executor,
+// as opposed to the resources allocated to it.
+type ResourceUsage struct {
+	// CPUSeconds is the CPU time used, in seconds.
+	CPUSeconds float64 `json:"CPUSeconds"`
+
+	// PeakMemory is the highest memory usage observed, in bytes.
+	PeakMemory uint64 `json:"PeakMemory"`
+
+	// DiskReadBytes and DiskWriteBytes are the bytes read from and written to block devices.
+	DiskReadBytes  uint64 `json:"DiskReadBytes"`
+	DiskWriteBytes uint64 `json:"DiskWriteBytes"`
+
+	// NetworkRxBytes and NetworkTxBytes are the bytes received and sent over the network.
+	NetworkRxBytes uint64 `json:"NetworkRxBytes"`
+	NetworkTxBytes uint64 `json:"NetworkTxBytes"`
+
+	// FunctionCalls is the number of function calls made by WASM modules, which meters their work
+	// deterministically. It is zero for other engines.
+	FunctionCalls uint64 `json:"FunctionCalls,omitempty"`
+}
+
+// Copy returns a deep copy of the usage
+func (u *ResourceUsage) Copy() *ResourceUsage {
+	if u == nil {
+		return nil
+	}
+	nu := new(ResourceUsage)
+	*nu = *u
+	return nu
+}
+
+// Add accounts for the usage of another execution. CPU time and bytes are summed,
+// and the peak memory is the highest of both.
+func (u *ResourceUsage) Add(other *ResourceUsage) {
+	if other == nil {
+		return
+	}
+	u.CPUSeconds += other.CPUSeconds
+	u.PeakMemory = max(u.PeakMemory, other.PeakMemory)
+	u.DiskReadBytes += other.DiskReadBytes
+	u.DiskWriteBytes += other.DiskWriteBytes
+	u.NetworkRxBytes += other.NetworkRxBytes
+	u.NetworkTxBytes += other.NetworkTxBytes
+	u.FunctionCalls += other.FunctionCalls
+}
+
+// IsZero returns true if no usage was recorded
+func (u *ResourceUsage) IsZero() bool {
+	return u == nil || *u == ResourceUsage{}
+}
+
+// CPUString returns the CPU time in a human-readable form
+func (u *ResourceUsage) CPUString() string {
+	if u == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.1fs", u.CPUSeconds)
+}
+
+// MemoryString returns the peak memory in a human-readable form
+func (u *ResourceUsage) MemoryString() string {
+	if u == nil {
+		return ""
+	}
+	return humanize.Bytes(u.PeakMemory)
+}
+
+// IOString returns the bytes read and written to disk and the network in a human-readable form
+func (u *ResourceUsage) IOString() string {
+	if u == nil {
+		return ""
+	}
+	return fmt.Sprintf("disk: %s/%s, net: %s/%s",
+		humanize.Bytes(u.DiskReadBytes), humanize.Bytes(u.DiskWriteBytes),
+		humanize.Bytes(u.NetworkRxBytes), humanize.Bytes(u.NetworkTxBytes))
+}
+
+// FunctionCallsString returns the number of function calls in a human-readable form
+func (u *ResourceUsage) FunctionCallsString() string {
+	if u == nil {
+		return ""
+	}
+	return humanize.Comma(int64(u.FunctionCalls))
+}
+
+func (u *ResourceUsage) String() string {
+	if u == nil {
+		return ""
+	}
+	parts := []string{
+		"cpu: " + u.CPUString(),
+		"peak memory: " + u.MemoryString(),
+		u.IOString(),
+	}
+	if u.FunctionCalls > 0 {
+		parts = append(parts, "function calls: "+u.FunctionCallsString())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// JobResourceUsage is the resources used by the executions of a job
+type JobResourceUsage struct {
+	JobID      string        `json:"JobID"`
+	Namespace  string        `json:"Namespace"`
+	Executions int           `json:"Executions"`
+	Usage      ResourceUsage `json:"Usage"`
+}
+
+// NamespaceResourceUsage is the resources used by the executions of the jobs in a namespace
+type NamespaceResourceUsage struct {
+	Namespace  string        `json:"Namespace"`
+	Jobs       int           `json:"Jobs"`
+	Executions int           `json:"Executions"`
+	Usage      ResourceUsage `json:"Usage"`
+}
@@ -30,3 +30,10 @@ func TestResourceBytes(t *testing.T) {
 		require.Equal(t, p.exp, actual.Disk)
 	}
 }
+
+func TestResourcesSub_ClampsEachField(t *testing.T) {
+	r := Resources{CPU: 2, Memory: 100, Disk: 50, GPU: 1}
+	// only memory is larger, which must not underflow
+	require.Equal(t, &Resources{CPU: 1, Memory: 0, Disk: 40, GPU: 1, GPUs: []GPU{}}, r.Sub(Resources{CPU: 1, Memory: 200, Disk: 10}))
+	require.Equal(t, &Resources{GPUs: []GPU{}}, r.Sub(Resources{CPU: 3, Memory: 200, Disk: 60, GPU: 2}))
+}
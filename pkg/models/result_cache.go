@@ -0,0 +1,110 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResultCacheEntry records the job whose published results are reused by later jobs
+// of the same namespace that have the same result cache key.
+type ResultCacheEntry struct {
+	// Namespace is the namespace of the job, as results are never shared across namespaces.
+	Namespace string `json:"Namespace"`
+
+	// Key is the result cache key of the job. See Job.ResultCacheKey.
+	Key string `json:"Key"`
+
+	// JobID is the ID of the job that completed and published the cached results.
+	JobID string `json:"JobID"`
+
+	CreateTime int64 `json:"CreateTime"`
+}
+
+// Normalize normalizes the entry
+func (e *ResultCacheEntry) Normalize() {
+	if e == nil {
+		return
+	}
+	e.Namespace = strings.TrimSpace(e.Namespace)
+	e.Key = strings.TrimSpace(e.Key)
+	e.JobID = strings.TrimSpace(e.JobID)
+}
+
+// Validate returns an error if the entry is missing its key or job
+func (e *ResultCacheEntry) Validate() error {
+	if e.Key == "" {
+		return fmt.Errorf("result cache entry of job %s is missing its key", e.JobID)
+	}
+	if e.JobID == "" {
+		return fmt.Errorf("result cache entry %s is missing its job", e.Key)
+	}
+	return nil
+}
+
+// IsCacheable returns true if the results of the task can be reused by identical tasks.
+// Tasks that reference secrets are not, as the values of the secrets can change between
+// jobs without changing the task.
+func (t *Task) IsCacheable() bool {
+	return t != nil && t.Cacheable && len(t.SecretRefs()) == 0
+}
+
+// IsCacheable returns true if the job can complete with the results of a previous identical job.
+// Only batch jobs whose main task is cacheable are, as the results of array jobs differ by index.
+func (j *Job) IsCacheable() bool {
+	return j.Type == JobTypeBatch && !j.IsArray() && j.Task().IsCacheable()
+}
+
+// taskCacheSpec holds the fields of a task that determine its results
+type taskCacheSpec struct {
+	Lifecycle    string            `json:"Lifecycle,omitempty"`
+	Engine       *SpecConfig       `json:"Engine"`
+	Publisher    *SpecConfig       `json:"Publisher"`
+	Env          map[string]string `json:"Env,omitempty"`
+	InputSources []*InputSource    `json:"InputSources,omitempty"`
+	ResultPaths  []*ResultPath     `json:"ResultPaths,omitempty"`
+}
+
+// ResultCacheKey returns a content hash of the fields of the job's tasks that determine their results:
+// the engine spec, input sources, environment variables, publisher and result paths. Fields such as the
+// task names, input aliases, resources and timeouts are ignored, and input sources and result paths are
+// hashed in a canonical order, so that jobs that only differ by those fields share the same key.
+func (j *Job) ResultCacheKey() (string, error) {
+	specs := make([]taskCacheSpec, 0, len(j.Tasks))
+	for _, task := range j.Tasks {
+		spec := taskCacheSpec{
+			Lifecycle:    task.Lifecycle,
+			Engine:       task.Engine,
+			Publisher:    task.Publisher,
+			Env:          task.Env,
+			InputSources: CopySlice(task.InputSources),
+			ResultPaths:  CopySlice(task.ResultPaths),
+		}
+		// aliases only name the input sources, such as after the upstream job they were injected from
+		for _, input := range spec.InputSources {
+			input.Alias = ""
+		}
+		// an empty publisher is the same as no publisher
+		if spec.Publisher != nil && spec.Publisher.Type == "" {
+			spec.Publisher = nil
+		}
+		sort.SliceStable(spec.InputSources, func(a, b int) bool {
+			return spec.InputSources[a].Target < spec.InputSources[b].Target
+		})
+		sort.SliceStable(spec.ResultPaths, func(a, b int) bool {
+			return spec.ResultPaths[a].Path < spec.ResultPaths[b].Path
+		})
+		specs = append(specs, spec)
+	}
+
+	// maps are marshalled with sorted keys, which makes the encoding canonical
+	data, err := json.Marshal(specs)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute result cache key of job %s: %w", j.ID, err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,86 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cacheableJob() *Job {
+	job := &Job{
+		ID:        "job-1",
+		Namespace: "default",
+		Type:      JobTypeBatch,
+		Count:     1,
+		Tasks: []*Task{{
+			Name:      "main",
+			Engine:    NewSpecConfig("docker").WithParam("Image", "ubuntu@sha256:abc"),
+			Publisher: NewSpecConfig("ipfs"),
+			Env:       map[string]string{"A": "1", "B": "2"},
+			InputSources: []*InputSource{
+				{Source: NewSpecConfig(StorageSourceIPFS).WithParam("CID", "Qm1"), Alias: "one", Target: "/inputs/1"},
+				{Source: NewSpecConfig(StorageSourceIPFS).WithParam("CID", "Qm2"), Alias: "two", Target: "/inputs/2"},
+			},
+			Cacheable: true,
+		}},
+	}
+	job.Normalize()
+	return job
+}
+
+func TestJob_IsCacheable(t *testing.T) {
+	job := cacheableJob()
+	assert.True(t, job.IsCacheable())
+
+	job.Task().Cacheable = false
+	assert.False(t, job.IsCacheable())
+
+	job = cacheableJob()
+	job.Type = JobTypeService
+	assert.False(t, job.IsCacheable())
+	assert.Error(t, job.ValidateSubmission())
+
+	job = cacheableJob()
+	job.Array = &JobArray{Start: 0, End: 1}
+	assert.False(t, job.IsCacheable())
+	assert.Error(t, job.ValidateSubmission())
+
+	job = cacheableJob()
+	job.Task().Env = map[string]string{"TOKEN": SecretRefPrefix + "token"}
+	assert.False(t, job.IsCacheable())
+}
+
+func TestJob_ResultCacheKey(t *testing.T) {
+	key, err := cacheableJob().ResultCacheKey()
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	// fields that do not determine the results are ignored
+	job := cacheableJob()
+	job.ID = "job-2"
+	job.Task().Name = "renamed"
+	job.Task().ResourcesConfig.Memory = "8gb"
+	job.Task().InputSources[0].Alias = "renamed"
+	job.Task().InputSources[0], job.Task().InputSources[1] = job.Task().InputSources[1], job.Task().InputSources[0]
+	sameKey, err := job.ResultCacheKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	for name, change := range map[string]func(job *Job){
+		"image":     func(job *Job) { job.Task().Engine.Params["Image"] = "ubuntu@sha256:def" },
+		"input":     func(job *Job) { job.Task().InputSources[0].Source.Params["CID"] = "Qm3" },
+		"env":       func(job *Job) { job.Task().Env["A"] = "3" },
+		"publisher": func(job *Job) { job.Task().Publisher = NewSpecConfig("s3") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			job := cacheableJob()
+			change(job)
+			otherKey, err := job.ResultCacheKey()
+			require.NoError(t, err)
+			assert.NotEqual(t, key, otherKey)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+const defaultMemoryMultiplier = 2
+
+const (
+	// RetryOnExecutionError retries executions that failed on the compute node.
+	RetryOnExecutionError = "execution-error"
+
+	// RetryOnNodeLost retries executions that were lost because their compute node became unhealthy.
+	RetryOnNodeLost = "node-lost"
+
+	// RetryOnTimeout retries executions that exceeded their execution timeout.
+	RetryOnTimeout = "timeout"
+)
+
+// RetryOnClasses returns all the failure classes that can be retried. Executions that were preempted
+// or drained are not failures, and are always replaced.
+func RetryOnClasses() []string {
+	return []string{RetryOnExecutionError, RetryOnNodeLost, RetryOnTimeout}
+}
+
+// RetryPolicy defines how the executions of a job are retried when they fail.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the job's executions are attempted,
+	// including the first attempt, counted across all of the job's executions.
+	// A policy with MaxAttempts of 1 never retries. Zero means no limit.
+	MaxAttempts int `json:"MaxAttempts,omitempty"`
+
+	// InitialDelay is the delay in seconds before the first retry.
+	InitialDelay int64 `json:"InitialDelay,omitempty"`
+
+	// MaxDelay is the maximum delay in seconds between retries. Zero means no limit.
+	MaxDelay int64 `json:"MaxDelay,omitempty"`
+
+	// Multiplier is the factor by which the delay grows after each retry.
+	Multiplier float64 `json:"Multiplier,omitempty"`
+
+	// RetryOn is the list of failure classes that are retried. Empty means all failures are retried.
+	RetryOn []string `json:"RetryOn,omitempty"`
+
+	// ResizeOnOOM increases the memory requested by the executions that replace executions killed
+	// for exceeding their memory limit. Nil means retries request the same memory.
+	ResizeOnOOM *ResizePolicy `json:"ResizeOnOOM,omitempty"`
+}
+
+// ResizePolicy defines how the memory requested by a job grows when its executions are OOM killed.
+type ResizePolicy struct {
+	// MemoryMultiplier is the factor by which the memory is multiplied after each OOM kill. Defaults to 2.
+	MemoryMultiplier float64 `json:"MemoryMultiplier,omitempty"`
+
+	// MaxMemory is the ceiling of the memory the job's executions can request, as a humanize string.
+	MaxMemory string `json:"MaxMemory"`
+}
+
+// Normalize sets default values for the retry policy
+func (p *RetryPolicy) Normalize() {
+	if p == nil {
+		return
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = 1
+	}
+	if p.RetryOn == nil {
+		p.RetryOn = make([]string, 0)
+	}
+	for i := range p.RetryOn {
+		p.RetryOn[i] = strings.ToLower(strings.TrimSpace(p.RetryOn[i]))
+	}
+	if p.ResizeOnOOM != nil && p.ResizeOnOOM.MemoryMultiplier == 0 {
+		p.ResizeOnOOM.MemoryMultiplier = defaultMemoryMultiplier
+	}
+}
+
+// Copy returns a deep copy of the retry policy
+func (p *RetryPolicy) Copy() *RetryPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(RetryPolicy)
+	*np = *p
+	np.RetryOn = slices.Clone(p.RetryOn)
+	if p.ResizeOnOOM != nil {
+		resize := *p.ResizeOnOOM
+		np.ResizeOnOOM = &resize
+	}
+	return np
+}
+
+// Validate validates the retry policy
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	var mErr error
+	if p.MaxAttempts < 0 {
+		mErr = errors.Join(mErr, errors.New("max attempts must be >= 0"))
+	}
+	if p.InitialDelay < 0 {
+		mErr = errors.Join(mErr, errors.New("initial delay must be >= 0"))
+	}
+	if p.MaxDelay < 0 {
+		mErr = errors.Join(mErr, errors.New("max delay must be >= 0"))
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		mErr = errors.Join(mErr, errors.New("multiplier must be >= 1"))
+	}
+	for _, class := range p.RetryOn {
+		if !slices.Contains(RetryOnClasses(), class) {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid retry on class %q. valid classes are %v", class, RetryOnClasses()))
+		}
+	}
+	if p.ResizeOnOOM != nil {
+		if p.ResizeOnOOM.MemoryMultiplier != 0 && p.ResizeOnOOM.MemoryMultiplier <= 1 {
+			mErr = errors.Join(mErr, errors.New("memory multiplier must be > 1"))
+		}
+		if p.ResizeOnOOM.MaxMemory == "" {
+			mErr = errors.Join(mErr, errors.New("max memory is required to resize on OOM"))
+		} else if _, err := humanize.ParseBytes(p.ResizeOnOOM.MaxMemory); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("invalid max memory %q: %w", p.ResizeOnOOM.MaxMemory, err))
+		}
+	}
+	return mErr
+}
+
+// HasAttemptsLeft returns true if the job can be attempted again after the given number of failed attempts.
+func (p *RetryPolicy) HasAttemptsLeft(failedAttempts int) bool {
+	return p.MaxAttempts == 0 || failedAttempts < p.MaxAttempts
+}
+
+// ShouldRetryOn returns true if failures of the given class are retried.
+func (p *RetryPolicy) ShouldRetryOn(class string) bool {
+	return len(p.RetryOn) == 0 || slices.Contains(p.RetryOn, class)
+}
+
+// Delay returns the delay before the next attempt after the given number of failed attempts.
+func (p *RetryPolicy) Delay(failedAttempts int) time.Duration {
+	if failedAttempts <= 0 || p.InitialDelay == 0 {
+		return 0
+	}
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(failedAttempts-1))
+	if p.MaxDelay > 0 {
+		delay = math.Min(delay, float64(p.MaxDelay))
+	}
+	// guard against overflowing time.Duration with large multipliers
+	if delay >= math.MaxInt64/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay * float64(time.Second))
+}
+
+// NextMemory returns the memory to request after an execution that requested the given memory was OOM killed.
+// It is the memory multiplied by the policy's multiplier, capped to the policy's max memory.
+func (p *ResizePolicy) NextMemory(memory uint64) (uint64, error) {
+	maxMemory, err := humanize.ParseBytes(p.MaxMemory)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max memory %q: %w", p.MaxMemory, err)
+	}
+	multiplier := p.MemoryMultiplier
+	if multiplier == 0 {
+		multiplier = defaultMemoryMultiplier
+	}
+	next := float64(memory) * multiplier
+	if next >= float64(maxMemory) {
+		return maxMemory, nil
+	}
+	return max(memory, uint64(next)), nil
+}
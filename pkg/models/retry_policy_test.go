@@ -0,0 +1,72 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := &RetryPolicy{InitialDelay: 10, Multiplier: 2, MaxDelay: 60}
+	assert.Equal(t, time.Duration(0), policy.Delay(0))
+	assert.Equal(t, 10*time.Second, policy.Delay(1))
+	assert.Equal(t, 20*time.Second, policy.Delay(2))
+	assert.Equal(t, 40*time.Second, policy.Delay(3))
+	assert.Equal(t, 60*time.Second, policy.Delay(4))
+
+	// no multiplier means a fixed delay
+	policy = &RetryPolicy{InitialDelay: 5}
+	assert.Equal(t, 5*time.Second, policy.Delay(3))
+
+	// huge delays should not overflow
+	policy = &RetryPolicy{InitialDelay: 10, Multiplier: 10}
+	assert.Greater(t, policy.Delay(100), time.Duration(0))
+}
+
+func TestRetryPolicy_HasAttemptsLeft(t *testing.T) {
+	assert.True(t, (&RetryPolicy{}).HasAttemptsLeft(100))
+	assert.False(t, (&RetryPolicy{MaxAttempts: 1}).HasAttemptsLeft(1))
+	assert.True(t, (&RetryPolicy{MaxAttempts: 3}).HasAttemptsLeft(2))
+	assert.False(t, (&RetryPolicy{MaxAttempts: 3}).HasAttemptsLeft(3))
+}
+
+func TestRetryPolicy_ShouldRetryOn(t *testing.T) {
+	assert.True(t, (&RetryPolicy{}).ShouldRetryOn(RetryOnTimeout))
+	policy := &RetryPolicy{RetryOn: []string{RetryOnNodeLost}}
+	assert.True(t, policy.ShouldRetryOn(RetryOnNodeLost))
+	assert.False(t, policy.ShouldRetryOn(RetryOnExecutionError))
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	var nilPolicy *RetryPolicy
+	assert.NoError(t, nilPolicy.Validate())
+	assert.NoError(t, (&RetryPolicy{MaxAttempts: 3, InitialDelay: 1, Multiplier: 2, RetryOn: RetryOnClasses()}).Validate())
+	assert.Error(t, (&RetryPolicy{MaxAttempts: -1}).Validate())
+	assert.Error(t, (&RetryPolicy{InitialDelay: -1}).Validate())
+	assert.Error(t, (&RetryPolicy{Multiplier: 0.5}).Validate())
+	assert.Error(t, (&RetryPolicy{RetryOn: []string{"oom"}}).Validate())
+	assert.NoError(t, (&RetryPolicy{ResizeOnOOM: &ResizePolicy{MaxMemory: "8gb"}}).Validate())
+	assert.Error(t, (&RetryPolicy{ResizeOnOOM: &ResizePolicy{}}).Validate())
+	assert.Error(t, (&RetryPolicy{ResizeOnOOM: &ResizePolicy{MaxMemory: "lots"}}).Validate())
+	assert.Error(t, (&RetryPolicy{ResizeOnOOM: &ResizePolicy{MaxMemory: "8gb", MemoryMultiplier: 1}}).Validate())
+}
+
+func TestResizePolicy_NextMemory(t *testing.T) {
+	policy := &ResizePolicy{MaxMemory: "1gb"}
+	next, err := policy.NextMemory(256_000_000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(512_000_000), next)
+
+	// the memory is capped to the max memory
+	next, err = policy.NextMemory(600_000_000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1_000_000_000), next)
+
+	policy = &ResizePolicy{MaxMemory: "1gb", MemoryMultiplier: 1.5}
+	next, err = policy.NextMemory(100_000_000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(150_000_000), next)
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// ConcurrencyPolicyAllow allows a new run to start while previous runs are still active.
+	ConcurrencyPolicyAllow = "allow"
+
+	// ConcurrencyPolicyForbid skips a new run if a previous run is still active.
+	ConcurrencyPolicyForbid = "forbid"
+
+	// ConcurrencyPolicyReplace stops the active runs before starting a new run.
+	ConcurrencyPolicyReplace = "replace"
+)
+
+// ConcurrencyPolicies returns all the supported concurrency policies.
+func ConcurrencyPolicies() []string {
+	return []string{ConcurrencyPolicyAllow, ConcurrencyPolicyForbid, ConcurrencyPolicyReplace}
+}
+
+// Schedule defines when a scheduled job spawns new runs, and how the runs are managed.
+type Schedule struct {
+	// Cron is a standard five field cron expression, or a descriptor such as @daily or @every 1h.
+	Cron string `json:"Cron"`
+
+	// Timezone is the IANA timezone the cron expression is evaluated in. Defaults to UTC.
+	Timezone string `json:"Timezone,omitempty"`
+
+	// ConcurrencyPolicy defines what happens when a run is due while previous runs are still active.
+	ConcurrencyPolicy string `json:"ConcurrencyPolicy,omitempty"`
+
+	// HistoryLimit is the number of finished runs to keep. Zero means all runs are kept.
+	HistoryLimit int `json:"HistoryLimit,omitempty"`
+}
+
+// Normalize sets default values for the schedule
+func (s *Schedule) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Cron = strings.TrimSpace(s.Cron)
+	s.Timezone = strings.TrimSpace(s.Timezone)
+	s.ConcurrencyPolicy = strings.ToLower(strings.TrimSpace(s.ConcurrencyPolicy))
+	if s.ConcurrencyPolicy == "" {
+		s.ConcurrencyPolicy = ConcurrencyPolicyAllow
+	}
+}
+
+// Copy returns a deep copy of the schedule
+func (s *Schedule) Copy() *Schedule {
+	if s == nil {
+		return nil
+	}
+	ns := new(Schedule)
+	*ns = *s
+	return ns
+}
+
+// Validate validates the schedule
+func (s *Schedule) Validate() error {
+	if s == nil {
+		return errors.New("missing schedule")
+	}
+	var mErr error
+	if _, err := cron.ParseStandard(s.Cron); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid cron expression %q: %w", s.Cron, err))
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err))
+	}
+	if s.ConcurrencyPolicy != "" && !slices.Contains(ConcurrencyPolicies(), s.ConcurrencyPolicy) {
+		mErr = errors.Join(mErr, fmt.Errorf("invalid concurrency policy %q. valid policies are %v",
+			s.ConcurrencyPolicy, ConcurrencyPolicies()))
+	}
+	if s.HistoryLimit < 0 {
+		mErr = errors.Join(mErr, errors.New("history limit must be >= 0"))
+	}
+	return mErr
+}
+
+// Next returns the first time the schedule fires strictly after the given time.
+func (s *Schedule) Next(after time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", s.Cron, err)
+	}
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+	return schedule.Next(after.In(location)).UTC(), nil
+}
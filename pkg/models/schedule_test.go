@@ -0,0 +1,53 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	after := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	schedule := &Schedule{Cron: "0 2 * * *"}
+	next, err := schedule.Next(after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC), next)
+
+	// the cron expression is evaluated in the schedule's timezone
+	schedule = &Schedule{Cron: "0 2 * * *", Timezone: "America/New_York"}
+	next, err = schedule.Next(after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 2, 7, 0, 0, 0, time.UTC), next)
+
+	schedule = &Schedule{Cron: "@every 1h"}
+	next, err = schedule.Next(after)
+	require.NoError(t, err)
+	assert.Equal(t, after.Add(time.Hour), next)
+}
+
+func TestSchedule_Normalize(t *testing.T) {
+	schedule := &Schedule{Cron: " @daily ", ConcurrencyPolicy: " Forbid "}
+	schedule.Normalize()
+	assert.Equal(t, "@daily", schedule.Cron)
+	assert.Equal(t, ConcurrencyPolicyForbid, schedule.ConcurrencyPolicy)
+
+	schedule = &Schedule{Cron: "@daily"}
+	schedule.Normalize()
+	assert.Equal(t, ConcurrencyPolicyAllow, schedule.ConcurrencyPolicy)
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	var nilSchedule *Schedule
+	assert.Error(t, nilSchedule.Validate())
+	assert.NoError(t, (&Schedule{Cron: "*/5 * * * *", Timezone: "Europe/Lisbon", ConcurrencyPolicy: ConcurrencyPolicyReplace}).Validate())
+	assert.Error(t, (&Schedule{}).Validate())
+	assert.Error(t, (&Schedule{Cron: "every night"}).Validate())
+	assert.Error(t, (&Schedule{Cron: "@daily", Timezone: "Mars/Olympus_Mons"}).Validate())
+	assert.Error(t, (&Schedule{Cron: "@daily", ConcurrencyPolicy: "queue"}).Validate())
+	assert.Error(t, (&Schedule{Cron: "@daily", HistoryLimit: -1}).Validate())
+}
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// SecretRefPrefix is the prefix of the values of environment variables that reference a secret,
+	// e.g. secret:mytoken references the secret named mytoken in the namespace of the job.
+	SecretRefPrefix = "secret:"
+
+	// RedactedSecretValue replaces the values of secrets wherever they would otherwise be shown.
+	RedactedSecretValue = "[REDACTED]"
+
+	maxSecretNameLength = 128
+)
+
+var secretNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Secret is a named value, such as a credential, that tasks of the jobs in its namespace can reference
+// from their environment variables without it being part of the job spec.
+type Secret struct {
+	// Namespace is the namespace of the jobs that can reference the secret.
+	Namespace string `json:"Namespace"`
+
+	// Name is the name the secret is referenced by, unique within its namespace.
+	Name string `json:"Name"`
+
+	// Value is the value of the secret. It is encrypted while the secret is stored, and never
+	// returned once the secret has been created.
+	Value string `json:"Value,omitempty"`
+
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// Normalize normalizes the secret
+func (s *Secret) Normalize() {
+	if s == nil {
+		return
+	}
+	s.Namespace = strings.TrimSpace(s.Namespace)
+	if s.Namespace == "" {
+		s.Namespace = DefaultNamespace
+	}
+	s.Name = strings.TrimSpace(s.Name)
+}
+
+// Validate returns an error if the secret has an invalid name or no value
+func (s *Secret) Validate() error {
+	var mErr error
+	if err := ValidateSecretName(s.Name); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+	if s.Value == "" {
+		mErr = errors.Join(mErr, fmt.Errorf("secret %s has no value", s.Name))
+	}
+	return mErr
+}
+
+// GetCreateTime returns the creation time
+func (s *Secret) GetCreateTime() time.Time {
+	return time.Unix(0, s.CreateTime).UTC()
+}
+
+// GetModifyTime returns the modify time
+func (s *Secret) GetModifyTime() time.Time {
+	return time.Unix(0, s.ModifyTime).UTC()
+}
+
+// ValidateSecretName returns an error if the name is not a valid secret name
+func ValidateSecretName(name string) error {
+	if len(name) > maxSecretNameLength {
+		return fmt.Errorf("secret name %s is longer than %d characters", name, maxSecretNameLength)
+	}
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: must start with a letter or digit, "+
+			"and only contain letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+// SecretRef returns the name of the secret referenced by the value of an environment variable,
+// and false if the value does not reference a secret.
+func SecretRef(value string) (string, bool) {
+	if !strings.HasPrefix(value, SecretRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, SecretRefPrefix), true
+}
+
+// SecretRefs returns the sorted names of the secrets referenced by the environment variables of the task
+func (t *Task) SecretRefs() []string {
+	names := make(map[string]bool)
+	for _, value := range t.Env {
+		if name, ok := SecretRef(value); ok {
+			names[name] = true
+		}
+	}
+	refs := maps.Keys(names)
+	sort.Strings(refs)
+	return refs
+}
+
+// SecretRefs returns the sorted names of the secrets referenced by the tasks of the job
+func (j *Job) SecretRefs() []string {
+	names := make(map[string]bool)
+	for _, task := range j.Tasks {
+		for _, name := range task.SecretRefs() {
+			names[name] = true
+		}
+	}
+	refs := maps.Keys(names)
+	sort.Strings(refs)
+	return refs
+}
+
+// SecretValues holds the values of secrets keyed by their name. Values are redacted when formatted,
+// so that they are not leaked by logging the requests that carry them.
+type SecretValues map[string]string
+
+// String returns the names of the secrets, with their values redacted
+func (v SecretValues) String() string {
+	names := maps.Keys(v)
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+":"+RedactedSecretValue)
+	}
+	return "map[" + strings.Join(parts, " ") + "]"
+}
+
+// ResolveEnv returns a copy of the environment variables where the values that reference a secret
+// are replaced by the value of the secret. It returns an error if a referenced secret is missing.
+func (v SecretValues) ResolveEnv(env map[string]string) (map[string]string, error) {
+	if len(env) == 0 {
+		return env, nil
+	}
+	resolved := make(map[string]string, len(env))
+	for key, value := range env {
+		name, ok := SecretRef(value)
+		if !ok {
+			resolved[key] = value
+			continue
+		}
+		secret, ok := v[name]
+		if !ok {
+			return nil, fmt.Errorf("secret %s referenced by environment variable %s is not available", name, key)
+		}
+		resolved[key] = secret
+	}
+	return resolved, nil
+}
+
+// Redact replaces the values of the secrets found in the text with RedactedSecretValue
+func (v SecretValues) Redact(text string) string {
+	if len(v) == 0 || text == "" {
+		return text
+	}
+	values := maps.Values(v)
+	// replace longer values first, in case a value contains another
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		if value != "" {
+			text = strings.ReplaceAll(text, value, RedactedSecretValue)
+		}
+	}
+	return text
+}
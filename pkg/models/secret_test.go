@@ -0,0 +1,60 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Validate(t *testing.T) {
+	secret := &Secret{Name: " token ", Value: "s3cr3t"}
+	secret.Normalize()
+	assert.Equal(t, DefaultNamespace, secret.Namespace)
+	assert.Equal(t, "token", secret.Name)
+	require.NoError(t, secret.Validate())
+
+	assert.Error(t, (&Secret{Name: "token"}).Validate())
+	assert.Error(t, (&Secret{Name: "-token", Value: "v"}).Validate())
+	assert.Error(t, (&Secret{Name: "my token", Value: "v"}).Validate())
+	assert.NoError(t, (&Secret{Name: "my_token.v2-a", Value: "v"}).Validate())
+}
+
+func TestJob_SecretRefs(t *testing.T) {
+	job := &Job{Tasks: []*Task{
+		{Name: "main", Env: map[string]string{"B": "secret:b", "A": "secret:a", "PLAIN": "value"}},
+		{Name: "sidecar", Env: map[string]string{"A": "secret:a", "C": "secret:c"}},
+	}}
+	assert.Equal(t, []string{"a", "b"}, job.Tasks[0].SecretRefs())
+	assert.Equal(t, []string{"a", "b", "c"}, job.SecretRefs())
+	assert.Empty(t, (&Task{}).SecretRefs())
+}
+
+func TestSecretValues_ResolveEnv(t *testing.T) {
+	values := SecretValues{"token": "s3cr3t"}
+	env := map[string]string{"TOKEN": "secret:token", "MODE": "test"}
+
+	resolved, err := values.ResolveEnv(env)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "s3cr3t", "MODE": "test"}, resolved)
+	// the original environment keeps referencing the secret
+	assert.Equal(t, "secret:token", env["TOKEN"])
+
+	_, err = SecretValues(nil).ResolveEnv(env)
+	assert.ErrorContains(t, err, "secret token referenced by environment variable TOKEN is not available")
+}
+
+func TestSecretValues_Redact(t *testing.T) {
+	values := SecretValues{"short": "abc", "long": "abcdef"}
+	assert.Equal(t, "token [REDACTED] and [REDACTED]", values.Redact("token abcdef and abc"))
+	assert.Equal(t, "nothing to hide", values.Redact("nothing to hide"))
+	assert.Equal(t, "abc", SecretValues(nil).Redact("abc"))
+
+	// values are never formatted
+	formatted := fmt.Sprintf("%v %+v", values, struct{ Secrets SecretValues }{values})
+	assert.NotContains(t, formatted, "abc")
+	assert.Contains(t, formatted, "long:[REDACTED]")
+}
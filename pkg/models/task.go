@@ -3,6 +3,7 @@ package models
 import (
 	"errors"
 	"fmt"
+	"regexp"
 
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
@@ -10,10 +11,17 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
 )
 
+// taskNamePattern is the pattern of the names of non-main tasks, which name their results directory.
+var taskNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]*[a-z0-9])?$`)
+
 type Task struct {
 	// Name of the task
 	Name string `json:"Name"`
 
+	// Lifecycle defines when the task runs relative to the main task of the job,
+	// such as prestart, sidecar or poststop. Empty means the task is the main task.
+	Lifecycle string `json:"Lifecycle,omitempty"`
+
 	Engine *SpecConfig `json:"Engine"`
 
 	Publisher *SpecConfig `json:"Publisher"`
@@ -37,6 +45,14 @@ type Task struct {
 	Network *NetworkConfig `json:"Network,omitempty"`
 
 	Timeouts *TimeoutConfig `json:"Timeouts,omitempty"`
+
+	// Checkpoint enables checkpointing the task so that it can resume from where it was stopped.
+	Checkpoint *CheckpointConfig `json:"Checkpoint,omitempty"`
+
+	// Cacheable marks the task as deterministic, so that a job identical to a previous job that
+	// completed and published its results can reuse those results instead of running again.
+	// Only the main task of batch jobs is considered.
+	Cacheable bool `json:"Cacheable,omitempty"`
 }
 
 func (t *Task) MetricAttributes() []attribute.KeyValue {
@@ -98,6 +114,7 @@ func (t *Task) Copy() *Task {
 	nt.Env = maps.Clone(t.Env)
 	nt.Network = t.Network.Copy()
 	nt.Timeouts = t.Timeouts.Copy()
+	nt.Checkpoint = t.Checkpoint.Copy()
 	return nt
 }
 
@@ -123,6 +140,11 @@ func (t *Task) ValidateSubmission() error {
 		mErr = errors.Join(mErr, errors.New("missing task name"))
 	} else if validate.ContainsNull(t.Name) {
 		mErr = errors.Join(mErr, errors.New("task name contains null character"))
+	} else if !t.IsMain() && !taskNamePattern.MatchString(t.Name) {
+		// other tasks write their results to a directory named after them
+		mErr = errors.Join(mErr, fmt.Errorf(
+			"invalid task name %q: names of non-main tasks must be lower case letters, digits, '-' and '_', "+
+				"starting and ending with a letter or digit", t.Name))
 	}
 	if err := t.Engine.Validate(); err != nil {
 		mErr = errors.Join(mErr, fmt.Errorf("engine validation failed: %v", err))
@@ -136,9 +158,31 @@ func (t *Task) ValidateSubmission() error {
 	if err := ValidateSlice(t.ResultPaths); err != nil {
 		mErr = errors.Join(mErr, fmt.Errorf("output validation failed: %v", err))
 	}
-	if len(t.ResultPaths) > 0 && t.Publisher.IsEmpty() {
+	if err := ValidateTaskLifecycle(t.Lifecycle); err != nil {
+		mErr = errors.Join(mErr, err)
+	}
+	// results of other tasks are published by the main task, which is validated at the job level
+	if t.IsMain() && len(t.ResultPaths) > 0 && t.Publisher.IsEmpty() {
 		mErr = errors.Join(mErr, errors.New("publisher must be set if result paths are set"))
 	}
+	if t.Checkpoint != nil {
+		if err := t.Checkpoint.Validate(); err != nil {
+			mErr = errors.Join(mErr, fmt.Errorf("checkpoint validation failed: %v", err))
+		}
+		if !t.IsMain() {
+			mErr = errors.Join(mErr, errors.New("only the main task can be checkpointed"))
+		} else if t.Publisher.IsEmpty() {
+			mErr = errors.Join(mErr, errors.New("publisher must be set to store checkpoints"))
+		}
+	}
+
+	for key, value := range t.Env {
+		if name, ok := SecretRef(value); ok {
+			if err := ValidateSecretName(name); err != nil {
+				mErr = errors.Join(mErr, fmt.Errorf("environment variable %s references an invalid secret: %w", key, err))
+			}
+		}
+	}
 
 	seenInputAliases := make(map[string]bool)
 	for _, input := range t.InputSources {
@@ -151,6 +195,19 @@ func (t *Task) ValidateSubmission() error {
 	return mErr
 }
 
+// GetLifecycle returns the lifecycle of the task, defaulting to main
+func (t *Task) GetLifecycle() string {
+	if t.Lifecycle == "" {
+		return TaskLifecycleMain
+	}
+	return t.Lifecycle
+}
+
+// IsMain returns true if the task is the main task of its job
+func (t *Task) IsMain() bool {
+	return t.GetLifecycle() == TaskLifecycleMain
+}
+
 // ToBuilder returns a new task builder with the same values as the task
 func (t *Task) ToBuilder() *TaskBuilder {
 	return NewTaskBuilderFromTask(t)
@@ -22,6 +22,11 @@ func (b *TaskBuilder) Name(name string) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Lifecycle(lifecycle string) *TaskBuilder {
+	b.task.Lifecycle = lifecycle
+	return b
+}
+
 func (b *TaskBuilder) Engine(engine *SpecConfig) *TaskBuilder {
 	b.task.Engine = engine
 	return b
@@ -66,6 +71,11 @@ func (b *TaskBuilder) Timeouts(timeouts *TimeoutConfig) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Checkpoint(checkpoint *CheckpointConfig) *TaskBuilder {
+	b.task.Checkpoint = checkpoint
+	return b
+}
+
 func (b *TaskBuilder) Build() (*Task, error) {
 	b.task.Normalize()
 	return b.task, b.task.Validate()
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	// TaskLifecyclePrestart tasks run to completion, one after the other, before the main task is started.
+	// e.g. downloading or warming up a model before the model server starts.
+	TaskLifecyclePrestart = "prestart"
+
+	// TaskLifecycleMain is the task that defines the outcome of the execution.
+	// Tasks without a lifecycle are main tasks.
+	TaskLifecycleMain = "main"
+
+	// TaskLifecycleSidecar tasks run alongside the main task, and are stopped once it completes.
+	// e.g. a log shipper next to the main container.
+	TaskLifecycleSidecar = "sidecar"
+
+	// TaskLifecyclePoststop tasks run to completion, one after the other, after the main task completed.
+	TaskLifecyclePoststop = "poststop"
+)
+
+// TaskLifecycles returns all the supported task lifecycles in the order they are run.
+func TaskLifecycles() []string {
+	return []string{TaskLifecyclePrestart, TaskLifecycleMain, TaskLifecycleSidecar, TaskLifecyclePoststop}
+}
+
+// ValidateTaskLifecycle returns an error if the lifecycle is not supported.
+func ValidateTaskLifecycle(lifecycle string) error {
+	if lifecycle != "" && !slices.Contains(TaskLifecycles(), lifecycle) {
+		return fmt.Errorf("invalid task lifecycle %q. must be one of %v", lifecycle, TaskLifecycles())
+	}
+	return nil
+}
+
+const (
+	TaskStatePending   = "pending"
+	TaskStateRunning   = "running"
+	TaskStateCompleted = "completed"
+	TaskStateFailed    = "failed"
+	TaskStateStopped   = "stopped"
+)
+
+// TaskState is the observed state of a single task of an execution.
+type TaskState struct {
+	// Lifecycle of the task
+	Lifecycle string `json:"Lifecycle"`
+
+	// State of the task, such as running or completed
+	State string `json:"State"`
+
+	// ExitCode of the task, if it has finished running
+	ExitCode int `json:"ExitCode"`
+
+	// Message is a human readable description of the state, such as the reason of a failure
+	Message string `json:"Message,omitempty"`
+}
+
+// NewTaskStates returns the initial state of the tasks of a job, keyed by task name.
+func NewTaskStates(job *Job) map[string]*TaskState {
+	states := make(map[string]*TaskState, len(job.Tasks))
+	for _, task := range job.Tasks {
+		states[task.Name] = &TaskState{
+			Lifecycle: task.GetLifecycle(),
+			State:     TaskStatePending,
+		}
+	}
+	return states
+}
+
+// CopyTaskStates returns a deep copy of task states.
+func CopyTaskStates(states map[string]*TaskState) map[string]*TaskState {
+	if states == nil {
+		return nil
+	}
+	copied := make(map[string]*TaskState, len(states))
+	for name, state := range states {
+		s := *state
+		copied[name] = &s
+	}
+	return copied
+}
@@ -0,0 +1,105 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lifecycleTestTask(name string, lifecycle string, cpu string) *Task {
+	return &Task{
+		Name:            name,
+		Lifecycle:       lifecycle,
+		Engine:          &SpecConfig{Type: "noop"},
+		Publisher:       &SpecConfig{},
+		ResourcesConfig: &ResourcesConfig{CPU: cpu},
+	}
+}
+
+func TestJob_MultipleTasks(t *testing.T) {
+	job := &Job{
+		Type: JobTypeBatch,
+		Tasks: []*Task{
+			lifecycleTestTask("warmup", TaskLifecyclePrestart, "0.5"),
+			lifecycleTestTask("server", "", "1"),
+			lifecycleTestTask("logs", TaskLifecycleSidecar, "0.25"),
+		},
+	}
+	job.Tasks[1].Publisher = &SpecConfig{Type: "noop"}
+	require.NoError(t, job.ValidateSubmission())
+	assert.Empty(t, job.SanitizeSubmission())
+	assert.Len(t, job.Tasks, 3)
+
+	assert.Equal(t, "server", job.Task().Name)
+	assert.Equal(t, []*Task{job.Tasks[0]}, job.TasksWithLifecycle(TaskLifecyclePrestart))
+	assert.Empty(t, job.TasksWithLifecycle(TaskLifecyclePoststop))
+
+	resources, err := job.TotalResources()
+	require.NoError(t, err)
+	assert.Equal(t, 1.75, resources.CPU)
+
+	states := NewTaskStates(job)
+	assert.Equal(t, &TaskState{Lifecycle: TaskLifecycleMain, State: TaskStatePending}, states["server"])
+	assert.Equal(t, TaskLifecycleSidecar, states["logs"].Lifecycle)
+}
+
+func TestJob_ValidateTaskLifecycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []*Task
+	}{
+		{
+			name:  "no main task",
+			tasks: []*Task{lifecycleTestTask("warmup", TaskLifecyclePrestart, "1")},
+		},
+		{
+			name:  "multiple main tasks",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("b", TaskLifecycleMain, "1")},
+		},
+		{
+			name:  "duplicate task names",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("a", TaskLifecycleSidecar, "1")},
+		},
+		{
+			name:  "invalid lifecycle",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("b", "preStart", "1")},
+		},
+		{
+			name: "publisher on sidecar",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), func() *Task {
+				task := lifecycleTestTask("b", TaskLifecycleSidecar, "1")
+				task.Publisher = &SpecConfig{Type: "noop"}
+				return task
+			}()},
+		},
+		{
+			name:  "path traversal in task name",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("../../x", TaskLifecycleSidecar, "1")},
+		},
+		{
+			name:  "upper case task name",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("Logs", TaskLifecycleSidecar, "1")},
+		},
+		{
+			name:  "task name ending with a dash",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), lifecycleTestTask("logs-", TaskLifecycleSidecar, "1")},
+		},
+		{
+			name: "result paths without publisher on main task",
+			tasks: []*Task{lifecycleTestTask("a", "", "1"), func() *Task {
+				task := lifecycleTestTask("b", TaskLifecyclePoststop, "1")
+				task.ResultPaths = []*ResultPath{{Name: "out", Path: "/out"}}
+				return task
+			}()},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &Job{Type: JobTypeBatch, Tasks: tc.tasks}
+			assert.Error(t, job.ValidateSubmission())
+		})
+	}
+}
@@ -61,6 +61,8 @@ func (h *CallbackHandler) handle(msg *nats.Msg) {
 		processCallback(ctx, msg, h.callback.OnCancelComplete)
 	case OnComputeFailure:
 		processCallback(ctx, msg, h.callback.OnComputeFailure)
+	case OnCheckpoint:
+		processCallback(ctx, msg, h.callback.OnCheckpoint)
 	default:
 		// Noop, not subscribed to this method
 		return
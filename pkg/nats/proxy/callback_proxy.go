@@ -46,6 +46,10 @@ func (p *CallbackProxy) OnComputeFailure(ctx context.Context, result compute.Com
 	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnComputeFailure, result)
 }
 
+func (p *CallbackProxy) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnCheckpoint, result)
+}
+
 func proxyCallbackRequest(
 	ctx context.Context,
 	conn *nats.Conn,
@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog/log"
@@ -76,6 +78,9 @@ func handleRequest(msg *nats.Msg, handler *ComputeHandler) {
 		processAndRespond(ctx, handler.conn, msg, handler.computeEndpoint.CancelExecution)
 	case ExecutionLogs:
 		processAndStream(ctx, handler.streamingClient, msg, handler.computeEndpoint.ExecutionLogs)
+	case Exec:
+		// exec sessions are interactive and long-lived, so they must not block the handling of other requests
+		go processAndStream(ctx, handler.streamingClient, msg, handler.exec)
 	default:
 		// Noop, not subscribed to this method
 		return
@@ -126,6 +131,64 @@ func sendResponse[Response any](conn *nats.Conn, reply string, result *concurren
 	return conn.Publish(reply, resultData)
 }
 
+// exec subscribes to the input subject of an exec session, and forwards its messages to the compute endpoint
+// until the client detaches or the session ends.
+func (h *ComputeHandler) exec(ctx context.Context, request ExecRequest) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	input := make(chan models.ExecInput, asyncRequestChanLen)
+	done := make(chan struct{})
+	var closeOnce sync.Once
+	detach := func() {
+		closeOnce.Do(func() {
+			close(done)
+		})
+	}
+	subscription, err := h.conn.Subscribe(request.InputSubject, func(m *nats.Msg) {
+		// acknowledge the input, so that the proxy knows the session is subscribed
+		defer func() {
+			_ = m.Respond(nil)
+		}()
+		msg := new(ExecInput)
+		if err := json.Unmarshal(m.Data, msg); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("error decoding exec input on %s", m.Subject)
+			return
+		}
+		if msg.Detach {
+			detach()
+			return
+		}
+		select {
+		case input <- msg.Input:
+		case <-done:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := h.computeEndpoint.Exec(ctx, request.ExecRequest, input)
+	if err != nil {
+		_ = subscription.Unsubscribe()
+		return nil, err
+	}
+	go func() {
+		<-done
+		_ = subscription.Unsubscribe()
+		close(input)
+	}()
+
+	// the session is also detached when the command exits before the client detaches
+	res := make(chan *concurrency.AsyncResult[models.ExecOutput], asyncRequestChanLen)
+	go func() {
+		defer close(res)
+		defer detach()
+		for out := range output {
+			res <- out
+		}
+	}()
+	return res, nil
+}
+
 func processAndStream[Request, Response any](ctx context.Context, streamingClient *stream.Client, msg *nats.Msg,
 	f handlerWithResponse[Request, <-chan *concurrency.AsyncResult[Response]]) {
 	if msg.Reply == "" {
@@ -3,19 +3,28 @@ package proxy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/nats/stream"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nuid"
 	"github.com/rs/zerolog/log"
 )
 
 const (
 	// asyncRequestChanLen is the channel length for buffering asynchronous results.
 	asyncRequestChanLen = 8
+
+	// execInputTimeout is how long the input of an exec session waits to be acknowledged by the remote node.
+	execInputTimeout = 10 * time.Second
+	// execInputRetryInterval is how often the input of an exec session is retried while the remote node is
+	// not subscribed to the input subject yet.
+	execInputRetryInterval = 50 * time.Millisecond
 )
 
 type ComputeProxyParams struct {
@@ -89,6 +98,71 @@ func (p *ComputeProxy) ExecutionLogs(ctx context.Context, request compute.Execut
 		})
 }
 
+// Exec opens a stream with the output of a command run in an execution on a remote node, and publishes
+// the input of the command to a subject unique to the session, which the remote node subscribes to.
+func (p *ComputeProxy) Exec(ctx context.Context, request compute.ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	inputSubject := execInputSubject(request.TargetPeerID, nuid.Next())
+	res, err := proxyStreamingRequest[ExecRequest, models.ExecOutput](
+		ctx, p.streamingClient, &BaseRequest[ExecRequest]{
+			TargetNodeID: request.TargetPeerID,
+			Method:       Exec,
+			Body:         ExecRequest{ExecRequest: request, InputSubject: inputSubject},
+		})
+	if err != nil {
+		return nil, err
+	}
+	go p.forwardExecInput(ctx, inputSubject, input)
+	return res, nil
+}
+
+// forwardExecInput publishes the input of an exec session until the client detaches, or ctx is done.
+// Each message is acknowledged by the remote node, which also covers input sent before it subscribed to the subject.
+func (p *ComputeProxy) forwardExecInput(ctx context.Context, subject string, input <-chan models.ExecInput) {
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is done, so the detach message is published on its own context
+			p.publishExecInput(context.Background(), subject, ExecInput{Detach: true})
+			return
+		case msg, ok := <-input:
+			if !ok {
+				p.publishExecInput(ctx, subject, ExecInput{Detach: true})
+				return
+			}
+			p.publishExecInput(ctx, subject, ExecInput{Input: msg})
+		}
+	}
+}
+
+// publishExecInput publishes a message to the input subject of an exec session, and retries while the remote node
+// has not subscribed to it yet.
+func (p *ComputeProxy) publishExecInput(ctx context.Context, subject string, msg ExecInput) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to encode exec input")
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, execInputTimeout)
+	defer cancel()
+	for {
+		_, err = p.conn.RequestWithContext(ctx, subject, data)
+		if !errors.Is(err, nats.ErrNoResponders) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(execInputRetryInterval):
+			continue
+		}
+		break
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to publish exec input to %s", subject)
+	}
+}
+
 func proxyRequest[Request any, Response any](
 	ctx context.Context,
 	conn *nats.Conn,
@@ -6,17 +6,20 @@ const (
 	ComputeEndpointSubjectPrefix = "node.compute"
 	CallbackSubjectPrefix        = "node.orchestrator"
 	ManagementSubjectPrefix      = "node.management"
+	ExecInputSubjectPrefix       = "node.exec"
 
 	AskForBid       = "AskForBid/v1"
 	BidAccepted     = "BidAccepted/v1"
 	BidRejected     = "BidRejected/v1"
 	CancelExecution = "CancelExecution/v1"
 	ExecutionLogs   = "ExecutionLogs/v1"
+	Exec            = "Exec/v1"
 
 	OnBidComplete    = "OnBidComplete/v1"
 	OnRunComplete    = "OnRunComplete/v1"
 	OnCancelComplete = "OnCancelComplete/v1"
 	OnComputeFailure = "OnComputeFailure/v1"
+	OnCheckpoint     = "OnCheckpoint/v1"
 
 	RegisterNode    = "RegisterNode/v1"
 	UpdateNodeInfo  = "UpdateNodeInfo/v1"
@@ -39,6 +42,11 @@ func callbackSubscribeSubject(nodeID string) string {
 	return fmt.Sprintf("%s.%s.>", CallbackSubjectPrefix, nodeID)
 }
 
+// execInputSubject returns a unique subject for the input of an exec session on the given node
+func execInputSubject(nodeID string, sessionID string) string {
+	return fmt.Sprintf("%s.%s.%s", ExecInputSubjectPrefix, nodeID, sessionID)
+}
+
 func managementPublishSubject(nodeID string, method string) string {
 	return fmt.Sprintf("%s.%s.%s", ManagementSubjectPrefix, nodeID, method)
 }
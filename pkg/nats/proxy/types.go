@@ -1,11 +1,29 @@
 package proxy
 
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
 type BaseRequest[T any] struct {
 	TargetNodeID string
 	Method       string
 	Body         T
 }
 
+// ExecRequest is a compute.ExecRequest along with the subject the proxy publishes the input of the session to
+type ExecRequest struct {
+	compute.ExecRequest
+	InputSubject string
+}
+
+// ExecInput is a message published by the proxy to the input subject of an exec session.
+// Detach is set when the client detached from the session, and no more input will be published.
+type ExecInput struct {
+	Input  models.ExecInput
+	Detach bool
+}
+
 // ComputeEndpoint return the compute endpoint for the base request.
 func (r *BaseRequest[T]) ComputeEndpoint() string {
 	return computeEndpointPublishSubject(r.TargetNodeID, r.Method)
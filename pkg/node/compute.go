@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"time"
+
+	"github.com/dustin/go-humanize"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/resource"
@@ -12,10 +15,12 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity/disk"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/execstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/sensors"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	pkgconfig "github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	executor_util "github.com/bacalhau-project/bacalhau/pkg/executor/util"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
@@ -25,6 +30,7 @@ import (
 	compute_endpoint "github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/cache"
 	repo_storage "github.com/bacalhau-project/bacalhau/pkg/storage/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
@@ -63,6 +69,12 @@ func NewComputeNode(
 ) (*Compute, error) {
 	executionStore := config.ExecutionStore
 
+	// inputs fetched by executions are cached and shared with later executions on this node
+	storages, inputCache, err := newInputCache(config.InputCache, storages)
+	if err != nil {
+		return nil, err
+	}
+
 	// executor/backend
 	runningCapacityTracker := capacity.NewLocalTracker(capacity.LocalTrackerParams{
 		MaxCapacity: config.TotalResourceLimits,
@@ -75,6 +87,10 @@ func NewComputeNode(
 	if err != nil {
 		return nil, err
 	}
+
+	// secrets of executions received from the requester, shared by the components that run and stream them
+	executionSecrets := compute.NewExecutionSecrets()
+
 	baseExecutor := compute.NewBaseExecutor(compute.BaseExecutorParams{
 		ID:                     nodeID,
 		Callback:               computeCallback,
@@ -85,6 +101,7 @@ func NewComputeNode(
 		Publishers:             publishers,
 		FailureInjectionConfig: config.FailureInjectionConfig,
 		ResultsPath:            *resultsPath,
+		Secrets:                executionSecrets,
 	})
 
 	bufferRunner := compute.NewExecutorBuffer(compute.ExecutorBufferParams{
@@ -129,6 +146,14 @@ func NewComputeNode(
 		ExecutionStore: executionStore,
 		Executors:      executors,
 		Buffer:         config.LogStreamBufferSize,
+		Secrets:        executionSecrets,
+	})
+
+	// exec server
+	execServer := execstream.NewServer(execstream.ServerParams{
+		ExecutionStore: executionStore,
+		Executors:      executors,
+		Buffer:         config.LogStreamBufferSize,
 	})
 
 	// node info
@@ -139,6 +164,7 @@ func NewComputeNode(
 		CapacityTracker:    runningCapacityTracker,
 		ExecutorBuffer:     bufferRunner,
 		MaxJobRequirements: config.JobResourceLimits,
+		InputCache:         inputCache,
 	})
 
 	bidder := NewBidder(config,
@@ -153,6 +179,7 @@ func NewComputeNode(
 		bufferRunner,
 		apiServer,
 		capacityCalculator,
+		executionSecrets,
 	)
 	baseEndpoint := compute.NewBaseEndpoint(compute.BaseEndpointParams{
 		ID:              nodeID,
@@ -161,6 +188,8 @@ func NewComputeNode(
 		Bidder:          bidder,
 		Executor:        bufferRunner,
 		LogServer:       logserver,
+		ExecServer:      execServer,
+		Secrets:         executionSecrets,
 	})
 
 	// register debug info providers for the /debug endpoint
@@ -263,6 +292,7 @@ func NewBidder(
 	bufferRunner *compute.ExecutorBuffer,
 	apiServer *publicapi.Server,
 	calculator capacity.UsageCalculator,
+	secrets *compute.ExecutionSecrets,
 ) compute.Bidder {
 	var semanticBidStrats []bidstrategy.SemanticBidStrategy
 	if config.BidSemanticStrategy == nil {
@@ -324,5 +354,28 @@ func NewBidder(
 			return apiServer.GetURI().JoinPath("/api/v1/compute/approve")
 		},
 		UsageCalculator: calculator,
+		Secrets:         secrets,
+	})
+}
+
+// newInputCache wraps the storages of the cached source types with an input cache when it is enabled,
+// and returns the cache, or nil if inputs are not cached.
+func newInputCache(
+	config types.InputCacheConfig, storages storage.StorageProvider) (storage.StorageProvider, *cache.InputCache, error) {
+	if !config.Enabled {
+		return storages, nil, nil
+	}
+	maxSize, err := humanize.ParseBytes(config.MaxSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid input cache max size %q: %w", config.MaxSize, err)
+	}
+	inputCache, err := cache.NewInputCache(cache.InputCacheParams{
+		Path:    config.Path,
+		MaxSize: maxSize,
+		TTL:     time.Duration(config.TTL),
 	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create input cache: %w", err)
+	}
+	return cache.NewProvider(storages, inputCache, config.Sources), inputCache, nil
 }
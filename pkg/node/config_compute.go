@@ -77,6 +77,8 @@ type ComputeConfigParams struct {
 	LocalPublisher types.LocalPublisherConfig
 
 	ControlPlaneSettings types.ComputeControlPlaneConfig
+
+	InputCache types.InputCacheConfig
 }
 
 type ComputeConfig struct {
@@ -123,6 +125,8 @@ type ComputeConfig struct {
 	LocalPublisher types.LocalPublisherConfig
 
 	ControlPlaneSettings types.ComputeControlPlaneConfig
+
+	InputCache types.InputCacheConfig
 }
 
 func NewComputeConfigWithDefaults() (ComputeConfig, error) {
@@ -223,6 +227,7 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 		ExecutionStore:               params.ExecutionStore,
 		LocalPublisher:               params.LocalPublisher,
 		ControlPlaneSettings:         params.ControlPlaneSettings,
+		InputCache:                   params.InputCache,
 	}
 
 	if err := validateConfig(config, physicalResources); err != nil {
@@ -33,6 +33,12 @@ type RequesterConfigParams struct {
 
 	RetryStrategy orchestrator.RetryStrategy
 
+	// preemption of lower priority executions by higher priority jobs
+	Preemption orchestrator.PreemptionPolicy
+
+	// retention and archival of terminal jobs
+	JobRetention orchestrator.RetentionPolicy
+
 	// evaluation broker config
 	EvalBrokerVisibilityTimeout    time.Duration
 	EvalBrokerInitialRetryDelay    time.Duration
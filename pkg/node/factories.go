@@ -11,12 +11,15 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	executor_util "github.com/bacalhau-project/bacalhau/pkg/executor/util"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/plugin"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	publisher_grpc "github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc"
 	publisher_util "github.com/bacalhau-project/bacalhau/pkg/publisher/util"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	storage_grpc "github.com/bacalhau-project/bacalhau/pkg/storage/plugins/grpc"
 )
 
 // Interfaces to inject dependencies into the stack
@@ -62,6 +65,21 @@ func NewStandardStorageProvidersFactory() StorageProvidersFactory {
 		if err != nil {
 			return nil, err
 		}
+
+		if len(nodeConfig.Plugins.Storages) > 0 {
+			plugins := plugin.Configs(config.GetExecutorPluginsPath(),
+				storage_grpc.PluginBinaryPrefix, storage_grpc.HandshakeConfig, nodeConfig.Plugins.Storages)
+			pluginProvider, err := executor_util.NewPluginStorageProvider(
+				ctx,
+				nodeConfig.CleanupManager,
+				executor_util.PluginStorageOptions{Plugins: plugins},
+			)
+			if err != nil {
+				return nil, err
+			}
+			// built-in storages take precedence over plugins of the same name
+			pr = &provider.ChainedProvider[storage.Storage]{Providers: []storage.StorageProvider{pr, pluginProvider}}
+		}
 		return provider.NewConfiguredProvider(pr, nodeConfig.DisabledFeatures.Storages), err
 	})
 }
@@ -130,6 +148,21 @@ func NewStandardPublishersFactory() PublishersFactory {
 			if err != nil {
 				return nil, err
 			}
+
+			if len(nodeConfig.Plugins.Publishers) > 0 {
+				plugins := plugin.Configs(config.GetExecutorPluginsPath(),
+					publisher_grpc.PluginBinaryPrefix, publisher_grpc.HandshakeConfig, nodeConfig.Plugins.Publishers)
+				pluginProvider, err := publisher_util.NewPluginPublisherProvider(
+					ctx,
+					nodeConfig.CleanupManager,
+					publisher_util.PluginPublisherOptions{Plugins: plugins},
+				)
+				if err != nil {
+					return nil, err
+				}
+				// built-in publishers take precedence over plugins of the same name
+				pr = &provider.ChainedProvider[publisher.Publisher]{Providers: []publisher.PublisherProvider{pr, pluginProvider}}
+			}
 			return provider.NewConfiguredProvider(pr, nodeConfig.DisabledFeatures.Publishers), err
 		})
 }
@@ -3,6 +3,8 @@ package manager
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
@@ -131,6 +133,8 @@ func (n *NodeManager) UpdateInfo(ctx context.Context, request requests.UpdateInf
 		Membership: existing.Membership,
 		// TODO can we assume the node is connected here?
 		Connection: models.NodeStates.CONNECTED,
+		// the node doesn't know it is cordoned, so keep the existing cordon
+		Cordon: existing.Cordon,
 	}); err != nil {
 		return nil, errors.Wrap(err, "failed to save nodestate during node registration")
 	}
@@ -168,6 +172,12 @@ func (n *NodeManager) FindPeer(ctx context.Context, peerID peer.ID) (peer.AddrIn
 }
 
 func (n *NodeManager) Add(ctx context.Context, nodeInfo models.NodeState) error {
+	// node states published by the nodes themselves don't carry the cordon, so keep the existing one
+	if nodeInfo.Cordon == nil {
+		if existing, err := n.store.Get(ctx, nodeInfo.Info.NodeID); err == nil {
+			nodeInfo.Cordon = existing.Cordon
+		}
+	}
 	return n.store.Add(ctx, nodeInfo)
 }
 
@@ -281,5 +291,79 @@ func (n *NodeManager) DeleteAction(ctx context.Context, nodeID string, reason st
 	return true, ""
 }
 
+// CordonAction is used to stop placing new executions on a node, along with a specific
+// reason for the cordon (for audit). Executions already running on the node are not affected.
+// The return values denote success and any failure of the operation as a human readable string.
+func (n *NodeManager) CordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if state.IsCordoned() {
+		return false, fmt.Sprintf("node already %s", strings.ToLower(state.Cordon.String()))
+	}
+
+	state.Cordon = &models.NodeCordon{Reason: reason, CreateTime: time.Now().UTC()}
+	log.Ctx(ctx).Info().Str("reason", reason).Msgf("node %s cordoned", nodeID)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node cordon"
+	}
+
+	return true, ""
+}
+
+// DrainAction is used to cordon a node and migrate its executions to other nodes, along with
+// a specific reason for the drain (for audit). Executions still running on the node after the
+// timeout are stopped. The return values denote success and any failure of the operation as a
+// human readable string.
+func (n *NodeManager) DrainAction(ctx context.Context, nodeID string, reason string, timeout time.Duration) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if state.IsDraining() {
+		return false, "node already draining"
+	}
+
+	if timeout <= 0 {
+		timeout = models.DefaultNodeDrainTimeout
+	}
+	now := time.Now().UTC()
+	state.Cordon = &models.NodeCordon{Reason: reason, CreateTime: now, Drain: true, DrainDeadline: now.Add(timeout)}
+	log.Ctx(ctx).Info().Str("reason", reason).Msgf("node %s draining within %s", nodeID, timeout)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node drain"
+	}
+
+	return true, ""
+}
+
+// UncordonAction is used to allow placing new executions on a cordoned or drained node again,
+// along with a specific reason (for audit). The return values denote success and any failure
+// of the operation as a human readable string.
+func (n *NodeManager) UncordonAction(ctx context.Context, nodeID string, reason string) (bool, string) {
+	state, err := n.store.GetByPrefix(ctx, nodeID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if !state.IsCordoned() {
+		return false, "node not cordoned"
+	}
+
+	state.Cordon = nil
+	log.Ctx(ctx).Info().Str("reason", reason).Msgf("node %s uncordoned", nodeID)
+
+	if err := n.store.Add(ctx, state); err != nil {
+		return false, "failed to save nodestate during node uncordon"
+	}
+
+	return true, ""
+}
+
 var _ compute.ManagementEndpoint = (*NodeManager)(nil)
 var _ routing.NodeInfoStore = (*NodeManager)(nil)
@@ -57,6 +57,7 @@ type NodeConfig struct {
 	RequesterTLSKeyFile         string
 	RequesterSelfSign           bool
 	DisabledFeatures            FeatureConfig
+	Plugins                     types.PluginConfig
 	ComputeConfig               ComputeConfig
 	RequesterNodeConfig         RequesterConfig
 	APIServerConfig             publicapi.Config
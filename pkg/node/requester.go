@@ -6,6 +6,8 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authn"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
 	"github.com/bacalhau-project/bacalhau/pkg/job"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -73,6 +75,16 @@ func NewRequesterNode(
 
 	jobStore := requesterConfig.JobStore
 
+	// secrets are encrypted at rest with a key derived from the private key of the node
+	privateKey, err := config.GetClientPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	secretManager, err := orchestrator.NewSecretManager(jobStore, orchestrator.SecretKeyFromPrivateKey(privateKey))
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO(forrest) [simplify]: given the current state of the code this interface obfuscates what is happening here,
 	// there isn't any "node discovery" happening here, we are simply listing a node store.
 	// The todo here is to simply pass a node store where it's needed instead of this chain wrapping a discoverer wrapping
@@ -93,18 +105,23 @@ func NewRequesterNode(
 		ranking.NewMaxUsageNodeRanker(),
 		ranking.NewMinVersionNodeRanker(ranking.MinVersionNodeRankerParams{MinVersion: requesterConfig.MinBacalhauVersion}),
 		ranking.NewPreviousExecutionsNodeRanker(ranking.PreviousExecutionsNodeRankerParams{JobStore: jobStore}),
+		// rankers that prefer some nodes over others
+		ranking.NewInputLocalityNodeRanker(),
 		// arbitrary rankers
 		ranking.NewRandomNodeRanker(ranking.RandomNodeRankerParams{
 			RandomnessRange: requesterConfig.NodeRankRandomnessRange,
 		}),
 	)
 
-	// evaluation broker
-	evalBroker, err := evaluation.NewInMemoryBroker(evaluation.InMemoryBrokerParams{
-		VisibilityTimeout:    requesterConfig.EvalBrokerVisibilityTimeout,
-		InitialRetryDelay:    requesterConfig.EvalBrokerInitialRetryDelay,
-		SubsequentRetryDelay: requesterConfig.EvalBrokerSubsequentRetryDelay,
-		MaxReceiveCount:      requesterConfig.EvalBrokerMaxRetryCount,
+	// evaluation broker, persisting evaluations in the job store to survive restarts
+	evalBroker, err := evaluation.NewPersistentBroker(evaluation.PersistentBrokerParams{
+		InMemoryBrokerParams: evaluation.InMemoryBrokerParams{
+			VisibilityTimeout:    requesterConfig.EvalBrokerVisibilityTimeout,
+			InitialRetryDelay:    requesterConfig.EvalBrokerInitialRetryDelay,
+			SubsequentRetryDelay: requesterConfig.EvalBrokerSubsequentRetryDelay,
+			MaxReceiveCount:      requesterConfig.EvalBrokerMaxRetryCount,
+		},
+		JobStore: jobStore,
 	})
 	if err != nil {
 		return nil, err
@@ -120,9 +137,23 @@ func NewRequesterNode(
 		// planner that forwards the desired state to the compute nodes,
 		// and updates the observed state if the compute node accepts the desired state
 		planner.NewComputeForwarder(planner.ComputeForwarderParams{
-			ID:             nodeID,
-			ComputeService: computeProxy,
-			JobStore:       jobStore,
+			ID:               nodeID,
+			ComputeService:   computeProxy,
+			JobStore:         jobStore,
+			Secrets:          secretManager,
+			EvaluationBroker: evalBroker,
+		}),
+
+		// planner that enqueues follow-up evaluations created by the scheduler, such as delayed retries
+		planner.NewEvaluationEnqueuer(planner.EvaluationEnqueuerParams{
+			JobStore:         jobStore,
+			EvaluationBroker: evalBroker,
+		}),
+
+		// planner that enqueues evaluations for jobs depending on a job that completed or failed
+		planner.NewDependencyNotifier(planner.DependencyNotifierParams{
+			JobStore:         jobStore,
+			EvaluationBroker: evalBroker,
 		}),
 
 		// planner that publishes events on job completion or failure
@@ -162,6 +193,7 @@ func NewRequesterNode(
 		Planner:       planners,
 		NodeSelector:  nodeSelector,
 		RetryStrategy: retryStrategy,
+		Preemption:    requesterConfig.Preemption,
 	})
 	schedulerProvider := orchestrator.NewMappedSchedulerProvider(map[string]orchestrator.Scheduler{
 		models.JobTypeBatch:   batchServiceJobScheduler,
@@ -176,6 +208,10 @@ func NewRequesterNode(
 			Planner:      planners,
 			NodeSelector: nodeSelector,
 		}),
+		models.JobTypeScheduled: scheduler.NewScheduledJobScheduler(scheduler.ScheduledJobSchedulerParams{
+			JobStore: jobStore,
+			Planner:  planners,
+		}),
 	})
 
 	workers := make([]*orchestrator.Worker, 0, requesterConfig.WorkerCount)
@@ -243,6 +279,14 @@ func NewRequesterNode(
 		}
 	}
 
+	// pin the docker images of cacheable jobs by digest, once defaults are applied, so that their
+	// result cache key changes when their image tags are moved
+	dockerClient, err := docker.NewDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	jobTransformers = append(jobTransformers, transformer.NewImageDigestPinner(dockerClient))
+
 	endpointV2 := orchestrator.NewBaseEndpoint(&orchestrator.BaseEndpointParams{
 		ID:                nodeID,
 		EvaluationBroker:  evalBroker,
@@ -259,6 +303,7 @@ func NewRequesterNode(
 		JobStore:         jobStore,
 		Interval:         requesterConfig.HousekeepingBackgroundTaskInterval,
 		TimeoutBuffer:    requesterConfig.HousekeepingTimeoutBuffer,
+		Retention:        requesterConfig.JobRetention,
 	})
 	if err != nil {
 		return nil, err
@@ -283,7 +328,9 @@ func NewRequesterNode(
 		Router:       apiServer.Router,
 		Orchestrator: endpointV2,
 		JobStore:     jobStore,
+		JobArchive:   requesterConfig.JobRetention.Archive,
 		NodeManager:  nodeManager,
+		Secrets:      secretManager,
 	})
 
 	auth_endpoint.BindEndpoint(ctx, apiServer.Router, authnProvider)
@@ -13,10 +13,12 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/transformer"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/encryption"
 	"github.com/bacalhau-project/bacalhau/pkg/translation"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"sigs.k8s.io/yaml"
 )
 
@@ -69,6 +71,29 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, request *SubmitJobRequest)
 		return nil, err
 	}
 
+	// validate encryption once the default publisher is applied, as results are encrypted by compute nodes
+	if err := encryption.ValidateJob(*job); err != nil {
+		return nil, err
+	}
+
+	// make sure upstream jobs exist before accepting a job that depends on them,
+	// and resolve short IDs to full job IDs
+	for _, dep := range job.DependsOn {
+		upstream, err := e.store.GetJob(ctx, dep.JobID)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to find upstream job %s", dep.JobID))
+		}
+		dep.JobID = upstream.ID
+	}
+
+	// make sure the secrets referenced by the job exist in its namespace. Their values are
+	// only resolved once the job is scheduled, and are never part of the stored job.
+	for _, name := range job.SecretRefs() {
+		if _, err := e.store.GetSecret(ctx, job.Namespace, name); err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to find secret %s referenced by job", name))
+		}
+	}
+
 	// We will only perform task translation in the orchestrator if we were provided with a provider
 	// that can give translators to perform the translation.
 	if e.taskTranslator != nil {
@@ -204,6 +229,45 @@ func (e *BaseEndpoint) StopJob(ctx context.Context, request *StopJobRequest) (St
 	}, nil
 }
 
+// DrainNode enqueues an evaluation for each active job with executions on the node, so that the schedulers
+// move or stop the executions according to the job type. The node is expected to be marked as draining already.
+func (e *BaseEndpoint) DrainNode(ctx context.Context, request *DrainNodeRequest) (DrainNodeResponse, error) {
+	executions, err := e.store.GetActiveExecutionsOnNode(ctx, request.NodeID)
+	if err != nil {
+		return DrainNodeResponse{}, fmt.Errorf("failed to retrieve executions on node %s: %w", request.NodeID, err)
+	}
+
+	var evalIDs []string
+	jobIDs := lo.Uniq(lo.Map(executions, func(execution models.Execution, _ int) string { return execution.JobID }))
+	for _, jobID := range jobIDs {
+		job, err := e.store.GetJob(ctx, jobID)
+		if err != nil {
+			return DrainNodeResponse{}, fmt.Errorf("failed to retrieve job %s: %w", jobID, err)
+		}
+		if job.IsTerminal() {
+			// the executions of the job are already being stopped
+			continue
+		}
+
+		eval := models.NewEvaluation().
+			WithJobID(job.ID).
+			WithNamespace(job.Namespace).
+			WithTriggeredBy(models.EvalTriggerNodeDrain).
+			WithType(job.Type).
+			WithPriority(job.Priority).
+			WithComment(fmt.Sprintf("drain node %s", request.NodeID)).
+			Normalize()
+		if err = e.store.CreateEvaluation(ctx, *eval); err != nil {
+			return DrainNodeResponse{}, fmt.Errorf("failed to save evaluation to drain job %s: %w", job.ID, err)
+		}
+		if err = e.evaluationBroker.Enqueue(eval); err != nil {
+			return DrainNodeResponse{}, err
+		}
+		evalIDs = append(evalIDs, eval.ID)
+	}
+	return DrainNodeResponse{EvaluationIDs: evalIDs}, nil
+}
+
 func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 	<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
@@ -258,6 +322,63 @@ func (e *BaseEndpoint) ReadLogs(ctx context.Context, request ReadLogsRequest) (
 	return e.computeProxy.ExecutionLogs(ctx, req)
 }
 
+// Exec runs a command in, or attaches to, a running execution of a job. Input received from the client is
+// forwarded to the compute node running the execution, which streams back the output of the command.
+func (e *BaseEndpoint) Exec(ctx context.Context, request ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	job, err := e.store.GetJob(ctx, request.JobID)
+	if err != nil {
+		return nil, err
+	}
+	// the namespace is checked, as it is the namespace the request was authorized for
+	if job.Namespace != request.Namespace {
+		return nil, fmt.Errorf("job %s not found in namespace %s", request.JobID, request.Namespace)
+	}
+	if !request.Attach && len(request.Command) == 0 {
+		return nil, fmt.Errorf("no command to exec in job %s", request.JobID)
+	}
+
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID: job.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var execution *models.Execution
+	var latestModifyTime int64
+	for i, exec := range executions {
+		if exec.ID == request.ExecutionID {
+			execution = &executions[i]
+			break
+		}
+		// If no specific execution is requested, track the latest running execution
+		if request.ExecutionID == "" && exec.ComputeState.StateType == models.ExecutionStateBidAccepted &&
+			exec.ModifyTime > latestModifyTime {
+			latestModifyTime = exec.ModifyTime
+			execution = &executions[i]
+		}
+	}
+	if execution == nil {
+		return nil, fmt.Errorf("unable to find running execution %s in job %s", request.ExecutionID, request.JobID)
+	}
+	if execution.ComputeState.StateType != models.ExecutionStateBidAccepted {
+		return nil, fmt.Errorf("execution %s is not running", execution.ID)
+	}
+
+	return e.computeProxy.Exec(ctx, compute.ExecRequest{
+		RoutingMetadata: compute.RoutingMetadata{
+			SourcePeerID: e.id,
+			TargetPeerID: execution.NodeID,
+		},
+		ExecutionID: execution.ID,
+		Command:     request.Command,
+		Attach:      request.Attach,
+		TTY:         request.TTY,
+		Stdin:       request.Stdin,
+	}, input)
+}
+
 // GetResults returns the results of a job
 func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsRequest) (GetResultsResponse, error) {
 	job, err := e.store.GetJob(ctx, request.JobID)
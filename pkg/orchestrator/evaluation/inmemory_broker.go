@@ -81,6 +81,11 @@ type InMemoryBroker struct {
 	// ready tracks the ready jobs by scheduler in a priority queue
 	ready map[string]ReadyEvaluations
 
+	// namespaceDequeues tracks the sequence number of the last dequeue of each namespace,
+	// so that evaluations of namespaces that were not served recently are dequeued first
+	namespaceDequeues map[string]uint64
+	dequeueSeq        uint64
+
 	// inflight is a map of evalID to an un-acknowledged evaluations
 	inflight map[string]*inflightEval
 
@@ -143,6 +148,7 @@ func NewInMemoryBroker(params InMemoryBrokerParams) (*InMemoryBroker, error) {
 		pending:              make(map[models.NamespacedID]PendingEvaluations),
 		cancelable:           []*models.Evaluation{},
 		ready:                make(map[string]ReadyEvaluations),
+		namespaceDequeues:    make(map[string]uint64),
 		inflight:             make(map[string]*inflightEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*models.Evaluation),
@@ -414,10 +420,13 @@ func (b *InMemoryBroker) scanForSchedulers(types []string) (*models.Evaluation,
 // This assumes locks are held and that this scheduler has work
 func (b *InMemoryBroker) dequeueForSched(jobType string) (*models.Evaluation, string, error) {
 	readyQueue := b.ready[jobType]
-	raw := heap.Pop(&readyQueue)
+	raw := heap.Remove(&readyQueue, b.nextFairShare(readyQueue))
 	b.ready[jobType] = readyQueue
 	eval := raw.(*models.Evaluation)
 
+	b.dequeueSeq++
+	b.namespaceDequeues[eval.Namespace] = b.dequeueSeq
+
 	// Generate a UUID for the receipt handle
 	receiptHandle := uuid.NewString()
 
@@ -449,6 +458,26 @@ func (b *InMemoryBroker) dequeueForSched(jobType string) (*models.Evaluation, st
 	return eval, receiptHandle, nil
 }
 
+// nextFairShare returns the index of the next evaluation to dequeue from the ready queue.
+// Among the evaluations with the highest priority, the one whose namespace was dequeued least
+// recently is selected, so that busy namespaces cannot starve the others. Evaluations of the
+// same namespace are dequeued in the order they were created.
+// This assumes locks are held and that the queue is not empty.
+func (b *InMemoryBroker) nextFairShare(readyQueue ReadyEvaluations) int {
+	next := 0
+	for i := 1; i < len(readyQueue); i++ {
+		eval, current := readyQueue[i], readyQueue[next]
+		if eval.Priority != readyQueue[0].Priority {
+			continue
+		}
+		evalDequeue, currentDequeue := b.namespaceDequeues[eval.Namespace], b.namespaceDequeues[current.Namespace]
+		if evalDequeue < currentDequeue || (evalDequeue == currentDequeue && eval.CreateTime < current.CreateTime) {
+			next = i
+		}
+	}
+	return next
+}
+
 // waitForSchedulers is used to wait for work on any of the scheduler or until a timeout.
 // Returns if there is work waiting potentially.
 func (b *InMemoryBroker) waitForSchedulers(types []string, timeoutCh <-chan time.Time) bool {
@@ -691,6 +720,8 @@ func (b *InMemoryBroker) flush() {
 	b.pending = make(map[models.NamespacedID]PendingEvaluations)
 	b.cancelable = []*models.Evaluation{}
 	b.ready = make(map[string]ReadyEvaluations)
+	b.namespaceDequeues = make(map[string]uint64)
+	b.dequeueSeq = 0
 	b.inflight = make(map[string]*inflightEval)
 	b.waiting = make(map[string]chan struct{})
 	b.delayHeap = collections.NewScheduledTaskHeap[*models.Evaluation]()
@@ -315,28 +315,27 @@ func (s *InMemoryBrokerTestSuite) TestSerialize_DuplicateJobID() {
 	s.Require().Equal(
 		BrokerStats{TotalReady: 2, TotalInflight: 0, TotalPending: 2, TotalCancelable: 2}, getStats())
 
-	// Dequeue should get 4th eval
+	// Dequeue should get 5th eval, as namespace-two has not been served yet
 	out, receiptHandle, err = s.broker.Dequeue(defaultSched, time.Second)
 	s.Require().NoError(err)
-	s.Require().Equal(out, eval4, "expected 4th eval")
+	s.Require().Equal(out, eval5, "expected 5th eval")
 	s.Require().Equal(
 		BrokerStats{TotalReady: 1, TotalInflight: 1, TotalPending: 2, TotalCancelable: 2}, getStats())
 
-	// Ack should clear the rest of namespace-one pending but leave
-	// namespace-two untouched
-	s.Require().NoError(s.broker.Ack(eval4.ID, receiptHandle))
+	// Ack should clear the rest of namespace-two pending but leave
+	// namespace-one untouched
+	s.Require().NoError(s.broker.Ack(eval5.ID, receiptHandle))
 	s.Require().Equal(
-		BrokerStats{TotalReady: 1, TotalInflight: 0, TotalPending: 2, TotalCancelable: 2}, getStats())
+		BrokerStats{TotalReady: 2, TotalInflight: 0, TotalPending: 0, TotalCancelable: 3}, getStats())
 
-	// Dequeue should get 5th eval
+	// Dequeue should get 4th eval, as namespace-one was served less recently
 	out, receiptHandle, err = s.broker.Dequeue(defaultSched, time.Second)
 	s.Require().NoError(err)
-	s.Require().Equal(out, eval5, "expected 5th eval")
+	s.Require().Equal(out, eval4, "expected 4th eval")
 	s.Require().Equal(
-		BrokerStats{TotalReady: 0, TotalInflight: 1, TotalPending: 2, TotalCancelable: 2}, getStats())
+		BrokerStats{TotalReady: 1, TotalInflight: 1, TotalPending: 0, TotalCancelable: 3}, getStats())
 
-	// Ack should clear remaining namespace-two pending evals
-	s.Require().NoError(s.broker.Ack(eval5.ID, receiptHandle))
+	s.Require().NoError(s.broker.Ack(eval4.ID, receiptHandle))
 	s.Require().Equal(
 		BrokerStats{TotalReady: 1, TotalInflight: 0, TotalPending: 0, TotalCancelable: 3}, getStats())
 
@@ -524,6 +523,35 @@ func (s *InMemoryBrokerTestSuite) TestDequeue_Fairness() {
 	s.Require().InDeltaf(typeBatchCounter, typeServiceCounter, 250, "expected schedulers to be fair")
 }
 
+// Ensure namespaces that were not served recently are dequeued first at fixed priority
+func (s *InMemoryBrokerTestSuite) TestDequeue_NamespaceFairShare() {
+	s.broker.SetEnabled(true)
+
+	newEval := func(createTime int64, namespace string, priority int) *models.Evaluation {
+		eval := mock.Eval()
+		eval.Namespace = namespace
+		eval.Priority = priority
+		eval.CreateTime = createTime
+		eval.ModifyTime = createTime
+		s.Require().NoError(s.broker.Enqueue(eval))
+		return eval
+	}
+
+	busy1 := newEval(1, "busy", 50)
+	busy2 := newEval(2, "busy", 50)
+	busy3 := newEval(3, "busy", 50)
+	quiet1 := newEval(4, "quiet", 50)
+	quiet2 := newEval(5, "quiet", 50)
+	urgent := newEval(6, "busy", 100)
+
+	// higher priority evaluations are still dequeued first
+	for _, expected := range []*models.Evaluation{urgent, quiet1, busy1, quiet2, busy2, busy3} {
+		out, _, err := s.broker.Dequeue(defaultSched, time.Second)
+		s.Require().NoError(err)
+		s.Require().Equal(expected, out, "expected eval with create time %d", expected.CreateTime)
+	}
+}
+
 // Ensure we get unblocked
 func (s *InMemoryBrokerTestSuite) TestDequeue_Blocked() {
 	s.broker.SetEnabled(true)
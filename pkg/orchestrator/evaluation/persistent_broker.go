@@ -0,0 +1,146 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// compile-time check to ensure type implements the models.EvaluationBroker interface
+var _ orchestrator.EvaluationBroker = &PersistentBroker{}
+
+type PersistentBrokerParams struct {
+	InMemoryBrokerParams
+	JobStore jobstore.Store
+}
+
+// PersistentBroker is an evaluation broker backed by the job store, so that pending and
+// in-flight evaluations survive restarts of the requester node.
+// Queuing, visibility timeouts and the single in-flight evaluation per job are delegated to
+// an in-memory broker, while the job store is the durable record of the evaluations that have
+// not been acknowledged yet:
+//   - evaluations are persisted on enqueue, if they were not persisted already.
+//   - acknowledged evaluations are marked as complete, and evaluations superseded by a more
+//     recent evaluation of the same job are marked as cancelled.
+//   - when the broker is enabled, it re-hydrates from the pending evaluations in the job store.
+//
+// Evaluations that are moved to the dead letter queue are left pending in the job store, and are
+// delivered again after a restart, which preserves the at-least-once delivery semantics.
+type PersistentBroker struct {
+	*InMemoryBroker
+	jobStore jobstore.Store
+}
+
+// NewPersistentBroker creates a new evaluation broker backed by the job store.
+func NewPersistentBroker(params PersistentBrokerParams) (*PersistentBroker, error) {
+	if params.JobStore == nil {
+		return nil, errors.New("job store is required")
+	}
+	inMemoryBroker, err := NewInMemoryBroker(params.InMemoryBrokerParams)
+	if err != nil {
+		return nil, err
+	}
+	return &PersistentBroker{
+		InMemoryBroker: inMemoryBroker,
+		jobStore:       params.JobStore,
+	}, nil
+}
+
+// SetEnabled is used to control if the broker is enabled.
+// Enabling the broker re-hydrates it from the pending evaluations in the job store.
+func (b *PersistentBroker) SetEnabled(enabled bool) {
+	prevEnabled := b.Enabled()
+	b.InMemoryBroker.SetEnabled(enabled)
+	if !prevEnabled && enabled {
+		if err := b.restore(context.Background()); err != nil {
+			log.Error().Err(err).Msg("failed to restore pending evaluations from the job store")
+		}
+	}
+}
+
+// restore enqueues all pending evaluations persisted in the job store.
+func (b *PersistentBroker) restore(ctx context.Context) error {
+	evals, err := b.jobStore.GetPendingEvaluations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve pending evaluations: %w", err)
+	}
+	if len(evals) == 0 {
+		return nil
+	}
+	toEnqueue := make(map[*models.Evaluation]string, len(evals))
+	for i := range evals {
+		toEnqueue[&evals[i]] = ""
+	}
+	log.Ctx(ctx).Info().Msgf("restoring %d pending evaluations from the job store", len(evals))
+	return b.InMemoryBroker.EnqueueAll(toEnqueue)
+}
+
+func (b *PersistentBroker) Enqueue(evaluation *models.Evaluation) error {
+	if err := b.persist(context.Background(), evaluation); err != nil {
+		return err
+	}
+	return b.InMemoryBroker.Enqueue(evaluation)
+}
+
+func (b *PersistentBroker) EnqueueAll(evals map[*models.Evaluation]string) error {
+	for evaluation := range evals {
+		if err := b.persist(context.Background(), evaluation); err != nil {
+			return err
+		}
+	}
+	return b.InMemoryBroker.EnqueueAll(evals)
+}
+
+// persist creates the evaluation in the job store if it does not exist yet.
+func (b *PersistentBroker) persist(ctx context.Context, evaluation *models.Evaluation) error {
+	_, err := b.jobStore.GetEvaluation(ctx, evaluation.ID)
+	if err == nil {
+		return nil
+	}
+	var notFound *bacerrors.EvaluationNotFound
+	if !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to retrieve evaluation %s: %w", evaluation.ID, err)
+	}
+	if err = b.jobStore.CreateEvaluation(ctx, *evaluation); err != nil {
+		return fmt.Errorf("failed to persist evaluation %s: %w", evaluation.ID, err)
+	}
+	return nil
+}
+
+func (b *PersistentBroker) Ack(evalID string, receiptHandle string) error {
+	// an evaluation that is re-enqueued on ack is still pending
+	b.l.RLock()
+	requeuedEval, requeued := b.requeue[receiptHandle]
+	requeued = requeued && requeuedEval.ID == evalID
+	b.l.RUnlock()
+
+	if err := b.InMemoryBroker.Ack(evalID, receiptHandle); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if !requeued {
+		b.updateStatus(ctx, evalID, models.EvalStatusComplete)
+	}
+	// pending evaluations of the same job that were superseded by a more recent one
+	for _, cancelled := range b.Cancelable(math.MaxInt) {
+		b.updateStatus(ctx, cancelled.ID, models.EvalStatusCancelled)
+	}
+	return nil
+}
+
+// updateStatus updates the status of the evaluation in the job store. Failures are only logged,
+// as the evaluation would just be delivered again after a restart.
+func (b *PersistentBroker) updateStatus(ctx context.Context, evalID string, status string) {
+	if err := b.jobStore.UpdateEvaluationStatus(ctx, evalID, status); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to update status of evaluation %s to %s", evalID, status)
+	}
+}
@@ -0,0 +1,160 @@
+//go:build unit || !integration
+
+package evaluation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type PersistentBrokerTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	jobStore *boltjobstore.BoltJobStore
+	broker   *PersistentBroker
+	job      *models.Job
+}
+
+func (s *PersistentBrokerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	jobStore, err := boltjobstore.NewBoltJobStore(filepath.Join(s.T().TempDir(), "jobs.db"))
+	s.Require().NoError(err)
+	s.jobStore = jobStore
+
+	s.job = mock.Job()
+	s.Require().NoError(s.jobStore.CreateJob(s.ctx, *s.job, models.Event{}))
+	s.broker = s.newBroker()
+}
+
+func (s *PersistentBrokerTestSuite) TearDownTest() {
+	s.broker.SetEnabled(false)
+	s.Require().NoError(s.jobStore.Close(s.ctx))
+}
+
+func TestPersistentBrokerTestSuite(t *testing.T) {
+	suite.Run(t, new(PersistentBrokerTestSuite))
+}
+
+func (s *PersistentBrokerTestSuite) newBroker() *PersistentBroker {
+	broker, err := NewPersistentBroker(PersistentBrokerParams{
+		InMemoryBrokerParams: defaultBrokerParams,
+		JobStore:             s.jobStore,
+	})
+	s.Require().NoError(err)
+	return broker
+}
+
+func (s *PersistentBrokerTestSuite) newEval() *models.Evaluation {
+	eval := mock.Eval()
+	eval.JobID = s.job.ID
+	eval.Namespace = s.job.Namespace
+	return eval
+}
+
+func (s *PersistentBrokerTestSuite) TestEnqueue_ShouldPersistEvaluation() {
+	s.broker.SetEnabled(true)
+	eval := s.newEval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+
+	persisted, err := s.jobStore.GetEvaluation(s.ctx, eval.ID)
+	s.Require().NoError(err)
+	s.Equal(models.EvalStatusPending, persisted.Status)
+
+	// enqueueing an evaluation that is already persisted is a no-op
+	s.Require().NoError(s.broker.Enqueue(eval))
+}
+
+func (s *PersistentBrokerTestSuite) TestAck_ShouldCompleteEvaluation() {
+	s.broker.SetEnabled(true)
+	eval := s.newEval()
+	s.Require().NoError(s.broker.Enqueue(eval))
+
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().Equal(eval.ID, out.ID)
+
+	// nacked evaluations are still pending
+	s.Require().NoError(s.broker.Nack(eval.ID, receiptHandle))
+	s.assertStatus(eval.ID, models.EvalStatusPending)
+
+	out, receiptHandle, err = s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().Equal(eval.ID, out.ID)
+	s.Require().NoError(s.broker.Ack(eval.ID, receiptHandle))
+	s.assertStatus(eval.ID, models.EvalStatusComplete)
+}
+
+func (s *PersistentBrokerTestSuite) TestAck_ShouldCancelSupersededEvaluations() {
+	s.broker.SetEnabled(true)
+	eval1, eval2, eval3 := s.newEval(), s.newEval(), s.newEval()
+	s.Require().NoError(s.broker.Enqueue(eval1))
+	s.Require().NoError(s.broker.Enqueue(eval2))
+	s.Require().NoError(s.broker.Enqueue(eval3))
+
+	// only a single evaluation of the job is ready, and the latest pending one
+	// supersedes the others once it is acked
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().Equal(eval1.ID, out.ID)
+	s.Require().NoError(s.broker.Ack(eval1.ID, receiptHandle))
+
+	s.assertStatus(eval1.ID, models.EvalStatusComplete)
+	pending, err := s.jobStore.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+
+	out, receiptHandle, err = s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().Equal(pending[0].ID, out.ID)
+	s.Require().NoError(s.broker.Ack(out.ID, receiptHandle))
+
+	pending, err = s.jobStore.GetPendingEvaluations(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(pending)
+}
+
+func (s *PersistentBrokerTestSuite) TestSetEnabled_ShouldRestorePendingEvaluations() {
+	s.broker.SetEnabled(true)
+	inflight := s.newEval()
+	s.Require().NoError(s.broker.Enqueue(inflight))
+	_, _, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+
+	delayed := mock.Eval()
+	delayed.Type = models.JobTypeService
+	delayed.WaitUntil = time.Now().Add(time.Hour)
+	otherJob := mock.Job()
+	otherJob.Type = models.JobTypeService
+	s.Require().NoError(s.jobStore.CreateJob(s.ctx, *otherJob, models.Event{}))
+	delayed.JobID = otherJob.ID
+	s.Require().NoError(s.broker.Enqueue(delayed))
+
+	// simulate a restart of the requester node with an evaluation in flight
+	s.broker.SetEnabled(false)
+	s.broker = s.newBroker()
+	s.broker.SetEnabled(true)
+
+	stats := s.broker.Stats()
+	s.Equal(1, stats.TotalReady)
+	s.Equal(1, stats.TotalWaiting)
+	out, receiptHandle, err := s.broker.Dequeue(defaultSched, time.Second)
+	s.Require().NoError(err)
+	s.Require().Equal(inflight.ID, out.ID)
+	s.Require().NoError(s.broker.Ack(out.ID, receiptHandle))
+	s.assertStatus(inflight.ID, models.EvalStatusComplete)
+	s.assertStatus(delayed.ID, models.EvalStatusPending)
+}
+
+func (s *PersistentBrokerTestSuite) assertStatus(evalID string, status string) {
+	persisted, err := s.jobStore.GetEvaluation(s.ctx, evalID)
+	s.Require().NoError(err)
+	s.Equal(status, persisted.Status)
+}
@@ -2,15 +2,18 @@ package orchestrator
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
 
 const (
-	EventTopicJobSubmission    models.EventTopic = "Submission"
-	EventTopicJobScheduling    models.EventTopic = "Scheduling"
-	EventTopicExecutionTimeout models.EventTopic = "Exec Timeout"
+	EventTopicJobSubmission       models.EventTopic = "Submission"
+	EventTopicJobScheduling       models.EventTopic = "Scheduling"
+	EventTopicExecutionTimeout    models.EventTopic = "Exec Timeout"
+	EventTopicExecutionCheckpoint models.EventTopic = "Checkpoint"
+	EventTopicExecutionEgress     models.EventTopic = "Egress"
 )
 
 const (
@@ -18,13 +21,30 @@ const (
 	jobTranslatedMessage       = "Job tasks translated to new type"
 	jobStopRequestedMessage    = "Job requested to stop before completion"
 	jobExhaustedRetriesMessage = "Job failed because it has been retried too many times"
+	jobDependencyFailedMessage = "Job failed because one of its dependencies did not complete successfully"
+	jobNotRetryableMessage     = "Job failed because its retry policy does not retry this type of failure"
+	jobRetryAttemptMessage     = "Retrying job"
+	jobRetryDelayedMessage     = "Job retry scheduled"
+	jobScheduledMessage        = "Job scheduled"
+	jobRunStartedMessage       = "Scheduled job started a new run"
+	jobRunSkippedMessage       = "Scheduled run skipped because previous runs are still active"
+	jobSpawnedMessage          = "Job submitted by scheduled job"
+	jobReplacedMessage         = "Job stopped because it was replaced by a newer run of its scheduled job"
+	jobQuotaExceededMessage    = "Job executions held back because its namespace quota has been reached"
+	jobResultCacheHitMessage   = "Job completed with the cached results of an identical job"
 
 	execStoppedByJobStopMessage          = "Execution stop requested because job has been stopped"
 	execStoppedByNodeUnhealthyMessage    = "Execution stop requested because node has disappeared"
 	execStoppedByNodeRejectedMessage     = "Execution stop requested because node has been rejected"
 	execStoppedByOversubscriptionMessage = "Execution stop requested because there are more executions than needed"
+	execStoppedByPreemptionMessage       = "Execution stop requested to make room for a higher priority job"
+	execStoppedByNodeDrainMessage        = "Execution stop requested because node is being drained"
 	execRejectedByNodeMessage            = "Node responded to execution run request"
 	execFailedMessage                    = "Execution did not complete successfully"
+	execSecretsUnresolvedMessage         = "Execution failed because the secrets of its job could not be resolved"
+	execSecretsUnresolvedHint            = "Check that the secrets referenced by the job exist in its namespace"
+	execCheckpointedMessage              = "Execution checkpointed"
+	execEgressMessage                    = "Execution completed with"
 
 	executionTimeoutMessage = "Execution timed out"
 	executionTimeoutHint    = "Try increasing the task timeout or reducing the task size"
@@ -60,22 +80,178 @@ func JobExhaustedRetriesEvent() models.Event {
 	return event(EventTopicJobScheduling, jobExhaustedRetriesMessage, map[string]string{})
 }
 
+// JobExhaustedRetryPolicyEvent is emitted when a job has no attempts left according to its retry policy.
+func JobExhaustedRetryPolicyEvent(failedAttempts int, policy *models.RetryPolicy) models.Event {
+	return event(EventTopicJobScheduling, jobExhaustedRetriesMessage, map[string]string{
+		"FailedAttempts": fmt.Sprint(failedAttempts),
+		"MaxAttempts":    fmt.Sprint(policy.MaxAttempts),
+	})
+}
+
+func JobNotRetryableEvent(failureClass string) models.Event {
+	return event(EventTopicJobScheduling, jobNotRetryableMessage, map[string]string{
+		"FailureClass": failureClass,
+	})
+}
+
+// JobRetryAttemptEvent is emitted when new executions are created to retry a job.
+func JobRetryAttemptEvent(attempt int, policy *models.RetryPolicy) models.Event {
+	return event(EventTopicJobScheduling, fmt.Sprintf("%s (attempt %s)", jobRetryAttemptMessage, formatAttempt(attempt, policy)),
+		map[string]string{
+			"Attempt":     fmt.Sprint(attempt),
+			"MaxAttempts": fmt.Sprint(policy.MaxAttempts),
+		})
+}
+
+// JobRetryDelayedEvent is emitted when retrying a job is delayed until a later time.
+func JobRetryDelayedEvent(attempt int, policy *models.RetryPolicy, nextRetry time.Time) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s (attempt %s, next retry at %s)",
+			jobRetryDelayedMessage, formatAttempt(attempt, policy), nextRetry.UTC().Format(time.RFC3339)),
+		map[string]string{
+			"Attempt":       fmt.Sprint(attempt),
+			"MaxAttempts":   fmt.Sprint(policy.MaxAttempts),
+			"NextRetryTime": nextRetry.UTC().Format(time.RFC3339),
+		})
+}
+
+// formatAttempt returns the attempt number relative to the maximum number of attempts, if any.
+func formatAttempt(attempt int, policy *models.RetryPolicy) string {
+	if policy.MaxAttempts == 0 {
+		return fmt.Sprint(attempt)
+	}
+	return fmt.Sprintf("%d/%d", attempt, policy.MaxAttempts)
+}
+
+func JobDependencyFailedEvent(upstreamJobID string, upstreamState models.JobStateType) models.Event {
+	return event(EventTopicJobScheduling, jobDependencyFailedMessage, map[string]string{
+		"UpstreamJobID":    upstreamJobID,
+		"UpstreamJobState": upstreamState.String(),
+	})
+}
+
+// JobScheduledEvent is emitted when a scheduled job is registered and its first run is scheduled.
+func JobScheduledEvent(nextRun time.Time) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s (next run at %s)", jobScheduledMessage, nextRun.UTC().Format(time.RFC3339)),
+		map[string]string{
+			"NextRunTime": nextRun.UTC().Format(time.RFC3339),
+		})
+}
+
+// JobRunStartedEvent is emitted when a scheduled job spawns a new run.
+func JobRunStartedEvent(runJobID string, scheduledTime, nextRun time.Time) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s %s (next run at %s)", jobRunStartedMessage, runJobID, nextRun.UTC().Format(time.RFC3339)),
+		map[string]string{
+			"RunJobID":      runJobID,
+			"ScheduledTime": scheduledTime.UTC().Format(time.RFC3339),
+			"NextRunTime":   nextRun.UTC().Format(time.RFC3339),
+		})
+}
+
+// JobRunSkippedEvent is emitted when a scheduled run is skipped due to the job's concurrency policy.
+func JobRunSkippedEvent(activeRuns int, scheduledTime, nextRun time.Time) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s (next run at %s)", jobRunSkippedMessage, nextRun.UTC().Format(time.RFC3339)),
+		map[string]string{
+			"ActiveRuns":    fmt.Sprint(activeRuns),
+			"ScheduledTime": scheduledTime.UTC().Format(time.RFC3339),
+			"NextRunTime":   nextRun.UTC().Format(time.RFC3339),
+		})
+}
+
+// JobSpawnedEvent is emitted when a job is submitted by a scheduled job.
+func JobSpawnedEvent(scheduledJobID string, scheduledTime time.Time) models.Event {
+	return event(EventTopicJobSubmission, jobSpawnedMessage, map[string]string{
+		"ScheduledJobID": scheduledJobID,
+		"ScheduledTime":  scheduledTime.UTC().Format(time.RFC3339),
+	})
+}
+
+// JobReplacedEvent is emitted when a run of a scheduled job is stopped to start a newer run.
+func JobReplacedEvent(replacedBy string) models.Event {
+	return event(EventTopicJobScheduling, jobReplacedMessage, map[string]string{
+		"ReplacedBy": replacedBy,
+	})
+}
+
+// JobQuotaExceededEvent is emitted when executions of a job are not scheduled because of its namespace quota.
+func JobQuotaExceededEvent(heldBack int, quota *models.NamespaceQuota, nextAttempt time.Time) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s (%s, next attempt at %s)",
+			jobQuotaExceededMessage, quota.String(), nextAttempt.UTC().Format(time.RFC3339)),
+		map[string]string{
+			"Namespace":       quota.Namespace,
+			"HeldBack":        fmt.Sprint(heldBack),
+			"NextAttemptTime": nextAttempt.UTC().Format(time.RFC3339),
+		})
+}
+
+// JobResultCacheHitEvent is emitted when a cacheable job completes with the published results
+// of a previous job with the same result cache key, instead of running.
+func JobResultCacheHitEvent(entry *models.ResultCacheEntry) models.Event {
+	return event(EventTopicJobScheduling,
+		fmt.Sprintf("%s %s", jobResultCacheHitMessage, entry.JobID),
+		map[string]string{
+			"CachedJobID": entry.JobID,
+			"CacheKey":    entry.Key,
+		})
+}
+
 func ExecStoppedByJobStopEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByJobStopMessage, map[string]string{})
 }
 
 func ExecStoppedByNodeUnhealthyEvent() models.Event {
-	return event(EventTopicJobScheduling, execStoppedByNodeUnhealthyMessage, map[string]string{})
+	return event(EventTopicJobScheduling, execStoppedByNodeUnhealthyMessage, map[string]string{
+		models.DetailsKeyFailureReason: string(models.FailureReasonNodeLost),
+	})
+}
+
+func ExecStoppedByNodeDrainEvent() models.Event {
+	return event(EventTopicJobScheduling, execStoppedByNodeDrainMessage, map[string]string{})
 }
 
 func ExecStoppedByExecutionTimeoutEvent(timeout time.Duration) models.Event {
 	e := models.NewEvent(EventTopicExecutionTimeout).
 		WithError(fmt.Errorf("%s. Execution took longer than %s", executionTimeoutMessage, timeout)).
 		WithHint(executionTimeoutHint).
+		WithFailsExecution(true).
+		WithDetail(models.DetailsKeyFailureReason, string(models.FailureReasonTimeout))
+	return *e
+}
+
+// ExecFailedToResolveSecretsEvent is emitted when the requester fails an execution without running it,
+// because the secrets referenced by its job cannot be resolved.
+func ExecFailedToResolveSecretsEvent(err error) models.Event {
+	e := models.NewEvent(EventTopicJobScheduling).
+		WithError(fmt.Errorf("%s: %w", execSecretsUnresolvedMessage, err)).
+		WithHint(execSecretsUnresolvedHint).
 		WithFailsExecution(true)
 	return *e
 }
 
+// ExecCheckpointedEvent is emitted when the requester records a checkpoint taken of an execution.
+func ExecCheckpointedEvent(checkpoint *models.Checkpoint) models.Event {
+	return event(EventTopicExecutionCheckpoint, execCheckpointedMessage, map[string]string{
+		"CheckpointTime": time.Unix(0, checkpoint.Time).UTC().Format(time.RFC3339),
+	})
+}
+
+// ExecEgressEvent is emitted when an execution whose outbound requests were filtered completes,
+// listing the hosts its requests were denied to.
+func ExecEgressEvent(egress *models.EgressLog) models.Event {
+	details := map[string]string{
+		"Allowed": fmt.Sprint(egress.Allowed),
+		"Denied":  fmt.Sprint(egress.Denied),
+	}
+	if len(egress.DeniedHosts) > 0 {
+		details["DeniedHosts"] = strings.Join(egress.DeniedHosts, ",")
+	}
+	return event(EventTopicExecutionEgress, fmt.Sprintf("%s %s", execEgressMessage, egress), details)
+}
+
 func ExecStoppedByNodeRejectedEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByNodeRejectedMessage, map[string]string{})
 }
@@ -83,3 +259,12 @@ func ExecStoppedByNodeRejectedEvent() models.Event {
 func ExecStoppedByOversubscriptionEvent() models.Event {
 	return event(EventTopicJobScheduling, execStoppedByOversubscriptionMessage, map[string]string{})
 }
+
+// ExecStoppedByPreemptionEvent is emitted when an execution is preempted by an execution of a higher priority job.
+func ExecStoppedByPreemptionEvent(preemptingJob *models.Job) models.Event {
+	return event(EventTopicJobScheduling, fmt.Sprintf("%s %s", execStoppedByPreemptionMessage, preemptingJob.ID),
+		map[string]string{
+			"PreemptedByJobID":    preemptingJob.ID,
+			"PreemptedByPriority": fmt.Sprint(preemptingJob.Priority),
+		})
+}
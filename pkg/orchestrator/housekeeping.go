@@ -30,6 +30,8 @@ type HousekeepingParams struct {
 	// It is better that compute nodes timeout and report the failure before the orchestrator does.
 	// This buffer is added to the execution timeout to allow for this.
 	TimeoutBuffer time.Duration
+	// Retention is the policy that decides which terminal jobs are removed from the job store
+	Retention RetentionPolicy
 	// Clock is the clock used for time-based operations.
 	// If not provided, the system clock is used.
 	Clock clock.Clock
@@ -40,6 +42,8 @@ type Housekeeping struct {
 	jobStore         jobstore.Store
 	interval         time.Duration
 	timeoutBuffer    time.Duration
+	retention        RetentionPolicy
+	lastRetention    time.Time
 
 	workersSem chan struct{}
 	waitGroup  sync.WaitGroup
@@ -59,6 +63,10 @@ func NewHousekeeping(params HousekeepingParams) (*Housekeeping, error) {
 		params.Clock = clock.New()
 	}
 
+	if params.Retention.Interval == 0 {
+		params.Retention.Interval = DefaultRetentionInterval
+	}
+
 	// validate params
 	err := errors.Join(
 		validate.IsNotNil(params.EvaluationBroker, "evaluation broker cannot be nil"),
@@ -66,6 +74,7 @@ func NewHousekeeping(params HousekeepingParams) (*Housekeeping, error) {
 		validate.IsGreaterThanZero(params.Interval, "interval must be greater than zero"),
 		validate.IsGreaterThanZero(params.Workers, "workers must be greater than zero"),
 		validate.IsGreaterThanZero(params.TimeoutBuffer, "timeout buffer must be greater than zero"),
+		params.Retention.Validate(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error validating housekeeping params: %w", err)
@@ -76,6 +85,7 @@ func NewHousekeeping(params HousekeepingParams) (*Housekeeping, error) {
 		jobStore:         params.JobStore,
 		interval:         params.Interval,
 		timeoutBuffer:    params.TimeoutBuffer,
+		retention:        params.Retention,
 		workersSem:       make(chan struct{}, params.Workers),
 		stopChan:         make(chan struct{}),
 		clock:            params.Clock,
@@ -124,6 +134,9 @@ func (h *Housekeeping) Stop(ctx context.Context) {
 func (h *Housekeeping) runHousekeepingTasks(ctx context.Context) {
 	h.running = true
 	defer func() { h.running = false }()
+	if h.ShouldRun() {
+		h.restoreScheduledJobs(ctx)
+	}
 	ticker := time.NewTicker(h.interval)
 	defer ticker.Stop()
 
@@ -139,6 +152,7 @@ func (h *Housekeeping) runHousekeepingTasks(ctx context.Context) {
 
 			// run housekeeping tasks
 			h.timeoutExecutions(ctx, activeExecutions)
+			h.enforceRetention(ctx)
 		case <-ctx.Done():
 			log.Ctx(ctx).Debug().Msg("Context cancelled, stopping housekeeping task")
 			return
@@ -185,6 +199,35 @@ func (h *Housekeeping) fetchActiveExecutions(ctx context.Context) []*models.Exec
 	return activeExecutions
 }
 
+// restoreScheduledJobs enqueues an evaluation for each active scheduled job, so that the
+// schedulers re-arm the next run persisted in the job store, such as after the requester restarted.
+func (h *Housekeeping) restoreScheduledJobs(ctx context.Context) {
+	scheduledJobs, err := h.jobStore.GetInProgressJobs(ctx, models.JobTypeScheduled)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to get active scheduled jobs")
+		return
+	}
+	for i := range scheduledJobs {
+		job := &scheduledJobs[i]
+		eval := models.NewEvaluation().
+			WithJobID(job.ID).
+			WithNamespace(job.Namespace).
+			WithTriggeredBy(models.EvalTriggerJobRestore).
+			WithType(job.Type).
+			WithPriority(job.Priority).
+			WithComment("restore schedule of job").
+			Normalize()
+
+		if err = h.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+			log.Ctx(ctx).Err(err).Msgf("failed to create evaluation to restore scheduled job %s", job.ID)
+			continue
+		}
+		if err = h.evaluationBroker.Enqueue(eval); err != nil {
+			log.Ctx(ctx).Err(err).Msgf("failed to enqueue evaluation to restore scheduled job %s", job.ID)
+		}
+	}
+}
+
 // timeoutExecutions checks for executions that have been in progress beyond the timeout period
 // and enqueue an evaluation for them. It is the responsibility of the scheduler to fail the executions
 func (h *Housekeeping) timeoutExecutions(ctx context.Context, activeExecutions []*models.Execution) {
@@ -37,6 +37,7 @@ func (s *HousekeepingTestSuite) SetupTest() {
 	s.clock = clock.NewMock()
 	s.mockJobStore = jobstore.NewMockStore(s.ctrl)
 	s.mockEvaluationBroker = NewMockEvaluationBroker(s.ctrl)
+	s.mockJobStore.EXPECT().GetInProgressJobs(gomock.Any(), models.JobTypeScheduled).Return(nil, nil).AnyTimes()
 
 	h, _ := NewHousekeeping(HousekeepingParams{
 		EvaluationBroker: s.mockEvaluationBroker,
@@ -344,6 +345,38 @@ func (s *HousekeepingTestSuite) TestCancelProvidedContext() {
 	s.Eventually(func() bool { return !s.housekeeping.IsRunning() }, 1*time.Second, 10*time.Millisecond)
 }
 
+func (s *HousekeepingTestSuite) TestRestoreScheduledJobs() {
+	// use dedicated mocks, as the suite's job store accepts any lookup of scheduled jobs
+	ctrl := gomock.NewController(s.T())
+	mockJobStore := jobstore.NewMockStore(ctrl)
+	mockEvaluationBroker := NewMockEvaluationBroker(ctrl)
+	h, err := NewHousekeeping(HousekeepingParams{
+		EvaluationBroker: mockEvaluationBroker,
+		JobStore:         mockJobStore,
+		Interval:         200 * time.Millisecond,
+		TimeoutBuffer:    timeoutBuffer,
+		Clock:            s.clock,
+	})
+	s.Require().NoError(err)
+
+	job := mock.Job()
+	job.Type = models.JobTypeScheduled
+	matchEvaluation := func(eval *models.Evaluation) {
+		s.Require().Equal(job.ID, eval.JobID)
+		s.Require().Equal(models.EvalTriggerJobRestore, eval.TriggeredBy)
+		s.Require().Equal(models.JobTypeScheduled, eval.Type)
+	}
+	mockJobStore.EXPECT().GetInProgressJobs(gomock.Any(), models.JobTypeScheduled).Return([]models.Job{*job}, nil)
+	mockJobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, eval models.Evaluation) {
+		matchEvaluation(&eval)
+	}).Return(nil)
+	mockEvaluationBroker.EXPECT().Enqueue(gomock.Any()).Do(func(eval *models.Evaluation) {
+		matchEvaluation(eval)
+	}).Return(nil)
+
+	h.restoreScheduledJobs(context.Background())
+}
+
 func (s *HousekeepingTestSuite) assertEvaluationEnqueued(job models.Job) {
 	matchEvaluation := func(eval *models.Evaluation) {
 		s.Require().Equal(job.ID, eval.JobID)
@@ -111,6 +111,15 @@ type NodeSelector interface {
 	// TopMatchingNodes return the top ranked desiredCount number of nodes that match job constraints
 	// ordered in descending order based on their rank, or error if not enough nodes match.
 	TopMatchingNodes(ctx context.Context, job *models.Job, desiredCount int) ([]models.NodeInfo, error)
+
+	// DrainingNodes returns the cordon of each node being drained, keyed by node ID.
+	DrainingNodes(ctx context.Context) (map[string]*models.NodeCordon, error)
+}
+
+// SecretResolver resolves the values of the secrets referenced by the jobs of a namespace.
+type SecretResolver interface {
+	// ResolveSecrets returns the values of the named secrets of a namespace, leaving out missing secrets.
+	ResolveSecrets(ctx context.Context, namespace string, names []string) (models.SecretValues, error)
 }
 
 type RetryStrategy interface {
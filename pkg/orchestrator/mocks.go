@@ -403,6 +403,21 @@ func (mr *MockNodeSelectorMockRecorder) AllNodes(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllNodes", reflect.TypeOf((*MockNodeSelector)(nil).AllNodes), ctx)
 }
 
+// DrainingNodes mocks base method.
+func (m *MockNodeSelector) DrainingNodes(ctx context.Context) (map[string]*models.NodeCordon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainingNodes", ctx)
+	ret0, _ := ret[0].(map[string]*models.NodeCordon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainingNodes indicates an expected call of DrainingNodes.
+func (mr *MockNodeSelectorMockRecorder) DrainingNodes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainingNodes", reflect.TypeOf((*MockNodeSelector)(nil).DrainingNodes), ctx)
+}
+
 // TopMatchingNodes mocks base method.
 func (m *MockNodeSelector) TopMatchingNodes(ctx context.Context, job *models.Job, desiredCount int) ([]models.NodeInfo, error) {
 	m.ctrl.T.Helper()
@@ -418,6 +433,44 @@ func (mr *MockNodeSelectorMockRecorder) TopMatchingNodes(ctx, job, desiredCount
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopMatchingNodes", reflect.TypeOf((*MockNodeSelector)(nil).TopMatchingNodes), ctx, job, desiredCount)
 }
 
+// MockSecretResolver is a mock of SecretResolver interface.
+type MockSecretResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretResolverMockRecorder
+}
+
+// MockSecretResolverMockRecorder is the mock recorder for MockSecretResolver.
+type MockSecretResolverMockRecorder struct {
+	mock *MockSecretResolver
+}
+
+// NewMockSecretResolver creates a new mock instance.
+func NewMockSecretResolver(ctrl *gomock.Controller) *MockSecretResolver {
+	mock := &MockSecretResolver{ctrl: ctrl}
+	mock.recorder = &MockSecretResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretResolver) EXPECT() *MockSecretResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveSecrets mocks base method.
+func (m *MockSecretResolver) ResolveSecrets(ctx context.Context, namespace string, names []string) (models.SecretValues, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSecrets", ctx, namespace, names)
+	ret0, _ := ret[0].(models.SecretValues)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveSecrets indicates an expected call of ResolveSecrets.
+func (mr *MockSecretResolverMockRecorder) ResolveSecrets(ctx, namespace, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSecrets", reflect.TypeOf((*MockSecretResolver)(nil).ResolveSecrets), ctx, namespace, names)
+}
+
 // MockRetryStrategy is a mock of RetryStrategy interface.
 type MockRetryStrategy struct {
 	ctrl     *gomock.Controller
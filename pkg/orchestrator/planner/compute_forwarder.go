@@ -2,30 +2,42 @@ package planner
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/rs/zerolog/log"
 )
 
 type ComputeForwarder struct {
-	id             string
-	computeService compute.Endpoint
-	jobStore       jobstore.Store
+	id               string
+	computeService   compute.Endpoint
+	jobStore         jobstore.Store
+	secrets          orchestrator.SecretResolver
+	evaluationBroker orchestrator.EvaluationBroker
 }
 
 type ComputeForwarderParams struct {
 	ID             string
 	ComputeService compute.Endpoint
 	JobStore       jobstore.Store
+	// Secrets resolves the secrets referenced by jobs, which are sent to the compute node that runs them.
+	// Jobs cannot reference secrets if it is not set.
+	Secrets orchestrator.SecretResolver
+	// EvaluationBroker enqueues evaluations of the jobs whose executions fail before reaching their node,
+	// such as when their secrets cannot be resolved, so that they are retried or failed.
+	EvaluationBroker orchestrator.EvaluationBroker
 }
 
 func NewComputeForwarder(params ComputeForwarderParams) *ComputeForwarder {
 	return &ComputeForwarder{
-		id:             params.ID,
-		computeService: params.ComputeService,
-		jobStore:       params.JobStore,
+		id:               params.ID,
+		computeService:   params.ComputeService,
+		jobStore:         params.JobStore,
+		secrets:          params.Secrets,
+		evaluationBroker: params.EvaluationBroker,
 	}
 }
 
@@ -45,6 +57,10 @@ func (s *ComputeForwarder) doProcess(ctx context.Context, plan *models.Plan) {
 	// TODO: notifying nodes for the same plan can be done in parallel, but we should limit
 	//  the total number of concurrent notifications across plans to avoid overloading the network.
 	for _, exec := range plan.NewExecutions {
+		// executions that reuse cached results are created completed, and never run on their node
+		if exec.IsTerminalComputeState() {
+			continue
+		}
 		waitForApproval := exec.DesiredState.StateType == models.ExecutionDesiredStatePending
 		s.doNotifyAskForBid(ctx, exec, waitForApproval)
 	}
@@ -87,6 +103,15 @@ func (s *ComputeForwarder) doNotifyAskForBid(ctx context.Context, execution *mod
 			TargetPeerID: execution.NodeID,
 		},
 	}
+	// executions that do not wait for approval start right away, and need their secrets now
+	if !waitForApproval {
+		secrets, err := s.resolveSecrets(ctx, execution)
+		if err != nil {
+			s.failExecution(ctx, execution, err, models.ExecutionStateNew)
+			return
+		}
+		request.Secrets = secrets
+	}
 	_, err := s.computeService.AskForBid(ctx, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msgf("Failed to notify node %s to bid for execution %s",
@@ -102,17 +127,92 @@ func (s *ComputeForwarder) doNotifyAskForBid(ctx context.Context, execution *mod
 	s.updateExecutionState(ctx, execution, newState, models.ExecutionStateNew)
 }
 
+// resolveSecrets returns the values of the secrets referenced by the job of the execution.
+func (s *ComputeForwarder) resolveSecrets(ctx context.Context, execution *models.Execution) (models.SecretValues, error) {
+	if execution.Job == nil {
+		return nil, nil
+	}
+	names := execution.Job.SecretRefs()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	if s.secrets == nil {
+		return nil, fmt.Errorf("job %s references secrets, but secrets are not supported", execution.JobID)
+	}
+	return s.secrets.ResolveSecrets(ctx, execution.Namespace, names)
+}
+
+// failExecution fails an execution without notifying its node, such as when the secrets it needs to run
+// cannot be resolved, and enqueues an evaluation so that the scheduler retries the job or fails it.
+func (s *ComputeForwarder) failExecution(ctx context.Context, execution *models.Execution, cause error,
+	expectedStates ...models.ExecutionStateType) {
+	log.Ctx(ctx).Error().Err(cause).Msgf("Failing execution %s before notifying node %s", execution.ID, execution.NodeID)
+	event := orchestrator.ExecFailedToResolveSecretsEvent(cause)
+	err := s.jobStore.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: execution.ID,
+		NewValues: models.Execution{
+			ComputeState: models.NewExecutionState(models.ExecutionStateFailed).WithMessage(event.Message),
+			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution failed"),
+		},
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: expectedStates,
+		},
+		Event: event,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("Failed to update execution %s to state %s", execution.ID, models.ExecutionStateFailed)
+		return
+	}
+	if s.evaluationBroker == nil {
+		return
+	}
+
+	eval := models.NewEvaluation().
+		WithJobID(execution.JobID).
+		WithNamespace(execution.Namespace).
+		WithTriggeredBy(models.EvalTriggerExecFailure).
+		WithType(execution.Job.Type).
+		WithPriority(execution.Job.Priority).
+		WithComment(fmt.Sprintf("execution %s failed before reaching its node", execution.ID)).
+		Normalize()
+	if err = s.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("Failed to create evaluation for failed execution %s", execution.ID)
+		return
+	}
+	if err = s.evaluationBroker.Enqueue(eval); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("Failed to enqueue evaluation for failed execution %s", execution.ID)
+	}
+}
+
 // doNotifyBidAccepted notifies the target node that the bid was accepted.
 func (s *ComputeForwarder) doNotifyBidAccepted(ctx context.Context, execution *models.Execution) {
 	log.Ctx(ctx).Debug().Msgf("Requester node %s responding with BidAccepted for bid: %s", s.id, execution.ID)
+	secrets, err := s.resolveSecrets(ctx, execution)
+	if err != nil {
+		s.failExecution(ctx, execution, err, models.ExecutionStateAskForBidAccepted)
+		// the node holds the execution until its bid is accepted or rejected
+		_, err = s.computeService.BidRejected(ctx, compute.BidRejectedRequest{
+			ExecutionID: execution.ID,
+			RoutingMetadata: compute.RoutingMetadata{
+				SourcePeerID: s.id,
+				TargetPeerID: execution.NodeID,
+			},
+		})
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("Failed to notify node %s that bid %s was rejected",
+				execution.NodeID, execution.ID)
+		}
+		return
+	}
 	request := compute.BidAcceptedRequest{
 		ExecutionID: execution.ID,
 		RoutingMetadata: compute.RoutingMetadata{
 			SourcePeerID: s.id,
 			TargetPeerID: execution.NodeID,
 		},
+		Secrets: secrets,
 	}
-	_, err := s.computeService.BidAccepted(ctx, request)
+	_, err = s.computeService.BidAccepted(ctx, request)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msgf("Failed to notify node %s that bid %s was accepted",
 			execution.NodeID, execution.ID)
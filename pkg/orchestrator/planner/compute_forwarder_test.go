@@ -11,6 +11,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -134,6 +135,95 @@ func (suite *ComputeForwarderSuite) TestProcess_OnNotifyFailure_NoStateUpdate()
 	suite.waitUntilSatisfied()
 }
 
+func (suite *ComputeForwarderSuite) TestProcess_WithSecrets_ShouldSendResolvedSecrets() {
+	secrets := orchestrator.NewMockSecretResolver(suite.ctrl)
+	suite.computeForwarder = NewComputeForwarder(ComputeForwarderParams{
+		ID:             suite.nodeID,
+		ComputeService: suite.computeService,
+		JobStore:       suite.jobStore,
+		Secrets:        secrets,
+	})
+
+	plan := mock.Plan()
+	plan.Job.Task().Env = map[string]string{"TOKEN": "secret:token", "MODE": "test"}
+	toAskForBid := suite.mockUpdateExecution(plan, "toAskForBid", models.ExecutionDesiredStateRunning, models.ExecutionStateNew)
+	toAskForBidPending := suite.mockUpdateExecution(plan, "toAskForBidPending", models.ExecutionDesiredStatePending, models.ExecutionStateNew)
+	bidAccepted := suite.mockUpdateExecution(plan, "bidAccepted", models.ExecutionDesiredStateRunning, models.ExecutionStateAskForBidAccepted)
+
+	values := models.SecretValues{"token": "s3cr3t"}
+	// secrets are only resolved for executions that start running, and not while waiting for approval
+	secrets.EXPECT().ResolveSecrets(suite.ctx, plan.Job.Namespace, []string{"token"}).Return(values, nil).Times(2)
+
+	suite.computeService.EXPECT().AskForBid(suite.ctx, NewComputeRequestMatcherFromPlanUpdate(suite.T(), suite.nodeID, toAskForBid)).
+		DoAndReturn(func(_ context.Context, request compute.AskForBidRequest) (compute.AskForBidResponse, error) {
+			suite.Equal(values, request.Secrets)
+			return compute.AskForBidResponse{}, nil
+		}).Times(1)
+	suite.assertStateUpdated(toAskForBid.Execution, models.ExecutionStateBidAccepted, models.ExecutionStateNew)
+	suite.computeService.EXPECT().AskForBid(suite.ctx, NewComputeRequestMatcherFromPlanUpdate(suite.T(), suite.nodeID, toAskForBidPending)).
+		DoAndReturn(func(_ context.Context, request compute.AskForBidRequest) (compute.AskForBidResponse, error) {
+			suite.Nil(request.Secrets)
+			return compute.AskForBidResponse{}, nil
+		}).Times(1)
+	suite.assertStateUpdated(toAskForBidPending.Execution, models.ExecutionStateAskForBid, models.ExecutionStateNew)
+	suite.computeService.EXPECT().BidAccepted(suite.ctx, NewComputeRequestMatcherFromPlanUpdate(suite.T(), suite.nodeID, bidAccepted)).
+		DoAndReturn(func(_ context.Context, request compute.BidAcceptedRequest) (compute.BidAcceptedResponse, error) {
+			suite.Equal(values, request.Secrets)
+			return compute.BidAcceptedResponse{}, nil
+		}).Times(1)
+	suite.assertStateUpdated(bidAccepted.Execution, models.ExecutionStateBidAccepted, models.ExecutionStateAskForBidAccepted)
+
+	suite.NoError(suite.computeForwarder.Process(suite.ctx, plan))
+
+	suite.waitUntilSatisfied()
+}
+
+func (suite *ComputeForwarderSuite) TestProcess_WithUnresolvedSecrets_ShouldFailExecutions() {
+	secrets := orchestrator.NewMockSecretResolver(suite.ctrl)
+	evaluationBroker := orchestrator.NewMockEvaluationBroker(suite.ctrl)
+	suite.computeForwarder = NewComputeForwarder(ComputeForwarderParams{
+		ID:               suite.nodeID,
+		ComputeService:   suite.computeService,
+		JobStore:         suite.jobStore,
+		Secrets:          secrets,
+		EvaluationBroker: evaluationBroker,
+	})
+
+	plan := mock.Plan()
+	plan.Job.Task().Env = map[string]string{"TOKEN": "secret:token"}
+	toAskForBid := suite.mockUpdateExecution(plan, "toAskForBid", models.ExecutionDesiredStateRunning, models.ExecutionStateNew)
+	bidAccepted := suite.mockUpdateExecution(plan, "bidAccepted", models.ExecutionDesiredStateRunning, models.ExecutionStateAskForBidAccepted)
+
+	resolveErr := errors.New("secret token not found")
+	secrets.EXPECT().ResolveSecrets(suite.ctx, plan.Job.Namespace, []string{"token"}).Return(nil, resolveErr).Times(2)
+
+	// the executions fail on the requester with the resolution error, and the node waiting for its bid
+	// to be accepted is told that it was rejected
+	expectedStates := map[string]models.ExecutionStateType{
+		toAskForBid.Execution.ID: models.ExecutionStateNew,
+		bidAccepted.Execution.ID: models.ExecutionStateAskForBidAccepted,
+	}
+	suite.jobStore.EXPECT().UpdateExecution(suite.ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, request jobstore.UpdateExecutionRequest) error {
+			suite.Equal(models.ExecutionStateFailed, request.NewValues.ComputeState.StateType)
+			suite.Contains(request.NewValues.ComputeState.Message, resolveErr.Error())
+			suite.Equal([]models.ExecutionStateType{expectedStates[request.ExecutionID]}, request.Condition.ExpectedStates)
+			return nil
+		}).Times(2)
+	suite.computeService.EXPECT().BidRejected(suite.ctx, NewComputeRequestMatcherFromPlanUpdate(suite.T(), suite.nodeID, bidAccepted)).Times(1)
+	suite.jobStore.EXPECT().CreateEvaluation(suite.ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, eval models.Evaluation) error {
+			suite.Equal(plan.Job.ID, eval.JobID)
+			suite.Equal(models.EvalTriggerExecFailure, eval.TriggeredBy)
+			return nil
+		}).Times(2)
+	evaluationBroker.EXPECT().Enqueue(gomock.Any()).Times(2)
+
+	suite.NoError(suite.computeForwarder.Process(suite.ctx, plan))
+
+	suite.waitUntilSatisfied()
+}
+
 func (suite *ComputeForwarderSuite) mockUpdateExecution(plan *models.Plan, id string, desiredState models.ExecutionDesiredStateType, currentState models.ExecutionStateType) *models.PlanExecutionDesiredUpdate {
 	execution := mock.ExecutionForJob(plan.Job)
 	execution.ID = id
@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// DependencyNotifier is a planner implementation that enqueues evaluations for jobs
+// that depend on a job that just reached a terminal state. This allows downstream jobs
+// to be scheduled as soon as their upstream jobs complete, or to be failed if an upstream job fails.
+type DependencyNotifier struct {
+	jobStore         jobstore.Store
+	evaluationBroker orchestrator.EvaluationBroker
+}
+
+// DependencyNotifierParams holds the parameters for creating a new DependencyNotifier.
+type DependencyNotifierParams struct {
+	JobStore         jobstore.Store
+	EvaluationBroker orchestrator.EvaluationBroker
+}
+
+// NewDependencyNotifier creates a new instance of DependencyNotifier.
+func NewDependencyNotifier(params DependencyNotifierParams) *DependencyNotifier {
+	return &DependencyNotifier{
+		jobStore:         params.JobStore,
+		evaluationBroker: params.EvaluationBroker,
+	}
+}
+
+// Process enqueues evaluations for downstream jobs if the plan moves the job to a terminal state.
+func (n *DependencyNotifier) Process(ctx context.Context, plan *models.Plan) error {
+	switch plan.DesiredJobState {
+	case models.JobStateTypeCompleted, models.JobStateTypeFailed, models.JobStateTypeStopped:
+	default:
+		return nil
+	}
+
+	dependents, err := n.jobStore.GetDependentJobs(ctx, plan.Job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dependents of job %s: %w", plan.Job.ID, err)
+	}
+
+	for i := range dependents {
+		dependent := &dependents[i]
+		if dependent.IsTerminal() {
+			continue
+		}
+		if err = n.notify(ctx, dependent, plan.Job.ID); err != nil {
+			// log error and avoid having a single job failure affect the other dependents
+			log.Ctx(ctx).Err(err).Msgf("failed to notify job %s of upstream job %s state change", dependent.ID, plan.Job.ID)
+		}
+	}
+	return nil
+}
+
+// notify creates and enqueues an evaluation for the dependent job
+func (n *DependencyNotifier) notify(ctx context.Context, dependent *models.Job, upstreamJobID string) error {
+	eval := models.NewEvaluation().
+		WithJobID(dependent.ID).
+		WithNamespace(dependent.Namespace).
+		WithTriggeredBy(models.EvalTriggerJobDependency).
+		WithType(dependent.Type).
+		WithPriority(dependent.Priority).
+		WithComment(fmt.Sprintf("upstream job %s reached a terminal state", upstreamJobID)).
+		Normalize()
+
+	if err := n.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+		return fmt.Errorf("failed to create evaluation %+v: %w", eval, err)
+	}
+	if err := n.evaluationBroker.Enqueue(eval); err != nil {
+		return fmt.Errorf("failed to enqueue evaluation %+v: %w", eval, err)
+	}
+	return nil
+}
+
+// compile-time check whether the DependencyNotifier implements the Planner interface.
+var _ orchestrator.Planner = (*DependencyNotifier)(nil)
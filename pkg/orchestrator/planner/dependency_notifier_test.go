@@ -0,0 +1,75 @@
+//go:build unit || !integration
+
+package planner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type DependencyNotifierSuite struct {
+	suite.Suite
+	ctx              context.Context
+	ctrl             *gomock.Controller
+	jobStore         *jobstore.MockStore
+	evaluationBroker *orchestrator.MockEvaluationBroker
+	notifier         *DependencyNotifier
+}
+
+func TestDependencyNotifierSuite(t *testing.T) {
+	suite.Run(t, new(DependencyNotifierSuite))
+}
+
+func (suite *DependencyNotifierSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.jobStore = jobstore.NewMockStore(suite.ctrl)
+	suite.evaluationBroker = orchestrator.NewMockEvaluationBroker(suite.ctrl)
+	suite.notifier = NewDependencyNotifier(DependencyNotifierParams{
+		JobStore:         suite.jobStore,
+		EvaluationBroker: suite.evaluationBroker,
+	})
+}
+
+func (suite *DependencyNotifierSuite) TestProcess_NonTerminalJobState_ShouldDoNothing() {
+	plan := mock.Plan()
+	plan.DesiredJobState = models.JobStateTypeRunning
+	suite.NoError(suite.notifier.Process(suite.ctx, plan))
+}
+
+func (suite *DependencyNotifierSuite) TestProcess_TerminalJobState_ShouldEvaluateDependents() {
+	for _, state := range []models.JobStateType{
+		models.JobStateTypeCompleted,
+		models.JobStateTypeFailed,
+		models.JobStateTypeStopped,
+	} {
+		suite.Run(state.String(), func() {
+			plan := mock.Plan()
+			plan.DesiredJobState = state
+
+			dependent := mock.Job()
+			dependent.DependsOn = []*models.JobDependency{{JobID: plan.Job.ID}}
+			finished := mock.Job()
+			finished.DependsOn = []*models.JobDependency{{JobID: plan.Job.ID}}
+			finished.State = models.NewJobState(models.JobStateTypeStopped)
+
+			suite.jobStore.EXPECT().GetDependentJobs(suite.ctx, plan.Job.ID).Return([]models.Job{*dependent, *finished}, nil)
+			suite.jobStore.EXPECT().CreateEvaluation(suite.ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, eval models.Evaluation) error {
+					suite.Equal(dependent.ID, eval.JobID)
+					suite.Equal(models.EvalTriggerJobDependency, eval.TriggeredBy)
+					return nil
+				}).Times(1)
+			suite.evaluationBroker.EXPECT().Enqueue(gomock.Any()).Times(1)
+			suite.NoError(suite.notifier.Process(suite.ctx, plan))
+		})
+	}
+}
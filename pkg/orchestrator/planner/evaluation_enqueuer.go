@@ -0,0 +1,49 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// EvaluationEnqueuer is a planner implementation that persists and enqueues the follow-up
+// evaluations created by the scheduler, such as to retry a job after a delay.
+type EvaluationEnqueuer struct {
+	jobStore         jobstore.Store
+	evaluationBroker orchestrator.EvaluationBroker
+}
+
+// EvaluationEnqueuerParams holds the parameters for creating a new EvaluationEnqueuer.
+type EvaluationEnqueuerParams struct {
+	JobStore         jobstore.Store
+	EvaluationBroker orchestrator.EvaluationBroker
+}
+
+// NewEvaluationEnqueuer creates a new instance of EvaluationEnqueuer.
+func NewEvaluationEnqueuer(params EvaluationEnqueuerParams) *EvaluationEnqueuer {
+	return &EvaluationEnqueuer{
+		jobStore:         params.JobStore,
+		evaluationBroker: params.EvaluationBroker,
+	}
+}
+
+// Process creates and enqueues the new evaluations in the plan.
+// Evaluations with a WaitUntil time are not visible to the workers until that time has passed.
+func (e *EvaluationEnqueuer) Process(ctx context.Context, plan *models.Plan) error {
+	for _, eval := range plan.NewEvaluations {
+		eval.Normalize()
+		if err := e.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+			return fmt.Errorf("failed to create evaluation %+v: %w", eval, err)
+		}
+		if err := e.evaluationBroker.Enqueue(eval); err != nil {
+			return fmt.Errorf("failed to enqueue evaluation %+v: %w", eval, err)
+		}
+	}
+	return nil
+}
+
+// compile-time check whether the EvaluationEnqueuer implements the Planner interface.
+var _ orchestrator.Planner = (*EvaluationEnqueuer)(nil)
@@ -0,0 +1,65 @@
+//go:build unit || !integration
+
+package planner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type EvaluationEnqueuerSuite struct {
+	suite.Suite
+	ctx              context.Context
+	ctrl             *gomock.Controller
+	jobStore         *jobstore.MockStore
+	evaluationBroker *orchestrator.MockEvaluationBroker
+	enqueuer         *EvaluationEnqueuer
+}
+
+func TestEvaluationEnqueuerSuite(t *testing.T) {
+	suite.Run(t, new(EvaluationEnqueuerSuite))
+}
+
+func (suite *EvaluationEnqueuerSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.jobStore = jobstore.NewMockStore(suite.ctrl)
+	suite.evaluationBroker = orchestrator.NewMockEvaluationBroker(suite.ctrl)
+	suite.enqueuer = NewEvaluationEnqueuer(EvaluationEnqueuerParams{
+		JobStore:         suite.jobStore,
+		EvaluationBroker: suite.evaluationBroker,
+	})
+}
+
+func (suite *EvaluationEnqueuerSuite) TestProcess_NoEvaluations() {
+	suite.NoError(suite.enqueuer.Process(suite.ctx, mock.Plan()))
+}
+
+func (suite *EvaluationEnqueuerSuite) TestProcess_ShouldCreateAndEnqueue() {
+	plan := mock.Plan()
+	eval := models.NewEvaluation().WithJobID(plan.Job.ID).WithWaitUntil(time.Now().Add(time.Minute))
+	plan.AppendEvaluation(eval)
+
+	suite.jobStore.EXPECT().CreateEvaluation(suite.ctx, *eval).Times(1)
+	suite.evaluationBroker.EXPECT().Enqueue(eval).Times(1)
+	suite.NoError(suite.enqueuer.Process(suite.ctx, plan))
+}
+
+func (suite *EvaluationEnqueuerSuite) TestProcess_CreateError_ShouldNotEnqueue() {
+	plan := mock.Plan()
+	eval := models.NewEvaluation().WithJobID(plan.Job.ID)
+	plan.AppendEvaluation(eval)
+
+	suite.jobStore.EXPECT().CreateEvaluation(suite.ctx, *eval).Return(errors.New("create error")).Times(1)
+	suite.Error(suite.enqueuer.Process(suite.ctx, plan))
+}
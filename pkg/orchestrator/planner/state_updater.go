@@ -45,6 +45,9 @@ func (s *StateUpdater) Process(ctx context.Context, plan *models.Plan) error {
 					StateType: u.DesiredState,
 					Message:   u.Event.Message,
 				},
+				PreemptedBy:   u.PreemptedBy,
+				Drained:       u.Drained,
+				FailureReason: models.FailureReasonFromEvent(u.Event),
 			},
 			Condition: jobstore.UpdateExecutionCondition{
 				ExpectedRevision: u.Execution.Revision,
@@ -56,6 +59,37 @@ func (s *StateUpdater) Process(ctx context.Context, plan *models.Plan) error {
 		}
 	}
 
+	// Create other jobs, such as the jobs spawned by a scheduled job
+	for _, u := range plan.NewJobs {
+		err := s.store.CreateJob(ctx, *u.Job, u.Event)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Update the state of other jobs
+	for _, u := range plan.UpdatedJobs {
+		err := s.store.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
+			JobID:    u.Job.ID,
+			NewState: u.DesiredState,
+			Event:    u.Event,
+			Condition: jobstore.UpdateJobCondition{
+				ExpectedRevision: u.Job.Revision,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete other jobs
+	for _, jobID := range plan.DeletedJobs {
+		err := s.store.DeleteJob(ctx, jobID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update job state if necessary
 	if !plan.DesiredJobState.IsUndefined() {
 		err := s.store.UpdateJobState(ctx, jobstore.UpdateJobStateRequest{
@@ -70,6 +104,13 @@ func (s *StateUpdater) Process(ctx context.Context, plan *models.Plan) error {
 			return err
 		}
 	}
+
+	// Record the job as the one whose results are reused by later identical jobs
+	if plan.ResultCacheEntry != nil {
+		if err := s.store.PutResultCacheEntry(ctx, *plan.ResultCacheEntry); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -74,6 +74,35 @@ func (suite *StateUpdaterSuite) TestStateUpdater_Process_UpdateJobState_Error()
 	suite.Error(suite.stateUpdater.Process(suite.ctx, plan))
 }
 
+func (suite *StateUpdaterSuite) TestStateUpdater_Process_OtherJobs_Success() {
+	plan := mock.Plan()
+	newJob, stoppedJob, deletedJob := mock.Job(), mock.Job(), mock.Job()
+	newJobEvent := models.Event{Message: "spawned"}
+	stoppedJobEvent := models.Event{Message: "replaced"}
+	plan.AppendJob(newJob, newJobEvent)
+	plan.AppendStoppedJob(stoppedJob, stoppedJobEvent)
+	plan.AppendDeletedJob(deletedJob.ID)
+
+	suite.mockStore.EXPECT().CreateJob(suite.ctx, *newJob, newJobEvent).Times(1)
+	suite.mockStore.EXPECT().UpdateJobState(suite.ctx, NewUpdateJobMatcher(suite.T(), stoppedJob, UpdateJobMatcherParams{
+		NewState:         models.JobStateTypeStopped,
+		Event:            stoppedJobEvent,
+		ExpectedRevision: stoppedJob.Revision,
+	})).Times(1)
+	suite.mockStore.EXPECT().DeleteJob(suite.ctx, deletedJob.ID).Times(1)
+	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
+}
+
+func (suite *StateUpdaterSuite) TestStateUpdater_Process_ResultCacheEntry_Success() {
+	plan := mock.Plan()
+	plan.DesiredJobState = models.JobStateTypeCompleted
+	plan.ResultCacheEntry = &models.ResultCacheEntry{Namespace: plan.Job.Namespace, Key: "key", JobID: plan.Job.ID}
+
+	suite.mockStore.EXPECT().UpdateJobState(suite.ctx, gomock.Any()).Times(1)
+	suite.mockStore.EXPECT().PutResultCacheEntry(suite.ctx, *plan.ResultCacheEntry).Times(1)
+	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
+}
+
 func (suite *StateUpdaterSuite) TestStateUpdater_Process_NoOp() {
 	plan := mock.Plan()
 	suite.NoError(suite.stateUpdater.Process(suite.ctx, plan))
@@ -0,0 +1,55 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// GetNamespaceUsage returns the number of active executions of in progress jobs in each namespace,
+// and the resources allocated to them. An execution is active if it is not in a terminal state
+// and has not been requested to stop. If namespace is set, only the usage of that namespace is returned,
+// and only the jobs of that namespace are read. Executions for which skip returns true are not counted,
+// and skip can be nil.
+func GetNamespaceUsage(ctx context.Context, store jobstore.Store, namespace string,
+	skip func(*models.Execution) bool) (map[string]*models.NamespaceUsage, error) {
+	var inProgress []models.Job
+	var err error
+	if namespace != "" {
+		inProgress, err = store.GetInProgressJobsInNamespace(ctx, namespace)
+	} else {
+		inProgress, err = store.GetInProgressJobs(ctx, "")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve in progress jobs: %w", err)
+	}
+
+	usage := make(map[string]*models.NamespaceUsage)
+	for i := range inProgress {
+		job := &inProgress[i]
+		resources, err := job.TotalResources()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert resources config of job %s to resources: %w", job.ID, err)
+		}
+		executions, err := store.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve executions of job %s: %w", job.ID, err)
+		}
+		for j := range executions {
+			exec := &executions[j]
+			if exec.IsTerminalComputeState() || exec.DesiredState.StateType == models.ExecutionDesiredStateStopped {
+				continue
+			}
+			if skip != nil && skip(exec) {
+				continue
+			}
+			if _, ok := usage[job.Namespace]; !ok {
+				usage[job.Namespace] = &models.NamespaceUsage{Namespace: job.Namespace}
+			}
+			usage[job.Namespace].Add(*resources)
+		}
+	}
+	return usage, nil
+}
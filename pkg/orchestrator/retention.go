@@ -0,0 +1,193 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// DefaultRetentionInterval is the default interval at which the retention policy is enforced
+const DefaultRetentionInterval = 1 * time.Hour
+
+// RetentionPolicy decides which terminal jobs are removed from the job store,
+// along with their executions, evaluations and history.
+type RetentionPolicy struct {
+	// MaxAge is how long terminal jobs are kept after they last changed.
+	// Zero keeps them regardless of their age.
+	MaxAge time.Duration
+	// KeepLast is how many of the most recently changed terminal jobs are kept in each namespace.
+	// Zero keeps them regardless of their number.
+	KeepLast int
+	// GroupByLabel keeps the last KeepLast terminal jobs for each value of this label in a namespace,
+	// instead of for the whole namespace.
+	GroupByLabel string
+	// Interval is the interval at which the policy is enforced.
+	// If not provided, DefaultRetentionInterval is used.
+	Interval time.Duration
+	// Archive is where removed jobs are archived. If nil, they are deleted without being archived.
+	Archive archive.Archive
+}
+
+// IsEnabled returns true if the policy removes any jobs
+func (p RetentionPolicy) IsEnabled() bool {
+	return p.MaxAge > 0 || p.KeepLast > 0
+}
+
+// Validate validates the policy
+func (p RetentionPolicy) Validate() error {
+	var err error
+	if p.MaxAge < 0 {
+		err = errors.Join(err, errors.New("job retention max age must be >= 0"))
+	}
+	if p.KeepLast < 0 {
+		err = errors.Join(err, errors.New("job retention keep last must be >= 0"))
+	}
+	if p.Interval < 0 {
+		err = errors.Join(err, errors.New("job retention interval must be >= 0"))
+	}
+	return err
+}
+
+// retentionPageSize is the number of jobs read at a time when enforcing the retention policy
+const retentionPageSize = 100
+
+// retentionScan decides which terminal jobs the policy no longer retains, as jobs are read one page at a time.
+// When only MaxAge is set, jobs are read from the least recently modified, and reading stops at the first job
+// modified after the cutoff. KeepLast needs jobs read from the most recently modified, so that the jobs retained
+// in each group are counted before reaching the older ones.
+type retentionScan struct {
+	policy   RetentionPolicy
+	cutoff   time.Time
+	retained map[string]int
+	done     bool
+}
+
+func (p RetentionPolicy) newScan(now time.Time) *retentionScan {
+	return &retentionScan{
+		policy:   p,
+		cutoff:   now.Add(-p.MaxAge),
+		retained: make(map[string]int),
+	}
+}
+
+// query returns the query of the page of jobs at the offset, in the order the scan reads them
+func (s *retentionScan) query(offset uint32) jobstore.JobQuery {
+	return jobstore.JobQuery{
+		Limit:       retentionPageSize,
+		Offset:      offset,
+		SortBy:      "modified_at",
+		SortReverse: s.policy.KeepLast > 0,
+	}
+}
+
+// expired returns true if the policy no longer retains the job, which must be read in the order of the scan.
+// The scan is done once it reaches a job that the policy retains along with all the jobs read after it.
+func (s *retentionScan) expired(job *models.Job) bool {
+	tooOld := s.policy.MaxAge > 0 && time.Unix(0, job.ModifyTime).Before(s.cutoff)
+	if s.policy.KeepLast == 0 && !tooOld {
+		s.done = true
+		return false
+	}
+	if !job.IsTerminal() {
+		return false
+	}
+	group := job.Namespace
+	if s.policy.GroupByLabel != "" {
+		group += "/" + job.Labels[s.policy.GroupByLabel]
+	}
+	s.retained[group]++
+	tooMany := s.policy.KeepLast > 0 && s.retained[group] > s.policy.KeepLast
+	return tooOld || tooMany
+}
+
+// enforceRetention removes the terminal jobs that the retention policy no longer retains,
+// archiving them first if the policy has an archive.
+func (h *Housekeeping) enforceRetention(ctx context.Context) {
+	if !h.retention.IsEnabled() {
+		return
+	}
+	now := h.clock.Now()
+	if !h.lastRetention.IsZero() && now.Sub(h.lastRetention) < h.retention.Interval {
+		return
+	}
+	h.lastRetention = now
+
+	scan := h.retention.newScan(now)
+	total := 0
+	for offset := uint32(0); !scan.done; {
+		response, err := h.jobStore.GetJobs(ctx, scan.query(offset))
+		if err != nil {
+			log.Ctx(ctx).Err(err).Msg("failed to get jobs to enforce retention policy")
+			return
+		}
+		removed := h.removeExpiredJobs(ctx, scan, response.Jobs)
+		total += removed
+		if response.NextOffset == 0 {
+			break
+		}
+		// the jobs removed from the page shift the following ones back
+		offset = response.NextOffset - uint32(removed)
+	}
+	if total > 0 {
+		log.Ctx(ctx).Debug().Msgf("removed %d jobs no longer retained by the retention policy", total)
+	}
+}
+
+// removeExpiredJobs removes the jobs of a page that the policy no longer retains, and returns how many it removed.
+// Jobs that non-terminal jobs depend on are always retained, as their results are still needed.
+func (h *Housekeeping) removeExpiredJobs(ctx context.Context, scan *retentionScan, jobs []models.Job) int {
+	removed := 0
+	for i := 0; i < len(jobs) && !scan.done; i++ {
+		job := &jobs[i]
+		if !scan.expired(job) {
+			continue
+		}
+		dependents, err := h.jobStore.GetDependentJobs(ctx, job.ID)
+		if err != nil {
+			log.Ctx(ctx).Err(err).Msgf("failed to get dependents of job %s", job.ID)
+			continue
+		}
+		if lo.ContainsBy(dependents, func(dependent models.Job) bool { return !dependent.IsTerminal() }) {
+			continue
+		}
+		if err = h.removeJob(ctx, job); err != nil {
+			// log error and avoid having a single job failure affect the retention of other jobs
+			log.Ctx(ctx).Err(err).Msgf("failed to remove job %s", job.ID)
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// removeJob archives the job if the retention policy has an archive, and deletes it from the job store
+func (h *Housekeeping) removeJob(ctx context.Context, job *models.Job) error {
+	if h.retention.Archive != nil {
+		executions, err := h.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+		if err != nil {
+			return fmt.Errorf("failed to get executions of job %s: %w", job.ID, err)
+		}
+		history, err := h.jobStore.GetJobHistory(ctx, job.ID, jobstore.JobHistoryFilterOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get history of job %s: %w", job.ID, err)
+		}
+		err = h.retention.Archive.Put(ctx, archive.ArchivedJob{
+			Job:         *job,
+			Executions:  executions,
+			History:     history,
+			ArchiveTime: h.clock.Now().UTC().UnixNano(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive job %s: %w", job.ID, err)
+		}
+	}
+	return h.jobStore.DeleteJob(ctx, job.ID)
+}
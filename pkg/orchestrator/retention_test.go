@@ -0,0 +1,182 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type RetentionTestSuite struct {
+	suite.Suite
+	ctrl         *gomock.Controller
+	clock        *clock.Mock
+	mockJobStore *jobstore.MockStore
+}
+
+func TestRetentionTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionTestSuite))
+}
+
+func (s *RetentionTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Now())
+	s.mockJobStore = jobstore.NewMockStore(s.ctrl)
+}
+
+func (s *RetentionTestSuite) newHousekeeping(policy RetentionPolicy) *Housekeeping {
+	h, err := NewHousekeeping(HousekeepingParams{
+		EvaluationBroker: NewMockEvaluationBroker(s.ctrl),
+		JobStore:         s.mockJobStore,
+		Interval:         time.Minute,
+		TimeoutBuffer:    timeoutBuffer,
+		Retention:        policy,
+		Clock:            s.clock,
+	})
+	s.Require().NoError(err)
+	return h
+}
+
+// retentionJob returns a job in the given state that was last modified age ago
+func (s *RetentionTestSuite) retentionJob(namespace string, state models.JobStateType, age time.Duration) models.Job {
+	job := mock.Job()
+	job.Namespace = namespace
+	job.State = models.NewJobState(state)
+	job.ModifyTime = s.clock.Now().Add(-age).UnixNano()
+	return *job
+}
+
+func (s *RetentionTestSuite) TestRetentionScan() {
+	running := s.retentionJob("a", models.JobStateTypeRunning, 30*time.Hour)
+	newest := s.retentionJob("a", models.JobStateTypeCompleted, 1*time.Hour)
+	newer := s.retentionJob("a", models.JobStateTypeFailed, 2*time.Hour)
+	old := s.retentionJob("a", models.JobStateTypeStopped, 25*time.Hour)
+	other := s.retentionJob("b", models.JobStateTypeCompleted, 3*time.Hour)
+
+	// expired returns the IDs of the jobs the policy no longer retains, reading them in the order of its scan
+	expired := func(policy RetentionPolicy, jobs ...models.Job) []string {
+		scan := policy.newScan(s.clock.Now())
+		sort.Slice(jobs, func(i, j int) bool {
+			if scan.query(0).SortReverse {
+				return jobs[i].ModifyTime > jobs[j].ModifyTime
+			}
+			return jobs[i].ModifyTime < jobs[j].ModifyTime
+		})
+		var result []string
+		for i := 0; i < len(jobs) && !scan.done; i++ {
+			if scan.expired(&jobs[i]) {
+				result = append(result, jobs[i].ID)
+			}
+		}
+		return result
+	}
+
+	s.Empty(expired(RetentionPolicy{}, newest, newer, other, old, running))
+	s.Equal([]string{old.ID},
+		expired(RetentionPolicy{MaxAge: 24 * time.Hour}, newest, newer, other, old, running))
+	s.Equal([]string{newer.ID, old.ID},
+		expired(RetentionPolicy{KeepLast: 1}, newest, newer, other, old, running))
+	s.Equal([]string{old.ID},
+		expired(RetentionPolicy{KeepLast: 2, MaxAge: 48 * time.Hour}, newest, newer, other, old, running))
+
+	// jobs are grouped by the value of the label within their namespace
+	newer.Labels = map[string]string{"team": "x"}
+	s.Equal([]string{old.ID},
+		expired(RetentionPolicy{KeepLast: 1, GroupByLabel: "team"}, newest, newer, other, old, running))
+}
+
+func (s *RetentionTestSuite) TestEnforceRetention_Archive() {
+	ctx := context.Background()
+	jobArchive, err := archive.NewLocalArchive(s.T().TempDir())
+	s.Require().NoError(err)
+
+	kept := s.retentionJob("a", models.JobStateTypeCompleted, 1*time.Hour)
+	expired := s.retentionJob("a", models.JobStateTypeCompleted, 48*time.Hour)
+	execution := mock.ExecutionForJob(&expired)
+	history := []models.JobHistory{{JobID: expired.ID, Type: models.JobHistoryTypeJobLevel}}
+
+	s.mockJobStore.EXPECT().GetJobs(gomock.Any(), jobstore.JobQuery{
+		Limit: retentionPageSize, SortBy: "modified_at",
+	}).Return(&jobstore.JobQueryResponse{Jobs: []models.Job{expired, kept}}, nil).Times(1)
+	s.mockJobStore.EXPECT().GetDependentJobs(gomock.Any(), expired.ID).Return(nil, nil)
+	s.mockJobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: expired.ID}).
+		Return([]models.Execution{*execution}, nil)
+	s.mockJobStore.EXPECT().GetJobHistory(gomock.Any(), expired.ID, jobstore.JobHistoryFilterOptions{}).
+		Return(history, nil)
+	s.mockJobStore.EXPECT().DeleteJob(gomock.Any(), expired.ID).Return(nil)
+
+	h := s.newHousekeeping(RetentionPolicy{MaxAge: 24 * time.Hour, Archive: jobArchive})
+	h.enforceRetention(ctx)
+
+	archived, err := jobArchive.Get(ctx, expired.ID)
+	s.Require().NoError(err)
+	s.Equal(expired.ID, archived.Job.ID)
+	s.Len(archived.Executions, 1)
+	s.Len(archived.History, 1)
+
+	_, err = jobArchive.Get(ctx, kept.ID)
+	s.ErrorAs(err, new(*bacerrors.JobNotFound))
+
+	// the policy is not enforced again until the interval elapsed
+	h.enforceRetention(ctx)
+}
+
+func (s *RetentionTestSuite) TestEnforceRetention_Pages() {
+	ctx := context.Background()
+	expired := s.retentionJob("a", models.JobStateTypeCompleted, 72*time.Hour)
+	upstream := s.retentionJob("a", models.JobStateTypeCompleted, 48*time.Hour)
+	dependent := s.retentionJob("a", models.JobStateTypeRunning, 48*time.Hour)
+	dependent.DependsOn = []*models.JobDependency{{JobID: upstream.ID}}
+	kept := s.retentionJob("a", models.JobStateTypeCompleted, 1*time.Hour)
+
+	query := jobstore.JobQuery{Limit: retentionPageSize, SortBy: "modified_at"}
+	s.mockJobStore.EXPECT().GetJobs(gomock.Any(), query).Return(&jobstore.JobQueryResponse{
+		Jobs: []models.Job{expired}, NextOffset: retentionPageSize,
+	}, nil)
+	s.mockJobStore.EXPECT().GetDependentJobs(gomock.Any(), expired.ID).Return(nil, nil)
+	s.mockJobStore.EXPECT().DeleteJob(gomock.Any(), expired.ID).Return(nil)
+
+	// the next page starts earlier, as the removed job shifted the following ones back. Reading stops at
+	// the first job modified after the cutoff, and jobs that non-terminal jobs depend on are retained.
+	query.Offset = retentionPageSize - 1
+	s.mockJobStore.EXPECT().GetJobs(gomock.Any(), query).Return(&jobstore.JobQueryResponse{
+		Jobs: []models.Job{upstream, dependent, kept}, NextOffset: 2 * retentionPageSize,
+	}, nil)
+	s.mockJobStore.EXPECT().GetDependentJobs(gomock.Any(), upstream.ID).Return([]models.Job{dependent}, nil)
+
+	h := s.newHousekeeping(RetentionPolicy{MaxAge: 24 * time.Hour})
+	h.enforceRetention(ctx)
+}
+
+func (s *RetentionTestSuite) TestEnforceRetention_Disabled() {
+	h := s.newHousekeeping(RetentionPolicy{})
+	h.enforceRetention(context.Background())
+}
+
+func (s *RetentionTestSuite) TestValidate() {
+	s.NoError(RetentionPolicy{}.Validate())
+	s.Error(RetentionPolicy{MaxAge: -time.Hour}.Validate())
+	s.Error(RetentionPolicy{KeepLast: -1}.Validate())
+
+	_, err := NewHousekeeping(HousekeepingParams{
+		EvaluationBroker: NewMockEvaluationBroker(s.ctrl),
+		JobStore:         s.mockJobStore,
+		Interval:         time.Minute,
+		TimeoutBuffer:    timeoutBuffer,
+		Retention:        RetentionPolicy{KeepLast: -1},
+	})
+	s.Error(err)
+}
@@ -4,6 +4,7 @@ package scheduler
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
@@ -43,6 +45,10 @@ type BatchJobSchedulerTestSuite struct {
 	nodeSelector  *orchestrator.MockNodeSelector
 	retryStrategy orchestrator.RetryStrategy
 	scheduler     *BatchServiceJobScheduler
+	// quota is the quota of the jobs' namespace, or nil if the namespace has no quota
+	quota *models.NamespaceQuota
+	// draining is the cordon of the nodes being drained, keyed by node ID
+	draining map[string]*models.NodeCordon
 }
 
 func (s *BatchJobSchedulerTestSuite) SetupTest() {
@@ -60,6 +66,17 @@ func (s *BatchJobSchedulerTestSuite) SetupTest() {
 		RetryStrategy: s.retryStrategy,
 		Clock:         s.clock,
 	})
+	s.quota = nil
+	s.jobStore.EXPECT().GetNamespaceQuota(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, namespace string) (models.NamespaceQuota, error) {
+			if s.quota == nil {
+				return models.NamespaceQuota{}, jobstore.NewErrNamespaceQuotaNotFound(namespace)
+			}
+			return *s.quota, nil
+		}).AnyTimes()
+	s.draining = nil
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).DoAndReturn(
+		func(context.Context) (map[string]*models.NodeCordon, error) { return s.draining, nil }).AnyTimes()
 
 	// we only want to freeze time to have more deterministic tests.
 	// It doesn't matter what time it is as we are using relative time to this value
@@ -347,6 +364,698 @@ func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldStopExpiredExecutions() {
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldWaitForPendingDependencies() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeRunning)
+	job.DependsOn = []*models.JobDependency{{JobID: upstream.ID, Target: "/inputs/upstream"}}
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+
+	// empty plan, job stays pending
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldFailWhenDependencyFails() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeFailed)
+	job.DependsOn = []*models.JobDependency{{JobID: upstream.ID}}
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeFailed,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldInjectDependencyResults() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeCompleted)
+	job.DependsOn = []*models.JobDependency{{JobID: upstream.ID, Target: "/inputs/upstream"}}
+
+	upstreamExec := mock.ExecutionForJob(upstream)
+	upstreamExec.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	upstreamExec.PublishedResult = models.NewSpecConfig(models.StorageSourceIPFS).WithParam("CID", "QmTest")
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: upstream.ID}).
+		Return([]models.Execution{*upstreamExec}, nil)
+
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[0])}
+	s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), gomock.Any(), 1).Return(nodeInfos, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Do(func(_ context.Context, plan *models.Plan) {
+		inputs := plan.NewExecutions[0].Job.Task().InputSources
+		s.Require().Len(inputs, 1)
+		s.Equal("/inputs/upstream", inputs[0].Target)
+		s.Equal(upstreamExec.PublishedResult.Params, inputs[0].Source.Params)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldInjectDependencyResultsOnRetry() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	upstream := mock.Job()
+	upstream.State = models.NewJobState(models.JobStateTypeCompleted)
+	job.DependsOn = []*models.JobDependency{{JobID: upstream.ID, Target: "/inputs/upstream"}}
+
+	upstreamExec := mock.ExecutionForJob(upstream)
+	upstreamExec.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	upstreamExec.PublishedResult = models.NewSpecConfig(models.StorageSourceIPFS).WithParam("CID", "QmTest")
+
+	failedExec := mock.ExecutionForJob(job)
+	failedExec.NodeID = nodeIDs[1]
+	failedExec.ComputeState = models.NewExecutionState(models.ExecutionStateFailed)
+	failedExec.DesiredState = models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped)
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).
+		Return([]models.Execution{*failedExec}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), upstream.ID).Return(*upstream, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: upstream.ID}).
+		Return([]models.Execution{*upstreamExec}, nil)
+
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[0])}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil).AnyTimes()
+	s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), gomock.Any(), 1).Return(nodeInfos, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		inputs := plan.NewExecutions[0].Job.Task().InputSources
+		s.Require().Len(inputs, 1)
+		s.Equal("/inputs/upstream", inputs[0].Target)
+		s.Equal(upstreamExec.PublishedResult.Params, inputs[0].Source.Params)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldFailWhenAttemptsExhausted() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now())
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+	}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Equal(models.JobStateTypeFailed, plan.DesiredJobState)
+		s.Equal("3", plan.Event.Details["FailedAttempts"])
+		s.Contains(plan.UpdatedExecutions, executions[execAskForBid].ID)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldFailOnNonRetryableClass() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now())
+	job.RetryPolicy = &models.RetryPolicy{RetryOn: []string{models.RetryOnNodeLost}}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+	}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Equal(models.JobStateTypeFailed, plan.DesiredJobState)
+		s.Equal(models.RetryOnExecutionError, plan.Event.Details["FailureClass"])
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldDelayRetry() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 5, InitialDelay: 60, Multiplier: 2}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+	}, nil)
+
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// three failed attempts a minute ago, so the next retry is 60s * 2^2 after that
+		s.Empty(plan.NewExecutions)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerRetryDelay, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(s.clock.Now().Add(3*time.Minute).UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+		s.Equal("4", plan.Event.Details["Attempt"])
+		s.Equal("5", plan.Event.Details["MaxAttempts"])
+		s.Equal(job.State.StateType, plan.DesiredJobState)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldNotDuplicateDelayedRetry() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 5, InitialDelay: 60, Multiplier: 2}
+	pendingRetry := models.NewEvaluation().WithJobID(job.ID).WithTriggeredBy(models.EvalTriggerRetryDelay)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+	}, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{*pendingRetry}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Empty(plan.NewExecutions)
+		s.Empty(plan.NewEvaluations)
+		s.True(plan.DesiredJobState.IsUndefined())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldNotCountCanceledExecutionsAsAttempts() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-time.Minute))
+	executions[execCanceled].FailureReason = ""
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// two failed attempts, as the execution canceled by the user is not counted
+		s.Len(plan.NewExecutions, 1)
+		s.Equal("3", plan.Event.Details["Attempt"])
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldRetryAfterDelay() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-5 * time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 5, InitialDelay: 60, Multiplier: 2}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Len(plan.NewExecutions, 1)
+		s.Empty(plan.NewEvaluations)
+		s.Equal("4", plan.Event.Details["Attempt"])
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldResizeMemoryOnOOM() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-5 * time.Minute))
+	job.Task().ResourcesConfig.Memory = "256mb"
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 5, ResizeOnOOM: &models.ResizePolicy{MaxMemory: "1gb"}}
+	executions[execFailed].FailureReason = models.FailureReasonOOMKilled
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+
+	// the executions are placed on nodes with enough memory for the resized job
+	s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), gomock.Any(), 1).
+		DoAndReturn(func(_ context.Context, placed *models.Job, _ int) ([]models.NodeInfo, error) {
+			s.Equal("512000000", placed.Task().ResourcesConfig.Memory)
+			return nodeInfos, nil
+		})
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal("512000000", plan.NewExecutions[0].Job.Task().ResourcesConfig.Memory)
+		// the job itself is not changed
+		s.Equal("256mb", job.Task().ResourcesConfig.Memory)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_RetryPolicy_ShouldCapResizedMemory() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-5 * time.Minute))
+	job.Task().ResourcesConfig.Memory = "256mb"
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 5, ResizeOnOOM: &models.ResizePolicy{MaxMemory: "1gb"}}
+
+	// a previous retry was already resized to 768mb, and was OOM killed again
+	resized := job.Copy()
+	resized.Task().ResourcesConfig.Memory = "768mb"
+	executions[execFailed].Job = resized
+	executions[execFailed].FailureReason = models.FailureReasonOOMKilled
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), gomock.Any(), 1).Return(nodeInfos, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal("1000000000", plan.NewExecutions[0].Job.Task().ResourcesConfig.Memory)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ArrayJob_ShouldCreateExecutionPerIndex() {
+	ctx := context.Background()
+	job, _, evaluation := mockArrayJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), nodeIDs[0]),
+		*fakeNodeInfo(s.T(), nodeIDs[1]),
+		*fakeNodeInfo(s.T(), nodeIDs[2]),
+	}
+	s.mockNodeSelection(job, nodeInfos, 3)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 3)
+		indexes := make([]int, 0, len(plan.NewExecutions))
+		for _, exec := range plan.NewExecutions {
+			indexes = append(indexes, exec.ArrayIndex)
+			s.Equal(strconv.Itoa(exec.ArrayIndex), exec.Job.Task().Env[models.EnvArrayIndex])
+		}
+		s.ElementsMatch([]int{0, 1, 2}, indexes)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ArrayJob_ShouldOnlyCreateMissingIndexes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockArrayJob()
+	executions[0].ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	executions[1].ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	executions[2].ComputeState = models.NewExecutionState(models.ExecutionStateFailed)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[1].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal(2, plan.NewExecutions[0].ArrayIndex)
+		s.True(plan.DesiredJobState.IsUndefined())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ArrayJob_ShouldCompleteWhenAllIndexesComplete() {
+	ctx := context.Background()
+	job, executions, evaluation := mockArrayJob()
+	for i := range executions {
+		executions[i].ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeCompleted,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Preemption_ShouldPreemptLowerPriorityExecutions() {
+	ctx := context.Background()
+	s.scheduler.preemption = orchestrator.PreemptionPolicy{Enabled: true}
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	job.Priority = 50
+	victimJob, victimExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fullNodeInfo(nodeIDs[0])}, 1)
+	s.jobStore.EXPECT().GetActiveExecutionsOnNode(gomock.Any(), nodeIDs[0]).Return([]models.Execution{*victimExec}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), victimJob.ID).Return(*victimJob, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Require().Contains(plan.UpdatedExecutions, victimExec.ID)
+		update := plan.UpdatedExecutions[victimExec.ID]
+		s.Equal(models.ExecutionDesiredStateStopped, update.DesiredState)
+		s.Equal(job.ID, update.PreemptedBy)
+		s.Equal(job.ID, update.Event.Details["PreemptedByJobID"])
+
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(victimJob.ID, plan.NewEvaluations[0].JobID)
+		s.Equal(models.EvalTriggerPreemption, plan.NewEvaluations[0].TriggeredBy)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Preemption_ShouldNotPreemptNonPreemptibleNamespaces() {
+	ctx := context.Background()
+	s.scheduler.preemption = orchestrator.PreemptionPolicy{Enabled: true}
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	job.Priority = 50
+	victimJob, victimExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+	s.quota = &models.NamespaceQuota{Namespace: job.Namespace, NonPreemptible: true}
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fullNodeInfo(nodeIDs[0])}, 1)
+	s.jobStore.EXPECT().GetActiveExecutionsOnNode(gomock.Any(), nodeIDs[0]).Return([]models.Execution{*victimExec}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), victimJob.ID).Return(*victimJob, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Empty(plan.UpdatedExecutions)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Preemption_ShouldNotPreemptWhenNodeHasCapacityLeft() {
+	ctx := context.Background()
+	s.scheduler.preemption = orchestrator.PreemptionPolicy{Enabled: true}
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	job.Priority = 50
+	victimJob, victimExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+	// the capacity the node last reported is outdated, as the victim is the only execution left on it
+	node := fullNodeInfo(nodeIDs[0])
+	node.ComputeNodeInfo.MaxCapacity = models.Resources{CPU: 1, Memory: 1 << 30}
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*node}, 1)
+	s.jobStore.EXPECT().GetActiveExecutionsOnNode(gomock.Any(), nodeIDs[0]).Return([]models.Execution{*victimExec}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), victimJob.ID).Return(*victimJob, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Empty(plan.UpdatedExecutions)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Preemption_ShouldRequeuePreemptedJob() {
+	ctx := context.Background()
+	job, executions, evaluation := mockPreemptedJob(s.clock.Now().Add(-time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[1])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal(nodeIDs[1], plan.NewExecutions[0].NodeID)
+		// preempted executions are replaced without counting as attempts
+		s.NotContains(plan.Event.Details, "Attempt")
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Checkpoint_ShouldRestoreFromLatestCheckpoint() {
+	ctx := context.Background()
+	job, executions, evaluation := mockPreemptedJob(s.clock.Now().Add(-time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3}
+	job.Task().Checkpoint = &models.CheckpointConfig{Path: "/checkpoint"}
+	checkpoint := &models.Checkpoint{
+		ExecutionID: executions[0].ID,
+		Result:      &models.SpecConfig{Type: models.PublisherLocal},
+		Time:        s.clock.Now().UnixNano(),
+	}
+	executions[0].Checkpoint = checkpoint
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[1])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal(checkpoint, plan.NewExecutions[0].RestoreFrom)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Preemption_ShouldReplacePreemptedExecutionsWhateverTheRetryOnClasses() {
+	ctx := context.Background()
+	job, executions, evaluation := mockPreemptedJob(s.clock.Now().Add(-time.Minute))
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 1, RetryOn: []string{models.RetryOnExecutionError}}
+	// the preempted execution failed once stopped, which doesn't make it a failure
+	executions[0].ComputeState = models.NewExecutionState(models.ExecutionStateFailed)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[1])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.True(plan.DesiredJobState.IsUndefined())
+		s.Len(plan.NewExecutions, 1)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Quota_ShouldHoldBackExecutionsOverQuota() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.quota = &models.NamespaceQuota{Namespace: job.Namespace, MaxConcurrentExecutions: 3}
+	otherJob, otherExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetInProgressJobsInNamespace(gomock.Any(), job.Namespace).Return([]models.Job{*otherJob}, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: otherJob.ID}).
+		Return([]models.Execution{*otherExec}, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[0]), *fakeNodeInfo(s.T(), nodeIDs[1])}, 2)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Len(plan.NewExecutions, 2)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerQuotaWait, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(s.clock.Now().Add(DefaultQuotaRetryDelay).UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+		s.True(plan.DesiredJobState.IsUndefined())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Quota_ShouldWaitWhenQuotaExhausted() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.quota = &models.NamespaceQuota{Namespace: job.Namespace, MaxResources: &models.ResourcesConfig{CPU: "0.1"}}
+	otherJob, otherExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetInProgressJobsInNamespace(gomock.Any(), job.Namespace).Return([]models.Job{*otherJob}, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: otherJob.ID}).
+		Return([]models.Execution{*otherExec}, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Empty(plan.NewExecutions)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerQuotaWait, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(job.State.StateType, plan.DesiredJobState)
+		s.Equal("3", plan.Event.Details["HeldBack"])
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Quota_ShouldNotDuplicatePendingQuotaWait() {
+	ctx := context.Background()
+	job, _, evaluation := mockJob()
+	s.quota = &models.NamespaceQuota{Namespace: job.Namespace, MaxConcurrentExecutions: 1}
+	otherJob, otherExec := mockPreemptionVictim(job.Namespace, nodeIDs[0])
+	pendingWait := models.NewEvaluation().WithJobID(job.ID).WithTriggeredBy(models.EvalTriggerQuotaWait)
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetInProgressJobsInNamespace(gomock.Any(), job.Namespace).Return([]models.Job{*otherJob}, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: otherJob.ID}).
+		Return([]models.Execution{*otherExec}, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{*pendingWait}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Empty(plan.NewExecutions)
+		s.Empty(plan.NewEvaluations)
+		s.True(plan.DesiredJobState.IsUndefined())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Drain_ShouldMigratePendingExecutions() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	deadline := s.clock.Now().Add(time.Hour)
+	s.draining = map[string]*models.NodeCordon{
+		executions[execAskForBid].NodeID:   {Drain: true, DrainDeadline: deadline},
+		executions[execBidAccepted].NodeID: {Drain: true, DrainDeadline: deadline},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[2])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// the pending execution is replaced right away, while the running one is given until the deadline
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal(nodeIDs[2], plan.NewExecutions[0].NodeID)
+		s.Require().Len(plan.UpdatedExecutions, 1)
+		s.Require().Contains(plan.UpdatedExecutions, executions[execAskForBid].ID)
+		s.True(plan.UpdatedExecutions[executions[execAskForBid].ID].Drained)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerNodeDrain, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(deadline.UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Drain_ShouldMigrateRunningExecutionsAfterDeadline() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	s.draining = map[string]*models.NodeCordon{
+		executions[execBidAccepted].NodeID: {Drain: true, DrainDeadline: s.clock.Now().Add(-time.Second)},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[2])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Require().Contains(plan.UpdatedExecutions, executions[execBidAccepted].ID)
+		s.True(plan.UpdatedExecutions[executions[execBidAccepted].ID].Drained)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_Drain_ShouldNotCountDrainedExecutionsAsAttempts() {
+	ctx := context.Background()
+	job, executions, evaluation := mockRetryJob(s.clock.Now().Add(-time.Minute))
+	executions[execCanceled].Drained = true
+	job.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), executions[execAskForBid].NodeID)}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// two failed attempts, as the drained execution is not counted
+		s.Len(plan.NewExecutions, 1)
+		s.Equal("3", plan.Event.Details["Attempt"])
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ResultCache_ShouldCompleteFromCache() {
+	ctx := context.Background()
+	job, _, evaluation := mockCacheableJob()
+	cachedJob, cachedExec := mockCachedJob(job)
+	key, err := job.ResultCacheKey()
+	s.Require().NoError(err)
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetResultCacheEntry(gomock.Any(), job.Namespace, key).Return(
+		models.ResultCacheEntry{Namespace: job.Namespace, Key: key, JobID: cachedJob.ID}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), cachedJob.ID).Return(*cachedJob, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: cachedJob.ID}).
+		Return([]models.Execution{*cachedExec}, nil)
+
+	// the job completes without placing executions on nodes
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:               evaluation,
+		JobState:                 models.JobStateTypeCompleted,
+		NewExecutionsNodes:       []string{cachedExec.NodeID},
+		NewExecutionDesiredState: models.ExecutionDesiredStateStopped,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Do(func(_ context.Context, plan *models.Plan) {
+		execution := plan.NewExecutions[0]
+		s.Equal(models.ExecutionStateCompleted, execution.ComputeState.StateType)
+		s.Equal(cachedExec.ID, execution.CachedFrom)
+		s.Equal(cachedExec.PublishedResult, execution.PublishedResult)
+		s.Equal(cachedJob.ID, plan.Event.Details["CachedJobID"])
+		s.Nil(plan.ResultCacheEntry)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ResultCache_ShouldRunWhenCachedJobIsGone() {
+	ctx := context.Background()
+	job, _, evaluation := mockCacheableJob()
+	cachedJob, _ := mockCachedJob(job)
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return([]models.Execution{}, nil)
+	s.jobStore.EXPECT().GetResultCacheEntry(gomock.Any(), job.Namespace, gomock.Any()).Return(
+		models.ResultCacheEntry{Namespace: job.Namespace, Key: "key", JobID: cachedJob.ID}, nil)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), cachedJob.ID).Return(models.Job{}, bacerrors.NewJobNotFound(cachedJob.ID))
+
+	nodeInfos := []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[0])}
+	s.mockNodeSelection(job, nodeInfos, 1)
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ResultCache_ShouldRecordCompletedJob() {
+	ctx := context.Background()
+	job, _, evaluation := mockCacheableJob()
+	execution := mock.ExecutionForJob(job)
+	execution.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	execution.PublishedResult = models.NewSpecConfig(models.StorageSourceIPFS).WithParam("CID", "QmTest")
+	key, err := job.ResultCacheKey()
+	s.Require().NoError(err)
+
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).
+		Return([]models.Execution{*execution}, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeCompleted,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Do(func(_ context.Context, plan *models.Plan) {
+		s.Equal(&models.ResultCacheEntry{Namespace: job.Namespace, Key: key, JobID: job.ID}, plan.ResultCacheEntry)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 func (s *BatchJobSchedulerTestSuite) mockNodeSelection(job *models.Job, nodeInfos []models.NodeInfo, desiredCount int) {
 	if len(nodeInfos) < desiredCount {
 		s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), job, desiredCount).Return(nil, orchestrator.ErrNotEnoughNodes{})
@@ -382,3 +1091,88 @@ func mockJob() (*models.Job, []models.Execution, *models.Evaluation) {
 	}
 	return job, executions, evaluation
 }
+
+// mockCacheableJob returns a batch job with a single execution whose results can be cached.
+func mockCacheableJob() (*models.Job, []models.Execution, *models.Evaluation) {
+	job, executions, evaluation := mockJob()
+	job.Count = 1
+	job.Task().Cacheable = true
+	return job, executions, evaluation
+}
+
+// mockCachedJob returns a completed job identical to the given job, with an execution that published its result.
+func mockCachedJob(job *models.Job) (*models.Job, *models.Execution) {
+	cachedJob := job.Copy()
+	cachedJob.ID = uuid.NewString()
+	cachedJob.State = models.NewJobState(models.JobStateTypeCompleted)
+	execution := mock.ExecutionForJob(cachedJob)
+	execution.NodeID = nodeIDs[1]
+	execution.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	execution.PublishedResult = models.NewSpecConfig(models.StorageSourceIPFS).WithParam("CID", "QmCached")
+	return cachedJob, execution
+}
+
+// mockArrayJob returns an array job with three indexes, and an execution for each index.
+func mockArrayJob() (*models.Job, []models.Execution, *models.Evaluation) {
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	job.Array = &models.JobArray{Start: 0, End: 2}
+
+	executions := make([]models.Execution, 3)
+	for i, e := range mock.Executions(job, len(executions)) {
+		e.NodeID = nodeIDs[i]
+		e.ArrayIndex = i
+		executions[i] = *e
+	}
+	return job, executions, evaluation
+}
+
+// mockPreemptionVictim returns a low priority job with an execution running on the given node.
+func mockPreemptionVictim(namespace string, nodeID string) (*models.Job, *models.Execution) {
+	job := mock.Job()
+	job.Namespace = namespace
+	job.State = models.NewJobState(models.JobStateTypeRunning)
+	execution := mock.ExecutionForJob(job)
+	execution.NodeID = nodeID
+	execution.ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	execution.DesiredState = models.NewExecutionDesiredState(models.ExecutionDesiredStateRunning)
+	return job, execution
+}
+
+// mockPreemptedJob returns a running job whose only execution was preempted by another job.
+func mockPreemptedJob(modifyTime time.Time) (*models.Job, []models.Execution, *models.Evaluation) {
+	job, _, evaluation := mockJob()
+	job.Count = 1
+	job.State = models.NewJobState(models.JobStateTypeRunning)
+	execution := mock.ExecutionForJob(job)
+	execution.NodeID = nodeIDs[0]
+	execution.ComputeState = models.NewExecutionState(models.ExecutionStateCancelled)
+	execution.PreemptedBy = uuid.NewString()
+	execution.ModifyTime = modifyTime.UnixNano()
+	evaluation.TriggeredBy = models.EvalTriggerPreemption
+	return job, []models.Execution{*execution}, evaluation
+}
+
+// fullNodeInfo returns a node without enough capacity to run a mock job besides another one.
+func fullNodeInfo(nodeID string) *models.NodeInfo {
+	return &models.NodeInfo{
+		NodeID:   nodeID,
+		NodeType: models.NodeTypeCompute,
+		ComputeNodeInfo: &models.ComputeNodeInfo{
+			MaxCapacity:       models.Resources{CPU: 0.15, Memory: 1 << 30},
+			AvailableCapacity: models.Resources{CPU: 0.05, Memory: 1 << 30},
+		},
+	}
+}
+
+// mockRetryJob returns a job with three failed attempts that last changed at the given time,
+// one of which was lost with its node, and a single execution left pending.
+func mockRetryJob(modifyTime time.Time) (*models.Job, []models.Execution, *models.Evaluation) {
+	job, executions, evaluation := mockJob()
+	executions[execBidAccepted].ComputeState = models.NewExecutionState(models.ExecutionStateFailed)
+	executions[execCanceled].FailureReason = models.FailureReasonNodeLost
+	for i := range executions {
+		executions[i].ModifyTime = modifyTime.UnixNano()
+	}
+	return job, executions, evaluation
+}
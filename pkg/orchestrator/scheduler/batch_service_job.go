@@ -2,12 +2,16 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/dustin/go-humanize"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -20,6 +24,12 @@ type BatchServiceJobSchedulerParams struct {
 	Planner       orchestrator.Planner
 	NodeSelector  orchestrator.NodeSelector
 	RetryStrategy orchestrator.RetryStrategy
+	// Preemption defines whether executions of lower priority jobs can be preempted
+	// to make room for the job's executions. Preemption is disabled by default.
+	Preemption orchestrator.PreemptionPolicy
+	// QuotaRetryDelay is the delay before retrying to schedule executions that were held back
+	// by the quota of their namespace. Defaults to DefaultQuotaRetryDelay.
+	QuotaRetryDelay time.Duration
 	// Clock is the clock used for time-based operations.
 	// If not provided, the system clock is used.
 	Clock clock.Clock
@@ -29,23 +39,30 @@ type BatchServiceJobSchedulerParams struct {
 // - batch jobs that run until completion on N number of nodes
 // - service jobs than run until stopped on N number of nodes
 type BatchServiceJobScheduler struct {
-	jobStore      jobstore.Store
-	planner       orchestrator.Planner
-	selector      orchestrator.NodeSelector
-	retryStrategy orchestrator.RetryStrategy
-	clock         clock.Clock
+	jobStore        jobstore.Store
+	planner         orchestrator.Planner
+	selector        orchestrator.NodeSelector
+	retryStrategy   orchestrator.RetryStrategy
+	preemption      orchestrator.PreemptionPolicy
+	quotaRetryDelay time.Duration
+	clock           clock.Clock
 }
 
 func NewBatchServiceJobScheduler(params BatchServiceJobSchedulerParams) *BatchServiceJobScheduler {
 	if params.Clock == nil {
 		params.Clock = clock.New()
 	}
+	if params.QuotaRetryDelay <= 0 {
+		params.QuotaRetryDelay = DefaultQuotaRetryDelay
+	}
 	return &BatchServiceJobScheduler{
-		jobStore:      params.JobStore,
-		planner:       params.Planner,
-		selector:      params.NodeSelector,
-		retryStrategy: params.RetryStrategy,
-		clock:         params.Clock,
+		jobStore:        params.JobStore,
+		planner:         params.Planner,
+		selector:        params.NodeSelector,
+		retryStrategy:   params.RetryStrategy,
+		preemption:      params.Preemption,
+		quotaRetryDelay: params.QuotaRetryDelay,
+		clock:           params.Clock,
 	}
 }
 
@@ -77,6 +94,30 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 		return b.planner.Process(ctx, plan)
 	}
 
+	// keep the job pending until all of its upstream dependencies have completed. The results of the
+	// dependencies are injected into the job on every evaluation, as the job is reloaded from the store,
+	// so that retries and replacements of its executions get them as well.
+	if job.HasDependencies() {
+		ready, err := b.resolveDependencies(ctx, &job, plan)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			return b.planner.Process(ctx, plan)
+		}
+	}
+
+	// complete cacheable jobs with the results of a previous identical job instead of running them
+	if job.IsCacheable() && len(existingExecs) == 0 {
+		cached, err := b.reuseCachedResults(ctx, &job, plan)
+		if err != nil {
+			return err
+		}
+		if cached {
+			return b.planner.Process(ctx, plan)
+		}
+	}
+
 	// Retrieve the info for all the nodes that have executions for this job
 	nodeInfos, err := existingNodeInfos(ctx, b.selector, nonTerminalExecs)
 	if err != nil {
@@ -91,23 +132,46 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 	allFailedExecs = allFailedExecs.union(lost)
 
+	// Migrate executions off nodes that are being drained, which are replaced rather than counted as failures.
+	// Executions of batch jobs still running on draining nodes keep counting towards the desired count until
+	// the drain deadline, while executions of long-running jobs are replaced right away, and stopped once their
+	// replacements are running.
+	nonTerminalExecs, migrating, _, drainDeadline, err := drainExecs(ctx, b.selector, b.clock.Now(), nonTerminalExecs, plan)
+	if err != nil {
+		return err
+	}
+	if !job.IsLongRunning() {
+		nonTerminalExecs = nonTerminalExecs.union(migrating)
+	}
+
 	// Mark executions that have exceeded their execution timeout as failed
 	// Only applicable for batch jobs and not service jobs.
+	timedOut := execSet{}
 	if !job.IsLongRunning() {
 		timeout := job.Task().Timeouts.GetExecutionTimeout()
 		expirationTime := b.clock.Now().Add(-timeout)
-		var timedOut execSet
 		nonTerminalExecs, timedOut = nonTerminalExecs.filterByExecutionTimeout(expirationTime)
 		timedOut.markStopped(orchestrator.ExecStoppedByExecutionTimeoutEvent(timeout), plan)
 		allFailedExecs = allFailedExecs.union(timedOut)
 	}
 
+	// keep track of the failures by class to apply the job's retry policy. Preempted and drained executions
+	// are not failures, even if they failed once stopped, and are replaced whatever classes the policy retries.
+	failuresByClass := map[string]execSet{
+		models.RetryOnExecutionError: existingExecs.excludeDisplaced().filterFailed(),
+		models.RetryOnNodeLost:       lost,
+		models.RetryOnTimeout:        timedOut,
+	}
+
 	// Calculate remaining job count
 	// Service jobs run until the user stops the job, and would be a bug if an execution is marked completed. So the desired
 	// remaining count equals the count specified in the job spec.
 	// Batch jobs on the other hand run until completion and the desired remaining count excludes the completed executions
+	// Array jobs run until an execution of each array index has completed.
 	desiredRemainingCount := job.Count
-	if job.Type == models.JobTypeBatch {
+	if job.IsArray() {
+		desiredRemainingCount = math.Max(0, job.Array.Size()-existingExecs.countCompletedArrayIndexes())
+	} else if job.Type == models.JobTypeBatch {
 		desiredRemainingCount = math.Max(0, job.Count-existingExecs.countCompleted())
 	}
 
@@ -118,16 +182,34 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 
 	// create new executions if needed
 	remainingExecutionCount := desiredRemainingCount - execsByApprovalStatus.activeCount()
+	var missingArrayIndexes []int
+	if job.IsArray() {
+		missingArrayIndexes = existingExecs.filterByState(models.ExecutionStateCompleted).
+			union(execsByApprovalStatus.active()).
+			missingArrayIndexes(job.Array)
+		remainingExecutionCount = len(missingArrayIndexes)
+	}
 	if remainingExecutionCount > 0 {
 		var placementErr error
+		var retryDelayed bool
 		if len(allFailedExecs) > 0 && !b.retryStrategy.ShouldRetry(ctx, orchestrator.RetryRequest{JobID: job.ID}) {
 			placementErr = fmt.Errorf("exceeded max retries for job %s", job.ID)
 			plan.Event = orchestrator.JobExhaustedRetriesEvent()
-		} else {
-			_, placementErr = b.createMissingExecs(ctx, remainingExecutionCount, &job, plan)
+		} else if retryDelayed, placementErr = b.applyRetryPolicy(ctx, evaluation, &job, existingExecs, failuresByClass, plan); placementErr == nil && !retryDelayed {
+			// hold back the executions that would exceed the quota of the job's namespace
+			allowedCount, err := b.allowedExecutions(ctx, evaluation, &job, remainingExecutionCount, plan)
+			if err != nil {
+				return err
+			}
+			if allowedCount > 0 {
+				if job.IsArray() {
+					missingArrayIndexes = missingArrayIndexes[:allowedCount]
+				}
+				_, placementErr = b.createMissingExecs(ctx, allowedCount, missingArrayIndexes, &job, existingExecs, plan)
+			}
 		}
 		if placementErr != nil {
-			b.handleFailure(nonTerminalExecs, allFailedExecs, plan, placementErr)
+			b.handleFailure(nonTerminalExecs.union(migrating), allFailedExecs, plan, placementErr)
 			return b.planner.Process(ctx, plan)
 		}
 	}
@@ -136,38 +218,82 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	_, overSubscriptions := execsByApprovalStatus.running.filterByOverSubscriptions(desiredRemainingCount)
 	overSubscriptions.markStopped(orchestrator.ExecStoppedByOversubscriptionEvent(), plan)
 
+	// stop the executions of long-running jobs on draining nodes once their replacements are running,
+	// or wait for the drain deadline to stop them
+	if len(migrating) > 0 {
+		if job.IsLongRunning() && len(execsByApprovalStatus.running) >= desiredRemainingCount {
+			migrating.markDrained(plan)
+		} else if err = waitForDrainDeadline(ctx, b.jobStore, evaluation, &job, drainDeadline, plan); err != nil {
+			return err
+		}
+	}
+
 	// Check the job's state and update it accordingly.
 	if desiredRemainingCount <= 0 {
 		// If there are no remaining tasks to be done, mark the job as completed.
 		plan.MarkJobCompleted()
+		if err = b.cacheResults(&job, existingExecs, plan); err != nil {
+			return err
+		}
 	}
 
 	plan.MarkJobRunningIfEligible()
 	return b.planner.Process(ctx, plan)
 }
 
-func (b *BatchServiceJobScheduler) createMissingExecs(
-	ctx context.Context, remainingExecutionCount int, job *models.Job, plan *models.Plan) (execSet, error) {
+// createMissingExecs creates the missing executions of the job. For array jobs, an execution is created
+// for each of the given array indexes, with the job's spec rendered for that index. Executions of checkpointed
+// jobs resume from the latest checkpoint taken of the existing executions of the same array index.
+func (b *BatchServiceJobScheduler) createMissingExecs(ctx context.Context, remainingExecutionCount int,
+	arrayIndexes []int, job *models.Job, existingExecs execSet, plan *models.Plan) (execSet, error) {
 	newExecs := execSet{}
+	// placementJob is the job the executions are placed for, with the largest memory any of them was resized to
+	placementJob := job
 	for i := 0; i < remainingExecutionCount; i++ {
+		execJob := job
+		arrayIndex := 0
+		var err error
+		if job.IsArray() {
+			arrayIndex = arrayIndexes[i]
+			if execJob, err = job.ForArrayIndex(arrayIndex); err != nil {
+				plan.Event = models.EventFromError(orchestrator.EventTopicJobScheduling, err)
+				return newExecs, err
+			}
+		}
+		if execJob, err = resizeForOOM(execJob, arrayIndex, existingExecs); err != nil {
+			plan.Event = models.EventFromError(orchestrator.EventTopicJobScheduling, err)
+			return newExecs, err
+		}
+		placementJob = largerMemory(placementJob, execJob)
 		execution := &models.Execution{
 			JobID:        job.ID,
-			Job:          job,
+			Job:          execJob,
+			ArrayIndex:   arrayIndex,
 			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
 			EvalID:       plan.EvalID,
 			Namespace:    job.Namespace,
 			ComputeState: models.NewExecutionState(models.ExecutionStateNew),
 			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStatePending),
 		}
+		if job.Task().Checkpoint != nil {
+			execution.RestoreFrom = existingExecs.latestCheckpoint(arrayIndex).Copy()
+		}
 		execution.Normalize()
 		newExecs[execution.ID] = execution
 	}
 	if len(newExecs) > 0 {
-		err := b.placeExecs(ctx, newExecs, job)
+		if placementJob != job {
+			log.Ctx(ctx).Info().Msgf("increased the memory of job %s to %s after its executions were OOM killed",
+				job.ID, humanize.Bytes(taskMemory(placementJob)))
+		}
+		selectedNodes, err := b.placeExecs(ctx, newExecs, placementJob)
 		if err != nil {
 			plan.Event = models.EventFromError(orchestrator.EventTopicJobScheduling, err)
 			return newExecs, err
 		}
+		if err = b.preemptForExecs(ctx, placementJob, newExecs, selectedNodes, plan); err != nil {
+			return newExecs, err
+		}
 	}
 	for _, exec := range newExecs {
 		plan.AppendExecution(exec)
@@ -175,20 +301,163 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 	return newExecs, nil
 }
 
-// placeExecs places the executions
-func (b *BatchServiceJobScheduler) placeExecs(ctx context.Context, execs execSet, job *models.Job) error {
+// placeExecs places the executions, and returns the nodes they were placed on
+func (b *BatchServiceJobScheduler) placeExecs(ctx context.Context, execs execSet, job *models.Job) ([]models.NodeInfo, error) {
 	if len(execs) > 0 {
 		selectedNodes, err := b.selector.TopMatchingNodes(ctx, job, len(execs))
 		if err != nil {
-			return err
+			return nil, err
 		}
 		i := 0
 		for _, exec := range execs {
 			exec.NodeID = selectedNodes[i].ID()
 			i++
 		}
+		return selectedNodes, nil
+	}
+	return nil, nil
+}
+
+// applyRetryPolicy checks the job's retry policy before creating executions to replace failed ones.
+// It returns an error if the job should not be retried, and true if retrying the job is delayed
+// to a follow-up evaluation. Only executions that failed, timed out or were lost count as attempts,
+// while preempted executions are replaced without using up the job's attempts.
+func (b *BatchServiceJobScheduler) applyRetryPolicy(ctx context.Context, evaluation *models.Evaluation,
+	job *models.Job, existingExecs execSet, failuresByClass map[string]execSet, plan *models.Plan) (bool, error) {
+	policy := job.RetryPolicy
+	if policy == nil {
+		return false, nil
+	}
+
+	for class, failures := range failuresByClass {
+		if len(failures) > 0 && !policy.ShouldRetryOn(class) {
+			plan.Event = orchestrator.JobNotRetryableEvent(class)
+			return false, fmt.Errorf("retry policy of job %s does not retry %s failures", job.ID, class)
+		}
+	}
+
+	// failed attempts of the job, including the executions that were just lost or timed out,
+	// which are only marked as such once the plan is processed
+	failedAttempts := existingExecs.filterFailedAttempts().
+		union(failuresByClass[models.RetryOnNodeLost]).
+		union(failuresByClass[models.RetryOnTimeout])
+	if len(failedAttempts) == 0 {
+		return false, nil
+	}
+
+	if !policy.HasAttemptsLeft(len(failedAttempts)) {
+		plan.Event = orchestrator.JobExhaustedRetryPolicyEvent(len(failedAttempts), policy)
+		return false, fmt.Errorf("exceeded max attempts for job %s", job.ID)
+	}
+
+	// executions that were just lost or timed out are considered to have failed now
+	now := b.clock.Now()
+	lastFailure := time.Unix(0, 0)
+	if len(failuresByClass[models.RetryOnNodeLost])+len(failuresByClass[models.RetryOnTimeout]) > 0 {
+		lastFailure = now
+	}
+	for _, exec := range failedAttempts {
+		if exec.GetModifyTime().After(lastFailure) {
+			lastFailure = exec.GetModifyTime()
+		}
+	}
+
+	attempt := len(failedAttempts) + 1
+	nextRetry := lastFailure.Add(policy.Delay(len(failedAttempts)))
+	if nextRetry.After(now) {
+		// the retry is already scheduled by an earlier evaluation of the job
+		pending, err := hasPendingEvaluation(ctx, b.jobStore, evaluation, job, models.EvalTriggerRetryDelay)
+		if err != nil || pending {
+			return true, err
+		}
+		eval := models.NewEvaluation().
+			WithJobID(job.ID).
+			WithNamespace(job.Namespace).
+			WithTriggeredBy(models.EvalTriggerRetryDelay).
+			WithType(job.Type).
+			WithPriority(job.Priority).
+			WithComment(fmt.Sprintf("retry attempt %d of job %s", attempt, job.ID)).
+			WithWaitUntil(nextRetry)
+		plan.MarkJobRetryDelayed(eval, orchestrator.JobRetryDelayedEvent(attempt, policy, nextRetry))
+		return true, nil
+	}
+	plan.Event = orchestrator.JobRetryAttemptEvent(attempt, policy)
+	return false, nil
+}
+
+// resolveDependencies checks the state of the job's upstream dependencies. It returns true if all
+// dependencies have completed, in which case their published results are injected as input sources
+// of the job's tasks. If any dependency failed or was stopped, the job is marked as failed in the plan.
+func (b *BatchServiceJobScheduler) resolveDependencies(ctx context.Context, job *models.Job, plan *models.Plan) (bool, error) {
+	var inputs []*models.InputSource
+	for _, dep := range job.DependsOn {
+		upstream, err := b.jobStore.GetJob(ctx, dep.JobID)
+		if err != nil {
+			var notFound *bacerrors.JobNotFound
+			if !errors.As(err, &notFound) {
+				return false, fmt.Errorf("failed to retrieve upstream job %s: %w", dep.JobID, err)
+			}
+			plan.MarkJobFailed(orchestrator.JobDependencyFailedEvent(dep.JobID, models.JobStateTypeUndefined))
+			return false, nil
+		}
+
+		switch upstream.State.StateType {
+		case models.JobStateTypeCompleted:
+		case models.JobStateTypeFailed, models.JobStateTypeStopped:
+			plan.MarkJobFailed(orchestrator.JobDependencyFailedEvent(dep.JobID, upstream.State.StateType))
+			return false, nil
+		default:
+			log.Ctx(ctx).Debug().Msgf("job %s is waiting for upstream job %s in state %s",
+				job.ID, dep.JobID, upstream.State.StateType)
+			return false, nil
+		}
+
+		if dep.Target == "" {
+			continue
+		}
+		depInputs, err := b.dependencyInputs(ctx, dep)
+		if err != nil {
+			return false, err
+		}
+		inputs = append(inputs, depInputs...)
+	}
+
+	for _, task := range job.Tasks {
+		task.InputSources = append(task.InputSources, models.CopySlice(inputs)...)
+	}
+	return true, nil
+}
+
+// dependencyInputs returns the published results of a completed upstream job as input sources
+// mounted at the dependency's target. If the upstream job published more than one result, each
+// result is mounted in a numbered subdirectory of the target.
+func (b *BatchServiceJobScheduler) dependencyInputs(ctx context.Context, dep *models.JobDependency) ([]*models.InputSource, error) {
+	executions, err := b.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: dep.JobID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve executions of upstream job %s: %w", dep.JobID, err)
+	}
+
+	var results []*models.SpecConfig
+	for _, exec := range execSetFromSliceOfValues(executions).filterByState(models.ExecutionStateCompleted).ordered() {
+		if exec.PublishedResult == nil || exec.PublishedResult.Type == "" {
+			continue
+		}
+		results = append(results, exec.PublishedResult)
+	}
+
+	inputs := make([]*models.InputSource, 0, len(results))
+	for i, result := range results {
+		target := dep.Target
+		if len(results) > 1 {
+			target = fmt.Sprintf("%s/%d", dep.Target, i)
+		}
+		inputs = append(inputs, &models.InputSource{
+			Source: result.Copy(),
+			Alias:  fmt.Sprintf("%s-%d", dep.JobID, i),
+			Target: target,
+		})
 	}
-	return nil
+	return inputs, nil
 }
 
 func (b *BatchServiceJobScheduler) handleFailure(nonTerminalExecs execSet, failed execSet, plan *models.Plan, err error) {
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+// reuseCachedResults completes a cacheable job with the published results of the previous job with the same
+// result cache key in its namespace, if that job still has enough of them. The job's executions are created
+// completed, reusing the published results and outputs of the cached executions, and never run on a node.
+// It returns true if the job was completed from the cache.
+func (b *BatchServiceJobScheduler) reuseCachedResults(ctx context.Context, job *models.Job, plan *models.Plan) (bool, error) {
+	key, err := job.ResultCacheKey()
+	if err != nil {
+		return false, err
+	}
+	entry, err := b.jobStore.GetResultCacheEntry(ctx, job.Namespace, key)
+	if err != nil {
+		if errors.As(err, new(jobstore.ErrResultCacheEntryNotFound)) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to retrieve result cache entry of job %s: %w", job.ID, err)
+	}
+
+	cached, err := b.cachedExecs(ctx, &entry)
+	if err != nil {
+		return false, err
+	}
+	if len(cached) < job.Count {
+		log.Ctx(ctx).Debug().Msgf("ignoring cached results of job %s, as it has %d published results and job %s needs %d",
+			entry.JobID, len(cached), job.ID, job.Count)
+		return false, nil
+	}
+
+	executions := make([]*models.Execution, 0, job.Count)
+	for _, source := range cached[:job.Count] {
+		execution := &models.Execution{
+			JobID:           job.ID,
+			Job:             job,
+			ID:              idgen.ExecutionIDPrefix + uuid.NewString(),
+			EvalID:          plan.EvalID,
+			Namespace:       job.Namespace,
+			NodeID:          source.NodeID,
+			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
+			DesiredState:    models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped),
+			PublishedResult: source.PublishedResult.Copy(),
+			RunOutput:       source.RunOutput,
+			CachedFrom:      source.ID,
+		}
+		execution.Normalize()
+		executions = append(executions, execution)
+	}
+	log.Ctx(ctx).Info().Msgf("completing job %s with the cached results of job %s", job.ID, entry.JobID)
+	plan.MarkJobCompletedFromCache(executions, orchestrator.JobResultCacheHitEvent(&entry))
+	return true, nil
+}
+
+// cachedExecs returns the completed executions with a published result of the job of a result cache entry.
+// No executions are returned if the job no longer exists, such as after being removed by the retention policy.
+func (b *BatchServiceJobScheduler) cachedExecs(ctx context.Context, entry *models.ResultCacheEntry) ([]*models.Execution, error) {
+	cachedJob, err := b.jobStore.GetJob(ctx, entry.JobID)
+	if err != nil {
+		var notFound *bacerrors.JobNotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve cached job %s: %w", entry.JobID, err)
+	}
+	if cachedJob.State.StateType != models.JobStateTypeCompleted {
+		return nil, nil
+	}
+
+	executions, err := b.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: entry.JobID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve executions of cached job %s: %w", entry.JobID, err)
+	}
+	var cached []*models.Execution
+	for _, exec := range execSetFromSliceOfValues(executions).filterByState(models.ExecutionStateCompleted).ordered() {
+		if exec.PublishedResult == nil || exec.PublishedResult.Type == "" {
+			continue
+		}
+		cached = append(cached, exec)
+	}
+	return cached, nil
+}
+
+// cacheResults records a completed cacheable job as the job whose published results are reused by later
+// identical jobs. Jobs that themselves completed from the cache, or whose executions did not all publish
+// their results, are not recorded.
+func (b *BatchServiceJobScheduler) cacheResults(job *models.Job, existingExecs execSet, plan *models.Plan) error {
+	if !job.IsCacheable() {
+		return nil
+	}
+	completed := existingExecs.filterByState(models.ExecutionStateCompleted).ordered()
+	if len(completed) == 0 {
+		return nil
+	}
+	for _, exec := range completed {
+		if exec.CachedFrom != "" || exec.PublishedResult == nil || exec.PublishedResult.Type == "" {
+			return nil
+		}
+	}
+
+	// the key is computed from the job the executions ran, which includes the results injected by its dependencies
+	key, err := completed[0].Job.ResultCacheKey()
+	if err != nil {
+		return err
+	}
+	plan.ResultCacheEntry = &models.ResultCacheEntry{
+		Namespace: job.Namespace,
+		Key:       key,
+		JobID:     job.ID,
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -69,9 +70,17 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 	}
 
 	// Mark executions that are running on nodes that are not healthy as failed
-	_, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
+	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 
+	// Stop executions on nodes being drained right away. Daemon jobs run on every matching node,
+	// so their executions are not moved to other nodes and have nothing to wait for.
+	_, migrating, _, _, err := drainExecs(ctx, b.nodeSelector, time.Now(), nonTerminalExecs, plan)
+	if err != nil {
+		return err
+	}
+	migrating.markDrained(plan)
+
 	// Look for new matching nodes and create new executions every time we evaluate the job
 	_, err = b.createMissingExecs(ctx, &job, plan, existingExecs)
 	if err != nil {
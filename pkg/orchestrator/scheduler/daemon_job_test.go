@@ -5,6 +5,7 @@ package scheduler
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -22,6 +23,8 @@ type DaemonJobSchedulerTestSuite struct {
 	planner      *orchestrator.MockPlanner
 	nodeSelector *orchestrator.MockNodeSelector
 	scheduler    *DaemonJobScheduler
+	// draining is the cordon of the nodes being drained, keyed by node ID
+	draining map[string]*models.NodeCordon
 }
 
 func (s *DaemonJobSchedulerTestSuite) SetupTest() {
@@ -35,6 +38,9 @@ func (s *DaemonJobSchedulerTestSuite) SetupTest() {
 		Planner:      s.planner,
 		NodeSelector: s.nodeSelector,
 	})
+	s.draining = nil
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).DoAndReturn(
+		func(context.Context) (map[string]*models.NodeCordon, error) { return s.draining, nil }).AnyTimes()
 }
 
 func TestDaemonJobSchedulerTestSuite(t *testing.T) {
@@ -176,6 +182,34 @@ func (s *DaemonJobSchedulerTestSuite) TestProcessFail_NoMatchingNodes() {
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *DaemonJobSchedulerTestSuite) TestProcess_Drain_ShouldStopExecutionsRightAway() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	s.draining = map[string]*models.NodeCordon{
+		executions[0].NodeID: {Drain: true, DrainDeadline: time.Now().Add(time.Hour)},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[0].NodeID),
+		*fakeNodeInfo(s.T(), executions[1].NodeID),
+	}, nil)
+	// the draining node no longer matches the job
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[1].NodeID),
+	}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// the running execution is stopped without waiting for the drain deadline, and not moved
+		s.Empty(plan.NewExecutions)
+		s.Require().Len(plan.UpdatedExecutions, 1)
+		s.Require().Contains(plan.UpdatedExecutions, executions[0].ID)
+		s.True(plan.UpdatedExecutions[executions[0].ID].Drained)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 func mockDaemonJob() (*models.Job, []models.Execution, *models.Evaluation) {
 	job := mock.Job()
 
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// drainExecs handles the executions of the job that are on nodes being drained. Executions that are not
+// running yet, or are still running after the drain deadline, are stopped without counting as failed attempts.
+// It returns the executions that are not on draining nodes, the ones still running on draining nodes,
+// the ones it stopped, and the earliest drain deadline of the executions still running.
+func drainExecs(ctx context.Context, selector orchestrator.NodeSelector, now time.Time, execs execSet,
+	plan *models.Plan) (remaining, migrating, drained execSet, deadline time.Time, err error) {
+	draining, err := selector.DrainingNodes(ctx)
+	if err != nil {
+		return nil, nil, nil, time.Time{}, fmt.Errorf("failed to list draining nodes: %w", err)
+	}
+	remaining, migrating, drained = execs.filterByNodeDrain(draining, now)
+	drained.markDrained(plan)
+	return remaining, migrating, drained, migrating.earliestDrainDeadline(draining), nil
+}
+
+// waitForDrainDeadline adds a follow-up evaluation to the plan to stop the executions still running on
+// draining nodes at the drain deadline, unless one is already pending for the job.
+func waitForDrainDeadline(ctx context.Context, jobStore jobstore.Store,
+	evaluation *models.Evaluation, job *models.Job, deadline time.Time, plan *models.Plan) error {
+	pending, err := hasPendingEvaluation(ctx, jobStore, evaluation, job, models.EvalTriggerNodeDrain)
+	if err != nil || pending {
+		return err
+	}
+	plan.AppendEvaluation(models.NewEvaluation().
+		WithJobID(job.ID).
+		WithNamespace(job.Namespace).
+		WithTriggeredBy(models.EvalTriggerNodeDrain).
+		WithType(job.Type).
+		WithPriority(job.Priority).
+		WithComment(fmt.Sprintf("drain deadline of job %s", job.ID)).
+		WithWaitUntil(deadline))
+	return nil
+}
+
+// earliestDrainDeadline returns the earliest drain deadline of the nodes of the executions
+func (set execSet) earliestDrainDeadline(draining map[string]*models.NodeCordon) time.Time {
+	var deadline time.Time
+	for _, exec := range set {
+		cordon, ok := draining[exec.NodeID]
+		if !ok {
+			continue
+		}
+		if deadline.IsZero() || cordon.DrainDeadline.Before(deadline) {
+			deadline = cordon.DrainDeadline
+		}
+	}
+	return deadline
+}
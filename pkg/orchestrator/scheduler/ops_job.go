@@ -86,6 +86,18 @@ func (b *OpsJobScheduler) Process(ctx context.Context, evaluation *models.Evalua
 	lost.markStopped(orchestrator.ExecStoppedByNodeUnhealthyEvent(), plan)
 	allFailedExecs = allFailedExecs.union(lost)
 
+	// Stop executions on nodes being drained. Ops jobs are not moved to other nodes, so running executions are
+	// given until the drain deadline to complete, and the job fails if any had to be stopped as the operation
+	// did not complete on every node.
+	allFailedExecs = allFailedExecs.union(existingExecs.filterDrained())
+	nonTerminalExecs, migrating, drained, drainDeadline, err :=
+		drainExecs(ctx, b.selector, b.clock.Now(), nonTerminalExecs, plan)
+	if err != nil {
+		return err
+	}
+	nonTerminalExecs = nonTerminalExecs.union(migrating)
+	allFailedExecs = allFailedExecs.union(drained)
+
 	// Mark executions that have exceeded their execution timeout as failed
 	timeout := job.Task().Timeouts.GetExecutionTimeout()
 	expirationTime := b.clock.Now().Add(-timeout)
@@ -113,6 +125,13 @@ func (b *OpsJobScheduler) Process(ctx context.Context, evaluation *models.Evalua
 		}
 	}
 
+	// wait for the drain deadline to stop the executions still running on draining nodes
+	if len(migrating) > 0 {
+		if err = waitForDrainDeadline(ctx, b.jobStore, evaluation, &job, drainDeadline, plan); err != nil {
+			return err
+		}
+	}
+
 	plan.MarkJobRunningIfEligible()
 	return b.planner.Process(ctx, plan)
 }
@@ -25,6 +25,8 @@ type OpsJobSchedulerTestSuite struct {
 	planner      *orchestrator.MockPlanner
 	nodeSelector *orchestrator.MockNodeSelector
 	scheduler    *OpsJobScheduler
+	// draining is the cordon of the nodes being drained, keyed by node ID
+	draining map[string]*models.NodeCordon
 }
 
 func (s *OpsJobSchedulerTestSuite) SetupTest() {
@@ -38,7 +40,11 @@ func (s *OpsJobSchedulerTestSuite) SetupTest() {
 		JobStore:     s.jobStore,
 		Planner:      s.planner,
 		NodeSelector: s.nodeSelector,
+		Clock:        s.clock,
 	})
+	s.draining = nil
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).DoAndReturn(
+		func(context.Context) (map[string]*models.NodeCordon, error) { return s.draining, nil }).AnyTimes()
 
 	// we only want to freeze time to have more deterministic tests.
 	// It doesn't matter what time it is as we are using relative time to this value
@@ -204,6 +210,49 @@ func (s *OpsJobSchedulerTestSuite) TestProcess_ShouldStopExpiredExecutions() {
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *OpsJobSchedulerTestSuite) TestProcess_Drain_ShouldWaitForDeadline() {
+	ctx := context.Background()
+	job, executions, evaluation := mockOpsJob()
+	deadline := s.clock.Now().Add(time.Hour)
+	s.draining = map[string]*models.NodeCordon{
+		executions[0].NodeID: {Drain: true, DrainDeadline: deadline},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{*fakeNodeInfo(s.T(), executions[0].NodeID)}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// the running execution is given until the deadline to complete
+		s.Empty(plan.UpdatedExecutions)
+		s.NotEqual(models.JobStateTypeFailed, plan.DesiredJobState)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(models.EvalTriggerNodeDrain, plan.NewEvaluations[0].TriggeredBy)
+		s.Equal(deadline.UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *OpsJobSchedulerTestSuite) TestProcess_Drain_ShouldFailJobAfterDeadline() {
+	ctx := context.Background()
+	job, executions, evaluation := mockOpsJob()
+	s.draining = map[string]*models.NodeCordon{
+		executions[0].NodeID: {Drain: true, DrainDeadline: s.clock.Now().Add(-time.Second)},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{*fakeNodeInfo(s.T(), executions[0].NodeID)}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// the operation did not complete on the drained node
+		s.Require().Contains(plan.UpdatedExecutions, executions[0].ID)
+		s.True(plan.UpdatedExecutions[executions[0].ID].Drained)
+		s.Equal(models.JobStateTypeFailed, plan.DesiredJobState)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 func (s *OpsJobSchedulerTestSuite) mockNodeSelection(job *models.Job, nodeInfos []models.NodeInfo) {
 	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job).Return(nodeInfos, nil)
 }
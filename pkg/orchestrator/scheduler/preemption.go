@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// preemptionCandidate is a running execution of a lower priority job that can be preempted.
+type preemptionCandidate struct {
+	execution *models.Execution
+	job       *models.Job
+	resources *models.Resources
+}
+
+// preemptForExecs stops running executions of lower priority jobs on the nodes the new executions
+// were placed on, when those nodes do not have enough available capacity to run them.
+// The available capacity of a node is its maximum capacity less the resources of the active executions
+// the orchestrator placed on it, which accounts for executions that the node has not reported yet.
+// Executions on a node are only preempted if doing so frees enough capacity for the new executions,
+// in which case the lowest priority and most recently started executions are preempted first.
+// A follow-up evaluation is created for each preempted job to re-queue its executions.
+func (b *BatchServiceJobScheduler) preemptForExecs(
+	ctx context.Context, job *models.Job, newExecs execSet, nodes []models.NodeInfo, plan *models.Plan) error {
+	if !b.preemption.Enabled || len(newExecs) == 0 {
+		return nil
+	}
+	required, err := job.TotalResources()
+	if err != nil {
+		return fmt.Errorf("failed to convert job resources config to resources: %w", err)
+	}
+	if required.IsZero() {
+		return nil
+	}
+
+	// resources required on each node by the new executions
+	requiredByNode := make(map[string]*models.Resources)
+	for _, exec := range newExecs {
+		if _, ok := requiredByNode[exec.NodeID]; !ok {
+			requiredByNode[exec.NodeID] = &models.Resources{}
+		}
+		requiredByNode[exec.NodeID] = requiredByNode[exec.NodeID].Add(*required)
+	}
+
+	lookups := &preemptionLookups{jobs: make(map[string]*models.Job), nonPreemptible: make(map[string]bool)}
+	preemptedJobs := make(map[string]*models.Job)
+	for _, node := range nodes {
+		nodeRequired, ok := requiredByNode[node.ID()]
+		if !ok || node.ComputeNodeInfo == nil || node.ComputeNodeInfo.MaxCapacity.IsZero() {
+			continue
+		}
+		used, candidates, err := b.nodeUsage(ctx, job, node.ID(), lookups, plan)
+		if err != nil {
+			return err
+		}
+		available := node.ComputeNodeInfo.MaxCapacity.Sub(*used)
+		if nodeRequired.LessThanEq(*available) {
+			continue
+		}
+		victims := selectPreemptionVictims(candidates, *available, *nodeRequired)
+		for _, victim := range victims {
+			log.Ctx(ctx).Debug().Msgf("preempting execution %s of job %s on node %s",
+				victim.execution.ID, victim.job.ID, node.ID())
+			plan.AppendPreemptedExecution(victim.execution, orchestrator.ExecStoppedByPreemptionEvent(job))
+			preemptedJobs[victim.job.ID] = victim.job
+		}
+	}
+
+	for _, preempted := range preemptedJobs {
+		plan.AppendEvaluation(models.NewEvaluation().
+			WithJobID(preempted.ID).
+			WithNamespace(preempted.Namespace).
+			WithTriggeredBy(models.EvalTriggerPreemption).
+			WithType(preempted.Type).
+			WithPriority(preempted.Priority).
+			WithComment(fmt.Sprintf("executions preempted by job %s", job.ID)))
+	}
+	return nil
+}
+
+// preemptionLookups caches the jobs and namespace settings read while looking for executions to preempt,
+// as the executions on different nodes often belong to the same jobs.
+type preemptionLookups struct {
+	jobs           map[string]*models.Job
+	nonPreemptible map[string]bool
+}
+
+// nodeUsage returns the resources allocated to the active executions on a node, and those of them that
+// can be preempted by the job: the executions of batch and service jobs with a lower priority in
+// namespaces that are not protected from preemption. Executions that are stopping, including the ones
+// the plan already stops, are not using the node's capacity anymore.
+func (b *BatchServiceJobScheduler) nodeUsage(ctx context.Context, job *models.Job, nodeID string,
+	lookups *preemptionLookups, plan *models.Plan) (*models.Resources, []preemptionCandidate, error) {
+	executions, err := b.jobStore.GetActiveExecutionsOnNode(ctx, nodeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve executions on node %s: %w", nodeID, err)
+	}
+
+	used := &models.Resources{}
+	var candidates []preemptionCandidate
+	for i := range executions {
+		exec := &executions[i]
+		if exec.DesiredState.StateType == models.ExecutionDesiredStateStopped {
+			continue
+		}
+		if update, ok := plan.UpdatedExecutions[exec.ID]; ok && update.DesiredState == models.ExecutionDesiredStateStopped {
+			continue
+		}
+		other, err := lookups.job(ctx, b.jobStore, exec.JobID)
+		if err != nil {
+			return nil, nil, err
+		}
+		resources, err := other.TotalResources()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert resources config of job %s to resources: %w", other.ID, err)
+		}
+		used = used.Add(*resources)
+
+		if other.ID == job.ID || other.Priority >= job.Priority ||
+			(other.Type != models.JobTypeBatch && other.Type != models.JobTypeService) {
+			continue
+		}
+		nonPreemptible, err := lookups.isNonPreemptible(ctx, b.jobStore, other.Namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !nonPreemptible {
+			candidates = append(candidates, preemptionCandidate{execution: exec, job: other, resources: resources})
+		}
+	}
+	return used, candidates, nil
+}
+
+// job returns the job with the given ID, reading it from the store the first time it is needed.
+func (l *preemptionLookups) job(ctx context.Context, store jobstore.Store, jobID string) (*models.Job, error) {
+	if job, ok := l.jobs[jobID]; ok {
+		return job, nil
+	}
+	job, err := store.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve job %s: %w", jobID, err)
+	}
+	l.jobs[jobID] = &job
+	return &job, nil
+}
+
+// isNonPreemptible returns true if the quota of the namespace protects its executions from preemption.
+func (l *preemptionLookups) isNonPreemptible(ctx context.Context, store jobstore.Store, namespace string) (bool, error) {
+	if nonPreemptible, ok := l.nonPreemptible[namespace]; ok {
+		return nonPreemptible, nil
+	}
+	quota, err := store.GetNamespaceQuota(ctx, namespace)
+	if err != nil && !errors.As(err, new(jobstore.ErrNamespaceQuotaNotFound)) {
+		return false, fmt.Errorf("failed to retrieve quota of namespace %s: %w", namespace, err)
+	}
+	l.nonPreemptible[namespace] = err == nil && quota.NonPreemptible
+	return l.nonPreemptible[namespace], nil
+}
+
+// selectPreemptionVictims returns the candidates to preempt so that the available capacity of a node
+// covers the required resources, or nil if preempting all candidates would not be enough.
+// Candidates of the lowest priority jobs are selected first, and then the most recently created ones,
+// to minimize the amount of work lost.
+func selectPreemptionVictims(
+	candidates []preemptionCandidate, available models.Resources, required models.Resources) []preemptionCandidate {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].job.Priority != candidates[j].job.Priority {
+			return candidates[i].job.Priority < candidates[j].job.Priority
+		}
+		return candidates[i].execution.CreateTime > candidates[j].execution.CreateTime
+	})
+
+	freed := &available
+	var victims []preemptionCandidate
+	for _, candidate := range candidates {
+		if required.LessThanEq(*freed) {
+			break
+		}
+		freed = freed.Add(*candidate.resources)
+		victims = append(victims, candidate)
+	}
+	if !required.LessThanEq(*freed) {
+		return nil
+	}
+	return victims
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// DefaultQuotaRetryDelay is the default delay before retrying to schedule executions
+// that were held back by the quota of their namespace.
+const DefaultQuotaRetryDelay = 30 * time.Second
+
+// allowedExecutions returns how many of the requested new executions of the job the quota of its namespace allows.
+// If some executions are held back, a follow-up evaluation is added to the plan to try again after the
+// quota retry delay, unless one is already pending for the job.
+func (b *BatchServiceJobScheduler) allowedExecutions(
+	ctx context.Context, evaluation *models.Evaluation, job *models.Job, requested int, plan *models.Plan) (int, error) {
+	quota, err := b.jobStore.GetNamespaceQuota(ctx, job.Namespace)
+	if err != nil {
+		if errors.As(err, new(jobstore.ErrNamespaceQuotaNotFound)) {
+			return requested, nil
+		}
+		return 0, fmt.Errorf("failed to retrieve quota of namespace %s: %w", job.Namespace, err)
+	}
+	if quota.IsUnlimited() {
+		return requested, nil
+	}
+
+	required, err := job.TotalResources()
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert job resources config to resources: %w", err)
+	}
+	// executions that are stopped by this plan no longer count towards the quota
+	stopping := func(exec *models.Execution) bool {
+		update, ok := plan.UpdatedExecutions[exec.ID]
+		return ok && update.DesiredState == models.ExecutionDesiredStateStopped
+	}
+	usage, err := orchestrator.GetNamespaceUsage(ctx, b.jobStore, job.Namespace, stopping)
+	if err != nil {
+		return 0, err
+	}
+	namespaceUsage := models.NamespaceUsage{Namespace: job.Namespace}
+	if u, ok := usage[job.Namespace]; ok {
+		namespaceUsage = *u
+	}
+
+	allowed := quota.AllowedExecutions(namespaceUsage, *required, requested)
+	if allowed == requested {
+		return allowed, nil
+	}
+
+	log.Ctx(ctx).Debug().Msgf("namespace %s quota allows %d of %d new executions of job %s",
+		job.Namespace, allowed, requested, job.ID)
+	waiting, err := hasPendingEvaluation(ctx, b.jobStore, evaluation, job, models.EvalTriggerQuotaWait)
+	if err != nil || waiting {
+		return allowed, err
+	}
+	nextAttempt := b.clock.Now().Add(b.quotaRetryDelay)
+	plan.AppendEvaluation(models.NewEvaluation().
+		WithJobID(job.ID).
+		WithNamespace(job.Namespace).
+		WithTriggeredBy(models.EvalTriggerQuotaWait).
+		WithType(job.Type).
+		WithPriority(job.Priority).
+		WithComment(fmt.Sprintf("waiting for quota of namespace %s", job.Namespace)).
+		WithWaitUntil(nextAttempt))
+
+	// only record the event in the job's history the first time its executions are held back,
+	// and when the plan does not create or update any executions, so that it does not clash with their events.
+	if allowed == 0 && len(plan.UpdatedExecutions) == 0 && evaluation.TriggeredBy != models.EvalTriggerQuotaWait {
+		plan.DesiredJobState = job.State.StateType
+		plan.Event = orchestrator.JobQuotaExceededEvent(requested, &quota, nextAttempt)
+	}
+	return allowed, nil
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"strconv"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// resizeForOOM returns the job to run a new execution of the array index with. If the job's retry policy
+// resizes on OOM and a previous execution of the array index was OOM killed, the memory of the job's task
+// is increased from the memory of that execution, up to the ceiling of the policy.
+func resizeForOOM(job *models.Job, arrayIndex int, existingExecs execSet) (*models.Job, error) {
+	if job.RetryPolicy == nil || job.RetryPolicy.ResizeOnOOM == nil {
+		return job, nil
+	}
+	killedMemory, ok := existingExecs.oomKilledMemory(arrayIndex)
+	if !ok {
+		return job, nil
+	}
+	next, err := job.RetryPolicy.ResizeOnOOM.NextMemory(killedMemory)
+	if err != nil {
+		return nil, err
+	}
+	if next <= taskMemory(job) {
+		return job, nil
+	}
+
+	resized := job.Copy()
+	task := resized.Task()
+	if task.ResourcesConfig == nil {
+		task.ResourcesConfig = &models.ResourcesConfig{}
+	}
+	task.ResourcesConfig.Memory = strconv.FormatUint(next, 10)
+	return resized, nil
+}
+
+// taskMemory returns the memory requested by the main task of the job, or zero if it is not set or invalid.
+func taskMemory(job *models.Job) uint64 {
+	if job == nil || len(job.Tasks) == 0 || job.Task().ResourcesConfig == nil {
+		return 0
+	}
+	resources, err := job.Task().ResourcesConfig.ToResources()
+	if err != nil {
+		return 0
+	}
+	return resources.Memory
+}
+
+// largerMemory returns whichever job requests the most memory for its main task.
+func largerMemory(a, b *models.Job) *models.Job {
+	if taskMemory(b) > taskMemory(a) {
+		return b
+	}
+	return a
+}
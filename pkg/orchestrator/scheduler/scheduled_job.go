@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+)
+
+type ScheduledJobSchedulerParams struct {
+	JobStore jobstore.Store
+	Planner  orchestrator.Planner
+	// Clock is the clock used for time-based operations.
+	// If not provided, the system clock is used.
+	Clock clock.Clock
+}
+
+// ScheduledJobScheduler is a scheduler for scheduled jobs that spawn a new batch job,
+// or run, each time their cron schedule fires. The time of the next run is persisted
+// in the job store as a schedule tick evaluation that is not visible to the workers
+// until the run is due, which allows the schedule to survive requester restarts.
+type ScheduledJobScheduler struct {
+	jobStore jobstore.Store
+	planner  orchestrator.Planner
+	clock    clock.Clock
+}
+
+func NewScheduledJobScheduler(params ScheduledJobSchedulerParams) *ScheduledJobScheduler {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	return &ScheduledJobScheduler{
+		jobStore: params.JobStore,
+		planner:  params.Planner,
+		clock:    params.Clock,
+	}
+}
+
+func (b *ScheduledJobScheduler) Process(ctx context.Context, evaluation *models.Evaluation) error {
+	ctx = log.Ctx(ctx).With().Str("JobID", evaluation.JobID).Str("EvalID", evaluation.ID).Logger().WithContext(ctx)
+
+	job, err := b.jobStore.GetJob(ctx, evaluation.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve job %s: %w", evaluation.JobID, err)
+	}
+
+	// Plan to hold the actions to be taken
+	plan := models.NewPlan(evaluation, &job)
+
+	// early exit if the job is stopped. Runs that were already spawned are left to complete.
+	if job.IsTerminal() {
+		return b.planner.Process(ctx, plan)
+	}
+
+	nextRun, err := b.nextRunTime(ctx, &job)
+	if err != nil {
+		return err
+	}
+
+	now := b.clock.Now().UTC()
+	switch {
+	case nextRun.IsZero():
+		// first evaluation of the job
+		nextRun, err = job.Schedule.Next(now)
+		if err != nil {
+			return err
+		}
+		plan.DesiredJobState = models.JobStateTypeRunning
+		plan.Event = orchestrator.JobScheduledEvent(nextRun)
+		plan.AppendEvaluation(tickEvaluation(&job, nextRun))
+	case evaluation.TriggeredBy == models.EvalTriggerScheduleTick && !evaluation.WaitUntil.Equal(nextRun):
+		// the tick has been superseded by a more recent one
+		log.Ctx(ctx).Debug().Msgf("ignoring stale schedule tick for job %s", job.ID)
+	case nextRun.After(now):
+		// the next run is not due yet, such as after the requester restarted.
+		// enqueue a tick for the persisted next run time.
+		plan.AppendEvaluation(tickEvaluation(&job, nextRun))
+	default:
+		if err = b.startRun(ctx, &job, nextRun, now, plan); err != nil {
+			return err
+		}
+	}
+
+	return b.planner.Process(ctx, plan)
+}
+
+// nextRunTime returns the next run time of the job as persisted by the most recent schedule tick,
+// or zero if no run has been scheduled yet.
+func (b *ScheduledJobScheduler) nextRunTime(ctx context.Context, job *models.Job) (time.Time, error) {
+	evals, err := b.jobStore.GetEvaluations(ctx, job.ID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to retrieve evaluations for job %s: %w", job.ID, err)
+	}
+	var nextRun time.Time
+	for _, eval := range evals {
+		if eval.TriggeredBy == models.EvalTriggerScheduleTick && eval.WaitUntil.After(nextRun) {
+			nextRun = eval.WaitUntil
+		}
+	}
+	return nextRun, nil
+}
+
+// startRun spawns a new run of the job according to its concurrency policy,
+// cleans up finished runs beyond the job's history limit, and schedules the next run.
+// Runs missed while the requester was down are not caught up on, except for the most recent one.
+func (b *ScheduledJobScheduler) startRun(
+	ctx context.Context, job *models.Job, scheduledTime time.Time, now time.Time, plan *models.Plan) error {
+	nextRun, err := job.Schedule.Next(now)
+	if err != nil {
+		return err
+	}
+	plan.AppendEvaluation(tickEvaluation(job, nextRun))
+
+	runs, err := b.jobStore.GetScheduledRuns(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve runs of job %s: %w", job.ID, err)
+	}
+	var activeRuns, finishedRuns []models.Job
+	for _, run := range runs {
+		if run.IsTerminal() {
+			finishedRuns = append(finishedRuns, run)
+		} else {
+			activeRuns = append(activeRuns, run)
+		}
+	}
+
+	// keep the most recent finished runs within the history limit
+	if limit := job.Schedule.HistoryLimit; limit > 0 && len(finishedRuns) > limit {
+		for _, run := range finishedRuns[:len(finishedRuns)-limit] {
+			plan.AppendDeletedJob(run.ID)
+		}
+	}
+
+	plan.DesiredJobState = models.JobStateTypeRunning
+	if len(activeRuns) > 0 && job.Schedule.ConcurrencyPolicy == models.ConcurrencyPolicyForbid {
+		plan.Event = orchestrator.JobRunSkippedEvent(len(activeRuns), scheduledTime, nextRun)
+		return nil
+	}
+
+	run := newRun(job, scheduledTime)
+	if job.Schedule.ConcurrencyPolicy == models.ConcurrencyPolicyReplace {
+		for i := range activeRuns {
+			activeRun := &activeRuns[i]
+			plan.AppendStoppedJob(activeRun, orchestrator.JobReplacedEvent(run.ID))
+			plan.AppendEvaluation(models.NewEvaluation().
+				WithJobID(activeRun.ID).
+				WithNamespace(activeRun.Namespace).
+				WithTriggeredBy(models.EvalTriggerJobCancel).
+				WithType(activeRun.Type).
+				WithPriority(activeRun.Priority).
+				WithComment(fmt.Sprintf("replaced by job %s", run.ID)))
+		}
+	}
+
+	plan.AppendJob(run, orchestrator.JobSpawnedEvent(job.ID, scheduledTime))
+	plan.AppendEvaluation(models.NewEvaluation().
+		WithJobID(run.ID).
+		WithNamespace(run.Namespace).
+		WithTriggeredBy(models.EvalTriggerJobRegister).
+		WithType(run.Type).
+		WithPriority(run.Priority).
+		WithComment(fmt.Sprintf("scheduled run of job %s", job.ID)))
+	plan.Event = orchestrator.JobRunStartedEvent(run.ID, scheduledTime, nextRun)
+	return nil
+}
+
+// newRun returns a new batch job spawned by the scheduled job, which inherits
+// the scheduled job's specification.
+func newRun(job *models.Job, scheduledTime time.Time) *models.Job {
+	run := job.Copy()
+	run.ID = idgen.NewJobID()
+	run.Name = fmt.Sprintf("%s-%d", job.Name, scheduledTime.Unix())
+	run.Type = models.JobTypeBatch
+	run.Schedule = nil
+	run.State = models.State[models.JobStateType]{}
+	run.Version = 0
+	run.Revision = 0
+	run.CreateTime = 0
+	run.ModifyTime = 0
+	run.Meta[models.MetaDerivedFrom] = job.ID
+	run.Meta[models.MetaScheduledTime] = scheduledTime.UTC().Format(time.RFC3339)
+	return run
+}
+
+// tickEvaluation returns an evaluation that triggers the next run of the job at the given time.
+func tickEvaluation(job *models.Job, nextRun time.Time) *models.Evaluation {
+	return models.NewEvaluation().
+		WithJobID(job.ID).
+		WithNamespace(job.Namespace).
+		WithTriggeredBy(models.EvalTriggerScheduleTick).
+		WithType(job.Type).
+		WithPriority(job.Priority).
+		WithComment(fmt.Sprintf("next run of job %s", job.ID)).
+		WithWaitUntil(nextRun)
+}
+
+// compile-time assertion that ScheduledJobScheduler satisfies the Scheduler interface
+var _ orchestrator.Scheduler = &ScheduledJobScheduler{}
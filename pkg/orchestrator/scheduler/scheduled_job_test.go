@@ -0,0 +1,231 @@
+//go:build unit || !integration
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ScheduledJobSchedulerTestSuite struct {
+	suite.Suite
+	jobStore  *jobstore.MockStore
+	planner   *orchestrator.MockPlanner
+	clock     *clock.Mock
+	scheduler *ScheduledJobScheduler
+}
+
+func (s *ScheduledJobSchedulerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.jobStore = jobstore.NewMockStore(ctrl)
+	s.planner = orchestrator.NewMockPlanner(ctrl)
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Date(2024, 3, 1, 2, 0, 5, 0, time.UTC))
+
+	s.scheduler = NewScheduledJobScheduler(ScheduledJobSchedulerParams{
+		JobStore: s.jobStore,
+		Planner:  s.planner,
+		Clock:    s.clock,
+	})
+}
+
+func TestScheduledJobSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduledJobSchedulerTestSuite))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_FirstEvaluation_ShouldScheduleNextRun() {
+	job, evaluation := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	job.State = models.NewJobState(models.JobStateTypePending)
+	s.expectJob(job, nil)
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.Equal(models.JobStateTypeRunning, plan.DesiredJobState)
+		s.Empty(plan.NewJobs)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.assertTick(plan.NewEvaluations[0], job, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC))
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), evaluation))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_DueTick_ShouldSpawnRun() {
+	job, _ := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	scheduledTime := time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)
+	tick := tickEvaluation(job, scheduledTime).Normalize()
+	s.expectJob(job, []models.Evaluation{*tick})
+	s.expectRuns(job)
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.Equal(models.JobStateTypeRunning, plan.DesiredJobState)
+		s.Require().Len(plan.NewJobs, 1)
+		run := plan.NewJobs[0].Job
+		s.NotEqual(job.ID, run.ID)
+		s.Equal(models.JobTypeBatch, run.Type)
+		s.Nil(run.Schedule)
+		s.Equal(job.ID, run.Meta[models.MetaDerivedFrom])
+		s.Equal(scheduledTime.Format(time.RFC3339), run.Meta[models.MetaScheduledTime])
+		s.NoError(run.ValidateSubmission())
+
+		s.Require().Len(plan.NewEvaluations, 2)
+		s.assertTick(plan.NewEvaluations[0], job, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC))
+		s.Equal(run.ID, plan.NewEvaluations[1].JobID)
+		s.Equal(models.EvalTriggerJobRegister, plan.NewEvaluations[1].TriggeredBy)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), tick))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_StaleTick_ShouldDoNothing() {
+	job, _ := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	stale := tickEvaluation(job, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)).Normalize()
+	latest := tickEvaluation(job, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)).Normalize()
+	s.expectJob(job, []models.Evaluation{*stale, *latest})
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.True(plan.DesiredJobState.IsUndefined())
+		s.Empty(plan.NewJobs)
+		s.Empty(plan.NewEvaluations)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), stale))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_Restore_ShouldReenqueuePersistedTick() {
+	job, evaluation := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	evaluation.TriggeredBy = models.EvalTriggerJobRestore
+	nextRun := time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+	s.expectJob(job, []models.Evaluation{*tickEvaluation(job, nextRun).Normalize()})
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.True(plan.DesiredJobState.IsUndefined())
+		s.Empty(plan.NewJobs)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.assertTick(plan.NewEvaluations[0], job, nextRun)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), evaluation))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_ForbidWithActiveRun_ShouldSkipRun() {
+	job, _ := mockScheduledJob(models.ConcurrencyPolicyForbid)
+	tick := tickEvaluation(job, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)).Normalize()
+	s.expectJob(job, []models.Evaluation{*tick})
+	s.expectRuns(job, mockRun(job, models.JobStateTypeRunning))
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.Equal(models.JobStateTypeRunning, plan.DesiredJobState)
+		s.Empty(plan.NewJobs)
+		s.Empty(plan.UpdatedJobs)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.assertTick(plan.NewEvaluations[0], job, time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC))
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), tick))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_ReplaceWithActiveRun_ShouldStopActiveRun() {
+	job, _ := mockScheduledJob(models.ConcurrencyPolicyReplace)
+	tick := tickEvaluation(job, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)).Normalize()
+	activeRun := mockRun(job, models.JobStateTypeRunning)
+	s.expectJob(job, []models.Evaluation{*tick})
+	s.expectRuns(job, activeRun)
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.Require().Len(plan.NewJobs, 1)
+		s.Require().Contains(plan.UpdatedJobs, activeRun.ID)
+		s.Equal(models.JobStateTypeStopped, plan.UpdatedJobs[activeRun.ID].DesiredState)
+
+		s.Require().Len(plan.NewEvaluations, 3)
+		s.Equal(activeRun.ID, plan.NewEvaluations[1].JobID)
+		s.Equal(models.EvalTriggerJobCancel, plan.NewEvaluations[1].TriggeredBy)
+		s.Equal(plan.NewJobs[0].Job.ID, plan.NewEvaluations[2].JobID)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), tick))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_HistoryLimit_ShouldDeleteOldestFinishedRuns() {
+	job, _ := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	job.Schedule.HistoryLimit = 2
+	tick := tickEvaluation(job, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)).Normalize()
+	oldest := mockRun(job, models.JobStateTypeCompleted)
+	s.expectJob(job, []models.Evaluation{*tick})
+	s.expectRuns(job,
+		oldest,
+		mockRun(job, models.JobStateTypeFailed),
+		mockRun(job, models.JobStateTypeRunning),
+		mockRun(job, models.JobStateTypeCompleted),
+	)
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.Equal([]string{oldest.ID}, plan.DeletedJobs)
+		s.Require().Len(plan.NewJobs, 1)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), tick))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) TestProcess_StoppedJob_ShouldDoNothing() {
+	job, evaluation := mockScheduledJob(models.ConcurrencyPolicyAllow)
+	job.State = models.NewJobState(models.JobStateTypeStopped)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+
+	s.expectPlan(func(plan *models.Plan) {
+		s.True(plan.DesiredJobState.IsUndefined())
+		s.Empty(plan.NewJobs)
+		s.Empty(plan.NewEvaluations)
+	})
+	s.Require().NoError(s.scheduler.Process(context.Background(), evaluation))
+}
+
+func (s *ScheduledJobSchedulerTestSuite) expectJob(job *models.Job, evals []models.Evaluation) {
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return(evals, nil)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) expectRuns(job *models.Job, runs ...*models.Job) {
+	var jobs []models.Job
+	for _, run := range runs {
+		jobs = append(jobs, *run)
+	}
+	s.jobStore.EXPECT().GetScheduledRuns(gomock.Any(), job.ID).Return(jobs, nil)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) expectPlan(assertPlan func(plan *models.Plan)) {
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, plan *models.Plan) error {
+		assertPlan(plan)
+		return nil
+	}).Times(1)
+}
+
+func (s *ScheduledJobSchedulerTestSuite) assertTick(eval *models.Evaluation, job *models.Job, nextRun time.Time) {
+	s.Equal(job.ID, eval.JobID)
+	s.Equal(models.EvalTriggerScheduleTick, eval.TriggeredBy)
+	s.Equal(nextRun, eval.WaitUntil)
+}
+
+func mockScheduledJob(concurrencyPolicy string) (*models.Job, *models.Evaluation) {
+	job := mock.Job()
+	job.Type = models.JobTypeScheduled
+	job.State = models.NewJobState(models.JobStateTypeRunning)
+	job.Schedule = &models.Schedule{
+		Cron:              "0 2 * * *",
+		ConcurrencyPolicy: concurrencyPolicy,
+	}
+	job.Normalize()
+
+	evaluation := mock.Eval()
+	evaluation.JobID = job.ID
+	evaluation.Type = job.Type
+	evaluation.TriggeredBy = models.EvalTriggerJobRegister
+	return job, evaluation
+}
+
+func mockRun(job *models.Job, state models.JobStateType) *models.Job {
+	run := newRun(job, time.Now())
+	run.State = models.NewJobState(state)
+	return run
+}
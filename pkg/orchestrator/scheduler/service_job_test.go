@@ -35,6 +35,8 @@ type ServiceJobSchedulerTestSuite struct {
 	nodeSelector  *orchestrator.MockNodeSelector
 	retryStrategy orchestrator.RetryStrategy
 	scheduler     *BatchServiceJobScheduler
+	// draining is the cordon of the nodes being drained, keyed by node ID
+	draining map[string]*models.NodeCordon
 }
 
 func (s *ServiceJobSchedulerTestSuite) SetupTest() {
@@ -51,6 +53,11 @@ func (s *ServiceJobSchedulerTestSuite) SetupTest() {
 		NodeSelector:  s.nodeSelector,
 		RetryStrategy: s.retryStrategy,
 	})
+	s.jobStore.EXPECT().GetNamespaceQuota(gomock.Any(), gomock.Any()).
+		Return(models.NamespaceQuota{}, jobstore.NewErrNamespaceQuotaNotFound("")).AnyTimes()
+	s.draining = nil
+	s.nodeSelector.EXPECT().DrainingNodes(gomock.Any()).DoAndReturn(
+		func(context.Context) (map[string]*models.NodeCordon, error) { return s.draining, nil }).AnyTimes()
 
 	// we only want to freeze time to have more deterministic tests.
 	// It doesn't matter what time it is as we are using relative time to this value
@@ -329,6 +336,60 @@ func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoRetry
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *ServiceJobSchedulerTestSuite) TestProcess_Drain_ShouldReplaceBeforeStopping() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	deadline := time.Now().Add(time.Hour)
+	s.draining = map[string]*models.NodeCordon{
+		executions[execServiceBidAccepted1].NodeID: {Drain: true, DrainDeadline: deadline},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.jobStore.EXPECT().GetEvaluations(gomock.Any(), job.ID).Return([]models.Evaluation{}, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}, nil)
+	s.mockNodeSelection(job, []models.NodeInfo{*fakeNodeInfo(s.T(), nodeIDs[3])}, 1)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		// the replacement is created, and the execution on the draining node keeps running until it is running
+		s.Require().Len(plan.NewExecutions, 1)
+		s.Equal(nodeIDs[3], plan.NewExecutions[0].NodeID)
+		s.NotContains(plan.UpdatedExecutions, executions[execServiceBidAccepted1].ID)
+		s.Require().Len(plan.NewEvaluations, 1)
+		s.Equal(deadline.UnixNano(), plan.NewEvaluations[0].WaitUntil.UnixNano())
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_Drain_ShouldStopOnceReplacementIsRunning() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	job.Count = 2
+	executions[execServiceAskForBid].ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+	s.draining = map[string]*models.NodeCordon{
+		executions[execServiceBidAccepted1].NodeID: {Drain: true, DrainDeadline: time.Now().Add(time.Hour)},
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return([]models.NodeInfo{
+		*fakeNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*fakeNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}, nil)
+
+	s.planner.EXPECT().Process(gomock.Any(), gomock.Any()).Do(func(_ context.Context, plan *models.Plan) {
+		s.Empty(plan.NewExecutions)
+		s.Require().Len(plan.UpdatedExecutions, 1)
+		s.Require().Contains(plan.UpdatedExecutions, executions[execServiceBidAccepted1].ID)
+		s.True(plan.UpdatedExecutions[executions[execServiceBidAccepted1].ID].Drained)
+		s.Empty(plan.NewEvaluations)
+	}).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 func (s *ServiceJobSchedulerTestSuite) mockNodeSelection(job *models.Job, nodeInfos []models.NodeInfo, desiredCount int) {
 	if len(nodeInfos) < desiredCount {
 		s.nodeSelector.EXPECT().TopMatchingNodes(gomock.Any(), job, desiredCount).Return(nil, orchestrator.ErrNotEnoughNodes{})
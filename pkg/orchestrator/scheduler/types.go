@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/rs/zerolog/log"
 )
 
@@ -106,6 +107,45 @@ func (set execSet) filterFailed() execSet {
 	return set.filterByState(models.ExecutionStateFailed)
 }
 
+// excludeDisplaced filters out executions that were displaced from their node, because they were preempted
+// by a higher priority job or their node was drained.
+func (set execSet) excludeDisplaced() execSet {
+	filtered := execSet{}
+	for _, exec := range set {
+		if exec.PreemptedBy == "" && !exec.Drained {
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
+// filterDrained filters out executions that were not stopped because their node was drained.
+func (set execSet) filterDrained() execSet {
+	filtered := execSet{}
+	for _, exec := range set {
+		if exec.Drained {
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
+// filterFailedAttempts filters out executions that are not failed attempts of the job. Failed attempts are
+// the executions that failed on their node, and the ones the orchestrator stopped because they timed out or
+// their node was lost. Executions canceled by the user, preempted or drained are not failed attempts.
+func (set execSet) filterFailedAttempts() execSet {
+	filtered := execSet{}
+	for _, exec := range set.excludeDisplaced() {
+		switch {
+		case exec.ComputeState.StateType == models.ExecutionStateFailed,
+			exec.FailureReason == models.FailureReasonTimeout,
+			exec.FailureReason == models.FailureReasonNodeLost:
+			filtered[exec.ID] = exec
+		}
+	}
+	return filtered
+}
+
 // filterOverSubscriptions partitions executions based on if they are more than the desired count.
 func (set execSet) filterByOverSubscriptions(desiredCount int) (remaining execSet, overSubscriptions execSet) {
 	remaining = make(execSet)
@@ -137,6 +177,28 @@ func (set execSet) filterByNodeHealth(nodeInfos map[string]*models.NodeInfo) (he
 	return healthy, lost
 }
 
+// filterByNodeDrain partitions executions based on whether their node is being drained. Executions on draining
+// nodes that are running are migrating until the drain deadline, and the others are to be drained right away.
+func (set execSet) filterByNodeDrain(
+	draining map[string]*models.NodeCordon, now time.Time) (remaining, migrating, drained execSet) {
+	remaining = make(execSet)
+	migrating = make(execSet)
+	drained = make(execSet)
+	for _, exec := range set {
+		cordon, ok := draining[exec.NodeID]
+		switch {
+		case !ok:
+			remaining[exec.ID] = exec
+		case exec.ComputeState.StateType == models.ExecutionStateBidAccepted && now.Before(cordon.DrainDeadline):
+			migrating[exec.ID] = exec
+		default:
+			drained[exec.ID] = exec
+			log.Debug().Msgf("Execution %s is on node %s which is being drained", exec.ID, exec.NodeID)
+		}
+	}
+	return remaining, migrating, drained
+}
+
 // filterByExecutionTimeout partitions executions based on their timeout status.
 func (set execSet) filterByExecutionTimeout(expirationTime time.Time) (remaining, timedOut execSet) {
 	remaining = make(execSet)
@@ -164,6 +226,11 @@ func (e executionsByApprovalStatus) activeCount() int {
 	return len(e.running) + len(e.toApprove) + len(e.pending)
 }
 
+// active returns the active executions, excluding rejected ones.
+func (e executionsByApprovalStatus) active() execSet {
+	return e.running.union(e.toApprove).union(e.pending)
+}
+
 // filterByApprovalStatus partitions executions based on their approval status.
 func (set execSet) filterByApprovalStatus(desiredCount int) executionsByApprovalStatus {
 	nonTermExecs := set.filterNonTerminal()
@@ -214,6 +281,13 @@ func (set execSet) markStopped(event models.Event, plan *models.Plan) {
 	}
 }
 
+// markDrained marks the executions to be stopped because their node is being drained
+func (set execSet) markDrained(plan *models.Plan) {
+	for _, exec := range set {
+		plan.AppendDrainedExecution(exec, orchestrator.ExecStoppedByNodeDrainEvent())
+	}
+}
+
 // markStopped
 func (set execSet) markApproved(plan *models.Plan) {
 	for _, exec := range set {
@@ -246,3 +320,59 @@ func (set execSet) countByState() map[models.ExecutionStateType]int {
 func (set execSet) countCompleted() int {
 	return set.countByState()[models.ExecutionStateCompleted]
 }
+
+// countCompletedArrayIndexes counts the number of distinct array indexes with a completed execution.
+func (set execSet) countCompletedArrayIndexes() int {
+	indexes := make(map[int]struct{})
+	for _, exec := range set.filterByState(models.ExecutionStateCompleted) {
+		indexes[exec.ArrayIndex] = struct{}{}
+	}
+	return len(indexes)
+}
+
+// latestCheckpoint returns the most recent checkpoint taken of the executions of the given array index, if any.
+func (set execSet) latestCheckpoint(arrayIndex int) *models.Checkpoint {
+	var latest *models.Checkpoint
+	for _, exec := range set {
+		if exec.ArrayIndex != arrayIndex || exec.Checkpoint == nil {
+			continue
+		}
+		if latest == nil || exec.Checkpoint.Time > latest.Time {
+			latest = exec.Checkpoint
+		}
+	}
+	return latest
+}
+
+// oomKilledMemory returns the highest memory requested or used by the executions of the array index that were
+// killed for exceeding their memory limit, and false if none was.
+func (set execSet) oomKilledMemory(arrayIndex int) (uint64, bool) {
+	var memory uint64
+	var found bool
+	for _, exec := range set {
+		if exec.ArrayIndex != arrayIndex || exec.FailureReason != models.FailureReasonOOMKilled {
+			continue
+		}
+		found = true
+		memory = max(memory, taskMemory(exec.Job))
+		if exec.ResourceUsage != nil {
+			memory = max(memory, exec.ResourceUsage.PeakMemory)
+		}
+	}
+	return memory, found
+}
+
+// missingArrayIndexes returns the indexes of the array that have no execution in the set, in ascending order.
+func (set execSet) missingArrayIndexes(array *models.JobArray) []int {
+	covered := make(map[int]struct{})
+	for _, exec := range set {
+		covered[exec.ArrayIndex] = struct{}{}
+	}
+	var missing []int
+	for _, index := range array.Indexes() {
+		if _, ok := covered[index]; !ok {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
@@ -73,6 +73,18 @@ func TestExecSet_FilterFailed(t *testing.T) {
 	assert.ElementsMatch(t, filtered.keys(), []string{"exec1", "exec3"})
 }
 
+func TestExecSet_ExcludeDisplaced(t *testing.T) {
+	executions := []*models.Execution{
+		{ID: "exec1", ComputeState: models.NewExecutionState(models.ExecutionStateFailed)},
+		{ID: "exec2", ComputeState: models.NewExecutionState(models.ExecutionStateFailed), PreemptedBy: "job"},
+		{ID: "exec3", ComputeState: models.NewExecutionState(models.ExecutionStateFailed), Drained: true},
+	}
+
+	set := execSetFromSlice(executions)
+	assert.ElementsMatch(t, set.excludeDisplaced().keys(), []string{"exec1"})
+	assert.ElementsMatch(t, set.filterFailedAttempts().keys(), []string{"exec1"})
+}
+
 func TestExecSet_Union(t *testing.T) {
 	set1 := execSet{
 		"exec1": {ID: "exec1", ComputeState: models.NewExecutionState(models.ExecutionStateAskForBid)},
@@ -240,3 +252,17 @@ func TestExecSet_FilterByExecutionTimeout(t *testing.T) {
 	assert.Contains(t, timedOutExecs, "exec2")
 	assert.Contains(t, timedOutExecs, "exec3")
 }
+
+func TestExecSet_LatestCheckpoint(t *testing.T) {
+	executions := []*models.Execution{
+		{ID: "exec1", ArrayIndex: 0, Checkpoint: &models.Checkpoint{ExecutionID: "exec1", Time: 1}},
+		{ID: "exec2", ArrayIndex: 0, Checkpoint: &models.Checkpoint{ExecutionID: "exec2", Time: 3}},
+		{ID: "exec3", ArrayIndex: 0},
+		{ID: "exec4", ArrayIndex: 1, Checkpoint: &models.Checkpoint{ExecutionID: "exec4", Time: 5}},
+	}
+
+	set := execSetFromSlice(executions)
+	assert.Equal(t, "exec2", set.latestCheckpoint(0).ExecutionID)
+	assert.Equal(t, "exec4", set.latestCheckpoint(1).ExecutionID)
+	assert.Nil(t, set.latestCheckpoint(2))
+}
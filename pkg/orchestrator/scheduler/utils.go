@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 )
@@ -43,3 +44,20 @@ func existingNodeInfos(ctx context.Context,
 	}
 	return out, nil
 }
+
+// hasPendingEvaluation returns true if the job already has a pending evaluation triggered by the given
+// trigger, such as a quota wait, other than the one being processed.
+func hasPendingEvaluation(ctx context.Context, jobStore jobstore.Store,
+	evaluation *models.Evaluation, job *models.Job, triggeredBy string) (bool, error) {
+	evals, err := jobStore.GetEvaluations(ctx, job.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve evaluations of job %s: %w", job.ID, err)
+	}
+	for _, eval := range evals {
+		if eval.ID != evaluation.ID && eval.TriggeredBy == triggeredBy &&
+			eval.Status == models.EvalStatusPending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,148 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// secretKeyDomain separates the secrets encryption key from other uses of the node key
+const secretKeyDomain = "bacalhau-secrets-v1:"
+
+// SecretKeyFromPrivateKey derives the 256-bit key secrets are encrypted with from the private key of the node.
+func SecretKeyFromPrivateKey(privateKey *rsa.PrivateKey) []byte {
+	hash := sha256.New()
+	hash.Write([]byte(secretKeyDomain))
+	hash.Write(x509.MarshalPKCS1PrivateKey(privateKey))
+	return hash.Sum(nil)
+}
+
+// SecretManager stores the secrets of namespaces in the job store, with their values encrypted at rest
+// using AES-GCM, and resolves them for the executions that reference them.
+type SecretManager struct {
+	store jobstore.Store
+	aead  cipher.AEAD
+}
+
+// NewSecretManager creates a secret manager that encrypts secrets with a 256-bit key
+func NewSecretManager(store jobstore.Store, key []byte) (*SecretManager, error) {
+	if len(key) != sha256.Size {
+		return nil, fmt.Errorf("secrets encryption key must be %d bytes, got %d", sha256.Size, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets cipher: %w", err)
+	}
+	return &SecretManager{store: store, aead: aead}, nil
+}
+
+// PutSecret creates or replaces a secret, and returns it without its value
+func (m *SecretManager) PutSecret(ctx context.Context, secret models.Secret) (models.Secret, error) {
+	secret.Normalize()
+	if err := secret.Validate(); err != nil {
+		return models.Secret{}, err
+	}
+	encrypted, err := m.encrypt(&secret)
+	if err != nil {
+		return models.Secret{}, err
+	}
+	secret.Value = encrypted
+	if err = m.store.PutSecret(ctx, secret); err != nil {
+		return models.Secret{}, err
+	}
+
+	stored, err := m.store.GetSecret(ctx, secret.Namespace, secret.Name)
+	if err != nil {
+		return models.Secret{}, err
+	}
+	stored.Value = ""
+	return stored, nil
+}
+
+// GetSecrets returns the secrets of a namespace without their values
+func (m *SecretManager) GetSecrets(ctx context.Context, namespace string) ([]models.Secret, error) {
+	secrets, err := m.store.GetSecrets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		secrets[i].Value = ""
+	}
+	return secrets, nil
+}
+
+// DeleteSecret removes a secret from a namespace
+func (m *SecretManager) DeleteSecret(ctx context.Context, namespace string, name string) error {
+	return m.store.DeleteSecret(ctx, namespace, name)
+}
+
+// ResolveSecrets returns the decrypted values of the named secrets of a namespace. Secrets that do not
+// exist are left out, so that the executions referencing them fail on the compute node.
+func (m *SecretManager) ResolveSecrets(
+	ctx context.Context, namespace string, names []string) (models.SecretValues, error) {
+	values := make(models.SecretValues, len(names))
+	for _, name := range names {
+		secret, err := m.store.GetSecret(ctx, namespace, name)
+		if err != nil {
+			if errors.As(err, new(jobstore.ErrSecretNotFound)) {
+				continue
+			}
+			return nil, err
+		}
+		value, err := m.decrypt(&secret)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// encrypt returns the base64 encoded nonce and ciphertext of the value of a secret. The namespace and name
+// of the secret are authenticated with the value, so that a stored value cannot be moved to another secret.
+func (m *SecretManager) encrypt(secret *models.Secret) (string, error) {
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce for secret %s: %w", secret.Name, err)
+	}
+	sealed := m.aead.Seal(nonce, nonce, []byte(secret.Value), secretAdditionalData(secret))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns the value of a secret encrypted by encrypt
+func (m *SecretManager) decrypt(secret *models.Secret) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(secret.Value)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", secret.Name, err)
+	}
+	if len(sealed) < m.aead.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt secret %s: value is too short", secret.Name)
+	}
+	nonce, ciphertext := sealed[:m.aead.NonceSize()], sealed[m.aead.NonceSize():]
+	value, err := m.aead.Open(nil, nonce, ciphertext, secretAdditionalData(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s: %w", secret.Name, err)
+	}
+	return string(value), nil
+}
+
+func secretAdditionalData(secret *models.Secret) []byte {
+	return []byte(secret.Namespace + "/" + secret.Name)
+}
+
+// compile-time check that SecretManager implements SecretResolver
+var _ SecretResolver = (*SecretManager)(nil)
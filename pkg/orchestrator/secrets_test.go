@@ -0,0 +1,114 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type SecretManagerTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockJobStore *jobstore.MockStore
+	stored       map[string]models.Secret
+	manager      *SecretManager
+}
+
+func TestSecretManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(SecretManagerTestSuite))
+}
+
+func (s *SecretManagerTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockJobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+	s.stored = make(map[string]models.Secret)
+
+	s.mockJobStore.EXPECT().PutSecret(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, secret models.Secret) error {
+			s.stored[secret.Namespace+"/"+secret.Name] = secret
+			return nil
+		}).AnyTimes()
+	s.mockJobStore.EXPECT().GetSecret(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, namespace string, name string) (models.Secret, error) {
+			secret, ok := s.stored[namespace+"/"+name]
+			if !ok {
+				return models.Secret{}, jobstore.NewErrSecretNotFound(namespace, name)
+			}
+			return secret, nil
+		}).AnyTimes()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.manager, err = NewSecretManager(s.mockJobStore, SecretKeyFromPrivateKey(key))
+	s.Require().NoError(err)
+}
+
+func (s *SecretManagerTestSuite) TestNewSecretManagerInvalidKey() {
+	_, err := NewSecretManager(s.mockJobStore, []byte("short"))
+	s.Error(err)
+}
+
+func (s *SecretManagerTestSuite) TestPutSecretEncryptsValue() {
+	secret, err := s.manager.PutSecret(s.ctx, models.Secret{Name: "token", Value: "s3cr3t"})
+	s.Require().NoError(err)
+	s.Equal(models.DefaultNamespace, secret.Namespace)
+	s.Empty(secret.Value)
+
+	stored := s.stored[models.DefaultNamespace+"/token"]
+	s.NotEmpty(stored.Value)
+	s.NotContains(stored.Value, "s3cr3t")
+
+	_, err = s.manager.PutSecret(s.ctx, models.Secret{Name: "token"})
+	s.Error(err)
+}
+
+func (s *SecretManagerTestSuite) TestResolveSecrets() {
+	_, err := s.manager.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "token", Value: "s3cr3t"})
+	s.Require().NoError(err)
+	_, err = s.manager.PutSecret(s.ctx, models.Secret{Namespace: "team-b", Name: "token", Value: "other"})
+	s.Require().NoError(err)
+
+	// missing secrets are left out
+	values, err := s.manager.ResolveSecrets(s.ctx, "team-a", []string{"token", "missing"})
+	s.Require().NoError(err)
+	s.Equal(models.SecretValues{"token": "s3cr3t"}, values)
+
+	values, err = s.manager.ResolveSecrets(s.ctx, "team-b", []string{"token"})
+	s.Require().NoError(err)
+	s.Equal(models.SecretValues{"token": "other"}, values)
+}
+
+func (s *SecretManagerTestSuite) TestResolveSecretsMovedValue() {
+	_, err := s.manager.PutSecret(s.ctx, models.Secret{Namespace: "team-a", Name: "token", Value: "s3cr3t"})
+	s.Require().NoError(err)
+
+	// a value copied to another namespace cannot be decrypted
+	moved := s.stored["team-a/token"]
+	moved.Namespace = "team-b"
+	s.stored["team-b/token"] = moved
+	_, err = s.manager.ResolveSecrets(s.ctx, "team-b", []string{"token"})
+	s.Error(err)
+}
+
+func (s *SecretManagerTestSuite) TestGetSecretsStripsValues() {
+	s.mockJobStore.EXPECT().GetSecrets(gomock.Any(), "team-a").Return([]models.Secret{
+		{Namespace: "team-a", Name: "key", Value: "encrypted"},
+		{Namespace: "team-a", Name: "token", Value: "encrypted"},
+	}, nil)
+
+	secrets, err := s.manager.GetSecrets(s.ctx, "team-a")
+	s.Require().NoError(err)
+	s.Require().Len(secrets, 2)
+	for _, secret := range secrets {
+		s.Empty(secret.Value)
+	}
+}
@@ -0,0 +1,69 @@
+package ranking
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+type InputLocalityNodeRanker struct {
+}
+
+func NewInputLocalityNodeRanker() *InputLocalityNodeRanker {
+	return &InputLocalityNodeRanker{}
+}
+
+// RankNodes ranks nodes based on how many of the job's inputs they hold in their input cache:
+// - Rank 10: Node holds all the inputs of the job.
+// - Rank 1-9: Node holds some of the inputs of the job, in proportion.
+// - Rank 0: Node holds none of the inputs, or the job has no inputs.
+func (s *InputLocalityNodeRanker) RankNodes(ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
+	var keys []string
+	for _, input := range job.Task().InputSources {
+		key, err := input.CacheKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	ranks := make([]orchestrator.NodeRank, len(nodes))
+	for i, node := range nodes {
+		rank := orchestrator.RankPossible
+		reason := "job inputs are not cached"
+		if cached := cachedInputs(node, keys); cached > 0 {
+			rank = max(1, orchestrator.RankPreferred*cached/len(keys))
+			reason = fmt.Sprintf("%d of %d job inputs are cached", cached, len(keys))
+		}
+		ranks[i] = orchestrator.NodeRank{
+			NodeInfo:  node,
+			Rank:      rank,
+			Reason:    reason,
+			Retryable: false,
+		}
+		log.Ctx(ctx).Trace().Object("Rank", ranks[i]).Msg("Ranked node")
+	}
+	return ranks, nil
+}
+
+// cachedInputs returns how many of the inputs with the given keys are in the input cache of the node
+func cachedInputs(node models.NodeInfo, keys []string) int {
+	if node.ComputeNodeInfo == nil || len(node.ComputeNodeInfo.CachedInputs) == 0 {
+		return 0
+	}
+	cached := make(map[string]bool, len(node.ComputeNodeInfo.CachedInputs))
+	for _, key := range node.ComputeNodeInfo.CachedInputs {
+		cached[key] = true
+	}
+	count := 0
+	for _, key := range keys {
+		if cached[key] {
+			count++
+		}
+	}
+	return count
+}
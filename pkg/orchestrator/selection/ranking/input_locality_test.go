@@ -0,0 +1,87 @@
+//go:build unit || !integration
+
+package ranking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type InputLocalityNodeRankerSuite struct {
+	suite.Suite
+	ranker *InputLocalityNodeRanker
+	inputs []*models.InputSource
+	keys   []string
+}
+
+func TestInputLocalityNodeRankerSuite(t *testing.T) {
+	suite.Run(t, new(InputLocalityNodeRankerSuite))
+}
+
+func (s *InputLocalityNodeRankerSuite) SetupTest() {
+	s.ranker = NewInputLocalityNodeRanker()
+	s.inputs = []*models.InputSource{
+		{
+			Source: &models.SpecConfig{Type: models.StorageSourceURL, Params: map[string]interface{}{"URL": "https://example.com/a"}},
+			Target: "/inputs/a",
+		},
+		{
+			Source: &models.SpecConfig{Type: models.StorageSourceS3, Params: map[string]interface{}{"Bucket": "b", "Key": "k"}},
+			Target: "/inputs/b",
+		},
+	}
+	s.keys = nil
+	for _, input := range s.inputs {
+		key, err := input.CacheKey()
+		s.Require().NoError(err)
+		s.keys = append(s.keys, key)
+	}
+}
+
+func (s *InputLocalityNodeRankerSuite) TestRankNodes() {
+	job := mock.Job()
+	job.Task().InputSources = s.inputs
+	nodes := []models.NodeInfo{
+		{NodeID: "all", ComputeNodeInfo: &models.ComputeNodeInfo{CachedInputs: []string{s.keys[1], "other", s.keys[0]}}},
+		{NodeID: "some", ComputeNodeInfo: &models.ComputeNodeInfo{CachedInputs: []string{s.keys[1]}}},
+		{NodeID: "none", ComputeNodeInfo: &models.ComputeNodeInfo{CachedInputs: []string{"other"}}},
+		{NodeID: "unknown"},
+	}
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, nodes)
+	s.Require().NoError(err)
+	s.Require().Len(ranks, len(nodes))
+	assertEquals(s.T(), ranks, "all", 10, "2 of 2 job inputs are cached")
+	assertEquals(s.T(), ranks, "some", 5, "1 of 2 job inputs are cached")
+	assertEquals(s.T(), ranks, "none", 0)
+	assertEquals(s.T(), ranks, "unknown", 0)
+}
+
+func (s *InputLocalityNodeRankerSuite) TestRankNodesIgnoresTarget() {
+	// the same sources mounted on other targets are cached with the same keys
+	job := mock.Job()
+	job.Task().InputSources = []*models.InputSource{
+		{Source: s.inputs[0].Source, Target: "/data", Alias: "data"},
+	}
+	nodes := []models.NodeInfo{
+		{NodeID: "cached", ComputeNodeInfo: &models.ComputeNodeInfo{CachedInputs: s.keys}},
+	}
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, nodes)
+	s.Require().NoError(err)
+	assertEquals(s.T(), ranks, "cached", 10)
+}
+
+func (s *InputLocalityNodeRankerSuite) TestRankNodesWithoutInputs() {
+	job := mock.Job()
+	job.Task().InputSources = nil
+	nodes := []models.NodeInfo{
+		{NodeID: "cached", ComputeNodeInfo: &models.ComputeNodeInfo{CachedInputs: s.keys}},
+	}
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, nodes)
+	s.Require().NoError(err)
+	assertEquals(s.T(), ranks, "cached", 0)
+}
@@ -21,10 +21,11 @@ func NewMaxUsageNodeRanker() *MaxUsageNodeRanker {
 // RankNodes ranks nodes based on the MaxJobRequirements the compute nodes are accepting:
 // - Rank 10: Node is accepting MaxJobRequirements that are equal or higher than the job requirements.
 // - Rank -1: Node is accepting MaxJobRequirements that are lower than the job requirements.
+// - Rank -1: Job has a GPU selector and the node doesn't have enough GPUs matching it.
 // - Rank 0: Node MaxJobRequirements are not set, or the node was discovered not through nodeInfoPublisher (e.g. identity protocol)
 func (s *MaxUsageNodeRanker) RankNodes(ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
 	ranks := make([]orchestrator.NodeRank, len(nodes))
-	jobResourceUsage, err := job.Task().ResourcesConfig.ToResources()
+	jobResourceUsage, err := job.TotalResources()
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert job resources config to resources: %w", err)
 	}
@@ -33,7 +34,12 @@ func (s *MaxUsageNodeRanker) RankNodes(ctx context.Context, job models.Job, node
 		rank := orchestrator.RankPossible
 		reason := "max job resource requirements not set or unknown"
 		if jobResourceUsageSet && node.ComputeNodeInfo != nil {
-			if jobResourceUsage.LessThanEq(node.ComputeNodeInfo.MaxJobRequirements) {
+			if !node.ComputeNodeInfo.MaxCapacity.HasGPUsFor(*jobResourceUsage) {
+				rank = orchestrator.RankUnsuitable
+				reason = fmt.Sprintf("job requires %d GPUs matching %s but node has %d",
+					jobResourceUsage.GPU, jobResourceUsage.GPUSelector.String(),
+					len(node.ComputeNodeInfo.MaxCapacity.MatchingGPUs(jobResourceUsage.GPUSelector)))
+			} else if jobResourceUsage.LessThanEq(node.ComputeNodeInfo.MaxJobRequirements) {
 				rank = orchestrator.RankPreferred
 				reason = "job requires less resources than are available"
 			} else {
@@ -140,3 +140,32 @@ func (s *MaxUsageNodeRankerSuite) TestRankNodesUnknownJob() {
 	assertEquals(s.T(), ranks, "med", 0)
 	assertEquals(s.T(), ranks, "large", 0)
 }
+
+func (s *MaxUsageNodeRankerSuite) TestRankNodes_GPUSelector() {
+	t4 := models.GPU{Index: 0, Name: "Tesla T4", Vendor: models.GPUVendorNvidia, Memory: 15360}
+	a100 := models.GPU{Index: 0, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920}
+	t4Peer := models.NodeInfo{
+		NodeID: "t4",
+		ComputeNodeInfo: &models.ComputeNodeInfo{
+			MaxCapacity:        models.Resources{CPU: 3, GPU: 1, GPUs: []models.GPU{t4}},
+			MaxJobRequirements: models.Resources{CPU: 3, GPU: 1},
+		},
+	}
+	a100Peer := models.NodeInfo{
+		NodeID: "a100",
+		ComputeNodeInfo: &models.ComputeNodeInfo{
+			MaxCapacity:        models.Resources{CPU: 3, GPU: 1, GPUs: []models.GPU{a100}},
+			MaxJobRequirements: models.Resources{CPU: 3, GPU: 1},
+		},
+	}
+
+	job := mock.Job()
+	job.Task().ResourcesConfig = &models.ResourcesConfig{
+		CPU:         "1",
+		GPUSelector: &models.GPUSelector{Model: "A100", MinMemory: "80GiB"},
+	}
+	ranks, err := s.MaxUsageNodeRanker.RankNodes(context.Background(), *job, []models.NodeInfo{t4Peer, a100Peer})
+	s.NoError(err)
+	assertEquals(s.T(), ranks, "t4", -1, "job requires 1 GPUs matching {min memory: 80GiB, model: A100} but node has 0")
+	assertEquals(s.T(), ranks, "a100", 10)
+}
@@ -46,6 +46,20 @@ func (n NodeSelector) AllNodes(ctx context.Context) ([]models.NodeInfo, error) {
 	return nodeInfos, nil
 }
 
+func (n NodeSelector) DrainingNodes(ctx context.Context) (map[string]*models.NodeCordon, error) {
+	nodeStates, err := n.discoverer.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discovered nodes: %w", err)
+	}
+	draining := make(map[string]*models.NodeCordon)
+	for _, ns := range nodeStates {
+		if ns.IsDraining() {
+			draining[ns.Info.ID()] = ns.Cordon
+		}
+	}
+	return draining, nil
+}
+
 func (n NodeSelector) AllMatchingNodes(ctx context.Context, job *models.Job) ([]models.NodeInfo, error) {
 	filteredNodes, _, err := n.rankAndFilterNodes(ctx, job)
 	if err != nil {
@@ -94,6 +108,7 @@ func (n NodeSelector) rankAndFilterNodes(
 	// - compute nodes
 	// - approved to executor jobs
 	// - connected (alive)
+	// - not cordoned
 	nodeStates := lo.Filter(listed, func(nodeState models.NodeState, index int) bool {
 		if nodeState.Info.NodeType != models.NodeTypeCompute {
 			return false
@@ -107,6 +122,10 @@ func (n NodeSelector) rankAndFilterNodes(
 			return false
 		}
 
+		if nodeState.IsCordoned() {
+			return false
+		}
+
 		return true
 	})
 
@@ -0,0 +1,44 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ImageResolver resolves the manifest, and so the digest, that a docker image reference currently points to.
+type ImageResolver interface {
+	ImageDistribution(ctx context.Context, image string, creds config.DockerCredentials) (*docker.ImageManifest, error)
+}
+
+// NewImageDigestPinner returns a job transformer that pins the docker images of cacheable jobs to the digest
+// their tags currently resolve to. The result cache key of a job is computed from its spec, so pinning the
+// image makes jobs run again once a tag is moved to a new image, instead of reusing results of the old image.
+// Images that are already pinned by digest are left as they are.
+func NewImageDigestPinner(resolver ImageResolver) JobTransformer {
+	f := func(ctx context.Context, j *models.Job) error {
+		if !j.IsCacheable() || !j.Task().Engine.IsType(models.EngineDocker) {
+			return nil
+		}
+		engine, err := dockermodels.DecodeSpec(j.Task().Engine)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(engine.Image, "@") {
+			return nil
+		}
+		manifest, err := resolver.ImageDistribution(ctx, engine.Image, config.GetDockerCredentials())
+		if err != nil {
+			return fmt.Errorf("failed to resolve the digest of image %s of cacheable job: %w. "+
+				"Pin the image by digest to cache the job's results without resolving it", engine.Image, err)
+		}
+		j.Task().Engine.Params[dockermodels.EngineKeyImageDocker] = engine.Image + "@" + manifest.Digest.String()
+		return nil
+	}
+	return JobFn(f)
+}
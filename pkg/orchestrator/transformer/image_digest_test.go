@@ -0,0 +1,73 @@
+//go:build unit || !integration
+
+package transformer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+const testDigest = digest.Digest("sha256:48603925e10c01936ea4258f26b3a7a0e80e2a5b1f4ac4e6d4b3a6d2b1c0e9f8")
+
+// fakeImageResolver resolves every image to the same digest, and records the images it resolved.
+type fakeImageResolver struct {
+	resolved []string
+	err      error
+}
+
+func (r *fakeImageResolver) ImageDistribution(_ context.Context, image string, _ config.DockerCredentials) (*docker.ImageManifest, error) {
+	r.resolved = append(r.resolved, image)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &docker.ImageManifest{Digest: testDigest}, nil
+}
+
+func dockerJob(image string, cacheable bool) *models.Job {
+	job := mock.Job()
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder(image).Build()
+	job.Task().Cacheable = cacheable
+	return job
+}
+
+func imageOf(t *testing.T, job *models.Job) string {
+	engine, err := dockermodels.DecodeSpec(job.Task().Engine)
+	require.NoError(t, err)
+	return engine.Image
+}
+
+func TestImageDigestPinner(t *testing.T) {
+	resolver := &fakeImageResolver{}
+	pinner := NewImageDigestPinner(resolver)
+
+	job := dockerJob("ubuntu:latest", true)
+	require.NoError(t, pinner.Transform(context.Background(), job))
+	assert.Equal(t, "ubuntu:latest@"+testDigest.String(), imageOf(t, job))
+
+	pinned := "ubuntu@" + testDigest.String()
+	job = dockerJob(pinned, true)
+	require.NoError(t, pinner.Transform(context.Background(), job))
+	assert.Equal(t, pinned, imageOf(t, job))
+
+	job = dockerJob("ubuntu:latest", false)
+	require.NoError(t, pinner.Transform(context.Background(), job))
+	assert.Equal(t, "ubuntu:latest", imageOf(t, job))
+
+	assert.Equal(t, []string{"ubuntu:latest"}, resolver.resolved, "only unpinned images of cacheable jobs are resolved")
+}
+
+func TestImageDigestPinner_FailsIfImageCannotBeResolved(t *testing.T) {
+	pinner := NewImageDigestPinner(&fakeImageResolver{err: errors.New("registry unavailable")})
+	assert.Error(t, pinner.Transform(context.Background(), dockerJob("ubuntu:latest", true)))
+}
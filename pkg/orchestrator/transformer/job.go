@@ -23,8 +23,9 @@ type JobDefaults struct {
 // DefaultsApplier is a transformer that applies default values to the job.
 func DefaultsApplier(defaults JobDefaults) JobTransformer {
 	f := func(ctx context.Context, job *models.Job) error {
-		// only apply default execution timeout to non-long running jobs
-		if !job.IsLongRunning() {
+		// only apply default execution timeout to non-long running jobs,
+		// and to scheduled jobs as the batch jobs they spawn inherit their tasks
+		if !job.IsLongRunning() || job.Type == models.JobTypeScheduled {
 			for _, task := range job.Tasks {
 				if task.Timeouts.GetExecutionTimeout() <= 0 {
 					task.Timeouts.ExecutionTimeout = int64(defaults.ExecutionTimeout.Seconds())
@@ -10,6 +10,10 @@ func DefaultPublisher(publisherConfig *models.SpecConfig) JobTransformer {
 	f := func(ctx context.Context, job *models.Job) error {
 		for i := range job.Tasks {
 			task := job.Tasks[i]
+			// results of all tasks are published by the main task
+			if !task.IsMain() {
+				continue
+			}
 			if task.Publisher == nil || task.Publisher.Type == "" {
 				task.Publisher = publisherConfig
 			}
@@ -25,6 +25,14 @@ type StopJobResponse struct {
 	EvaluationID string
 }
 
+type DrainNodeRequest struct {
+	NodeID string
+}
+
+type DrainNodeResponse struct {
+	EvaluationIDs []string
+}
+
 type ReadLogsRequest struct {
 	JobID       string
 	ExecutionID string
@@ -37,6 +45,16 @@ type ReadLogsResponse struct {
 	ExecutionComplete bool
 }
 
+type ExecRequest struct {
+	JobID       string
+	ExecutionID string
+	Namespace   string
+	Command     []string
+	Attach      bool
+	TTY         bool
+	Stdin       bool
+}
+
 type GetResultsRequest struct {
 	JobID string
 }
@@ -94,3 +112,10 @@ type NodeSelectionConstraints struct {
 	RequireConnected bool
 	RequireApproval  bool
 }
+
+// PreemptionPolicy defines whether running executions of lower priority jobs can be stopped
+// to make room for the executions of higher priority jobs. Namespaces whose quota is marked
+// as non-preemptible are protected from preemption.
+type PreemptionPolicy struct {
+	Enabled bool
+}
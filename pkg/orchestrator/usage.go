@@ -0,0 +1,116 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// usagePageSize is how many jobs are read at once to compute their resource usage
+const usagePageSize = 100
+
+// GetResourceUsage returns the resources actually used by the executions of each job, and their totals
+// in each namespace. Unlike GetNamespaceUsage, executions in a terminal state are counted, and jobs or
+// executions that reported no usage are left out. If namespace is set, only the jobs of that namespace
+// are considered, and if jobID is set, only that job is. Jobs are read in pages, along with the executions
+// of each page, so that the store is never asked for all jobs or for the executions of one job at a time.
+func GetResourceUsage(ctx context.Context, store jobstore.Store, namespace, jobID string) (
+	[]models.JobResourceUsage, []models.NamespaceResourceUsage, error) {
+	aggregate := newUsageAggregate()
+	if jobID != "" {
+		job, err := store.GetJob(ctx, jobID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if namespace == "" || job.Namespace == namespace {
+			if err = aggregate.addJobs(ctx, store, []models.Job{job}); err != nil {
+				return nil, nil, err
+			}
+		}
+		return aggregate.results()
+	}
+
+	query := jobstore.JobQuery{Namespace: namespace, Limit: usagePageSize, SortBy: "created_at"}
+	for {
+		response, err := store.GetJobs(ctx, query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve jobs: %w", err)
+		}
+		if err = aggregate.addJobs(ctx, store, response.Jobs); err != nil {
+			return nil, nil, err
+		}
+		if response.NextOffset == 0 {
+			return aggregate.results()
+		}
+		query.Offset = response.NextOffset
+	}
+}
+
+// usageAggregate accumulates the resource usage of jobs and of their namespaces
+type usageAggregate struct {
+	jobs       []models.JobResourceUsage
+	namespaces map[string]*models.NamespaceResourceUsage
+}
+
+func newUsageAggregate() *usageAggregate {
+	return &usageAggregate{namespaces: make(map[string]*models.NamespaceResourceUsage)}
+}
+
+// addJobs accounts for the usage of the executions of a page of jobs, which are read at once
+func (a *usageAggregate) addJobs(ctx context.Context, store jobstore.Store, jobs []models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	jobIDs := make([]string, len(jobs))
+	for i := range jobs {
+		jobIDs[i] = jobs[i].ID
+	}
+	executions, err := store.GetExecutionsForJobs(ctx, jobIDs)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve executions of jobs: %w", err)
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		usage := models.JobResourceUsage{JobID: job.ID, Namespace: job.Namespace}
+		for _, execution := range executions[job.ID] {
+			if execution.ResourceUsage.IsZero() {
+				continue
+			}
+			usage.Executions++
+			usage.Usage.Add(execution.ResourceUsage)
+		}
+		if usage.Executions == 0 {
+			continue
+		}
+		a.jobs = append(a.jobs, usage)
+
+		if _, ok := a.namespaces[job.Namespace]; !ok {
+			a.namespaces[job.Namespace] = &models.NamespaceResourceUsage{Namespace: job.Namespace}
+		}
+		ns := a.namespaces[job.Namespace]
+		ns.Jobs++
+		ns.Executions += usage.Executions
+		ns.Usage.Add(&usage.Usage)
+	}
+	return nil
+}
+
+// results returns the usage of each job, and of each namespace sorted by name
+func (a *usageAggregate) results() ([]models.JobResourceUsage, []models.NamespaceResourceUsage, error) {
+	jobs := a.jobs
+	if jobs == nil {
+		jobs = []models.JobResourceUsage{}
+	}
+	namespaces := make([]models.NamespaceResourceUsage, 0, len(a.namespaces))
+	for _, ns := range a.namespaces {
+		namespaces = append(namespaces, *ns)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Namespace < namespaces[j].Namespace
+	})
+	return jobs, namespaces, nil
+}
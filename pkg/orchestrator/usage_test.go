@@ -0,0 +1,106 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type UsageTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockJobStore *jobstore.MockStore
+	executions   map[string][]models.Execution
+}
+
+func TestUsageTestSuite(t *testing.T) {
+	suite.Run(t, new(UsageTestSuite))
+}
+
+func (s *UsageTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockJobStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+	s.executions = make(map[string][]models.Execution)
+	s.mockJobStore.EXPECT().GetExecutionsForJobs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, jobIDs []string) (map[string][]models.Execution, error) {
+			executions := make(map[string][]models.Execution)
+			for _, jobID := range jobIDs {
+				executions[jobID] = s.executions[jobID]
+			}
+			return executions, nil
+		}).AnyTimes()
+}
+
+func (s *UsageTestSuite) job(namespace string, usage ...*models.ResourceUsage) models.Job {
+	job := mock.Job()
+	job.Namespace = namespace
+	executions := make([]models.Execution, 0, len(usage))
+	for _, u := range usage {
+		execution := mock.ExecutionForJob(job)
+		execution.ResourceUsage = u
+		executions = append(executions, *execution)
+	}
+	s.executions[job.ID] = executions
+	return *job
+}
+
+func (s *UsageTestSuite) TestGetResourceUsage() {
+	a1 := s.job("a",
+		&models.ResourceUsage{CPUSeconds: 1, PeakMemory: 100, NetworkRxBytes: 10},
+		&models.ResourceUsage{CPUSeconds: 2, PeakMemory: 300, NetworkRxBytes: 20},
+		nil,
+	)
+	a2 := s.job("a", &models.ResourceUsage{CPUSeconds: 4, PeakMemory: 200, DiskWriteBytes: 5})
+	b := s.job("b", &models.ResourceUsage{CPUSeconds: 8})
+	noUsage := s.job("c", nil)
+	// jobs are read in pages
+	query := jobstore.JobQuery{Limit: usagePageSize, SortBy: "created_at"}
+	s.mockJobStore.EXPECT().GetJobs(gomock.Any(), query).Return(&jobstore.JobQueryResponse{
+		Jobs: []models.Job{a1, a2}, NextOffset: usagePageSize,
+	}, nil)
+	query.Offset = usagePageSize
+	s.mockJobStore.EXPECT().GetJobs(gomock.Any(), query).Return(&jobstore.JobQueryResponse{
+		Jobs: []models.Job{b, noUsage},
+	}, nil)
+
+	jobs, namespaces, err := GetResourceUsage(s.ctx, s.mockJobStore, "", "")
+	s.Require().NoError(err)
+	s.Equal([]models.JobResourceUsage{
+		{JobID: a1.ID, Namespace: "a", Executions: 2,
+			Usage: models.ResourceUsage{CPUSeconds: 3, PeakMemory: 300, NetworkRxBytes: 30}},
+		{JobID: a2.ID, Namespace: "a", Executions: 1,
+			Usage: models.ResourceUsage{CPUSeconds: 4, PeakMemory: 200, DiskWriteBytes: 5}},
+		{JobID: b.ID, Namespace: "b", Executions: 1,
+			Usage: models.ResourceUsage{CPUSeconds: 8}},
+	}, jobs)
+	s.Equal([]models.NamespaceResourceUsage{
+		{Namespace: "a", Jobs: 2, Executions: 3,
+			Usage: models.ResourceUsage{CPUSeconds: 7, PeakMemory: 300, DiskWriteBytes: 5, NetworkRxBytes: 30}},
+		{Namespace: "b", Jobs: 1, Executions: 1,
+			Usage: models.ResourceUsage{CPUSeconds: 8}},
+	}, namespaces)
+}
+
+func (s *UsageTestSuite) TestGetResourceUsage_SingleJob() {
+	job := s.job("a", &models.ResourceUsage{CPUSeconds: 1})
+	s.mockJobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(job, nil).Times(2)
+
+	jobs, namespaces, err := GetResourceUsage(s.ctx, s.mockJobStore, "a", job.ID)
+	s.Require().NoError(err)
+	s.Len(jobs, 1)
+	s.Len(namespaces, 1)
+
+	// a job in another namespace is not visible
+	jobs, namespaces, err = GetResourceUsage(s.ctx, s.mockJobStore, "b", job.ID)
+	s.Require().NoError(err)
+	s.Empty(jobs)
+	s.Empty(namespaces)
+}
@@ -187,3 +187,35 @@ func (o *GetLogsRequest) ToHTTPRequest() *HTTPRequest {
 	}
 	return r
 }
+
+type ExecRequest struct {
+	BaseGetRequest
+	JobID       string   `query:"-"`
+	ExecutionID string   `query:"execution_id" validate:"omitempty"`
+	Command     []string `query:"command"`
+	Attach      bool     `query:"attach"`
+	TTY         bool     `query:"tty"`
+	Stdin       bool     `query:"stdin"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ExecRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+
+	if o.ExecutionID != "" {
+		r.Params.Set("execution_id", o.ExecutionID)
+	}
+	for _, arg := range o.Command {
+		r.Params.Add("command", arg)
+	}
+	if o.Attach {
+		r.Params.Set("attach", "true")
+	}
+	if o.TTY {
+		r.Params.Set("tty", "true")
+	}
+	if o.Stdin {
+		r.Params.Set("stdin", "true")
+	}
+	return r
+}
@@ -0,0 +1,75 @@
+package apimodels
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// Namespace describes a namespace that has a quota or active executions
+type Namespace struct {
+	Name string
+	// Quota is the quota of the namespace, or nil if the namespace has no quota
+	Quota *models.NamespaceQuota
+	Usage models.NamespaceUsage
+}
+
+type GetNamespaceRequest struct {
+	BaseGetRequest
+	Name string `query:"-"`
+}
+
+type GetNamespaceResponse struct {
+	BaseGetResponse
+	Namespace *Namespace
+}
+
+type ListNamespacesRequest struct {
+	BaseListRequest
+}
+
+type ListNamespacesResponse struct {
+	BaseListResponse
+	Namespaces []*Namespace
+}
+
+type PutNamespaceQuotaRequest struct {
+	BasePutRequest
+	Name  string `json:"-"`
+	Quota *models.NamespaceQuota
+}
+
+type PutNamespaceQuotaResponse struct {
+	BasePutResponse
+	Quota *models.NamespaceQuota
+}
+
+type DeleteNamespaceQuotaRequest struct {
+	BasePutRequest
+	Name string `json:"-"`
+}
+
+type DeleteNamespaceQuotaResponse struct {
+	BasePutResponse
+}
+
+// GetUsageRequest requests the resources actually used by jobs. The namespace of the base request
+// selects the jobs of a namespace, and all namespaces are reported if it is empty or "*".
+type GetUsageRequest struct {
+	BaseGetRequest
+	JobID string `query:"job_id"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *GetUsageRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+
+	if o.JobID != "" {
+		r.Params.Set("job_id", o.JobID)
+	}
+	return r
+}
+
+type GetUsageResponse struct {
+	BaseGetResponse
+	Jobs       []models.JobResourceUsage
+	Namespaces []models.NamespaceResourceUsage
+}
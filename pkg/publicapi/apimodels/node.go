@@ -1,6 +1,8 @@
 package apimodels
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -52,6 +54,9 @@ type PutNodeRequest struct {
 	Action  string
 	Message string
 	NodeID  string
+	// DrainTimeout is the time executions are given to move off the node when draining it.
+	// Defaults to models.DefaultNodeDrainTimeout.
+	DrainTimeout time.Duration
 }
 
 type PutNodeResponse struct {
@@ -63,9 +68,12 @@ type PutNodeResponse struct {
 type NodeAction string
 
 const (
-	NodeActionApprove NodeAction = "approve"
-	NodeActionReject  NodeAction = "reject"
-	NodeActionDelete  NodeAction = "delete"
+	NodeActionApprove  NodeAction = "approve"
+	NodeActionReject   NodeAction = "reject"
+	NodeActionDelete   NodeAction = "delete"
+	NodeActionCordon   NodeAction = "cordon"
+	NodeActionUncordon NodeAction = "uncordon"
+	NodeActionDrain    NodeAction = "drain"
 )
 
 func (n NodeAction) Description() string {
@@ -76,10 +84,20 @@ func (n NodeAction) Description() string {
 		return "Reject a node whose membership is pending"
 	case NodeActionDelete:
 		return "Delete a node from the cluster."
+	case NodeActionCordon:
+		return "Stop placing new executions on a node."
+	case NodeActionUncordon:
+		return "Allow placing new executions on a cordoned or drained node again."
+	case NodeActionDrain:
+		return "Cordon a node and move its executions to other nodes."
 	}
 	return ""
 }
 
 func (n NodeAction) IsValid() bool {
-	return n == NodeActionApprove || n == NodeActionReject || n == NodeActionDelete
+	switch n {
+	case NodeActionApprove, NodeActionReject, NodeActionDelete, NodeActionCordon, NodeActionUncordon, NodeActionDrain:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,37 @@
+package apimodels
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ListSecretsRequest lists the secrets of the namespace of the base request, without their values
+type ListSecretsRequest struct {
+	BaseListRequest
+}
+
+type ListSecretsResponse struct {
+	BaseListResponse
+	Secrets []*models.Secret
+}
+
+// PutSecretRequest creates or replaces a secret in the namespace of the base request
+type PutSecretRequest struct {
+	BasePutRequest
+	Name  string `json:"-"`
+	Value string
+}
+
+type PutSecretResponse struct {
+	BasePutResponse
+	// Secret is the stored secret, without its value
+	Secret *models.Secret
+}
+
+type DeleteSecretRequest struct {
+	BasePutRequest
+	Name string `json:"-"`
+}
+
+type DeleteSecretResponse struct {
+	BasePutResponse
+}
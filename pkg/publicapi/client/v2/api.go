@@ -7,7 +7,9 @@ type API interface {
 	Agent() *Agent
 	Auth() *Auth
 	Jobs() *Jobs
+	Namespaces() *Namespaces
 	Nodes() *Nodes
+	Secrets() *Secrets
 }
 
 type api struct {
@@ -26,10 +28,18 @@ func (c *api) Jobs() *Jobs {
 	return &Jobs{client: c.Client}
 }
 
+func (c *api) Namespaces() *Namespaces {
+	return &Namespaces{client: c.Client}
+}
+
 func (c *api) Nodes() *Nodes {
 	return &Nodes{client: c.Client}
 }
 
+func (c *api) Secrets() *Secrets {
+	return &Secrets{client: c.Client}
+}
+
 func NewAPI(transport Client) API {
 	return &api{Client: transport}
 }
@@ -82,3 +82,12 @@ func (j *Jobs) Stop(ctx context.Context, r *apimodels.StopJobRequest) (*apimodel
 func (j *Jobs) Logs(ctx context.Context, r *apimodels.GetLogsRequest) (<-chan *concurrency.AsyncResult[models.ExecutionLog], error) {
 	return DialAsyncResult[*apimodels.GetLogsRequest, models.ExecutionLog](ctx, j.client, jobsPath+"/"+r.JobID+"/logs", r)
 }
+
+// Exec runs a command in, or attaches to, a running execution of a job. Messages sent on input are forwarded
+// to the command, and closing input detaches from it. The last message of the returned stream holds the exit
+// code of the command.
+func (j *Jobs) Exec(ctx context.Context, r *apimodels.ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	return DialDuplexAsyncResult[*apimodels.ExecRequest, models.ExecInput, models.ExecOutput](
+		ctx, j.client, jobsPath+"/"+r.JobID+"/exec", r, input)
+}
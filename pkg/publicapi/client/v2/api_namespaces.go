@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const (
+	namespacesPath = "/api/v1/orchestrator/namespaces"
+	usagePath      = "/api/v1/orchestrator/usage"
+)
+
+type Namespaces struct {
+	client Client
+}
+
+// Get is used to get a namespace's quota and usage by name.
+func (n *Namespaces) Get(ctx context.Context, r *apimodels.GetNamespaceRequest) (*apimodels.GetNamespaceResponse, error) {
+	var resp apimodels.GetNamespaceResponse
+	if err := n.client.Get(ctx, namespacesPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// List is used to list the namespaces that have a quota or active executions.
+func (n *Namespaces) List(ctx context.Context, r *apimodels.ListNamespacesRequest) (*apimodels.ListNamespacesResponse, error) {
+	var resp apimodels.ListNamespacesResponse
+	if err := n.client.List(ctx, namespacesPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PutQuota is used to create or replace the quota of a namespace.
+func (n *Namespaces) PutQuota(ctx context.Context, r *apimodels.PutNamespaceQuotaRequest) (*apimodels.PutNamespaceQuotaResponse, error) {
+	var resp apimodels.PutNamespaceQuotaResponse
+	if err := n.client.Put(ctx, namespacesPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteQuota is used to remove the quota of a namespace.
+func (n *Namespaces) DeleteQuota(
+	ctx context.Context, r *apimodels.DeleteNamespaceQuotaRequest) (*apimodels.DeleteNamespaceQuotaResponse, error) {
+	var resp apimodels.DeleteNamespaceQuotaResponse
+	if err := n.client.Delete(ctx, namespacesPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Usage is used to get the resources actually used by the jobs of a namespace, or of all namespaces.
+func (n *Namespaces) Usage(ctx context.Context, r *apimodels.GetUsageRequest) (*apimodels.GetUsageResponse, error) {
+	var resp apimodels.GetUsageResponse
+	if err := n.client.Get(ctx, usagePath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
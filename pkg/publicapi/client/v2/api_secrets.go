@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const (
+	secretsPath = "/api/v1/orchestrator/secrets"
+)
+
+type Secrets struct {
+	client Client
+}
+
+// List is used to list the secrets of a namespace, without their values.
+func (s *Secrets) List(ctx context.Context, r *apimodels.ListSecretsRequest) (*apimodels.ListSecretsResponse, error) {
+	var resp apimodels.ListSecretsResponse
+	if err := s.client.List(ctx, secretsPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Put is used to create or replace a secret of a namespace.
+func (s *Secrets) Put(ctx context.Context, r *apimodels.PutSecretRequest) (*apimodels.PutSecretResponse, error) {
+	var resp apimodels.PutSecretResponse
+	if err := s.client.Put(ctx, secretsPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Delete is used to remove a secret of a namespace.
+func (s *Secrets) Delete(ctx context.Context, r *apimodels.DeleteSecretRequest) (*apimodels.DeleteSecretResponse, error) {
+	var resp apimodels.DeleteSecretResponse
+	if err := s.client.Delete(ctx, secretsPath+"/"+r.Name, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,6 +26,7 @@ type Client interface {
 	Post(context.Context, string, apimodels.PutRequest, apimodels.PutResponse) error
 	Delete(context.Context, string, apimodels.PutRequest, apimodels.Response) error
 	Dial(context.Context, string, apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error)
+	DialDuplex(context.Context, string, apimodels.Request, <-chan []byte) (<-chan *concurrency.AsyncResult[[]byte], error)
 }
 
 // New creates a new transport.
@@ -123,6 +125,18 @@ func (c *httpClient) Delete(ctx context.Context, endpoint string, in apimodels.P
 // successfully dialed, from which point on the returned channel will contain
 // every received message.
 func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Request) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	return c.DialDuplex(ctx, endpoint, in, nil)
+}
+
+// DialDuplex is like Dial, but also sends every message received on the send
+// channel to the endpoint. The connection is closed once the send channel is
+// closed, which tells the endpoint that the client is gone.
+func (c *httpClient) DialDuplex(
+	ctx context.Context,
+	endpoint string,
+	in apimodels.Request,
+	send <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
 	r := in.ToHTTPRequest()
 	httpR, err := c.toHTTP(ctx, http.MethodGet, endpoint, r)
 	if err != nil {
@@ -145,14 +159,36 @@ func (c *httpClient) Dial(ctx context.Context, endpoint string, in apimodels.Req
 	}
 	defer resp.Body.Close()
 
+	// Websocket connections support a single concurrent writer
+	var writeMu sync.Mutex
+	closeConn := func() {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.Close()
+	}
+
+	if send != nil {
+		go func() {
+			for msg := range send {
+				writeMu.Lock()
+				err := conn.WriteMessage(websocket.TextMessage, msg)
+				writeMu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+			closeConn()
+		}()
+	}
+
 	// Read messages from the server, and send them until the conn is closed or
 	// the context is cancelled. We have to read them here because the reader
 	// will be discarded upon the next call to NextReader.
 	output := make(chan *concurrency.AsyncResult[[]byte], c.config.WebsocketChannelBuffer)
 	go func() {
 		defer func() {
-			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-			conn.Close()
+			closeConn()
 			close(output)
 		}()
 
@@ -356,6 +392,20 @@ func (t *AuthenticatingClient) Dial(
 	return output, err
 }
 
+func (t *AuthenticatingClient) DialDuplex(
+	ctx context.Context,
+	path string,
+	in apimodels.Request,
+	send <-chan []byte,
+) (<-chan *concurrency.AsyncResult[[]byte], error) {
+	var output <-chan *concurrency.AsyncResult[[]byte]
+	err := doRequest(ctx, t, in, func(req apimodels.Request) (err error) {
+		output, err = t.Client.DialDuplex(ctx, path, req, send)
+		return
+	})
+	return output, err
+}
+
 func doRequest[R apimodels.Request](ctx context.Context, t *AuthenticatingClient, request R, runRequest func(R) error) (err error) {
 	if t.Credential != nil {
 		request.SetCredential(t.Credential)
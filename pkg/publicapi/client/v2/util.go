@@ -109,12 +109,43 @@ func DialAsyncResult[In apimodels.Request, Out any](
 	endpoint string,
 	r In,
 ) (<-chan *concurrency.AsyncResult[Out], error) {
-	output := make(chan *concurrency.AsyncResult[Out])
-
 	input, err := client.Dial(ctx, endpoint, r)
 	if err != nil {
 		return nil, err
 	}
+	return decodeAsyncResults[Out](input), nil
+}
+
+// DialDuplexAsyncResult is like DialAsyncResult, but also encodes every
+// message received on the send channel and sends it to the endpoint.
+func DialDuplexAsyncResult[In apimodels.Request, Send any, Out any](
+	ctx context.Context,
+	client Client,
+	endpoint string,
+	r In,
+	send <-chan Send,
+) (<-chan *concurrency.AsyncResult[Out], error) {
+	encoded := make(chan []byte)
+	input, err := client.DialDuplex(ctx, endpoint, r, encoded)
+	if err != nil {
+		close(encoded)
+		return nil, err
+	}
+	go func() {
+		defer close(encoded)
+		for msg := range send {
+			data, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			encoded <- data
+		}
+	}()
+	return decodeAsyncResults[Out](input), nil
+}
+
+func decodeAsyncResults[Out any](input <-chan *concurrency.AsyncResult[[]byte]) <-chan *concurrency.AsyncResult[Out] {
+	output := make(chan *concurrency.AsyncResult[Out])
 	go func() {
 		for result := range input {
 			outResult := new(concurrency.AsyncResult[Out])
@@ -128,6 +159,5 @@ func DialAsyncResult[In apimodels.Request, Out any](
 		}
 		close(output)
 	}()
-
-	return output, nil
+	return output
 }
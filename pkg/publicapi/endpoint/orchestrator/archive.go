@@ -0,0 +1,63 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// jobReader reads a job along with its history and executions
+type jobReader interface {
+	GetJob(ctx context.Context, id string) (models.Job, error)
+	GetJobHistory(ctx context.Context, jobID string, options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error)
+	GetExecutions(ctx context.Context, options jobstore.GetExecutionsOptions) ([]models.Execution, error)
+}
+
+// jobReaderFor returns the reader of the job, which is the job store unless the job was removed
+// from it and archived, in which case the job is read from the archive.
+func (e *Endpoint) jobReaderFor(ctx context.Context, jobID string) (jobReader, error) {
+	if e.archive == nil {
+		return e.store, nil
+	}
+	if _, err := e.store.GetJob(ctx, jobID); !errors.As(err, new(*bacerrors.JobNotFound)) {
+		return e.store, nil
+	}
+
+	archived, err := e.archive.Get(ctx, jobID)
+	if errors.As(err, new(*bacerrors.JobNotFound)) {
+		// let the job store report the job as not found
+		return e.store, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return archivedJobReader{job: archived}, nil
+}
+
+// archivedJobReader reads a job from its archive
+type archivedJobReader struct {
+	job archive.ArchivedJob
+}
+
+func (r archivedJobReader) GetJob(context.Context, string) (models.Job, error) {
+	return r.job.Job, nil
+}
+
+func (r archivedJobReader) GetJobHistory(
+	_ context.Context, _ string, options jobstore.JobHistoryFilterOptions) ([]models.JobHistory, error) {
+	return r.job.GetHistory(options), nil
+}
+
+func (r archivedJobReader) GetExecutions(_ context.Context, options jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+	executions := make([]models.Execution, len(r.job.Executions))
+	copy(executions, r.job.Executions)
+	if options.IncludeJob {
+		for i := range executions {
+			executions[i].Job = &r.job.Job
+		}
+	}
+	return executions, nil
+}
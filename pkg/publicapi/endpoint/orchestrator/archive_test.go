@@ -0,0 +1,108 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
+	boltjobstore "github.com/bacalhau-project/bacalhau/pkg/jobstore/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type ArchivedJobsTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	router  *echo.Echo
+	store   jobstore.Store
+	archive *archive.LocalArchive
+}
+
+func TestArchivedJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(ArchivedJobsTestSuite))
+}
+
+func (s *ArchivedJobsTestSuite) SetupTest() {
+	var err error
+	s.ctx = context.Background()
+	s.store, err = boltjobstore.NewBoltJobStore(filepath.Join(s.T().TempDir(), "jobs.db"))
+	s.Require().NoError(err)
+	s.archive, err = archive.NewLocalArchive(s.T().TempDir())
+	s.Require().NoError(err)
+
+	s.router = echo.New()
+	s.router.Validator = publicapi.NewCustomValidator()
+	NewEndpoint(EndpointParams{
+		Router:     s.router,
+		JobStore:   s.store,
+		JobArchive: s.archive,
+	})
+}
+
+func (s *ArchivedJobsTestSuite) TearDownTest() {
+	s.Require().NoError(s.store.Close(s.ctx))
+}
+
+func (s *ArchivedJobsTestSuite) get(path string, response interface{}) int {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code == http.StatusOK {
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), response))
+	}
+	return rec.Code
+}
+
+func (s *ArchivedJobsTestSuite) TestGetArchivedJob() {
+	job := mock.Job()
+	execution := mock.ExecutionForJob(job)
+	s.Require().NoError(s.archive.Put(s.ctx, archive.ArchivedJob{
+		Job:        *job,
+		Executions: []models.Execution{*execution},
+		History:    []models.JobHistory{{Type: models.JobHistoryTypeJobLevel, JobID: job.ID, Time: time.Now()}},
+	}))
+
+	var response apimodels.GetJobResponse
+	s.Require().Equal(http.StatusOK, s.get("/api/v1/orchestrator/jobs/"+job.ID+"?include=executions,history", &response))
+	s.Equal(job.ID, response.Job.ID)
+	s.Require().Len(response.Executions.Executions, 1)
+	s.Equal(execution.ID, response.Executions.Executions[0].ID)
+	s.Len(response.History.History, 1)
+
+	var history apimodels.ListJobHistoryResponse
+	s.Require().Equal(http.StatusOK, s.get("/api/v1/orchestrator/jobs/"+job.ID+"/history", &history))
+	s.Len(history.History, 1)
+
+	var executions apimodels.ListJobExecutionsResponse
+	s.Require().Equal(http.StatusOK, s.get("/api/v1/orchestrator/jobs/"+job.ID+"/executions", &executions))
+	s.Len(executions.Executions, 1)
+}
+
+func (s *ArchivedJobsTestSuite) TestStoredJobTakesPrecedence() {
+	job := mock.Job()
+	s.Require().NoError(s.store.CreateJob(s.ctx, *job, models.Event{}))
+	archived := *job
+	archived.Name = "archived"
+	s.Require().NoError(s.archive.Put(s.ctx, archive.ArchivedJob{Job: archived}))
+
+	var response apimodels.GetJobResponse
+	s.Require().Equal(http.StatusOK, s.get("/api/v1/orchestrator/jobs/"+job.ID, &response))
+	s.Equal(job.Name, response.Job.Name)
+}
+
+func (s *ArchivedJobsTestSuite) TestJobNotFound() {
+	var response apimodels.GetJobResponse
+	s.NotEqual(http.StatusOK, s.get("/api/v1/orchestrator/jobs/j-missing", &response))
+}
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore/archive"
 	"github.com/bacalhau-project/bacalhau/pkg/node/manager"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
@@ -12,14 +13,20 @@ type EndpointParams struct {
 	Router       *echo.Echo
 	Orchestrator *orchestrator.BaseEndpoint
 	JobStore     jobstore.Store
-	NodeManager  *manager.NodeManager
+	// JobArchive is where jobs removed from the job store are read from. It is optional.
+	JobArchive  archive.Archive
+	NodeManager *manager.NodeManager
+	// Secrets manages the secrets of namespaces. Secrets are not enabled if it is not set.
+	Secrets *orchestrator.SecretManager
 }
 
 type Endpoint struct {
 	router       *echo.Echo
 	orchestrator *orchestrator.BaseEndpoint
 	store        jobstore.Store
+	archive      archive.Archive
 	nodeManager  *manager.NodeManager
+	secrets      *orchestrator.SecretManager
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
@@ -27,7 +34,9 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 		router:       params.Router,
 		orchestrator: params.Orchestrator,
 		store:        params.JobStore,
+		archive:      params.JobArchive,
 		nodeManager:  params.NodeManager,
+		secrets:      params.Secrets,
 	}
 
 	// JSON group
@@ -42,8 +51,17 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 	g.GET("/jobs/:id/executions", e.jobExecutions)
 	g.GET("/jobs/:id/results", e.jobResults)
 	g.GET("/jobs/:id/logs", e.logs)
+	g.GET("/jobs/:id/exec", e.exec)
 	g.GET("/nodes", e.listNodes)
 	g.GET("/nodes/:id", e.getNode)
 	g.PUT("/nodes/:id", e.updateNode)
+	g.GET("/namespaces", e.listNamespaces)
+	g.GET("/namespaces/:namespace", e.getNamespace)
+	g.PUT("/namespaces/:namespace", e.putNamespaceQuota)
+	g.DELETE("/namespaces/:namespace", e.deleteNamespaceQuota)
+	g.GET("/usage", e.getUsage)
+	g.GET("/secrets", e.listSecrets)
+	g.PUT("/secrets/:name", e.putSecret)
+	g.DELETE("/secrets/:name", e.deleteSecret)
 	return e
 }
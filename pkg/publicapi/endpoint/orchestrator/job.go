@@ -76,7 +76,11 @@ func (e *Endpoint) getJob(c echo.Context) error {
 	if err := c.Bind(&args); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	job, err := e.store.GetJob(ctx, jobID)
+	reader, err := e.jobReaderFor(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	job, err := reader.GetJob(ctx, jobID)
 	if err != nil {
 		return err
 	}
@@ -92,7 +96,7 @@ func (e *Endpoint) getJob(c echo.Context) error {
 			if response.History != nil {
 				continue
 			}
-			history, err := e.store.GetJobHistory(ctx, jobID, jobstore.JobHistoryFilterOptions{})
+			history, err := reader.GetJobHistory(ctx, jobID, jobstore.JobHistoryFilterOptions{})
 			if err != nil {
 				return err
 			}
@@ -107,7 +111,7 @@ func (e *Endpoint) getJob(c echo.Context) error {
 			if response.Executions != nil {
 				continue
 			}
-			executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+			executions, err := reader.GetExecutions(ctx, jobstore.GetExecutionsOptions{
 				JobID: jobID,
 			})
 			if err != nil {
@@ -293,7 +297,11 @@ func (e *Endpoint) jobHistory(c echo.Context) error {
 		ExecutionID:           args.ExecutionID,
 		NodeID:                args.NodeID,
 	}
-	history, err := e.store.GetJobHistory(ctx, jobID, options)
+	reader, err := e.jobReaderFor(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	history, err := reader.GetJobHistory(ctx, jobID, options)
 	if err != nil {
 		return err
 	}
@@ -367,7 +375,11 @@ func (e *Endpoint) jobExecutions(c echo.Context) error {
 	}
 
 	// query executions
-	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+	reader, err := e.jobReaderFor(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	executions, err := reader.GetExecutions(ctx, jobstore.GetExecutionsOptions{
 		JobID: jobID,
 	})
 	if err != nil {
@@ -498,3 +510,97 @@ func (e *Endpoint) logsWS(c echo.Context, ws *websocket.Conn) error {
 	}
 	return nil
 }
+
+// godoc for Orchestrator JobExec
+//
+// @ID				orchestrator/exec
+// @Summary			Runs a command in, or attaches to, a running execution of a job
+// @Description		Opens a bidirectional stream with a command run in the execution of the job specified by `id`.
+// @Description		The client sends the input of the command, and receives its output followed by its exit code.
+// @Description		The stream ends when the command exits or the client disconnects.
+// @Tags			Orchestrator
+// @Accept			json
+// @Produce			json
+// @Param			id				path	string		true	"ID of the job to exec into"
+// @Param			execution_id	query 	string		false	"Exec into a specific execution"
+// @Param			namespace		query 	string		false	"Namespace of the job"
+// @Param			command			query	[]string	false	"Command to run, and its arguments"
+// @Param			attach			query	bool		false	"Attach to the main process instead of running a command"
+// @Param			tty				query	bool		false	"Allocate a terminal for the command"
+// @Param			stdin			query	bool		false	"Forward input to the command"
+// @Success		200			{object}	string
+// @Failure		400			{object}	string
+// @Failure		500			{object}	string
+// @Router			/api/v1/orchestrator/jobs/{id}/exec [get]
+func (e *Endpoint) exec(c echo.Context) error {
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade websocket connection: %w", err)
+	}
+	defer ws.Close()
+
+	err = e.execWS(c, ws)
+	if err != nil {
+		log.Ctx(c.Request().Context()).Error().Err(err).Msg("websocket failure")
+		err = ws.WriteJSON(concurrency.AsyncResult[models.ExecOutput]{
+			Err: err,
+		})
+		if err != nil {
+			log.Ctx(c.Request().Context()).Error().Err(err).Msg("failed to write error to websocket")
+		}
+	}
+	_ = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
+
+func (e *Endpoint) execWS(c echo.Context, ws *websocket.Conn) error {
+	jobID := c.Param("id")
+	var args apimodels.ExecRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+	if args.Namespace == "" {
+		args.Namespace = models.DefaultNamespace
+	}
+
+	// the input of the client is read until it disconnects, which detaches it from the command
+	ctx := c.Request().Context()
+	input := make(chan models.ExecInput)
+	go func() {
+		defer close(input)
+		for {
+			var msg models.ExecInput
+			if err := ws.ReadJSON(&msg); err != nil {
+				return
+			}
+			select {
+			case input <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	outputCh, err := e.orchestrator.Exec(ctx, orchestrator.ExecRequest{
+		JobID:       jobID,
+		ExecutionID: args.ExecutionID,
+		Namespace:   args.Namespace,
+		Command:     args.Command,
+		Attach:      args.Attach,
+		TTY:         args.TTY,
+		Stdin:       args.Stdin,
+	}, input)
+	if err != nil {
+		return fmt.Errorf("failed to exec into job %s: %w", jobID, err)
+	}
+
+	for output := range outputCh {
+		if err = ws.WriteJSON(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
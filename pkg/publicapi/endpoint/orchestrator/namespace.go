@@ -0,0 +1,242 @@
+package orchestrator
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator GetNamespace
+//
+// @ID			orchestrator/getNamespace
+// @Summary		Returns the quota and usage of a namespace.
+// @Description	Returns the quota and usage of a namespace.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Name of the namespace"
+// @Success		200	{object}	apimodels.GetNamespaceResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/namespaces/{namespace} [get]
+func (e *Endpoint) getNamespace(c echo.Context) error {
+	ctx := c.Request().Context()
+	name := c.Param("namespace")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing namespace")
+	}
+
+	namespace := &apimodels.Namespace{Name: name, Usage: models.NamespaceUsage{Namespace: name}}
+	quota, err := e.store.GetNamespaceQuota(ctx, name)
+	if err == nil {
+		namespace.Quota = &quota
+	} else if !errors.As(err, new(jobstore.ErrNamespaceQuotaNotFound)) {
+		return err
+	}
+
+	usage, err := orchestrator.GetNamespaceUsage(ctx, e.store, name, nil)
+	if err != nil {
+		return err
+	}
+	if u, ok := usage[name]; ok {
+		namespace.Usage = *u
+	}
+	return c.JSON(http.StatusOK, apimodels.GetNamespaceResponse{
+		Namespace: namespace,
+	})
+}
+
+// godoc for Orchestrator ListNamespaces
+//
+// @ID			orchestrator/listNamespaces
+// @Summary		Returns the namespaces that have a quota or active executions.
+// @Description	Returns the namespaces that have a quota or active executions, with their quota and usage.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Success		200	{object}	apimodels.ListNamespacesResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/namespaces [get]
+func (e *Endpoint) listNamespaces(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.ListNamespacesRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	quotas, err := e.store.GetNamespaceQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	usage, err := orchestrator.GetNamespaceUsage(ctx, e.store, "", nil)
+	if err != nil {
+		return err
+	}
+
+	namespaces := make(map[string]*apimodels.Namespace)
+	get := func(name string) *apimodels.Namespace {
+		if _, ok := namespaces[name]; !ok {
+			namespaces[name] = &apimodels.Namespace{Name: name, Usage: models.NamespaceUsage{Namespace: name}}
+		}
+		return namespaces[name]
+	}
+	for i := range quotas {
+		get(quotas[i].Namespace).Quota = &quotas[i]
+	}
+	for name, u := range usage {
+		get(name).Usage = *u
+	}
+
+	res := make([]*apimodels.Namespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		res = append(res, namespace)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if args.Reverse {
+			return res[i].Name > res[j].Name
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	// apply limit
+	if args.Limit > 0 && len(res) > int(args.Limit) {
+		res = res[:args.Limit]
+	}
+
+	return c.JSON(http.StatusOK, &apimodels.ListNamespacesResponse{
+		Namespaces: res,
+	})
+}
+
+// godoc for Orchestrator PutNamespaceQuota
+//
+// @ID			orchestrator/putNamespaceQuota
+// @Summary		Creates or replaces the quota of a namespace.
+// @Description	Creates or replaces the quota of a namespace.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Name of the namespace"
+// @Param			quota	body	apimodels.PutNamespaceQuotaRequest	true	"Quota of the namespace"
+// @Success		200	{object}	apimodels.PutNamespaceQuotaResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/namespaces/{namespace} [put]
+func (e *Endpoint) putNamespaceQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	name := c.Param("namespace")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing namespace")
+	}
+
+	var args apimodels.PutNamespaceQuotaRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+	if args.Quota == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing quota")
+	}
+
+	args.Quota.Namespace = name
+	args.Quota.Normalize()
+	if err := args.Quota.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := e.store.PutNamespaceQuota(ctx, *args.Quota); err != nil {
+		return err
+	}
+
+	quota, err := e.store.GetNamespaceQuota(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.PutNamespaceQuotaResponse{
+		Quota: &quota,
+	})
+}
+
+// godoc for Orchestrator DeleteNamespaceQuota
+//
+// @ID			orchestrator/deleteNamespaceQuota
+// @Summary		Removes the quota of a namespace.
+// @Description	Removes the quota of a namespace, so that its executions are no longer limited.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	path	string	true	"Name of the namespace"
+// @Success		200	{object}	apimodels.DeleteNamespaceQuotaResponse
+// @Failure		400	{object}	string
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/namespaces/{namespace} [delete]
+func (e *Endpoint) deleteNamespaceQuota(c echo.Context) error {
+	ctx := c.Request().Context()
+	name := c.Param("namespace")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing namespace")
+	}
+
+	var args apimodels.DeleteNamespaceQuotaRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := e.store.DeleteNamespaceQuota(ctx, name); err != nil {
+		if errors.As(err, new(jobstore.ErrNamespaceQuotaNotFound)) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.DeleteNamespaceQuotaResponse{})
+}
+
+// godoc for Orchestrator GetUsage
+//
+// @ID			orchestrator/getUsage
+// @Summary		Returns the resources actually used by jobs.
+// @Description	Returns the resources used by the executions of each job, as measured by the compute nodes, and their totals per namespace.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	query	string	false	"Namespace of the jobs, or all namespaces if empty or *"
+// @Param			job_id		query	string	false	"Only report the usage of this job"
+// @Success		200	{object}	apimodels.GetUsageResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/usage [get]
+func (e *Endpoint) getUsage(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.GetUsageRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	namespace := args.Namespace
+	if namespace == apimodels.AllNamespacesNamespace {
+		namespace = ""
+	}
+	jobs, namespaces, err := orchestrator.GetResourceUsage(ctx, e.store, namespace, args.JobID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.GetUsageResponse{
+		Jobs:       jobs,
+		Namespaces: namespaces,
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/util"
 )
@@ -170,6 +171,12 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 		action = e.nodeManager.RejectAction
 	} else if args.Action == string(apimodels.NodeActionDelete) {
 		action = e.nodeManager.DeleteAction
+	} else if args.Action == string(apimodels.NodeActionCordon) {
+		action = e.nodeManager.CordonAction
+	} else if args.Action == string(apimodels.NodeActionUncordon) {
+		action = e.nodeManager.UncordonAction
+	} else if args.Action == string(apimodels.NodeActionDrain) {
+		return e.drainNode(c, nodeID, args)
 	} else {
 		action = func(context.Context, string, string) (bool, string) {
 			return false, "unsupported action"
@@ -182,3 +189,22 @@ func (e *Endpoint) updateNode(c echo.Context) error {
 		Error:   msg,
 	})
 }
+
+// drainNode cordons the node, and asks the schedulers to migrate the executions of its jobs to other nodes
+func (e *Endpoint) drainNode(c echo.Context, nodeID string, args apimodels.PutNodeRequest) error {
+	ctx := c.Request().Context()
+	success, msg := e.nodeManager.DrainAction(ctx, nodeID, args.Message, args.DrainTimeout)
+	if success {
+		state, err := e.nodeManager.GetByPrefix(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		if _, err = e.orchestrator.DrainNode(ctx, &orchestrator.DrainNodeRequest{NodeID: state.Info.NodeID}); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, apimodels.PutNodeResponse{
+		Success: success,
+		Error:   msg,
+	})
+}
@@ -0,0 +1,159 @@
+package orchestrator
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// godoc for Orchestrator ListSecrets
+//
+// @ID			orchestrator/listSecrets
+// @Summary		Returns the secrets of a namespace.
+// @Description	Returns the names and timestamps of the secrets of a namespace. Their values are never returned.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			namespace	query	string	false	"Namespace of the secrets"
+// @Success		200	{object}	apimodels.ListSecretsResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets [get]
+func (e *Endpoint) listSecrets(c echo.Context) error {
+	ctx := c.Request().Context()
+	if e.secrets == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "secrets are not enabled")
+	}
+	var args apimodels.ListSecretsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	secrets, err := e.secrets.GetSecrets(ctx, secretNamespace(args.Namespace))
+	if err != nil {
+		return err
+	}
+	if args.Reverse {
+		for i, j := 0, len(secrets)-1; i < j; i, j = i+1, j-1 {
+			secrets[i], secrets[j] = secrets[j], secrets[i]
+		}
+	}
+
+	// apply limit
+	if args.Limit > 0 && len(secrets) > int(args.Limit) {
+		secrets = secrets[:args.Limit]
+	}
+
+	res := make([]*models.Secret, 0, len(secrets))
+	for i := range secrets {
+		res = append(res, &secrets[i])
+	}
+	return c.JSON(http.StatusOK, &apimodels.ListSecretsResponse{
+		Secrets: res,
+	})
+}
+
+// godoc for Orchestrator PutSecret
+//
+// @ID			orchestrator/putSecret
+// @Summary		Creates or replaces a secret.
+// @Description	Creates or replaces a secret in a namespace. The value is encrypted at rest, and never returned.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			name		path	string						true	"Name of the secret"
+// @Param			namespace	query	string						false	"Namespace of the secret"
+// @Param			secret		body	apimodels.PutSecretRequest	true	"Value of the secret"
+// @Success		200	{object}	apimodels.PutSecretResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets/{name} [put]
+func (e *Endpoint) putSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+	if e.secrets == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "secrets are not enabled")
+	}
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing secret name")
+	}
+
+	var args apimodels.PutSecretRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	secret := models.Secret{
+		Namespace: secretNamespace(args.Namespace),
+		Name:      name,
+		Value:     args.Value,
+	}
+	secret.Normalize()
+	if err := secret.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	stored, err := e.secrets.PutSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.PutSecretResponse{
+		Secret: &stored,
+	})
+}
+
+// godoc for Orchestrator DeleteSecret
+//
+// @ID			orchestrator/deleteSecret
+// @Summary		Removes a secret.
+// @Description	Removes a secret from a namespace. Executions of jobs that reference it fail if sent to a compute node afterwards.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			name		path	string	true	"Name of the secret"
+// @Param			namespace	query	string	false	"Namespace of the secret"
+// @Success		200	{object}	apimodels.DeleteSecretResponse
+// @Failure		400	{object}	string
+// @Failure		404	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/secrets/{name} [delete]
+func (e *Endpoint) deleteSecret(c echo.Context) error {
+	ctx := c.Request().Context()
+	if e.secrets == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "secrets are not enabled")
+	}
+	name := c.Param("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing secret name")
+	}
+
+	var args apimodels.DeleteSecretRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := e.secrets.DeleteSecret(ctx, secretNamespace(args.Namespace), name); err != nil {
+		if errors.As(err, new(jobstore.ErrSecretNotFound)) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.DeleteSecretResponse{})
+}
+
+// secretNamespace returns the namespace of a secrets request, which is the default namespace if not set
+func secretNamespace(namespace string) string {
+	if namespace == "" {
+		return models.DefaultNamespace
+	}
+	return namespace
+}
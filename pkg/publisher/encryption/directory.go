@@ -0,0 +1,110 @@
+package encryption
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// EncryptDirectory writes the files of sourceDir encrypted for the recipient to targetDir, keeping their paths,
+// along with the envelope that holds the key to decrypt them.
+func EncryptDirectory(sourceDir, targetDir string, recipient *rsa.PublicKey) error {
+	dataKey, envelope, err := newEnvelope(recipient)
+	if err != nil {
+		return err
+	}
+
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relpath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		if relpath == EnvelopeName {
+			// the envelope is what tells encrypted results apart, so it can't be part of the results
+			return fmt.Errorf("results can't be encrypted as they contain a %s file, which is reserved", EnvelopeName)
+		}
+		target := filepath.Join(targetDir, relpath)
+		if info.IsDir() {
+			return os.MkdirAll(target, models.DownloadFolderPerm)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return transformFile(path, target, func(in *os.File, out *os.File) error {
+			return encryptStream(dataKey, filepath.ToSlash(relpath), in, out)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return writeEnvelope(targetDir, envelope)
+}
+
+// DecryptDirectory decrypts in place the files of a result directory encrypted by EncryptDirectory, and
+// returns false if the result is not encrypted.
+func DecryptDirectory(dir string, key *rsa.PrivateKey) (bool, error) {
+	envelope, encrypted, err := readEnvelope(dir)
+	if err != nil || !encrypted {
+		return false, err
+	}
+	if key == nil {
+		return true, errors.New("result is encrypted but no key was provided to decrypt it")
+	}
+	dataKey, err := envelope.open(key)
+	if err != nil {
+		return true, err
+	}
+
+	envelopePath := filepath.Join(dir, EnvelopeName)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || path == envelopePath {
+			return nil
+		}
+		relpath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		decrypted := path + ".decrypted"
+		err = transformFile(path, decrypted, func(in *os.File, out *os.File) error {
+			return decryptStream(dataKey, filepath.ToSlash(relpath), in, out)
+		})
+		if err != nil {
+			_ = os.Remove(decrypted)
+			return err
+		}
+		return os.Rename(decrypted, path)
+	})
+	if err != nil {
+		return true, err
+	}
+	return true, os.Remove(envelopePath)
+}
+
+// transformFile writes the result of transforming the source file to the target file
+func transformFile(source, target string, transform func(in *os.File, out *os.File) error) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, models.DownloadFilePerm)
+	if err != nil {
+		return err
+	}
+	if err = transform(in, out); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,220 @@
+//go:build unit || !integration
+
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type EncryptionSuite struct {
+	suite.Suite
+	key       *rsa.PrivateKey
+	resultDir string
+	files     map[string][]byte
+}
+
+func TestEncryptionSuite(t *testing.T) {
+	suite.Run(t, new(EncryptionSuite))
+}
+
+func (s *EncryptionSuite) SetupSuite() {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+}
+
+func (s *EncryptionSuite) SetupTest() {
+	large := make([]byte, 3*chunkSize+17)
+	_, err := rand.Read(large)
+	s.Require().NoError(err)
+	s.files = map[string][]byte{
+		"stdout":                  []byte("hello"),
+		"stderr":                  {},
+		"outputs/chunk.bin":       bytes.Repeat([]byte{7}, chunkSize),
+		"outputs/nested/large.db": large,
+	}
+	s.resultDir = s.T().TempDir()
+	for path, content := range s.files {
+		file := filepath.Join(s.resultDir, filepath.FromSlash(path))
+		s.Require().NoError(os.MkdirAll(filepath.Dir(file), 0755))
+		s.Require().NoError(os.WriteFile(file, content, 0644))
+	}
+}
+
+func (s *EncryptionSuite) encrypt() string {
+	encryptedDir := s.T().TempDir()
+	s.Require().NoError(EncryptDirectory(s.resultDir, encryptedDir, &s.key.PublicKey))
+	return encryptedDir
+}
+
+func (s *EncryptionSuite) TestRoundTrip() {
+	encryptedDir := s.encrypt()
+	s.FileExists(filepath.Join(encryptedDir, EnvelopeName))
+	for path, content := range s.files {
+		encrypted, err := os.ReadFile(filepath.Join(encryptedDir, filepath.FromSlash(path)))
+		s.Require().NoError(err)
+		if len(content) > 0 {
+			s.False(bytes.Contains(encrypted, content), path)
+		}
+	}
+
+	decrypted, err := DecryptDirectory(encryptedDir, s.key)
+	s.Require().NoError(err)
+	s.True(decrypted)
+	s.NoFileExists(filepath.Join(encryptedDir, EnvelopeName))
+	for path, content := range s.files {
+		actual, err := os.ReadFile(filepath.Join(encryptedDir, filepath.FromSlash(path)))
+		s.Require().NoError(err)
+		s.True(bytes.Equal(content, actual), path)
+	}
+}
+
+func (s *EncryptionSuite) TestDecryptUnencrypted() {
+	decrypted, err := DecryptDirectory(s.resultDir, nil)
+	s.Require().NoError(err)
+	s.False(decrypted)
+}
+
+func (s *EncryptionSuite) TestDecryptWithOtherKey() {
+	encryptedDir := s.encrypt()
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	_, err = DecryptDirectory(encryptedDir, other)
+	s.ErrorContains(err, "not for the client key")
+
+	_, err = DecryptDirectory(encryptedDir, nil)
+	s.ErrorContains(err, "no key")
+}
+
+func (s *EncryptionSuite) TestDecryptTampered() {
+	for name, tamper := range map[string]func(dir string){
+		"modified": func(dir string) {
+			path := filepath.Join(dir, "outputs", "nested", "large.db")
+			data, err := os.ReadFile(path)
+			s.Require().NoError(err)
+			data[len(data)/2] ^= 1
+			s.Require().NoError(os.WriteFile(path, data, 0644))
+		},
+		"truncated at a chunk boundary": func(dir string) {
+			path := filepath.Join(dir, "outputs", "nested", "large.db")
+			data, err := os.ReadFile(path)
+			s.Require().NoError(err)
+			size := len(fileMagic) + nonceSize + 2*(chunkSize+16)
+			s.Require().NoError(os.WriteFile(path, data[:size], 0644))
+		},
+		"swapped": func(dir string) {
+			s.Require().NoError(os.Rename(filepath.Join(dir, "stdout"), filepath.Join(dir, "stderr")))
+		},
+	} {
+		s.Run(name, func() {
+			encryptedDir := s.encrypt()
+			tamper(encryptedDir)
+			_, err := DecryptDirectory(encryptedDir, s.key)
+			s.Error(err)
+		})
+	}
+}
+
+func (s *EncryptionSuite) TestParsePublicKey() {
+	pkcs1 := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PublicKey(&s.key.PublicKey))
+	pkix, err := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+	s.Require().NoError(err)
+
+	for name, encoded := range map[string]string{
+		"client public key": pkcs1,
+		"pem pkcs1":         string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&s.key.PublicKey)})),
+		"pem pkix":          string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix})),
+	} {
+		s.Run(name, func() {
+			key, err := ParsePublicKey(encoded)
+			s.Require().NoError(err)
+			s.True(key.Equal(&s.key.PublicKey))
+		})
+	}
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	s.Require().NoError(err)
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PublicKey(&small.PublicKey)))
+	s.Error(err)
+	_, err = ParsePublicKey("not a key")
+	s.Error(err)
+}
+
+func (s *EncryptionSuite) TestPublisher() {
+	var published string
+	p := Wrap(noop.NewNoopPublisherWithConfig(noop.PublisherConfig{
+		ExternalHooks: noop.PublisherExternalHooks{
+			PublishResult: func(_ context.Context, _ *models.Execution, resultPath string) (models.SpecConfig, error) {
+				published = resultPath
+				_, err := os.Stat(filepath.Join(resultPath, EnvelopeName))
+				return models.SpecConfig{}, err
+			},
+		},
+	}))
+	job := mock.Job()
+	job.Task().Publisher = &models.SpecConfig{Type: models.PublisherNoop, Params: map[string]interface{}{}}
+	execution := &models.Execution{Job: job}
+
+	// results are published as they are without a recipient
+	s.Require().NoError(p.ValidateJob(context.Background(), *job))
+	_, err := p.PublishResult(context.Background(), execution, s.resultDir)
+	s.Error(err)
+	s.Equal(s.resultDir, published)
+
+	// and are encrypted in a directory that is removed once published with a recipient
+	job.Task().Publisher.Params["recipientpublickey"] = base64.StdEncoding.EncodeToString(
+		x509.MarshalPKCS1PublicKey(&s.key.PublicKey))
+	s.Require().NoError(p.ValidateJob(context.Background(), *job))
+	_, err = p.PublishResult(context.Background(), execution, s.resultDir)
+	s.Require().NoError(err)
+	s.NotEqual(s.resultDir, published)
+	s.NoDirExists(published)
+
+	job.Task().Publisher.Params[RecipientKeyParam] = "invalid"
+	delete(job.Task().Publisher.Params, "recipientpublickey")
+	s.Error(p.ValidateJob(context.Background(), *job))
+}
+
+func (s *EncryptionSuite) TestEncryptReservedFile() {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.resultDir, EnvelopeName), []byte("{}"), 0644))
+	s.ErrorContains(EncryptDirectory(s.resultDir, s.T().TempDir(), &s.key.PublicKey), EnvelopeName)
+
+	// the name is only reserved at the root of the results
+	s.Require().NoError(os.Rename(filepath.Join(s.resultDir, EnvelopeName), filepath.Join(s.resultDir, "outputs", EnvelopeName)))
+	s.NoError(EncryptDirectory(s.resultDir, s.T().TempDir(), &s.key.PublicKey))
+}
+
+func (s *EncryptionSuite) TestValidateJob() {
+	job := mock.Job()
+	job.Task().Publisher = &models.SpecConfig{Type: models.PublisherS3, Params: map[string]interface{}{}}
+	job.Task().Checkpoint = &models.CheckpointConfig{Path: "/checkpoint"}
+	s.NoError(ValidateJob(*job))
+	s.False(IsEncrypted(job.Task().Publisher))
+
+	job.Task().Publisher.Params[RecipientKeyParam] = base64.StdEncoding.EncodeToString(
+		x509.MarshalPKCS1PublicKey(&s.key.PublicKey))
+	s.True(IsEncrypted(job.Task().Publisher))
+	s.ErrorContains(ValidateJob(*job), "checkpointed")
+
+	job.Task().Checkpoint = nil
+	s.NoError(ValidateJob(*job))
+
+	job.Task().Publisher.Params[RecipientKeyParam] = "invalid"
+	s.True(IsEncrypted(job.Task().Publisher))
+	s.Error(ValidateJob(*job))
+}
@@ -0,0 +1,164 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// RecipientKeyParam is the publisher param with the public key that results are encrypted for. It is
+	// either a base64 encoded PKCS #1 RSA public key, like the client public key, or a PEM encoded RSA public key.
+	RecipientKeyParam = "RecipientPublicKey"
+
+	// EnvelopeName is the name of the file, at the root of encrypted results, with the key that decrypts them
+	EnvelopeName = ".bacalhau-encryption.json"
+
+	envelopeVersion = 1
+	algorithm       = "RSA-OAEP-SHA256+AES-256-GCM"
+	dataKeySize     = 32
+	minRSAKeyBits   = 2048
+)
+
+// keyLabel binds the wrapped data keys to their use
+var keyLabel = []byte("bacalhau-result-key")
+
+// Envelope holds the data key that encrypts a result, encrypted for its recipient
+type Envelope struct {
+	Version   int    `json:"Version"`
+	Algorithm string `json:"Algorithm"`
+	// RecipientID identifies the key the data key is encrypted for, in the format of client IDs
+	RecipientID  string `json:"RecipientID"`
+	EncryptedKey []byte `json:"EncryptedKey"`
+}
+
+// RecipientKey returns the recipient public key in a publisher spec, or nil if the results are not to be encrypted
+func RecipientKey(spec *models.SpecConfig) (*rsa.PublicKey, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	for key, value := range spec.Params {
+		if !strings.EqualFold(key, RecipientKeyParam) {
+			continue
+		}
+		encoded, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid publisher param %s: expected a string", RecipientKeyParam)
+		}
+		if encoded == "" {
+			return nil, nil
+		}
+		return ParsePublicKey(encoded)
+	}
+	return nil, nil
+}
+
+// IsEncrypted returns true if results published with the publisher spec are encrypted for a recipient key.
+// Specs with an invalid key are considered encrypted, as they fail to publish in clear.
+func IsEncrypted(spec *models.SpecConfig) bool {
+	recipient, err := RecipientKey(spec)
+	return recipient != nil || err != nil
+}
+
+// ParsePublicKey parses a base64 encoded PKCS #1 RSA public key, or a PEM encoded PKCS #1 or PKIX RSA public key
+func ParsePublicKey(encoded string) (*rsa.PublicKey, error) {
+	var der []byte
+	if block, _ := pem.Decode([]byte(encoded)); block != nil {
+		der = block.Bytes
+	} else {
+		var err error
+		if der, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded)); err != nil {
+			return nil, fmt.Errorf("invalid recipient public key: %w", err)
+		}
+	}
+
+	key, err := x509.ParsePKCS1PublicKey(der)
+	if err != nil {
+		parsed, pkixErr := x509.ParsePKIXPublicKey(der)
+		if pkixErr != nil {
+			return nil, fmt.Errorf("invalid recipient public key: %w", err)
+		}
+		var ok bool
+		if key, ok = parsed.(*rsa.PublicKey); !ok {
+			return nil, errors.New("invalid recipient public key: only RSA keys are supported")
+		}
+	}
+	if key.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("invalid recipient public key: RSA keys must be at least %d bits", minRSAKeyBits)
+	}
+	return key, nil
+}
+
+// RecipientID returns the ID of a public key, which is the client ID of clients using it
+func RecipientID(key *rsa.PublicKey) string {
+	hash := sha256.Sum256(key.N.Bytes())
+	return fmt.Sprintf("%x", hash)
+}
+
+// newEnvelope generates a data key and returns it along with the envelope that holds it for the recipient
+func newEnvelope(recipient *rsa.PublicKey) ([]byte, Envelope, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, Envelope{}, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, recipient, dataKey, keyLabel)
+	if err != nil {
+		return nil, Envelope{}, fmt.Errorf("failed to encrypt result key: %w", err)
+	}
+	return dataKey, Envelope{
+		Version:      envelopeVersion,
+		Algorithm:    algorithm,
+		RecipientID:  RecipientID(recipient),
+		EncryptedKey: encryptedKey,
+	}, nil
+}
+
+// open returns the data key of the envelope
+func (e Envelope) open(key *rsa.PrivateKey) ([]byte, error) {
+	if e.Version != envelopeVersion || e.Algorithm != algorithm {
+		return nil, fmt.Errorf("unsupported result encryption %s version %d", e.Algorithm, e.Version)
+	}
+	if e.RecipientID != RecipientID(&key.PublicKey) {
+		return nil, fmt.Errorf("result is encrypted for %s and not for the client key %s",
+			e.RecipientID, RecipientID(&key.PublicKey))
+	}
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, e.EncryptedKey, keyLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt result key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// readEnvelope reads the envelope at the root of a result directory, or returns false if the result is not encrypted
+func readEnvelope(dir string) (Envelope, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, EnvelopeName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Envelope{}, false, nil
+	} else if err != nil {
+		return Envelope{}, false, err
+	}
+	var envelope Envelope
+	if err = json.Unmarshal(data, &envelope); err != nil {
+		return Envelope{}, false, fmt.Errorf("invalid result encryption envelope: %w", err)
+	}
+	return envelope, true, nil
+}
+
+func writeEnvelope(dir string, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, EnvelopeName), data, models.DownloadFilePerm)
+}
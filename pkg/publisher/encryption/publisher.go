@@ -0,0 +1,92 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+)
+
+// encryptingPublisher encrypts results for the recipient public key of the job's publisher spec, when it
+// has one, before publishing them with its delegate, so that results never leave the compute node in clear.
+type encryptingPublisher struct {
+	delegate publisher.Publisher
+}
+
+// Wrap returns a publisher that encrypts the results published by the delegate publisher for the recipient
+// public key of the job's publisher spec.
+func Wrap(delegate publisher.Publisher) publisher.Publisher {
+	return &encryptingPublisher{
+		delegate: delegate,
+	}
+}
+
+func (p *encryptingPublisher) IsInstalled(ctx context.Context) (bool, error) {
+	return p.delegate.IsInstalled(ctx)
+}
+
+func (p *encryptingPublisher) ValidateJob(ctx context.Context, j models.Job) error {
+	if err := ValidateJob(j); err != nil {
+		return err
+	}
+	return p.delegate.ValidateJob(ctx, j)
+}
+
+// ValidateJob returns an error if the recipient public key of the job's publisher spec is invalid, or if results
+// are encrypted for a task that is checkpointed. Checkpoints are stored with the task's publisher, and restored by
+// compute nodes that can't decrypt them.
+func ValidateJob(j models.Job) error {
+	task := j.Task()
+	if task == nil {
+		return nil
+	}
+	recipient, err := RecipientKey(task.Publisher)
+	if err != nil {
+		return err
+	}
+	if recipient != nil && task.Checkpoint != nil {
+		return fmt.Errorf("task %s can't be checkpointed when its results are encrypted with %s", task.Name, RecipientKeyParam)
+	}
+	return nil
+}
+
+func (p *encryptingPublisher) PublishResult(
+	ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+	recipient, err := RecipientKey(execution.Job.Task().Publisher)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	if recipient == nil {
+		return p.delegate.PublishResult(ctx, execution, resultPath)
+	}
+
+	encryptedPath, err := os.MkdirTemp(filepath.Dir(resultPath), "encrypted-*")
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	defer func() {
+		if err := os.RemoveAll(encryptedPath); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to remove encrypted results %s", encryptedPath)
+		}
+	}()
+
+	if err = EncryptDirectory(resultPath, encryptedPath, recipient); err != nil {
+		return models.SpecConfig{}, fmt.Errorf("failed to encrypt results: %w", err)
+	}
+	log.Ctx(ctx).Debug().Str("RecipientID", RecipientID(recipient)).Msg("encrypted results before publishing them")
+	return p.delegate.PublishResult(ctx, execution, encryptedPath)
+}
+
+// RemoveResult removes a result published by the delegate, whether it was encrypted or not.
+func (p *encryptingPublisher) RemoveResult(ctx context.Context, result models.SpecConfig) error {
+	return publisher.RemoveResult(ctx, p.delegate, result)
+}
+
+// compile-time interface check
+var _ publisher.Publisher = (*encryptingPublisher)(nil)
+var _ publisher.ResultRemover = (*encryptingPublisher)(nil)
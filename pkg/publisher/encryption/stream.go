@@ -0,0 +1,144 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Files are encrypted in chunks, so that they are not loaded in memory, each sealed with AES-256-GCM with a
+// nonce derived from a random per file nonce and the index of the chunk. The path of the file in the result
+// and whether the chunk is the last one are authenticated with each chunk, so that files can't be swapped
+// or truncated without failing decryption.
+const (
+	chunkSize = 64 * 1024
+	nonceSize = 12
+)
+
+var fileMagic = []byte("BACENC1\n")
+
+// chunkCipher seals and opens the chunks of a file
+type chunkCipher struct {
+	aead  cipher.AEAD
+	nonce [nonceSize]byte
+	path  string
+	index uint64
+}
+
+func newChunkCipher(dataKey []byte, nonce [nonceSize]byte, path string) (*chunkCipher, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkCipher{aead: aead, nonce: nonce, path: path}, nil
+}
+
+// next returns the nonce and additional data of the next chunk
+func (c *chunkCipher) next(last bool) ([]byte, []byte) {
+	nonce := c.nonce
+	counter := binary.BigEndian.Uint64(nonce[nonceSize-8:]) ^ c.index
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], counter)
+	c.index++
+
+	additionalData := []byte(c.path)
+	if last {
+		additionalData = append(additionalData, 1)
+	} else {
+		additionalData = append(additionalData, 0)
+	}
+	return nonce[:], additionalData
+}
+
+// encryptStream encrypts plaintext read from in to out, authenticating it as the file at path in the result
+func encryptStream(dataKey []byte, path string, in io.Reader, out io.Writer) error {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	c, err := newChunkCipher(dataKey, nonce, path)
+	if err != nil {
+		return err
+	}
+	if _, err = out.Write(fileMagic); err != nil {
+		return err
+	}
+	if _, err = out.Write(nonce[:]); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReaderSize(in, chunkSize+1)
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		// the chunk is the last one if nothing follows it
+		_, peekErr := reader.Peek(1)
+		last := errors.Is(peekErr, io.EOF)
+		if peekErr != nil && !last {
+			return peekErr
+		}
+
+		chunkNonce, additionalData := c.next(last)
+		if _, err = out.Write(c.aead.Seal(nil, chunkNonce, buf[:n], additionalData)); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// decryptStream decrypts the ciphertext of the file at path in the result read from in to out
+func decryptStream(dataKey []byte, path string, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReaderSize(in, chunkSize+aes.BlockSize+1)
+	header := make([]byte, len(fileMagic)+nonceSize)
+	if _, err := io.ReadFull(reader, header); err != nil || !bytes.Equal(header[:len(fileMagic)], fileMagic) {
+		return fmt.Errorf("%s is not an encrypted result file", path)
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], header[len(fileMagic):])
+	c, err := newChunkCipher(dataKey, nonce, path)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize+c.aead.Overhead())
+	for {
+		n, err := io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("%s is truncated", path)
+			}
+			return err
+		}
+		_, peekErr := reader.Peek(1)
+		last := errors.Is(peekErr, io.EOF)
+		if peekErr != nil && !last {
+			return peekErr
+		}
+
+		chunkNonce, additionalData := c.next(last)
+		plaintext, err := c.aead.Open(buf[:0], chunkNonce, buf[:n], additionalData)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		if _, err = out.Write(plaintext); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/network"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -91,7 +92,23 @@ func (p *Publisher) PublishResult(
 	}, nil
 }
 
+// RemoveResult removes a result published by the publisher from its base directory.
+func (p *Publisher) RemoveResult(ctx context.Context, result models.SpecConfig) error {
+	downloadURL, _ := result.Params["URL"].(string)
+	filename := strings.TrimPrefix(downloadURL, p.urlPrefix+"/")
+	if filename == downloadURL || filename != path.Base(filename) || !strings.HasSuffix(filename, ".tgz") {
+		return fmt.Errorf("local publisher did not publish result %s", downloadURL)
+	}
+
+	err := os.Remove(path.Join(p.baseDirectory, filename))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "local publisher failed to remove result")
+	}
+	return nil
+}
+
 var _ publisher.Publisher = (*Publisher)(nil)
+var _ publisher.ResultRemover = (*Publisher)(nil)
 
 func ResolveAddress(ctx context.Context, address string) string {
 	addressType, ok := network.AddressTypeFromString(address)
@@ -66,3 +66,25 @@ func (s *PublisherTestSuite) TestPublishFolder() {
 	expected := fmt.Sprintf("http://%s:%d/eid.tgz", defaultHost, defaultPort)
 	s.Require().Equal(expected, cfg.Params["URL"])
 }
+
+func (s *PublisherTestSuite) TestRemoveResult() {
+	source := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(source, "file.txt"), []byte("test"), 0644))
+
+	cfg, err := s.pub.PublishResult(s.ctx, &models.Execution{ID: "eid", JobID: "jid"}, source)
+	s.Require().NoError(err)
+	s.Require().FileExists(filepath.Join(s.baseDir, "eid.tgz"))
+
+	s.Require().NoError(s.pub.RemoveResult(s.ctx, cfg))
+	s.Require().NoFileExists(filepath.Join(s.baseDir, "eid.tgz"))
+	s.Require().NoError(s.pub.RemoveResult(s.ctx, cfg), "removing a removed result is not an error")
+
+	s.Require().Error(s.pub.RemoveResult(s.ctx, models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": "http://example.com/eid.tgz"},
+	}))
+	s.Require().Error(s.pub.RemoveResult(s.ctx, models.SpecConfig{
+		Type:   models.StorageSourceURL,
+		Params: map[string]interface{}{"URL": fmt.Sprintf("http://%s:%d/../eid.tgz", defaultHost, defaultPort)},
+	}))
+}
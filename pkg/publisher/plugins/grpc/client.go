@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc/proto"
+)
+
+var _ publisher.Publisher = (*GRPCClient)(nil)
+
+// GRPCClient is the publisher used by the node, which calls the publisher served by a plugin
+type GRPCClient struct {
+	client proto.PublisherClient
+}
+
+func (c *GRPCClient) IsInstalled(ctx context.Context) (bool, error) {
+	resp, err := c.client.IsInstalled(ctx, &proto.IsInstalledRequest{})
+	if err != nil {
+		return false, err
+	}
+	return resp.Installed, nil
+}
+
+func (c *GRPCClient) ValidateJob(ctx context.Context, j models.Job) error {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.ValidateJob(ctx, &proto.ValidateJobRequest{Job: b})
+	return err
+}
+
+func (c *GRPCClient) PublishResult(ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+	b, err := json.Marshal(execution)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	resp, err := c.client.PublishResult(ctx, &proto.PublishResultRequest{
+		Execution:  b,
+		ResultPath: resultPath,
+	})
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	var out models.SpecConfig
+	if err = json.Unmarshal(resp.SpecConfig, &out); err != nil {
+		return models.SpecConfig{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+//go:build unit || !integration
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type PublisherGRPCTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	client publisher.Publisher
+}
+
+func TestPublisherGRPCTestSuite(t *testing.T) {
+	suite.Run(t, new(PublisherGRPCTestSuite))
+}
+
+func (s *PublisherGRPCTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	impl := noop.NewNoopPublisherWithConfig(noop.PublisherConfig{
+		ExternalHooks: noop.PublisherExternalHooks{
+			PublishResult: func(_ context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+				if resultPath == "" {
+					return models.SpecConfig{}, errors.New("no results")
+				}
+				return models.SpecConfig{
+					Type:   "custom",
+					Params: map[string]interface{}{"Execution": execution.ID, "Path": resultPath},
+				}, nil
+			},
+		},
+	})
+
+	client, server := plugin.TestPluginGRPCConn(s.T(), false, map[string]plugin.Plugin{
+		PluggablePublisherPluginName: &PublisherGRPCPlugin{Impl: impl},
+	})
+	s.T().Cleanup(func() {
+		_ = client.Close()
+		server.Stop()
+	})
+	raw, err := client.Dispense(PluggablePublisherPluginName)
+	s.Require().NoError(err)
+	s.client = raw.(publisher.Publisher)
+}
+
+func (s *PublisherGRPCTestSuite) TestIsInstalled() {
+	installed, err := s.client.IsInstalled(s.ctx)
+	s.Require().NoError(err)
+	s.True(installed)
+}
+
+func (s *PublisherGRPCTestSuite) TestValidateJob() {
+	s.NoError(s.client.ValidateJob(s.ctx, *mock.Job()))
+}
+
+func (s *PublisherGRPCTestSuite) TestPublishResult() {
+	execution := mock.Execution()
+	result, err := s.client.PublishResult(s.ctx, execution, "/results")
+	s.Require().NoError(err)
+	s.Equal("custom", result.Type)
+	s.Equal(execution.ID, result.Params["Execution"])
+	s.Equal("/results", result.Params["Path"])
+
+	_, err = s.client.PublishResult(s.ctx, execution, "")
+	s.ErrorContains(err, "no results")
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc/proto"
+)
+
+// PluggablePublisherPluginName is the name under which publisher plugins serve their publisher
+const PluggablePublisherPluginName = "PLUGGABLE_PUBLISHER"
+
+// PluginBinaryPrefix is the prefix of the names of publisher plugin binaries in the plugin path.
+// The rest of the binary name is the publisher type the plugin provides.
+const PluginBinaryPrefix = "bacalhau-publisher-"
+
+// HandshakeConfig is the handshake between the node and publisher plugins.
+// It is a UX feature to avoid running binaries that are not publisher plugins, not a security feature.
+var HandshakeConfig = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "PUBLISHER_PLUGIN",
+	MagicCookieValue: "bacalhau_publisher",
+}
+
+type PublisherGRPCPlugin struct {
+	plugin.Plugin
+	Impl publisher.Publisher
+}
+
+func (p *PublisherGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterPublisherServer(s, &GRPCServer{Impl: p.Impl})
+	return nil
+}
+
+func (p *PublisherGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &GRPCClient{client: proto.NewPublisherClient(c)}, nil
+}
@@ -0,0 +1,468 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: publisher.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type IsInstalledRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *IsInstalledRequest) Reset() {
+	*x = IsInstalledRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsInstalledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsInstalledRequest) ProtoMessage() {}
+
+func (x *IsInstalledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsInstalledRequest.ProtoReflect.Descriptor instead.
+func (*IsInstalledRequest) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{0}
+}
+
+type IsInstalledResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Installed bool `protobuf:"varint,1,opt,name=Installed,proto3" json:"Installed,omitempty"`
+}
+
+func (x *IsInstalledResponse) Reset() {
+	*x = IsInstalledResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsInstalledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsInstalledResponse) ProtoMessage() {}
+
+func (x *IsInstalledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsInstalledResponse.ProtoReflect.Descriptor instead.
+func (*IsInstalledResponse) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IsInstalledResponse) GetInstalled() bool {
+	if x != nil {
+		return x.Installed
+	}
+	return false
+}
+
+type ValidateJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Job []byte `protobuf:"bytes,1,opt,name=Job,proto3" json:"Job,omitempty"`
+}
+
+func (x *ValidateJobRequest) Reset() {
+	*x = ValidateJobRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateJobRequest) ProtoMessage() {}
+
+func (x *ValidateJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateJobRequest.ProtoReflect.Descriptor instead.
+func (*ValidateJobRequest) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateJobRequest) GetJob() []byte {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ValidateJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ValidateJobResponse) Reset() {
+	*x = ValidateJobResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateJobResponse) ProtoMessage() {}
+
+func (x *ValidateJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateJobResponse.ProtoReflect.Descriptor instead.
+func (*ValidateJobResponse) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{3}
+}
+
+type PublishResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Execution  []byte `protobuf:"bytes,1,opt,name=Execution,proto3" json:"Execution,omitempty"`
+	ResultPath string `protobuf:"bytes,2,opt,name=ResultPath,proto3" json:"ResultPath,omitempty"`
+}
+
+func (x *PublishResultRequest) Reset() {
+	*x = PublishResultRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResultRequest) ProtoMessage() {}
+
+func (x *PublishResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResultRequest.ProtoReflect.Descriptor instead.
+func (*PublishResultRequest) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PublishResultRequest) GetExecution() []byte {
+	if x != nil {
+		return x.Execution
+	}
+	return nil
+}
+
+func (x *PublishResultRequest) GetResultPath() string {
+	if x != nil {
+		return x.ResultPath
+	}
+	return ""
+}
+
+type PublishResultResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpecConfig []byte `protobuf:"bytes,1,opt,name=SpecConfig,proto3" json:"SpecConfig,omitempty"`
+}
+
+func (x *PublishResultResponse) Reset() {
+	*x = PublishResultResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_publisher_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishResultResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResultResponse) ProtoMessage() {}
+
+func (x *PublishResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_publisher_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResultResponse.ProtoReflect.Descriptor instead.
+func (*PublishResultResponse) Descriptor() ([]byte, []int) {
+	return file_publisher_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PublishResultResponse) GetSpecConfig() []byte {
+	if x != nil {
+		return x.SpecConfig
+	}
+	return nil
+}
+
+var File_publisher_proto protoreflect.FileDescriptor
+
+var file_publisher_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x22, 0x14, 0x0a, 0x12,
+	0x49, 0x73, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x33, 0x0a, 0x13, 0x49, 0x73, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x73,
+	0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x49, 0x6e,
+	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x22, 0x26, 0x0a, 0x12, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x4a, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x4a, 0x6f, 0x62, 0x22,
+	0x15, 0x0a, 0x13, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x54, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x74, 0x68, 0x22, 0x37, 0x0a, 0x15,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x70, 0x65, 0x63, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x70, 0x65, 0x63, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x32, 0xfb, 0x01, 0x0a, 0x09, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73,
+	0x68, 0x65, 0x72, 0x12, 0x4c, 0x0a, 0x0b, 0x49, 0x73, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
+	0x65, 0x64, 0x12, 0x1d, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x49,
+	0x73, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x49, 0x73,
+	0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4c, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62,
+	0x12, 0x1d, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1e, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x52, 0x0a, 0x0d, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x1f, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x20, 0x2e, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x2e, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x73, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_publisher_proto_rawDescOnce sync.Once
+	file_publisher_proto_rawDescData = file_publisher_proto_rawDesc
+)
+
+func file_publisher_proto_rawDescGZIP() []byte {
+	file_publisher_proto_rawDescOnce.Do(func() {
+		file_publisher_proto_rawDescData = protoimpl.X.CompressGZIP(file_publisher_proto_rawDescData)
+	})
+	return file_publisher_proto_rawDescData
+}
+
+var file_publisher_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_publisher_proto_goTypes = []interface{}{
+	(*IsInstalledRequest)(nil),    // 0: publisher.IsInstalledRequest
+	(*IsInstalledResponse)(nil),   // 1: publisher.IsInstalledResponse
+	(*ValidateJobRequest)(nil),    // 2: publisher.ValidateJobRequest
+	(*ValidateJobResponse)(nil),   // 3: publisher.ValidateJobResponse
+	(*PublishResultRequest)(nil),  // 4: publisher.PublishResultRequest
+	(*PublishResultResponse)(nil), // 5: publisher.PublishResultResponse
+}
+var file_publisher_proto_depIdxs = []int32{
+	0, // 0: publisher.Publisher.IsInstalled:input_type -> publisher.IsInstalledRequest
+	2, // 1: publisher.Publisher.ValidateJob:input_type -> publisher.ValidateJobRequest
+	4, // 2: publisher.Publisher.PublishResult:input_type -> publisher.PublishResultRequest
+	1, // 3: publisher.Publisher.IsInstalled:output_type -> publisher.IsInstalledResponse
+	3, // 4: publisher.Publisher.ValidateJob:output_type -> publisher.ValidateJobResponse
+	5, // 5: publisher.Publisher.PublishResult:output_type -> publisher.PublishResultResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_publisher_proto_init() }
+func file_publisher_proto_init() {
+	if File_publisher_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_publisher_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IsInstalledRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IsInstalledResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateJobRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateJobResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishResultRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_publisher_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishResultResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_publisher_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_publisher_proto_goTypes,
+		DependencyIndexes: file_publisher_proto_depIdxs,
+		MessageInfos:      file_publisher_proto_msgTypes,
+	}.Build()
+	File_publisher_proto = out.File
+	file_publisher_proto_rawDesc = nil
+	file_publisher_proto_goTypes = nil
+	file_publisher_proto_depIdxs = nil
+}
@@ -0,0 +1,183 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: publisher.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Publisher_IsInstalled_FullMethodName   = "/publisher.Publisher/IsInstalled"
+	Publisher_ValidateJob_FullMethodName   = "/publisher.Publisher/ValidateJob"
+	Publisher_PublishResult_FullMethodName = "/publisher.Publisher/PublishResult"
+)
+
+// PublisherClient is the client API for Publisher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PublisherClient interface {
+	IsInstalled(ctx context.Context, in *IsInstalledRequest, opts ...grpc.CallOption) (*IsInstalledResponse, error)
+	ValidateJob(ctx context.Context, in *ValidateJobRequest, opts ...grpc.CallOption) (*ValidateJobResponse, error)
+	PublishResult(ctx context.Context, in *PublishResultRequest, opts ...grpc.CallOption) (*PublishResultResponse, error)
+}
+
+type publisherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPublisherClient(cc grpc.ClientConnInterface) PublisherClient {
+	return &publisherClient{cc}
+}
+
+func (c *publisherClient) IsInstalled(ctx context.Context, in *IsInstalledRequest, opts ...grpc.CallOption) (*IsInstalledResponse, error) {
+	out := new(IsInstalledResponse)
+	err := c.cc.Invoke(ctx, Publisher_IsInstalled_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherClient) ValidateJob(ctx context.Context, in *ValidateJobRequest, opts ...grpc.CallOption) (*ValidateJobResponse, error) {
+	out := new(ValidateJobResponse)
+	err := c.cc.Invoke(ctx, Publisher_ValidateJob_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publisherClient) PublishResult(ctx context.Context, in *PublishResultRequest, opts ...grpc.CallOption) (*PublishResultResponse, error) {
+	out := new(PublishResultResponse)
+	err := c.cc.Invoke(ctx, Publisher_PublishResult_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PublisherServer is the server API for Publisher service.
+// All implementations must embed UnimplementedPublisherServer
+// for forward compatibility
+type PublisherServer interface {
+	IsInstalled(context.Context, *IsInstalledRequest) (*IsInstalledResponse, error)
+	ValidateJob(context.Context, *ValidateJobRequest) (*ValidateJobResponse, error)
+	PublishResult(context.Context, *PublishResultRequest) (*PublishResultResponse, error)
+	mustEmbedUnimplementedPublisherServer()
+}
+
+// UnimplementedPublisherServer must be embedded to have forward compatible implementations.
+type UnimplementedPublisherServer struct {
+}
+
+func (UnimplementedPublisherServer) IsInstalled(context.Context, *IsInstalledRequest) (*IsInstalledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsInstalled not implemented")
+}
+func (UnimplementedPublisherServer) ValidateJob(context.Context, *ValidateJobRequest) (*ValidateJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateJob not implemented")
+}
+func (UnimplementedPublisherServer) PublishResult(context.Context, *PublishResultRequest) (*PublishResultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishResult not implemented")
+}
+func (UnimplementedPublisherServer) mustEmbedUnimplementedPublisherServer() {}
+
+// UnsafePublisherServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PublisherServer will
+// result in compilation errors.
+type UnsafePublisherServer interface {
+	mustEmbedUnimplementedPublisherServer()
+}
+
+func RegisterPublisherServer(s grpc.ServiceRegistrar, srv PublisherServer) {
+	s.RegisterService(&Publisher_ServiceDesc, srv)
+}
+
+func _Publisher_IsInstalled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsInstalledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServer).IsInstalled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Publisher_IsInstalled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServer).IsInstalled(ctx, req.(*IsInstalledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Publisher_ValidateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServer).ValidateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Publisher_ValidateJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServer).ValidateJob(ctx, req.(*ValidateJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Publisher_PublishResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublisherServer).PublishResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Publisher_PublishResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublisherServer).PublishResult(ctx, req.(*PublishResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Publisher_ServiceDesc is the grpc.ServiceDesc for Publisher service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Publisher_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "publisher.Publisher",
+	HandlerType: (*PublisherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IsInstalled",
+			Handler:    _Publisher_IsInstalled_Handler,
+		},
+		{
+			MethodName: "ValidateJob",
+			Handler:    _Publisher_ValidateJob_Handler,
+		},
+		{
+			MethodName: "PublishResult",
+			Handler:    _Publisher_PublishResult_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "publisher.proto",
+}
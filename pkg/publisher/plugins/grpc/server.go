@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc/proto"
+)
+
+// GRPCServer serves the publisher of a plugin to the node
+type GRPCServer struct {
+	Impl publisher.Publisher
+
+	proto.UnimplementedPublisherServer
+}
+
+func (s *GRPCServer) IsInstalled(ctx context.Context, _ *proto.IsInstalledRequest) (*proto.IsInstalledResponse, error) {
+	installed, err := s.Impl.IsInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.IsInstalledResponse{Installed: installed}, nil
+}
+
+func (s *GRPCServer) ValidateJob(ctx context.Context, request *proto.ValidateJobRequest) (*proto.ValidateJobResponse, error) {
+	var job models.Job
+	if err := json.Unmarshal(request.Job, &job); err != nil {
+		return nil, err
+	}
+	if err := s.Impl.ValidateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return &proto.ValidateJobResponse{}, nil
+}
+
+func (s *GRPCServer) PublishResult(ctx context.Context, request *proto.PublishResultRequest) (*proto.PublishResultResponse, error) {
+	execution := new(models.Execution)
+	if err := json.Unmarshal(request.Execution, execution); err != nil {
+		return nil, err
+	}
+	result, err := s.Impl.PublishResult(ctx, execution, request.ResultPath)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PublishResultResponse{SpecConfig: b}, nil
+}
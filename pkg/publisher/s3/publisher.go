@@ -2,8 +2,11 @@ package s3
 
 import (
 	"context"
-	"io"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -12,6 +15,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
 )
 
 type PublisherParams struct {
@@ -21,6 +25,7 @@ type PublisherParams struct {
 
 // Compile-time check that publisher implements the correct interface:
 var _ publisher.Publisher = (*Publisher)(nil)
+var _ publisher.ResultRemover = (*Publisher)(nil)
 
 type Publisher struct {
 	localDir       string
@@ -56,6 +61,19 @@ func (publisher *Publisher) PublishResult(
 	}
 
 	client := publisher.clientProvider.GetClient(spec.Endpoint, spec.Region)
+	uploader := s3helper.NewMultipartUploader(s3helper.MultipartUploaderParams{
+		Client:      client.S3,
+		PartSize:    s3helper.DefaultPartSize,
+		Concurrency: s3helper.DefaultUploadConcurrency,
+		MaxRetries:  s3helper.DefaultUploadRetries,
+		// Only use SHA256 checksums if the endpoint is AWS, as it is
+		// not supported by other S3-compatible providers, such as GCP buckets
+		Checksum: client.IsAWSEndpoint(),
+	})
+
+	if !spec.Archive {
+		return publisher.publishFiles(ctx, client, uploader, spec, ParsePublishedKey(spec.Key, execution, false), resultPath)
+	}
 	key := ParsePublishedKey(spec.Key, execution, true)
 
 	// Create a new GZIP writer that writes to the file.
@@ -71,40 +89,134 @@ func (publisher *Publisher) PublishResult(
 		return models.SpecConfig{}, err
 	}
 
-	// reset the archived file to read and upload it
-	_, err = targetFile.Seek(0, io.SeekStart)
+	// Upload the GZIP archive to S3.
+	res, err := uploader.UploadFile(ctx, spec.Bucket, key, targetFile.Name())
 	if err != nil {
 		return models.SpecConfig{}, err
 	}
+	log.Debug().Msgf("Uploaded s3://%s/%s", spec.Bucket, res.Key)
 
-	putObjectInput := &s3.PutObjectInput{
-		Bucket: aws.String(spec.Bucket),
-		Key:    aws.String(key),
-		Body:   targetFile,
+	return models.SpecConfig{
+		Type: models.StorageSourceS3,
+		Params: s3helper.SourceSpec{
+			Bucket:         spec.Bucket,
+			Key:            key,
+			Endpoint:       spec.Endpoint,
+			Region:         spec.Region,
+			ChecksumSHA256: res.ChecksumSHA256,
+			VersionID:      res.VersionID,
+		}.ToMap(),
+	}, nil
+}
+
+// publishFiles uploads each file of the result as an object under the prefix, followed by a manifest
+// that lists them.
+func (publisher *Publisher) publishFiles(
+	ctx context.Context,
+	client *s3helper.ClientWrapper,
+	uploader *s3helper.MultipartUploader,
+	spec s3helper.PublisherSpec,
+	prefix string,
+	resultPath string,
+) (models.SpecConfig, error) {
+	files, err := listFiles(resultPath)
+	if err != nil {
+		return models.SpecConfig{}, err
 	}
 
-	// Only use SHA256 checksums if the endpoint is AWS, as it is
-	// not supported by other S3-compatible providers, such as GCP buckets
-	if client.IsAWSEndpoint() {
-		putObjectInput.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	var mu sync.Mutex
+	manifest := s3helper.Manifest{Files: make([]s3helper.ManifestFile, 0, len(files))}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(uploader.Concurrency())
+	for _, file := range files {
+		file := file
+		group.Go(func() error {
+			res, err := uploader.UploadFile(groupCtx, spec.Bucket, prefix+file, filepath.Join(resultPath, filepath.FromSlash(file)))
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			manifest.Files = append(manifest.Files, s3helper.ManifestFile{
+				Path:           file,
+				Size:           res.Size,
+				ETag:           res.ETag,
+				VersionID:      res.VersionID,
+				ChecksumSHA256: res.ChecksumSHA256,
+			})
+			return nil
+		})
 	}
+	if err = group.Wait(); err != nil {
+		return models.SpecConfig{}, err
+	}
+	sortManifest(&manifest)
 
-	// Upload the GZIP archive to S3.
-	res, err := client.Uploader.Upload(ctx, putObjectInput)
-	if err != nil {
+	// the manifest is written last, so that a result with a manifest is complete
+	manifestKey := s3helper.ManifestKey(prefix)
+	if _, err = s3helper.WriteManifest(ctx, client.S3, spec.Bucket, manifestKey, manifest); err != nil {
 		return models.SpecConfig{}, err
 	}
-	log.Debug().Msgf("Uploaded s3://%s/%s", spec.Bucket, aws.ToString(res.Key))
+	log.Debug().Msgf("Uploaded %d files under s3://%s/%s", len(manifest.Files), spec.Bucket, prefix)
 
 	return models.SpecConfig{
 		Type: models.StorageSourceS3,
 		Params: s3helper.SourceSpec{
-			Bucket:         spec.Bucket,
-			Key:            key,
-			Endpoint:       spec.Endpoint,
-			Region:         spec.Region,
-			ChecksumSHA256: aws.ToString(res.ChecksumSHA256),
-			VersionID:      aws.ToString(res.VersionID),
+			Bucket:   spec.Bucket,
+			Key:      prefix,
+			Endpoint: spec.Endpoint,
+			Region:   spec.Region,
+			Manifest: manifestKey,
 		}.ToMap(),
 	}, nil
 }
+
+// RemoveResult removes the objects of a result published by the publisher. Results published as
+// individual objects are removed using their manifest, which is removed last.
+func (publisher *Publisher) RemoveResult(ctx context.Context, result models.SpecConfig) error {
+	source, err := s3helper.DecodeSourceSpec(&result)
+	if err != nil {
+		return err
+	}
+	client := publisher.clientProvider.GetClient(source.Endpoint, source.Region)
+	if source.Manifest == "" {
+		return deleteObject(ctx, client, source.Bucket, source.Key, source.VersionID)
+	}
+
+	manifest, err := s3helper.ReadManifest(ctx, client.S3, source.Bucket, source.Manifest)
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil
+		}
+		return err
+	}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(s3helper.DefaultUploadConcurrency)
+	for _, file := range manifest.Files {
+		file := file
+		group.Go(func() error {
+			return deleteObject(groupCtx, client, source.Bucket, file.ObjectKey(source.Key), file.VersionID)
+		})
+	}
+	if err = group.Wait(); err != nil {
+		return err
+	}
+	return deleteObject(ctx, client, source.Bucket, source.Manifest, "")
+}
+
+// deleteObject deletes an object, or the given version of the object if versionID is set.
+func deleteObject(ctx context.Context, client *s3helper.ClientWrapper, bucket, key, versionID string) error {
+	input := &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	if _, err := client.S3.DeleteObject(ctx, input); err != nil {
+		return fmt.Errorf("failed to delete s3://%s/%s: %w", bucket, key, err)
+	}
+	log.Ctx(ctx).Debug().Msgf("Deleted s3://%s/%s", bucket, key)
+	return nil
+}
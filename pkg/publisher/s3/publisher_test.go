@@ -12,6 +12,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/smithy-go"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -186,8 +188,9 @@ func (s *PublisherTestSuite) TestPublish() {
 				s.T().Skip(skipMessage)
 			}
 			params := s3helper.PublisherSpec{
-				Bucket: s.Bucket,
-				Key:    tc.key,
+				Bucket:  s.Bucket,
+				Key:     tc.key,
+				Archive: true,
 			}
 			if tc.region == "" && tc.endpoint == "" {
 				params.Region = s.Region
@@ -232,3 +235,34 @@ func (s *PublisherTestSuite) TestPublish() {
 		})
 	}
 }
+
+func (s *PublisherTestSuite) TestPublishFiles() {
+	params := s.PreparePublisherSpec(false)
+	resultPath := s.PrepareResultsPath()
+	storageSpec := s.PublishResultSilently(params, resultPath)
+
+	sourceSpec, err := s3helper.DecodeSourceSpec(&storageSpec)
+	s.Require().NoError(err)
+	s.Equal(params.Key, sourceSpec.Key)
+	s.Equal(s3helper.ManifestKey(params.Key), sourceSpec.Manifest)
+
+	// the result is fetched file by file from the manifest
+	s3test.AssertEqualDirectories(s.T(), resultPath, s.GetResult(&storageSpec))
+}
+
+func (s *PublisherTestSuite) TestRemoveResult() {
+	for _, compressed := range []bool{true, false} {
+		storageSpec, _ := s.PrepareAndPublish(compressed)
+		sourceSpec, err := s3helper.DecodeSourceSpec(&storageSpec)
+		s.Require().NoError(err)
+
+		s.Require().NoError(s.Publisher.RemoveResult(s.Ctx, storageSpec))
+		objects, err := s.GetClient().S3.ListObjectsV2(s.Ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(sourceSpec.Bucket),
+			Prefix: aws.String(sourceSpec.Key),
+		})
+		s.Require().NoError(err)
+		s.Empty(objects.Contents, "compressed: %t", compressed)
+		s.Require().NoError(s.Publisher.RemoveResult(s.Ctx, storageSpec), "removing a removed result is not an error")
+	}
+}
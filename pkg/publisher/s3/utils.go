@@ -6,10 +6,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 )
 
 func ParsePublishedKey(key string, execution *models.Execution, archive bool) string {
@@ -71,3 +73,30 @@ func archiveDirectory(sourceDir string, targetFile *os.File) error {
 		return nil
 	})
 }
+
+// listFiles returns the slash separated paths of the regular files under sourceDir, relative to it
+func listFiles(sourceDir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relpath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relpath))
+		return nil
+	})
+	return files, err
+}
+
+// sortManifest sorts the files of a manifest by path, as they are added in the order their uploads complete
+func sortManifest(manifest *s3helper.Manifest) {
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+}
@@ -58,4 +58,12 @@ func (t *tracingPublisher) PublishResult(
 	return t.delegate.PublishResult(ctx, execution, resultPath)
 }
 
+func (t *tracingPublisher) RemoveResult(ctx context.Context, result models.SpecConfig) error {
+	ctx, span := system.NewSpan(ctx, system.GetTracer(), fmt.Sprintf("%s.RemoveResult", t.name))
+	defer span.End()
+
+	return publisher.RemoveResult(ctx, t.delegate, result)
+}
+
 var _ publisher.Publisher = &tracingPublisher{}
+var _ publisher.ResultRemover = &tracingPublisher{}
@@ -2,6 +2,7 @@ package publisher
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -34,3 +35,23 @@ type Publisher interface {
 		resultPath string,
 	) (models.SpecConfig, error)
 }
+
+// ResultRemover is implemented by publishers that can remove the results they published,
+// such as checkpoints that were superseded by a later checkpoint of the same execution.
+type ResultRemover interface {
+	// RemoveResult removes a result that was published by the publisher, given the storage spec
+	// returned by PublishResult. Removing a result that no longer exists is not an error.
+	RemoveResult(ctx context.Context, result models.SpecConfig) error
+}
+
+// ErrRemoveNotSupported is returned when removing a result with a publisher that cannot remove results.
+var ErrRemoveNotSupported = errors.New("publisher does not support removing results")
+
+// RemoveResult removes a result published by p, or returns ErrRemoveNotSupported if p cannot remove results.
+func RemoveResult(ctx context.Context, p Publisher, result models.SpecConfig) error {
+	remover, ok := p.(ResultRemover)
+	if !ok {
+		return ErrRemoveNotSupported
+	}
+	return remover.RemoveResult(ctx, result)
+}
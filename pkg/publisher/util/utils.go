@@ -5,15 +5,20 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	ipfsClient "github.com/bacalhau-project/bacalhau/pkg/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/plugin"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/encryption"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/local"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/plugins/grpc"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/tracing"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
@@ -39,11 +44,12 @@ func NewPublisherProvider(
 
 	localPublisher := local.NewLocalPublisher(ctx, localConfig.Directory, localConfig.Address, localConfig.Port)
 
+	// results are encrypted for the recipient key of the job's publisher spec, if any, before being published
 	return provider.NewMappedProvider(map[string]publisher.Publisher{
 		models.PublisherNoop:  tracing.Wrap(noopPublisher),
-		models.PublisherIPFS:  tracing.Wrap(ipfsPublisher),
-		models.PublisherS3:    tracing.Wrap(s3Publisher),
-		models.PublisherLocal: tracing.Wrap(localPublisher),
+		models.PublisherIPFS:  encryption.Wrap(tracing.Wrap(ipfsPublisher)),
+		models.PublisherS3:    encryption.Wrap(tracing.Wrap(s3Publisher)),
+		models.PublisherLocal: encryption.Wrap(tracing.Wrap(localPublisher)),
 	}), nil
 }
 
@@ -81,3 +87,26 @@ func NewNoopPublishers(
 	noopPublisher := noop.NewNoopPublisherWithConfig(config)
 	return provider.NewNoopProvider[publisher.Publisher](noopPublisher), nil
 }
+
+type PluginPublisherOptions struct {
+	Plugins []plugin.Config
+}
+
+// NewPluginPublisherProvider starts the publisher plugins and returns a provider of their publishers.
+// Plugins that are missing or fail to start are logged and skipped.
+func NewPluginPublisherProvider(
+	ctx context.Context,
+	cm *system.CleanupManager,
+	pluginOptions PluginPublisherOptions,
+) (publisher.PublisherProvider, error) {
+	pm := plugin.NewManager[publisher.Publisher](
+		grpc.PluggablePublisherPluginName, &grpc.PublisherGRPCPlugin{})
+	for _, cfg := range pluginOptions.Plugins {
+		if err := pm.RegisterPlugin(cfg); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("skipping publisher plugin %s", cfg.Name)
+		}
+	}
+	cm.RegisterCallbackWithContext(pm.Stop)
+	pm.StartAvailable(ctx)
+	return pm, nil
+}
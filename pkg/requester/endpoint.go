@@ -293,11 +293,18 @@ func (e *BaseEndpoint) OnRunComplete(ctx context.Context, result compute.RunResu
 		NewValues: models.Execution{
 			PublishedResult: result.PublishResult,
 			RunOutput:       result.RunCommandResult,
+			TaskStates:      result.TaskStates,
 			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
 			DesiredState:    models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution completed"),
 		},
 	}
 
+	if result.RunCommandResult != nil {
+		updateExecutionRequest.NewValues.ResourceUsage = result.RunCommandResult.ResourceUsage
+		updateExecutionRequest.NewValues.FailureReason = result.RunCommandResult.FailureReason
+		updateExecutionRequest.Events = egressEvents(result.RunCommandResult.Egress)
+	}
+
 	if job.IsLongRunning() {
 		log.Ctx(ctx).Error().Msgf(
 			"[OnRunComplete] job %s is long running, but received a RunComplete. Marking the execution as failed instead", result.JobID)
@@ -320,6 +327,19 @@ func (e *BaseEndpoint) OnRunComplete(ctx context.Context, result compute.RunResu
 func (e *BaseEndpoint) OnCancelComplete(ctx context.Context, result compute.CancelResult) {
 	log.Ctx(ctx).Debug().Msgf("Requester node %s received CancelComplete for execution: %s from %s",
 		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Egress.IsEmpty() {
+		return
+	}
+
+	// the execution is usually already canceled, and only its history is updated with its egress
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition:   jobstore.UpdateExecutionCondition{AllowTerminal: true},
+		Event:       orchestrator.ExecEgressEvent(result.Egress),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnCancelComplete] failed to update execution")
+	}
 }
 
 func (e *BaseEndpoint) OnComputeFailure(ctx context.Context, result compute.ComputeError) {
@@ -336,10 +356,13 @@ func (e *BaseEndpoint) OnComputeFailure(ctx context.Context, result compute.Comp
 			},
 		},
 		NewValues: models.Execution{
-			ComputeState: models.NewExecutionState(models.ExecutionStateFailed).WithMessage(result.Error()),
-			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution failed"),
+			ComputeState:  models.NewExecutionState(models.ExecutionStateFailed).WithMessage(result.Error()),
+			DesiredState:  models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution failed"),
+			ResourceUsage: result.ResourceUsage,
+			FailureReason: models.FailureReasonFromEvent(result.Event),
 		},
-		Event: result.Event,
+		Event:  result.Event,
+		Events: egressEvents(result.Egress),
 	})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msgf("[OnComputeFailure] failed to update execution")
@@ -351,6 +374,28 @@ func (e *BaseEndpoint) OnComputeFailure(ctx context.Context, result compute.Comp
 	e.eventEmitter.EmitComputeFailure(ctx, result.ExecutionID, result)
 }
 
+func (e *BaseEndpoint) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received Checkpoint for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+
+	// record the latest checkpoint of the execution, so that a later execution of the job can resume from it.
+	// Executions are checkpointed after they are canceled, so the checkpoint is recorded in terminal states too.
+	checkpoint := result.Checkpoint
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		NewValues: models.Execution{
+			Checkpoint: &checkpoint,
+		},
+		Condition: jobstore.UpdateExecutionCondition{
+			AllowTerminal: true,
+		},
+		Event: orchestrator.ExecCheckpointedEvent(&checkpoint),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnCheckpoint] failed to update execution")
+	}
+}
+
 // enqueueEvaluation enqueues an evaluation to allow the scheduler to either accept the bid, or find a new node
 // TODO: solve edge case where execution is updated, but evaluation is not enqueued
 func (e *BaseEndpoint) enqueueEvaluation(ctx context.Context, jobID, operation string) {
@@ -385,3 +430,11 @@ func (e *BaseEndpoint) enqueueEvaluation(ctx context.Context, jobID, operation s
 // Compile-time interface check:
 var _ Endpoint = (*BaseEndpoint)(nil)
 var _ compute.Callback = (*BaseEndpoint)(nil)
+
+// egressEvents returns the event recording the egress of an execution, if it made any outbound requests.
+func egressEvents(egress *models.EgressLog) []models.Event {
+	if egress.IsEmpty() {
+		return nil
+	}
+	return []models.Event{orchestrator.ExecEgressEvent(egress)}
+}
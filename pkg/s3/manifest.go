@@ -0,0 +1,95 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ManifestName is the name of the manifest object that results published as individual objects
+// have under their prefix
+const ManifestName = ".bacalhau-manifest.json"
+
+// Manifest lists the objects of a result published as individual objects under a prefix
+type Manifest struct {
+	Files []ManifestFile `json:"Files"`
+}
+
+// ManifestFile is an object of a result published as individual objects
+type ManifestFile struct {
+	// Path is the slash separated path of the file in the result, and of the object under the prefix
+	Path           string `json:"Path"`
+	Size           int64  `json:"Size"`
+	ETag           string `json:"ETag,omitempty"`
+	VersionID      string `json:"VersionID,omitempty"`
+	ChecksumSHA256 string `json:"ChecksumSHA256,omitempty"`
+}
+
+// ManifestKey returns the key of the manifest of a result published under a prefix
+func ManifestKey(prefix string) string {
+	return prefix + ManifestName
+}
+
+// ObjectKey returns the key of a file of a result published under a prefix
+func (f ManifestFile) ObjectKey(prefix string) string {
+	return prefix + f.Path
+}
+
+// Validate returns an error if a file of the manifest would be written outside the result directory
+func (m Manifest) Validate() error {
+	for _, file := range m.Files {
+		clean := path.Clean(file.Path)
+		if file.Path == "" || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("invalid s3 manifest: path %q is not within the result", file.Path)
+		}
+	}
+	return nil
+}
+
+// ReadManifest fetches and decodes a manifest object
+func ReadManifest(ctx context.Context, client *s3.Client, bucket, key string) (Manifest, error) {
+	res, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to get s3 manifest s3://%s/%s: %w", bucket, key, err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	var manifest Manifest
+	if err = json.NewDecoder(res.Body).Decode(&manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to decode s3 manifest s3://%s/%s: %w", bucket, key, err)
+	}
+	return manifest, manifest.Validate()
+}
+
+// WriteManifest encodes and uploads a manifest object
+func WriteManifest(ctx context.Context, client MultipartAPI, bucket, key string, manifest Manifest) (UploadResult, error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	res, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/json"),
+	})
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("failed to upload s3 manifest s3://%s/%s: %w", bucket, key, err)
+	}
+	return UploadResult{
+		Key:       key,
+		ETag:      aws.ToString(res.ETag),
+		VersionID: aws.ToString(res.VersionId),
+		Size:      int64(len(data)),
+	}, nil
+}
@@ -0,0 +1,39 @@
+//go:build unit || !integration
+
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		path  string
+		valid bool
+	}{
+		{"stdout", true},
+		{"outputs/nested/1.txt", true},
+		{"outputs/../stdout", true},
+		{"", false},
+		{"/etc/passwd", false},
+		{"..", false},
+		{"../outputs/1.txt", false},
+		{"outputs/../../1.txt", false},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			err := Manifest{Files: []ManifestFile{{Path: tc.path}}}.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestManifestKeys(t *testing.T) {
+	require.Equal(t, "results/job/"+ManifestName, ManifestKey("results/job/"))
+	require.Equal(t, "results/job/outputs/1.txt", ManifestFile{Path: "outputs/1.txt"}.ObjectKey("results/job/"))
+}
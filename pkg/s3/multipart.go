@@ -0,0 +1,377 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // used to compare with the ETag of uploaded parts, not for security
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+)
+
+const (
+	// DefaultPartSize is the size of the parts of multipart uploads. Files up to this size are uploaded
+	// with a single request.
+	DefaultPartSize = 64 * 1024 * 1024
+	// DefaultUploadConcurrency is how many parts, or files, are uploaded in parallel
+	DefaultUploadConcurrency = 4
+	// DefaultUploadRetries is how many times the upload of a part is retried before the upload fails
+	DefaultUploadRetries = 3
+
+	// minPartSize and maxParts are the limits S3 puts on multipart uploads
+	minPartSize = 5 * 1024 * 1024
+	maxParts    = 10000
+
+	uploadBaseBackoff = 500 * time.Millisecond
+	uploadMaxBackoff  = 10 * time.Second
+)
+
+// MultipartAPI is the subset of the S3 API used to upload objects in parts
+type MultipartAPI interface {
+	PutObject(context.Context, *s3.PutObjectInput, ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(
+		context.Context, *s3.CreateMultipartUploadInput, ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(context.Context, *s3.UploadPartInput, ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	ListParts(context.Context, *s3.ListPartsInput, ...func(*s3.Options)) (*s3.ListPartsOutput, error)
+	ListMultipartUploads(
+		context.Context, *s3.ListMultipartUploadsInput, ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+	CompleteMultipartUpload(
+		context.Context, *s3.CompleteMultipartUploadInput, ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+}
+
+type MultipartUploaderParams struct {
+	Client      MultipartAPI
+	PartSize    int64
+	Concurrency int
+	MaxRetries  int
+	Backoff     backoff.Backoff
+	// Checksum adds SHA256 checksums to the uploads, which only AWS supports
+	Checksum bool
+}
+
+// MultipartUploader uploads files to S3, in parallel parts for the files larger than the part size. Parts that
+// fail to upload are retried, and a multipart upload that still fails is left incomplete, so that the next
+// upload of the same file to the same key resumes it, reusing the parts that were already uploaded.
+//
+// Incomplete uploads that are never resumed should be cleaned up by a lifecycle rule on the bucket.
+type MultipartUploader struct {
+	client      MultipartAPI
+	partSize    int64
+	concurrency int
+	maxRetries  int
+	backoff     backoff.Backoff
+	checksum    bool
+}
+
+// UploadResult describes an uploaded object
+type UploadResult struct {
+	Key            string
+	ETag           string
+	VersionID      string
+	ChecksumSHA256 string
+	Size           int64
+}
+
+func NewMultipartUploader(params MultipartUploaderParams) *MultipartUploader {
+	if params.PartSize < minPartSize {
+		params.PartSize = DefaultPartSize
+	}
+	if params.Concurrency <= 0 {
+		params.Concurrency = DefaultUploadConcurrency
+	}
+	if params.MaxRetries <= 0 {
+		params.MaxRetries = DefaultUploadRetries
+	}
+	if params.Backoff == nil {
+		params.Backoff = backoff.NewExponential(uploadBaseBackoff, uploadMaxBackoff)
+	}
+	return &MultipartUploader{
+		client:      params.Client,
+		partSize:    params.PartSize,
+		concurrency: params.Concurrency,
+		maxRetries:  params.MaxRetries,
+		backoff:     params.Backoff,
+		checksum:    params.Checksum,
+	}
+}
+
+// Concurrency returns how many parts, or files, the uploader uploads in parallel
+func (u *MultipartUploader) Concurrency() int {
+	return u.concurrency
+}
+
+// UploadFile uploads a local file to an object
+func (u *MultipartUploader) UploadFile(ctx context.Context, bucket, key, path string) (UploadResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	info, err := file.Stat()
+	if err != nil {
+		return UploadResult{}, err
+	}
+	if info.Size() <= u.partSize {
+		return u.putObject(ctx, bucket, key, file, info.Size())
+	}
+	return u.multipartUpload(ctx, bucket, key, file, info.Size())
+}
+
+// putObject uploads a file with a single request
+func (u *MultipartUploader) putObject(ctx context.Context, bucket, key string, file *os.File, size int64) (UploadResult, error) {
+	var checksum *string
+	if u.checksum {
+		sum, err := partChecksum(io.NewSectionReader(file, 0, size))
+		if err != nil {
+			return UploadResult{}, err
+		}
+		checksum = aws.String(sum)
+	}
+	var res *s3.PutObjectOutput
+	err := u.retry(ctx, fmt.Sprintf("upload of s3://%s/%s", bucket, key), func() error {
+		var err error
+		res, err = u.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:         aws.String(bucket),
+			Key:            aws.String(key),
+			Body:           io.NewSectionReader(file, 0, size),
+			ContentLength:  aws.Int64(size),
+			ChecksumSHA256: checksum,
+		})
+		return err
+	})
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{
+		Key:            key,
+		ETag:           aws.ToString(res.ETag),
+		VersionID:      aws.ToString(res.VersionId),
+		ChecksumSHA256: aws.ToString(res.ChecksumSHA256),
+		Size:           size,
+	}, nil
+}
+
+// multipartUpload uploads a file in parts, resuming an incomplete upload of the key if there is one
+func (u *MultipartUploader) multipartUpload(
+	ctx context.Context, bucket, key string, file *os.File, size int64) (UploadResult, error) {
+	partSize := u.partSize
+	if size > partSize*maxParts {
+		partSize = (size + maxParts - 1) / maxParts
+	}
+	partCount := int((size + partSize - 1) / partSize)
+
+	// listing incomplete uploads requires more permissions than uploading, so it does not fail the upload
+	uploadID, uploaded, err := u.resumableUpload(ctx, bucket, key)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msgf("not resuming upload of s3://%s/%s", bucket, key)
+		uploadID, uploaded = "", nil
+	}
+	if uploadID == "" {
+		input := &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}
+		if u.checksum {
+			input.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+		}
+		res, err := u.client.CreateMultipartUpload(ctx, input)
+		if err != nil {
+			return UploadResult{}, fmt.Errorf("failed to create multipart upload of s3://%s/%s: %w", bucket, key, err)
+		}
+		uploadID = aws.ToString(res.UploadId)
+	} else {
+		log.Ctx(ctx).Debug().Msgf("resuming multipart upload %s of s3://%s/%s with %d uploaded parts",
+			uploadID, bucket, key, len(uploaded))
+	}
+
+	parts := make([]types.CompletedPart, partCount)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(u.concurrency)
+	for i := 0; i < partCount; i++ {
+		number := int32(i + 1)
+		offset := int64(i) * partSize
+		section := io.NewSectionReader(file, offset, min(partSize, size-offset))
+		group.Go(func() error {
+			part, err := u.uploadPart(groupCtx, bucket, key, uploadID, number, section, uploaded[number])
+			if err != nil {
+				return err
+			}
+			parts[number-1] = part
+			return nil
+		})
+	}
+	if err = group.Wait(); err != nil {
+		// the upload is not aborted, so that it can be resumed
+		return UploadResult{}, fmt.Errorf("failed to upload s3://%s/%s, which can be resumed: %w", bucket, key, err)
+	}
+
+	var res *s3.CompleteMultipartUploadOutput
+	err = u.retry(ctx, fmt.Sprintf("completion of upload of s3://%s/%s", bucket, key), func() error {
+		var err error
+		res, err = u.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(bucket),
+			Key:             aws.String(key),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+		return err
+	})
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return UploadResult{
+		Key:            key,
+		ETag:           aws.ToString(res.ETag),
+		VersionID:      aws.ToString(res.VersionId),
+		ChecksumSHA256: aws.ToString(res.ChecksumSHA256),
+		Size:           size,
+	}, nil
+}
+
+// uploadPart uploads a part, unless the part already uploaded with the same number has the same content
+func (u *MultipartUploader) uploadPart(ctx context.Context, bucket, key, uploadID string,
+	number int32, section *io.SectionReader, existing *types.Part) (types.CompletedPart, error) {
+	if existing != nil && aws.ToInt64(existing.Size) == section.Size() {
+		etag, err := partETag(section)
+		if err != nil {
+			return types.CompletedPart{}, err
+		}
+		// the ETag of a part is the MD5 of its content, unless the bucket encrypts it with KMS
+		if etag == aws.ToString(existing.ETag) {
+			return types.CompletedPart{
+				PartNumber:     aws.Int32(number),
+				ETag:           existing.ETag,
+				ChecksumSHA256: existing.ChecksumSHA256,
+			}, nil
+		}
+	}
+
+	var checksum *string
+	if u.checksum {
+		sum, err := partChecksum(section)
+		if err != nil {
+			return types.CompletedPart{}, err
+		}
+		checksum = aws.String(sum)
+	}
+	var res *s3.UploadPartOutput
+	err := u.retry(ctx, fmt.Sprintf("upload of part %d of s3://%s/%s", number, bucket, key), func() error {
+		if _, err := section.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		var err error
+		res, err = u.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:         aws.String(bucket),
+			Key:            aws.String(key),
+			UploadId:       aws.String(uploadID),
+			PartNumber:     aws.Int32(number),
+			Body:           section,
+			ContentLength:  aws.Int64(section.Size()),
+			ChecksumSHA256: checksum,
+		})
+		return err
+	})
+	if err != nil {
+		return types.CompletedPart{}, err
+	}
+	return types.CompletedPart{
+		PartNumber:     aws.Int32(number),
+		ETag:           res.ETag,
+		ChecksumSHA256: res.ChecksumSHA256,
+	}, nil
+}
+
+// resumableUpload returns the ID and uploaded parts of the most recent incomplete multipart upload of a key,
+// or an empty ID if there is none.
+func (u *MultipartUploader) resumableUpload(ctx context.Context, bucket, key string) (string, map[int32]*types.Part, error) {
+	res, err := u.client.ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list multipart uploads of s3://%s/%s: %w", bucket, key, err)
+	}
+	var uploads []types.MultipartUpload
+	for _, upload := range res.Uploads {
+		if aws.ToString(upload.Key) == key {
+			uploads = append(uploads, upload)
+		}
+	}
+	if len(uploads) == 0 {
+		return "", nil, nil
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		return aws.ToTime(uploads[i].Initiated).After(aws.ToTime(uploads[j].Initiated))
+	})
+	uploadID := aws.ToString(uploads[0].UploadId)
+
+	parts := make(map[int32]*types.Part)
+	var marker *string
+	for {
+		partsRes, err := u.client.ListParts(ctx, &s3.ListPartsInput{
+			Bucket:           aws.String(bucket),
+			Key:              aws.String(key),
+			UploadId:         aws.String(uploadID),
+			PartNumberMarker: marker,
+		})
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to list parts of multipart upload of s3://%s/%s: %w", bucket, key, err)
+		}
+		for i := range partsRes.Parts {
+			part := partsRes.Parts[i]
+			parts[aws.ToInt32(part.PartNumber)] = &part
+		}
+		if !aws.ToBool(partsRes.IsTruncated) {
+			break
+		}
+		marker = partsRes.NextPartNumberMarker
+	}
+	return uploadID, parts, nil
+}
+
+// retry calls fn until it succeeds, backing off between attempts, up to the maximum number of retries
+func (u *MultipartUploader) retry(ctx context.Context, operation string, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= u.maxRetries; attempt++ {
+		u.backoff.Backoff(ctx, attempt)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		log.Ctx(ctx).Debug().Err(err).Msgf("attempt %d of %s failed", attempt+1, operation)
+	}
+	return err
+}
+
+// partETag returns the quoted hex encoded MD5 of a part, which is its ETag once uploaded
+func partETag(section *io.SectionReader) (string, error) {
+	hash := md5.New() //nolint:gosec
+	if _, err := io.Copy(hash, io.NewSectionReader(section, 0, section.Size())); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`, nil
+}
+
+// partChecksum returns the base64 encoded SHA256 of a part
+func partChecksum(section *io.SectionReader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(section, 0, section.Size())); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
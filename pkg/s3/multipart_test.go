@@ -0,0 +1,252 @@
+//go:build unit || !integration
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+)
+
+type fakeUpload struct {
+	key       string
+	initiated time.Time
+	parts     map[int32][]byte
+}
+
+// fakeMultipartAPI is an in-memory bucket that fails the upload of parts as many times as configured
+type fakeMultipartAPI struct {
+	mu            sync.Mutex
+	objects       map[string][]byte
+	uploads       map[string]*fakeUpload
+	partFailures  map[int32]int
+	uploadedParts []int32
+	puts          int
+}
+
+func newFakeMultipartAPI() *fakeMultipartAPI {
+	return &fakeMultipartAPI{
+		objects:      make(map[string][]byte),
+		uploads:      make(map[string]*fakeUpload),
+		partFailures: make(map[int32]int),
+	}
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data) //nolint:gosec
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (f *fakeMultipartAPI) PutObject(
+	_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.puts++
+	f.objects[aws.ToString(in.Key)] = data
+	return &s3.PutObjectOutput{ETag: aws.String(etag(data))}, nil
+}
+
+func (f *fakeMultipartAPI) CreateMultipartUpload(
+	_ context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := fmt.Sprintf("upload-%d", len(f.uploads))
+	f.uploads[id] = &fakeUpload{key: aws.ToString(in.Key), initiated: time.Now(), parts: make(map[int32][]byte)}
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeMultipartAPI) UploadPart(
+	_ context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	number := aws.ToInt32(in.PartNumber)
+	f.uploadedParts = append(f.uploadedParts, number)
+	if f.partFailures[number] > 0 {
+		f.partFailures[number]--
+		return nil, errors.New("connection reset")
+	}
+	f.uploads[aws.ToString(in.UploadId)].parts[number] = data
+	return &s3.UploadPartOutput{ETag: aws.String(etag(data))}, nil
+}
+
+func (f *fakeMultipartAPI) ListParts(
+	_ context.Context, in *s3.ListPartsInput, _ ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &s3.ListPartsOutput{IsTruncated: aws.Bool(false)}
+	for number, data := range f.uploads[aws.ToString(in.UploadId)].parts {
+		res.Parts = append(res.Parts, types.Part{
+			PartNumber: aws.Int32(number),
+			ETag:       aws.String(etag(data)),
+			Size:       aws.Int64(int64(len(data))),
+		})
+	}
+	return res, nil
+}
+
+func (f *fakeMultipartAPI) ListMultipartUploads(
+	_ context.Context, in *s3.ListMultipartUploadsInput, _ ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := &s3.ListMultipartUploadsOutput{}
+	for id, upload := range f.uploads {
+		res.Uploads = append(res.Uploads, types.MultipartUpload{
+			Key:       aws.String(upload.key),
+			UploadId:  aws.String(id),
+			Initiated: aws.Time(upload.initiated),
+		})
+	}
+	return res, nil
+}
+
+func (f *fakeMultipartAPI) CompleteMultipartUpload(
+	_ context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	upload := f.uploads[aws.ToString(in.UploadId)]
+	var data []byte
+	for i, part := range in.MultipartUpload.Parts {
+		number := aws.ToInt32(part.PartNumber)
+		if number != int32(i+1) || aws.ToString(part.ETag) != etag(upload.parts[number]) {
+			return nil, fmt.Errorf("invalid part %d", number)
+		}
+		data = append(data, upload.parts[number]...)
+	}
+	delete(f.uploads, aws.ToString(in.UploadId))
+	f.objects[upload.key] = data
+	return &s3.CompleteMultipartUploadOutput{ETag: aws.String(fmt.Sprintf(`"multipart-%d"`, len(in.MultipartUpload.Parts)))}, nil
+}
+
+type MultipartUploaderSuite struct {
+	suite.Suite
+	ctx      context.Context
+	client   *fakeMultipartAPI
+	uploader *MultipartUploader
+}
+
+func TestMultipartUploaderSuite(t *testing.T) {
+	suite.Run(t, new(MultipartUploaderSuite))
+}
+
+func (s *MultipartUploaderSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.client = newFakeMultipartAPI()
+	s.uploader = NewMultipartUploader(MultipartUploaderParams{
+		Client:      s.client,
+		PartSize:    minPartSize,
+		Concurrency: 2,
+		MaxRetries:  1,
+		Backoff:     backoff.NewNoop(),
+	})
+}
+
+func (s *MultipartUploaderSuite) writeFile(size int) (string, []byte) {
+	data := make([]byte, size)
+	_, err := rand.New(rand.NewSource(int64(size))).Read(data) //nolint:gosec
+	s.Require().NoError(err)
+	path := filepath.Join(s.T().TempDir(), "file")
+	s.Require().NoError(os.WriteFile(path, data, 0644))
+	return path, data
+}
+
+func (s *MultipartUploaderSuite) TestUploadSmallFile() {
+	path, data := s.writeFile(1024)
+	res, err := s.uploader.UploadFile(s.ctx, "bucket", "small", path)
+	s.Require().NoError(err)
+	s.Equal(1, s.client.puts)
+	s.Empty(s.client.uploadedParts)
+	s.Equal(etag(data), res.ETag)
+	s.Equal(int64(1024), res.Size)
+	s.True(bytes.Equal(data, s.client.objects["small"]))
+}
+
+func (s *MultipartUploaderSuite) TestUploadLargeFile() {
+	path, data := s.writeFile(2*minPartSize + 1024)
+	res, err := s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().NoError(err)
+	s.Equal(0, s.client.puts)
+	s.ElementsMatch([]int32{1, 2, 3}, s.client.uploadedParts)
+	s.Equal(`"multipart-3"`, res.ETag)
+	s.True(bytes.Equal(data, s.client.objects["large"]))
+	s.Empty(s.client.uploads)
+}
+
+func (s *MultipartUploaderSuite) TestRetryFailedPart() {
+	path, data := s.writeFile(2*minPartSize + 1024)
+	s.client.partFailures[2] = 1
+	_, err := s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().NoError(err)
+	s.ElementsMatch([]int32{1, 2, 2, 3}, s.client.uploadedParts)
+	s.True(bytes.Equal(data, s.client.objects["large"]))
+}
+
+// sequentialUploader uploads one part at a time, so that the parts following a failed part are not uploaded
+func (s *MultipartUploaderSuite) sequentialUploader() {
+	s.uploader = NewMultipartUploader(MultipartUploaderParams{
+		Client:      s.client,
+		PartSize:    minPartSize,
+		Concurrency: 1,
+		MaxRetries:  1,
+		Backoff:     backoff.NewNoop(),
+	})
+}
+
+func (s *MultipartUploaderSuite) TestResumeFailedUpload() {
+	s.sequentialUploader()
+	path, data := s.writeFile(2*minPartSize + 1024)
+	s.client.partFailures[2] = 2
+	_, err := s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().Error(err)
+	s.NotContains(s.client.objects, "large")
+	s.Len(s.client.uploads, 1)
+
+	// the part uploaded before the failure is not uploaded again
+	s.client.uploadedParts = nil
+	_, err = s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().NoError(err)
+	s.Equal([]int32{2, 3}, s.client.uploadedParts)
+	s.True(bytes.Equal(data, s.client.objects["large"]))
+	s.Empty(s.client.uploads)
+}
+
+func (s *MultipartUploaderSuite) TestResumeChangedFile() {
+	s.sequentialUploader()
+	path, _ := s.writeFile(2*minPartSize + 1024)
+	s.client.partFailures[2] = 2
+	_, err := s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().Error(err)
+
+	// parts whose content changed since the failed upload are uploaded again
+	data := make([]byte, 2*minPartSize+1024)
+	s.Require().NoError(os.WriteFile(path, data, 0644))
+	s.client.uploadedParts = nil
+	_, err = s.uploader.UploadFile(s.ctx, "bucket", "large", path)
+	s.Require().NoError(err)
+	s.ElementsMatch([]int32{1, 2, 3}, s.client.uploadedParts)
+	s.True(bytes.Equal(data, s.client.objects["large"]))
+}
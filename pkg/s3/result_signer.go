@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/rs/zerolog/log"
@@ -43,25 +44,64 @@ func (signer *ResultSigner) Transform(ctx context.Context, spec *models.SpecConf
 		return err
 	}
 
+	if sourceSpec.Manifest != "" {
+		return signer.transformManifest(ctx, spec, sourceSpec)
+	}
+
 	if !strings.HasSuffix(sourceSpec.Key, ".tar.gz") {
 		log.Ctx(ctx).Debug().Str("S3Key", sourceSpec.Key).Msg("Skipping signing because the result is not a tar.gz file.")
 		return nil
 	}
 	client := signer.clientProvider.GetClient(sourceSpec.Endpoint, sourceSpec.Region)
-	request := &s3.GetObjectInput{
-		Bucket: &sourceSpec.Bucket,
-		Key:    &sourceSpec.Key,
-	}
 	log.Ctx(ctx).Debug().Msgf("Signing URL for s3://%s/%s", sourceSpec.Bucket, sourceSpec.Key)
 
-	resp, err := client.PresignClient().PresignGetObject(ctx, request, s3.WithPresignExpires(signer.expiration))
+	url, err := signer.presign(ctx, client, sourceSpec.Bucket, sourceSpec.Key, sourceSpec.VersionID)
 	if err != nil {
 		return err
 	}
 	spec.Type = models.StorageSourceS3PreSigned
 	spec.Params = PreSignedResultSpec{
 		SourceSpec:   sourceSpec,
-		PreSignedURL: resp.URL,
+		PreSignedURL: url,
 	}.ToMap()
 	return nil
 }
+
+// transformManifest signs each file of a result published as individual objects
+func (signer *ResultSigner) transformManifest(ctx context.Context, spec *models.SpecConfig, sourceSpec SourceSpec) error {
+	client := signer.clientProvider.GetClient(sourceSpec.Endpoint, sourceSpec.Region)
+	manifest, err := ReadManifest(ctx, client.S3, sourceSpec.Bucket, sourceSpec.Manifest)
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Debug().Msgf("Signing URLs for %d files under s3://%s/%s", len(manifest.Files), sourceSpec.Bucket, sourceSpec.Key)
+
+	urls := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		urls[file.Path], err = signer.presign(ctx, client, sourceSpec.Bucket, file.ObjectKey(sourceSpec.Key), file.VersionID)
+		if err != nil {
+			return err
+		}
+	}
+	spec.Type = models.StorageSourceS3PreSigned
+	spec.Params = PreSignedResultSpec{
+		SourceSpec:    sourceSpec,
+		PreSignedURLs: urls,
+	}.ToMap()
+	return nil
+}
+
+func (signer *ResultSigner) presign(ctx context.Context, client *ClientWrapper, bucket, key, versionID string) (string, error) {
+	request := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		request.VersionId = aws.String(versionID)
+	}
+	resp, err := client.PresignClient().PresignGetObject(ctx, request, s3.WithPresignExpires(signer.expiration))
+	if err != nil {
+		return "", err
+	}
+	return resp.URL, nil
+}
@@ -150,6 +150,7 @@ func (s *HelperSuite) PreparePublisherSpec(compressed bool) s3helper.PublisherSp
 		Key:      prefix,
 		Region:   s.Region,
 		Endpoint: s.Endpoint,
+		Archive:  compressed,
 	}
 }
 
@@ -17,6 +17,9 @@ type SourceSpec struct {
 	Endpoint       string
 	VersionID      string
 	ChecksumSHA256 string
+	// Manifest is the key of the manifest of a result published as individual objects under Key,
+	// which is then a prefix.
+	Manifest string `structs:",omitempty"`
 }
 
 func (c SourceSpec) Validate() error {
@@ -33,10 +36,13 @@ func (c SourceSpec) ToMap() map[string]interface{} {
 type PreSignedResultSpec struct {
 	SourceSpec
 	PreSignedURL string
+	// PreSignedURLs are the pre-signed URLs of the files of a result published as individual objects,
+	// by their path in the result.
+	PreSignedURLs map[string]string
 }
 
 func (c PreSignedResultSpec) Validate() error {
-	if c.PreSignedURL == "" {
+	if c.PreSignedURL == "" && len(c.PreSignedURLs) == 0 {
 		return errors.New("invalid s3 signed storage params: signed url cannot be empty")
 	}
 	return c.SourceSpec.Validate()
@@ -51,6 +57,9 @@ type PublisherSpec struct {
 	Key      string `json:"Key"`
 	Endpoint string `json:"Endpoint"`
 	Region   string `json:"Region"`
+	// Archive publishes the result as a single tar.gz archive when true, which is the default,
+	// or as individual objects under the key with a manifest when false.
+	Archive bool `json:"Archive"`
 }
 
 func DecodeSourceSpec(spec *models.SpecConfig) (SourceSpec, error) {
@@ -99,8 +108,16 @@ func DecodePublisherSpec(spec *models.SpecConfig) (PublisherSpec, error) {
 		return PublisherSpec{}, fmt.Errorf("invalid publisher params. cannot be nil")
 	}
 
-	var c PublisherSpec
-	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+	c := PublisherSpec{Archive: true}
+	// options set on the command line, such as archive=false, are strings
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &c,
+	})
+	if err != nil {
+		return c, err
+	}
+	if err = decoder.Decode(spec.Params); err != nil {
 		return c, err
 	}
 
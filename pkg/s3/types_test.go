@@ -40,6 +40,7 @@ func (s *ParamsTestSuite) TestDecodeInterface() {
 		Key:      uuid.NewString(),
 		Endpoint: "localhost:9000",
 		Region:   "us-east-1",
+		Archive:  true,
 	}
 	params := map[string]interface{}{
 		"Bucket":   expected.Bucket,
@@ -61,6 +62,7 @@ func (s *ParamsTestSuite) TestDecodeInterfaceLowerCase() {
 		Key:      uuid.NewString(),
 		Endpoint: "localhost:9000",
 		Region:   "us-east-1",
+		Archive:  true,
 	}
 	params := map[string]interface{}{
 		"bucket":   expected.Bucket,
@@ -76,6 +78,31 @@ func (s *ParamsTestSuite) TestDecodeInterfaceLowerCase() {
 	s.Equal(expected, decoded)
 }
 
+func (s *ParamsTestSuite) TestDecodeWithoutArchive() {
+	decoded, err := DecodePublisherSpec(&models.SpecConfig{
+		Type: models.PublisherS3,
+		Params: map[string]interface{}{
+			"Bucket":  "bucket",
+			"Key":     "results/",
+			"Archive": false,
+		},
+	})
+	s.Require().NoError(err)
+	s.False(decoded.Archive)
+
+	// publisher options of the command line are strings
+	decoded, err = DecodePublisherSpec(&models.SpecConfig{
+		Type: models.PublisherS3,
+		Params: map[string]interface{}{
+			"bucket":  "bucket",
+			"key":     "results/",
+			"archive": "false",
+		},
+	})
+	s.Require().NoError(err)
+	s.False(decoded.Archive)
+}
+
 func (s *ParamsTestSuite) TestDecodeJson() {
 	expected := PublisherSpec{
 		Bucket:   "bucket",
@@ -140,3 +167,14 @@ func (s *ParamsTestSuite) TestDecodeInvalidParams() {
 		})
 	}
 }
+
+func (s *ParamsTestSuite) TestSourceSpecToMapOmitsEmptyManifest() {
+	spec := SourceSpec{Bucket: "bucket", Key: "key"}
+	s.NotContains(spec.ToMap(), "Manifest")
+
+	spec.Manifest = "key/manifest.json"
+	s.Equal(spec.Manifest, spec.ToMap()["Manifest"])
+	decoded, err := DecodeSourceSpec(&models.SpecConfig{Type: models.StorageSourceS3, Params: spec.ToMap()})
+	s.Require().NoError(err)
+	s.Equal(spec, decoded)
+}
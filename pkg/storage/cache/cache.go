@@ -0,0 +1,475 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/filecopy"
+)
+
+const (
+	entryMetadataFile = "entry.json"
+	entryDataDir      = "data"
+	stagingDirPrefix  = ".staging-"
+	cacheDirPerm      = 0755
+	entryMetadataPerm = 0644
+)
+
+// entry is a cached input, stored in a directory of the cache named after its key, with its metadata
+// in entry.json and its data under data/<Name>.
+type entry struct {
+	Key string `json:"Key"`
+	// Name is the name of the file or directory the data was prepared as. It is kept when the data is
+	// prepared from the cache, as it can be part of the target, such as the file name of a URL.
+	Name string `json:"Name"`
+	// Version is the version of the data of the input source when it was cached, such as its CID or ETag.
+	// The data is only reused while the source still points to the same version.
+	Version string `json:"Version"`
+	// TargetSuffix is the path of the prepared volume relative to the target of the input source
+	TargetSuffix string                             `json:"TargetSuffix"`
+	Type         storage.StorageVolumeConnectorType `json:"Type"`
+	Size         uint64                             `json:"Size"`
+	CreateTime   time.Time                          `json:"CreateTime"`
+
+	lastUsed time.Time
+	// pins counts the entry's data being linked, during which it must not be evicted
+	pins    int
+	element *list.Element
+}
+
+type InputCacheParams struct {
+	// Path is the directory the cached inputs are stored in
+	Path string
+	// MaxSize is the maximum total size of the cached inputs in bytes
+	MaxSize uint64
+	// TTL is how long inputs are cached for after they are first fetched. Zero caches them until evicted.
+	TTL   time.Duration
+	Clock clock.Clock
+}
+
+// InputCache is a local cache of the data of input sources shared by the executions of a compute node.
+// Inputs are keyed by the hash of their source, along with the version of the data the source pointed to,
+// and the least recently used ones are evicted when the cache is full. Cached data is hard linked into the
+// storage directory of each execution and mounted read-only, so that executions neither copy the data, nor
+// modify the cache, nor lose their inputs to an eviction. Data is only copied when it cannot be linked,
+// such as when the cache is on another file system.
+type InputCache struct {
+	path    string
+	maxSize uint64
+	ttl     time.Duration
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	// lru orders the keys of the entries from the most to the least recently used
+	lru  *list.List
+	size uint64
+	// prepared holds the directories of the volumes prepared from the cache, keyed by their source
+	prepared map[string]string
+}
+
+// NewInputCache creates an input cache in a directory, and loads the inputs cached in it by previous runs
+func NewInputCache(params InputCacheParams) (*InputCache, error) {
+	if params.Path == "" {
+		return nil, errors.New("input cache path is required")
+	}
+	if params.MaxSize == 0 {
+		return nil, errors.New("input cache max size must be greater than zero")
+	}
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	if err := os.MkdirAll(params.Path, cacheDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create input cache directory %s: %w", params.Path, err)
+	}
+	c := &InputCache{
+		path:     params.Path,
+		maxSize:  params.MaxSize,
+		ttl:      params.TTL,
+		clock:    params.Clock,
+		entries:  make(map[string]*entry),
+		lru:      list.New(),
+		prepared: make(map[string]string),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Has returns true if the input with the given key is cached
+func (c *InputCache) Has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookup(key) != nil
+}
+
+// Size returns the size of the cached input with the given key, and false if it is not cached
+func (c *InputCache) Size(key string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.lookup(key)
+	if e == nil {
+		return 0, false
+	}
+	return e.Size, true
+}
+
+// Keys returns the keys of the cached inputs, from the most to the least recently used, up to limit keys.
+// A limit of zero returns all of them.
+func (c *InputCache) Keys(limit int) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, c.lru.Len())
+	for element := c.lru.Front(); element != nil; element = element.Next() {
+		if limit > 0 && len(keys) >= limit {
+			break
+		}
+		e := c.entries[element.Value.(string)]
+		if !c.expired(e) {
+			keys = append(keys, e.Key)
+		}
+	}
+	return keys
+}
+
+// TotalSize returns the total size of the cached inputs in bytes
+func (c *InputCache) TotalSize() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// prepare links the cached input with the given key into a new directory of storageDirectory, and returns
+// its read-only volume. It returns false if the input is not cached, or was cached at another version.
+func (c *InputCache) prepare(
+	key string, version string, storageDirectory string, target string) (storage.StorageVolume, bool, error) {
+	c.mu.Lock()
+	e := c.lookup(key)
+	if e == nil || e.Version != version {
+		c.mu.Unlock()
+		return storage.StorageVolume{}, false, nil
+	}
+	e.pins++
+	c.touch(e)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		e.pins--
+	}()
+
+	dir, err := os.MkdirTemp(storageDirectory, "cached-*")
+	if err != nil {
+		return storage.StorageVolume{}, false, err
+	}
+	source := filepath.Join(dir, e.Name)
+	if err = linkPath(filepath.Join(c.entryPath(key), entryDataDir, e.Name), source); err != nil {
+		_ = os.RemoveAll(dir)
+		return storage.StorageVolume{}, false, fmt.Errorf("failed to link cached input %s: %w", key, err)
+	}
+
+	c.mu.Lock()
+	c.prepared[source] = dir
+	c.mu.Unlock()
+	return storage.StorageVolume{
+		Type:     e.Type,
+		ReadOnly: true,
+		Source:   source,
+		Target:   filepath.Join(target, e.TargetSuffix),
+	}, true, nil
+}
+
+// release removes a volume prepared from the cache, and returns false if the volume was not prepared by it
+func (c *InputCache) release(volume storage.StorageVolume) (bool, error) {
+	c.mu.Lock()
+	dir, ok := c.prepared[volume.Source]
+	delete(c.prepared, volume.Source)
+	c.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, os.RemoveAll(dir)
+}
+
+// add caches a copy of the data of a volume prepared for an input source mounted on target, replacing the
+// data cached at another version of the source. Volumes that are not bind mounts, are larger than the
+// cache, or whose target is not under the input's target are not cached.
+func (c *InputCache) add(key string, version string, target string, volume storage.StorageVolume) error {
+	if volume.Type != storage.StorageVolumeConnectorBind {
+		return nil
+	}
+	targetSuffix, ok := relativeTarget(target, volume.Target)
+	if !ok {
+		return nil
+	}
+	size, err := pathSize(volume.Source)
+	if err != nil {
+		return err
+	}
+	if size > c.maxSize {
+		return nil
+	}
+	c.mu.Lock()
+	cached := c.lookup(key)
+	c.mu.Unlock()
+	if cached != nil && cached.Version == version {
+		return nil
+	}
+
+	staging, err := os.MkdirTemp(c.path, stagingDirPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	e := &entry{
+		Key:          key,
+		Name:         filepath.Base(volume.Source),
+		Version:      version,
+		TargetSuffix: targetSuffix,
+		Type:         volume.Type,
+		Size:         size,
+		CreateTime:   c.clock.Now().UTC(),
+	}
+	if err = os.Mkdir(filepath.Join(staging, entryDataDir), cacheDirPerm); err != nil {
+		return err
+	}
+	if err = copyPath(volume.Source, filepath.Join(staging, entryDataDir, e.Name)); err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(staging, entryMetadataFile), metadata, entryMetadataPerm); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, exists := c.entries[key]; exists {
+		// the same input may have been cached by a concurrent execution while it was being copied,
+		// and the data of an older version cannot be replaced while it is being linked
+		if cached.Version == version || cached.pins > 0 {
+			return nil
+		}
+		log.Debug().Msgf("replacing input %s cached at version %s with version %s", key, cached.Version, version)
+		c.remove(cached)
+	}
+	if !c.evict(size) {
+		log.Debug().Msgf("not caching input %s of %d bytes, as the input cache is full of inputs in use", key, size)
+		return nil
+	}
+	if err = os.Rename(staging, c.entryPath(key)); err != nil {
+		return err
+	}
+	if err = os.Chtimes(c.entryPath(key), e.CreateTime, e.CreateTime); err != nil {
+		log.Debug().Err(err).Msgf("failed to record last use of cached input %s", key)
+	}
+	c.insert(e, e.CreateTime)
+	return nil
+}
+
+// lookup returns the entry of a key, or nil if it is not cached or has expired. Expired entries that are not
+// in use are removed. It must be called with the lock held.
+func (c *InputCache) lookup(key string) *entry {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if c.expired(e) {
+		if e.pins == 0 {
+			c.remove(e)
+		}
+		return nil
+	}
+	return e
+}
+
+func (c *InputCache) expired(e *entry) bool {
+	return c.ttl > 0 && c.clock.Since(e.CreateTime) > c.ttl
+}
+
+// touch marks an entry as the most recently used. The modification time of its directory records when it
+// was last used across restarts. It must be called with the lock held.
+func (c *InputCache) touch(e *entry) {
+	e.lastUsed = c.clock.Now()
+	c.lru.MoveToFront(e.element)
+	if err := os.Chtimes(c.entryPath(e.Key), e.lastUsed, e.lastUsed); err != nil {
+		log.Debug().Err(err).Msgf("failed to record last use of cached input %s", e.Key)
+	}
+}
+
+// evict removes the least recently used entries that are not in use until size bytes fit in the cache,
+// and returns false if they cannot fit. It must be called with the lock held.
+func (c *InputCache) evict(size uint64) bool {
+	element := c.lru.Back()
+	for c.size+size > c.maxSize && element != nil {
+		previous := element.Prev()
+		if e := c.entries[element.Value.(string)]; e.pins == 0 {
+			log.Debug().Msgf("evicting input %s of %d bytes from the input cache", e.Key, e.Size)
+			c.remove(e)
+		}
+		element = previous
+	}
+	return c.size+size <= c.maxSize
+}
+
+// insert adds an entry to the index. It must be called with the lock held.
+func (c *InputCache) insert(e *entry, lastUsed time.Time) {
+	e.lastUsed = lastUsed
+	// entries are loaded in any order, so they are inserted after the ones used more recently
+	element := c.lru.Front()
+	for element != nil && c.entries[element.Value.(string)].lastUsed.After(lastUsed) {
+		element = element.Next()
+	}
+	if element == nil {
+		e.element = c.lru.PushBack(e.Key)
+	} else {
+		e.element = c.lru.InsertBefore(e.Key, element)
+	}
+	c.entries[e.Key] = e
+	c.size += e.Size
+}
+
+// remove deletes an entry from the index and the disk. It must be called with the lock held.
+func (c *InputCache) remove(e *entry) {
+	c.lru.Remove(e.element)
+	delete(c.entries, e.Key)
+	c.size -= e.Size
+	if err := os.RemoveAll(c.entryPath(e.Key)); err != nil {
+		log.Warn().Err(err).Msgf("failed to remove cached input %s", e.Key)
+	}
+}
+
+// load indexes the entries found in the cache directory, and removes the ones that are incomplete or no
+// longer fit in the cache.
+func (c *InputCache) load() error {
+	dirEntries, err := os.ReadDir(c.path)
+	if err != nil {
+		return fmt.Errorf("failed to read input cache directory %s: %w", c.path, err)
+	}
+	for _, dirEntry := range dirEntries {
+		path := filepath.Join(c.path, dirEntry.Name())
+		if !dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), stagingDirPrefix) {
+			_ = os.RemoveAll(path)
+			continue
+		}
+		e, lastUsed, err := readEntry(path)
+		if err != nil || e.Key != dirEntry.Name() {
+			log.Debug().Err(err).Msgf("removing invalid input cache entry %s", path)
+			_ = os.RemoveAll(path)
+			continue
+		}
+		c.insert(e, lastUsed)
+	}
+	c.evict(0)
+	return nil
+}
+
+func (c *InputCache) entryPath(key string) string {
+	return filepath.Join(c.path, key)
+}
+
+func readEntry(path string) (*entry, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	metadata, err := os.ReadFile(filepath.Join(path, entryMetadataFile))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	e := new(entry)
+	if err = json.Unmarshal(metadata, e); err != nil {
+		return nil, time.Time{}, err
+	}
+	if _, err = os.Stat(filepath.Join(path, entryDataDir, e.Name)); err != nil {
+		return nil, time.Time{}, err
+	}
+	return e, info.ModTime(), nil
+}
+
+// relativeTarget returns the path of a volume's target relative to the target of its input source
+func relativeTarget(inputTarget string, volumeTarget string) (string, bool) {
+	suffix, err := filepath.Rel(inputTarget, volumeTarget)
+	if err != nil || suffix == ".." || strings.HasPrefix(suffix, "../") {
+		return "", false
+	}
+	return suffix, true
+}
+
+// pathSize returns the total size of the files of a file or directory
+func pathSize(path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
+
+// linkPath hard links the files of a file or directory to destination, recreating its directories.
+// Files that cannot be linked, such as across file systems, are copied instead.
+func linkPath(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destination, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, cacheDirPerm)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			if err = os.Link(path, target); err != nil {
+				return filecopy.CopyFile(path, target)
+			}
+			return nil
+		}
+	})
+}
+
+// copyPath copies a file or directory
+func copyPath(source string, destination string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return filecopy.CopyDir(source, destination)
+	}
+	return filecopy.CopyFile(source, destination)
+}
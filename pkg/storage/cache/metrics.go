@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	meter = otel.GetMeterProvider().Meter("storage")
+
+	inputCacheHits = lo.Must(meter.Int64Counter(
+		"job_storage_input_cache_hits",
+		metric.WithDescription("Number of job storage inputs prepared from the input cache of the compute node."),
+	))
+
+	inputCacheMisses = lo.Must(meter.Int64Counter(
+		"job_storage_input_cache_misses",
+		metric.WithDescription("Number of job storage inputs the compute node had to fetch as they were not in its input cache."),
+	))
+)
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+)
+
+// cachingStorage prepares input sources from the input cache when they are cached at the version their
+// source currently points to, and caches the ones it had to prepare with its delegate. Inputs whose version
+// the delegate cannot tell are never cached.
+type cachingStorage struct {
+	delegate storage.Storage
+	cache    *InputCache
+}
+
+// Wrap returns a storage that caches the inputs prepared by the delegate storage in the input cache
+func Wrap(delegate storage.Storage, cache *InputCache) storage.Storage {
+	return &cachingStorage{
+		delegate: delegate,
+		cache:    cache,
+	}
+}
+
+func (s *cachingStorage) IsInstalled(ctx context.Context) (bool, error) {
+	return s.delegate.IsInstalled(ctx)
+}
+
+// HasStorageLocally returns true if the input is cached, or if the delegate storage has it locally.
+// The cached input is only revalidated once it is prepared.
+func (s *cachingStorage) HasStorageLocally(ctx context.Context, spec models.InputSource) (bool, error) {
+	if key, err := spec.CacheKey(); err == nil && s.cache.Has(key) {
+		return true, nil
+	}
+	return s.delegate.HasStorageLocally(ctx, spec)
+}
+
+func (s *cachingStorage) GetVolumeSize(ctx context.Context, spec models.InputSource) (uint64, error) {
+	if key, err := spec.CacheKey(); err == nil {
+		if size, ok := s.cache.Size(key); ok {
+			return size, nil
+		}
+	}
+	return s.delegate.GetVolumeSize(ctx, spec)
+}
+
+func (s *cachingStorage) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	spec models.InputSource) (storage.StorageVolume, error) {
+	key, err := spec.CacheKey()
+	if err != nil {
+		return s.delegate.PrepareStorage(ctx, storageDirectory, spec)
+	}
+	// the cached data is revalidated on every use against the version the source currently points to
+	version, err := storage.Version(ctx, s.delegate, spec)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msgf("not caching input %s whose version is unknown", key)
+		return s.delegate.PrepareStorage(ctx, storageDirectory, spec)
+	}
+
+	volume, hit, err := s.cache.prepare(key, version, storageDirectory, spec.Target)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to prepare input %s from the input cache", key)
+	} else if hit {
+		inputCacheHits.Add(ctx, 1, metric.WithAttributes(spec.Source.MetricAttributes()...))
+		log.Ctx(ctx).Debug().Str("Key", key).Str("Alias", spec.Alias).Msg("input prepared from the input cache")
+		return volume, nil
+	}
+	inputCacheMisses.Add(ctx, 1, metric.WithAttributes(spec.Source.MetricAttributes()...))
+
+	volume, err = s.delegate.PrepareStorage(ctx, storageDirectory, spec)
+	if err != nil {
+		return volume, err
+	}
+	// failing to cache an input does not fail its execution, which already has its data
+	if err = s.cache.add(key, version, spec.Target, volume); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to add input %s to the input cache", key)
+	}
+	return volume, nil
+}
+
+func (s *cachingStorage) CleanupStorage(ctx context.Context, spec models.InputSource, volume storage.StorageVolume) error {
+	released, err := s.cache.release(volume)
+	if released {
+		return err
+	}
+	return s.delegate.CleanupStorage(ctx, spec, volume)
+}
+
+func (s *cachingStorage) Upload(ctx context.Context, path string) (models.SpecConfig, error) {
+	return s.delegate.Upload(ctx, path)
+}
+
+// Provider is a storage provider that wraps the storages of a delegate provider for the cached source
+// types with the input cache.
+type Provider struct {
+	storage.StorageProvider
+	cache   *InputCache
+	sources []string
+}
+
+// NewProvider returns a provider of the storages of delegate, where the storages of the given source types
+// cache their inputs in the input cache.
+func NewProvider(delegate storage.StorageProvider, cache *InputCache, sources []string) *Provider {
+	return &Provider{
+		StorageProvider: delegate,
+		cache:           cache,
+		sources:         sources,
+	}
+}
+
+func (p *Provider) Get(ctx context.Context, key string) (storage.Storage, error) {
+	s, err := p.StorageProvider.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range p.sources {
+		if strings.EqualFold(source, key) {
+			return Wrap(s, p.cache), nil
+		}
+	}
+	return s, nil
+}
+
+// compile-time interface checks
+var _ storage.Storage = (*cachingStorage)(nil)
+var _ storage.StorageProvider = (*Provider)(nil)
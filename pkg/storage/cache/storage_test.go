@@ -0,0 +1,279 @@
+//go:build unit || !integration
+
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/noop"
+)
+
+type CachingStorageSuite struct {
+	suite.Suite
+	ctx        context.Context
+	clock      *clock.Mock
+	cacheDir   string
+	cache      *InputCache
+	delegate   *versionedStorage
+	storage    storage.Storage
+	downloads  int
+	cleanedUp  int
+	contents   map[string]string
+	versions   map[string]string
+	storageDir string
+}
+
+// versionedStorage is a storage that reports the versions of its sources
+type versionedStorage struct {
+	storage.Storage
+	versions map[string]string
+}
+
+func (s *versionedStorage) Version(_ context.Context, spec models.InputSource) (string, error) {
+	return s.versions[spec.Source.Params["URL"].(string)], nil
+}
+
+func TestCachingStorageSuite(t *testing.T) {
+	suite.Run(t, new(CachingStorageSuite))
+}
+
+func (s *CachingStorageSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.cacheDir = s.T().TempDir()
+	s.storageDir = s.T().TempDir()
+	s.downloads = 0
+	s.cleanedUp = 0
+	s.contents = map[string]string{
+		"https://example.com/a.csv": "aaaa",
+		"https://example.com/b.csv": "bbbbbb",
+		"https://example.com/c.csv": "cccccccc",
+	}
+	s.versions = map[string]string{
+		"https://example.com/a.csv": "v1",
+		"https://example.com/b.csv": "v1",
+		"https://example.com/c.csv": "v1",
+	}
+
+	// the delegate downloads the content of the URL to a file named after it, like the URL storage
+	s.delegate = &versionedStorage{versions: s.versions}
+	s.delegate.Storage = noop.NewNoopStorageWithConfig(noop.StorageConfig{
+		ExternalHooks: noop.StorageConfigExternalHooks{
+			HasStorageLocally: func(context.Context, models.InputSource) (bool, error) {
+				return false, nil
+			},
+			GetVolumeSize: func(context.Context, models.InputSource) (uint64, error) {
+				return 0, nil
+			},
+			PrepareStorage: func(_ context.Context, dir string, spec models.InputSource) (storage.StorageVolume, error) {
+				s.downloads++
+				url := spec.Source.Params["URL"].(string)
+				outputDir, err := os.MkdirTemp(dir, "*")
+				if err != nil {
+					return storage.StorageVolume{}, err
+				}
+				file := filepath.Join(outputDir, filepath.Base(url))
+				if err = os.WriteFile(file, []byte(s.contents[url]), 0644); err != nil {
+					return storage.StorageVolume{}, err
+				}
+				return storage.StorageVolume{
+					Type:   storage.StorageVolumeConnectorBind,
+					Source: file,
+					Target: filepath.Join(spec.Target, filepath.Base(url)),
+				}, nil
+			},
+			CleanupStorage: func(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+				s.cleanedUp++
+				return os.RemoveAll(filepath.Dir(volume.Source))
+			},
+		},
+	})
+	s.newCache(10)
+}
+
+func (s *CachingStorageSuite) newCache(maxSize uint64) {
+	var err error
+	s.cache, err = NewInputCache(InputCacheParams{
+		Path:    s.cacheDir,
+		MaxSize: maxSize,
+		TTL:     time.Hour,
+		Clock:   s.clock,
+	})
+	s.Require().NoError(err)
+	s.storage = Wrap(s.delegate, s.cache)
+}
+
+func urlInput(url string, target string) models.InputSource {
+	return models.InputSource{
+		Source: &models.SpecConfig{Type: models.StorageSourceURL, Params: map[string]interface{}{"URL": url}},
+		Target: target,
+	}
+}
+
+func (s *CachingStorageSuite) prepare(input models.InputSource) storage.StorageVolume {
+	volume, err := s.storage.PrepareStorage(s.ctx, s.storageDir, input)
+	s.Require().NoError(err)
+	content, err := os.ReadFile(volume.Source)
+	s.Require().NoError(err)
+	s.Equal(s.contents[input.Source.Params["URL"].(string)], string(content))
+	return volume
+}
+
+func (s *CachingStorageSuite) TestPrepareFromCache() {
+	input := urlInput("https://example.com/a.csv", "/inputs")
+	local, err := s.storage.HasStorageLocally(s.ctx, input)
+	s.Require().NoError(err)
+	s.False(local)
+
+	first := s.prepare(input)
+	s.Equal(1, s.downloads)
+	s.Require().NoError(s.storage.CleanupStorage(s.ctx, input, first))
+	s.Equal(1, s.cleanedUp)
+
+	local, err = s.storage.HasStorageLocally(s.ctx, input)
+	s.Require().NoError(err)
+	s.True(local)
+	size, err := s.storage.GetVolumeSize(s.ctx, input)
+	s.Require().NoError(err)
+	s.Equal(uint64(4), size)
+
+	// the same source mounted elsewhere is prepared from the cache, with the file name of the download
+	second := s.prepare(urlInput("https://example.com/a.csv", "/data"))
+	s.Equal(1, s.downloads)
+	s.Equal("/data/a.csv", second.Target)
+	s.NotEqual(first.Source, second.Source)
+
+	// volumes prepared from the cache are read-only links to the cached data
+	s.True(second.ReadOnly)
+	s.True(sameFile(second.Source, filepath.Join(s.cacheDir, mustKey(input), entryDataDir, "a.csv")))
+
+	// volumes prepared from the cache are removed without reaching the delegate
+	s.Require().NoError(s.storage.CleanupStorage(s.ctx, input, second))
+	s.Equal(1, s.cleanedUp)
+	s.NoFileExists(second.Source)
+	s.True(s.cache.Has(mustKey(input)))
+}
+
+func (s *CachingStorageSuite) TestRevalidateChangedSource() {
+	input := urlInput("https://example.com/a.csv", "/inputs")
+	s.prepare(input)
+
+	// the source now points to new data, which is downloaded again and replaces the cached data
+	s.contents["https://example.com/a.csv"] = "AAAA"
+	s.versions["https://example.com/a.csv"] = "v2"
+	s.prepare(input)
+	s.Equal(2, s.downloads)
+	s.prepare(input)
+	s.Equal(2, s.downloads)
+	s.Equal(uint64(4), s.cache.TotalSize())
+}
+
+func (s *CachingStorageSuite) TestUnversionedSourceIsNotCached() {
+	s.storage = Wrap(s.delegate.Storage, s.cache)
+	input := urlInput("https://example.com/a.csv", "/inputs")
+	s.prepare(input)
+	s.prepare(input)
+	s.Equal(2, s.downloads)
+	s.Empty(s.cache.Keys(0))
+}
+
+func (s *CachingStorageSuite) TestEvictLeastRecentlyUsed() {
+	a := urlInput("https://example.com/a.csv", "/inputs")
+	b := urlInput("https://example.com/b.csv", "/inputs")
+	c := urlInput("https://example.com/c.csv", "/inputs")
+	s.newCache(12)
+
+	s.prepare(a)
+	s.clock.Add(time.Minute)
+	s.prepare(b)
+	s.Equal(uint64(10), s.cache.TotalSize())
+
+	// using a makes b the least recently used input, which is evicted to make room for c
+	s.clock.Add(time.Minute)
+	s.prepare(a)
+	s.clock.Add(time.Minute)
+	s.prepare(c)
+	s.Equal(3, s.downloads)
+	s.True(s.cache.Has(mustKey(a)))
+	s.False(s.cache.Has(mustKey(b)))
+	s.True(s.cache.Has(mustKey(c)))
+	s.Equal([]string{mustKey(c), mustKey(a)}, s.cache.Keys(0))
+	s.Equal([]string{mustKey(c)}, s.cache.Keys(1))
+}
+
+func (s *CachingStorageSuite) TestInputLargerThanCache() {
+	s.newCache(5)
+	input := urlInput("https://example.com/b.csv", "/inputs")
+	s.prepare(input)
+	s.prepare(input)
+	s.Equal(2, s.downloads)
+	s.Empty(s.cache.Keys(0))
+}
+
+func (s *CachingStorageSuite) TestExpiredInput() {
+	input := urlInput("https://example.com/a.csv", "/inputs")
+	s.prepare(input)
+	s.clock.Add(2 * time.Hour)
+	s.False(s.cache.Has(mustKey(input)))
+	s.prepare(input)
+	s.Equal(2, s.downloads)
+}
+
+func (s *CachingStorageSuite) TestReloadCache() {
+	a := urlInput("https://example.com/a.csv", "/inputs")
+	b := urlInput("https://example.com/b.csv", "/inputs")
+	s.prepare(a)
+	s.prepare(b)
+
+	// inputs cached by a previous run are loaded, and evicted if they no longer fit
+	s.newCache(8)
+	s.Equal(1, len(s.cache.Keys(0)))
+	s.LessOrEqual(s.cache.TotalSize(), uint64(8))
+
+	s.newCache(10)
+	s.Equal(1, len(s.cache.Keys(0)))
+	for _, key := range s.cache.Keys(0) {
+		s.DirExists(filepath.Join(s.cacheDir, key))
+	}
+}
+
+func (s *CachingStorageSuite) TestProviderWrapsCachedSources() {
+	delegate := provider.NewMappedProvider(map[string]storage.Storage{
+		models.StorageSourceURL:    s.delegate,
+		models.StorageSourceInline: s.delegate,
+	})
+	p := NewProvider(delegate, s.cache, []string{"urldownload"})
+
+	urlStorage, err := p.Get(s.ctx, models.StorageSourceURL)
+	s.Require().NoError(err)
+	s.IsType(&cachingStorage{}, urlStorage)
+
+	inlineStorage, err := p.Get(s.ctx, models.StorageSourceInline)
+	s.Require().NoError(err)
+	s.Equal(s.delegate, inlineStorage)
+	s.ElementsMatch(delegate.Keys(s.ctx), p.Keys(s.ctx))
+}
+
+func sameFile(a string, b string) bool {
+	aInfo, aErr := os.Stat(a)
+	bInfo, bErr := os.Stat(b)
+	return aErr == nil && bErr == nil && os.SameFile(aInfo, bInfo)
+}
+
+func mustKey(input models.InputSource) string {
+	key, err := input.CacheKey()
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
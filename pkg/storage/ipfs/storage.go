@@ -74,6 +74,15 @@ func (s *StorageProvider) GetVolumeSize(ctx context.Context, volume models.Input
 	return size, nil
 }
 
+// Version returns the CID of the input source, as IPFS content is addressed by its hash and never changes
+func (s *StorageProvider) Version(_ context.Context, volume models.InputSource) (string, error) {
+	source, err := DecodeSpec(volume.Source)
+	if err != nil {
+		return "", err
+	}
+	return source.CID, nil
+}
+
 func (s *StorageProvider) PrepareStorage(
 	ctx context.Context,
 	storageDirectory string,
@@ -155,3 +164,4 @@ func (s *StorageProvider) getFileFromIPFS(ctx context.Context, cid, storageDirec
 
 // Compile time interface check:
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.VersionedStorage = (*StorageProvider)(nil)
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/plugins/grpc/proto"
+)
+
+var _ storage.Storage = (*GRPCClient)(nil)
+
+// GRPCClient is the storage used by the node, which calls the storage served by a plugin
+type GRPCClient struct {
+	client proto.StorageClient
+}
+
+func (c *GRPCClient) IsInstalled(ctx context.Context) (bool, error) {
+	resp, err := c.client.IsInstalled(ctx, &proto.IsInstalledRequest{})
+	if err != nil {
+		return false, err
+	}
+	return resp.Installed, nil
+}
+
+func (c *GRPCClient) HasStorageLocally(ctx context.Context, spec models.InputSource) (bool, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.client.HasStorageLocally(ctx, &proto.HasStorageLocallyRequest{InputSource: b})
+	if err != nil {
+		return false, err
+	}
+	return resp.HasStorage, nil
+}
+
+func (c *GRPCClient) GetVolumeSize(ctx context.Context, spec models.InputSource) (uint64, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.GetVolumeSize(ctx, &proto.GetVolumeSizeRequest{InputSource: b})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Size, nil
+}
+
+func (c *GRPCClient) PrepareStorage(
+	ctx context.Context, storageDirectory string, spec models.InputSource) (storage.StorageVolume, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	resp, err := c.client.PrepareStorage(ctx, &proto.PrepareStorageRequest{
+		StorageDirectory: storageDirectory,
+		InputSource:      b,
+	})
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	var out storage.StorageVolume
+	if err = json.Unmarshal(resp.StorageVolume, &out); err != nil {
+		return storage.StorageVolume{}, err
+	}
+	return out, nil
+}
+
+func (c *GRPCClient) CleanupStorage(ctx context.Context, spec models.InputSource, volume storage.StorageVolume) error {
+	specBytes, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	volumeBytes, err := json.Marshal(volume)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.CleanupStorage(ctx, &proto.CleanupStorageRequest{
+		InputSource:   specBytes,
+		StorageVolume: volumeBytes,
+	})
+	return err
+}
+
+func (c *GRPCClient) Upload(ctx context.Context, localPath string) (models.SpecConfig, error) {
+	resp, err := c.client.Upload(ctx, &proto.UploadRequest{LocalPath: localPath})
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	var out models.SpecConfig
+	if err = json.Unmarshal(resp.SpecConfig, &out); err != nil {
+		return models.SpecConfig{}, err
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+//go:build unit || !integration
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/noop"
+)
+
+type StorageGRPCTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	client  storage.Storage
+	cleaned []storage.StorageVolume
+	spec    models.InputSource
+}
+
+func TestStorageGRPCTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageGRPCTestSuite))
+}
+
+func (s *StorageGRPCTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.cleaned = nil
+	s.spec = models.InputSource{
+		Source: &models.SpecConfig{
+			Type:   "custom",
+			Params: map[string]interface{}{"Key": "data"},
+		},
+		Target: "/inputs",
+	}
+	impl := noop.NewNoopStorageWithConfig(noop.StorageConfig{
+		ExternalHooks: noop.StorageConfigExternalHooks{
+			HasStorageLocally: func(_ context.Context, spec models.InputSource) (bool, error) {
+				return spec.Source.Params["Key"] == "data", nil
+			},
+			GetVolumeSize: func(context.Context, models.InputSource) (uint64, error) {
+				return 42, nil
+			},
+			PrepareStorage: func(_ context.Context, storageDir string, spec models.InputSource) (storage.StorageVolume, error) {
+				return storage.StorageVolume{
+					Type:   storage.StorageVolumeConnectorBind,
+					Source: filepath.Join(storageDir, spec.Source.Params["Key"].(string)),
+					Target: spec.Target,
+				}, nil
+			},
+			CleanupStorage: func(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+				s.cleaned = append(s.cleaned, volume)
+				return nil
+			},
+			Upload: func(context.Context, string) (models.SpecConfig, error) {
+				return models.SpecConfig{}, errors.New("upload not supported")
+			},
+		},
+	})
+
+	client, server := plugin.TestPluginGRPCConn(s.T(), false, map[string]plugin.Plugin{
+		PluggableStoragePluginName: &StorageGRPCPlugin{Impl: impl},
+	})
+	s.T().Cleanup(func() {
+		_ = client.Close()
+		server.Stop()
+	})
+	raw, err := client.Dispense(PluggableStoragePluginName)
+	s.Require().NoError(err)
+	s.client = raw.(storage.Storage)
+}
+
+func (s *StorageGRPCTestSuite) TestIsInstalled() {
+	installed, err := s.client.IsInstalled(s.ctx)
+	s.Require().NoError(err)
+	s.True(installed)
+}
+
+func (s *StorageGRPCTestSuite) TestHasStorageLocally() {
+	hasStorage, err := s.client.HasStorageLocally(s.ctx, s.spec)
+	s.Require().NoError(err)
+	s.True(hasStorage)
+}
+
+func (s *StorageGRPCTestSuite) TestGetVolumeSize() {
+	size, err := s.client.GetVolumeSize(s.ctx, s.spec)
+	s.Require().NoError(err)
+	s.Equal(uint64(42), size)
+}
+
+func (s *StorageGRPCTestSuite) TestPrepareAndCleanupStorage() {
+	volume, err := s.client.PrepareStorage(s.ctx, "/storage", s.spec)
+	s.Require().NoError(err)
+	s.Equal(storage.StorageVolume{
+		Type:   storage.StorageVolumeConnectorBind,
+		Source: "/storage/data",
+		Target: "/inputs",
+	}, volume)
+
+	s.Require().NoError(s.client.CleanupStorage(s.ctx, s.spec, volume))
+	s.Equal([]storage.StorageVolume{volume}, s.cleaned)
+}
+
+func (s *StorageGRPCTestSuite) TestUpload() {
+	_, err := s.client.Upload(s.ctx, "/outputs")
+	s.ErrorContains(err, "upload not supported")
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/plugins/grpc/proto"
+)
+
+// PluggableStoragePluginName is the name under which storage plugins serve their storage
+const PluggableStoragePluginName = "PLUGGABLE_STORAGE"
+
+// PluginBinaryPrefix is the prefix of the names of storage plugin binaries in the plugin path.
+// The rest of the binary name is the storage source type the plugin provides.
+const PluginBinaryPrefix = "bacalhau-storage-"
+
+// HandshakeConfig is the handshake between the node and storage plugins.
+// It is a UX feature to avoid running binaries that are not storage plugins, not a security feature.
+var HandshakeConfig = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "STORAGE_PLUGIN",
+	MagicCookieValue: "bacalhau_storage",
+}
+
+type StorageGRPCPlugin struct {
+	plugin.Plugin
+	Impl storage.Storage
+}
+
+func (p *StorageGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterStorageServer(s, &GRPCServer{Impl: p.Impl})
+	return nil
+}
+
+func (p *StorageGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &GRPCClient{client: proto.NewStorageClient(c)}, nil
+}
@@ -0,0 +1,294 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: storage.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Storage_IsInstalled_FullMethodName       = "/storage.Storage/IsInstalled"
+	Storage_HasStorageLocally_FullMethodName = "/storage.Storage/HasStorageLocally"
+	Storage_GetVolumeSize_FullMethodName     = "/storage.Storage/GetVolumeSize"
+	Storage_PrepareStorage_FullMethodName    = "/storage.Storage/PrepareStorage"
+	Storage_CleanupStorage_FullMethodName    = "/storage.Storage/CleanupStorage"
+	Storage_Upload_FullMethodName            = "/storage.Storage/Upload"
+)
+
+// StorageClient is the client API for Storage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StorageClient interface {
+	IsInstalled(ctx context.Context, in *IsInstalledRequest, opts ...grpc.CallOption) (*IsInstalledResponse, error)
+	HasStorageLocally(ctx context.Context, in *HasStorageLocallyRequest, opts ...grpc.CallOption) (*HasStorageLocallyResponse, error)
+	GetVolumeSize(ctx context.Context, in *GetVolumeSizeRequest, opts ...grpc.CallOption) (*GetVolumeSizeResponse, error)
+	PrepareStorage(ctx context.Context, in *PrepareStorageRequest, opts ...grpc.CallOption) (*PrepareStorageResponse, error)
+	CleanupStorage(ctx context.Context, in *CleanupStorageRequest, opts ...grpc.CallOption) (*CleanupStorageResponse, error)
+	Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*UploadResponse, error)
+}
+
+type storageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageClient(cc grpc.ClientConnInterface) StorageClient {
+	return &storageClient{cc}
+}
+
+func (c *storageClient) IsInstalled(ctx context.Context, in *IsInstalledRequest, opts ...grpc.CallOption) (*IsInstalledResponse, error) {
+	out := new(IsInstalledResponse)
+	err := c.cc.Invoke(ctx, Storage_IsInstalled_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) HasStorageLocally(ctx context.Context, in *HasStorageLocallyRequest, opts ...grpc.CallOption) (*HasStorageLocallyResponse, error) {
+	out := new(HasStorageLocallyResponse)
+	err := c.cc.Invoke(ctx, Storage_HasStorageLocally_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetVolumeSize(ctx context.Context, in *GetVolumeSizeRequest, opts ...grpc.CallOption) (*GetVolumeSizeResponse, error) {
+	out := new(GetVolumeSizeResponse)
+	err := c.cc.Invoke(ctx, Storage_GetVolumeSize_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) PrepareStorage(ctx context.Context, in *PrepareStorageRequest, opts ...grpc.CallOption) (*PrepareStorageResponse, error) {
+	out := new(PrepareStorageResponse)
+	err := c.cc.Invoke(ctx, Storage_PrepareStorage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CleanupStorage(ctx context.Context, in *CleanupStorageRequest, opts ...grpc.CallOption) (*CleanupStorageResponse, error) {
+	out := new(CleanupStorageResponse)
+	err := c.cc.Invoke(ctx, Storage_CleanupStorage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Upload(ctx context.Context, in *UploadRequest, opts ...grpc.CallOption) (*UploadResponse, error) {
+	out := new(UploadResponse)
+	err := c.cc.Invoke(ctx, Storage_Upload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageServer is the server API for Storage service.
+// All implementations must embed UnimplementedStorageServer
+// for forward compatibility
+type StorageServer interface {
+	IsInstalled(context.Context, *IsInstalledRequest) (*IsInstalledResponse, error)
+	HasStorageLocally(context.Context, *HasStorageLocallyRequest) (*HasStorageLocallyResponse, error)
+	GetVolumeSize(context.Context, *GetVolumeSizeRequest) (*GetVolumeSizeResponse, error)
+	PrepareStorage(context.Context, *PrepareStorageRequest) (*PrepareStorageResponse, error)
+	CleanupStorage(context.Context, *CleanupStorageRequest) (*CleanupStorageResponse, error)
+	Upload(context.Context, *UploadRequest) (*UploadResponse, error)
+	mustEmbedUnimplementedStorageServer()
+}
+
+// UnimplementedStorageServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageServer struct {
+}
+
+func (UnimplementedStorageServer) IsInstalled(context.Context, *IsInstalledRequest) (*IsInstalledResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsInstalled not implemented")
+}
+func (UnimplementedStorageServer) HasStorageLocally(context.Context, *HasStorageLocallyRequest) (*HasStorageLocallyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HasStorageLocally not implemented")
+}
+func (UnimplementedStorageServer) GetVolumeSize(context.Context, *GetVolumeSizeRequest) (*GetVolumeSizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVolumeSize not implemented")
+}
+func (UnimplementedStorageServer) PrepareStorage(context.Context, *PrepareStorageRequest) (*PrepareStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareStorage not implemented")
+}
+func (UnimplementedStorageServer) CleanupStorage(context.Context, *CleanupStorageRequest) (*CleanupStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CleanupStorage not implemented")
+}
+func (UnimplementedStorageServer) Upload(context.Context, *UploadRequest) (*UploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
+
+// UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageServer will
+// result in compilation errors.
+type UnsafeStorageServer interface {
+	mustEmbedUnimplementedStorageServer()
+}
+
+func RegisterStorageServer(s grpc.ServiceRegistrar, srv StorageServer) {
+	s.RegisterService(&Storage_ServiceDesc, srv)
+}
+
+func _Storage_IsInstalled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsInstalledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).IsInstalled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_IsInstalled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).IsInstalled(ctx, req.(*IsInstalledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_HasStorageLocally_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasStorageLocallyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).HasStorageLocally(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_HasStorageLocally_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).HasStorageLocally(ctx, req.(*HasStorageLocallyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetVolumeSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVolumeSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetVolumeSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetVolumeSize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetVolumeSize(ctx, req.(*GetVolumeSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_PrepareStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).PrepareStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_PrepareStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).PrepareStorage(ctx, req.(*PrepareStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CleanupStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CleanupStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CleanupStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_CleanupStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CleanupStorage(ctx, req.(*CleanupStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Upload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Upload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Upload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Upload(ctx, req.(*UploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Storage_ServiceDesc is the grpc.ServiceDesc for Storage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Storage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "storage.Storage",
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IsInstalled",
+			Handler:    _Storage_IsInstalled_Handler,
+		},
+		{
+			MethodName: "HasStorageLocally",
+			Handler:    _Storage_HasStorageLocally_Handler,
+		},
+		{
+			MethodName: "GetVolumeSize",
+			Handler:    _Storage_GetVolumeSize_Handler,
+		},
+		{
+			MethodName: "PrepareStorage",
+			Handler:    _Storage_PrepareStorage_Handler,
+		},
+		{
+			MethodName: "CleanupStorage",
+			Handler:    _Storage_CleanupStorage_Handler,
+		},
+		{
+			MethodName: "Upload",
+			Handler:    _Storage_Upload_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storage.proto",
+}
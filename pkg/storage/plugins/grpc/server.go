@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/plugins/grpc/proto"
+)
+
+// GRPCServer serves the storage of a plugin to the node
+type GRPCServer struct {
+	Impl storage.Storage
+
+	proto.UnimplementedStorageServer
+}
+
+func (s *GRPCServer) IsInstalled(ctx context.Context, _ *proto.IsInstalledRequest) (*proto.IsInstalledResponse, error) {
+	installed, err := s.Impl.IsInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.IsInstalledResponse{Installed: installed}, nil
+}
+
+func (s *GRPCServer) HasStorageLocally(
+	ctx context.Context, request *proto.HasStorageLocallyRequest) (*proto.HasStorageLocallyResponse, error) {
+	var spec models.InputSource
+	if err := json.Unmarshal(request.InputSource, &spec); err != nil {
+		return nil, err
+	}
+	hasStorage, err := s.Impl.HasStorageLocally(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.HasStorageLocallyResponse{HasStorage: hasStorage}, nil
+}
+
+func (s *GRPCServer) GetVolumeSize(ctx context.Context, request *proto.GetVolumeSizeRequest) (*proto.GetVolumeSizeResponse, error) {
+	var spec models.InputSource
+	if err := json.Unmarshal(request.InputSource, &spec); err != nil {
+		return nil, err
+	}
+	size, err := s.Impl.GetVolumeSize(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.GetVolumeSizeResponse{Size: size}, nil
+}
+
+func (s *GRPCServer) PrepareStorage(ctx context.Context, request *proto.PrepareStorageRequest) (*proto.PrepareStorageResponse, error) {
+	var spec models.InputSource
+	if err := json.Unmarshal(request.InputSource, &spec); err != nil {
+		return nil, err
+	}
+	volume, err := s.Impl.PrepareStorage(ctx, request.StorageDirectory, spec)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(volume)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PrepareStorageResponse{StorageVolume: b}, nil
+}
+
+func (s *GRPCServer) CleanupStorage(ctx context.Context, request *proto.CleanupStorageRequest) (*proto.CleanupStorageResponse, error) {
+	var spec models.InputSource
+	if err := json.Unmarshal(request.InputSource, &spec); err != nil {
+		return nil, err
+	}
+	var volume storage.StorageVolume
+	if err := json.Unmarshal(request.StorageVolume, &volume); err != nil {
+		return nil, err
+	}
+	if err := s.Impl.CleanupStorage(ctx, spec, volume); err != nil {
+		return nil, err
+	}
+	return &proto.CleanupStorageResponse{}, nil
+}
+
+func (s *GRPCServer) Upload(ctx context.Context, request *proto.UploadRequest) (*proto.UploadResponse, error) {
+	result, err := s.Impl.Upload(ctx, request.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.UploadResponse{SpecConfig: b}, nil
+}
@@ -2,6 +2,8 @@ package s3
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -28,6 +30,7 @@ The storage provider supports downloading:
 - a single object: s3://myBucket/dir/file-001.txt
 - a directory and all its content: s3://myBucket/dir/
 - a prefix and all objects matching the prefix: s3://myBucket/dir/file-*
+- the objects listed in the manifest of a result published as individual objects
 */
 
 type s3ObjectSummary struct {
@@ -88,6 +91,30 @@ func (s *StorageProvider) GetVolumeSize(ctx context.Context, volume models.Input
 	return size, nil
 }
 
+// Version returns a hash of the keys, version IDs and ETags of the objects the input source currently
+// points to, which changes whenever any of the objects is added, removed or overwritten.
+func (s *StorageProvider) Version(ctx context.Context, volume models.InputSource) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.GetVolumeSizeRequestTimeout())
+	defer cancel()
+
+	source, err := s3helper.DecodeSourceSpec(volume.Source)
+	if err != nil {
+		return "", err
+	}
+
+	client := s.clientProvider.GetClient(source.Endpoint, source.Region)
+	objects, err := s.explodeKey(ctx, client, source)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, object := range objects {
+		_, _ = fmt.Fprintf(hash, "%s %s %s %d\n",
+			aws.ToString(object.key), aws.ToString(object.versionID), aws.ToString(object.eTag), object.size)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func (s *StorageProvider) PrepareStorage(
 	ctx context.Context,
 	storageDirectory string,
@@ -200,6 +227,9 @@ func (s *StorageProvider) Upload(_ context.Context, _ string) (models.SpecConfig
 //nolint:gocyclo
 func (s *StorageProvider) explodeKey(
 	ctx context.Context, client *s3helper.ClientWrapper, storageSpec s3helper.SourceSpec) ([]s3ObjectSummary, error) {
+	if storageSpec.Manifest != "" {
+		return s.explodeManifest(ctx, client, storageSpec)
+	}
 	if storageSpec.Key != "" && !strings.HasSuffix(storageSpec.Key, "*") && !strings.HasSuffix(storageSpec.Key, "/") {
 		request := &s3.HeadObjectInput{
 			Bucket: aws.String(storageSpec.Bucket),
@@ -262,6 +292,7 @@ func (s *StorageProvider) explodeKey(
 			}
 			res = append(res, s3ObjectSummary{
 				key:   object.Key,
+				eTag:  object.ETag,
 				size:  *object.Size,
 				isDir: strings.HasSuffix(*object.Key, "/"),
 			})
@@ -274,6 +305,39 @@ func (s *StorageProvider) explodeKey(
 	return res, nil
 }
 
+// explodeManifest returns the objects listed in the manifest of a result published as individual objects,
+// which are only downloaded if they were not overwritten since.
+func (s *StorageProvider) explodeManifest(
+	ctx context.Context, client *s3helper.ClientWrapper, storageSpec s3helper.SourceSpec) ([]s3ObjectSummary, error) {
+	regex, err := regexp.Compile(storageSpec.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+	manifest, err := s3helper.ReadManifest(ctx, client.S3, storageSpec.Bucket, storageSpec.Manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]s3ObjectSummary, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		if storageSpec.Filter != "" && !regex.MatchString(file.Path) {
+			continue
+		}
+		object := s3ObjectSummary{
+			key:  aws.String(file.ObjectKey(storageSpec.Key)),
+			size: file.Size,
+		}
+		if file.ETag != "" {
+			object.eTag = aws.String(file.ETag)
+		}
+		if file.VersionID != "" {
+			object.versionID = aws.String(file.VersionID)
+		}
+		res = append(res, object)
+	}
+	return res, nil
+}
+
 func (s *StorageProvider) sanitizeKey(key string) string {
 	key = strings.TrimSpace(key)
 	key = strings.TrimSuffix(key, "*")
@@ -282,3 +346,4 @@ func (s *StorageProvider) sanitizeKey(key string) string {
 
 // Compile time interface check:
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.VersionedStorage = (*StorageProvider)(nil)
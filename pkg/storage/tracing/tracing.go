@@ -82,6 +82,13 @@ func (t *tracingStorage) CleanupStorage(ctx context.Context, spec models.InputSo
 	return t.delegate.CleanupStorage(ctx, spec, volume)
 }
 
+func (t *tracingStorage) Version(ctx context.Context, spec models.InputSource) (string, error) {
+	ctx, span := system.NewSpan(ctx, system.GetTracer(), fmt.Sprintf("%s.Version", t.name))
+	defer span.End()
+
+	return storage.Version(ctx, t.delegate, spec)
+}
+
 func (t *tracingStorage) Upload(ctx context.Context, path string) (models.SpecConfig, error) {
 	ctx, span := system.NewSpan(ctx, system.GetTracer(), fmt.Sprintf("%s.Upload", t.name))
 	defer span.End()
@@ -98,3 +105,4 @@ func (t *tracingStorage) Upload(ctx context.Context, path string) (models.SpecCo
 }
 
 var _ storage.Storage = &tracingStorage{}
+var _ storage.VersionedStorage = &tracingStorage{}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -28,6 +29,28 @@ type Storage interface {
 	Upload(context.Context, string) (models.SpecConfig, error)
 }
 
+// VersionedStorage is implemented by storages that can tell which version of its data a source
+// currently points to, so that copies of the data can be revalidated before they are reused.
+type VersionedStorage interface {
+	// Version returns an identifier of the data the input source currently points to, such as
+	// its CID, version ID or ETag, which changes whenever the data does.
+	Version(context.Context, models.InputSource) (string, error)
+}
+
+// ErrVersionNotSupported is returned when asking for the version of an input source
+// with a storage that cannot tell the version of its data.
+var ErrVersionNotSupported = errors.New("storage does not support versioning its data")
+
+// Version returns the version of the data an input source of s currently points to,
+// or ErrVersionNotSupported if s cannot tell the version of its data.
+func Version(ctx context.Context, s Storage, spec models.InputSource) (string, error) {
+	versioned, ok := s.(VersionedStorage)
+	if !ok {
+		return "", ErrVersionNotSupported
+	}
+	return versioned.Version(ctx, spec)
+}
+
 // a storage entity that is consumed are produced by a job
 // input storage specs are turned into storage volumes by drivers
 // for example - the input storage spec might be ipfs cid XXX
@@ -80,6 +80,40 @@ func (sp *StorageProvider) GetVolumeSize(context.Context, models.InputSource) (u
 	return 0, nil
 }
 
+// Version returns the ETag of the file at the URL, or its last modification time if the server does not
+// send an ETag, as reported by a HEAD request. It fails if the server reports neither, as the file cannot
+// be told apart from a changed one.
+func (sp *StorageProvider) Version(ctx context.Context, storageSpec models.InputSource) (string, error) {
+	source, err := DecodeSpec(storageSpec.Source)
+	if err != nil {
+		return "", err
+	}
+	u, err := IsURLSupported(source.URL)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := sp.client.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to check the version of url %s: %w", u, err)
+	}
+	defer closer.DrainAndCloseWithLogOnError(ctx, "response", res.Body)
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("non-200 response from URL (%s): %s", source.URL, res.Status)
+	}
+	if etag := res.Header.Get("ETag"); etag != "" {
+		return "etag:" + etag, nil
+	}
+	if lastModified := res.Header.Get("Last-Modified"); lastModified != "" {
+		return "last-modified:" + lastModified, nil
+	}
+	return "", fmt.Errorf("url %s has neither an ETag nor a Last-Modified header to tell its version", source.URL)
+}
+
 // PrepareStorage will download the file from the URL
 func (sp *StorageProvider) PrepareStorage(
 	ctx context.Context,
@@ -241,6 +275,7 @@ func IsURLSupported(rawURL string) (*url.URL, error) {
 }
 
 var _ storage.Storage = (*StorageProvider)(nil)
+var _ storage.VersionedStorage = (*StorageProvider)(nil)
 
 var _ retryablehttp.LeveledLogger = retryLogger{}
 
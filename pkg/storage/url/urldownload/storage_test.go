@@ -339,3 +339,37 @@ func (s *StorageSuite) TestPrepareStorageURL() {
 		})
 	}
 }
+
+func (s *StorageSuite) TestVersion() {
+	headers := map[string]map[string]string{
+		"/etag":          {"ETag": `"abc"`, "Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT"},
+		"/last-modified": {"Last-Modified": "Mon, 02 Jan 2006 15:04:05 GMT"},
+		"/unversioned":   {},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodHead, r.Method)
+		for k, v := range headers[r.URL.Path] {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.T().Cleanup(ts.Close)
+
+	version := func(path string) (string, error) {
+		return NewStorage().Version(context.Background(), models.InputSource{
+			Source: &models.SpecConfig{Type: models.StorageSourceURL, Params: Source{URL: ts.URL + path}.ToMap()},
+			Target: "/inputs",
+		})
+	}
+
+	v, err := version("/etag")
+	s.Require().NoError(err)
+	s.Equal(`etag:"abc"`, v)
+
+	v, err = version("/last-modified")
+	s.Require().NoError(err)
+	s.Equal("last-modified:Mon, 02 Jan 2006 15:04:05 GMT", v)
+
+	_, err = version("/unversioned")
+	s.Error(err)
+}
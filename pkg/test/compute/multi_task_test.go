@@ -0,0 +1,153 @@
+//go:build integration || !unit
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+type MultiTaskSuite struct {
+	ComputeSuite
+	mu       sync.Mutex
+	runOrder []string
+}
+
+func TestMultiTaskSuite(t *testing.T) {
+	suite.Run(t, new(MultiTaskSuite))
+}
+
+func (s *MultiTaskSuite) SetupTest() {
+	s.ComputeSuite.SetupTest()
+	s.runOrder = nil
+}
+
+// setJobHandler records the order in which tasks are run, and fails the task named failingTask.
+// Tasks other than the main task are identified by their results directory, which is named after them.
+func (s *MultiTaskSuite) setJobHandler(execution *models.Execution, failingTask string) {
+	s.executor.Config.ExternalHooks.JobHandler = func(
+		ctx context.Context, jobID string, resultsDir string) (*models.RunCommandResult, error) {
+		taskName := execution.Job.Task().Name
+		for _, task := range execution.Job.Tasks {
+			if !task.IsMain() && task.Name == filepath.Base(resultsDir) {
+				taskName = task.Name
+			}
+		}
+		if taskName == "logs" {
+			// sidecars are stopped once the main task completes, whether they are done or not
+			return nil, nil
+		}
+		s.mu.Lock()
+		s.runOrder = append(s.runOrder, taskName)
+		s.mu.Unlock()
+		if taskName == failingTask {
+			return nil, errors.New("task failed")
+		}
+		return nil, nil
+	}
+}
+
+func (s *MultiTaskSuite) multiTaskExecution() *models.Execution {
+	execution := mock.Execution()
+	for _, task := range []struct{ name, lifecycle string }{
+		{"warmup", models.TaskLifecyclePrestart},
+		{"logs", models.TaskLifecycleSidecar},
+		{"flush", models.TaskLifecyclePoststop},
+	} {
+		t := mock.Task()
+		t.Name = task.name
+		t.Lifecycle = task.lifecycle
+		t.Publisher = &models.SpecConfig{}
+		execution.Job.Tasks = append(execution.Job.Tasks, t)
+	}
+	execution.Job.Normalize()
+	s.Require().NoError(execution.Job.Validate())
+	return execution
+}
+
+func (s *MultiTaskSuite) TestRunTasksInLifecycleOrder() {
+	ctx := context.Background()
+	execution := s.multiTaskExecution()
+	executionID := s.prepareAndAskForBid(ctx, execution)
+	s.setJobHandler(execution, "")
+
+	_, err := s.node.LocalEndpoint.BidAccepted(ctx, compute.BidAcceptedRequest{ExecutionID: executionID})
+	s.Require().NoError(err)
+
+	select {
+	case result := <-s.completedChannel:
+		s.Equal(models.TaskStateCompleted, result.TaskStates["warmup"].State)
+		s.Equal(models.TaskStateCompleted, result.TaskStates["task1"].State)
+		s.Equal(models.TaskStateStopped, result.TaskStates["logs"].State)
+		s.Equal(models.TaskStateCompleted, result.TaskStates["flush"].State)
+	case err := <-s.failureChannel:
+		s.FailNow("unexpected failure", err.Error())
+	case <-time.After(5 * time.Second):
+		s.FailNow("did not receive a run result")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]string{"warmup", "task1", "flush"}, s.runOrder)
+}
+
+func (s *MultiTaskSuite) TestPrestartFailureFailsExecution() {
+	ctx := context.Background()
+	execution := s.multiTaskExecution()
+	executionID := s.prepareAndAskForBid(ctx, execution)
+	s.setJobHandler(execution, "warmup")
+
+	_, err := s.node.LocalEndpoint.BidAccepted(ctx, compute.BidAcceptedRequest{ExecutionID: executionID})
+	s.Require().NoError(err)
+
+	select {
+	case result := <-s.failureChannel:
+		s.Contains(result.Error(), "prestart task warmup failed")
+	case <-s.completedChannel:
+		s.FailNow("execution should have failed")
+	case <-time.After(5 * time.Second):
+		s.FailNow("did not receive a failure")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]string{"warmup", "flush"}, s.runOrder, "poststop tasks run on every exit path")
+}
+
+func (s *MultiTaskSuite) TestMainTaskFailureRunsPoststop() {
+	ctx := context.Background()
+	execution := s.multiTaskExecution()
+	executionID := s.prepareAndAskForBid(ctx, execution)
+	s.setJobHandler(execution, "task1")
+
+	_, err := s.node.LocalEndpoint.BidAccepted(ctx, compute.BidAcceptedRequest{ExecutionID: executionID})
+	s.Require().NoError(err)
+
+	select {
+	case result := <-s.failureChannel:
+		s.Contains(result.Error(), "task failed")
+	case <-s.completedChannel:
+		s.FailNow("execution should have failed")
+	case <-time.After(5 * time.Second):
+		s.FailNow("did not receive a failure")
+	}
+
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.runOrder) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal([]string{"warmup", "task1", "flush"}, s.runOrder)
+}
@@ -123,5 +123,6 @@ func Plan() *models.Plan {
 		Job:               job,
 		NewExecutions:     []*models.Execution{},
 		UpdatedExecutions: make(map[string]*models.PlanExecutionDesiredUpdate),
+		UpdatedJobs:       make(map[string]*models.PlanJobDesiredUpdate),
 	}
 }
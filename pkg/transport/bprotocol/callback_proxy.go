@@ -64,6 +64,12 @@ func (p *CallbackProxy) OnComputeFailure(ctx context.Context, result compute.Com
 	})
 }
 
+func (p *CallbackProxy) OnCheckpoint(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnCheckpoint, result, func(ctx2 context.Context) {
+		p.localCallback.OnCheckpoint(ctx2, result)
+	})
+}
+
 func proxyCallbackRequest(
 	ctx context.Context,
 	p *CallbackProxy,
@@ -96,6 +96,19 @@ func (p *ComputeProxy) ExecutionLogs(ctx context.Context, request compute.Execut
 		ctx, p.host, request.TargetPeerID, ExecutionLogsID, request)
 }
 
+// Exec is only supported on the local compute node, as libp2p streams to remote nodes are not bidirectional.
+// Nodes that need to exec into executions on remote nodes should use the NATS transport.
+func (p *ComputeProxy) Exec(ctx context.Context, request compute.ExecRequest, input <-chan models.ExecInput) (
+	<-chan *concurrency.AsyncResult[models.ExecOutput], error) {
+	if request.TargetPeerID != p.host.ID().String() {
+		return nil, fmt.Errorf("exec into executions on remote nodes is not supported by the libp2p transport")
+	}
+	if p.localEndpoint == nil {
+		return nil, fmt.Errorf("unable to dial to self, unless a local compute endpoint is provided")
+	}
+	return p.localEndpoint.Exec(ctx, request, input)
+}
+
 func proxyRequest[Request any, Response any](
 	ctx context.Context,
 	h host.Host,
@@ -13,4 +13,5 @@ const (
 	OnRunComplete       = "/bacalhau/callback/on_run_complete/1.0.0"
 	OnCancelComplete    = "/bacalhau/callback/on_cancel_complete/1.0.0"
 	OnComputeFailure    = "/bacalhau/callback/on_compute_failure/1.0.0"
+	OnCheckpoint        = "/bacalhau/callback/on_checkpoint/1.0.0"
 )